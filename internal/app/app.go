@@ -3,23 +3,49 @@ package app
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
 	"os"
+	"os/exec"
 	"os/signal"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
-	"golang.org/x/term"
 	tea "github.com/charmbracelet/bubbletea"
-	"gopkg.in/yaml.v3"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+	"gossh/internal/clipboard"
 	"gossh/internal/config"
+	"gossh/internal/discovery"
+	"gossh/internal/hooks"
 	"gossh/internal/i18n"
+	"gossh/internal/keycache"
 	"gossh/internal/model"
+	"gossh/internal/monitor"
+	"gossh/internal/notify"
+	"gossh/internal/playbook"
+	"gossh/internal/plugins"
+	"gossh/internal/report"
+	"gossh/internal/schedule"
 	"gossh/internal/sftp"
+	"gossh/internal/shell"
 	"gossh/internal/ssh"
 	"gossh/internal/sshconfig"
 	"gossh/internal/ui"
+	"gossh/internal/ui/styles"
+	"gossh/internal/ui/views"
 )
 
 // version is set at build time, defaults to dev
@@ -35,6 +61,16 @@ func GetVersion() string {
 	return version
 }
 
+// ExitError asks main to exit with Code without printing anything, e.g. to
+// pass on a remote command's exit status
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
 // Run starts the application
 func Run() error {
 	// Initialize config manager
@@ -43,11 +79,9 @@ func Run() error {
 		return fmt.Errorf("failed to initialize config: %w", err)
 	}
 
-	// Load saved language setting
-	savedLang := cfg.GetLanguage()
-	if savedLang != "" {
-		i18n.SetLanguage(i18n.Language(savedLang))
-	}
+	initLanguage(cfg, true)
+	initSettings(cfg)
+	styles.SetTheme(cfg.GetSettings().Theme)
 
 	// Create the app model
 	appModel := ui.NewModel(cfg)
@@ -56,16 +90,106 @@ func Run() error {
 	// Create and run the Bubbletea program
 	p := tea.NewProgram(appModel, tea.WithAltScreen())
 
-	if _, err := p.Run(); err != nil {
+	_, err = p.Run()
+	// Status the background writer has not got to yet
+	if flushErr := cfg.FlushState(); flushErr != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("common.state_error")+"\n", flushErr)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to run program: %w", err)
 	}
 
 	return nil
 }
 
+// initLanguage loads external locale files and applies the language setting.
+// On first run the language is detected from the environment instead and,
+// if persist is set, saved to the config.
+func initLanguage(cfg *config.Manager, persist bool) {
+	if dir, err := config.LocalesDir(); err == nil {
+		if err := i18n.LoadDir(dir); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	// GOSSH_LANG is used as is, never saved
+	if cfg.IsFirstRun() && os.Getenv(config.EnvLang) == "" {
+		lang := i18n.DetectLanguage()
+		i18n.SetLanguage(lang)
+		if persist {
+			_ = cfg.SetLanguage(string(lang))
+		}
+		return
+	}
+
+	// Load saved language setting
+	savedLang := cfg.GetLanguage()
+	if savedLang != "" {
+		i18n.SetLanguage(i18n.Language(savedLang))
+	}
+}
+
+// initSettings applies the settings the TUI and the CLI share, warning
+// about environment overrides that are ignored
+func initSettings(cfg *config.Manager) {
+	if err := config.CheckEnv(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	settings := cfg.GetSettings()
+	ssh.SetConnectTimeout(settings.ConnectTimeout())
+	ssh.DefaultPool.SetIdleTimeout(settings.KeepWarm())
+	ssh.SetKeepaliveInterval(settings.Keepalive())
+	ssh.SetTerminalDefaults(settings.Term, settings.Locale)
+	ssh.SetScrollbackSize(settings.Scrollback())
+}
+
+// configFlag takes --config <path> or --config=<path> out of args and
+// returns the path. The flag may come before or after the subcommand, but
+// not after a "--" that starts a remote command.
+func configFlag(args []string) ([]string, string, error) {
+	var rest []string
+	var path string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return append(rest, args[i:]...), path, nil
+		case arg == "--config":
+			if i+1 >= len(args) {
+				return nil, "", errors.New(i18n.T("cli.usage.config"))
+			}
+			path = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--config="):
+			path = strings.TrimPrefix(arg, "--config=")
+		default:
+			rest = append(rest, arg)
+			continue
+		}
+		if path == "" {
+			return nil, "", errors.New(i18n.T("cli.usage.config"))
+		}
+	}
+	return rest, path, nil
+}
+
 // RunWithArgs runs the app with command line arguments
 func RunWithArgs(args []string) error {
+	args, path, err := configFlag(args)
+	if err != nil {
+		return err
+	}
+	// Every subcommand and the TUI open the config through config.NewManager
+	config.SetConfigPath(path)
+
 	if len(args) > 1 {
+		// CLI output follows the configured language too
+		if cfg, err := config.NewManager(); err == nil {
+			initLanguage(cfg, false)
+			initSettings(cfg)
+		}
+		ssh.SetKeyWarning(warnKeyFile)
+
 		switch args[1] {
 		case "version", "-v", "--version":
 			fmt.Printf("gossh v%s\n", version)
@@ -77,24 +201,81 @@ func RunWithArgs(args []string) error {
 			return runExport(args[2:])
 		case "import":
 			return runImport(args[2:])
+		case "discover":
+			return runDiscover(args[2:])
 		case "list":
 			return runList()
+		case "rm":
+			return runRemove(args[2:])
+		case "trash":
+			return runTrash(args[2:])
+		case "rename":
+			return runRename(args[2:])
+		case "dedupe":
+			return runDedupe(args[2:])
+		case "history":
+			return runHistory(args[2:])
+		case "workspace":
+			return runWorkspace(args[2:])
+		case "schedule":
+			return runSchedule(args[2:])
 		case "connect":
-			if len(args) < 3 {
-				return fmt.Errorf("usage: gossh connect <name>")
-			}
-			return runConnect(args[2])
+			return runConnect(args[2:])
 		case "sftp":
-			if len(args) < 3 {
-				return fmt.Errorf("usage: gossh sftp <name>")
-			}
-			return runSFTP(args[2])
+			return runSFTP(args[2:])
 		case "forward":
 			return runForward(args[2:])
 		case "exec":
 			return runExec(args[2:])
+		case "push":
+			return runPush(args[2:])
+		case "tail":
+			return runTail(args[2:])
+		case "watch":
+			return runWatch(args[2:])
+		case "archive":
+			return runArchive(args[2:])
+		case "extract":
+			return runExtract(args[2:])
 		case "check":
 			return runHealthCheck(args[2:])
+		case "facts":
+			return runFacts(args[2:])
+		case "report":
+			return runReport(args[2:])
+		case "audit-credentials":
+			return runAuditCredentials(args[2:])
+		case "validate":
+			return runValidate()
+		case "monitor":
+			return runMonitor(args[2:])
+		case "lock":
+			return runLock()
+		case "remember":
+			return runRemember()
+		case "forget":
+			return runForget()
+		case "plugins":
+			return runPlugins()
+		case "print-ssh-config":
+			return runPrintSSHConfig(args[2:])
+		case "stdio":
+			return runStdio(args[2:])
+		case "probe":
+			return runProbe(args[2:])
+		case "agent":
+			return runAgent(args[2:])
+		case "play":
+			return runPlay(args[2:])
+		case keycache.AgentCommand:
+			return keycache.Serve(os.Stdin, os.Stdout)
+		}
+
+		// Anything else may be a plugin: gossh-<command> in the plugins directory
+		if dir, err := config.PluginsDir(); err == nil && !strings.HasPrefix(args[1], "-") {
+			if plugin, ok := plugins.Find(dir, args[1]); ok {
+				return runPlugin(plugin, args[2:])
+			}
 		}
 	}
 
@@ -102,86 +283,222 @@ func RunWithArgs(args []string) error {
 }
 
 func printHelp() {
-	help := `GoSSH - TUI SSH Connection Manager v%s
-
-Usage:
-  gossh                              Start the TUI application
-  gossh help                         Show this help message
-  gossh version                      Show version information
-  gossh list                         List all connections
-  gossh connect <name>               Connect to a server by name
-  gossh export [file]                Export connections (default: connections.yaml)
-  gossh import <file>                Import connections from file
-  gossh import --ssh-config [path]   Import from SSH config file
-
-Advanced Commands (v1.2):
-  gossh sftp <name>                  Start SFTP session with a server
-  gossh forward <name> -L/-R <spec>  Port forwarding (-L local, -R remote)
-  gossh exec <command> [options]     Execute command on multiple servers
-    --group=<group>                  Filter by group
-    --tags=<tag1,tag2>               Filter by tags
-    --names=<n1,n2>                  Filter by names
-    --timeout=<seconds>              Command timeout (default: 30)
-  gossh check [options]              Health check connections
-    --all                            Check all connections
-    --group=<group>                  Check by group
-    --name=<name>                    Check specific connection
-
-Port Forwarding:
-  gossh forward <name> -L <local-port>:<remote-host>:<remote-port>
-  gossh forward <name> -R <remote-port>:<local-host>:<local-port>
-
-  -L (Local Forward): Map remote port to local
-    Listens on <local-port> on your machine, traffic is forwarded through the
-    SSH server to <remote-host>:<remote-port>.
-    Use "localhost" as <remote-host> to access the server's own port.
-
-  -R (Remote Forward): Map local port to remote
-    Listens on <remote-port> on the SSH server, traffic is forwarded back to
-    <local-host>:<local-port> on your machine.
-    Use "localhost" as <local-host> to expose your machine's own port.
-
-Examples:
-  gossh sftp prod-web-01
+	row := func(cmd, desc string) {
+		fmt.Printf("  %-34s %s\n", cmd, desc)
+	}
+	opt := func(flag, desc string) {
+		fmt.Printf("    %-32s %s\n", flag, desc)
+	}
+
+	fmt.Printf(i18n.T("cli.help.title")+"\n\n", version)
+
+	fmt.Println(i18n.T("cli.help.usage"))
+	row("gossh", i18n.T("cli.help.tui"))
+	row("gossh --config <path> [command]", i18n.T("cli.help.config_flag"))
+	row("gossh help", i18n.T("cli.help.help"))
+	row("gossh version", i18n.T("cli.help.version"))
+	row("gossh list", i18n.T("cli.help.list"))
+	row("gossh connect <name> [-- <command...>]", i18n.T("cli.help.connect"))
+	opt("--share=<addr|unix:path|file>", i18n.T("cli.help.connect.share"))
+	opt("--scrollback", i18n.T("cli.help.connect.scrollback"))
+	opt("-- <command...>", i18n.T("cli.help.connect.command"))
+	row("gossh rm <name> [--force] [--purge]", i18n.T("cli.help.rm"))
+	row("gossh trash [restore|purge <name>|empty]", i18n.T("cli.help.trash"))
+	row("gossh rename <old> <new>", i18n.T("cli.help.rename"))
+	row("gossh dedupe [options]", i18n.T("cli.help.dedupe"))
+	opt("--dry-run", i18n.T("cli.help.dedupe.dry_run"))
+	opt("--yes", i18n.T("cli.help.dedupe.yes"))
+	row("gossh history <name> [<n> [--restore]]", i18n.T("cli.help.history"))
+	row("gossh workspace [list]", i18n.T("cli.help.workspace"))
+	row("gossh workspace save <name> [options]", i18n.T("cli.help.workspace.save"))
+	opt("<connection>...", i18n.T("cli.help.workspace.sessions"))
+	opt("-L/-R <connection>=<spec>", i18n.T("cli.help.workspace.tunnel"))
+	opt("--snippet <name>=<command>", i18n.T("cli.help.workspace.snippet"))
+	row("gossh workspace open <name>", i18n.T("cli.help.workspace.open"))
+	row("gossh workspace run <name> <snippet>", i18n.T("cli.help.workspace.run"))
+	row("gossh workspace rm <name>", i18n.T("cli.help.workspace.rm"))
+	row("gossh export [file]", i18n.T("cli.help.export"))
+	opt("--force", i18n.T("cli.help.export.force"))
+	row("gossh import <file>", i18n.T("cli.help.import"))
+	opt("--merge", i18n.T("cli.help.import.merge"))
+	opt("--dry-run", i18n.T("cli.help.import.dry_run"))
+	row("gossh import --ssh-config [path]", i18n.T("cli.help.import_ssh"))
+	opt("--dry-run", i18n.T("cli.help.import_ssh.dry_run"))
+	row("gossh print-ssh-config [options]", i18n.T("cli.help.print_ssh_config"))
+	opt("--group/--tags/--names/--match", i18n.T("cli.help.print_ssh_config.filter"))
+	row("gossh discover tailscale [options]", i18n.T("cli.help.discover"))
+	opt("--socket=<path>", i18n.T("cli.help.discover.socket"))
+	opt("--user=<name>", i18n.T("cli.help.discover.user"))
+	opt("--key=<path>", i18n.T("cli.help.discover.key"))
+	opt("--dry-run", i18n.T("cli.help.discover.dry_run"))
+	fmt.Println()
+
+	fmt.Println(i18n.T("cli.help.advanced"))
+	row("gossh sftp <name>", i18n.T("cli.help.sftp"))
+	opt("--parallel=<n>", i18n.T("cli.help.sftp.parallel"))
+	opt("--preserve[=links,owner,times]", i18n.T("cli.help.sftp.preserve"))
+	opt("--trash", i18n.T("cli.help.sftp.trash"))
+	opt("--batch <file>", i18n.T("cli.help.sftp.batch"))
+	opt("-e <commands>", i18n.T("cli.help.sftp.exec"))
+	row("gossh forward <name> -L/-R/-D <spec>...", i18n.T("cli.help.forward"))
+	opt("--match=<regex>", i18n.T("cli.help.forward.match"))
+	opt("--save=<profile>", i18n.T("cli.help.forward.save"))
+	opt("--profile=<profile>", i18n.T("cli.help.forward.profile"))
+	opt("--all-profiles", i18n.T("cli.help.forward.all_profiles"))
+	opt("--list", i18n.T("cli.help.forward.list"))
+	opt("--gateway", i18n.T("cli.help.forward.gateway"))
+	opt("--hint=<command>", i18n.T("cli.help.forward.hint"))
+	opt("--copy", i18n.T("cli.help.forward.copy"))
+	row("gossh stdio <name> [<host>:<port>]", i18n.T("cli.help.stdio"))
+	opt("<host>:<port>", i18n.T("cli.help.stdio.addr"))
+	row("gossh probe <name> --ports=<ports>", i18n.T("cli.help.probe"))
+	opt("--ports=<80,443,8000-8010>", i18n.T("cli.help.probe.ports"))
+	opt("--host=<host>", i18n.T("cli.help.probe.host"))
+	opt("--timeout=<seconds>", i18n.T("cli.help.probe.timeout"))
+	row("gossh agent [list]", i18n.T("cli.help.agent"))
+	row("gossh agent pin <name> <key>", i18n.T("cli.help.agent.pin"))
+	opt("<key>", i18n.T("cli.help.agent.key"))
+	row("gossh agent unpin <name>", i18n.T("cli.help.agent.unpin"))
+	row("gossh exec <command> [options]", i18n.T("cli.help.exec"))
+	opt("--group=<group>", i18n.T("cli.help.exec.group"))
+	opt("--tags=<tag1,tag2>", i18n.T("cli.help.exec.tags"))
+	opt("--names=<n1,n2>", i18n.T("cli.help.exec.names"))
+	opt("--match=<regex>", i18n.T("cli.help.exec.match"))
+	opt("--exclude-group=<group>", i18n.T("cli.help.exec.exclude_group"))
+	opt("--exclude-tags=<tag1,tag2>", i18n.T("cli.help.exec.exclude_tags"))
+	opt("--exclude-names=<n1,n2>", i18n.T("cli.help.exec.exclude_names"))
+	opt("--timeout=<seconds>", i18n.T("cli.help.exec.timeout"))
+	opt("--deadline=<seconds>", i18n.T("cli.help.exec.deadline"))
+	opt("--copy", i18n.T("cli.help.exec.copy"))
+	opt("--diff", i18n.T("cli.help.exec.diff"))
+	opt("--pty", i18n.T("cli.help.exec.pty"))
+	opt("--strip-color", i18n.T("cli.help.exec.strip_color"))
+	opt("--env KEY=VALUE", i18n.T("cli.help.exec.env"))
+	opt("--env-file <file>", i18n.T("cli.help.exec.env_file"))
+	opt("--output=<format>", i18n.T("cli.help.exec.output"))
+	opt("--retry-last", i18n.T("cli.help.exec.retry_last"))
+	opt("--retry-failed=<run-id>", i18n.T("cli.help.exec.retry_failed"))
+	row("gossh play <playbook.yaml>", i18n.T("cli.help.play"))
+	opt("--dry-run", i18n.T("cli.help.play.dry_run"))
+	opt("--yes", i18n.T("cli.help.play.yes"))
+	row("gossh schedule [list]", i18n.T("cli.help.schedule"))
+	row("gossh schedule add <cron> <cmd>", i18n.T("cli.help.schedule.add"))
+	opt("--group/--tags/--names/--match", i18n.T("cli.help.schedule.filter"))
+	opt("--timeout=<seconds>", i18n.T("cli.help.exec.timeout"))
+	row("gossh schedule rm <id>", i18n.T("cli.help.schedule.rm"))
+	row("gossh schedule daemon", i18n.T("cli.help.schedule.daemon"))
+	row("gossh push <local-file> <remote-path> [options]", i18n.T("cli.help.push"))
+	opt("--group/--tags/--names/--match", i18n.T("cli.help.push.filter"))
+	opt("--mode=<octal>", i18n.T("cli.help.push.mode"))
+	opt("--owner=<user[:group]>", i18n.T("cli.help.push.owner"))
+	opt("--preserve-times", i18n.T("cli.help.push.preserve_times"))
+	opt("--run=<command>", i18n.T("cli.help.push.run"))
+	opt("--parallel=<n>", i18n.T("cli.help.push.parallel"))
+	opt("--timeout=<seconds>", i18n.T("cli.help.push.timeout"))
+	row("gossh tail <remote-file> [options]", i18n.T("cli.help.tail"))
+	opt("--group/--tags/--names/--match", i18n.T("cli.help.tail.filter"))
+	opt("--grep=<regex>", i18n.T("cli.help.tail.grep"))
+	opt("--lines=<n>", i18n.T("cli.help.tail.lines"))
+	row("gossh watch <name> <local-dir> <remote-dir>", i18n.T("cli.help.watch"))
+	opt("--exclude=<glob>[,<glob>...]", i18n.T("cli.help.watch.exclude"))
+	opt("--delete", i18n.T("cli.help.watch.delete"))
+	opt("--debounce=<ms>", i18n.T("cli.help.watch.debounce"))
+	row("gossh archive <name> <remote-dir> <local-file>", i18n.T("cli.help.archive"))
+	row("gossh extract <name> <local-file> <remote-dir>", i18n.T("cli.help.extract"))
+	row("gossh check [options]", i18n.T("cli.help.check"))
+	opt("--all", i18n.T("cli.help.check.all"))
+	opt("--group=<group>", i18n.T("cli.help.check.group"))
+	opt("--name=<name>", i18n.T("cli.help.check.name"))
+	row("gossh facts [options]", i18n.T("cli.help.facts"))
+	opt("--group/--tags/--names/--match", i18n.T("cli.help.facts.filter"))
+	opt("--timeout=<seconds>", i18n.T("cli.help.exec.timeout"))
+	row("gossh report [options]", i18n.T("cli.help.report"))
+	opt("--format=<md|html>", i18n.T("cli.help.report.format"))
+	opt("--output=<file>", i18n.T("cli.help.report.output"))
+	opt("--max-age=<days>", i18n.T("cli.help.audit.max_age"))
+	opt("--group/--tags/--names/--match", i18n.T("cli.help.report.filter"))
+	row("gossh audit-credentials [options]", i18n.T("cli.help.audit"))
+	opt("--max-age=<days>", i18n.T("cli.help.audit.max_age"))
+	opt("--group=<group>", i18n.T("cli.help.check.group"))
+	opt("--all", i18n.T("cli.help.audit.all"))
+	row("gossh validate", i18n.T("cli.help.validate"))
+	row("gossh monitor [options]", i18n.T("cli.help.monitor"))
+	opt("--listen=<addr>", i18n.T("cli.help.monitor.listen"))
+	opt("--interval=<seconds>", i18n.T("cli.help.monitor.interval"))
+	opt("--timeout=<seconds>", i18n.T("cli.help.monitor.timeout"))
+	opt("--group/--tags/--names/--match", i18n.T("cli.help.monitor.filter"))
+	row("gossh lock", i18n.T("cli.help.lock"))
+	row("gossh remember", i18n.T("cli.help.remember"))
+	row("gossh forget", i18n.T("cli.help.forget"))
+	row("gossh plugins", i18n.T("cli.help.plugins"))
+	row("gossh <plugin> [<name>] [args...]", i18n.T("cli.help.plugin"))
+	fmt.Println()
+
+	fmt.Println(i18n.T("cli.help.forwarding"))
+	fmt.Println("  gossh forward <name> -L <local-port>:<remote-host>:<remote-port>")
+	fmt.Println("  gossh forward <name> -R <remote-port>:<local-host>:<local-port>")
+	fmt.Println("  gossh forward <name> -D [<bind-address>:]<local-port>")
+	fmt.Println()
+	fmt.Println("  " + strings.ReplaceAll(i18n.T("cli.help.forward.local"), "\n", "\n  "))
+	fmt.Println()
+	fmt.Println("  " + strings.ReplaceAll(i18n.T("cli.help.forward.remote"), "\n", "\n  "))
+	fmt.Println()
+	fmt.Println("  " + strings.ReplaceAll(i18n.T("cli.help.forward.dynamic"), "\n", "\n  "))
+	fmt.Println()
+
+	fmt.Println(i18n.T("cli.help.examples"))
+	fmt.Println(`  gossh sftp prod-web-01
   gossh exec "uptime" --group=Production
   gossh exec "df -h" --tags=web,nginx
+  gossh exec "uptime" --names='web-*'
+  gossh exec "uptime" --match='^db-[0-9]+$'
+  gossh exec "uptime" --group=Production --exclude-tags=canary
   gossh import --ssh-config
-  gossh check --all
-
-  # Access remote server's MySQL (port 3306) from local port 3306
-  #   local:3306 -> [server] -> 3306
-  gossh forward <name> -L 3306:localhost:3306
-
-  # Expose local web service (port 80) as port 8080 on remote server
-  #   [server]:8080 -> local:80
-  gossh forward <name> -R 8080:localhost:80
-
-TUI Navigation:
-  up/k               Move up
-  down/j             Move down
-  g/G                Jump to top/bottom
-  /                  Search connections
-  enter              Connect to selected server
-  a                  Add new connection
-  e                  Edit selected connection
-  d                  Delete selected connection
-  s                  Settings
-  t                  Test connection
-  ?                  Show help
-  q                  Quit
-
-Config location:
-  Linux/macOS: ~/.config/gossh/config.yaml
-  Windows:     %%APPDATA%%\gossh\config.yaml
-`
-	fmt.Fprintf(os.Stdout, help, version)
+  gossh check --all`)
+	fmt.Println()
+	fmt.Println("  # " + i18n.T("cli.help.example.mysql"))
+	fmt.Println("  #   local:3306 -> [server] -> 3306")
+	fmt.Println("  gossh forward <name> -L 3306:localhost:3306")
+	fmt.Println()
+	fmt.Println("  # " + i18n.T("cli.help.example.expose"))
+	fmt.Println("  #   [server]:8080 -> local:80")
+	fmt.Println("  gossh forward <name> -R 8080:localhost:80")
+	fmt.Println()
+
+	key := func(keys, desc string) {
+		fmt.Printf("  %-18s %s\n", keys, desc)
+	}
+	fmt.Println(i18n.T("cli.help.navigation"))
+	key("up/k", i18n.T("help.key.up"))
+	key("down/j", i18n.T("help.key.down"))
+	key("g/G", i18n.T("help.key.top")+" / "+i18n.T("help.key.bottom"))
+	key("/", i18n.T("help.key.search"))
+	key("enter", i18n.T("help.key.connect"))
+	key("a", i18n.T("help.key.add"))
+	key("e", i18n.T("help.key.edit"))
+	key("d", i18n.T("help.key.delete"))
+	key("s", i18n.T("help.key.settings"))
+	key("t", i18n.T("help.key.test"))
+	key("?", i18n.T("help.key.help"))
+	key("q", i18n.T("help.key.quit"))
+	fmt.Println()
+
+	fmt.Println(i18n.T("cli.help.config"))
+	fmt.Println("  Linux/macOS: ~/.config/gossh/config.yaml")
+	fmt.Println(`  Windows:     %APPDATA%\gossh\config.yaml`)
+	fmt.Println("  " + i18n.T("cli.help.config.env"))
 }
 
 // runExport exports connections to a file
 func runExport(args []string) error {
 	filename := "connections.yaml"
-	if len(args) > 0 {
-		filename = args[0]
+	force := false
+	for _, arg := range args {
+		if arg == "--force" {
+			force = true
+		} else {
+			filename = arg
+		}
 	}
 
 	cfg, err := config.NewManager()
@@ -193,40 +510,22 @@ func runExport(args []string) error {
 		return err
 	}
 
-	connections := cfg.Connections()
-
-	// Create export structure (without encrypted fields)
-	exportData := struct {
-		Version     string             `yaml:"version"`
-		Connections []model.Connection `yaml:"connections"`
-	}{
-		Version:     version,
-		Connections: make([]model.Connection, len(connections)),
-	}
-
-	for i, conn := range connections {
-		exportData.Connections[i] = conn
-		exportData.Connections[i].EncryptedPassword = ""
-		exportData.Connections[i].EncryptedKeyPassphrase = ""
+	result, err := cfg.Export(filename, config.FormatYAML, version, force)
+	if errors.Is(err, config.ErrInsecurePermissions) {
+		return fmt.Errorf(i18n.T("cli.export.insecure_dir"), err)
 	}
-
-	data, err := yaml.Marshal(&exportData)
 	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
-	}
-
-	if err := os.WriteFile(filename, data, 0600); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+		return err
 	}
 
-	fmt.Printf("Exported %d connections to %s\n", len(connections), filename)
+	fmt.Printf(i18n.T("cli.export.done")+"\n", result.Exported, filename)
 	return nil
 }
 
 // runImport imports connections from a file
 func runImport(args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("usage: gossh import <file> or gossh import --ssh-config [path]")
+		return errors.New(i18n.T("cli.usage.import"))
 	}
 
 	// Check if importing from SSH config
@@ -234,20 +533,22 @@ func runImport(args []string) error {
 		return runImportSSHConfig(args[1:])
 	}
 
-	filename := args[0]
-
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
-	}
-
-	var importData struct {
-		Version     string             `yaml:"version"`
-		Connections []model.Connection `yaml:"connections"`
+	var filename string
+	merge, dryRun := false, false
+	for _, arg := range args {
+		switch {
+		case arg == "--merge":
+			merge = true
+		case arg == "--dry-run":
+			dryRun = true
+		case filename == "" && !strings.HasPrefix(arg, "-"):
+			filename = arg
+		default:
+			return errors.New(i18n.T("cli.usage.import"))
+		}
 	}
-
-	if err := yaml.Unmarshal(data, &importData); err != nil {
-		return fmt.Errorf("failed to parse file: %w", err)
+	if filename == "" || (dryRun && !merge) {
+		return errors.New(i18n.T("cli.usage.import"))
 	}
 
 	cfg, err := config.NewManager()
@@ -259,29 +560,84 @@ func runImport(args []string) error {
 		return err
 	}
 
-	fmt.Print("Overwrite existing connections with same name? [y/N]: ")
+	if merge {
+		return runImportMerge(cfg, filename, dryRun)
+	}
+
+	fmt.Print(i18n.T("cli.import.overwrite"))
 	var answer string
 	_, _ = fmt.Scanln(&answer)
 	overwrite := answer == "y" || answer == "Y"
 
-	imported, err := cfg.ImportConnections(importData.Connections, overwrite)
+	result, err := cfg.Import(filename, config.FormatYAML, overwrite)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf(i18n.T("cli.import.done")+"\n", result.Imported, filename)
+	return nil
+}
+
+// runImportMerge merges a file into the stored connections, taking each
+// field that differs from the file unless the file leaves it empty
+func runImportMerge(cfg *config.Manager, filename string, dryRun bool) error {
+	connections, err := config.ReadImport(filename, config.FormatYAML)
 	if err != nil {
-		return fmt.Errorf("failed to import: %w", err)
+		return err
+	}
+
+	plan := cfg.PlanMerge(connections)
+	for _, c := range plan.New {
+		fmt.Printf("  + %s\n", c.Name)
+	}
+	for _, c := range plan.Conflicts {
+		fmt.Printf("  ~ %s\n", c.Local.Name)
+		for _, d := range c.Diffs {
+			local, incoming := d.Local, d.Incoming
+			if d.Secret {
+				local, incoming = "******", "******"
+			}
+			if d.TakeIncoming {
+				fmt.Printf("      %-22s %q → %q\n", d.Field, local, incoming)
+			} else {
+				fmt.Printf("      %-22s %q ("+i18n.T("cli.import.merge.kept")+")\n", d.Field, local)
+			}
+		}
+	}
+	fmt.Printf("\n"+i18n.T("cli.import.merge.summary")+"\n", len(plan.New), len(plan.Conflicts), plan.Unchanged)
+
+	if dryRun {
+		fmt.Println(i18n.T("cli.import.ssh.dry_run"))
+		return nil
 	}
 
-	fmt.Printf("Imported %d connections from %s\n", imported, filename)
+	added, merged, err := cfg.ApplyMerge(plan)
+	if err != nil {
+		return err
+	}
+	fmt.Printf(i18n.T("cli.import.merge.done")+"\n", added, merged, filename)
 	return nil
 }
 
 // runImportSSHConfig imports connections from SSH config file
 func runImportSSHConfig(args []string) error {
+	var path string
+	dryRun := false
+	for _, arg := range args {
+		if arg == "--dry-run" {
+			dryRun = true
+		} else if path == "" {
+			path = arg
+		}
+	}
+
 	parser := sshconfig.NewParser()
 
 	var connections []model.Connection
 	var err error
 
-	if len(args) > 0 {
-		connections, err = parser.ParseFile(args[0])
+	if path != "" {
+		connections, err = parser.ParseFile(path)
 	} else {
 		connections, err = parser.ParseDefault()
 	}
@@ -291,50 +647,225 @@ func runImportSSHConfig(args []string) error {
 	}
 
 	if len(connections) == 0 {
-		fmt.Println("No connections found in SSH config.")
+		fmt.Println(i18n.T("cli.import.ssh.none"))
 		return nil
 	}
 
-	fmt.Printf("Found %d connections in SSH config.\n", len(connections))
+	fmt.Printf(i18n.T("cli.import.ssh.found")+"\n\n", len(connections))
 
 	cfg, err := config.NewManager()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if err := unlockIfNeeded(cfg); err != nil {
-		return err
+	// A dry run only compares names, so the config does not need unlocking
+	if !dryRun {
+		if err := unlockIfNeeded(cfg); err != nil {
+			return err
+		}
 	}
 
 	// Merge with existing connections
 	existing := cfg.Connections()
 	newConns, skipped := sshconfig.Merge(existing, connections)
+	printImportPreview(connections, newConns)
+
+	if dryRun {
+		fmt.Printf("\n"+i18n.T("cli.import.ssh.pending")+"\n", len(newConns), skipped)
+		fmt.Println(i18n.T("cli.import.ssh.dry_run"))
+		return nil
+	}
 
 	if len(newConns) == 0 {
-		fmt.Printf("All %d connections already exist, nothing to import.\n", skipped)
+		fmt.Printf("\n"+i18n.T("cli.import.ssh.exists")+"\n", skipped)
 		return nil
 	}
 
-	fmt.Printf("Will import %d new connections (%d skipped as duplicates).\n", len(newConns), skipped)
-	fmt.Print("Proceed? [Y/n]: ")
+	fmt.Printf("\n"+i18n.T("cli.import.ssh.pending")+"\n", len(newConns), skipped)
+	fmt.Print(i18n.T("cli.confirm.proceed"))
 	var answer string
 	_, _ = fmt.Scanln(&answer)
 	if answer != "" && answer != "y" && answer != "Y" {
-		fmt.Println("Import cancelled.")
+		fmt.Println(i18n.T("cli.import.ssh.cancelled"))
 		return nil
 	}
 
 	// Import the new connections
+	imported := 0
 	for _, conn := range newConns {
 		if err := cfg.AddConnection(conn); err != nil {
-			fmt.Printf("Warning: failed to add %s: %v\n", conn.Name, err)
+			fmt.Printf(i18n.T("cli.import.ssh.add_failed")+"\n", conn.Name, err)
+			continue
+		}
+		imported++
+	}
+
+	fmt.Printf(i18n.T("cli.import.ssh.done")+"\n", imported)
+	return nil
+}
+
+// runDiscover imports the peers of a Tailscale tailnet into the Tailnet
+// group. Running it again adds new peers and refreshes the hosts and tags
+// of known ones.
+func runDiscover(args []string) error {
+	if len(args) == 0 || args[0] != "tailscale" {
+		return errors.New(i18n.T("cli.usage.discover"))
+	}
+
+	var socket, user, keyPath string
+	dryRun := false
+	for _, arg := range args[1:] {
+		switch {
+		case arg == "--dry-run":
+			dryRun = true
+		case strings.HasPrefix(arg, "--socket="):
+			socket = strings.TrimPrefix(arg, "--socket=")
+		case strings.HasPrefix(arg, "--user="):
+			user = strings.TrimPrefix(arg, "--user=")
+		case strings.HasPrefix(arg, "--key="):
+			keyPath = config.ExpandHome(strings.TrimPrefix(arg, "--key="))
+		default:
+			return errors.New(i18n.T("cli.usage.discover"))
+		}
+	}
+	if user == "" {
+		user = currentUser()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	peers, err := discovery.TailscalePeers(ctx, socket)
+	if err != nil {
+		return err
+	}
+	if len(peers) == 0 {
+		fmt.Println(i18n.T("cli.discover.none"))
+		return nil
+	}
+	fmt.Printf(i18n.T("cli.discover.found")+"\n\n", len(peers))
+
+	discovered := discovery.TailscaleConnections(peers, user)
+	if keyPath != "" {
+		for i := range discovered {
+			discovered[i].AuthType = model.AuthKey
+			discovered[i].KeyPath = keyPath
+		}
+	}
+
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if !dryRun {
+		if err := unlockIfNeeded(cfg); err != nil {
+			return err
+		}
+	}
+
+	added, updated, skipped := discovery.MergeTailnet(cfg.Connections(), discovered)
+	printDiscoverPreview(discovered, added, updated)
+	fmt.Printf("\n"+i18n.T("cli.discover.pending")+"\n", len(added), len(updated), skipped)
+
+	if dryRun {
+		fmt.Println(i18n.T("cli.import.ssh.dry_run"))
+		return nil
+	}
+	if len(added) == 0 && len(updated) == 0 {
+		fmt.Println(i18n.T("cli.discover.up_to_date"))
+		return nil
+	}
+
+	fmt.Print(i18n.T("cli.confirm.proceed"))
+	var answer string
+	_, _ = fmt.Scanln(&answer)
+	if answer != "" && answer != "y" && answer != "Y" {
+		fmt.Println(i18n.T("cli.import.ssh.cancelled"))
+		return nil
+	}
+
+	saved := 0
+	for _, conn := range added {
+		if err := cfg.AddConnection(conn); err != nil {
+			fmt.Printf(i18n.T("cli.import.ssh.add_failed")+"\n", conn.Name, err)
+			continue
+		}
+		saved++
+	}
+	for _, conn := range updated {
+		if err := cfg.UpdateConnection(conn); err != nil {
+			fmt.Printf(i18n.T("cli.import.ssh.add_failed")+"\n", conn.Name, err)
+			continue
 		}
+		saved++
 	}
 
-	fmt.Printf("Successfully imported %d connections.\n", len(newConns))
+	fmt.Printf(i18n.T("cli.discover.done")+"\n", saved)
 	return nil
 }
 
+// printDiscoverPreview prints a table of discovered peers and what will
+// happen to each
+func printDiscoverPreview(discovered, added, updated []model.Connection) {
+	actions := make(map[string]string, len(discovered))
+	for _, c := range added {
+		actions[c.Name] = i18n.T("cli.discover.action.add")
+	}
+	for _, c := range updated {
+		actions[c.Name] = i18n.T("cli.discover.action.update")
+	}
+
+	fmt.Printf("%-20s %-40s %-20s %s\n", i18n.T("cli.list.name"), i18n.T("cli.list.host"), i18n.T("cli.discover.tags"), i18n.T("cli.import.ssh.action"))
+	fmt.Println("-------------------------------------------------------------------------------------------")
+	for _, conn := range discovered {
+		action, ok := actions[conn.Name]
+		if !ok {
+			action = i18n.T("cli.discover.action.keep")
+		}
+		tags := strings.Join(conn.Tags, ",")
+		if tags == "" {
+			tags = "-"
+		}
+		fmt.Printf("%-20s %-40s %-20s %s\n", conn.Name, conn.Host, tags, action)
+	}
+}
+
+// currentUser returns the local user name, the default login like ssh's
+func currentUser() string {
+	u, err := user.Current()
+	if err != nil {
+		return os.Getenv("USER")
+	}
+	// Windows names come as DOMAIN\user
+	name := u.Username
+	if i := strings.LastIndex(name, "\\"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// printImportPreview prints a table of parsed connections, marking the
+// ones that will be imported and the ones skipped as duplicates
+func printImportPreview(parsed, newConns []model.Connection) {
+	isNew := make(map[string]bool, len(newConns))
+	for _, c := range newConns {
+		isNew[c.Name] = true
+	}
+
+	fmt.Printf("%-20s %-30s %-6s %-12s %s\n", i18n.T("cli.list.name"), i18n.T("cli.list.host"), i18n.T("cli.list.port"), i18n.T("cli.import.ssh.key"), i18n.T("cli.import.ssh.action"))
+	fmt.Println("-------------------------------------------------------------------------------")
+	for _, conn := range parsed {
+		action := i18n.T("cli.import.ssh.action.skip")
+		if isNew[conn.Name] {
+			action = i18n.T("cli.import.ssh.action.add")
+		}
+		key := "-"
+		if conn.KeyPath != "" {
+			key = filepath.Base(conn.KeyPath)
+		}
+		fmt.Printf("%-20s %-30s %-6d %-12s %s\n", conn.Name, conn.User+"@"+conn.Host, conn.Port, key, action)
+	}
+}
+
 // runHealthCheck checks connection health
 func runHealthCheck(args []string) error {
 	cfg, err := config.NewManager()
@@ -348,7 +879,7 @@ func runHealthCheck(args []string) error {
 
 	connections := cfg.Connections()
 	if len(connections) == 0 {
-		fmt.Println("No connections found.")
+		fmt.Println(i18n.T("cli.no_connections"))
 		return nil
 	}
 
@@ -385,61 +916,56 @@ func runHealthCheck(args []string) error {
 	}
 
 	if len(toCheck) == 0 {
-		fmt.Println("No connections match the filter.")
+		fmt.Println(i18n.T("cli.check.no_match"))
 		return nil
 	}
 
-	fmt.Printf("Checking %d connection(s)...\n\n", len(toCheck))
+	fmt.Printf(i18n.T("cli.check.checking")+"\n\n", len(toCheck))
 
 	// Check each connection
 	for _, conn := range toCheck {
 		fmt.Printf("%-20s %s:%d ... ", conn.Name, conn.Host, conn.Port)
-		
-		err := ssh.QuickCheck(conn.Host, conn.Port, 5*time.Second)
+
+		err := ssh.CheckConnection(conn, 5*time.Second)
+		status := model.ConnStatusSuccess
 		if err != nil {
+			status = model.ConnStatusFailed
 			fmt.Printf("✗ %v\n", err)
 		} else {
-			fmt.Printf("✓ reachable\n")
+			fmt.Printf("✓ %s\n", i18n.T("cli.check.reachable"))
+		}
+
+		previous, _ := cfg.UpdateHealthStatus(conn.ID, status)
+		if p, changed := hooks.HealthChange(conn, previous, status, err); changed {
+			fireHooks(cfg, p)
 		}
 	}
 
 	return nil
 }
 
-// runList lists all connections
-func runList() error {
-	cfg, err := config.NewManager()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
-
-	if err := unlockIfNeeded(cfg); err != nil {
-		return err
-	}
-
-	connections := cfg.Connections()
-
-	if len(connections) == 0 {
-		fmt.Println("No connections found.")
-		return nil
-	}
-
-	fmt.Printf("%-20s %-30s %-10s %s\n", "NAME", "HOST", "PORT", "GROUP")
-	fmt.Println("-------------------------------------------------------------------------------")
-	for _, conn := range connections {
-		group := conn.Group
-		if group == "" {
-			group = "Ungrouped"
+// runFacts gathers the system facts shown in the list from the selected
+// SSH connections, all of them by default
+func runFacts(args []string) error {
+	var filter ssh.TargetFilter
+	timeout := 10 * time.Second
+	for _, arg := range args {
+		if ok, err := parseTargetArg(arg, &filter); err != nil {
+			return err
+		} else if ok {
+			continue
+		}
+		if strings.HasPrefix(arg, "--timeout=") {
+			var secs int
+			_, _ = fmt.Sscanf(strings.TrimPrefix(arg, "--timeout="), "%d", &secs)
+			if secs > 0 {
+				timeout = time.Duration(secs) * time.Second
+			}
+		} else {
+			return errors.New(i18n.T("cli.usage.facts"))
 		}
-		fmt.Printf("%-20s %-30s %-10d %s\n", conn.Name, conn.User+"@"+conn.Host, conn.Port, group)
 	}
 
-	fmt.Printf("\nTotal: %d connections\n", len(connections))
-	return nil
-}
-
-// runConnect connects to a server by name
-func runConnect(name string) error {
 	cfg, err := config.NewManager()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -449,212 +975,3474 @@ func runConnect(name string) error {
 		return err
 	}
 
-	conn := findConnection(cfg.Connections(), name)
-	if conn == nil {
-		return fmt.Errorf("connection '%s' not found", name)
+	var connections []model.Connection
+	for _, conn := range filter.Apply(cfg.Connections()) {
+		if !conn.IsTelnet() {
+			connections = append(connections, conn)
+		}
+	}
+	if len(connections) == 0 {
+		return errors.New(i18n.T("cli.error.no_match"))
 	}
 
-	fmt.Printf("Connecting to %s (%s@%s:%d)...\n", conn.Name, conn.User, conn.Host, conn.Port)
+	fmt.Printf(i18n.T("cli.facts.gathering")+"\n\n", len(connections))
 
-	terminal := ssh.NewTerminal(*conn)
-	err = terminal.Run()
+	executor := ssh.NewBatchExecutor(connections)
+	executor.SetTimeout(timeout)
 
-	if err != nil {
-		_ = cfg.UpdateConnectionStatus(conn.ID, model.ConnStatusFailed)
-		// Avoid double-wrapping "connection lost" errors
-		if strings.Contains(err.Error(), "connection lost") {
+	now := time.Now()
+	for _, r := range executor.Execute(context.Background(), ssh.FactsCommand) {
+		fmt.Printf("%-20s ", r.Connection.Name)
+		if r.Error != nil {
+			fmt.Printf("✗ %v\n", r.Error)
+			continue
+		}
+		facts, err := ssh.ParseFacts(r.Output, now)
+		if err != nil {
+			fmt.Printf("✗ %v\n", err)
+			continue
+		}
+		if err := cfg.SetFacts(r.Connection.ID, facts); err != nil {
 			return err
 		}
-		return fmt.Errorf("connection failed: %w", err)
+
+		line := facts.OS()
+		if facts.Kernel != "" {
+			line += " (" + facts.Kernel + ")"
+		}
+		if uptime := facts.Uptime(now); uptime > 0 {
+			line += ", " + fmt.Sprintf(i18n.T("list.uptime"), model.ShortDuration(uptime))
+		}
+		fmt.Printf("✓ %s\n", line)
 	}
 
-	_ = cfg.UpdateConnectionStatus(conn.ID, model.ConnStatusSuccess)
 	return nil
 }
 
-// runSFTP starts an SFTP session
-func runSFTP(name string) error {
+// runReport writes an inventory report of the selected connections, all of
+// them by default
+func runReport(args []string) error {
+	var filter ssh.TargetFilter
+	var format report.Format
+	output := ""
+	maxAge := 0
+	for _, arg := range args {
+		if ok, err := parseTargetArg(arg, &filter); err != nil {
+			return err
+		} else if ok {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(arg, "--format="):
+			f, err := report.ParseFormat(strings.TrimPrefix(arg, "--format="))
+			if err != nil {
+				return err
+			}
+			format = f
+		case strings.HasPrefix(arg, "--output="):
+			output = strings.TrimPrefix(arg, "--output=")
+		case strings.HasPrefix(arg, "--max-age="):
+			days, err := strconv.Atoi(strings.TrimPrefix(arg, "--max-age="))
+			if err != nil || days <= 0 {
+				return errors.New(i18n.T("cli.usage.report"))
+			}
+			maxAge = days
+		default:
+			return errors.New(i18n.T("cli.usage.report"))
+		}
+	}
+	if format == "" {
+		format = report.FormatFor(output)
+	}
+
+	// Only metadata is needed, so the config stays locked
 	cfg, err := config.NewManager()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if err := unlockIfNeeded(cfg); err != nil {
-		return err
+	connections := filter.Apply(cfg.Connections())
+	if len(connections) == 0 {
+		return errors.New(i18n.T("cli.error.no_match"))
 	}
 
-	conn := findConnection(cfg.Connections(), name)
+	r := report.New(connections, time.Now(), maxAge, func(path string) string {
+		key, err := ssh.InspectKey(path)
+		if err != nil {
+			return ""
+		}
+		return key.Type
+	})
+
+	if output == "" {
+		return r.Write(os.Stdout, format)
+	}
+	// The inventory names hosts and users, so keep it private like the config
+	f, err := config.OpenPrivateFile(output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return err
+	}
+	if err := r.Write(f, format); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf(i18n.T("cli.report.done")+"\n", len(r.Hosts), output)
+	return nil
+}
+
+// fireHooks runs the configured hooks for an event, reporting failures
+// without aborting the command
+func fireHooks(cfg *config.Manager, p hooks.Payload) {
+	for _, err := range hooks.NewDispatcher(cfg.Hooks()).Fire(p) {
+		fmt.Fprintf(os.Stderr, i18n.T("cli.hook.failed")+"\n", err)
+	}
+}
+
+// hostKeyHooks returns a host key manager's OnChange function firing the
+// hostkey.change hooks for conn
+func hostKeyHooks(cfg *config.Manager, conn model.Connection) func(ssh.HostKeyResult) {
+	return func(result ssh.HostKeyResult) {
+		fireHooks(cfg, hooks.HostKeyChange(conn, result.OldKey, result.Fingerprint))
+	}
+}
+
+// notifyDone notifies the user that a task started at started has finished,
+// unless it was quick enough that they are still watching
+func notifyDone(cfg *config.Manager, started time.Time, body string) {
+	settings := cfg.GetSettings()
+	if time.Since(started) < settings.NotifyAfter() {
+		return
+	}
+	mode := settings.NotifyMode()
+	if mode == model.NotifyOff {
+		return
+	}
+	_ = notify.Send(mode, "gossh", body)
+	if mode == model.NotifyBell {
+		fmt.Fprintln(os.Stderr, body)
+	}
+}
+
+// notifyFailed notifies the user that a task nobody is watching failed,
+// however long it took
+func notifyFailed(cfg *config.Manager, body string) {
+	settings := cfg.GetSettings()
+	_ = notify.Send(settings.NotifyMode(), "gossh", body)
+}
+
+// runList lists all connections
+func runList() error {
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := unlockIfNeeded(cfg); err != nil {
+		return err
+	}
+
+	connections := cfg.Connections()
+
+	if len(connections) == 0 {
+		fmt.Println(i18n.T("cli.no_connections"))
+		return nil
+	}
+
+	fmt.Printf("%-20s %-30s %-10s %s\n", i18n.T("cli.list.name"), i18n.T("cli.list.host"), i18n.T("cli.list.port"), i18n.T("cli.list.group"))
+	fmt.Println("-------------------------------------------------------------------------------")
+	for _, conn := range connections {
+		group := conn.Group
+		if group == "" {
+			group = i18n.T("list.ungrouped")
+		}
+		fmt.Printf("%-20s %-30s %-10d %s\n", conn.Name, conn.User+"@"+conn.Host, conn.Port, group)
+	}
+
+	fmt.Printf("\n"+i18n.T("cli.list.total")+"\n", len(connections))
+	return nil
+}
+
+// runAuditCredentials reports connections that are expired or overdue for
+// credential rotation and fails when any need attention
+func runAuditCredentials(args []string) error {
+	maxAge := 0
+	groupFilter := ""
+	showAll := false
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--max-age=") {
+			days, err := strconv.Atoi(strings.TrimPrefix(arg, "--max-age="))
+			if err != nil || days <= 0 {
+				return errors.New(i18n.T("cli.usage.audit"))
+			}
+			maxAge = days
+		} else if strings.HasPrefix(arg, "--group=") {
+			groupFilter = strings.TrimPrefix(arg, "--group=")
+		} else if arg == "--all" {
+			showAll = true
+		}
+	}
+
+	// Only metadata is needed, so the config stays locked
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var connections []model.Connection
+	for _, conn := range cfg.Connections() {
+		if groupFilter == "" || conn.Group == groupFilter {
+			connections = append(connections, conn)
+		}
+	}
+	if len(connections) == 0 {
+		fmt.Println(i18n.T("cli.no_connections"))
+		return nil
+	}
+
+	now := time.Now()
+	stale := 0
+	fmt.Printf("%-20s %-30s %-12s %-8s %-12s %s\n", i18n.T("cli.list.name"), i18n.T("cli.list.host"),
+		i18n.T("cli.audit.changed"), i18n.T("cli.audit.age"), i18n.T("cli.audit.due"), i18n.T("cli.audit.status"))
+	fmt.Println(strings.Repeat("-", 100))
+	for _, conn := range connections {
+		state := conn.Credentials(now, maxAge)
+		if state != model.CredentialOK {
+			stale++
+		} else if !showAll {
+			continue
+		}
+
+		changed := conn.CredentialsChangedAt()
+		due := "-"
+		if t := conn.RotationDue(maxAge); !t.IsZero() {
+			due = t.Format("2006-01-02")
+		}
+		if conn.ExpiresAt != nil && (due == "-" || conn.ExpiresAt.Format("2006-01-02") < due) {
+			due = conn.ExpiresAt.Format("2006-01-02")
+		}
+		age := fmt.Sprintf("%dd", int(now.Sub(changed).Hours()/24))
+
+		fmt.Printf("%-20s %-30s %-12s %-8s %-12s %s\n", conn.Name, conn.User+"@"+conn.Host,
+			changed.Format("2006-01-02"), age, due, i18n.T("cli.audit.state."+string(state)))
+	}
+
+	fmt.Printf("\n"+i18n.T("cli.audit.summary")+"\n", stale, len(connections))
+	if stale > 0 {
+		return fmt.Errorf(i18n.T("cli.audit.failed"), stale)
+	}
+	return nil
+}
+
+// runValidate reports problems with the stored connections and fails when
+// there are any
+func runValidate() error {
+	// Only metadata is needed, so the config stays locked
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	total := len(cfg.Connections())
+	if total == 0 {
+		fmt.Println(i18n.T("cli.no_connections"))
+		return nil
+	}
+
+	problems := cfg.Validate()
+	if len(problems) == 0 {
+		fmt.Printf(i18n.T("cli.validate.ok")+"\n", total)
+		return nil
+	}
+
+	affected := make(map[string]bool)
+	fmt.Printf("%-20s %-30s %s\n", i18n.T("cli.list.name"), i18n.T("cli.list.host"), i18n.T("cli.validate.problem"))
+	fmt.Println(strings.Repeat("-", 100))
+	for _, p := range problems {
+		affected[p.Connection.ID] = true
+		fmt.Printf("%-20s %-30s %s\n", p.Connection.Name, p.Connection.User+"@"+p.Connection.Host, views.ErrorText(p.Err))
+	}
+
+	fmt.Printf("\n"+i18n.T("cli.validate.summary")+"\n", len(problems), len(affected), total)
+	return fmt.Errorf(i18n.T("cli.validate.failed"), len(problems))
+}
+
+// runRemove removes a connection by name
+func runRemove(args []string) error {
+	var name string
+	force, purge := false, false
+	for _, arg := range args {
+		switch {
+		case arg == "--force" || arg == "-f":
+			force = true
+		case arg == "--purge":
+			purge = true
+		case name == "" && !strings.HasPrefix(arg, "-"):
+			name = arg
+		default:
+			// A mistyped flag must not be taken for the name
+			return errors.New(i18n.T("cli.usage.rm"))
+		}
+	}
+	if name == "" {
+		return errors.New(i18n.T("cli.usage.rm"))
+	}
+
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := unlockIfNeeded(cfg); err != nil {
+		return err
+	}
+
+	conn := findConnection(cfg.Connections(), name)
+	if conn == nil {
+		return fmt.Errorf(i18n.T("cli.error.not_found"), name)
+	}
+
+	if !force {
+		prompt := i18n.T("cli.rm.confirm")
+		if purge {
+			prompt = i18n.T("cli.rm.confirm_purge")
+		}
+		fmt.Printf(prompt, conn.Name, conn.User, conn.Host, conn.Port)
+		var answer string
+		_, _ = fmt.Scanln(&answer)
+		if answer != "y" && answer != "Y" {
+			fmt.Println(i18n.T("cli.aborted"))
+			return nil
+		}
+	}
+
+	if err := cfg.DeleteConnection(conn.ID); err != nil {
+		return fmt.Errorf("failed to delete connection: %w", err)
+	}
+
+	if purge {
+		if err := cfg.PurgeConnection(conn.ID); err != nil {
+			return fmt.Errorf("failed to purge connection: %w", err)
+		}
+		fmt.Printf(i18n.T("cli.rm.purged")+"\n", conn.Name)
+		return nil
+	}
+
+	fmt.Printf(i18n.T("cli.rm.done")+"\n", conn.Name)
+	return nil
+}
+
+// runDedupe finds connections to the same user, host and port and merges
+// each set into one, asking first unless --yes is given
+func runDedupe(args []string) error {
+	dryRun, yes := false, false
+	for _, arg := range args {
+		switch arg {
+		case "--dry-run":
+			dryRun = true
+		case "--yes", "-y":
+			yes = true
+		default:
+			return errors.New(i18n.T("cli.usage.dedupe"))
+		}
+	}
+
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := unlockIfNeeded(cfg); err != nil {
+		return err
+	}
+
+	sets := cfg.Duplicates()
+	if len(sets) == 0 {
+		fmt.Println(i18n.T("cli.dedupe.none"))
+		return nil
+	}
+
+	merged := 0
+	for i, set := range sets {
+		keep := model.PickSurvivor(set)
+		fmt.Printf(i18n.T("cli.dedupe.set")+"\n", i+1, len(sets), set[0].DuplicateKey())
+		for j, c := range set {
+			marker := " "
+			if j == keep {
+				marker = "*"
+			}
+			last := i18n.T("cli.dedupe.never")
+			if c.LastConnected != nil {
+				last = c.LastConnected.Format("2006-01-02")
+			}
+			group := c.Group
+			if group == "" {
+				group = "-"
+			}
+			fmt.Printf("  %s %-20s %-15s %-20s %-12s %s\n", marker, c.Name, group, strings.Join(c.Tags, ","), last,
+				fmt.Sprintf(i18n.T("cli.dedupe.sessions"), len(c.History)))
+		}
+
+		if dryRun {
+			fmt.Println()
+			continue
+		}
+		if !yes {
+			fmt.Printf(i18n.T("cli.dedupe.confirm"), set[keep].Name)
+			var answer string
+			_, _ = fmt.Scanln(&answer)
+			if answer != "" && answer != "y" && answer != "Y" {
+				fmt.Println()
+				continue
+			}
+		}
+
+		var ids []string
+		for j, c := range set {
+			if j != keep {
+				ids = append(ids, c.ID)
+			}
+		}
+		if _, err := cfg.Dedupe(set[keep].ID, ids); err != nil {
+			return fmt.Errorf("failed to merge duplicates: %w", err)
+		}
+		merged += len(ids)
+		fmt.Printf(i18n.T("cli.dedupe.merged")+"\n\n", len(ids), set[keep].Name)
+	}
+
+	if dryRun {
+		fmt.Println(i18n.T("cli.import.ssh.dry_run"))
+		return nil
+	}
+	fmt.Printf(i18n.T("cli.dedupe.done")+"\n", merged)
+	return nil
+}
+
+// runHistory lists the edits of a connection, shows what one changed or
+// restores the version before it. Edits are numbered newest first.
+func runHistory(args []string) error {
+	var name, number string
+	restore := false
+	for _, arg := range args {
+		switch {
+		case arg == "--restore":
+			restore = true
+		case name == "":
+			name = arg
+		case number == "":
+			number = arg
+		default:
+			return errors.New(i18n.T("cli.usage.history"))
+		}
+	}
+	if name == "" || (restore && number == "") {
+		return errors.New(i18n.T("cli.usage.history"))
+	}
+
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := unlockIfNeeded(cfg); err != nil {
+		return err
+	}
+
+	conn := findConnection(cfg.Connections(), name)
+	if conn == nil {
+		return fmt.Errorf(i18n.T("cli.error.not_found"), name)
+	}
+
+	revisions := cfg.Revisions(conn.ID)
+	if number == "" {
+		if len(revisions) == 0 {
+			fmt.Printf(i18n.T("cli.history.empty")+"\n", conn.Name)
+			return nil
+		}
+		fmt.Printf("%-4s %-17s %-12s %s\n", "#", i18n.T("cli.history.when"), i18n.T("cli.history.by"), i18n.T("cli.history.fields"))
+		fmt.Println(strings.Repeat("-", 80))
+		for i := len(revisions) - 1; i >= 0; i-- {
+			r := revisions[i]
+			fmt.Printf("%-4d %-17s %-12s %s\n", len(revisions)-i, r.At.Local().Format("2006-01-02 15:04"), r.By, strings.Join(r.Fields, ", "))
+		}
+		return nil
+	}
+
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 1 || n > len(revisions) {
+		return fmt.Errorf(i18n.T("cli.history.not_found"), number, conn.Name)
+	}
+	index := len(revisions) - n
+
+	if restore {
+		if _, err := cfg.RestoreRevision(conn.ID, index); err != nil {
+			return fmt.Errorf("failed to restore revision: %w", err)
+		}
+		fmt.Printf(i18n.T("cli.history.restored")+"\n", conn.Name, n)
+		return nil
+	}
+
+	changes, err := cfg.RevisionChanges(conn.ID, index)
+	if err != nil {
+		return err
+	}
+	r := revisions[index]
+	fmt.Printf(i18n.T("cli.history.edit")+"\n", n, r.At.Local().Format("2006-01-02 15:04"), r.By)
+	for _, c := range changes {
+		before, after := c.Before, c.After
+		if c.Secret {
+			// Only whether it was set; the encrypted values always differ
+			before, after = strings.Repeat("*", min(len(before), 6)), strings.Repeat("*", min(len(after), 6))
+		}
+		fmt.Printf("  %-22s %q → %q\n", c.Field, before, after)
+	}
+	return nil
+}
+
+// runTrash lists, restores or purges connections in the trash
+func runTrash(args []string) error {
+	action := "list"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := unlockIfNeeded(cfg); err != nil {
+		return err
+	}
+
+	switch action {
+	case "list", "ls":
+		trash := cfg.TrashedConnections()
+		if len(trash) == 0 {
+			fmt.Println(i18n.T("cli.trash.empty"))
+			return nil
+		}
+
+		fmt.Printf("%-20s %-30s %-12s %s\n", i18n.T("cli.list.name"), i18n.T("cli.list.host"), i18n.T("cli.trash.deleted"), i18n.T("cli.trash.expires"))
+		fmt.Println(strings.Repeat("-", 80))
+		for _, conn := range trash {
+			deleted := ""
+			if conn.DeletedAt != nil {
+				deleted = conn.DeletedAt.Format("2006-01-02")
+			}
+			expires := cfg.TrashExpiry(conn).Format("2006-01-02")
+			fmt.Printf("%-20s %-30s %-12s %s\n", conn.Name, conn.User+"@"+conn.Host, deleted, expires)
+		}
+		fmt.Printf("\n"+i18n.T("cli.list.total")+"\n", len(trash))
+		return nil
+
+	case "restore", "purge":
+		if len(args) < 2 {
+			return errors.New(i18n.T("cli.usage.trash"))
+		}
+		conn := findConnection(cfg.TrashedConnections(), args[1])
+		if conn == nil {
+			return fmt.Errorf(i18n.T("cli.trash.not_found"), args[1])
+		}
+
+		if action == "restore" {
+			if err := cfg.RestoreConnection(conn.ID); err != nil {
+				return fmt.Errorf("failed to restore connection: %w", err)
+			}
+			fmt.Printf(i18n.T("cli.trash.restored")+"\n", conn.Name)
+			return nil
+		}
+
+		if err := cfg.PurgeConnection(conn.ID); err != nil {
+			return fmt.Errorf("failed to purge connection: %w", err)
+		}
+		fmt.Printf(i18n.T("cli.rm.purged")+"\n", conn.Name)
+		return nil
+
+	case "empty":
+		count, err := cfg.EmptyTrash()
+		if err != nil {
+			return fmt.Errorf("failed to empty trash: %w", err)
+		}
+		fmt.Printf(i18n.T("cli.trash.emptied")+"\n", count)
+		return nil
+	}
+
+	return errors.New(i18n.T("cli.usage.trash"))
+}
+
+// runRename renames a connection
+func runRename(args []string) error {
+	if len(args) < 2 {
+		return errors.New(i18n.T("cli.usage.rename"))
+	}
+	oldName, newName := args[0], args[1]
+
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := unlockIfNeeded(cfg); err != nil {
+		return err
+	}
+
+	conn := findConnection(cfg.Connections(), oldName)
+	if conn == nil {
+		return fmt.Errorf(i18n.T("cli.error.not_found"), oldName)
+	}
+
+	if err := cfg.RenameConnection(conn.ID, newName); err != nil {
+		return fmt.Errorf("failed to rename connection: %w", err)
+	}
+
+	fmt.Printf(i18n.T("cli.rename.done")+"\n", oldName, newName)
+	return nil
+}
+
+// runLock makes running gossh sessions on this config lock, as ctrl+l
+// does in one of them
+func runLock() error {
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := cfg.RequestLock(); err != nil {
+		if errors.Is(err, config.ErrNotPasswordProtected) {
+			return errors.New(i18n.T("cli.lock.unprotected"))
+		}
+		return fmt.Errorf("failed to lock: %w", err)
+	}
+	if err := keycache.Clear(cfg.Path()); err != nil {
+		return fmt.Errorf("failed to lock: %w", err)
+	}
+
+	fmt.Println(i18n.T("cli.lock.done"))
+	return nil
+}
+
+// runRemember stores the master key in the OS keyring, after asking for
+// the master password
+func runRemember() error {
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if !cfg.IsPasswordProtected() {
+		return errors.New(i18n.T("cli.remember.unprotected"))
+	}
+
+	if err := unlockIfNeeded(cfg); err != nil {
+		return err
+	}
+	if err := cfg.RememberKey(); err != nil {
+		return fmt.Errorf("failed to remember the master key: %w", err)
+	}
+
+	fmt.Println(i18n.T("cli.remember.done"))
+	return nil
+}
+
+// runForget removes the master key runRemember stored
+func runForget() error {
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := cfg.ForgetKey(); err != nil {
+		return fmt.Errorf("failed to forget the master key: %w", err)
+	}
+
+	fmt.Println(i18n.T("cli.forget.done"))
+	return nil
+}
+
+// runPlugins lists the plugins installed in the plugins directory
+func runPlugins() error {
+	dir, err := config.PluginsDir()
+	if err != nil {
+		return err
+	}
+	list, err := plugins.List(dir)
+	if err != nil {
+		return fmt.Errorf("failed to list plugins: %w", err)
+	}
+	if len(list) == 0 {
+		fmt.Printf(i18n.T("cli.plugins.none")+"\n", dir, plugins.Prefix)
+		return nil
+	}
+	for _, p := range list {
+		fmt.Printf("%-20s %s\n", p.Name, p.Path)
+	}
+	return nil
+}
+
+// runPlugin runs a plugin as gossh <name>. When its first argument names a
+// connection, the plugin gets it on stdin, with its secrets if the plugin
+// is trusted with them, which needs the config unlocked.
+func runPlugin(plugin plugins.Plugin, args []string) error {
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	input := plugins.Input{Args: args, ConfigPath: cfg.Path()}
+	if len(args) > 0 && !cfg.IsFirstRun() {
+		if conn := findConnection(cfg.Connections(), args[0]); conn != nil {
+			secrets := plugin.Trusted(cfg.GetSettings().PluginSecrets)
+			if secrets {
+				if err := unlockIfNeeded(cfg); err != nil {
+					return err
+				}
+				conn = findConnection(cfg.Connections(), args[0])
+			}
+			input.Connection = plugins.NewConnection(*conn, secrets)
+		}
+	}
+
+	cmd, err := plugins.Command(plugin, input)
+	if err != nil {
+		return err
+	}
+	if err := cmd.Run(); err != nil {
+		if code := shell.ExitCode(err); code > 0 {
+			return &ExitError{Code: code}
+		}
+		return fmt.Errorf(i18n.T("cli.plugin.failed"), plugin.Name, err)
+	}
+	return nil
+}
+
+// runPrintSSHConfig prints OpenSSH Host blocks for the connections, with
+// gossh stdio as their ProxyCommand, for ssh, scp and editors' remote
+// extensions to reach the hosts the way gossh does. No secrets are needed,
+// so the config stays locked.
+func runPrintSSHConfig(args []string) error {
+	var filter ssh.TargetFilter
+	for _, arg := range args {
+		if ok, err := parseTargetArg(arg, &filter); err != nil {
+			return err
+		} else if !ok {
+			return errors.New(i18n.T("cli.usage.print_ssh_config"))
+		}
+	}
+
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.IsFirstRun() {
+		return errors.New(i18n.T("cli.error.first_run"))
+	}
+	command, err := cfg.StdioCommand()
+	if err != nil {
+		return err
+	}
+	fmt.Print(sshconfig.FormatProxied(filter.Apply(cfg.Connections()), command))
+	return nil
+}
+
+// runStdio connects stdin and stdout to the SSH port of the named
+// connection, for use as ssh's ProxyCommand, or with an address to that
+// address as reached from the connection: gossh logs in to it and the
+// connection acts as a jump host or tunnel. Without an address only jump
+// hosts log in, so the config is only unlocked for connections that have
+// some.
+func runStdio(args []string) error {
+	if len(args) != 1 && len(args) != 2 {
+		return errors.New(i18n.T("cli.usage.stdio"))
+	}
+	var addr string
+	if len(args) == 2 {
+		host, port, err := net.SplitHostPort(args[1])
+		if n, convErr := strconv.Atoi(port); err != nil || convErr != nil || n < 1 || n > 65535 {
+			return errors.New(i18n.T("cli.usage.stdio"))
+		}
+		if host == "" {
+			// :5432 is a port of the host itself
+			host = "localhost"
+		}
+		addr = net.JoinHostPort(host, port)
+	}
+
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.IsFirstRun() {
+		return errors.New(i18n.T("cli.error.first_run"))
+	}
+	conn := findConnection(cfg.Connections(), args[0])
+	if conn == nil {
+		return fmt.Errorf(i18n.T("cli.error.not_found"), args[0])
+	}
+	if addr != "" || len(conn.JumpHosts) > 0 {
+		if err := unlockIfNeeded(cfg); err != nil {
+			return err
+		}
+		conn = findConnection(cfg.Connections(), args[0])
+	}
+
+	hkm, err := ssh.NewHostKeyManager()
+	if err != nil {
+		return err
+	}
+	hkm.OnChange(hostKeyHooks(cfg, *conn))
+	callback := ssh.VerifyHostKeyCallback(hkm)
+	var netConn net.Conn
+	if addr != "" {
+		netConn, err = ssh.DialThrough(*conn, callback, addr)
+	} else {
+		netConn, err = ssh.DialTransport(*conn, callback)
+	}
+	if err != nil {
+		return err
+	}
+	return pipeStdio(netConn)
+}
+
+// runAgent lists the keys held by the running SSH agent, with the
+// connections pinned to each, or pins a connection to one of them so only
+// that key of the agent's is offered
+func runAgent(args []string) error {
+	usage := errors.New(i18n.T("cli.usage.agent"))
+	action := "list"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	switch action {
+	case "list", "ls":
+		if len(args) > 1 {
+			return usage
+		}
+		keys, err := ssh.AgentKeys()
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			fmt.Println(i18n.T("cli.agent.empty"))
+			return nil
+		}
+		pinned := make(map[string][]string)
+		for _, conn := range cfg.Connections() {
+			if conn.AgentKey != "" {
+				pinned[conn.AgentKey] = append(pinned[conn.AgentKey], conn.Name)
+			}
+		}
+		for _, key := range keys {
+			fmt.Printf("%s  %-20s %s\n", key.Fingerprint, key.Type, key.Comment)
+			if names := pinned[key.Fingerprint]; len(names) > 0 {
+				fmt.Printf("  "+i18n.T("cli.agent.pinned_by")+"\n", strings.Join(names, ", "))
+			}
+		}
+		return nil
+
+	case "pin":
+		if len(args) != 3 {
+			return usage
+		}
+		if err := unlockIfNeeded(cfg); err != nil {
+			return err
+		}
+		conn := findConnection(cfg.Connections(), args[1])
+		if conn == nil {
+			return fmt.Errorf(i18n.T("cli.error.not_found"), args[1])
+		}
+		keys, err := ssh.AgentKeys()
+		if err != nil {
+			return err
+		}
+		key, err := findAgentKey(keys, args[2])
+		if err != nil {
+			return err
+		}
+
+		conn.AuthType = model.AuthKey
+		conn.AgentKey = key.Fingerprint
+		if err := cfg.UpdateConnection(*conn); err != nil {
+			return fmt.Errorf("failed to save connection: %w", err)
+		}
+		fmt.Printf(i18n.T("cli.agent.pinned")+"\n", conn.Name, key.Fingerprint, key.Comment)
+		return nil
+
+	case "unpin":
+		if len(args) != 2 {
+			return usage
+		}
+		if err := unlockIfNeeded(cfg); err != nil {
+			return err
+		}
+		conn := findConnection(cfg.Connections(), args[1])
+		if conn == nil {
+			return fmt.Errorf(i18n.T("cli.error.not_found"), args[1])
+		}
+		if conn.AgentKey == "" {
+			fmt.Printf(i18n.T("cli.agent.not_pinned")+"\n", conn.Name)
+			return nil
+		}
+		if conn.AuthType == model.AuthKey && conn.KeyPath == "" {
+			// The pinned key is all it has to log in with
+			return fmt.Errorf(i18n.T("cli.agent.no_key_file"), conn.Name)
+		}
+
+		conn.AgentKey = ""
+		if err := cfg.UpdateConnection(*conn); err != nil {
+			return fmt.Errorf("failed to save connection: %w", err)
+		}
+		fmt.Printf(i18n.T("cli.agent.unpinned")+"\n", conn.Name)
+		return nil
+	}
+
+	return usage
+}
+
+// findAgentKey returns the key of the agent's with the given fingerprint,
+// with or without its SHA256: prefix, or else the one with that comment
+func findAgentKey(keys []ssh.AgentKey, name string) (ssh.AgentKey, error) {
+	fingerprint := name
+	if !strings.HasPrefix(fingerprint, "SHA256:") {
+		fingerprint = "SHA256:" + fingerprint
+	}
+	var byComment []ssh.AgentKey
+	for _, key := range keys {
+		if key.Fingerprint == fingerprint {
+			return key, nil
+		}
+		if key.Comment == name {
+			byComment = append(byComment, key)
+		}
+	}
+
+	switch len(byComment) {
+	case 0:
+		return ssh.AgentKey{}, fmt.Errorf(i18n.T("cli.agent.key_not_found"), name)
+	case 1:
+		return byComment[0], nil
+	}
+	return ssh.AgentKey{}, fmt.Errorf(i18n.T("cli.agent.key_ambiguous"), name)
+}
+
+// runProbe checks which ports a host can reach by opening forwarding
+// channels from it, telling a network problem from a service one without
+// logging in to a shell. It fails unless every port is open.
+func runProbe(args []string) error {
+	usage := errors.New(i18n.T("cli.usage.probe"))
+	var name, portSpec string
+	host := "localhost"
+	timeout := 5 * time.Second
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--ports" && i+1 < len(args):
+			i++
+			portSpec = args[i]
+		case strings.HasPrefix(arg, "--ports="):
+			portSpec = strings.TrimPrefix(arg, "--ports=")
+		case arg == "--host" && i+1 < len(args):
+			i++
+			host = args[i]
+		case strings.HasPrefix(arg, "--host="):
+			host = strings.TrimPrefix(arg, "--host=")
+		case strings.HasPrefix(arg, "--timeout="):
+			secs, err := strconv.Atoi(strings.TrimPrefix(arg, "--timeout="))
+			if err != nil || secs <= 0 {
+				return usage
+			}
+			timeout = time.Duration(secs) * time.Second
+		case name == "" && !strings.HasPrefix(arg, "-"):
+			name = arg
+		default:
+			return usage
+		}
+	}
+	if name == "" || portSpec == "" || host == "" {
+		return usage
+	}
+	ports, err := ssh.ParsePorts(portSpec)
+	if err != nil {
+		return fmt.Errorf("--ports: %w", err)
+	}
+
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := unlockIfNeeded(cfg); err != nil {
+		return err
+	}
+	conn := findConnection(cfg.Connections(), name)
+	if conn == nil {
+		return fmt.Errorf(i18n.T("cli.error.not_found"), name)
+	}
+
+	hkm, err := ssh.NewHostKeyManager()
+	if err != nil {
+		return err
+	}
+	hkm.OnChange(hostKeyHooks(cfg, *conn))
+	client, err := ssh.ConnectWithConnection(*conn, ssh.VerifyHostKeyCallback(hkm))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	fmt.Printf(i18n.T("cli.probe.probing")+"\n\n", len(ports), host, conn.Name)
+	states := map[ssh.PortState]string{
+		ssh.PortOpen:      i18n.T("cli.probe.open"),
+		ssh.PortClosed:    i18n.T("cli.probe.closed"),
+		ssh.PortTimeout:   i18n.T("cli.probe.timeout"),
+		ssh.PortForbidden: i18n.T("cli.probe.forbidden"),
+	}
+	// Padded by display width, the labels may be CJK
+	width := 0
+	for _, label := range states {
+		width = max(width, lipgloss.Width(label))
+	}
+	column := lipgloss.NewStyle().Width(width)
+
+	open := 0
+	for _, r := range ssh.ProbePorts(client, host, ports, timeout) {
+		mark, detail := "✗", r.Reason
+		switch r.State {
+		case ssh.PortOpen:
+			open++
+			mark, detail = "✓", fmt.Sprintf("%4dms  %s", r.Latency.Milliseconds(), r.Banner)
+		case ssh.PortTimeout:
+			detail = fmt.Sprintf(i18n.T("cli.probe.no_answer"), timeout)
+		}
+		fmt.Printf("  %s %-11s %s  %s\n", mark, fmt.Sprintf("%d/tcp", r.Port), column.Render(states[r.State]), strings.TrimRight(detail, " "))
+	}
+	fmt.Printf("\n"+i18n.T("cli.probe.summary")+"\n", open, len(ports))
+	if open < len(ports) {
+		return &ExitError{Code: 1}
+	}
+	return nil
+}
+
+// pipeStdio copies stdin to conn and conn to stdout until conn is closed,
+// like netcat, then closes it. The end of stdin is passed on to conn.
+func pipeStdio(conn net.Conn) error {
+	defer conn.Close()
+	go func() {
+		_, _ = io.Copy(conn, os.Stdin)
+		if cw, ok := conn.(interface{ CloseWrite() error }); ok {
+			_ = cw.CloseWrite()
+		}
+	}()
+	_, err := io.Copy(os.Stdout, conn)
+	return err
+}
+
+// runWorkspace lists, saves, removes, opens workspaces or runs one of
+// their snippets
+func runWorkspace(args []string) error {
+	usage := errors.New(i18n.T("cli.usage.workspace"))
+	action := "list"
+	if len(args) > 0 {
+		action = args[0]
+	}
+	if action != "list" && action != "ls" && len(args) < 2 {
+		return usage
+	}
+
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := unlockIfNeeded(cfg); err != nil {
+		return err
+	}
+
+	switch action {
+	case "list", "ls":
+		workspaces := cfg.Workspaces()
+		if len(workspaces) == 0 {
+			fmt.Println(i18n.T("cli.workspace.empty"))
+			return nil
+		}
+		for _, w := range workspaces {
+			printWorkspace(w)
+		}
+		return nil
+
+	case "save":
+		w, err := parseWorkspace(cfg, args[1], args[2:])
+		if err != nil {
+			return err
+		}
+		if err := cfg.SaveWorkspace(w); err != nil {
+			return fmt.Errorf("failed to save workspace: %w", err)
+		}
+		fmt.Printf(i18n.T("cli.workspace.saved")+"\n", w.Name)
+		return nil
+
+	case "rm":
+		if err := cfg.DeleteWorkspace(args[1]); err != nil {
+			return fmt.Errorf(i18n.T("cli.workspace.not_found"), args[1])
+		}
+		fmt.Printf(i18n.T("cli.workspace.removed")+"\n", args[1])
+		return nil
+
+	case "open":
+		w, ok := cfg.Workspace(args[1])
+		if !ok {
+			return fmt.Errorf(i18n.T("cli.workspace.not_found"), args[1])
+		}
+		return openWorkspace(cfg, w)
+
+	case "run":
+		if len(args) < 3 {
+			return usage
+		}
+		w, ok := cfg.Workspace(args[1])
+		if !ok {
+			return fmt.Errorf(i18n.T("cli.workspace.not_found"), args[1])
+		}
+		snippet, ok := w.Snippet(args[2])
+		if !ok {
+			return fmt.Errorf(i18n.T("cli.workspace.no_snippet"), args[2], w.Name)
+		}
+		if len(w.Connections) == 0 {
+			return errors.New(i18n.T("cli.error.no_match"))
+		}
+		return runExec(append([]string{snippet.Command, "--names=" + strings.Join(w.Connections, ",")}, args[3:]...))
+	}
+
+	return usage
+}
+
+// parseWorkspace builds a workspace from the arguments of workspace save:
+// connection names, -L/-R <connection>=<spec> and --snippet <name>=<command>
+func parseWorkspace(cfg *config.Manager, name string, args []string) (model.Workspace, error) {
+	usage := errors.New(i18n.T("cli.usage.workspace"))
+	connections := cfg.Connections()
+	resolve := func(name string) (string, error) {
+		conn := findConnection(connections, name)
+		if conn == nil {
+			return "", fmt.Errorf(i18n.T("cli.error.not_found"), name)
+		}
+		return conn.Name, nil
+	}
+
+	w := model.Workspace{Name: name}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "-L", "-R", "--snippet":
+			if i+1 >= len(args) {
+				return w, usage
+			}
+			i++
+			key, value, ok := strings.Cut(args[i], "=")
+			if !ok || key == "" || value == "" {
+				return w, usage
+			}
+			if arg == "--snippet" {
+				w.Snippets = append(w.Snippets, model.Snippet{Name: key, Command: value})
+				continue
+			}
+
+			fwdType := ssh.ForwardLocal
+			if arg == "-R" {
+				fwdType = ssh.ForwardRemote
+			}
+			if _, err := ssh.ParsePortForward(fwdType, value); err != nil {
+				return w, err
+			}
+			conn, err := resolve(key)
+			if err != nil {
+				return w, err
+			}
+			w.Tunnels = append(w.Tunnels, model.WorkspaceTunnel{Connection: conn, Type: string(fwdType), Spec: value})
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return w, usage
+			}
+			conn, err := resolve(arg)
+			if err != nil {
+				return w, err
+			}
+			w.Connections = append(w.Connections, conn)
+		}
+	}
+	return w, nil
+}
+
+// printWorkspace prints what a workspace opens
+func printWorkspace(w model.Workspace) {
+	fmt.Println(w.Name)
+	if len(w.Connections) > 0 {
+		fmt.Printf("  %-10s %s\n", i18n.T("cli.workspace.sessions"), strings.Join(w.Connections, ", "))
+	}
+	for _, t := range w.Tunnels {
+		flag := "-L"
+		if t.Type == string(ssh.ForwardRemote) {
+			flag = "-R"
+		}
+		fmt.Printf("  %-10s %s %s %s\n", i18n.T("cli.workspace.tunnel"), t.Connection, flag, t.Spec)
+	}
+	for _, sn := range w.Snippets {
+		fmt.Printf("  %-10s %s: %s\n", i18n.T("cli.workspace.snippet"), sn.Name, sn.Command)
+	}
+	fmt.Println()
+}
+
+// openWorkspace starts a workspace's tunnels and opens its sessions: in
+// tmux windows when run inside tmux, else one after another here. The
+// tunnels stay up until interrupted.
+func openWorkspace(cfg *config.Manager, w model.Workspace) error {
+	connections := cfg.Connections()
+	var forwarders []*ssh.Forwarder
+	stop := func() {
+		for _, f := range forwarders {
+			f.Stop()
+		}
+	}
+
+	// One SSH connection per host carries all of its tunnels
+	byHost := make(map[string]*ssh.Forwarder)
+	for _, t := range w.Tunnels {
+		conn := findConnection(connections, t.Connection)
+		if conn == nil {
+			stop()
+			return fmt.Errorf(i18n.T("cli.error.not_found"), t.Connection)
+		}
+		pf, err := ssh.ParsePortForward(ssh.ForwardType(t.Type), t.Spec)
+		if err != nil {
+			stop()
+			return err
+		}
+		f, ok := byHost[conn.ID]
+		if !ok {
+			f = ssh.NewForwarder(*conn)
+			if err := f.Connect(); err != nil {
+				stop()
+				return fmt.Errorf("failed to connect to %s: %w", conn.Name, err)
+			}
+			byHost[conn.ID] = f
+			forwarders = append(forwarders, f)
+		}
+		if err := f.StartForward(pf); err != nil {
+			stop()
+			return fmt.Errorf("failed to start forwarding: %w", err)
+		}
+	}
+	defer stop()
+
+	var sessions []*model.Connection
+	for _, name := range w.Connections {
+		conn := findConnection(connections, name)
+		if conn == nil {
+			return fmt.Errorf(i18n.T("cli.error.not_found"), name)
+		}
+		sessions = append(sessions, conn)
+	}
+
+	if os.Getenv("TMUX") != "" && len(sessions) > 0 {
+		exe, err := os.Executable()
+		if err != nil {
+			return err
+		}
+		for _, conn := range sessions {
+			cmd := exec.Command("tmux", "new-window", "-n", conn.Name, exe, "--config", cfg.Path(), "connect", conn.Name)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				return fmt.Errorf("failed to open a tmux window for %s: %w: %s", conn.Name, err, strings.TrimSpace(string(out)))
+			}
+			fmt.Printf(i18n.T("cli.workspace.window")+"\n", conn.Name)
+		}
+	} else {
+		for i, conn := range sessions {
+			fmt.Printf(i18n.T("cli.workspace.session")+"\n", i+1, len(sessions), conn.Name, conn.User, conn.Host, conn.Port)
+			if err := runSession(cfg, conn, ssh.NewTerminal(*conn), ""); err != nil {
+				// An ended session is no reason to skip the rest
+				var exitErr *ExitError
+				if !errors.As(err, &exitErr) {
+					fmt.Fprintf(os.Stderr, "%s: %v\n", i18n.T("common.error"), err)
+				}
+			}
+		}
+	}
+
+	if len(forwarders) == 0 {
+		return nil
+	}
+	fmt.Println(i18n.T("cli.workspace.tunnels_active"))
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	<-sigCh
+	fmt.Println("\n" + i18n.T("cli.forward.stopping"))
+	return nil
+}
+
+// runSchedule manages commands run on a cron spec, and runs them when due
+// with schedule daemon
+func runSchedule(args []string) error {
+	usage := errors.New(i18n.T("cli.usage.schedule"))
+	action := "list"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := unlockIfNeeded(cfg); err != nil {
+		return err
+	}
+
+	switch action {
+	case "list", "ls":
+		jobs := cfg.Schedules()
+		if len(jobs) == 0 {
+			fmt.Println(i18n.T("cli.schedule.empty"))
+			return nil
+		}
+		for _, j := range jobs {
+			printScheduledJob(j)
+		}
+		return nil
+
+	case "add":
+		job, err := parseScheduledJob(args[1:])
+		if err != nil {
+			return err
+		}
+		if err := cfg.AddSchedule(job); err != nil {
+			return fmt.Errorf("failed to save scheduled command: %w", err)
+		}
+		spec, _ := schedule.Parse(job.Cron)
+		fmt.Printf(i18n.T("cli.schedule.added")+"\n", job.ShortID(), spec.Next(time.Now()).Format("2006-01-02 15:04"))
+		return nil
+
+	case "rm":
+		if len(args) < 2 {
+			return usage
+		}
+		if err := cfg.DeleteSchedule(args[1]); err != nil {
+			return fmt.Errorf(i18n.T("cli.schedule.not_found"), args[1])
+		}
+		fmt.Printf(i18n.T("cli.schedule.removed")+"\n", args[1])
+		return nil
+
+	case "daemon":
+		return runScheduleDaemon(cfg)
+	}
+
+	return usage
+}
+
+// parseScheduledJob builds a job from the arguments of schedule add: the
+// cron spec, exec target flags, --timeout and the command
+func parseScheduledJob(args []string) (model.ScheduledJob, error) {
+	usage := errors.New(i18n.T("cli.usage.schedule"))
+	if len(args) == 0 {
+		return model.ScheduledJob{}, usage
+	}
+
+	var command string
+	var targets []string
+	timeout := 0
+	for _, arg := range args[1:] {
+		// Targets are kept as given and applied to the connections of the
+		// day at each run
+		var filter ssh.TargetFilter
+		if ok, err := parseTargetArg(arg, &filter); err != nil {
+			return model.ScheduledJob{}, err
+		} else if ok {
+			targets = append(targets, arg)
+			continue
+		}
+		switch {
+		case strings.HasPrefix(arg, "--timeout="):
+			secs, err := strconv.Atoi(strings.TrimPrefix(arg, "--timeout="))
+			if err != nil || secs <= 0 {
+				return model.ScheduledJob{}, usage
+			}
+			timeout = secs
+		case command == "":
+			command = arg
+		default:
+			return model.ScheduledJob{}, usage
+		}
+	}
+	if command == "" {
+		return model.ScheduledJob{}, errors.New(i18n.T("cli.error.no_command"))
+	}
+
+	job := model.NewScheduledJob(args[0], command, targets)
+	job.Timeout = timeout
+	return job, nil
+}
+
+// printScheduledJob prints a job with its next and last run
+func printScheduledJob(j model.ScheduledJob) {
+	fmt.Printf("%s  %-14s %s\n", j.ShortID(), j.Cron, j.Command)
+	hosts := i18n.T("cli.schedule.all_hosts")
+	if len(j.Targets) > 0 {
+		hosts = strings.Join(j.Targets, " ")
+	}
+	fmt.Printf("  %-10s %s\n", i18n.T("cli.schedule.hosts"), hosts)
+	if spec, err := schedule.Parse(j.Cron); err == nil {
+		fmt.Printf("  %-10s %s\n", i18n.T("cli.schedule.next"), spec.Next(time.Now()).Format("2006-01-02 15:04"))
+	}
+	last := i18n.T("cli.schedule.never")
+	if j.LastRun != nil {
+		last = fmt.Sprintf(i18n.T("cli.schedule.last_run"), j.LastRun.Local().Format("2006-01-02 15:04"), j.LastFailed)
+	}
+	fmt.Printf("  %-10s %s\n", i18n.T("cli.schedule.last"), last)
+	fmt.Println()
+}
+
+// runScheduleDaemon runs scheduled commands when they are due until
+// interrupted. The config is read again every minute, so commands added or
+// removed meanwhile are picked up without a restart.
+func runScheduleDaemon(cfg *config.Manager) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf(i18n.T("cli.schedule.daemon")+"\n", len(cfg.Schedules()))
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	running := make(map[string]bool)
+	for {
+		// Wake at the start of every minute
+		now := time.Now()
+		select {
+		case <-ctx.Done():
+			fmt.Println(i18n.T("cli.schedule.stopping"))
+			wg.Wait()
+			return nil
+		case <-time.After(now.Truncate(time.Minute).Add(time.Minute).Sub(now)):
+		}
+
+		if err := cfg.Reload(); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", i18n.T("common.error"), err)
+			continue
+		}
+		now = time.Now()
+		for _, job := range cfg.Schedules() {
+			spec, err := schedule.Parse(job.Cron)
+			if err != nil || !spec.Matches(now) {
+				continue
+			}
+			// A run still going when the next is due is not started twice
+			mu.Lock()
+			if running[job.ID] {
+				mu.Unlock()
+				continue
+			}
+			running[job.ID] = true
+			mu.Unlock()
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				runScheduledJob(ctx, cfg, job)
+				mu.Lock()
+				delete(running, job.ID)
+				mu.Unlock()
+			}()
+		}
+	}
+}
+
+// runScheduledJob runs a job's command on its targets, records the run in
+// each host's session history and notifies the user of failures
+func runScheduledJob(ctx context.Context, cfg *config.Manager, job model.ScheduledJob) {
+	started := time.Now()
+	prefix := fmt.Sprintf("[%s] %s %q", started.Format("2006-01-02 15:04"), job.ShortID(), job.Command)
+
+	var filter ssh.TargetFilter
+	for _, arg := range job.Targets {
+		if _, err := parseTargetArg(arg, &filter); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", prefix, err)
+			return
+		}
+	}
+	timeout := 30 * time.Second
+	if job.Timeout > 0 {
+		timeout = time.Duration(job.Timeout) * time.Second
+	}
+
+	resolveCtx, cancelResolve := context.WithTimeout(ctx, timeout)
+	connections := ssh.ExpandServices(resolveCtx, filter.Apply(cfg.Connections()))
+	cancelResolve()
+
+	if len(connections) == 0 {
+		summary := prefix + ": " + i18n.T("cli.error.no_match")
+		fmt.Println(summary)
+		notifyFailed(cfg, summary)
+		_ = cfg.RecordScheduleRun(job.ID, started, 0)
+		return
+	}
+
+	executor := ssh.NewBatchExecutor(connections)
+	executor.SetTimeout(timeout)
+	results := executor.Execute(ctx, job.Command)
+
+	for _, r := range results {
+		// An exit status means the host was reached and ran the command
+		if r.Error == nil || r.ExitCode != 0 {
+			_ = cfg.RecordSession(r.Connection.ID, model.SessionRecord{
+				StartedAt: started,
+				Duration:  r.Duration,
+				ExitCode:  r.ExitCode,
+				Command:   job.Command,
+			})
+		} else {
+			_ = cfg.UpdateConnectionStatus(r.Connection.ID, model.ConnStatusFailed)
+		}
+	}
+	run := recordRun(cfg, job.Command, results)
+	failed := len(run.Failed)
+	if err := cfg.RecordScheduleRun(job.ID, started, failed); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", i18n.T("common.error"), err)
+	}
+
+	summary := prefix + ": " + fmt.Sprintf(i18n.T("cli.schedule.ran"), len(results)-failed, failed, run.ShortID())
+	fmt.Println(summary)
+	for _, r := range results {
+		if r.Error != nil {
+			fmt.Printf("  ✗ %s: %v\n", r.Connection.Name, r.Error)
+		}
+	}
+	if failed > 0 {
+		notifyFailed(cfg, summary)
+	}
+	fireHooks(cfg, execPayload(job.Command, results))
+}
+
+// runConnect connects to a server by name
+func runConnect(args []string) error {
+	var name, share, command string
+	var page bool
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			// Everything after -- is the remote command, like `ssh host cmd`
+			if i+1 == len(args) {
+				return errors.New(i18n.T("cli.usage.connect"))
+			}
+			command = strings.Join(args[i+1:], " ")
+			i = len(args)
+		case arg == "--share" && i+1 < len(args):
+			i++
+			share = args[i]
+		case strings.HasPrefix(arg, "--share="):
+			share = strings.TrimPrefix(arg, "--share=")
+		case arg == "--scrollback":
+			page = true
+		case name == "" && !strings.HasPrefix(arg, "-"):
+			name = arg
+		default:
+			return errors.New(i18n.T("cli.usage.connect"))
+		}
+	}
+	if name == "" {
+		return errors.New(i18n.T("cli.usage.connect"))
+	}
+
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := unlockIfNeeded(cfg); err != nil {
+		return err
+	}
+
+	conn := findConnection(cfg.Connections(), name)
+	if conn == nil {
+		return fmt.Errorf(i18n.T("cli.error.not_found"), name)
+	}
+
+	terminal := ssh.NewTerminal(*conn)
+
+	if share != "" {
+		mirror, err := ssh.NewMirror(share)
+		if err != nil {
+			return err
+		}
+		defer mirror.Close()
+		terminal.SetMirror(mirror)
+
+		switch mirror.Network() {
+		case "file":
+			fmt.Printf(i18n.T("cli.connect.share.file")+"\n", mirror.Addr())
+		case "unix":
+			fmt.Printf(i18n.T("cli.connect.share.socket")+"\n", mirror.Addr(), "socat - UNIX-CONNECT:"+mirror.Addr())
+		default:
+			host, port, _ := net.SplitHostPort(mirror.Addr())
+			fmt.Printf(i18n.T("cli.connect.share.socket")+"\n", mirror.Addr(), "nc "+host+" "+port)
+		}
+	}
+
+	if !page || command != "" {
+		return runSession(cfg, conn, terminal, command)
+	}
+
+	if terminal.Scrollback() == nil {
+		terminal.SetScrollback(model.DefaultScrollbackKB * 1024)
+	}
+	err = runSession(cfg, conn, terminal, command)
+	// Page even when the connection was lost, as that is often what to look into
+	if pageErr := pageScrollback(terminal.Scrollback()); pageErr != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", i18n.T("common.error"), pageErr)
+	}
+	return err
+}
+
+// pageScrollback shows what a session left in its scrollback in $PAGER,
+// less by default
+func pageScrollback(scrollback *ssh.Scrollback) error {
+	output := ssh.PlainText(scrollback.Bytes())
+	if strings.TrimSpace(output) == "" {
+		return nil
+	}
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less"
+		if runtime.GOOS == "windows" {
+			pager = "more"
+		}
+	}
+	cmd := shell.Command(pager, "")
+	cmd.Stdin = strings.NewReader(strings.TrimRight(output, "\n") + "\n")
+	return cmd.Run()
+}
+
+// runSession runs command on a connection's terminal, or a shell when
+// command is empty, and records the session
+func runSession(cfg *config.Manager, conn *model.Connection, terminal *ssh.Terminal, command string) error {
+	if err := preConnect(cfg, conn); err != nil {
+		return err
+	}
+
+	var err error
+	started := time.Now()
+	if command != "" {
+		// Keep stdout clean for the command's own output
+		err = terminal.RunCommand(command)
+	} else {
+		fmt.Printf(i18n.T("cli.connect.connecting")+"\n", conn.Name, conn.User, conn.Host, conn.Port)
+		err = terminal.Run()
+		// ~Z suspends gossh itself; fg brings the session back
+		for errors.Is(err, ssh.ErrSuspended) {
+			ssh.SuspendProcess()
+			err = terminal.Resume()
+		}
+	}
+
+	if errors.Is(err, ssh.ErrDisconnected) {
+		// Like OpenSSH, ~. exits with 255
+		postDisconnect(cfg, conn, 255, nil)
+		return &ExitError{Code: 255}
+	}
+
+	code, exited := ssh.ExitStatus(err)
+	if !exited {
+		postDisconnect(cfg, conn, -1, err)
+		_ = cfg.UpdateConnectionStatus(conn.ID, model.ConnStatusFailed)
+		// Avoid double-wrapping "connection lost" errors
+		if strings.Contains(err.Error(), "connection lost") {
+			return err
+		}
+		return fmt.Errorf("connection failed: %w", err)
+	}
+
+	postDisconnect(cfg, conn, code, nil)
+	duration := time.Since(started)
+	_ = cfg.RecordSession(conn.ID, model.SessionRecord{
+		StartedAt: started,
+		Duration:  duration,
+		ExitCode:  code,
+		Command:   command,
+		Address:   terminal.Address(),
+	})
+	if command == "" {
+		fmt.Fprintln(os.Stderr, sessionSummary(conn.Name, code, duration))
+	}
+
+	if code != 0 {
+		return &ExitError{Code: code}
+	}
+	return nil
+}
+
+// preConnect fires the session.pre_connect hooks for a session about to
+// start on conn and runs the connection's pre_connect command, whose
+// failure keeps the session from starting
+func preConnect(cfg *config.Manager, conn *model.Connection) error {
+	p := hooks.PreConnect(*conn)
+	fireHooks(cfg, p)
+	if conn.PreConnect == "" {
+		return nil
+	}
+	if err := hooks.RunConnectionCommand(conn.PreConnect, p); err != nil {
+		return fmt.Errorf(i18n.T("session.pre_connect.failed"), err)
+	}
+	return nil
+}
+
+// postDisconnect runs the post_disconnect command of a connection whose
+// session ended and fires the session.post_disconnect hooks, only warning
+// when they fail
+func postDisconnect(cfg *config.Manager, conn *model.Connection, code int, cause error) {
+	p := hooks.PostDisconnect(*conn, code, cause)
+	if conn.PostDisconnect != "" {
+		if err := hooks.RunConnectionCommand(conn.PostDisconnect, p); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("cli.hook.failed")+"\n", fmt.Errorf(i18n.T("session.post_disconnect.failed"), err))
+		}
+	}
+	fireHooks(cfg, p)
+}
+
+// sessionSummary describes how a session ended, e.g. "web: exited 130 after 14m"
+func sessionSummary(name string, code int, duration time.Duration) string {
+	return fmt.Sprintf(i18n.T("session.summary"), name, code, model.ShortDuration(duration))
+}
+
+// runSFTP starts an SFTP session
+func runSFTP(args []string) error {
+	var name string
+	var preserve sftp.TransferOptions
+	var trash bool
+	parallel := 1
+	batch := false // Run script instead of reading commands
+	var script []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--batch" || strings.HasPrefix(arg, "--batch="):
+			path, ok := strings.CutPrefix(arg, "--batch=")
+			if !ok {
+				if i+1 >= len(args) {
+					return errors.New(i18n.T("cli.usage.sftp"))
+				}
+				i++
+				path = args[i]
+			}
+			lines, err := readScript(path)
+			if err != nil {
+				return err
+			}
+			batch, script = true, append(script, lines...)
+		case arg == "-e":
+			if i+1 >= len(args) {
+				return errors.New(i18n.T("cli.usage.sftp"))
+			}
+			i++
+			batch = true
+			script = append(script, strings.FieldsFunc(args[i], func(r rune) bool { return r == ';' || r == '\n' })...)
+		case strings.HasPrefix(arg, "--parallel="):
+			n, err := strconv.Atoi(strings.TrimPrefix(arg, "--parallel="))
+			if err != nil || n <= 0 {
+				return errors.New(i18n.T("cli.usage.sftp"))
+			}
+			parallel = n
+		case arg == "--trash":
+			trash = true
+		case arg == "--preserve":
+			preserve = sftp.TransferOptions{Links: true, Owner: true, Times: true}
+		case strings.HasPrefix(arg, "--preserve="):
+			opts, err := sftp.ParsePreserve(strings.TrimPrefix(arg, "--preserve="))
+			if err != nil {
+				return err
+			}
+			preserve = opts
+		case name == "" && !strings.HasPrefix(arg, "-"):
+			name = arg
+		default:
+			return errors.New(i18n.T("cli.usage.sftp"))
+		}
+	}
+	if name == "" {
+		return errors.New(i18n.T("cli.usage.sftp"))
+	}
+
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := unlockIfNeeded(cfg); err != nil {
+		return err
+	}
+
+	conn := findConnection(cfg.Connections(), name)
+	if conn == nil {
+		return fmt.Errorf(i18n.T("cli.error.not_found"), name)
+	}
+
+	fmt.Printf(i18n.T("cli.sftp.starting")+"\n", conn.Name, conn.User, conn.Host, conn.Port)
+
+	client := sftp.NewClient(*conn)
+	client.SetTransferOptions(preserve)
+	if err := client.Connect(); err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer client.Close()
+
+	if !batch {
+		fmt.Println(i18n.T("cli.sftp.connected"))
+	}
+
+	// Open in the connection's directories rather than the home directory
+	if conn.RemoteDir != "" {
+		if err := client.Cd(conn.RemoteDir); err != nil {
+			fmt.Printf(i18n.T("cli.sftp.remote_dir_failed")+"\n", conn.RemoteDir, err)
+		}
+	}
+	if dir := client.LocalDir(); dir != "" {
+		fmt.Printf(i18n.T("cli.sftp.local_dir")+"\n", dir)
+	}
+
+	// Queued transfers run in the background while the shell takes commands
+	queue := sftp.NewQueue(client, parallel)
+	queue.SetOnDrain(func(s sftp.QueueSummary) {
+		summary := fmt.Sprintf(i18n.T("cli.sftp.queue.drained"), s.Done, len(s.Failed), s.Canceled, sftp.FormatSize(s.Bytes), model.ShortDuration(s.Elapsed))
+		fmt.Println("\n" + summary)
+		for _, t := range s.Failed {
+			fmt.Printf("  ✗ #%d %s: %v\n", t.ID, transferName(t), t.Err)
+		}
+		notifyDone(cfg, time.Now().Add(-s.Elapsed), summary)
+	})
+	defer func() {
+		if n := queue.Active(); n > 0 {
+			fmt.Printf(i18n.T("cli.sftp.queue.closing")+"\n", n)
+		}
+		queue.Close()
+	}()
+
+	sh := &sftpShell{cfg: cfg, client: client, queue: queue, trash: trash}
+	if batch {
+		return runSFTPBatch(sh, script)
+	}
+
+	// Simple SFTP shell
+	scanner := bufio.NewScanner(os.Stdin)
+	sh.confirm = func(prompt string) bool {
+		fmt.Print(prompt)
+		if !scanner.Scan() {
+			return false
+		}
+		answer := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		return answer == "y" || answer == "yes"
+	}
+	for {
+		pwd, _ := client.Pwd()
+		fmt.Printf("sftp:%s> ", pwd)
+
+		if !scanner.Scan() {
+			break
+		}
+		if err := sh.run(scanner.Text()); errors.Is(err, errSFTPQuit) {
+			fmt.Println(i18n.T("cli.sftp.goodbye"))
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// errSFTPQuit is returned by sftpShell.run for exit and quit
+var errSFTPQuit = errors.New("quit")
+
+// errSFTPFailed is returned by sftpShell.run for a command that failed,
+// once it has said why
+var errSFTPFailed = errors.New("sftp command failed")
+
+// sftpShell runs the commands of gossh sftp, typed or from a script
+type sftpShell struct {
+	cfg     *config.Manager
+	client  *sftp.Client
+	queue   *sftp.Queue
+	trash   bool                     // rm and rmdir move to the trash
+	confirm func(prompt string) bool // Asks before deleting
+}
+
+// run runs one command line, printing what it did
+func (sh *sftpShell) run(line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil
+	}
+
+	// !command runs on this side, in the local directory, like lftp
+	if strings.HasPrefix(line, "!") {
+		if err := shell.Command(strings.TrimSpace(line[1:]), sh.client.LocalDir()).Run(); err != nil {
+			if code := shell.ExitCode(err); code > 0 {
+				fmt.Printf(i18n.T("cli.sftp.local_exit")+"\n", code)
+			} else {
+				fmt.Printf("%s: %v\n", i18n.T("common.error"), err)
+			}
+			return errSFTPFailed
+		}
+		return nil
+	}
+
+	parts := strings.Fields(line)
+	cmd := parts[0]
+	args := parts[1:]
+
+	switch cmd {
+	case "help":
+		fmt.Println(i18n.T("cli.sftp.commands"))
+		for _, c := range [][2]string{
+			{"ls [path]", "cli.sftp.cmd.ls"},
+			{"cd <path>", "cli.sftp.cmd.cd"},
+			{"pwd", "cli.sftp.cmd.pwd"},
+			{"copy [path]", "cli.sftp.cmd.copy"},
+			{"get [-r] <remote> [local]", "cli.sftp.cmd.get"},
+			{"put [-r] <local> [remote]", "cli.sftp.cmd.put"},
+			{"mkdir <path>", "cli.sftp.cmd.mkdir"},
+			{"rm [-f] <path>", "cli.sftp.cmd.rm"},
+			{"rmdir [-f] <path>", "cli.sftp.cmd.rmdir"},
+			{"empty-trash", "cli.sftp.cmd.empty_trash"},
+			{"chmod [-R] <mode> <path>", "cli.sftp.cmd.chmod"},
+			{"chown [-R] <owner> <path>", "cli.sftp.cmd.chown"},
+			{"!<command>", "cli.sftp.cmd.local"},
+			{"du [path]", "cli.sftp.cmd.du"},
+			{"queue get|put <src> [dst]", "cli.sftp.cmd.queue"},
+			{"jobs", "cli.sftp.cmd.jobs"},
+			{"pause [id]", "cli.sftp.cmd.pause"},
+			{"resume [id]", "cli.sftp.cmd.resume"},
+			{"cancel [id]", "cli.sftp.cmd.cancel"},
+			{"exit/quit", "cli.sftp.cmd.exit"},
+		} {
+			fmt.Printf("  %-20s %s\n", c[0], i18n.T(c[1]))
+		}
+
+	case "ls":
+		path := "."
+		if len(args) > 0 {
+			path = args[0]
+		}
+		files, err := sh.client.List(path)
+		if err != nil {
+			fmt.Printf("%s: %v\n", i18n.T("common.error"), err)
+			return errSFTPFailed
+		}
+		for _, f := range files {
+			fmt.Println(f.String())
+		}
+
+	case "cd":
+		if len(args) == 0 {
+			fmt.Printf(i18n.T("cli.sftp.usage")+"\n", "cd <path>")
+			return errSFTPFailed
+		}
+		if err := sh.client.Cd(args[0]); err != nil {
+			fmt.Printf("%s: %v\n", i18n.T("common.error"), err)
+			return errSFTPFailed
+		}
+
+	case "pwd":
+		pwd, err := sh.client.Pwd()
+		if err != nil {
+			fmt.Printf("%s: %v\n", i18n.T("common.error"), err)
+			return errSFTPFailed
+		}
+		fmt.Println(pwd)
+
+	case "copy":
+		target := ""
+		if len(args) > 0 {
+			target = args[0]
+		}
+		remote, err := sh.client.AbsPath(target)
+		if err != nil {
+			fmt.Printf("%s: %v\n", i18n.T("common.error"), err)
+			return errSFTPFailed
+		}
+		if err := clipboard.Copy(remote); err != nil {
+			fmt.Printf("%s: %v\n", i18n.T("common.error"), err)
+			return errSFTPFailed
+		}
+		fmt.Printf(i18n.T("cli.sftp.copied")+"\n", remote)
+
+	case "get":
+		recursive := len(args) > 0 && args[0] == "-r"
+		if recursive {
+			args = args[1:]
+		}
+		if len(args) == 0 {
+			fmt.Printf(i18n.T("cli.sftp.usage")+"\n", "get [-r] <remote> [local]")
+			return errSFTPFailed
+		}
+		remote := args[0]
+		local := filepath.Base(remote)
+		if len(args) > 1 {
+			local = args[1]
+		}
+		download := sh.client.Download
+		if recursive {
+			download = sh.client.DownloadDir
+		}
+		started := time.Now()
+		if err := download(remote, local); err != nil {
+			fmt.Printf("%s: %v\n", i18n.T("common.error"), err)
+			notifyDone(sh.cfg, started, fmt.Sprintf(i18n.T("cli.sftp.notify.failed"), remote))
+			return errSFTPFailed
+		}
+		fmt.Printf(i18n.T("cli.sftp.downloaded")+"\n", remote, local)
+		notifyDone(sh.cfg, started, fmt.Sprintf(i18n.T("cli.sftp.downloaded"), remote, local))
+
+	case "put":
+		recursive := len(args) > 0 && args[0] == "-r"
+		if recursive {
+			args = args[1:]
+		}
+		if len(args) == 0 {
+			fmt.Printf(i18n.T("cli.sftp.usage")+"\n", "put [-r] <local> [remote]")
+			return errSFTPFailed
+		}
+		local := args[0]
+		remote := filepath.Base(local)
+		if len(args) > 1 {
+			remote = args[1]
+		}
+		upload := sh.client.Upload
+		if recursive {
+			upload = sh.client.UploadDir
+		}
+		started := time.Now()
+		if err := upload(local, remote); err != nil {
+			fmt.Printf("%s: %v\n", i18n.T("common.error"), err)
+			notifyDone(sh.cfg, started, fmt.Sprintf(i18n.T("cli.sftp.notify.failed"), local))
+			return errSFTPFailed
+		}
+		fmt.Printf(i18n.T("cli.sftp.uploaded")+"\n", local, remote)
+		notifyDone(sh.cfg, started, fmt.Sprintf(i18n.T("cli.sftp.uploaded"), local, remote))
+
+	case "mkdir":
+		if len(args) == 0 {
+			fmt.Printf(i18n.T("cli.sftp.usage")+"\n", "mkdir <path>")
+			return errSFTPFailed
+		}
+		if err := sh.client.Mkdir(args[0]); err != nil {
+			fmt.Printf("%s: %v\n", i18n.T("common.error"), err)
+			return errSFTPFailed
+		}
+		fmt.Printf(i18n.T("cli.sftp.mkdir_done")+"\n", args[0])
+
+	case "rm", "rmdir":
+		force := len(args) > 0 && args[0] == "-f"
+		if force {
+			args = args[1:]
+		}
+		if len(args) == 0 {
+			fmt.Printf(i18n.T("cli.sftp.usage")+"\n", cmd+" [-f] <path>")
+			return errSFTPFailed
+		}
+		target := args[0]
+		if !force {
+			files, dirs, err := sh.client.Count(target)
+			if err != nil {
+				fmt.Printf("%s: %v\n", i18n.T("common.error"), err)
+				return errSFTPFailed
+			}
+			prompt := fmt.Sprintf(i18n.T("cli.sftp.confirm.delete"), target)
+			if dirs > 0 {
+				prompt = fmt.Sprintf(i18n.T("cli.sftp.confirm.delete_dir"), target, files, dirs-1)
+			}
+			ask := "cli.sftp.confirm.ask"
+			if sh.trash {
+				ask = "cli.sftp.confirm.trash"
+			}
+			if !sh.confirm(fmt.Sprintf(i18n.T(ask), prompt)) {
+				fmt.Println(i18n.T("cli.aborted"))
+				return errSFTPFailed
+			}
+		}
+
+		if sh.trash {
+			dest, err := sh.client.Trash(target)
+			if err != nil {
+				fmt.Printf("%s: %v\n", i18n.T("common.error"), err)
+				return errSFTPFailed
+			}
+			fmt.Printf(i18n.T("cli.sftp.trashed")+"\n", target, dest)
+			return nil
+		}
+		remove, done := sh.client.Remove, "cli.sftp.rm_done"
+		if cmd == "rmdir" {
+			remove, done = sh.client.RemoveAll, "cli.sftp.rmdir_done"
+		}
+		if err := remove(target); err != nil {
+			fmt.Printf("%s: %v\n", i18n.T("common.error"), err)
+			return errSFTPFailed
+		}
+		fmt.Printf(i18n.T(done)+"\n", target)
+
+	case "chmod", "chown":
+		recursive := len(args) > 0 && args[0] == "-R"
+		if recursive {
+			args = args[1:]
+		}
+		if cmd == "chmod" && len(args) == 1 {
+			// Just a path shows its permissions
+			info, err := sh.client.Stat(args[0])
+			if err != nil {
+				fmt.Printf("%s: %v\n", i18n.T("common.error"), err)
+				return errSFTPFailed
+			}
+			printPermissions(info.Mode)
+			return nil
+		}
+		if len(args) != 2 {
+			fmt.Printf(i18n.T("cli.sftp.usage")+"\n", cmd+" [-R] "+map[string]string{"chmod": "<mode>", "chown": "<user[:group]>"}[cmd]+" <path>")
+			return errSFTPFailed
+		}
+		change := sh.client.Chmod
+		if cmd == "chown" {
+			change = sh.client.Chown
+		}
+		n, err := change(args[1], args[0], recursive)
+		if err != nil {
+			fmt.Printf("%s: %v\n", i18n.T("common.error"), err)
+			if n <= 0 {
+				return errSFTPFailed
+			}
+		}
+		switch {
+		case n < 0 || !recursive && cmd == "chown":
+			fmt.Printf(i18n.T("cli.sftp.chown_done")+"\n", args[1], args[0])
+		case !recursive:
+			info, err := sh.client.Stat(args[1])
+			if err != nil {
+				fmt.Printf("%s: %v\n", i18n.T("common.error"), err)
+				return errSFTPFailed
+			}
+			fmt.Printf("%s: %s\n", args[1], sftp.ModeString(info.Mode))
+		default:
+			fmt.Printf(i18n.T("cli.sftp.changed")+"\n", n, args[1])
+		}
+		if err != nil {
+			return errSFTPFailed
+		}
+
+	case "empty-trash":
+		n, err := sh.client.TrashCount()
+		if err != nil {
+			fmt.Printf("%s: %v\n", i18n.T("common.error"), err)
+			return errSFTPFailed
+		}
+		if n == 0 {
+			fmt.Println(i18n.T("cli.sftp.trash.empty"))
+			return nil
+		}
+		if !sh.confirm(fmt.Sprintf(i18n.T("cli.sftp.confirm.empty_trash"), n, sftp.TrashDir)) {
+			fmt.Println(i18n.T("cli.aborted"))
+			return errSFTPFailed
+		}
+		removed, err := sh.client.EmptyTrash()
+		if err != nil {
+			fmt.Printf("%s: %v\n", i18n.T("common.error"), err)
+		}
+		fmt.Printf(i18n.T("cli.sftp.trash.emptied")+"\n", removed)
+		if err != nil {
+			return errSFTPFailed
+		}
+
+	case "du":
+		path := "."
+		if len(args) > 0 {
+			path = args[0]
+		}
+		fmt.Printf(i18n.T("cli.sftp.du.computing")+"\n", path)
+		usage, err := sh.client.DiskUsage(path)
+		if err != nil {
+			fmt.Printf("%s: %v\n", i18n.T("common.error"), err)
+			return errSFTPFailed
+		}
+		printUsage(usage)
+
+	case "queue":
+		if len(args) < 2 || args[0] != "get" && args[0] != "put" {
+			fmt.Printf(i18n.T("cli.sftp.usage")+"\n", "queue get|put <src> [dst]")
+			return errSFTPFailed
+		}
+		src, dst := args[1], filepath.Base(args[1])
+		if len(args) > 2 {
+			dst = args[2]
+		}
+		var id int
+		if args[0] == "get" {
+			id = sh.queue.Add(sftp.DirectionDownload, dst, src)
+		} else {
+			id = sh.queue.Add(sftp.DirectionUpload, src, dst)
+		}
+		fmt.Printf(i18n.T("cli.sftp.queue.added")+"\n", id, args[0], src)
+
+	case "jobs":
+		transfers := sh.queue.Transfers()
+		if len(transfers) == 0 {
+			fmt.Println(i18n.T("cli.sftp.queue.empty"))
+			return nil
+		}
+		for _, t := range transfers {
+			fmt.Printf("  #%-3d %-8s %s", t.ID, t.State, transferName(t))
+			if t.Total > 0 {
+				fmt.Printf("  %d%% (%s/%s)", t.Transferred*100/t.Total, sftp.FormatSize(t.Transferred), sftp.FormatSize(t.Total))
+			}
+			if t.Err != nil {
+				fmt.Printf("  %v", t.Err)
+			}
+			fmt.Println()
+		}
+
+	case "pause", "resume", "cancel":
+		action := map[string]func(int) error{"pause": sh.queue.Pause, "resume": sh.queue.Resume, "cancel": sh.queue.Cancel}[cmd]
+		if len(args) == 0 {
+			// Every unfinished transfer
+			for _, t := range sh.queue.Transfers() {
+				if !t.State.Finished() {
+					_ = action(t.ID)
+				}
+			}
+			return nil
+		}
+		id, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err == nil {
+			err = action(id)
+		}
+		if err != nil {
+			fmt.Printf(i18n.T("cli.sftp.queue.no_transfer")+"\n", args[0])
+			return errSFTPFailed
+		}
+
+	case "exit", "quit":
+		return errSFTPQuit
+
+	default:
+		fmt.Printf(i18n.T("cli.sftp.unknown")+"\n", cmd)
+		return errSFTPFailed
+	}
+	return nil
+}
+
+// readScript returns the lines of the file at path, or of stdin for -
+func readScript(path string) ([]string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(string(data), "\n"), nil
+}
+
+// runSFTPBatch runs the commands of script without asking anything, as
+// OpenSSH's sftp -b does: it stops at the first that fails, unless it
+// starts with -, and then waits for the queued transfers
+func runSFTPBatch(sh *sftpShell, script []string) error {
+	sh.confirm = func(prompt string) bool {
+		fmt.Println(prompt + i18n.T("cli.sftp.batch.no_confirm"))
+		return false
+	}
+	for _, line := range script {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line, optional := strings.CutPrefix(line, "-")
+		fmt.Println("sftp> " + line)
+		err := sh.run(line)
+		if errors.Is(err, errSFTPQuit) {
+			break
+		}
+		if err != nil && !optional {
+			fmt.Fprintf(os.Stderr, i18n.T("cli.sftp.batch.stopped")+"\n", line)
+			return &ExitError{Code: 1}
+		}
+	}
+
+	sh.queue.Wait()
+	failed := 0
+	for _, t := range sh.queue.Transfers() {
+		if t.State == sftp.TransferFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, i18n.T("cli.sftp.batch.queue_failed")+"\n", failed)
+		return &ExitError{Code: 1}
+	}
+	return nil
+}
+
+// printPermissions shows a mode as a grid of who may read, write and
+// execute
+func printPermissions(mode os.FileMode) {
+	fmt.Println(sftp.ModeString(mode))
+	fmt.Printf("  %-8s %-6s %-6s %s\n", "", i18n.T("cli.sftp.perm.read"), i18n.T("cli.sftp.perm.write"), i18n.T("cli.sftp.perm.execute"))
+	for i, who := range []string{"cli.sftp.perm.owner", "cli.sftp.perm.group", "cli.sftp.perm.other"} {
+		shift := uint(6 - 3*i)
+		mark := func(bit os.FileMode) string {
+			if mode.Perm()>>shift&bit != 0 {
+				return "[x]"
+			}
+			return "[ ]"
+		}
+		fmt.Printf("  %-8s %-6s %-6s %s\n", i18n.T(who), mark(4), mark(2), mark(1))
+	}
+}
+
+// duRows is how many entries printUsage shows before summing up the rest
+const duRows = 20
+
+// printUsage prints the largest entries of a disk usage breakdown, each
+// with a bar for its share of the total
+func printUsage(usage []sftp.Usage) {
+	var total int64
+	for _, u := range usage {
+		total += u.Size
+	}
+	for i, u := range usage {
+		if i == duRows {
+			var rest int64
+			for _, r := range usage[i:] {
+				rest += r.Size
+			}
+			fmt.Printf("  "+i18n.T("cli.sftp.du.more")+"\n", len(usage)-i, sftp.FormatSize(rest))
+			break
+		}
+		share := 0.0
+		if total > 0 {
+			share = float64(u.Size) / float64(total)
+		}
+		filled := int(share*20 + 0.5)
+		name := u.Name
+		if u.IsDir {
+			name += "/"
+		}
+		fmt.Printf("  %9s %5.1f%% %s%s  %s\n", sftp.FormatSize(u.Size), share*100,
+			strings.Repeat("█", filled), strings.Repeat("░", 20-filled), name)
+	}
+	fmt.Printf(i18n.T("cli.sftp.du.total")+"\n", sftp.FormatSize(total), len(usage))
+}
+
+// transferName describes a queued transfer, e.g. "app.log -> /home/me/app.log"
+func transferName(t sftp.Transfer) string {
+	if t.Direction == sftp.DirectionUpload {
+		return t.Local + " -> " + t.Remote
+	}
+	return t.Remote + " -> " + t.Local
+}
+
+// runWatch uploads files under a local directory to a remote one as they
+// change, until interrupted
+func runWatch(args []string) error {
+	var paths []string
+	opts := sftp.WatchOptions{Exclude: slices.Clone(sftp.DefaultWatchExclude)}
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "--exclude="):
+			for _, pattern := range strings.Split(strings.TrimPrefix(arg, "--exclude="), ",") {
+				if pattern = strings.TrimSpace(pattern); pattern != "" {
+					opts.Exclude = append(opts.Exclude, pattern)
+				}
+			}
+		case arg == "--delete":
+			opts.Delete = true
+		case strings.HasPrefix(arg, "--debounce="):
+			ms, err := strconv.Atoi(strings.TrimPrefix(arg, "--debounce="))
+			if err != nil || ms <= 0 {
+				return errors.New(i18n.T("cli.usage.watch"))
+			}
+			opts.Debounce = time.Duration(ms) * time.Millisecond
+		case strings.HasPrefix(arg, "-"):
+			return errors.New(i18n.T("cli.usage.watch"))
+		default:
+			paths = append(paths, arg)
+		}
+	}
+	if len(paths) != 3 {
+		return errors.New(i18n.T("cli.usage.watch"))
+	}
+	name, localDir, remoteDir := paths[0], paths[1], paths[2]
+
+	_, conn, err := streamConnection(name)
+	if err != nil {
+		return err
+	}
+
+	client := sftp.NewClient(*conn)
+	if err := client.Connect(); err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer client.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf(i18n.T("cli.watch.watching")+"\n", localDir, conn.Name, remoteDir)
+	uploaded, failed := 0, 0
+	err = client.Watch(ctx, localDir, remoteDir, opts, func(e sftp.WatchEvent) {
+		stamp := time.Now().Format("15:04:05")
+		switch {
+		case e.Err != nil:
+			failed++
+			fmt.Printf("[%s] ✗ %s: %v\n", stamp, e.Path, e.Err)
+		case e.Removed:
+			fmt.Printf("[%s] - %s\n", stamp, e.Path)
+		default:
+			uploaded++
+			fmt.Printf("[%s] ↑ %s (%s)\n", stamp, e.Path, sftp.FormatSize(e.Size))
+		}
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n"+i18n.T("cli.watch.stopped")+"\n", uploaded, failed)
+	return nil
+}
+
+// runArchive downloads a remote directory as a gzipped tar, to a local
+// file or stdout for "-"
+func runArchive(args []string) error {
+	if len(args) != 3 {
+		return errors.New(i18n.T("cli.usage.archive"))
+	}
+	name, remoteDir, localPath := args[0], args[1], args[2]
+
+	cfg, conn, err := streamConnection(name)
+	if err != nil {
+		return err
+	}
+
+	out := io.Writer(os.Stdout)
+	var file *os.File
+	if localPath != "-" {
+		file, err = os.Create(config.ExpandHome(localPath))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(os.Stderr, i18n.T("cli.archive.starting")+"\n", conn.Name, remoteDir, localPath)
+	var counter ssh.StreamCounter
+	hideProgress := showStreamProgress(&counter)
+	started := time.Now()
+	err = ssh.Archive(ctx, *conn, remoteDir, out, &counter)
+	hideProgress()
+	if err == nil && file != nil {
+		err = file.Close()
+	}
+	if err != nil {
+		// Leave no truncated archive behind
+		if file != nil {
+			file.Close()
+			_ = os.Remove(file.Name())
+		}
+		notifyDone(cfg, started, fmt.Sprintf(i18n.T("cli.archive.failed"), remoteDir))
+		return err
+	}
+
+	done := fmt.Sprintf(i18n.T("cli.archive.done"), remoteDir, localPath, sftp.FormatSize(counter.Bytes()), model.ShortDuration(time.Since(started)))
+	fmt.Fprintln(os.Stderr, done)
+	notifyDone(cfg, started, done)
+	return nil
+}
+
+// runExtract uploads a gzipped tar, from a local file or stdin for "-",
+// and unpacks it into a remote directory
+func runExtract(args []string) error {
+	if len(args) != 3 {
+		return errors.New(i18n.T("cli.usage.extract"))
+	}
+	name, localPath, remoteDir := args[0], args[1], args[2]
+
+	cfg, conn, err := streamConnection(name)
+	if err != nil {
+		return err
+	}
+
+	in := io.Reader(os.Stdin)
+	if localPath != "-" {
+		file, err := os.Open(config.ExpandHome(localPath))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(os.Stderr, i18n.T("cli.extract.starting")+"\n", localPath, conn.Name, remoteDir)
+	var counter ssh.StreamCounter
+	hideProgress := showStreamProgress(&counter)
+	started := time.Now()
+	err = ssh.Extract(ctx, *conn, in, remoteDir, &counter)
+	hideProgress()
+	if err != nil {
+		notifyDone(cfg, started, fmt.Sprintf(i18n.T("cli.extract.failed"), localPath))
+		return err
+	}
+
+	done := fmt.Sprintf(i18n.T("cli.extract.done"), localPath, remoteDir, sftp.FormatSize(counter.Bytes()), model.ShortDuration(time.Since(started)))
+	fmt.Fprintln(os.Stderr, done)
+	notifyDone(cfg, started, done)
+	return nil
+}
+
+// streamConnection loads the config and finds the SSH connection an
+// archive or extract runs on
+func streamConnection(name string) (*config.Manager, *model.Connection, error) {
+	cfg, err := config.NewManager()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := unlockIfNeeded(cfg); err != nil {
+		return nil, nil, err
+	}
+
+	conn := findConnection(cfg.Connections(), name)
 	if conn == nil {
-		return fmt.Errorf("connection '%s' not found", name)
+		return nil, nil, fmt.Errorf(i18n.T("cli.error.not_found"), name)
+	}
+	if conn.IsTelnet() {
+		return nil, nil, fmt.Errorf(i18n.T("cli.error.telnet"), conn.Name)
 	}
+	return cfg, conn, nil
+}
 
-	fmt.Printf("Starting SFTP session to %s (%s@%s:%d)...\n", conn.Name, conn.User, conn.Host, conn.Port)
+// showStreamProgress keeps a count of the bytes streamed so far on stderr,
+// when it is a terminal, until the returned function is called
+func showStreamProgress(counter *ssh.StreamCounter) func() {
+	if !term.IsTerminal(int(os.Stderr.Fd())) {
+		return func() {}
+	}
 
-	client := sftp.NewClient(*conn)
-	if err := client.Connect(); err != nil {
+	started := time.Now()
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(views.SpinnerInterval)
+		defer ticker.Stop()
+		for frame := 0; ; frame++ {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				fmt.Fprintf(os.Stderr, "\r\033[K%s %s · %s", views.SpinnerFrames[frame%len(views.SpinnerFrames)],
+					sftp.FormatSize(counter.Bytes()), model.ShortDuration(time.Since(started)))
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+}
+
+// runForward starts port forwarding: any number of -L, -R and -D rules and
+// saved profiles over one connection, with their status shown until Ctrl+C
+func runForward(args []string) error {
+	usage := errors.New(i18n.T("cli.usage.forward"))
+
+	var match *regexp.Regexp
+	var name, save, hint string
+	var specs []model.ForwardSpec
+	var profiles []string
+	allProfiles, list, gateway, copyHint := false, false, false, false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case strings.HasPrefix(arg, "--match="):
+			re, err := regexp.Compile(strings.TrimPrefix(arg, "--match="))
+			if err != nil {
+				return fmt.Errorf("invalid --match pattern: %w", err)
+			}
+			match = re
+		case strings.HasPrefix(arg, "--profile="):
+			profiles = append(profiles, strings.TrimPrefix(arg, "--profile="))
+		case arg == "--all-profiles":
+			allProfiles = true
+		case strings.HasPrefix(arg, "--save="):
+			save = strings.TrimPrefix(arg, "--save=")
+		case arg == "--list":
+			list = true
+		case arg == "--gateway":
+			gateway = true
+		case strings.HasPrefix(arg, "--hint="):
+			hint = strings.TrimPrefix(arg, "--hint=")
+		case arg == "--copy":
+			copyHint = true
+		case strings.HasPrefix(arg, "-") && len(arg) == 2:
+			fwdType, ok := ssh.ParseForwardFlag(arg)
+			if !ok {
+				return fmt.Errorf(i18n.T("cli.error.forward_type"), arg)
+			}
+			if i+1 >= len(args) {
+				return usage
+			}
+			i++
+			if _, err := ssh.ParsePortForward(fwdType, args[i]); err != nil {
+				return err
+			}
+			specs = append(specs, model.ForwardSpec{Type: string(fwdType), Spec: args[i]})
+		case strings.HasPrefix(arg, "-"):
+			return usage
+		case name == "":
+			name = arg
+		default:
+			return usage
+		}
+	}
+
+	// With --match the name may be omitted
+	if name == "" && match == nil {
+		return usage
+	}
+	if !list && len(specs) == 0 && len(profiles) == 0 && !allProfiles {
+		return usage
+	}
+	if save != "" && len(specs) == 0 {
+		return errors.New(i18n.T("cli.forward.save_nothing"))
+	}
+
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := unlockIfNeeded(cfg); err != nil {
+		return err
+	}
+
+	conn, err := resolveTarget(cfg.Connections(), name, match)
+	if err != nil {
+		return err
+	}
+
+	if list {
+		printForwardProfiles(conn)
+		return nil
+	}
+
+	if save != "" {
+		profile := model.ForwardProfile{Name: save, Forwards: specs, Hint: hint}
+		if err := profile.Validate(); err != nil {
+			return err
+		}
+		conn.SetForwardProfile(profile)
+		if err := cfg.UpdateConnection(*conn); err != nil {
+			return err
+		}
+		fmt.Printf(i18n.T("cli.forward.saved")+"\n", save, len(specs), conn.Name)
+	}
+
+	// Rules from the command line first, then the profiles' in order. Each
+	// group may have a client command to show once its ports are known.
+	var rules []forwardRule
+	var hints []forwardHint
+	if hint != "" {
+		hints = append(hints, forwardHint{profile: model.ForwardProfile{Name: save, Hint: hint}, first: 0, count: len(specs)})
+	}
+	for _, spec := range specs {
+		rules = append(rules, forwardRule{profile: save, spec: spec})
+	}
+	if allProfiles {
+		if len(conn.ForwardProfiles) == 0 {
+			return fmt.Errorf(i18n.T("cli.forward.no_profiles"), conn.Name)
+		}
+		profiles = profiles[:0]
+		for _, p := range conn.ForwardProfiles {
+			profiles = append(profiles, p.Name)
+		}
+	}
+	for _, pname := range profiles {
+		p, ok := conn.ForwardProfile(pname)
+		if !ok {
+			return fmt.Errorf(i18n.T("cli.forward.no_profile"), pname, conn.Name)
+		}
+		if p.Hint != "" {
+			hints = append(hints, forwardHint{profile: p, first: len(rules), count: len(p.Forwards)})
+		}
+		for _, spec := range p.Forwards {
+			rules = append(rules, forwardRule{profile: p.Name, spec: spec})
+		}
+	}
+	for i := range rules {
+		pf, err := ssh.ParsePortForward(ssh.ForwardType(rules[i].spec.Type), rules[i].spec.Spec)
+		if err != nil {
+			return fmt.Errorf("%s: %w", rules[i].profile, err)
+		}
+		// Other hosts could reach a -R on all interfaces, so it must be asked for
+		if gateway {
+			pf.UseGateway()
+		} else if pf.IsGateway() {
+			return fmt.Errorf(i18n.T("cli.forward.gateway_needed"), pf)
+		}
+		rules[i].pf = pf
+		rules[i].anyPort = pf.ListenPort() == 0
+	}
+
+	fmt.Printf(i18n.T("cli.forward.setup")+"\n",
+		conn.Name, conn.User, conn.Host, conn.Port)
+
+	// One connection carries every rule; the table reports them instead of
+	// the forwarder's own messages. Local ports in use move to free ones.
+	forwarder := ssh.NewForwarder(*conn)
+	forwarder.SetOutput(io.Discard)
+	forwarder.SetAutoPort(true)
+
+	if err := forwarder.Connect(); err != nil {
 		return fmt.Errorf("failed to connect: %w", err)
 	}
-	defer client.Close()
 
-	fmt.Println("Connected. Type 'help' for available commands.")
+	// A rule that fails, e.g. on a port in use, leaves the others running
+	started := 0
+	for i := range rules {
+		if rules[i].err = forwarder.StartForward(rules[i].pf); rules[i].err == nil {
+			started++
+		}
+	}
+	if started == 0 {
+		forwarder.Stop()
+		return fmt.Errorf("failed to start forwarding: %w", rules[0].err)
+	}
+
+	// Ports that were picked rather than given, which the user needs to know
+	for _, r := range rules {
+		switch {
+		case r.err != nil:
+		case r.pf.RequestedPort != 0:
+			fmt.Println(styles.SuccessStyle.Render(fmt.Sprintf(i18n.T("cli.forward.moved"), r.pf.RequestedPort, r.pf.LocalPort)))
+		case r.anyPort && r.pf.Type == ssh.ForwardRemote:
+			fmt.Println(styles.SuccessStyle.Render(fmt.Sprintf(i18n.T("cli.forward.assigned"), r.pf.RemotePort, conn.Host)))
+		case r.anyPort:
+			fmt.Println(styles.SuccessStyle.Render(fmt.Sprintf(i18n.T("cli.forward.local_port"), r.pf.LocalPort)))
+		}
+		if r.err == nil && r.pf.IsGateway() {
+			fmt.Printf(i18n.T("cli.forward.gateway")+"\n", net.JoinHostPort(conn.Host, strconv.Itoa(r.pf.RemotePort)))
+		}
+	}
+	printForwardHints(rules, hints, copyHint)
+
+	fmt.Println(i18n.T("cli.forward.active"))
+	stopStatus := showForwardStatus(rules)
+
+	// Wait for interrupt
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	<-sigCh
+
+	stopStatus()
+	fmt.Println("\n" + i18n.T("cli.forward.stopping"))
+	forwarder.Stop()
+
+	return nil
+}
+
+// forwardRule is one forward of gossh forward, with the profile it came
+// from, empty for the command line
+type forwardRule struct {
+	profile string
+	spec    model.ForwardSpec
+	pf      *ssh.PortForward
+	anyPort bool // Port 0, picked by the system or the server
+	err     error
+}
+
+// forwardHint is the client command of a group of rules: a profile, or the
+// command line's rules with --hint
+type forwardHint struct {
+	profile      model.ForwardProfile
+	first, count int // The group's rules
+}
+
+// printForwardHints shows the client commands of the started forwards,
+// with the ports they listen on, and copies them to the clipboard if asked
+func printForwardHints(rules []forwardRule, hints []forwardHint, copyHints bool) {
+	var commands []string
+	for _, h := range hints {
+		var ports []int
+		for _, r := range rules[h.first : h.first+h.count] {
+			ports = append(ports, r.pf.ListenPort())
+		}
+		commands = append(commands, h.profile.ExpandHint(ports))
+	}
+	if len(commands) == 0 {
+		if copyHints {
+			fmt.Println(i18n.T("cli.forward.no_hint"))
+		}
+		return
+	}
+
+	fmt.Println(i18n.T("cli.forward.hint"))
+	for _, c := range commands {
+		fmt.Println("  " + c)
+	}
+	if copyHints {
+		if err := clipboard.Copy(strings.Join(commands, "\n")); err != nil {
+			fmt.Printf("%s: %v\n", i18n.T("common.error"), err)
+		} else {
+			fmt.Println(i18n.T("cli.forward.hint_copied"))
+		}
+	}
+}
+
+// forwardStatusLines renders the status table of running forwards
+func forwardStatusLines(rules []forwardRule) []string {
+	lines := []string{fmt.Sprintf("%-12s %-44s %-14s %s", i18n.T("cli.forward.profile"), i18n.T("cli.forward.rule"),
+		i18n.T("cli.forward.connections"), i18n.T("cli.forward.traffic"))}
+	for _, r := range rules {
+		profile := r.profile
+		if profile == "" {
+			profile = "-"
+		}
+		if r.err != nil {
+			lines = append(lines, fmt.Sprintf("%-12s %-44s %s: %v", profile, r.pf, i18n.T("common.error"), r.err))
+			continue
+		}
+		stats := r.pf.Stats()
+		lines = append(lines, fmt.Sprintf("%-12s %-44s %-14s %s", profile, r.pf,
+			fmt.Sprintf("%d / %d", stats.Active, stats.Total), sftp.FormatSize(stats.Bytes)))
+	}
+	return lines
+}
+
+// showForwardStatus prints the status table of running forwards, redrawn
+// every second on a terminal, and returns a function to stop redrawing
+func showForwardStatus(rules []forwardRule) func() {
+	fmt.Println()
+	lines := forwardStatusLines(rules)
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			fmt.Printf("\033[%dA", len(lines))
+			lines = forwardStatusLines(rules)
+			for _, line := range lines {
+				fmt.Print("\r\033[K" + line + "\n")
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// printForwardProfiles lists the forward profiles saved with a connection
+func printForwardProfiles(conn *model.Connection) {
+	if len(conn.ForwardProfiles) == 0 {
+		fmt.Printf(i18n.T("cli.forward.no_profiles")+"\n", conn.Name)
+		return
+	}
+	for _, p := range conn.ForwardProfiles {
+		var specs []string
+		for _, f := range p.Forwards {
+			specs = append(specs, ssh.ForwardType(f.Type).Flag()+" "+f.Spec)
+		}
+		fmt.Printf("%-12s %s\n", p.Name, strings.Join(specs, "  "))
+		if p.Hint != "" {
+			fmt.Printf("%-12s %s\n", "", p.Hint)
+		}
+	}
+}
+
+// runExec executes a command on multiple servers
+func runExec(args []string) error {
+	if len(args) == 0 {
+		return errors.New(i18n.T("cli.usage.exec"))
+	}
+
+	// Parse arguments
+	var command string
+	var filter ssh.TargetFilter
+	timeout := 30 * time.Second
+	var deadline time.Duration
+	copyOutput := false
+	diff := false
+	format := ssh.OutputText
+	retry := false
+	var retryID string // Empty to retry the last run
+	pty := false
+	stripColor := false
+	env := make(map[string]string) // Later values win
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if ok, err := parseTargetArg(arg, &filter); err != nil {
+			return err
+		} else if ok {
+			continue
+		}
+		if arg == "--copy" {
+			copyOutput = true
+		} else if arg == "--diff" {
+			diff = true
+		} else if arg == "--pty" {
+			pty = true
+		} else if arg == "--strip-color" {
+			stripColor = true
+		} else if arg == "--env" || strings.HasPrefix(arg, "--env=") {
+			pair, ok := strings.CutPrefix(arg, "--env=")
+			if !ok {
+				if i+1 >= len(args) {
+					return errors.New(i18n.T("cli.usage.exec"))
+				}
+				i++
+				pair = args[i]
+			}
+			name, value, err := ssh.ParseEnv(pair)
+			if err != nil {
+				return err
+			}
+			env[name] = value
+		} else if arg == "--env-file" || strings.HasPrefix(arg, "--env-file=") {
+			path, ok := strings.CutPrefix(arg, "--env-file=")
+			if !ok {
+				if i+1 >= len(args) {
+					return errors.New(i18n.T("cli.usage.exec"))
+				}
+				i++
+				path = args[i]
+			}
+			if err := ssh.ReadEnvFile(path, env); err != nil {
+				return err
+			}
+		} else if arg == "--retry-last" {
+			retry = true
+		} else if arg == "--retry-failed" && i+1 < len(args) {
+			i++
+			retry, retryID = true, args[i]
+		} else if strings.HasPrefix(arg, "--retry-failed=") {
+			retry, retryID = true, strings.TrimPrefix(arg, "--retry-failed=")
+		} else if strings.HasPrefix(arg, "--output=") {
+			f, err := ssh.ParseOutputFormat(strings.TrimPrefix(arg, "--output="))
+			if err != nil {
+				return err
+			}
+			format = f
+		} else if strings.HasPrefix(arg, "--timeout=") {
+			var secs int
+			_, _ = fmt.Sscanf(strings.TrimPrefix(arg, "--timeout="), "%d", &secs)
+			if secs > 0 {
+				timeout = time.Duration(secs) * time.Second
+			}
+		} else if strings.HasPrefix(arg, "--deadline=") {
+			secs, err := strconv.Atoi(strings.TrimPrefix(arg, "--deadline="))
+			if err != nil || secs <= 0 {
+				return errors.New(i18n.T("cli.usage.exec"))
+			}
+			deadline = time.Duration(secs) * time.Second
+		} else if command == "" {
+			command = arg
+		}
+	}
+
+	// A retry runs the same command again unless given another
+	if command == "" && !retry {
+		return errors.New(i18n.T("cli.error.no_command"))
+	}
+	if diff && format != ssh.OutputText {
+		return errors.New(i18n.T("cli.exec.diff_output"))
+	}
+	renderer := format.Renderer()
+	if diff {
+		renderer = ssh.DiffRenderer{}
+	}
+	// Everything but the results goes to stderr when they are for a program
+	info := os.Stdout
+	if format != ssh.OutputText {
+		info = os.Stderr
+	}
+
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := unlockIfNeeded(cfg); err != nil {
+		return err
+	}
+
+	var failed []string
+	if retry {
+		run, err := cfg.FindRun(retryID)
+		if err != nil {
+			if retryID == "" {
+				return errors.New(i18n.T("cli.exec.no_last_run"))
+			}
+			return fmt.Errorf(i18n.T("cli.exec.no_run"), retryID)
+		}
+		if len(run.Failed) == 0 {
+			return fmt.Errorf(i18n.T("cli.exec.nothing_to_retry"), run.ShortID())
+		}
+		if command == "" {
+			command = run.Command
+		}
+		failed = run.Failed
+		fmt.Fprintf(info, i18n.T("cli.exec.retrying")+"\n\n", run.ShortID(), run.At.Local().Format("2006-01-02 15:04"), len(run.Failed), run.Hosts)
+	}
+
+	// Services run the command on every instance
+	resolveCtx, cancelResolve := context.WithTimeout(context.Background(), timeout)
+	connections := ssh.ExpandServices(resolveCtx, filter.Apply(failedConnections(cfg.Connections(), failed)))
+	cancelResolve()
+	if retry {
+		connections = slices.DeleteFunc(connections, func(c model.Connection) bool {
+			return !slices.Contains(failed, c.Name)
+		})
+	}
+
+	if len(connections) == 0 {
+		return errors.New(i18n.T("cli.error.no_match"))
+	}
+
+	fmt.Fprintf(info, i18n.T("cli.exec.targets")+"\n", len(connections))
+	for _, c := range connections {
+		fmt.Fprintf(info, "  - %s (%s@%s)\n", c.Name, c.User, c.Host)
+	}
+	fmt.Fprintf(info, "\n"+i18n.T("cli.exec.command")+"\n", command)
+	fmt.Fprintf(info, i18n.T("cli.exec.timeout")+"\n", timeout)
+	if deadline > 0 {
+		fmt.Fprintf(info, i18n.T("cli.exec.deadline")+"\n", deadline)
+	}
+	// The pseudo-terminal is as big as ours, so commands lay out their
+	// output as they would here
+	ptyWidth, ptyHeight := 80, 24
+	if w, h, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 && h > 0 {
+		ptyWidth, ptyHeight = w, h
+	}
+	if pty {
+		fmt.Fprintf(info, i18n.T("cli.exec.pty")+"\n", ptyWidth, ptyHeight)
+	}
+	if len(env) > 0 {
+		// Names only, as values may be secrets
+		names := slices.Sorted(maps.Keys(env))
+		fmt.Fprintf(info, i18n.T("cli.exec.env")+"\n", strings.Join(names, ", "))
+	}
+	fmt.Fprintln(info)
+
+	// Confirm execution
+	fmt.Fprint(info, i18n.T("cli.confirm.continue"))
+	var answer string
+	_, _ = fmt.Scanln(&answer)
+	if answer != "y" && answer != "Y" {
+		fmt.Fprintln(info, i18n.T("cli.aborted"))
+		return nil
+	}
+
+	// Ctrl+C stops the remote commands and shows what they printed so
+	// far; a second one quits at once
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	// Execute
+	executor := ssh.NewBatchExecutor(connections)
+	executor.SetTimeout(timeout)
+	executor.SetDeadline(deadline)
+	if pty {
+		executor.SetPty(ptyWidth, ptyHeight)
+	}
+	executor.SetStripColor(stripColor)
+	executor.SetEnv(env)
+	hideProgress := showProgress(executor)
+
+	started := time.Now()
+	results := executor.Execute(ctx, command)
+	interrupted := ctx.Err() != nil
+	stop()
+	hideProgress()
+	if interrupted {
+		fmt.Fprintln(info, "\n"+i18n.T("cli.exec.interrupted"))
+	}
+	if err := renderer.Render(os.Stdout, command, results); err != nil {
+		return err
+	}
+
+	run := recordRun(cfg, command, results)
+	notifyDone(cfg, started, fmt.Sprintf(i18n.T("cli.exec.notify"), len(results)-len(run.Failed), len(run.Failed)))
+	if len(run.Failed) > 0 {
+		fmt.Fprintf(info, "\n"+i18n.T("cli.exec.retry_hint")+"\n", len(run.Failed), run.ShortID())
+	}
+
+	if copyOutput {
+		if err := clipboard.Copy(execOutput(results)); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", i18n.T("common.error"), err)
+		} else {
+			fmt.Fprintln(info, i18n.T("cli.exec.copied"))
+		}
+	}
+
+	fireHooks(cfg, execPayload(command, results))
+	if interrupted {
+		return &ExitError{Code: 130}
+	}
+	return nil
+}
+
+// runPlay runs the steps of a playbook in order, each on the hosts its
+// targets select, after showing them and asking to continue
+func runPlay(args []string) error {
+	var path string
+	dryRun, yes := false, false
+	for _, arg := range args {
+		switch {
+		case arg == "--dry-run":
+			dryRun = true
+		case arg == "--yes" || arg == "-y":
+			yes = true
+		case path == "" && !strings.HasPrefix(arg, "-"):
+			path = arg
+		default:
+			return errors.New(i18n.T("cli.usage.play"))
+		}
+	}
+	if path == "" {
+		return errors.New(i18n.T("cli.usage.play"))
+	}
+	p, err := playbook.Load(path)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := unlockIfNeeded(cfg); err != nil {
+		return err
+	}
 
-	// Simple SFTP shell
-	scanner := bufio.NewScanner(os.Stdin)
-	for {
-		pwd, _ := client.Pwd()
-		fmt.Printf("sftp:%s> ", pwd)
+	// Services run the steps on every instance, as resolved when the play
+	// gets to the step
+	targets := func(filter ssh.TargetFilter) []model.Connection {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return ssh.ExpandServices(ctx, filter.Apply(cfg.Connections()))
+	}
 
-		if !scanner.Scan() {
-			break
+	fmt.Printf(i18n.T("cli.play.plan")+"\n", p.Name, len(p.Steps))
+	for i, step := range p.Steps {
+		// A step selecting nothing is a mistake in its targets, caught
+		// before anything runs
+		hosts := p.Filter(step).Apply(cfg.Connections())
+		if len(hosts) == 0 {
+			return fmt.Errorf(i18n.T("cli.play.no_hosts"), i+1, step.Title())
 		}
-
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
+		fmt.Printf("\n  %d. %s\n", i+1, step.Title())
+		if step.Name != "" {
+			fmt.Printf("     $ %s\n", step.Command)
+		}
+		var notes []string
+		if step.When != "" {
+			notes = append(notes, "when "+step.When)
+		}
+		if step.Register != "" {
+			notes = append(notes, "register "+step.Register)
+		}
+		if step.ContinueOnError {
+			notes = append(notes, "continue_on_error")
+		}
+		names := make([]string, len(hosts))
+		for j, h := range hosts {
+			names[j] = h.Name
+		}
+		fmt.Printf("     "+i18n.T("cli.play.hosts")+"\n", len(hosts), strings.Join(names, ", "))
+		if len(notes) > 0 {
+			fmt.Printf("     (%s)\n", strings.Join(notes, ", "))
+		}
+	}
+	if len(p.Env) > 0 {
+		// Names only, as values may be secrets
+		fmt.Printf("\n"+i18n.T("cli.exec.env")+"\n", strings.Join(slices.Sorted(maps.Keys(p.Env)), ", "))
+	}
+	fmt.Println()
+	if dryRun {
+		return nil
+	}
+	if !yes {
+		fmt.Print(i18n.T("cli.confirm.continue"))
+		var answer string
+		_, _ = fmt.Scanln(&answer)
+		if answer != "y" && answer != "Y" {
+			fmt.Println(i18n.T("cli.aborted"))
+			return nil
 		}
+	}
 
-		parts := strings.Fields(line)
-		cmd := parts[0]
-		args := parts[1:]
-
-		switch cmd {
-		case "help":
-			fmt.Println("Commands:")
-			fmt.Println("  ls [path]           List directory")
-			fmt.Println("  cd <path>           Change directory")
-			fmt.Println("  pwd                 Print working directory")
-			fmt.Println("  get <remote> [local] Download file")
-			fmt.Println("  put <local> [remote] Upload file")
-			fmt.Println("  mkdir <path>        Create directory")
-			fmt.Println("  rm <path>           Remove file")
-			fmt.Println("  rmdir <path>        Remove directory")
-			fmt.Println("  exit/quit           Exit SFTP")
-
-		case "ls":
-			path := "."
-			if len(args) > 0 {
-				path = args[0]
-			}
-			files, err := client.List(path)
-			if err != nil {
-				fmt.Printf("Error: %v\n", err)
-				continue
+	// Ctrl+C stops the step running and the play; a second one quits at once
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	runner := playbook.Runner{
+		Targets: targets,
+		Exec: func(ctx context.Context, command string, timeout time.Duration, hosts []model.Connection, env map[string]string, hostEnv map[string]map[string]string) []ssh.BatchResult {
+			executor := ssh.NewBatchExecutor(hosts)
+			executor.SetTimeout(timeout)
+			executor.SetEnv(env)
+			executor.SetHostEnv(hostEnv)
+			hideProgress := showProgress(executor)
+			defer hideProgress()
+			return executor.Execute(ctx, command)
+		},
+		Start: func(i int, step playbook.Step, hosts []model.Connection) {
+			fmt.Printf("\n[%d/%d] %s\n", i+1, len(p.Steps), step.Title())
+			if len(hosts) == 0 {
+				fmt.Println("  " + i18n.T("cli.play.skipped"))
 			}
-			for _, f := range files {
-				fmt.Println(f.String())
+		},
+		Finish: func(r playbook.StepResult) {
+			for _, res := range r.Results {
+				mark := "✓"
+				if res.Error != nil {
+					mark = "✗"
+				}
+				fmt.Printf("  %s %s (%.2fs)", mark, res.Connection.Name, res.Duration.Seconds())
+				if res.Error != nil {
+					fmt.Printf(": %v", res.Error)
+				}
+				fmt.Println()
+				if out := strings.TrimRight(res.Output, "\r\n"); out != "" {
+					fmt.Println("      " + strings.ReplaceAll(out, "\n", "\n      "))
+				}
 			}
-
-		case "cd":
-			if len(args) == 0 {
-				fmt.Println("Usage: cd <path>")
-				continue
+			if len(r.Skipped) > 0 && len(r.Results) > 0 {
+				fmt.Printf("  "+i18n.T("cli.play.skipped_hosts")+"\n", len(r.Skipped))
 			}
-			// Note: SFTP doesn't have cd, we'd need to track cwd ourselves
-			fmt.Println("Note: cd not fully supported, use absolute paths")
-
-		case "pwd":
-			pwd, err := client.Pwd()
-			if err != nil {
-				fmt.Printf("Error: %v\n", err)
-				continue
+			if failed := r.Failed(); failed > 0 && r.Step.ContinueOnError {
+				fmt.Printf("  "+i18n.T("cli.play.continuing")+"\n", failed)
 			}
-			fmt.Println(pwd)
+		},
+	}
 
-		case "get":
-			if len(args) == 0 {
-				fmt.Println("Usage: get <remote> [local]")
-				continue
-			}
-			remote := args[0]
-			local := remote
-			if len(args) > 1 {
-				local = args[1]
-			}
-			if err := client.Download(remote, local); err != nil {
-				fmt.Printf("Error: %v\n", err)
-				continue
-			}
-			fmt.Printf("Downloaded %s -> %s\n", remote, local)
+	started := time.Now()
+	results, err := runner.Run(ctx, p)
+	tolerated := 0
+	for _, r := range results {
+		if r.Step.ContinueOnError {
+			tolerated += r.Failed()
+		}
+	}
 
-		case "put":
-			if len(args) == 0 {
-				fmt.Println("Usage: put <local> [remote]")
-				continue
+	fmt.Println()
+	var stepErr *playbook.StepError
+	switch {
+	case errors.Is(err, context.Canceled):
+		fmt.Println(i18n.T("cli.exec.interrupted"))
+		return &ExitError{Code: 130}
+	case errors.As(err, &stepErr):
+		summary := fmt.Sprintf(i18n.T("cli.play.failed"), p.Name, stepErr.Step, stepErr.Title, stepErr.Failed)
+		fmt.Println(summary)
+		notifyDone(cfg, started, summary)
+		return &ExitError{Code: 1}
+	case err != nil:
+		return err
+	}
+	summary := fmt.Sprintf(i18n.T("cli.play.done"), p.Name, len(results), tolerated)
+	fmt.Println(summary)
+	notifyDone(cfg, started, summary)
+	return nil
+}
+
+// runPush uploads a file to the selected hosts in parallel, optionally
+// setting its mode and owner and running a command after each upload
+func runPush(args []string) error {
+	var filter ssh.TargetFilter
+	var paths []string
+	opts := sftp.PushOptions{Timeout: 2 * time.Minute}
+	for _, arg := range args {
+		if ok, err := parseTargetArg(arg, &filter); err != nil {
+			return err
+		} else if ok {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(arg, "--mode="):
+			mode, err := strconv.ParseUint(strings.TrimPrefix(arg, "--mode="), 8, 32)
+			if err != nil || mode == 0 || mode > 0o7777 {
+				return fmt.Errorf(i18n.T("cli.push.invalid_mode"), strings.TrimPrefix(arg, "--mode="))
 			}
-			local := args[0]
-			remote := local
-			if len(args) > 1 {
-				remote = args[1]
+			opts.Mode = os.FileMode(mode)
+		case strings.HasPrefix(arg, "--owner="):
+			opts.Owner = strings.TrimPrefix(arg, "--owner=")
+		case arg == "--preserve-times":
+			opts.Times = true
+		case strings.HasPrefix(arg, "--run="):
+			opts.Command = strings.TrimPrefix(arg, "--run=")
+		case strings.HasPrefix(arg, "--parallel="):
+			n, err := strconv.Atoi(strings.TrimPrefix(arg, "--parallel="))
+			if err != nil || n <= 0 {
+				return errors.New(i18n.T("cli.usage.push"))
 			}
-			if err := client.Upload(local, remote); err != nil {
-				fmt.Printf("Error: %v\n", err)
-				continue
+			opts.Parallel = n
+		case strings.HasPrefix(arg, "--timeout="):
+			secs, err := strconv.Atoi(strings.TrimPrefix(arg, "--timeout="))
+			if err != nil || secs <= 0 {
+				return errors.New(i18n.T("cli.usage.push"))
 			}
-			fmt.Printf("Uploaded %s -> %s\n", local, remote)
+			opts.Timeout = time.Duration(secs) * time.Second
+		case strings.HasPrefix(arg, "--"):
+			return errors.New(i18n.T("cli.usage.push"))
+		default:
+			paths = append(paths, arg)
+		}
+	}
+	if len(paths) != 2 {
+		return errors.New(i18n.T("cli.usage.push"))
+	}
+	localPath, remotePath := paths[0], paths[1]
+	if info, err := os.Stat(config.ExpandHome(localPath)); err != nil {
+		return err
+	} else if info.IsDir() {
+		return fmt.Errorf(i18n.T("cli.push.not_file"), localPath)
+	}
 
-		case "mkdir":
-			if len(args) == 0 {
-				fmt.Println("Usage: mkdir <path>")
-				continue
-			}
-			if err := client.Mkdir(args[0]); err != nil {
-				fmt.Printf("Error: %v\n", err)
-				continue
-			}
-			fmt.Printf("Created directory %s\n", args[0])
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
 
-		case "rm":
-			if len(args) == 0 {
-				fmt.Println("Usage: rm <path>")
-				continue
-			}
-			if err := client.Remove(args[0]); err != nil {
-				fmt.Printf("Error: %v\n", err)
-				continue
-			}
-			fmt.Printf("Removed %s\n", args[0])
+	if err := unlockIfNeeded(cfg); err != nil {
+		return err
+	}
 
-		case "rmdir":
-			if len(args) == 0 {
-				fmt.Println("Usage: rmdir <path>")
-				continue
-			}
-			if err := client.RemoveAll(args[0]); err != nil {
-				fmt.Printf("Error: %v\n", err)
-				continue
-			}
-			fmt.Printf("Removed directory %s\n", args[0])
+	var connections []model.Connection
+	for _, conn := range filter.Apply(cfg.Connections()) {
+		if !conn.IsTelnet() {
+			connections = append(connections, conn)
+		}
+	}
+	if len(connections) == 0 {
+		return errors.New(i18n.T("cli.error.no_match"))
+	}
 
-		case "exit", "quit":
-			fmt.Println("Goodbye!")
-			return nil
+	fmt.Printf(i18n.T("cli.push.targets")+"\n", localPath, remotePath, len(connections))
+	for _, c := range connections {
+		fmt.Printf("  - %s (%s@%s)\n", c.Name, c.User, c.Host)
+	}
+	if opts.Command != "" {
+		fmt.Printf("\n"+i18n.T("cli.exec.command")+"\n", opts.Command)
+	}
+	fmt.Println()
 
-		default:
-			fmt.Printf("Unknown command: %s. Type 'help' for available commands.\n", cmd)
+	// Confirm the upload
+	fmt.Print(i18n.T("cli.confirm.continue"))
+	var answer string
+	_, _ = fmt.Scanln(&answer)
+	if answer != "y" && answer != "Y" {
+		fmt.Println(i18n.T("cli.aborted"))
+		return nil
+	}
+
+	started := time.Now()
+	results := sftp.Push(context.Background(), connections, localPath, remotePath, opts)
+
+	failed := 0
+	fmt.Println()
+	for _, r := range results {
+		if r.Error != nil {
+			failed++
+			fmt.Printf("✗ %-20s %v\n", r.Connection.Name, r.Error)
+		} else {
+			fmt.Printf("✓ %-20s %s (%s)\n", r.Connection.Name, r.RemotePath, model.ShortDuration(r.Duration))
+		}
+		if out := strings.TrimSpace(r.Output); out != "" {
+			fmt.Println("    " + strings.ReplaceAll(out, "\n", "\n    "))
 		}
 	}
+	fmt.Printf("\n"+i18n.T("cli.push.summary")+"\n", len(results)-failed, failed)
+	notifyDone(cfg, started, fmt.Sprintf(i18n.T("cli.push.summary"), len(results)-failed, failed))
 
+	if failed > 0 {
+		return &ExitError{Code: 1}
+	}
 	return nil
 }
 
-// runForward starts port forwarding
-func runForward(args []string) error {
-	if len(args) < 3 {
-		return fmt.Errorf("usage: gossh forward <name> -L/-R <spec>\nExample: gossh forward myserver -L 8080:localhost:80")
+func runTail(args []string) error {
+	var filter ssh.TargetFilter
+	var paths []string
+	var grep *regexp.Regexp
+	lines := 10
+	for _, arg := range args {
+		if ok, err := parseTargetArg(arg, &filter); err != nil {
+			return err
+		} else if ok {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(arg, "--grep="):
+			re, err := regexp.Compile(strings.TrimPrefix(arg, "--grep="))
+			if err != nil {
+				return fmt.Errorf("invalid --grep pattern: %w", err)
+			}
+			grep = re
+		case strings.HasPrefix(arg, "--lines="):
+			n, err := strconv.Atoi(strings.TrimPrefix(arg, "--lines="))
+			if err != nil || n < 0 {
+				return errors.New(i18n.T("cli.usage.tail"))
+			}
+			lines = n
+		case strings.HasPrefix(arg, "--"):
+			return errors.New(i18n.T("cli.usage.tail"))
+		default:
+			paths = append(paths, arg)
+		}
 	}
-
-	name := args[0]
-	fwdFlag := args[1]
-	spec := args[2]
-
-	var fwdType ssh.ForwardType
-	switch fwdFlag {
-	case "-L":
-		fwdType = ssh.ForwardLocal
-	case "-R":
-		fwdType = ssh.ForwardRemote
-	default:
-		return fmt.Errorf("invalid forward type: %s (use -L or -R)", fwdFlag)
+	if len(paths) != 1 {
+		return errors.New(i18n.T("cli.usage.tail"))
 	}
 
 	cfg, err := config.NewManager()
@@ -666,131 +4454,266 @@ func runForward(args []string) error {
 		return err
 	}
 
-	conn := findConnection(cfg.Connections(), name)
-	if conn == nil {
-		return fmt.Errorf("connection '%s' not found", name)
+	var connections []model.Connection
+	for _, conn := range filter.Apply(cfg.Connections()) {
+		if !conn.IsTelnet() {
+			connections = append(connections, conn)
+		}
 	}
-
-	pf, err := ssh.ParsePortForward(fwdType, spec)
-	if err != nil {
-		return err
+	if len(connections) == 0 {
+		return errors.New(i18n.T("cli.error.no_match"))
 	}
 
-	fmt.Printf("Setting up port forwarding to %s (%s@%s:%d)...\n",
-		conn.Name, conn.User, conn.Host, conn.Port)
-
-	forwarder := ssh.NewForwarder(*conn)
-	forwarder.AddForward(pf)
-
-	if err := forwarder.Connect(); err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
+	// Each host keeps its color and a prefix as wide as the longest name,
+	// so interleaved lines stay readable
+	width := 0
+	for _, c := range connections {
+		width = max(width, lipgloss.Width(c.Name))
 	}
-
-	if err := forwarder.Start(); err != nil {
-		forwarder.Stop()
-		return fmt.Errorf("failed to start forwarding: %w", err)
+	prefixes := make(map[string]string, len(connections))
+	for i, c := range connections {
+		style := lipgloss.NewStyle().Foreground(styles.GroupColor("", i)).Width(width)
+		prefixes[c.ID] = style.Render(c.Name) + " │ "
 	}
 
-	fmt.Println("Port forwarding active. Press Ctrl+C to stop.")
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	// Wait for interrupt
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-	<-sigCh
+	fmt.Fprintf(os.Stderr, i18n.T("cli.tail.following")+"\n", paths[0], len(connections))
+	out := make(chan ssh.TailLine)
+	go ssh.Tail(ctx, connections, ssh.TailCommand(paths[0], lines), out)
 
-	fmt.Println("\nStopping port forwarding...")
-	forwarder.Stop()
+	failed := 0
+	for line := range out {
+		if line.Err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "%s%v\n", prefixes[line.Connection.ID], line.Err)
+			continue
+		}
+		if grep != nil && !grep.MatchString(line.Text) {
+			continue
+		}
+		fmt.Println(prefixes[line.Connection.ID] + line.Text)
+	}
 
+	if failed > 0 && ctx.Err() == nil {
+		return &ExitError{Code: 1}
+	}
 	return nil
 }
 
-// runExec executes a command on multiple servers
-func runExec(args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("usage: gossh exec <command> [--group=<group>] [--tags=<tags>] [--names=<names>]")
+// failedConnections returns the connections that a run failed on, by the
+// names it recorded: a service's failed instances are named
+// <service>/<host>, so the service itself is returned for them. Nil selects
+// every connection.
+func failedConnections(connections []model.Connection, failed []string) []model.Connection {
+	if failed == nil {
+		return connections
+	}
+	var selected []model.Connection
+	for _, c := range connections {
+		if slices.ContainsFunc(failed, func(name string) bool {
+			return name == c.Name || strings.HasPrefix(name, c.Name+"/")
+		}) {
+			selected = append(selected, c)
+		}
 	}
+	return selected
+}
 
-	// Parse arguments
-	var command string
-	var group string
-	var tags []string
-	var names []string
-	timeout := 30 * time.Second
+// showProgress draws a line on stderr showing how far the executor's run
+// has got, redrawn as hosts start and finish and the spinner turns, when
+// stderr is a terminal. The returned function stops it and clears the line.
+func showProgress(executor *ssh.BatchExecutor) func() {
+	if !term.IsTerminal(int(os.Stderr.Fd())) {
+		return func() {}
+	}
 
-	for _, arg := range args {
-		if strings.HasPrefix(arg, "--group=") {
-			group = strings.TrimPrefix(arg, "--group=")
-		} else if strings.HasPrefix(arg, "--tags=") {
-			tags = strings.Split(strings.TrimPrefix(arg, "--tags="), ",")
-		} else if strings.HasPrefix(arg, "--names=") {
-			names = strings.Split(strings.TrimPrefix(arg, "--names="), ",")
-		} else if strings.HasPrefix(arg, "--timeout=") {
-			var secs int
-			_, _ = fmt.Sscanf(strings.TrimPrefix(arg, "--timeout="), "%d", &secs)
-			if secs > 0 {
-				timeout = time.Duration(secs) * time.Second
+	var mu sync.Mutex
+	var progress ssh.BatchProgress
+	frame := 0
+	draw := func() {
+		if progress.Total > 0 {
+			fmt.Fprint(os.Stderr, "\r\033[K"+views.ProgressLine(progress, frame))
+		}
+	}
+	executor.SetProgress(func(p ssh.BatchProgress) {
+		mu.Lock()
+		defer mu.Unlock()
+		progress = p
+		draw()
+	})
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(views.SpinnerInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				mu.Lock()
+				frame++
+				draw()
+				mu.Unlock()
 			}
-		} else if command == "" {
-			command = arg
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+		mu.Lock()
+		defer mu.Unlock()
+		if progress.Total > 0 {
+			fmt.Fprint(os.Stderr, "\r\033[K")
 		}
 	}
+}
 
-	if command == "" {
-		return fmt.Errorf("no command specified")
+// recordRun records a finished batch run with the hosts it failed on, so
+// they can be retried with exec --retry-last or --retry-failed
+func recordRun(cfg *config.Manager, command string, results []ssh.BatchResult) model.Run {
+	var failed []string
+	for _, r := range results {
+		if r.Error != nil {
+			failed = append(failed, r.Connection.Name)
+		}
 	}
-
-	cfg, err := config.NewManager()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+	run := model.NewRun(command, len(results), failed)
+	if err := cfg.RecordRun(run); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", i18n.T("common.error"), err)
 	}
+	return run
+}
 
-	if err := unlockIfNeeded(cfg); err != nil {
-		return err
+// execOutput joins each host's output under a header for the clipboard
+func execOutput(results []ssh.BatchResult) string {
+	var b strings.Builder
+	for i, r := range results {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "== %s ==\n", r.Connection.Name)
+		if r.Error != nil && r.Output == "" {
+			fmt.Fprintf(&b, "%v\n", r.Error)
+			continue
+		}
+		b.WriteString(r.Output)
+		if !strings.HasSuffix(r.Output, "\n") {
+			b.WriteString("\n")
+		}
 	}
+	return b.String()
+}
 
-	connections := cfg.Connections()
-
-	// Filter connections
-	if group != "" {
-		connections = ssh.FilterByGroup(connections, group)
-	}
-	if len(tags) > 0 {
-		connections = ssh.FilterByTags(connections, tags)
+// execPayload summarizes a batch exec run for hooks
+func execPayload(command string, results []ssh.BatchResult) hooks.Payload {
+	failed := 0
+	hostResults := make([]map[string]any, 0, len(results))
+	for _, r := range results {
+		entry := map[string]any{
+			"name":        r.Connection.Name,
+			"host":        r.Connection.Host,
+			"exit_code":   r.ExitCode,
+			"duration_ms": r.Duration.Milliseconds(),
+		}
+		if r.Error != nil {
+			failed++
+			entry["error"] = r.Error.Error()
+		}
+		hostResults = append(hostResults, entry)
 	}
-	if len(names) > 0 {
-		connections = ssh.FilterByNames(connections, names)
+
+	text := fmt.Sprintf("gossh exec %q finished: %d succeeded, %d failed", command, len(results)-failed, failed)
+	p := hooks.NewPayload(hooks.EventExecComplete, nil, text)
+	p.Data = map[string]any{
+		"command":   command,
+		"succeeded": len(results) - failed,
+		"failed":    failed,
+		"results":   hostResults,
 	}
+	return p
+}
 
-	if len(connections) == 0 {
-		return fmt.Errorf("no matching connections found")
+// runMonitor probes connections on an interval and serves the results
+// as Prometheus metrics
+func runMonitor(args []string) error {
+	listen := ":9100"
+	interval := 30 * time.Second
+	timeout := 5 * time.Second
+	var filter ssh.TargetFilter
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if ok, err := parseTargetArg(arg, &filter); err != nil {
+			return err
+		} else if ok {
+			continue
+		}
+		switch {
+		case arg == "--listen" && i+1 < len(args):
+			i++
+			listen = args[i]
+		case strings.HasPrefix(arg, "--listen="):
+			listen = strings.TrimPrefix(arg, "--listen=")
+		case strings.HasPrefix(arg, "--interval="):
+			secs, err := strconv.Atoi(strings.TrimPrefix(arg, "--interval="))
+			if err != nil || secs <= 0 {
+				return errors.New(i18n.T("cli.usage.monitor"))
+			}
+			interval = time.Duration(secs) * time.Second
+		case strings.HasPrefix(arg, "--timeout="):
+			secs, err := strconv.Atoi(strings.TrimPrefix(arg, "--timeout="))
+			if err != nil || secs <= 0 {
+				return errors.New(i18n.T("cli.usage.monitor"))
+			}
+			timeout = time.Duration(secs) * time.Second
+		default:
+			return errors.New(i18n.T("cli.usage.monitor"))
+		}
 	}
 
-	fmt.Printf("Executing command on %d server(s):\n", len(connections))
-	for _, c := range connections {
-		fmt.Printf("  - %s (%s@%s)\n", c.Name, c.User, c.Host)
+	// Probes only need host and port, so the config stays locked
+	cfg, err := config.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
 	}
-	fmt.Printf("\nCommand: %s\n", command)
-	fmt.Printf("Timeout: %v\n\n", timeout)
 
-	// Confirm execution
-	fmt.Print("Continue? [y/N]: ")
-	var answer string
-	_, _ = fmt.Scanln(&answer)
-	if answer != "y" && answer != "Y" {
-		fmt.Println("Aborted.")
-		return nil
+	connections := filter.Apply(cfg.Connections())
+	if len(connections) == 0 {
+		return errors.New(i18n.T("cli.error.no_match"))
 	}
 
-	// Execute
-	ctx, cancel := context.WithTimeout(context.Background(), timeout*time.Duration(len(connections)))
-	defer cancel()
+	mon := monitor.NewMonitor(connections)
+	mon.SetInterval(interval)
+	mon.SetTimeout(timeout)
+	mon.SetHooks(hooks.NewDispatcher(cfg.Hooks()))
 
-	executor := ssh.NewBatchExecutor(connections)
-	executor.SetTimeout(timeout)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	results := executor.Execute(ctx, command)
-	ssh.PrintResults(results)
+	server := &http.Server{
+		Addr:              listen,
+		Handler:           mon.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
 
+	go mon.Run(ctx)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf(i18n.T("cli.monitor.start")+"\n", len(connections), interval, listen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve metrics: %w", err)
+	}
 	return nil
 }
 
@@ -798,7 +4721,7 @@ func runExec(args []string) error {
 
 func unlockIfNeeded(cfg *config.Manager) error {
 	if cfg.IsFirstRun() {
-		return fmt.Errorf("first run: please use TUI mode to complete setup")
+		return errors.New(i18n.T("cli.error.first_run"))
 	}
 
 	// Try auto-unlock first (for password protection disabled mode)
@@ -806,35 +4729,166 @@ func unlockIfNeeded(cfg *config.Manager) error {
 		return err
 	}
 
-	// If still locked, prompt for password
+	// If still locked, use the key remembered by a recent command or in
+	// the OS keyring, or prompt for password unless failed attempts make
+	// the next one wait
 	if !cfg.IsUnlocked() {
-		password, err := readPassword("Enter master password: ")
+		settings := cfg.GetSettings()
+		if settings.KeyCache() > 0 {
+			if key, err := keycache.Get(cfg.Path(), settings.EncryptionSalt); err == nil && cfg.UnlockWithKey(key) == nil {
+				return nil
+			}
+		}
+		if settings.RememberKey {
+			if err := cfg.UnlockFromKeyring(); err == nil {
+				return nil
+			} else if !errors.Is(err, config.ErrNotRemembered) {
+				fmt.Fprintf(os.Stderr, i18n.T("cli.warning")+"\n", err)
+			}
+		}
+		if _, wait := cfg.UnlockAttempts(); wait > 0 {
+			return fmt.Errorf(i18n.T("unlock.failed"), wait.Round(time.Second))
+		}
+		password, err := readPassword(i18n.T("cli.password.prompt"))
 		if err != nil {
 			return err
 		}
 		if err := cfg.Unlock(password); err != nil {
+			if _, wait := cfg.UnlockAttempts(); wait > 0 {
+				return fmt.Errorf("failed to unlock: %w\n"+i18n.T("unlock.failed"), err, wait.Round(time.Second))
+			}
 			return fmt.Errorf("failed to unlock: %w", err)
 		}
+		if ttl := settings.KeyCache(); ttl > 0 {
+			if key, ok := cfg.MasterKey(); ok {
+				if err := keycache.Start(cfg.Path(), key, settings.EncryptionSalt, ttl); err != nil {
+					fmt.Fprintf(os.Stderr, i18n.T("cli.key_cache.failed")+"\n", err)
+				}
+			}
+		}
 	}
 	return nil
 }
 
-// readPassword reads a password from stdin without echoing it
+// warnedKeys holds the key files warnKeyFile warned about
+var warnedKeys sync.Map
+
+// warnKeyFile warns once per key file that other users can access it
+func warnKeyFile(err error) {
+	var perm config.PermissionError
+	if errors.As(err, &perm) {
+		if _, warned := warnedKeys.LoadOrStore(perm.Path, true); warned {
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, i18n.T("cli.warning.key_mode")+"\n", err)
+}
+
+// readPassword reads a password from the terminal without echoing it.
+// When stdin is not the terminal, e.g. under ssh's ProxyCommand, the
+// controlling terminal is used, leaving stdin and stdout to the data.
 func readPassword(prompt string) (string, error) {
-	fmt.Print(prompt)
-	bytePassword, err := term.ReadPassword(int(os.Stdin.Fd()))
+	in, out := os.Stdin, os.Stdout
+	if !term.IsTerminal(int(in.Fd())) && runtime.GOOS != "windows" {
+		if tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0); err == nil {
+			defer tty.Close()
+			in, out = tty, tty
+		}
+	}
+	fmt.Fprint(out, prompt)
+	bytePassword, err := term.ReadPassword(int(in.Fd()))
 	if err != nil {
 		return "", fmt.Errorf("failed to read password: %w", err)
 	}
-	fmt.Println() // Print newline after password input
+	fmt.Fprintln(out) // Print newline after password input
 	return string(bytePassword), nil
 }
 
+// parseTargetArg parses a host selection flag shared by batch commands into
+// the filter. It reports whether the argument was consumed.
+func parseTargetArg(arg string, f *ssh.TargetFilter) (bool, error) {
+	switch {
+	case strings.HasPrefix(arg, "--group="):
+		f.Group = strings.TrimPrefix(arg, "--group=")
+	case strings.HasPrefix(arg, "--tags="):
+		f.Tags = splitList(strings.TrimPrefix(arg, "--tags="))
+	case strings.HasPrefix(arg, "--names="):
+		f.Names = splitList(strings.TrimPrefix(arg, "--names="))
+	case strings.HasPrefix(arg, "--match="):
+		re, err := regexp.Compile(strings.TrimPrefix(arg, "--match="))
+		if err != nil {
+			return false, fmt.Errorf("invalid --match pattern: %w", err)
+		}
+		f.Match = re
+	case strings.HasPrefix(arg, "--exclude-group="):
+		f.ExcludeGroup = strings.TrimPrefix(arg, "--exclude-group=")
+	case strings.HasPrefix(arg, "--exclude-tags="):
+		f.ExcludeTags = splitList(strings.TrimPrefix(arg, "--exclude-tags="))
+	case strings.HasPrefix(arg, "--exclude-names="):
+		f.ExcludeNames = splitList(strings.TrimPrefix(arg, "--exclude-names="))
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// resolveTarget resolves a single connection from an exact name, a glob
+// pattern and/or a regex, failing when the selection is empty or ambiguous
+func resolveTarget(connections []model.Connection, name string, match *regexp.Regexp) (*model.Connection, error) {
+	if match == nil {
+		if conn := findConnection(connections, name); conn != nil {
+			return conn, nil
+		}
+	}
+
+	candidates := connections
+	if name != "" {
+		candidates = ssh.FilterByNames(candidates, []string{name})
+	}
+	if match != nil {
+		candidates = ssh.FilterByMatch(candidates, match)
+	}
+
+	switch len(candidates) {
+	case 0:
+		if match != nil {
+			return nil, fmt.Errorf(i18n.T("cli.error.match_none"), match.String())
+		}
+		return nil, fmt.Errorf(i18n.T("cli.error.not_found"), name)
+	case 1:
+		return &candidates[0], nil
+	default:
+		matched := make([]string, len(candidates))
+		for i, c := range candidates {
+			matched[i] = c.Name
+		}
+		return nil, fmt.Errorf(i18n.T("cli.error.match_many"), len(candidates), strings.Join(matched, ", "))
+	}
+}
+
+// findConnection looks a connection up by name, then by alias
 func findConnection(connections []model.Connection, name string) *model.Connection {
 	for i := range connections {
 		if connections[i].Name == name {
 			return &connections[i]
 		}
 	}
+	for i := range connections {
+		if connections[i].HasName(name) {
+			return &connections[i]
+		}
+	}
 	return nil
 }
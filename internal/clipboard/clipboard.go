@@ -0,0 +1,44 @@
+// Package clipboard copies text to the user's clipboard
+package clipboard
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// Copy places text on the clipboard. Locally it uses the system clipboard
+// (pbcopy, xclip/xsel/wl-copy or the Windows API). Inside an SSH session,
+// or when no clipboard tool is available, it falls back to an OSC 52
+// escape sequence, which most modern terminals turn into a local copy.
+func Copy(text string) error {
+	if os.Getenv("SSH_TTY") == "" && os.Getenv("SSH_CONNECTION") == "" {
+		if err := clipboard.WriteAll(text); err == nil {
+			return nil
+		}
+	}
+	return copyOSC52(text)
+}
+
+// copyOSC52 asks the terminal to set its clipboard
+func copyOSC52(text string) error {
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	} else if strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		seq = seq.Screen()
+	}
+
+	// Write to the controlling terminal so redirected output stays clean
+	var out io.Writer = os.Stderr
+	if tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0); err == nil {
+		defer tty.Close()
+		out = tty
+	}
+
+	_, err := seq.WriteTo(out)
+	return err
+}
@@ -0,0 +1,244 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gossh/internal/crypto"
+	"gossh/internal/model"
+)
+
+var (
+	// ErrLocked is returned for what needs the master key while the config
+	// is locked
+	ErrLocked = errors.New("config is locked")
+
+	ErrAttachmentTooLarge = fmt.Errorf("attachments are limited to %d KB", model.MaxAttachmentSize/1024)
+	ErrAttachmentEmpty    = errors.New("attachment is empty")
+	ErrAttachmentExists   = errors.New("connection has an attachment of that name")
+	ErrAttachmentNotFound = errors.New("attachment not found")
+)
+
+// attachmentPath returns the file holding an attachment of a connection
+func (m *Manager) attachmentPath(connID, attachmentID string) string {
+	return filepath.Join(AttachmentsDir(m.path), connID, attachmentID)
+}
+
+// AddAttachment encrypts data and keeps it with the connection under name,
+// the base name of the file it came from. The config must be unlocked.
+func (m *Manager) AddAttachment(connID, name string, data []byte) (model.Attachment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = filepath.Base(strings.TrimSpace(name))
+	switch {
+	case m.cryptoService == nil:
+		return model.Attachment{}, ErrLocked
+	case len(data) == 0:
+		return model.Attachment{}, ErrAttachmentEmpty
+	case len(data) > model.MaxAttachmentSize:
+		return model.Attachment{}, ErrAttachmentTooLarge
+	case name == "." || name == string(filepath.Separator):
+		return model.Attachment{}, model.ErrNameRequired
+	}
+
+	conn := m.findUnlocked(connID)
+	if conn == nil {
+		return model.Attachment{}, errors.New("connection not found")
+	}
+	if slices.ContainsFunc(conn.Attachments, func(a model.Attachment) bool { return a.Name == name }) {
+		return model.Attachment{}, ErrAttachmentExists
+	}
+
+	// Each attachment has a key of its own, so a new master password only
+	// re-encrypts the keys in the config, not the files
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return model.Attachment{}, err
+	}
+	encryptor, err := crypto.NewEncryptor(key)
+	if err != nil {
+		return model.Attachment{}, err
+	}
+	content, err := encryptor.Encrypt(string(data))
+	if err != nil {
+		return model.Attachment{}, err
+	}
+	encryptedKey, err := m.cryptoService.Encrypt(base64.StdEncoding.EncodeToString(key))
+	if err != nil {
+		return model.Attachment{}, err
+	}
+
+	attachment := model.Attachment{
+		ID:           uuid.New().String(),
+		Name:         name,
+		Size:         len(data),
+		AddedAt:      time.Now(),
+		EncryptedKey: encryptedKey,
+	}
+	path := m.attachmentPath(connID, attachment.ID)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return model.Attachment{}, err
+	}
+	if err := WritePrivateFile(path, []byte(content)); err != nil {
+		return model.Attachment{}, err
+	}
+
+	conn.Attachments = append(conn.Attachments, attachment)
+	if err := m.saveUnlocked(); err != nil {
+		conn.Attachments = conn.Attachments[:len(conn.Attachments)-1]
+		_ = os.Remove(path)
+		return model.Attachment{}, err
+	}
+	return attachment, nil
+}
+
+// OpenAttachment returns the decrypted content of an attachment. Each use
+// is recorded in the audit log.
+func (m *Manager) OpenAttachment(connID, attachmentID string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cryptoService == nil {
+		return nil, ErrLocked
+	}
+	conn := m.findUnlocked(connID)
+	if conn == nil {
+		return nil, ErrAttachmentNotFound
+	}
+	i := slices.IndexFunc(conn.Attachments, func(a model.Attachment) bool { return a.ID == attachmentID })
+	if i < 0 {
+		return nil, ErrAttachmentNotFound
+	}
+	attachment := conn.Attachments[i]
+
+	encryptor, err := m.attachmentEncryptorUnlocked(attachment)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(m.attachmentPath(connID, attachment.ID))
+	if err != nil {
+		return nil, err
+	}
+	data, err := encryptor.Decrypt(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", attachment.Name, err)
+	}
+
+	_ = m.auditUnlocked(AuditAttachment, conn.Name+"/"+attachment.Name)
+	return []byte(data), nil
+}
+
+// DeleteAttachment removes an attachment and its file
+func (m *Manager) DeleteAttachment(connID, attachmentID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	conn := m.findUnlocked(connID)
+	if conn == nil {
+		return ErrAttachmentNotFound
+	}
+	i := slices.IndexFunc(conn.Attachments, func(a model.Attachment) bool { return a.ID == attachmentID })
+	if i < 0 {
+		return ErrAttachmentNotFound
+	}
+	conn.Attachments = slices.Delete(conn.Attachments, i, i+1)
+	// The file goes with the next sweep, once the config no longer has it
+	return m.saveUnlocked()
+}
+
+// findUnlocked returns the stored connection with id, trashed ones too
+// (caller must hold lock)
+func (m *Manager) findUnlocked(id string) *model.Connection {
+	for _, conn := range m.storedConnections() {
+		if conn.ID == id {
+			return conn
+		}
+	}
+	return nil
+}
+
+// attachmentEncryptorUnlocked returns the encryptor of an attachment's
+// content, from its key (caller must hold lock)
+func (m *Manager) attachmentEncryptorUnlocked(a model.Attachment) (*crypto.Encryptor, error) {
+	encoded, err := m.cryptoService.Decrypt(a.EncryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the key of %s: %w", a.Name, err)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the key of %s: %w", a.Name, err)
+	}
+	return crypto.NewEncryptor(key)
+}
+
+// rekeyAttachmentsUnlocked re-encrypts the key of every attachment for
+// next, which is about to replace the crypto service (caller must hold
+// lock). Nothing changes unless all keys could be re-encrypted.
+func (m *Manager) rekeyAttachmentsUnlocked(next *crypto.CryptoService) error {
+	type rekeyed struct {
+		attachment *model.Attachment
+		key        string
+	}
+	var done []rekeyed
+	for _, conn := range m.storedConnections() {
+		for i := range conn.Attachments {
+			a := &conn.Attachments[i]
+			if m.cryptoService == nil {
+				return ErrLocked
+			}
+			key, err := m.cryptoService.Decrypt(a.EncryptedKey)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt the key of %s: %w", a.Name, err)
+			}
+			encrypted, err := next.Encrypt(key)
+			if err != nil {
+				return err
+			}
+			done = append(done, rekeyed{a, encrypted})
+		}
+	}
+	for _, r := range done {
+		r.attachment.EncryptedKey = r.key
+	}
+	return nil
+}
+
+// sweepAttachmentsUnlocked removes the files of attachments the config no
+// longer has, e.g. of deleted attachments and purged connections (caller
+// must hold lock). Failures are left for the next sweep.
+func (m *Manager) sweepAttachmentsUnlocked() {
+	dir := AttachmentsDir(m.path)
+	connDirs, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	kept := make(map[string][]model.Attachment)
+	for _, conn := range m.storedConnections() {
+		kept[conn.ID] = conn.Attachments
+	}
+	for _, connDir := range connDirs {
+		attachments, ok := kept[connDir.Name()]
+		if !ok || len(attachments) == 0 {
+			_ = os.RemoveAll(filepath.Join(dir, connDir.Name()))
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(dir, connDir.Name()))
+		if err != nil {
+			continue
+		}
+		for _, f := range files {
+			if !slices.ContainsFunc(attachments, func(a model.Attachment) bool { return a.ID == f.Name() }) {
+				_ = os.Remove(filepath.Join(dir, connDir.Name(), f.Name()))
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Audit log events
+const (
+	AuditUnlock        = "unlock"         // The master password was accepted
+	AuditUnlockFailed  = "unlock.failed"  // A wrong master password was entered
+	AuditUnlockDelayed = "unlock.delayed" // An attempt was refused while waiting after failures
+	AuditLock          = "lock"           // The keys were dropped with ctrl+l or gossh lock
+	AuditRemember      = "remember"       // The master key was stored in the OS keyring
+	AuditForget        = "forget"         // The master key was removed from the OS keyring
+	AuditAttachment    = "attachment"     // An attachment was decrypted to be viewed or exported
+	AuditReveal        = "reveal"         // A password or key passphrase was shown with ctrl+r
+)
+
+// AuditEntry is a line of the audit log
+type AuditEntry struct {
+	Time   time.Time `json:"time"`
+	Event  string    `json:"event"`
+	User   string    `json:"user,omitempty"` // Local user
+	Detail string    `json:"detail,omitempty"`
+}
+
+// auditUnlocked appends an entry to the audit log (caller must hold lock).
+// The log is append-only JSON lines, one entry per line.
+func (m *Manager) auditUnlocked(event, detail string) error {
+	if err := os.MkdirAll(filepath.Dir(m.path), 0700); err != nil {
+		return err
+	}
+	line, err := json.Marshal(AuditEntry{
+		Time:   time.Now(),
+		Event:  event,
+		User:   revisionAuthor(),
+		Detail: detail,
+	})
+	if err != nil {
+		return err
+	}
+
+	f, err := OpenPrivateFile(AuditPath(m.path), os.O_APPEND|os.O_CREATE|os.O_WRONLY)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// RecordReveal logs that a secret of a connection, its password or key
+// passphrase, was shown on screen. field is the one shown, e.g. "password".
+func (m *Manager) RecordReveal(connName, field string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.auditUnlocked(AuditReveal, connName+"/"+field)
+}
+
+// AuditLog returns the entries of the audit log, oldest first
+func (m *Manager) AuditLog() ([]AuditEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	f, err := os.Open(AuditPath(m.path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", AuditPath(m.path), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
@@ -2,7 +2,12 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
 	"sync"
 	"time"
 
@@ -18,6 +23,18 @@ type Manager struct {
 	path          string
 	cryptoService *crypto.CryptoService
 	unlocked      bool
+	history       model.History        // Revisions of edited connections
+	historyDirty  bool                 // history has changes not yet written
+	attempts      model.UnlockAttempts // Failed unlocks, kept in the state file
+	unlockedAt    time.Time            // Last unlock with the master password, see LockRequested
+
+	// Status updates left to the background writer, see DeferStateWrites
+	stateWake    chan struct{} // Nil while the state is written at once
+	stateErrs    chan error
+	stateDirty   bool   // The state has changes not yet written
+	stateSeq     uint64 // Snapshots of the state taken
+	writeMu      sync.Mutex
+	stateWritten uint64 // Newest snapshot written (guarded by writeMu)
 }
 
 // NewManager creates a new config manager
@@ -26,7 +43,12 @@ func NewManager() (*Manager, error) {
 	if err != nil {
 		return nil, err
 	}
+	return NewManagerAt(path)
+}
 
+// NewManagerAt creates a config manager for the config file at path, which
+// need not exist yet
+func NewManagerAt(path string) (*Manager, error) {
 	m := &Manager{
 		config: model.NewConfig(),
 		path:   path,
@@ -55,6 +77,24 @@ func (m *Manager) Load() error {
 	}
 
 	m.config = cfg
+	if err := m.loadStateUnlocked(); err != nil {
+		return err
+	}
+	if err := m.loadHistoryUnlocked(); err != nil {
+		return err
+	}
+	m.purgeExpiredTrash()
+
+	// Rewrite older config files so they only use current keys, moving
+	// connection state out to the state file. The original is kept next to
+	// it; files from newer versions are left alone.
+	if model.OlderVersion(cfg.Version, model.ConfigVersion) {
+		if err := os.WriteFile(m.path+".bak", data, 0600); err != nil {
+			return fmt.Errorf("failed to back up config before migrating: %w", err)
+		}
+		m.config.Version = model.ConfigVersion
+		return m.saveUnlocked()
+	}
 	return nil
 }
 
@@ -66,6 +106,22 @@ func (m *Manager) Save() error {
 	return m.saveUnlocked()
 }
 
+// Path returns the path of the config file
+func (m *Manager) Path() string {
+	return m.path
+}
+
+// StdioCommand returns the gossh stdio command for ssh's ProxyCommand. The
+// config is named explicitly, as ssh may run the proxy without the
+// environment gossh was run with.
+func (m *Manager) StdioCommand() ([]string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	return []string{exe, "--config", m.path, "stdio"}, nil
+}
+
 // IsFirstRun returns true if the app has not been initialized yet
 func (m *Manager) IsFirstRun() bool {
 	m.mu.RLock()
@@ -93,6 +149,9 @@ func (m *Manager) SetupMasterPassword(password string) error {
 	if m.config.Settings.IsPasswordSet() {
 		return errors.New("master password already set")
 	}
+	if err := m.config.Settings.PasswordPolicy.Check(password); err != nil {
+		return err
+	}
 
 	// Generate salt
 	salt, err := crypto.GenerateSalt()
@@ -112,12 +171,16 @@ func (m *Manager) SetupMasterPassword(password string) error {
 		return err
 	}
 
+	if err := m.setKeyCheckUnlocked(cryptoService); err != nil {
+		return err
+	}
 	m.config.Settings.MasterPasswordHash = hash
 	m.config.Settings.EncryptionSalt = salt
 	m.config.Settings.PasswordProtectionEnabled = true
 	m.config.Settings.Initialized = true
 	m.cryptoService = cryptoService
 	m.unlocked = true
+	m.unlockedAt = time.Now()
 
 	return m.saveUnlocked()
 }
@@ -144,7 +207,7 @@ func (m *Manager) SetupWithoutPassword() error {
 		// Fallback to a less secure but functional approach
 		machineKey = []byte(crypto.GetMachineID())
 	}
-	
+
 	cryptoService, err := crypto.NewCryptoServiceWithKey(machineKey, salt)
 	if err != nil {
 		return err
@@ -174,44 +237,251 @@ func (m *Manager) Unlock(password string) error {
 		return nil
 	}
 
+	// Refuse attempts that come too soon after failed ones, without
+	// counting them as failures
+	if wait := m.attempts.Wait(time.Now()); wait > 0 {
+		_ = m.auditUnlocked(AuditUnlockDelayed, fmt.Sprintf("%d failed attempts, %s left to wait", m.attempts.Failures, wait.Round(time.Second)))
+		return UnlockDelayError{Wait: wait}
+	}
+
+	// Verifying the password and deriving the encryption key are both
+	// Argon2 runs, so derive the key alongside and drop it if the
+	// password turns out wrong
+	type derived struct {
+		service *crypto.CryptoService
+		err     error
+	}
+	key := make(chan derived, 1)
+	go func() {
+		service, err := crypto.NewCryptoService(password, m.config.Settings.EncryptionSalt)
+		key <- derived{service, err}
+	}()
+
 	// Verify password
 	valid, err := crypto.VerifyPassword(password, m.config.Settings.MasterPasswordHash)
+	d := <-key
 	if err != nil {
 		return err
 	}
 	if !valid {
-		return errors.New("invalid password")
+		m.attempts.Failures++
+		m.attempts.LastFailure = time.Now()
+		detail := fmt.Sprintf("failed attempt %d", m.attempts.Failures)
+		if delay := m.attempts.Delay(); delay > 0 {
+			detail += fmt.Sprintf(", next attempt in %s", delay)
+		}
+		_ = m.auditUnlocked(AuditUnlockFailed, detail)
+		if err := m.saveStateUnlocked(); err != nil {
+			return err
+		}
+		return crypto.ErrInvalidPassword
+	}
+	if d.err != nil {
+		return d.err
 	}
+	cryptoService := d.service
 
-	// Create crypto service
-	cryptoService, err := crypto.NewCryptoService(password, m.config.Settings.EncryptionSalt)
-	if err != nil {
-		return err
+	detail := ""
+	if m.attempts.Failures > 0 {
+		detail = fmt.Sprintf("after %d failed attempts", m.attempts.Failures)
+		m.attempts = model.UnlockAttempts{}
+		if err := m.saveStateUnlocked(); err != nil {
+			return err
+		}
 	}
+	_ = m.auditUnlocked(AuditUnlock, detail)
 
 	m.cryptoService = cryptoService
 	m.unlocked = true
+	m.unlockedAt = time.Now()
 
-	// Decrypt all connection passwords
-	for i := range m.config.Connections {
-		conn := &m.config.Connections[i]
-		if conn.EncryptedPassword != "" {
-			decrypted, err := m.cryptoService.Decrypt(conn.EncryptedPassword)
-			if err == nil {
-				conn.Password = decrypted
-			}
+	m.decryptSecrets()
+
+	// Configs protected before the key check existed get one now, so the
+	// master key can be verified without a password
+	if m.config.Settings.KeyCheck == "" {
+		if err := m.setKeyCheckUnlocked(cryptoService); err != nil {
+			return err
+		}
+		return m.saveUnlocked()
+	}
+	return nil
+}
+
+// keyCheckText is encrypted with the master key into Settings.KeyCheck
+const keyCheckText = "gossh master key"
+
+// setKeyCheckUnlocked stores keyCheckText encrypted with service
+func (m *Manager) setKeyCheckUnlocked(service *crypto.CryptoService) error {
+	check, err := service.Encrypt(keyCheckText)
+	if err != nil {
+		return err
+	}
+	m.config.Settings.KeyCheck = check
+	return nil
+}
+
+// ErrInvalidKey is returned by UnlockWithKey for a key that doesn't
+// decrypt the stored secrets
+var ErrInvalidKey = errors.New("key does not match the master password")
+
+// UnlockWithKey unlocks with the key derived from the master password, as
+// MasterKey returns it, e.g. from the key cache. It doesn't count as an
+// unlock attempt.
+func (m *Manager) UnlockWithKey(key []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.unlockWithKeyUnlocked(key, "with the cached master key")
+}
+
+// unlockWithKeyUnlocked unlocks with key, noting where it came from in the
+// audit log (caller must hold lock)
+func (m *Manager) unlockWithKeyUnlocked(key []byte, detail string) error {
+	if !m.config.Settings.PasswordProtectionEnabled {
+		return m.autoUnlock()
+	}
+	if len(key) != 32 {
+		return ErrInvalidKey
+	}
+	cryptoService, err := crypto.NewCryptoServiceWithKey(key, m.config.Settings.EncryptionSalt)
+	if err != nil {
+		return err
+	}
+
+	// A key derived from another password fails to decrypt the key check.
+	// Older configs without one are checked against a stored secret, and a
+	// key that nothing can verify is refused.
+	if check := m.config.Settings.KeyCheck; check != "" {
+		if text, err := cryptoService.Decrypt(check); err != nil || text != keyCheckText {
+			return ErrInvalidKey
 		}
-		if conn.EncryptedKeyPassphrase != "" {
-			decrypted, err := m.cryptoService.Decrypt(conn.EncryptedKeyPassphrase)
-			if err == nil {
-				conn.KeyPassword = decrypted
+	} else {
+		verified := false
+		for _, conn := range m.storedConnections() {
+			secret := conn.EncryptedPassword
+			if secret == "" {
+				secret = conn.EncryptedKeyPassphrase
+			}
+			if secret == "" {
+				continue
+			}
+			if _, err := cryptoService.Decrypt(secret); err != nil {
+				return ErrInvalidKey
 			}
+			verified = true
+			break
+		}
+		if !verified {
+			return ErrInvalidKey
 		}
 	}
+	_ = m.auditUnlocked(AuditUnlock, detail)
+
+	m.cryptoService = cryptoService
+	m.unlocked = true
+	m.unlockedAt = time.Now()
 
+	m.decryptSecrets()
 	return nil
 }
 
+// MasterKey returns the key derived from the master password, once the
+// config is unlocked with it
+func (m *Manager) MasterKey() ([]byte, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.config.Settings.PasswordProtectionEnabled || !m.unlocked || m.cryptoService == nil {
+		return nil, false
+	}
+	return m.cryptoService.Key(), true
+}
+
+// ErrNotPasswordProtected is returned when locking a config that has no
+// master password to unlock it with again
+var ErrNotPasswordProtected = errors.New("no master password is set")
+
+// Lock drops the encryption key and the decrypted passwords and key
+// passphrases, so the master password is needed again
+func (m *Manager) Lock() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.config.Settings.PasswordProtectionEnabled {
+		return ErrNotPasswordProtected
+	}
+	if !m.unlocked {
+		return nil
+	}
+
+	m.cryptoService = nil
+	m.unlocked = false
+	for _, conn := range m.storedConnections() {
+		conn.Password = ""
+		conn.KeyPassword = ""
+	}
+	_ = m.auditUnlocked(AuditLock, "")
+	return nil
+}
+
+// RequestLock asks every running gossh using this config to lock, by
+// touching the file at LockPath. They notice it through LockRequested.
+func (m *Manager) RequestLock() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.config.Settings.PasswordProtectionEnabled {
+		return ErrNotPasswordProtected
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(LockPath(m.path), []byte(time.Now().Format(time.RFC3339)+"\n"), 0600); err != nil {
+		return err
+	}
+	_ = m.auditUnlocked(AuditLock, "requested with gossh lock")
+	return nil
+}
+
+// LockRequested reports whether RequestLock was called, by this or another
+// process, since the master password was last entered here
+func (m *Manager) LockRequested() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.unlocked || m.unlockedAt.IsZero() {
+		return false
+	}
+	info, err := os.Stat(LockPath(m.path))
+	return err == nil && info.ModTime().After(m.unlockedAt)
+}
+
+// ErrUnlockDelayed is returned for unlock attempts made too soon after
+// failed ones
+var ErrUnlockDelayed = errors.New("too many failed unlock attempts")
+
+// UnlockDelayError says how long to wait before the next unlock attempt.
+// It wraps ErrUnlockDelayed.
+type UnlockDelayError struct {
+	Wait time.Duration
+}
+
+func (e UnlockDelayError) Error() string {
+	return fmt.Sprintf("%v, try again in %s", ErrUnlockDelayed, e.Wait.Round(time.Second))
+}
+
+func (e UnlockDelayError) Unwrap() error {
+	return ErrUnlockDelayed
+}
+
+// UnlockAttempts returns how many wrong master passwords were entered since
+// the last unlock, and how long the next attempt must wait
+func (m *Manager) UnlockAttempts() (int, time.Duration) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.attempts.Failures, m.attempts.Wait(time.Now())
+}
+
 // autoUnlock unlocks without password (for password protection disabled mode)
 func (m *Manager) autoUnlock() error {
 	if m.config.Settings.EncryptionSalt == "" {
@@ -225,7 +495,7 @@ func (m *Manager) autoUnlock() error {
 		// Fallback
 		machineKey = []byte(crypto.GetMachineID())
 	}
-	
+
 	cryptoService, err := crypto.NewCryptoServiceWithKey(machineKey, m.config.Settings.EncryptionSalt)
 	if err != nil {
 		return err
@@ -234,24 +504,42 @@ func (m *Manager) autoUnlock() error {
 	m.cryptoService = cryptoService
 	m.unlocked = true
 
-	// Decrypt all connection passwords
-	for i := range m.config.Connections {
-		conn := &m.config.Connections[i]
-		if conn.EncryptedPassword != "" {
-			decrypted, err := m.cryptoService.Decrypt(conn.EncryptedPassword)
-			if err == nil {
-				conn.Password = decrypted
-			}
-		}
-		if conn.EncryptedKeyPassphrase != "" {
-			decrypted, err := m.cryptoService.Decrypt(conn.EncryptedKeyPassphrase)
-			if err == nil {
-				conn.KeyPassword = decrypted
+	m.decryptSecrets()
+	return nil
+}
+
+// decryptSecrets fills in every stored connection's password and key
+// passphrase (caller must hold lock). Secrets are decrypted by a worker per
+// CPU, which matters with hundreds of connections.
+func (m *Manager) decryptSecrets() {
+	conns := m.storedConnections()
+	jobs := make(chan *model.Connection)
+	var wg sync.WaitGroup
+	for range min(runtime.NumCPU(), len(conns)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for conn := range jobs {
+				if conn.EncryptedPassword != "" {
+					decrypted, err := m.cryptoService.Decrypt(conn.EncryptedPassword)
+					if err == nil {
+						conn.Password = decrypted
+					}
+				}
+				if conn.EncryptedKeyPassphrase != "" {
+					decrypted, err := m.cryptoService.Decrypt(conn.EncryptedKeyPassphrase)
+					if err == nil {
+						conn.KeyPassword = decrypted
+					}
+				}
 			}
-		}
+		}()
 	}
-
-	return nil
+	for _, conn := range conns {
+		jobs <- conn
+	}
+	close(jobs)
+	wg.Wait()
 }
 
 // AutoUnlockIfNeeded automatically unlocks if password protection is disabled
@@ -271,7 +559,9 @@ func (m *Manager) Connections() []model.Connection {
 	defer m.mu.RUnlock()
 
 	result := make([]model.Connection, len(m.config.Connections))
-	copy(result, m.config.Connections)
+	for i, c := range m.config.Connections {
+		result[i] = m.withJumps(c)
+	}
 	return result
 }
 
@@ -282,12 +572,21 @@ func (m *Manager) GetConnection(id string) (model.Connection, bool) {
 
 	for _, c := range m.config.Connections {
 		if c.ID == id {
-			return c, true
+			return m.withJumps(c), true
 		}
 	}
 	return model.Connection{}, false
 }
 
+// withJumps fills in the jump host chain of c (caller must hold the lock).
+// Chains that no longer resolve are left empty; connecting reports them.
+func (m *Manager) withJumps(c model.Connection) model.Connection {
+	if len(c.JumpHosts) > 0 {
+		c.Jumps, _ = model.ResolveJumps(c, m.config.Connections)
+	}
+	return c
+}
+
 // AddConnection adds a new connection
 func (m *Manager) AddConnection(conn model.Connection) error {
 	if err := conn.Validate(); err != nil {
@@ -297,8 +596,17 @@ func (m *Manager) AddConnection(conn model.Connection) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if _, err := model.ResolveJumps(conn, m.config.Connections); err != nil {
+		return err
+	}
+	conn.Jumps = nil
+	conn = m.fileUnlocked(conn)
+
 	conn.CreatedAt = time.Now()
 	conn.UpdatedAt = time.Now()
+	if conn.PasswordChangedAt == nil {
+		conn.PasswordChangedAt = &conn.CreatedAt
+	}
 
 	// Encrypt sensitive data if crypto service is available
 	if m.cryptoService != nil {
@@ -332,10 +640,32 @@ func (m *Manager) UpdateConnection(conn model.Connection) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if err := m.updateUnlocked(conn); err != nil {
+		return err
+	}
+	return m.saveUnlocked()
+}
+
+// updateUnlocked replaces the stored connection with conn's ID, keeping the
+// replaced one as a revision (caller must hold lock and save)
+func (m *Manager) updateUnlocked(conn model.Connection) error {
+	if _, err := model.ResolveJumps(conn, m.config.Connections); err != nil {
+		return err
+	}
+	conn.Jumps = nil
+
 	for i, c := range m.config.Connections {
 		if c.ID == conn.ID {
 			conn.CreatedAt = c.CreatedAt
 			conn.UpdatedAt = time.Now()
+			// Changed through AddAttachment and DeleteAttachment only
+			conn.Attachments = c.Attachments
+
+			// Only a new secret restarts the rotation clock
+			conn.PasswordChangedAt = c.PasswordChangedAt
+			if conn.Password != c.Password || conn.KeyPassword != c.KeyPassword || conn.KeyPath != c.KeyPath {
+				conn.PasswordChangedAt = &conn.UpdatedAt
+			}
 
 			// Encrypt sensitive data if crypto service is available
 			if m.cryptoService != nil {
@@ -355,8 +685,9 @@ func (m *Manager) UpdateConnection(conn model.Connection) error {
 				}
 			}
 
+			m.recordRevisionUnlocked(c, conn)
 			m.config.Connections[i] = conn
-			return m.saveUnlocked()
+			return nil
 		}
 	}
 
@@ -373,22 +704,66 @@ func (m *Manager) UpdateConnectionStatus(id string, status model.ConnStatus) err
 			now := time.Now()
 			m.config.Connections[i].LastConnected = &now
 			m.config.Connections[i].LastStatus = status
-			m.config.Connections[i].UpdatedAt = now
-			return m.saveUnlocked()
+			return m.stateChangedUnlocked()
+		}
+	}
+
+	return errors.New("connection not found")
+}
+
+// RecordSession stores a finished session in the connection's history.
+// Any exit status means the connection itself worked, at the address it
+// records.
+func (m *Manager) RecordSession(id string, rec model.SessionRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, c := range m.config.Connections {
+		if c.ID == id {
+			conn := &m.config.Connections[i]
+			now := time.Now()
+			conn.LastConnected = &now
+			conn.LastStatus = model.ConnStatusSuccess
+			if rec.Address != "" {
+				conn.LastAddress = rec.Address
+			}
+			conn.History = append(conn.History, rec)
+			if len(conn.History) > model.MaxHistory {
+				conn.History = conn.History[len(conn.History)-model.MaxHistory:]
+			}
+			return m.stateChangedUnlocked()
+		}
+	}
+
+	return errors.New("connection not found")
+}
+
+// SetFacts stores the system facts gathered from a connection's host
+func (m *Manager) SetFacts(id string, facts model.HostFacts) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, c := range m.config.Connections {
+		if c.ID == id {
+			m.config.Connections[i].Facts = &facts
+			return m.stateChangedUnlocked()
 		}
 	}
 
 	return errors.New("connection not found")
 }
 
-// DeleteConnection removes a connection by ID
+// DeleteConnection moves a connection to the trash by ID
 func (m *Manager) DeleteConnection(id string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	for i, c := range m.config.Connections {
 		if c.ID == id {
+			now := time.Now()
+			c.DeletedAt = &now
 			m.config.Connections = append(m.config.Connections[:i], m.config.Connections[i+1:]...)
+			m.config.Trash = append(m.config.Trash, c)
 			return m.saveUnlocked()
 		}
 	}
@@ -396,6 +771,44 @@ func (m *Manager) DeleteConnection(id string) error {
 	return errors.New("connection not found")
 }
 
+// RenameConnection changes the name of a connection, rejecting names already in use
+func (m *Manager) RenameConnection(id, newName string) error {
+	if newName == "" {
+		return model.ErrNameRequired
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	idx := -1
+	for i, c := range m.config.Connections {
+		if c.ID == id {
+			idx = i
+			continue
+		}
+		if c.HasName(newName) {
+			return errors.New("connection with that name already exists")
+		}
+	}
+	if idx < 0 {
+		return errors.New("connection not found")
+	}
+
+	before := m.config.Connections[idx]
+	oldName := before.Name
+	m.config.Connections[idx].Name = newName
+	// An alias promoted to the name is no longer an alias
+	m.config.Connections[idx].Aliases = slices.DeleteFunc(slices.Clone(m.config.Connections[idx].Aliases), func(a string) bool {
+		return a == newName
+	})
+	m.config.Connections[idx].UpdatedAt = time.Now()
+	m.recordRevisionUnlocked(before, m.config.Connections[idx])
+
+	// Keep jump hosts and workspaces naming this one pointing at it
+	m.renameReferencesUnlocked(oldName, newName)
+	return m.saveUnlocked()
+}
+
 // Groups returns all groups
 func (m *Manager) Groups() []model.Group {
 	m.mu.RLock()
@@ -418,6 +831,23 @@ func (m *Manager) GroupNames() []string {
 	return names
 }
 
+// TagNames returns every tag used by a connection, sorted
+func (m *Manager) TagNames() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var names []string
+	for _, c := range m.config.Connections {
+		for _, tag := range c.Tags {
+			if !slices.Contains(names, tag) {
+				names = append(names, tag)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 // AddGroup adds a new group
 func (m *Manager) AddGroup(group model.Group) error {
 	m.mu.Lock()
@@ -433,6 +863,54 @@ func (m *Manager) AddGroup(group model.Group) error {
 	return m.saveUnlocked()
 }
 
+// SetGroupColor sets the color a group is shown in, a hex color such as
+// #ff6b6b
+func (m *Manager) SetGroupColor(name, color string) error {
+	if !model.ValidColor(color) {
+		return fmt.Errorf("invalid color %q", color)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, g := range m.config.Groups {
+		if g.Name == name {
+			m.config.Groups[i].Color = color
+			return m.saveUnlocked()
+		}
+	}
+	return errors.New("group not found")
+}
+
+// Hooks returns the configured event hooks
+func (m *Manager) Hooks() []model.Hook {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]model.Hook, len(m.config.Hooks))
+	copy(result, m.config.Hooks)
+	return result
+}
+
+// UpdateHealthStatus records a health check result and returns the previous status
+func (m *Manager) UpdateHealthStatus(id string, status model.ConnStatus) (model.ConnStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, c := range m.config.Connections {
+		if c.ID == id {
+			previous := c.HealthStatus
+			if previous == status {
+				return previous, nil
+			}
+			m.config.Connections[i].HealthStatus = status
+			return previous, m.stateChangedUnlocked()
+		}
+	}
+
+	return "", errors.New("connection not found")
+}
+
 // Settings returns the current settings
 func (m *Manager) Settings() model.Settings {
 	m.mu.RLock()
@@ -454,6 +932,8 @@ func (m *Manager) ImportConnections(connections []model.Connection, overwrite bo
 
 	imported := 0
 	for _, conn := range connections {
+		conn = m.importedUnlocked(conn)
+
 		// Check if connection with same name exists
 		found := false
 		for i, c := range m.config.Connections {
@@ -462,22 +942,7 @@ func (m *Manager) ImportConnections(connections []model.Connection, overwrite bo
 				if overwrite {
 					conn.ID = c.ID
 					conn.CreatedAt = c.CreatedAt
-					conn.UpdatedAt = time.Now()
-
-					// Re-encrypt passwords if crypto service available
-					if m.cryptoService != nil && conn.Password != "" {
-						encrypted, err := m.cryptoService.Encrypt(conn.Password)
-						if err == nil {
-							conn.EncryptedPassword = encrypted
-						}
-					}
-					if m.cryptoService != nil && conn.KeyPassword != "" {
-						encrypted, err := m.cryptoService.Encrypt(conn.KeyPassword)
-						if err == nil {
-							conn.EncryptedKeyPassphrase = encrypted
-						}
-					}
-
+					conn.Attachments = c.Attachments
 					m.config.Connections[i] = conn
 					imported++
 				}
@@ -486,24 +951,6 @@ func (m *Manager) ImportConnections(connections []model.Connection, overwrite bo
 		}
 
 		if !found {
-			conn.ID = model.NewConnection().ID
-			conn.CreatedAt = time.Now()
-			conn.UpdatedAt = time.Now()
-
-			// Encrypt passwords if crypto service available
-			if m.cryptoService != nil && conn.Password != "" {
-				encrypted, err := m.cryptoService.Encrypt(conn.Password)
-				if err == nil {
-					conn.EncryptedPassword = encrypted
-				}
-			}
-			if m.cryptoService != nil && conn.KeyPassword != "" {
-				encrypted, err := m.cryptoService.Encrypt(conn.KeyPassword)
-				if err == nil {
-					conn.EncryptedKeyPassphrase = encrypted
-				}
-			}
-
 			m.config.Connections = append(m.config.Connections, conn)
 			imported++
 		}
@@ -518,13 +965,51 @@ func (m *Manager) ImportConnections(connections []model.Connection, overwrite bo
 	return imported, nil
 }
 
+// importedUnlocked prepares an imported connection for storing: it is
+// filed by the grouping rules and gets a new ID and encrypted secrets
+// (caller must hold lock)
+func (m *Manager) importedUnlocked(conn model.Connection) model.Connection {
+	conn = m.fileUnlocked(conn)
+	conn.ID = model.NewConnection().ID
+	conn.CreatedAt = time.Now()
+	conn.UpdatedAt = time.Now()
+	// Attachments are files of the config they were added to
+	conn.Attachments = nil
+	conn.EncryptedPassword = m.encryptUnlocked(conn.Password)
+	conn.EncryptedKeyPassphrase = m.encryptUnlocked(conn.KeyPassword)
+	return conn
+}
+
+// encryptUnlocked encrypts a secret for storing, returning "" for an empty
+// secret or when there is no crypto service (caller must hold lock)
+func (m *Manager) encryptUnlocked(secret string) string {
+	if m.cryptoService == nil || secret == "" {
+		return ""
+	}
+	encrypted, err := m.cryptoService.Encrypt(secret)
+	if err != nil {
+		return ""
+	}
+	return encrypted
+}
+
 // saveUnlocked saves without acquiring lock (caller must hold lock)
 func (m *Manager) saveUnlocked() error {
-	if err := EnsureConfigDir(); err != nil {
+	// The file may live outside the config directory, see SetConfigPath
+	if err := os.MkdirAll(filepath.Dir(m.path), 0700); err != nil {
+		return err
+	}
+
+	// The state goes first, so it is never only in memory once the
+	// config no longer holds it
+	if err := m.saveStateUnlocked(); err != nil {
+		return err
+	}
+	if err := m.saveHistoryUnlocked(); err != nil {
 		return err
 	}
 
-	// Create a copy for saving (without plain text passwords)
+	// Create a copy for saving (without plain text passwords or state)
 	saveCfg := m.config
 	saveCfg.Connections = make([]model.Connection, len(m.config.Connections))
 	for i, conn := range m.config.Connections {
@@ -532,6 +1017,14 @@ func (m *Manager) saveUnlocked() error {
 		// Clear plain text passwords from saved config
 		saveCfg.Connections[i].Password = ""
 		saveCfg.Connections[i].KeyPassword = ""
+		saveCfg.Connections[i].SetState(model.ConnectionState{})
+	}
+	saveCfg.Trash = make([]model.Connection, len(m.config.Trash))
+	for i, conn := range m.config.Trash {
+		saveCfg.Trash[i] = conn
+		saveCfg.Trash[i].Password = ""
+		saveCfg.Trash[i].KeyPassword = ""
+		saveCfg.Trash[i].SetState(model.ConnectionState{})
 	}
 
 	data, err := yaml.Marshal(&saveCfg)
@@ -539,7 +1032,71 @@ func (m *Manager) saveUnlocked() error {
 		return err
 	}
 
-	return os.WriteFile(m.path, data, 0600)
+	if err := WritePrivateFile(m.path, data); err != nil {
+		return err
+	}
+	m.sweepAttachmentsUnlocked()
+	return nil
+}
+
+// saveStateUnlocked writes only the state file, which is much smaller than
+// the config with many connections (caller must hold lock)
+func (m *Manager) saveStateUnlocked() error {
+	data, seq, err := m.snapshotStateUnlocked()
+	if err != nil {
+		return err
+	}
+	if err := m.writeState(data, seq); err != nil {
+		// Left for the background writer or FlushState to retry
+		m.stateDirty = true
+		return err
+	}
+	return nil
+}
+
+// snapshotStateUnlocked returns the state file as it should be now, and
+// its place among the snapshots taken (caller must hold lock)
+func (m *Manager) snapshotStateUnlocked() ([]byte, uint64, error) {
+	state := model.State{Connections: make(map[string]model.ConnectionState), Unlock: m.attempts}
+	for _, conn := range m.storedConnections() {
+		if s := conn.State(); !s.IsZero() {
+			state.Connections[conn.ID] = s
+		}
+	}
+
+	data, err := yaml.Marshal(&state)
+	if err != nil {
+		return nil, 0, err
+	}
+	m.stateDirty = false
+	m.stateSeq++
+	return data, m.stateSeq, nil
+}
+
+// loadStateUnlocked applies the state file to the loaded connections
+// (caller must hold lock). Connections it has no entry for keep the state
+// an older config file carried inline.
+func (m *Manager) loadStateUnlocked() error {
+	data, err := os.ReadFile(StatePath(m.path))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var state model.State
+	if err := yaml.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to read %s: %w", StatePath(m.path), err)
+	}
+	m.attempts = state.Unlock
+
+	for _, conn := range m.storedConnections() {
+		if s, ok := state.Connections[conn.ID]; ok {
+			conn.SetState(s)
+		}
+		if conn.LastStatus == "" {
+			conn.LastStatus = model.ConnStatusUnknown
+		}
+	}
+	return nil
 }
 
 // IsPasswordProtected returns true if password protection is enabled
@@ -553,6 +1110,31 @@ func (m *Manager) IsPasswordProtected() bool {
 func (m *Manager) EnablePassword(password string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	return m.setPasswordUnlocked(password)
+}
+
+// ChangePassword replaces the master password, after checking the current
+// one
+func (m *Manager) ChangePassword(currentPassword, password string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	valid, err := crypto.VerifyPassword(currentPassword, m.config.Settings.MasterPasswordHash)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return crypto.ErrInvalidPassword
+	}
+	return m.setPasswordUnlocked(password)
+}
+
+// setPasswordUnlocked protects the config with password, re-encrypting
+// the stored secrets. The password must meet the password policy.
+func (m *Manager) setPasswordUnlocked(password string) error {
+	if err := m.config.Settings.PasswordPolicy.Check(password); err != nil {
+		return err
+	}
 
 	// Generate new salt
 	salt, err := crypto.GenerateSalt()
@@ -571,10 +1153,15 @@ func (m *Manager) EnablePassword(password string) error {
 	if err != nil {
 		return err
 	}
+	if err := m.rekeyAttachmentsUnlocked(cryptoService); err != nil {
+		return err
+	}
+	if err := m.rekeyHistoryUnlocked(cryptoService); err != nil {
+		return err
+	}
 
 	// Re-encrypt all connection passwords with new key
-	for i := range m.config.Connections {
-		conn := &m.config.Connections[i]
+	for _, conn := range m.storedConnections() {
 		if conn.Password != "" {
 			encrypted, err := cryptoService.Encrypt(conn.Password)
 			if err != nil {
@@ -591,10 +1178,18 @@ func (m *Manager) EnablePassword(password string) error {
 		}
 	}
 
+	if err := m.setKeyCheckUnlocked(cryptoService); err != nil {
+		return err
+	}
 	m.config.Settings.MasterPasswordHash = hash
 	m.config.Settings.EncryptionSalt = salt
 	m.config.Settings.PasswordProtectionEnabled = true
 	m.cryptoService = cryptoService
+	m.unlockedAt = time.Now()
+	if m.config.Settings.RememberKey {
+		// The remembered key is for the old password
+		_ = m.forgetKeyUnlocked()
+	}
 
 	return m.saveUnlocked()
 }
@@ -611,7 +1206,7 @@ func (m *Manager) DisablePassword(currentPassword string) error {
 			return err
 		}
 		if !valid {
-			return errors.New("invalid password")
+			return crypto.ErrInvalidPassword
 		}
 	}
 
@@ -631,10 +1226,15 @@ func (m *Manager) DisablePassword(currentPassword string) error {
 	if err != nil {
 		return err
 	}
+	if err := m.rekeyAttachmentsUnlocked(cryptoService); err != nil {
+		return err
+	}
+	if err := m.rekeyHistoryUnlocked(cryptoService); err != nil {
+		return err
+	}
 
 	// Re-encrypt all connection passwords with machine key
-	for i := range m.config.Connections {
-		conn := &m.config.Connections[i]
+	for _, conn := range m.storedConnections() {
 		if conn.Password != "" {
 			encrypted, err := cryptoService.Encrypt(conn.Password)
 			if err != nil {
@@ -652,15 +1252,23 @@ func (m *Manager) DisablePassword(currentPassword string) error {
 	}
 
 	m.config.Settings.MasterPasswordHash = ""
+	m.config.Settings.KeyCheck = ""
 	m.config.Settings.EncryptionSalt = salt
 	m.config.Settings.PasswordProtectionEnabled = false
 	m.cryptoService = cryptoService
+	if m.config.Settings.RememberKey {
+		_ = m.forgetKeyUnlocked()
+	}
 
 	return m.saveUnlocked()
 }
 
-// GetLanguage returns the configured language
+// GetLanguage returns the configured language, GOSSH_LANG when it is set
 func (m *Manager) GetLanguage() string {
+	if lang := getenv(EnvLang); lang != "" {
+		return lang
+	}
+
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	if m.config.Settings.Language == "" {
@@ -677,9 +1285,255 @@ func (m *Manager) SetLanguage(lang string) error {
 	return m.saveUnlocked()
 }
 
-// GetSettings returns a copy of current settings
+// SetNotify sets how long-running tasks notify on completion
+func (m *Manager) SetNotify(mode model.NotifyMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Settings.Notify = mode
+	return m.saveUnlocked()
+}
+
+// SetKeepWarm sets how many minutes connections stay open after their last
+// use, zero to close them at once
+func (m *Manager) SetKeepWarm(minutes int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Settings.KeepWarmMinutes = minutes
+	return m.saveUnlocked()
+}
+
+// SetScrollback sets how many KB of each session's output are kept to look
+// through after it ends, zero to keep none
+func (m *Manager) SetScrollback(kb int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Settings.ScrollbackKB = kb
+	return m.saveUnlocked()
+}
+
+// SetTheme sets the color theme, one of model.Themes
+func (m *Manager) SetTheme(theme string) error {
+	if !slices.Contains(model.Themes, theme) {
+		return model.ErrInvalidTheme
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Settings.Theme = theme
+	return m.saveUnlocked()
+}
+
+// SetConnectionTimeout sets how many seconds connecting to a host may take
+func (m *Manager) SetConnectionTimeout(seconds int) error {
+	if seconds <= 0 {
+		return model.ErrInvalidTimeout
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Settings.ConnectionTimeout = seconds
+	return m.saveUnlocked()
+}
+
+// SetDefaultPort sets the port filled in for new SSH connections
+func (m *Manager) SetDefaultPort(port int) error {
+	if port <= 0 || port > 65535 {
+		return model.ErrInvalidPort
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Settings.DefaultPort = port
+	return m.saveUnlocked()
+}
+
+// SetDefaultUser sets the user filled in for new connections, empty for
+// none
+func (m *Manager) SetDefaultUser(user string) error {
+	if user != "" && !model.ValidEnvValue(user) {
+		return model.ErrInvalidDefaultUser
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Settings.DefaultUser = user
+	return m.saveUnlocked()
+}
+
+// SetKeepalive sets how many seconds apart sessions send keepalives, zero
+// for the built-in interval
+func (m *Manager) SetKeepalive(seconds int) error {
+	if seconds < 0 {
+		return model.ErrInvalidInterval
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Settings.KeepaliveSeconds = seconds
+	return m.saveUnlocked()
+}
+
+// SetConfirmDelete sets whether deleting a connection asks first
+func (m *Manager) SetConfirmDelete(confirm bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Settings.NoConfirmDelete = !confirm
+	return m.saveUnlocked()
+}
+
+// SetHealthCheck sets how many minutes apart all connections are checked
+// in the background, zero to only check them on request
+func (m *Manager) SetHealthCheck(minutes int) error {
+	if minutes < 0 {
+		return model.ErrInvalidInterval
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Settings.HealthCheckMinutes = minutes
+	return m.saveUnlocked()
+}
+
+// SetKeyCache sets how many minutes CLI commands reuse the master key
+// after its last use, 0 to ask for the master password every time
+func (m *Manager) SetKeyCache(minutes int) error {
+	if minutes < 0 {
+		return model.ErrInvalidInterval
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Settings.KeyCacheMinutes = minutes
+	return m.saveUnlocked()
+}
+
+// SetTerminal sets the TERM and locale for sessions whose connection sets
+// none, empty to use the local ones
+func (m *Manager) SetTerminal(term, locale string) error {
+	if term != "" && !model.ValidEnvValue(term) {
+		return model.ErrInvalidTerm
+	}
+	if locale != "" && !model.ValidEnvValue(locale) {
+		return model.ErrInvalidLocale
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Settings.Term = term
+	m.config.Settings.Locale = locale
+	return m.saveUnlocked()
+}
+
+// SaveFilter saves a search query under a name, replacing the query saved
+// under it before
+func (m *Manager) SaveFilter(filter model.SavedFilter) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, f := range m.config.Settings.SavedFilters {
+		if f.Name == filter.Name {
+			m.config.Settings.SavedFilters[i] = filter
+			return m.saveUnlocked()
+		}
+	}
+	m.config.Settings.SavedFilters = append(m.config.Settings.SavedFilters, filter)
+	return m.saveUnlocked()
+}
+
+// DeleteFilter removes the saved filter with the given name
+func (m *Manager) DeleteFilter(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Settings.SavedFilters = slices.DeleteFunc(slices.Clone(m.config.Settings.SavedFilters), func(f model.SavedFilter) bool {
+		return f.Name == name
+	})
+	return m.saveUnlocked()
+}
+
+// SetGroupRules replaces the rules that file added and imported
+// connections into groups
+func (m *Manager) SetGroupRules(rules []model.GroupRule) error {
+	for _, r := range rules {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Settings.GroupRules = rules
+	return m.saveUnlocked()
+}
+
+// SetDefaultGroup sets the group for new connections no rule files, empty
+// to leave them ungrouped
+func (m *Manager) SetDefaultGroup(group string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Settings.DefaultGroup = group
+	return m.saveUnlocked()
+}
+
+// PreviewGroupRules returns what the grouping rules would change on the
+// existing connections
+func (m *Manager) PreviewGroupRules() []model.GroupChange {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s := m.config.Settings
+	return model.PreviewGroupRules(m.config.Connections, s.GroupRules, s.DefaultGroup)
+}
+
+// ApplyGroupRules files the existing connections by the grouping rules, as
+// if they were added now, and returns how many changed
+func (m *Manager) ApplyGroupRules() (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	changed := 0
+	for i, c := range m.config.Connections {
+		filed := m.fileUnlocked(c)
+		if filed.Group == c.Group && len(filed.Tags) == len(c.Tags) {
+			continue
+		}
+		filed.UpdatedAt = time.Now()
+		m.recordRevisionUnlocked(c, filed)
+		m.config.Connections[i] = filed
+		changed++
+	}
+	if changed == 0 {
+		return 0, nil
+	}
+	return changed, m.saveUnlocked()
+}
+
+// fileUnlocked applies the grouping rules to a connection (caller must
+// hold lock)
+func (m *Manager) fileUnlocked(conn model.Connection) model.Connection {
+	s := m.config.Settings
+	return model.ApplyGroupRules(conn, s.GroupRules, s.DefaultGroup)
+}
+
+// SetListLayout sets how the connection list is drawn
+func (m *Manager) SetListLayout(layout model.ListLayout) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Settings.ListLayout = layout
+	return m.saveUnlocked()
+}
+
+// SetListColumns sets the columns the table layout shows
+func (m *Manager) SetListColumns(columns []model.ListColumn) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Settings.ListColumns = columns
+	return m.saveUnlocked()
+}
+
+// SetListSort sets the column the table layout is sorted by, or none to
+// keep the config order
+func (m *Manager) SetListSort(column model.ListColumn, desc bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Settings.ListSort = column
+	m.config.Settings.ListSortDesc = desc
+	return m.saveUnlocked()
+}
+
+// GetSettings returns a copy of current settings, with the environment
+// overrides applied
 func (m *Manager) GetSettings() model.Settings {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	return m.config.Settings
+	return applyEnv(m.config.Settings)
 }
@@ -1,10 +1,20 @@
 package config
 
 import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"gossh/internal/crypto"
 	"gossh/internal/model"
 )
 
@@ -50,6 +60,160 @@ func TestGetKnownHostsPath(t *testing.T) {
 	}
 }
 
+func TestContractHome(t *testing.T) {
+	t.Setenv("HOME", "/home/alice")
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/home/alice", "~"},
+		{"/home/alice/.config/gossh/config.yaml", "~/.config/gossh/config.yaml"},
+		{"/home/alicia/config.yaml", "/home/alicia/config.yaml"},
+		{"/etc/gossh.yaml", "/etc/gossh.yaml"},
+	}
+	for _, tt := range tests {
+		if got := ContractHome(tt.path); got != tt.want {
+			t.Errorf("ContractHome(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+		if got := ExpandHome(ContractHome(tt.path)); got != tt.path {
+			t.Errorf("ExpandHome(ContractHome(%q)) = %q", tt.path, got)
+		}
+	}
+}
+
+func TestExpandHomeWindows(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	tests := []struct {
+		path    string
+		windows bool
+		want    string
+	}{
+		{"~/.ssh/id_ed25519", false, filepath.Join(home, ".ssh", "id_ed25519")},
+		{"%USERPROFILE%/.ssh/id_ed25519", false, "%USERPROFILE%/.ssh/id_ed25519"},
+		{"%USERPROFILE%/.ssh/id_ed25519", true, home + "/.ssh/id_ed25519"},
+		{"%GOSSH_UNSET_VAR%/id", true, "%GOSSH_UNSET_VAR%/id"},
+		{`~\.ssh\id_ed25519`, false, `~\.ssh\id_ed25519`},
+	}
+	for _, tt := range tests {
+		if got := expandHome(tt.path, tt.windows); got != tt.want {
+			t.Errorf("expandHome(%q, %v) = %q, want %q", tt.path, tt.windows, got, tt.want)
+		}
+	}
+
+	if got := expandHome(`~\.ssh\id_ed25519`, true); !strings.HasPrefix(got, home) {
+		t.Errorf("expandHome(~\\...) on Windows = %q, want it under %q", got, home)
+	}
+}
+
+func TestConfigDirEnv(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvConfigDir, dir)
+
+	got, err := ConfigDir()
+	if err != nil {
+		t.Fatalf("ConfigDir returned error: %v", err)
+	}
+	if got != dir {
+		t.Errorf("ConfigDir() = %q, want %q from %s", got, dir, EnvConfigDir)
+	}
+	if path := GetKnownHostsPath(); path != filepath.Join(dir, "known_hosts") {
+		t.Errorf("GetKnownHostsPath() = %q, want it under %s", path, EnvConfigDir)
+	}
+	if path, _ := PluginsDir(); path != filepath.Join(dir, "plugins") {
+		t.Errorf("PluginsDir() = %q, want it under %s", path, EnvConfigDir)
+	}
+}
+
+func TestSetConfigPath(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), "vault", "team.yaml")
+	SetConfigPath(path)
+	defer SetConfigPath("")
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if cfg.Path() != path {
+		t.Errorf("Path() = %q, want %q", cfg.Path(), path)
+	}
+	// Saving creates the file's directory
+	if err := cfg.SetupWithoutPassword(); err != nil {
+		t.Fatalf("SetupWithoutPassword() error = %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("config file not written to %s: %v", path, err)
+	}
+}
+
+func TestManagerEnvOverrides(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	cfg.SetupWithoutPassword()
+	cfg.SetLanguage("de")
+
+	t.Setenv(EnvLang, "zh")
+	t.Setenv(EnvTheme, "light")
+	t.Setenv(EnvTimeout, "45")
+
+	if got := cfg.GetLanguage(); got != "zh" {
+		t.Errorf("GetLanguage() = %q, want zh from %s", got, EnvLang)
+	}
+	settings := cfg.GetSettings()
+	if settings.Language != "zh" || settings.Theme != "light" || settings.ConnectTimeout() != 45*time.Second {
+		t.Errorf("GetSettings() = %q, %q, %v; want zh, light, 45s", settings.Language, settings.Theme, settings.ConnectTimeout())
+	}
+
+	// Overrides are never written to the file
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	t.Setenv(EnvLang, "")
+	t.Setenv(EnvTheme, "")
+	t.Setenv(EnvTimeout, "")
+	cfg, err = NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	settings = cfg.GetSettings()
+	if settings.Language != "de" || settings.Theme != "dark" || settings.ConnectTimeout() != 10*time.Second {
+		t.Errorf("saved settings = %q, %q, %v; want de, dark, 10s", settings.Language, settings.Theme, settings.ConnectTimeout())
+	}
+}
+
+func TestCheckEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		theme   string
+		timeout string
+		wantErr bool
+	}{
+		{"unset", "", "", false},
+		{"valid", "light", "30", false},
+		{"unknown theme", "solarized", "", true},
+		{"timeout not a number", "", "30s", true},
+		{"timeout zero", "", "0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvTheme, tt.theme)
+			t.Setenv(EnvTimeout, tt.timeout)
+			if err := CheckEnv(); (err != nil) != tt.wantErr {
+				t.Errorf("CheckEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestNewManagerCreatesDir(t *testing.T) {
 	// Create a temp home directory
 	tmpDir, err := os.MkdirTemp("", "gossh-config-test-*")
@@ -110,7 +274,7 @@ func TestManagerConnections(t *testing.T) {
 	conn.Host = "192.168.1.1"
 	conn.User = "root"
 	conn.Port = 22
-	conn.AuthMethod = model.AuthPassword
+	conn.AuthType = model.AuthPassword
 	conn.Password = "test"
 
 	if err := cfg.AddConnection(conn); err != nil {
@@ -153,7 +317,7 @@ func TestManagerGroupNames(t *testing.T) {
 	conn1.Host = "192.168.1.1"
 	conn1.User = "root"
 	conn1.Port = 22
-	conn1.AuthMethod = model.AuthPassword
+	conn1.AuthType = model.AuthPassword
 	conn1.Password = "test"
 	conn1.Group = "Production"
 	cfg.AddConnection(conn1)
@@ -163,7 +327,7 @@ func TestManagerGroupNames(t *testing.T) {
 	conn2.Host = "192.168.1.2"
 	conn2.User = "root"
 	conn2.Port = 22
-	conn2.AuthMethod = model.AuthPassword
+	conn2.AuthType = model.AuthPassword
 	conn2.Password = "test"
 	conn2.Group = "Development"
 	cfg.AddConnection(conn2)
@@ -173,7 +337,7 @@ func TestManagerGroupNames(t *testing.T) {
 	conn3.Host = "192.168.1.3"
 	conn3.User = "root"
 	conn3.Port = 22
-	conn3.AuthMethod = model.AuthPassword
+	conn3.AuthType = model.AuthPassword
 	conn3.Password = "test"
 	conn3.Group = "Production"
 	cfg.AddConnection(conn3)
@@ -198,6 +362,151 @@ func TestManagerGroupNames(t *testing.T) {
 	}
 }
 
+func TestManagerSetGroupColor(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	cfg.SetupWithoutPassword()
+
+	if err := cfg.SetGroupColor("Production", "#0072B2"); err != nil {
+		t.Fatalf("SetGroupColor() error = %v", err)
+	}
+	for _, g := range cfg.Groups() {
+		if g.Name == "Production" && g.Color != "#0072B2" {
+			t.Errorf("Production color = %q, want #0072B2", g.Color)
+		}
+	}
+
+	if err := cfg.SetGroupColor("Production", "blue"); err == nil {
+		t.Error("SetGroupColor() should reject a color that is not hex")
+	}
+	if err := cfg.SetGroupColor("Missing", "#0072B2"); err == nil {
+		t.Error("SetGroupColor() should fail for an unknown group")
+	}
+}
+
+func TestManagerListSettings(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	cfg.SetupWithoutPassword()
+
+	columns := []model.ListColumn{model.ColumnName, model.ColumnStatus}
+	if err := cfg.SetListLayout(model.LayoutTable); err != nil {
+		t.Fatalf("SetListLayout() error = %v", err)
+	}
+	if err := cfg.SetListColumns(columns); err != nil {
+		t.Fatalf("SetListColumns() error = %v", err)
+	}
+	if err := cfg.SetListSort(model.ColumnLastSeen, true); err != nil {
+		t.Fatalf("SetListSort() error = %v", err)
+	}
+
+	// Reload to check the settings are persisted
+	cfg, err = NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	settings := cfg.GetSettings()
+	if settings.Layout() != model.LayoutTable {
+		t.Errorf("Layout() = %q, want table", settings.Layout())
+	}
+	if got := settings.TableColumns(); !slices.Equal(got, columns) {
+		t.Errorf("TableColumns() = %v, want %v", got, columns)
+	}
+	if settings.ListSort != model.ColumnLastSeen || !settings.ListSortDesc {
+		t.Errorf("ListSort, ListSortDesc = %q, %v; want last_seen, true", settings.ListSort, settings.ListSortDesc)
+	}
+}
+
+func TestManagerSessionSettings(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	cfg.SetupWithoutPassword()
+
+	if err := cfg.SetDefaultPort(70000); err != model.ErrInvalidPort {
+		t.Errorf("SetDefaultPort(70000) error = %v, want %v", err, model.ErrInvalidPort)
+	}
+	if err := cfg.SetTheme("blue"); err != model.ErrInvalidTheme {
+		t.Errorf("SetTheme(blue) error = %v, want %v", err, model.ErrInvalidTheme)
+	}
+	if err := cfg.SetTerminal("xterm 256", ""); err != model.ErrInvalidTerm {
+		t.Errorf("SetTerminal(xterm 256) error = %v, want %v", err, model.ErrInvalidTerm)
+	}
+
+	for name, set := range map[string]func() error{
+		"SetConnectionTimeout": func() error { return cfg.SetConnectionTimeout(30) },
+		"SetDefaultPort":       func() error { return cfg.SetDefaultPort(2222) },
+		"SetDefaultUser":       func() error { return cfg.SetDefaultUser("deploy") },
+		"SetKeepalive":         func() error { return cfg.SetKeepalive(45) },
+		"SetTheme":             func() error { return cfg.SetTheme("light") },
+		"SetConfirmDelete":     func() error { return cfg.SetConfirmDelete(false) },
+		"SetHealthCheck":       func() error { return cfg.SetHealthCheck(15) },
+		"SetTerminal":          func() error { return cfg.SetTerminal("vt100", "C.UTF-8") },
+	} {
+		if err := set(); err != nil {
+			t.Fatalf("%s() error = %v", name, err)
+		}
+	}
+
+	// Reload to check the settings are persisted
+	cfg, err = NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	settings := cfg.GetSettings()
+	if settings.ConnectTimeout() != 30*time.Second || settings.Keepalive() != 45*time.Second || settings.HealthCheck() != 15*time.Minute {
+		t.Errorf("ConnectTimeout, Keepalive, HealthCheck = %v, %v, %v; want 30s, 45s, 15m", settings.ConnectTimeout(), settings.Keepalive(), settings.HealthCheck())
+	}
+	if settings.NewConnectionPort(model.ConnTypeSSH) != 2222 || settings.NewConnectionPort(model.ConnTypeTelnet) != 23 {
+		t.Errorf("NewConnectionPort = %d, %d; want 2222 for SSH, 23 for telnet", settings.NewConnectionPort(model.ConnTypeSSH), settings.NewConnectionPort(model.ConnTypeTelnet))
+	}
+	if settings.DefaultUser != "deploy" || settings.Theme != "light" || settings.ConfirmDelete() {
+		t.Errorf("DefaultUser, Theme, ConfirmDelete = %q, %q, %v; want deploy, light, false", settings.DefaultUser, settings.Theme, settings.ConfirmDelete())
+	}
+	if settings.Term != "vt100" || settings.Locale != "C.UTF-8" {
+		t.Errorf("Term, Locale = %q, %q; want vt100, C.UTF-8", settings.Term, settings.Locale)
+	}
+}
+
+func TestManagerTagNames(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", oldHome)
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	cfg.SetupWithoutPassword()
+
+	for i, tags := range [][]string{{"web", "prod"}, {"db", "prod"}, nil} {
+		conn := model.NewConnection()
+		conn.Name = fmt.Sprintf("server%d", i)
+		conn.Host = "192.168.1.1"
+		conn.User = "root"
+		conn.Tags = tags
+		cfg.AddConnection(conn)
+	}
+
+	got := cfg.TagNames()
+	want := []string{"db", "prod", "web"}
+	if !slices.Equal(got, want) {
+		t.Errorf("TagNames() = %v, want %v", got, want)
+	}
+}
+
 func TestManagerDeleteConnection(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "gossh-config-test-*")
 	if err != nil {
@@ -222,7 +531,7 @@ func TestManagerDeleteConnection(t *testing.T) {
 	conn.Host = "192.168.1.1"
 	conn.User = "root"
 	conn.Port = 22
-	conn.AuthMethod = model.AuthPassword
+	conn.AuthType = model.AuthPassword
 	conn.Password = "test"
 	cfg.AddConnection(conn)
 
@@ -269,7 +578,7 @@ func TestManagerGetConnection(t *testing.T) {
 	conn1.Host = "192.168.1.1"
 	conn1.User = "root"
 	conn1.Port = 22
-	conn1.AuthMethod = model.AuthPassword
+	conn1.AuthType = model.AuthPassword
 	conn1.Password = "test"
 	cfg.AddConnection(conn1)
 
@@ -296,6 +605,1770 @@ func TestManagerGetConnection(t *testing.T) {
 	}
 }
 
+func TestManagerRenameConnection(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gossh-config-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", oldHome)
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	cfg.SetupWithoutPassword()
+
+	for _, name := range []string{"web-01", "web-02"} {
+		conn := model.NewConnection()
+		conn.Name = name
+		conn.Host = "192.168.1.1"
+		conn.User = "root"
+		conn.Port = 22
+		conn.AuthType = model.AuthPassword
+		cfg.AddConnection(conn)
+	}
+
+	conns := cfg.Connections()
+	id := conns[0].ID
+
+	// Rename to a free name
+	if err := cfg.RenameConnection(id, "web-primary"); err != nil {
+		t.Fatalf("Failed to rename connection: %v", err)
+	}
+	found, _ := cfg.GetConnection(id)
+	if found.Name != "web-primary" {
+		t.Errorf("Expected name 'web-primary', got '%s'", found.Name)
+	}
+
+	// Renaming onto an existing name should fail
+	if err := cfg.RenameConnection(id, "web-02"); err == nil {
+		t.Error("Expected error when renaming to an existing name")
+	}
+
+	// Empty name should fail
+	if err := cfg.RenameConnection(id, ""); err == nil {
+		t.Error("Expected error for empty name")
+	}
+
+	// Unknown ID should fail
+	if err := cfg.RenameConnection("non-existent-id", "other"); err == nil {
+		t.Error("Expected error for unknown connection")
+	}
+}
+
+func TestManagerJumpHosts(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gossh-config-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", oldHome)
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	cfg.SetupWithoutPassword()
+
+	newConn := func(name string, jumps ...string) model.Connection {
+		conn := model.NewConnection()
+		conn.Name = name
+		conn.Host = "10.0.0.1"
+		conn.User = "root"
+		conn.Port = 22
+		conn.AuthType = model.AuthPassword
+		conn.JumpHosts = jumps
+		return conn
+	}
+
+	if err := cfg.AddConnection(newConn("db", "bastion")); !errors.Is(err, model.ErrJumpNotFound) {
+		t.Fatalf("Expected ErrJumpNotFound for an unknown jump host, got %v", err)
+	}
+
+	bastion := newConn("bastion")
+	cfg.AddConnection(bastion)
+	db := newConn("db", "bastion")
+	if err := cfg.AddConnection(db); err != nil {
+		t.Fatalf("Failed to add connection: %v", err)
+	}
+
+	found, _ := cfg.GetConnection(db.ID)
+	if len(found.Jumps) != 1 || found.Jumps[0].ID != bastion.ID {
+		t.Errorf("Expected the bastion as resolved jump host, got %+v", found.Jumps)
+	}
+
+	// A bastion hopping through the connection behind it would loop
+	bastion.JumpHosts = []string{"db"}
+	if err := cfg.UpdateConnection(bastion); !errors.Is(err, model.ErrJumpLoop) {
+		t.Errorf("Expected ErrJumpLoop, got %v", err)
+	}
+
+	// Renaming the bastion keeps the reference
+	if err := cfg.RenameConnection(bastion.ID, "gateway"); err != nil {
+		t.Fatalf("Failed to rename connection: %v", err)
+	}
+	found, _ = cfg.GetConnection(db.ID)
+	if len(found.JumpHosts) != 1 || found.JumpHosts[0] != "gateway" || len(found.Jumps) != 1 {
+		t.Errorf("Expected jump host 'gateway' after rename, got %v", found.JumpHosts)
+	}
+}
+
+func TestManagerExportImport(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gossh-config-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", oldHome)
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	cfg.SetupWithoutPassword()
+
+	for _, name := range []string{"web-01", "db-01"} {
+		conn := model.NewConnection()
+		conn.Name = name
+		conn.Host = "192.168.1.1"
+		conn.User = "root"
+		conn.Port = 22
+		conn.AuthType = model.AuthPassword
+		cfg.AddConnection(conn)
+	}
+
+	for _, format := range TransferFormats {
+		path := filepath.Join(tmpDir, "export-"+string(format))
+		result, err := cfg.Export(path, format, "test", false)
+		if err != nil {
+			t.Fatalf("Export(%s) failed: %v", format, err)
+		}
+		if result.Exported != 2 {
+			t.Errorf("Export(%s) exported %d, want 2", format, result.Exported)
+		}
+
+		// Re-importing the same names without overwrite skips everything
+		result, err = cfg.Import(path, format, false)
+		if err != nil {
+			t.Fatalf("Import(%s) failed: %v", format, err)
+		}
+		if result.Imported != 0 || result.Skipped != 2 {
+			t.Errorf("Import(%s) = %d imported, %d skipped, want 0 and 2", format, result.Imported, result.Skipped)
+		}
+	}
+
+	if _, err := cfg.Import(filepath.Join(tmpDir, "missing.yaml"), FormatYAML, false); err == nil {
+		t.Error("Expected error for missing import file")
+	}
+}
+
+func TestExportPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes don't apply on Windows")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	cfg.SetupWithoutPassword()
+	conn := model.NewConnection()
+	conn.Name = "web"
+	conn.Host = "web.example.com"
+	conn.User = "root"
+	conn.Port = 22
+	conn.AuthType = model.AuthPassword
+	conn.Password = "secret"
+	if err := cfg.AddConnection(conn); err != nil {
+		t.Fatalf("AddConnection failed: %v", err)
+	}
+
+	shared := t.TempDir()
+	if err := os.Chmod(shared, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(shared, "export.yaml")
+	if _, err := cfg.Export(path, FormatYAML, "test", false); !errors.Is(err, ErrInsecurePermissions) {
+		t.Fatalf("Export into a shared directory error = %v, want %v", err, ErrInsecurePermissions)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Export wrote into a shared directory")
+	}
+	// ssh_config exports hold no passwords
+	if _, err := cfg.Export(filepath.Join(shared, "ssh_config"), FormatSSHConfig, "test", false); err != nil {
+		t.Errorf("Export(ssh_config) into a shared directory failed: %v", err)
+	}
+
+	// An existing file keeps no wider mode
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.Export(path, FormatYAML, "test", true); err != nil {
+		t.Fatalf("Export with force failed: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("exported file mode = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+}
+
+func TestCheckKeyFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes don't apply on Windows")
+	}
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(path, []byte("key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckKeyFile(path); err != nil {
+		t.Errorf("CheckKeyFile(0600) = %v, want nil", err)
+	}
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+	var perm PermissionError
+	if err := CheckKeyFile(path); !errors.As(err, &perm) || !perm.Key || perm.Mode.Perm() != 0644 {
+		t.Errorf("CheckKeyFile(0644) = %v, want a PermissionError for the key", err)
+	}
+	if err := CheckKeyFile(filepath.Join(t.TempDir(), "missing")); err != nil {
+		t.Errorf("CheckKeyFile(missing) = %v, want nil", err)
+	}
+}
+
+func TestUnlockDecryptsSecrets(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if err := cfg.SetupMasterPassword("correct horse battery"); err != nil {
+		t.Fatalf("SetupMasterPassword failed: %v", err)
+	}
+	for i := range 50 {
+		conn := model.NewConnection()
+		conn.Name = fmt.Sprintf("host-%d", i)
+		conn.Host = "10.0.0.1"
+		conn.User = "root"
+		conn.Port = 22
+		conn.AuthType = model.AuthPassword
+		conn.Password = fmt.Sprintf("secret-%d", i)
+		conn.KeyPassword = fmt.Sprintf("passphrase-%d", i)
+		if err := cfg.AddConnection(conn); err != nil {
+			t.Fatalf("AddConnection failed: %v", err)
+		}
+	}
+
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	if err := reloaded.Unlock("wrong"); err == nil {
+		t.Fatal("Unlock with a wrong password should fail")
+	}
+	if reloaded.Connections()[0].Password != "" {
+		t.Fatal("a failed unlock should not decrypt secrets")
+	}
+	if err := reloaded.Unlock("correct horse battery"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	for i, conn := range reloaded.Connections() {
+		if conn.Password != fmt.Sprintf("secret-%d", i) || conn.KeyPassword != fmt.Sprintf("passphrase-%d", i) {
+			t.Fatalf("connection %d secrets = %q, %q after unlock", i, conn.Password, conn.KeyPassword)
+		}
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if err := cfg.SetupMasterPassword("letmein1"); !errors.Is(err, crypto.ErrPasswordTooWeak) {
+		t.Fatalf("SetupMasterPassword(letmein1) error = %v, want %v", err, crypto.ErrPasswordTooWeak)
+	}
+	if err := cfg.SetupMasterPassword("correct horse battery"); err != nil {
+		t.Fatalf("SetupMasterPassword failed: %v", err)
+	}
+	conn := model.NewConnection()
+	conn.Name = "web"
+	conn.Host = "10.0.0.1"
+	conn.User = "root"
+	conn.Password = "secret"
+	if err := cfg.AddConnection(conn); err != nil {
+		t.Fatalf("AddConnection failed: %v", err)
+	}
+
+	if err := cfg.ChangePassword("wrong", "purple monkey dishwasher"); !errors.Is(err, crypto.ErrInvalidPassword) {
+		t.Errorf("ChangePassword with a wrong current password error = %v, want %v", err, crypto.ErrInvalidPassword)
+	}
+	if err := cfg.ChangePassword("correct horse battery", "password123"); !errors.Is(err, crypto.ErrPasswordTooWeak) {
+		t.Errorf("ChangePassword to password123 error = %v, want %v", err, crypto.ErrPasswordTooWeak)
+	}
+	if err := cfg.ChangePassword("correct horse battery", "purple monkey dishwasher"); err != nil {
+		t.Fatalf("ChangePassword failed: %v", err)
+	}
+
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	if err := reloaded.Unlock("purple monkey dishwasher"); err != nil {
+		t.Fatalf("Unlock with the new password failed: %v", err)
+	}
+	if got := reloaded.Connections()[0].Password; got != "secret" {
+		t.Errorf("password after the change = %q, want secret", got)
+	}
+}
+
+func TestAttachments(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if err := cfg.SetupMasterPassword("correct horse battery"); err != nil {
+		t.Fatalf("SetupMasterPassword failed: %v", err)
+	}
+	conn := model.NewConnection()
+	conn.Name = "web"
+	conn.Host = "10.0.0.1"
+	conn.User = "root"
+	if err := cfg.AddConnection(conn); err != nil {
+		t.Fatalf("AddConnection failed: %v", err)
+	}
+
+	if _, err := cfg.AddAttachment(conn.ID, "empty.txt", nil); !errors.Is(err, ErrAttachmentEmpty) {
+		t.Errorf("AddAttachment of nothing error = %v, want %v", err, ErrAttachmentEmpty)
+	}
+	if _, err := cfg.AddAttachment(conn.ID, "big.bin", make([]byte, model.MaxAttachmentSize+1)); !errors.Is(err, ErrAttachmentTooLarge) {
+		t.Errorf("AddAttachment of a large file error = %v, want %v", err, ErrAttachmentTooLarge)
+	}
+	runbook, err := cfg.AddAttachment(conn.ID, "/tmp/runbook.md", []byte("# Restart\nsystemctl restart web\n"))
+	if err != nil {
+		t.Fatalf("AddAttachment failed: %v", err)
+	}
+	if runbook.Name != "runbook.md" {
+		t.Errorf("attachment name = %q, want runbook.md", runbook.Name)
+	}
+	if _, err := cfg.AddAttachment(conn.ID, "runbook.md", []byte("again")); !errors.Is(err, ErrAttachmentExists) {
+		t.Errorf("AddAttachment of a taken name error = %v, want %v", err, ErrAttachmentExists)
+	}
+	vpn, err := cfg.AddAttachment(conn.ID, "vpn.conf", []byte("remote vpn.example.com"))
+	if err != nil {
+		t.Fatalf("AddAttachment failed: %v", err)
+	}
+
+	// The file on disk is encrypted
+	path := filepath.Join(AttachmentsDir(cfg.Path()), conn.ID, runbook.ID)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read attachment file: %v", err)
+	}
+	if bytes.Contains(content, []byte("systemctl")) {
+		t.Error("attachment file holds the content in the clear")
+	}
+
+	// Editing the connection keeps its attachments
+	edited, _ := cfg.GetConnection(conn.ID)
+	edited.Attachments = nil
+	edited.Notes = "edited"
+	if err := cfg.UpdateConnection(edited); err != nil {
+		t.Fatalf("UpdateConnection failed: %v", err)
+	}
+	if got, _ := cfg.GetConnection(conn.ID); len(got.Attachments) != 2 {
+		t.Fatalf("attachments after an edit = %d, want 2", len(got.Attachments))
+	}
+
+	// A new password re-encrypts the keys, the files still open
+	if err := cfg.ChangePassword("correct horse battery", "purple monkey dishwasher"); err != nil {
+		t.Fatalf("ChangePassword failed: %v", err)
+	}
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	if _, err := reloaded.OpenAttachment(conn.ID, runbook.ID); !errors.Is(err, ErrLocked) {
+		t.Errorf("OpenAttachment while locked error = %v, want %v", err, ErrLocked)
+	}
+	if err := reloaded.Unlock("purple monkey dishwasher"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	data, err := reloaded.OpenAttachment(conn.ID, runbook.ID)
+	if err != nil {
+		t.Fatalf("OpenAttachment failed: %v", err)
+	}
+	if string(data) != "# Restart\nsystemctl restart web\n" {
+		t.Errorf("OpenAttachment() = %q", data)
+	}
+
+	if err := reloaded.DeleteAttachment(conn.ID, runbook.ID); err != nil {
+		t.Fatalf("DeleteAttachment failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("file of a deleted attachment still exists: %v", err)
+	}
+	if _, err := reloaded.OpenAttachment(conn.ID, runbook.ID); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("OpenAttachment of a deleted attachment error = %v, want %v", err, ErrAttachmentNotFound)
+	}
+
+	// Purging the connection removes the rest
+	if err := reloaded.DeleteConnection(conn.ID); err != nil {
+		t.Fatalf("DeleteConnection failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(AttachmentsDir(cfg.Path()), conn.ID, vpn.ID)); err != nil {
+		t.Errorf("a trashed connection lost its attachments: %v", err)
+	}
+	if err := reloaded.PurgeConnection(conn.ID); err != nil {
+		t.Fatalf("PurgeConnection failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(AttachmentsDir(cfg.Path()), conn.ID)); !os.IsNotExist(err) {
+		t.Errorf("attachments of a purged connection still exist: %v", err)
+	}
+}
+
+func TestUnlockBackoff(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if err := cfg.SetupMasterPassword("correct horse battery"); err != nil {
+		t.Fatalf("SetupMasterPassword failed: %v", err)
+	}
+
+	cfg, err = NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	for i := 1; i <= model.FreeUnlockAttempts; i++ {
+		if err := cfg.Unlock("wrong"); !errors.Is(err, crypto.ErrInvalidPassword) {
+			t.Fatalf("Unlock attempt %d error = %v, want %v", i, err, crypto.ErrInvalidPassword)
+		}
+	}
+
+	// The wait survives a restart, and refuses even the right password
+	cfg, err = NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	failures, wait := cfg.UnlockAttempts()
+	if failures != model.FreeUnlockAttempts || wait <= 0 {
+		t.Fatalf("UnlockAttempts() = %d, %v; want %d and a wait", failures, wait, model.FreeUnlockAttempts)
+	}
+	var delayed UnlockDelayError
+	if err := cfg.Unlock("correct horse battery"); !errors.As(err, &delayed) || !errors.Is(err, ErrUnlockDelayed) {
+		t.Fatalf("Unlock while waiting error = %v, want an UnlockDelayError", err)
+	}
+
+	cfg.attempts.LastFailure = time.Now().Add(-time.Minute)
+	if err := cfg.Unlock("correct horse battery"); err != nil {
+		t.Fatalf("Unlock after the wait failed: %v", err)
+	}
+	cfg, err = NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	if failures, wait := cfg.UnlockAttempts(); failures != 0 || wait != 0 {
+		t.Errorf("UnlockAttempts() after unlocking = %d, %v; want 0, 0", failures, wait)
+	}
+
+	entries, err := cfg.AuditLog()
+	if err != nil {
+		t.Fatalf("AuditLog failed: %v", err)
+	}
+	var events []string
+	for _, e := range entries {
+		events = append(events, e.Event)
+	}
+	want := []string{AuditUnlockFailed, AuditUnlockFailed, AuditUnlockFailed, AuditUnlockDelayed, AuditUnlock}
+	if !slices.Equal(events, want) {
+		t.Errorf("audit log events = %v, want %v", events, want)
+	}
+}
+
+func TestRecordReveal(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if err := cfg.RecordReveal("web", "password"); err != nil {
+		t.Fatalf("RecordReveal failed: %v", err)
+	}
+
+	entries, err := cfg.AuditLog()
+	if err != nil {
+		t.Fatalf("AuditLog failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Event != AuditReveal || entries[0].Detail != "web/password" {
+		t.Errorf("audit log = %+v, want one reveal of web/password", entries)
+	}
+}
+
+func TestUnlockWithKey(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if err := cfg.SetupMasterPassword("correct horse battery"); err != nil {
+		t.Fatalf("SetupMasterPassword failed: %v", err)
+	}
+	if err := cfg.AddConnection(model.Connection{Name: "web", Host: "web.example.com", Port: 22, User: "root", AuthType: model.AuthPassword, Password: "secret"}); err != nil {
+		t.Fatalf("AddConnection failed: %v", err)
+	}
+	key, ok := cfg.MasterKey()
+	if !ok || len(key) != 32 {
+		t.Fatalf("MasterKey() = %x, %v; want a 32-byte key", key, ok)
+	}
+
+	cfg, err = NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	if _, ok := cfg.MasterKey(); ok {
+		t.Error("MasterKey() while locked returned a key")
+	}
+	if err := cfg.UnlockWithKey(make([]byte, 32)); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("UnlockWithKey with another key error = %v, want %v", err, ErrInvalidKey)
+	}
+	if err := cfg.UnlockWithKey(key); err != nil {
+		t.Fatalf("UnlockWithKey failed: %v", err)
+	}
+	if got := cfg.Connections()[0].Password; got != "secret" {
+		t.Errorf("Password after UnlockWithKey = %q, want %q", got, "secret")
+	}
+}
+
+func TestUnlockWithKeyWithoutSecrets(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if err := cfg.SetupMasterPassword("correct horse battery"); err != nil {
+		t.Fatalf("SetupMasterPassword failed: %v", err)
+	}
+	key, _ := cfg.MasterKey()
+
+	// No connection holds a secret, so only the key check can tell keys apart
+	cfg, err = NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	if err := cfg.UnlockWithKey(make([]byte, 32)); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("UnlockWithKey with another key error = %v, want %v", err, ErrInvalidKey)
+	}
+	if err := cfg.UnlockWithKey(key); err != nil {
+		t.Errorf("UnlockWithKey failed: %v", err)
+	}
+
+	// A config from before the key check refuses keys until the password
+	// unlocks it once
+	cfg.mu.Lock()
+	cfg.config.Settings.KeyCheck = ""
+	cfg.mu.Unlock()
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	cfg, err = NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	if err := cfg.UnlockWithKey(key); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("UnlockWithKey without a key check error = %v, want %v", err, ErrInvalidKey)
+	}
+	if err := cfg.Unlock("correct horse battery"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	cfg, err = NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	if err := cfg.UnlockWithKey(key); err != nil {
+		t.Errorf("UnlockWithKey after a password unlock failed: %v", err)
+	}
+}
+
+func TestWrapKey(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	secret, err := wrapKey(key, "salt")
+	if err != nil {
+		t.Fatalf("wrapKey failed: %v", err)
+	}
+	if strings.Contains(secret, base64.StdEncoding.EncodeToString(key)) {
+		t.Fatal("wrapKey left the key readable")
+	}
+
+	got, err := unwrapKey(secret, "salt")
+	if err != nil {
+		t.Fatalf("unwrapKey failed: %v", err)
+	}
+	if !bytes.Equal(got, key) {
+		t.Errorf("unwrapKey = %x, want %x", got, key)
+	}
+	// The master password changed since
+	if _, err := unwrapKey(secret, "new salt"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("unwrapKey with another salt error = %v, want %v", err, ErrInvalidKey)
+	}
+	if _, err := unwrapKey("garbage", "salt"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("unwrapKey of garbage error = %v, want %v", err, ErrInvalidKey)
+	}
+}
+
+func TestLock(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if err := cfg.Lock(); !errors.Is(err, ErrNotPasswordProtected) {
+		t.Fatalf("Lock without a master password error = %v, want %v", err, ErrNotPasswordProtected)
+	}
+	if err := cfg.SetupMasterPassword("correct horse battery"); err != nil {
+		t.Fatalf("SetupMasterPassword failed: %v", err)
+	}
+	if err := cfg.AddConnection(model.Connection{Name: "web", Host: "web.example.com", Port: 22, User: "root", AuthType: model.AuthPassword, Password: "secret"}); err != nil {
+		t.Fatalf("AddConnection failed: %v", err)
+	}
+
+	if err := cfg.Lock(); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	if cfg.IsUnlocked() {
+		t.Fatal("IsUnlocked() after Lock = true")
+	}
+	if got := cfg.Connections()[0].Password; got != "" {
+		t.Errorf("Password after Lock = %q, want it dropped", got)
+	}
+	if err := cfg.Unlock("correct horse battery"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if got := cfg.Connections()[0].Password; got != "secret" {
+		t.Errorf("Password after unlocking again = %q, want %q", got, "secret")
+	}
+
+	// gossh lock runs in another process
+	if cfg.LockRequested() {
+		t.Fatal("LockRequested() before RequestLock = true")
+	}
+	cfg.unlockedAt = time.Now().Add(-time.Minute)
+	other, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if err := other.RequestLock(); err != nil {
+		t.Fatalf("RequestLock failed: %v", err)
+	}
+	if !cfg.LockRequested() {
+		t.Error("LockRequested() after RequestLock = false")
+	}
+	if err := cfg.Unlock("correct horse battery"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if cfg.LockRequested() {
+		t.Error("LockRequested() after unlocking again = true")
+	}
+}
+
+func TestManagerTrash(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gossh-config-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", oldHome)
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	cfg.SetupWithoutPassword()
+
+	conn := model.NewConnection()
+	conn.Name = "to-trash"
+	conn.Host = "192.168.1.1"
+	conn.User = "root"
+	conn.Port = 22
+	conn.AuthType = model.AuthPassword
+	conn.Password = "secret"
+	cfg.AddConnection(conn)
+	id := cfg.Connections()[0].ID
+
+	// Deleting moves the connection to the trash
+	if err := cfg.DeleteConnection(id); err != nil {
+		t.Fatalf("Failed to delete connection: %v", err)
+	}
+	trash := cfg.TrashedConnections()
+	if len(cfg.Connections()) != 0 || len(trash) != 1 {
+		t.Fatalf("Expected 0 connections and 1 trashed, got %d and %d", len(cfg.Connections()), len(trash))
+	}
+	if trash[0].DeletedAt == nil {
+		t.Error("Expected DeletedAt to be set")
+	}
+
+	// Trash survives a reload and credentials are still decryptable
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	reloaded.AutoUnlockIfNeeded()
+	if got := reloaded.TrashedConnections(); len(got) != 1 || got[0].Password != "secret" {
+		t.Fatalf("Expected trashed connection with password after reload, got %+v", got)
+	}
+
+	// Restore brings it back
+	if err := reloaded.RestoreConnection(id); err != nil {
+		t.Fatalf("Failed to restore connection: %v", err)
+	}
+	if len(reloaded.Connections()) != 1 || len(reloaded.TrashedConnections()) != 0 {
+		t.Fatal("Expected connection to be restored")
+	}
+	if reloaded.Connections()[0].DeletedAt != nil {
+		t.Error("Expected DeletedAt to be cleared on restore")
+	}
+
+	// Purge removes it for good
+	reloaded.DeleteConnection(id)
+	if err := reloaded.PurgeConnection(id); err != nil {
+		t.Fatalf("Failed to purge connection: %v", err)
+	}
+	if len(reloaded.TrashedConnections()) != 0 {
+		t.Error("Expected trash to be empty after purge")
+	}
+}
+
+func TestManagerPasswordChangedAt(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gossh-config-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", oldHome)
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	cfg.SetupWithoutPassword()
+
+	conn := model.NewConnection()
+	conn.Name = "rotating"
+	conn.Host = "192.168.1.1"
+	conn.User = "root"
+	conn.AuthType = model.AuthPassword
+	conn.Password = "first"
+	cfg.AddConnection(conn)
+
+	added := cfg.Connections()[0]
+	if added.PasswordChangedAt == nil {
+		t.Fatal("Expected PasswordChangedAt to be set on add")
+	}
+
+	// Editing other fields keeps the rotation clock
+	old := added.PasswordChangedAt.Add(-48 * time.Hour)
+	cfg.config.Connections[0].PasswordChangedAt = &old
+	added.Group = "Production"
+	added.PasswordChangedAt = nil
+	if err := cfg.UpdateConnection(added); err != nil {
+		t.Fatalf("Failed to update connection: %v", err)
+	}
+	if got := cfg.Connections()[0].PasswordChangedAt; got == nil || !got.Equal(old) {
+		t.Errorf("Expected PasswordChangedAt to be preserved, got %v", got)
+	}
+
+	// A new password restarts it
+	added.Password = "second"
+	if err := cfg.UpdateConnection(added); err != nil {
+		t.Fatalf("Failed to update connection: %v", err)
+	}
+	if got := cfg.Connections()[0].PasswordChangedAt; got == nil || !got.After(old) {
+		t.Errorf("Expected PasswordChangedAt to be reset, got %v", got)
+	}
+}
+
+func TestManagerUpdateHealthStatus(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gossh-config-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", oldHome)
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	cfg.SetupWithoutPassword()
+
+	conn := model.NewConnection()
+	conn.Name = "checked"
+	conn.Host = "192.168.1.1"
+	conn.User = "root"
+	cfg.AddConnection(conn)
+
+	previous, err := cfg.UpdateHealthStatus(conn.ID, model.ConnStatusSuccess)
+	if err != nil || previous != "" {
+		t.Fatalf("First UpdateHealthStatus() = %q, %v; want empty previous", previous, err)
+	}
+
+	previous, err = cfg.UpdateHealthStatus(conn.ID, model.ConnStatusFailed)
+	if err != nil || previous != model.ConnStatusSuccess {
+		t.Errorf("Second UpdateHealthStatus() = %q, %v; want %q", previous, err, model.ConnStatusSuccess)
+	}
+
+	if _, err := cfg.UpdateHealthStatus("missing", model.ConnStatusFailed); err == nil {
+		t.Error("Expected error for unknown connection")
+	}
+}
+
+func TestManagerRecordSession(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gossh-config-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", oldHome)
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	cfg.SetupWithoutPassword()
+
+	conn := model.NewConnection()
+	conn.Name = "session"
+	conn.Host = "192.168.1.1"
+	conn.User = "root"
+	cfg.AddConnection(conn)
+
+	for i := 0; i < model.MaxHistory+5; i++ {
+		rec := model.SessionRecord{StartedAt: time.Now(), Duration: time.Minute, ExitCode: i}
+		if i == 0 {
+			rec.Address = "10.8.0.5"
+		}
+		if err := cfg.RecordSession(conn.ID, rec); err != nil {
+			t.Fatalf("RecordSession() error = %v", err)
+		}
+	}
+
+	// Reload to check the history is persisted
+	cfg, err = NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	got, _ := cfg.GetConnection(conn.ID)
+	if len(got.History) != model.MaxHistory {
+		t.Fatalf("len(History) = %d, want %d", len(got.History), model.MaxHistory)
+	}
+	last, _ := got.LastSession()
+	if last.ExitCode != model.MaxHistory+4 || last.Duration != time.Minute {
+		t.Errorf("LastSession() = %+v, want newest record", last)
+	}
+	if got.LastStatus != model.ConnStatusSuccess || got.LastConnected == nil {
+		t.Error("RecordSession() should mark the connection as successful")
+	}
+	if got.LastAddress != "10.8.0.5" {
+		t.Errorf("LastAddress = %q, want the last recorded address", got.LastAddress)
+	}
+
+	if err := cfg.RecordSession("missing", model.SessionRecord{}); err == nil {
+		t.Error("Expected error for unknown connection")
+	}
+}
+
+func TestManagerMigratesLegacyAuth(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gossh-config-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", oldHome)
+
+	// Saved by an older version: one connection added in the app, one
+	// imported from ~/.ssh/config
+	legacy := `version: "1.0"
+settings:
+  initialized: true
+connections:
+  - id: added
+    name: added
+    host: 10.0.0.1
+    port: 22
+    user: root
+    auth_type: ""
+    auth_method: key
+    key_path: /keys/added
+  - id: imported
+    name: imported
+    host: 10.0.0.2
+    port: 22
+    user: root
+    auth_type: key
+    key_path: /keys/imported
+  - id: both
+    name: both
+    host: 10.0.0.3
+    port: 22
+    user: root
+    auth_type: password
+    auth_method: key
+    key_path: /keys/both
+`
+	if err := EnsureConfigDir(); err != nil {
+		t.Fatalf("EnsureConfigDir() error = %v", err)
+	}
+	path, _ := ConfigPath()
+	if err := os.WriteFile(path, []byte(legacy), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	for _, id := range []string{"added", "imported", "both"} {
+		conn, _ := cfg.GetConnection(id)
+		if conn.AuthType != model.AuthKey {
+			t.Errorf("%s: AuthType = %q, want key", id, conn.AuthType)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if contains(string(data), "auth_method") {
+		t.Error("Migrated config should not contain auth_method")
+	}
+	if !contains(string(data), `version: "`+model.ConfigVersion+`"`) {
+		t.Errorf("Migrated config should be version %s", model.ConfigVersion)
+	}
+	if backup, err := os.ReadFile(path + ".bak"); err != nil || string(backup) != legacy {
+		t.Errorf("backup = %q, %v; want the original config", backup, err)
+	}
+}
+
+func TestManagerLeavesNewerConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	newer := "version: \"9.0\"\nsettings:\n  initialized: true\n"
+	if err := EnsureConfigDir(); err != nil {
+		t.Fatalf("EnsureConfigDir() error = %v", err)
+	}
+	path, _ := ConfigPath()
+	if err := os.WriteFile(path, []byte(newer), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	if _, err := NewManager(); err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != newer {
+		t.Errorf("config from a newer version was rewritten:\n%s", data)
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Errorf("Stat(backup) error = %v, want no backup", err)
+	}
+}
+
+func TestManagerStateFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	cfg.SetupWithoutPassword()
+
+	conn := model.NewConnection()
+	conn.Name = "busy"
+	conn.Host = "192.168.1.1"
+	conn.User = "root"
+	cfg.AddConnection(conn)
+
+	path, _ := ConfigPath()
+	before, _ := os.ReadFile(path)
+
+	// Status updates only touch the state file
+	if err := cfg.UpdateConnectionStatus(conn.ID, model.ConnStatusFailed); err != nil {
+		t.Fatalf("UpdateConnectionStatus() error = %v", err)
+	}
+	if after, _ := os.ReadFile(path); string(after) != string(before) {
+		t.Errorf("UpdateConnectionStatus() rewrote the config:\n%s", after)
+	}
+	state, err := os.ReadFile(StatePath(path))
+	if err != nil || !contains(string(state), "last_status: failed") {
+		t.Errorf("state file = %q, %v; want the new status", state, err)
+	}
+
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	if got, _ := reloaded.GetConnection(conn.ID); got.LastStatus != model.ConnStatusFailed || got.LastConnected == nil {
+		t.Errorf("reloaded state = %+v, want the saved status", got.State())
+	}
+}
+
+func TestDeferStateWrites(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	delay := stateWriteDelay
+	defer func() { stateWriteDelay = delay }()
+	stateWriteDelay = 10 * time.Millisecond
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	cfg.SetupWithoutPassword()
+	conn := model.NewConnection()
+	conn.Name = "busy"
+	conn.Host = "192.168.1.1"
+	conn.User = "root"
+	cfg.AddConnection(conn)
+	path, _ := ConfigPath()
+	errs := cfg.DeferStateWrites()
+
+	stateHas := func(text string) bool {
+		state, _ := os.ReadFile(StatePath(path))
+		return contains(string(state), text)
+	}
+	waitFor := func(text string) bool {
+		for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+			if stateHas(text) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Updates are written in the background
+	if err := cfg.UpdateConnectionStatus(conn.ID, model.ConnStatusFailed); err != nil {
+		t.Fatalf("UpdateConnectionStatus() error = %v", err)
+	}
+	if !waitFor("last_status: failed") {
+		t.Fatal("the background writer did not write the status")
+	}
+
+	// A failed write is reported and retried with the next update
+	if err := os.Remove(StatePath(path)); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(StatePath(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	cfg.UpdateHealthStatus(conn.ID, model.ConnStatusFailed)
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("got a nil write error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("a failed background write was not reported")
+	}
+	os.Remove(StatePath(path))
+	cfg.RecordSession(conn.ID, model.SessionRecord{StartedAt: time.Now(), Command: "uptime"})
+	if !waitFor("health_status: failed") || !stateHas("uptime") {
+		t.Error("the update after a failed write did not write both")
+	}
+
+	// What the writer has not got to is written by FlushState
+	stateWriteDelay = time.Hour
+	slow, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	slow.DeferStateWrites()
+	slow.UpdateHealthStatus(conn.ID, model.ConnStatusSuccess)
+	if stateHas("health_status: success") {
+		t.Error("the status was written at once")
+	}
+	if err := slow.FlushState(); err != nil {
+		t.Fatalf("FlushState() error = %v", err)
+	}
+	if !stateHas("health_status: success") {
+		t.Error("FlushState() did not write the pending status")
+	}
+}
+
+func TestManagerMigratesInlineState(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	legacy := `version: "1.1"
+settings:
+  initialized: true
+connections:
+  - id: web
+    name: web
+    host: 10.0.0.1
+    port: 22
+    user: root
+    auth_type: key
+    last_status: success
+    health_status: failed
+`
+	if err := EnsureConfigDir(); err != nil {
+		t.Fatalf("EnsureConfigDir() error = %v", err)
+	}
+	path, _ := ConfigPath()
+	if err := os.WriteFile(path, []byte(legacy), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if got, _ := cfg.GetConnection("web"); got.LastStatus != model.ConnStatusSuccess || got.HealthStatus != model.ConnStatusFailed {
+		t.Errorf("state = %+v, want the inline status kept", got.State())
+	}
+
+	data, _ := os.ReadFile(path)
+	if contains(string(data), "last_status") || contains(string(data), "health_status") {
+		t.Errorf("Migrated config should not hold state:\n%s", data)
+	}
+	state, _ := os.ReadFile(StatePath(path))
+	if !contains(string(state), "health_status: failed") {
+		t.Errorf("state file should hold the migrated state:\n%s", state)
+	}
+}
+
+func TestManagerSavedFilters(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	cfg.SetupWithoutPassword()
+
+	cfg.SaveFilter(model.SavedFilter{Name: "prod web", Query: "group:prod tag:web"})
+	cfg.SaveFilter(model.SavedFilter{Name: "no root", Query: "-user:root"})
+	// Saving under a taken name replaces the query
+	cfg.SaveFilter(model.SavedFilter{Name: "prod web", Query: "group:prod tag:nginx"})
+
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	want := []model.SavedFilter{{Name: "prod web", Query: "group:prod tag:nginx"}, {Name: "no root", Query: "-user:root"}}
+	if got := reloaded.GetSettings().SavedFilters; !slices.Equal(got, want) {
+		t.Errorf("SavedFilters = %v, want %v", got, want)
+	}
+
+	if err := reloaded.DeleteFilter("prod web"); err != nil {
+		t.Fatalf("DeleteFilter() error = %v", err)
+	}
+	if got := reloaded.GetSettings().SavedFilters; len(got) != 1 || got[0].Name != "no root" {
+		t.Errorf("SavedFilters after delete = %v", got)
+	}
+}
+
+func TestManagerWorkspaces(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	cfg.SetupWithoutPassword()
+
+	conn := model.NewConnection()
+	conn.Name = "db"
+	conn.Host = "10.0.0.5"
+	conn.User = "root"
+	cfg.AddConnection(conn)
+
+	if err := cfg.SaveWorkspace(model.Workspace{Name: "empty"}); err == nil {
+		t.Error("SaveWorkspace() of a workspace opening nothing succeeded")
+	}
+	release := model.Workspace{
+		Name:        "release-day",
+		Connections: []string{"db"},
+		Tunnels:     []model.WorkspaceTunnel{{Connection: "db", Type: "local", Spec: "5432:localhost:5432"}},
+		Snippets:    []model.Snippet{{Name: "deploy", Command: "./deploy.sh"}},
+	}
+	if err := cfg.SaveWorkspace(release); err != nil {
+		t.Fatalf("SaveWorkspace() error = %v", err)
+	}
+	if err := cfg.RenameConnection(conn.ID, "pg"); err != nil {
+		t.Fatalf("RenameConnection() error = %v", err)
+	}
+
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	got, ok := reloaded.Workspace("release-day")
+	if !ok {
+		t.Fatal("Workspace() did not find the saved workspace")
+	}
+	if !slices.Equal(got.Connections, []string{"pg"}) || got.Tunnels[0].Connection != "pg" {
+		t.Errorf("workspace after rename = %v %v, want it to name pg", got.Connections, got.Tunnels)
+	}
+	if s, ok := got.Snippet("deploy"); !ok || s.Command != "./deploy.sh" {
+		t.Errorf("Snippet(deploy) = %v, %v", s, ok)
+	}
+
+	if err := reloaded.DeleteWorkspace("release-day"); err != nil {
+		t.Fatalf("DeleteWorkspace() error = %v", err)
+	}
+	if err := reloaded.DeleteWorkspace("release-day"); err == nil {
+		t.Error("DeleteWorkspace() of a missing workspace succeeded")
+	}
+	if len(reloaded.Workspaces()) != 0 {
+		t.Errorf("Workspaces() after delete = %v", reloaded.Workspaces())
+	}
+}
+
+func TestManagerSchedules(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	cfg.SetupWithoutPassword()
+
+	if err := cfg.AddSchedule(model.NewScheduledJob("0 3 * *", "run-backup.sh", nil)); !errors.Is(err, model.ErrInvalidCron) {
+		t.Errorf("AddSchedule() with a bad cron spec error = %v, want %v", err, model.ErrInvalidCron)
+	}
+	job := model.NewScheduledJob("0 3 * * *", "run-backup.sh", []string{"--group=Backups"})
+	if err := cfg.AddSchedule(job); err != nil {
+		t.Fatalf("AddSchedule() error = %v", err)
+	}
+	ran := time.Now().Truncate(time.Second)
+	if err := cfg.RecordScheduleRun(job.ID, ran, 2); err != nil {
+		t.Fatalf("RecordScheduleRun() error = %v", err)
+	}
+
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	jobs := reloaded.Schedules()
+	if len(jobs) != 1 {
+		t.Fatalf("Schedules() = %v, want 1 job", jobs)
+	}
+	if got := jobs[0]; !slices.Equal(got.Targets, job.Targets) || got.LastRun == nil || !got.LastRun.Equal(ran) || got.LastFailed != 2 {
+		t.Errorf("Schedules()[0] = %+v, want targets %v, last run %v and 2 failed", got, job.Targets, ran)
+	}
+
+	if err := reloaded.DeleteSchedule(job.ID[:8]); err != nil {
+		t.Fatalf("DeleteSchedule() error = %v", err)
+	}
+	if err := reloaded.DeleteSchedule(job.ID[:8]); err == nil {
+		t.Error("DeleteSchedule() of a missing job succeeded")
+	}
+	if len(reloaded.Schedules()) != 0 {
+		t.Errorf("Schedules() after delete = %v", reloaded.Schedules())
+	}
+}
+
+func TestManagerRuns(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	cfg.SetupWithoutPassword()
+
+	if _, err := cfg.FindRun(""); err == nil {
+		t.Error("FindRun() with no runs succeeded")
+	}
+	first := model.NewRun("uptime", 3, []string{"web2"})
+	if err := cfg.RecordRun(first); err != nil {
+		t.Fatalf("RecordRun() error = %v", err)
+	}
+	for i := range model.MaxRuns {
+		if err := cfg.RecordRun(model.NewRun(fmt.Sprintf("echo %d", i), 1, nil)); err != nil {
+			t.Fatalf("RecordRun() error = %v", err)
+		}
+	}
+	last := model.NewRun("df -h", 2, []string{"db/10.0.0.7"})
+	if err := cfg.RecordRun(last); err != nil {
+		t.Fatalf("RecordRun() error = %v", err)
+	}
+
+	runs, err := cfg.Runs()
+	if err != nil {
+		t.Fatalf("Runs() error = %v", err)
+	}
+	if len(runs) != model.MaxRuns {
+		t.Errorf("Runs() kept %d runs, want %d", len(runs), model.MaxRuns)
+	}
+	if _, err := cfg.FindRun(first.ShortID()); err == nil {
+		t.Error("FindRun() found a run beyond MaxRuns")
+	}
+	if got, err := cfg.FindRun(""); err != nil || got.ID != last.ID {
+		t.Errorf("FindRun(\"\") = %v, %v, want the latest run", got.ID, err)
+	}
+	got, err := cfg.FindRun(last.ShortID())
+	if err != nil {
+		t.Fatalf("FindRun() error = %v", err)
+	}
+	if got.Command != "df -h" || !slices.Equal(got.Failed, last.Failed) {
+		t.Errorf("FindRun() = %+v, want %+v", got, last)
+	}
+}
+
+func TestManagerGroupRules(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	cfg.SetupWithoutPassword()
+
+	// Connections from before the rules are only filed when asked
+	old := model.NewConnection()
+	old.Name, old.Host, old.User = "old", "old.prod.example.com", "root"
+	if err := cfg.AddConnection(old); err != nil {
+		t.Fatalf("AddConnection() error = %v", err)
+	}
+
+	if err := cfg.SetGroupRules([]model.GroupRule{{Pattern: "[", Group: "Production"}}); err == nil {
+		t.Error("SetGroupRules() accepted an invalid pattern")
+	}
+	rules := []model.GroupRule{{Pattern: `\.prod\.example\.com$`, Group: "Production", Tags: []string{"prod"}}}
+	if err := cfg.SetGroupRules(rules); err != nil {
+		t.Fatalf("SetGroupRules() error = %v", err)
+	}
+	if err := cfg.SetDefaultGroup("Misc"); err != nil {
+		t.Fatalf("SetDefaultGroup() error = %v", err)
+	}
+
+	added := model.NewConnection()
+	added.Name, added.Host, added.User = "web", "web.prod.example.com", "root"
+	if err := cfg.AddConnection(added); err != nil {
+		t.Fatalf("AddConnection() error = %v", err)
+	}
+	if _, err := cfg.ImportConnections([]model.Connection{{Name: "nas", Host: "192.168.1.5", User: "admin", Port: 22}}, false); err != nil {
+		t.Fatalf("ImportConnections() error = %v", err)
+	}
+
+	groups := map[string]string{}
+	for _, c := range cfg.Connections() {
+		groups[c.Name] = c.Group
+	}
+	if groups["old"] != "" || groups["web"] != "Production" || groups["nas"] != "Misc" {
+		t.Errorf("groups = %v", groups)
+	}
+
+	if changes := cfg.PreviewGroupRules(); len(changes) != 1 || changes[0].Connection.Name != "old" {
+		t.Errorf("PreviewGroupRules() = %+v", changes)
+	}
+	if n, err := cfg.ApplyGroupRules(); err != nil || n != 1 {
+		t.Errorf("ApplyGroupRules() = %d, %v, want 1", n, err)
+	}
+
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	for _, c := range reloaded.Connections() {
+		if c.Name == "old" && (c.Group != "Production" || !slices.Equal(c.Tags, []string{"prod"})) {
+			t.Errorf("old = %q %v after apply", c.Group, c.Tags)
+		}
+	}
+	if got := reloaded.GetSettings().GroupRules; len(got) != 1 || got[0].Group != "Production" {
+		t.Errorf("GroupRules = %+v", got)
+	}
+}
+
+func TestDiffConnections(t *testing.T) {
+	local := model.Connection{Name: "web", Host: "10.0.0.1", Port: 22, User: "root", Password: "secret", Notes: "rack 4", QuietLogin: true}
+	incoming := model.Connection{Name: "web", Host: "10.0.0.2", Port: 22, User: "root", RemoteDir: "/var/www/app"}
+
+	diffs := DiffConnections(local, incoming)
+	want := []FieldDiff{
+		{Field: "host", Local: "10.0.0.1", Incoming: "10.0.0.2", TakeIncoming: true},
+		{Field: "remote_dir", Incoming: "/var/www/app", TakeIncoming: true},
+		{Field: "notes", Local: "rack 4"},
+		{Field: "quiet_login", Local: "true", Incoming: "false"},
+	}
+	if !slices.Equal(diffs, want) {
+		t.Fatalf("DiffConnections() = %+v, want %+v", diffs, want)
+	}
+
+	merged := MergeConnection(local, incoming, diffs)
+	if merged.Host != "10.0.0.2" || merged.RemoteDir != "/var/www/app" || merged.Notes != "rack 4" || !merged.QuietLogin || merged.Password != "secret" {
+		t.Errorf("MergeConnection() = %+v", merged)
+	}
+
+	incoming.Password = "rotated"
+	diffs = DiffConnections(local, incoming)
+	if i := slices.IndexFunc(diffs, func(d FieldDiff) bool { return d.Field == "password" }); i < 0 || !diffs[i].Secret || !diffs[i].TakeIncoming {
+		t.Errorf("DiffConnections() with a new password = %+v", diffs)
+	}
+}
+
+func TestManagerMerge(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	cfg.SetupWithoutPassword()
+
+	for _, c := range []model.Connection{
+		{Name: "web", Host: "10.0.0.1", Port: 22, User: "root", Password: "secret", Group: "Production"},
+		{Name: "db-01", Aliases: []string{"db1"}, Host: "10.0.0.5", Port: 22, User: "postgres"},
+		{Name: "cache", Host: "10.0.0.9", Port: 22, User: "redis"},
+	} {
+		conn := model.NewConnection()
+		conn.Name, conn.Aliases, conn.Host, conn.Port, conn.User, conn.Password, conn.Group = c.Name, c.Aliases, c.Host, c.Port, c.User, c.Password, c.Group
+		if err := cfg.AddConnection(conn); err != nil {
+			t.Fatalf("AddConnection() error = %v", err)
+		}
+	}
+
+	plan := cfg.PlanMerge([]model.Connection{
+		{Name: "web", Host: "10.0.0.2", Port: 22, User: "root"},        // By name, no password
+		{Name: "db1", Host: "10.0.0.5", Port: 22, User: "postgres"},    // By alias
+		{Name: "redis", Host: "10.0.0.9", Port: 22, User: "redis"},     // By address
+		{Name: "new", Host: "10.0.0.20", Port: 22, User: "root"},       // Not stored
+		{Name: "cache-old", Host: "10.0.0.9", Port: 22, User: "redis"}, // Address already matched
+	})
+	if len(plan.New) != 2 || len(plan.Conflicts) != 3 || plan.Unchanged != 0 {
+		t.Fatalf("PlanMerge() = %d new, %d conflicts, %d unchanged", len(plan.New), len(plan.Conflicts), plan.Unchanged)
+	}
+
+	// Keeping the stored name of the cache leaves nothing to merge
+	for i, d := range plan.Conflicts[2].Diffs {
+		if d.Field == "name" {
+			plan.Conflicts[2].Diffs[i].TakeIncoming = false
+		}
+	}
+
+	added, merged, err := cfg.ApplyMerge(plan)
+	if err != nil || added != 2 || merged != 2 {
+		t.Fatalf("ApplyMerge() = %d, %d, %v, want 2, 2", added, merged, err)
+	}
+
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	if err := reloaded.AutoUnlockIfNeeded(); err != nil {
+		t.Fatalf("AutoUnlockIfNeeded() error = %v", err)
+	}
+	byName := map[string]model.Connection{}
+	for _, c := range reloaded.Connections() {
+		byName[c.Name] = c
+	}
+	if web := byName["web"]; web.Host != "10.0.0.2" || web.Password != "secret" || web.Group != "Production" {
+		t.Errorf("web = %q %q %q, want the new host and the stored password and group", web.Host, web.Password, web.Group)
+	}
+	if _, ok := byName["db1"]; !ok {
+		t.Errorf("db-01 was not renamed to the imported name: %v", slices.Collect(maps.Keys(byName)))
+	}
+	if _, ok := byName["cache"]; !ok || len(byName) != 5 {
+		t.Errorf("connections = %v", slices.Collect(maps.Keys(byName)))
+	}
+}
+
+func TestManagerDedupe(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	cfg.SetupWithoutPassword()
+
+	for _, c := range []model.Connection{
+		{Name: "web", Host: "10.0.0.1", Port: 22, User: "root", Tags: []string{"web"}},
+		{Name: "web-old", Host: "10.0.0.1", Port: 22, User: "root", Tags: []string{"legacy"}, Password: "secret"},
+		{Name: "app", Host: "10.0.0.2", Port: 22, User: "root", JumpHosts: []string{"web-old"}},
+	} {
+		conn := model.NewConnection()
+		conn.Name, conn.Host, conn.Port, conn.User, conn.Tags, conn.Password, conn.JumpHosts = c.Name, c.Host, c.Port, c.User, c.Tags, c.Password, c.JumpHosts
+		if err := cfg.AddConnection(conn); err != nil {
+			t.Fatalf("AddConnection() error = %v", err)
+		}
+	}
+
+	sets := cfg.Duplicates()
+	if len(sets) != 1 || len(sets[0]) != 2 {
+		t.Fatalf("Duplicates() = %v", sets)
+	}
+	if _, err := cfg.Dedupe(sets[0][0].ID, []string{sets[0][1].ID}); err != nil {
+		t.Fatalf("Dedupe() error = %v", err)
+	}
+
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	if err := reloaded.AutoUnlockIfNeeded(); err != nil {
+		t.Fatalf("AutoUnlockIfNeeded() error = %v", err)
+	}
+	if len(reloaded.Duplicates()) != 0 || len(reloaded.TrashedConnections()) != 1 {
+		t.Errorf("%d duplicate sets and %d trashed after Dedupe()", len(reloaded.Duplicates()), len(reloaded.TrashedConnections()))
+	}
+	for _, c := range reloaded.Connections() {
+		switch c.Name {
+		case "web":
+			if !slices.Equal(c.Tags, []string{"web", "legacy"}) || !slices.Equal(c.Aliases, []string{"web-old"}) || c.Password != "secret" {
+				t.Errorf("web = %v %v %q", c.Tags, c.Aliases, c.Password)
+			}
+		case "app":
+			if !slices.Equal(c.JumpHosts, []string{"web"}) {
+				t.Errorf("app jump hosts = %v, want [web]", c.JumpHosts)
+			}
+		}
+	}
+}
+
+func TestManagerHistory(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	cfg.SetupWithoutPassword()
+
+	conn := model.NewConnection()
+	conn.Name = "web"
+	conn.Host = "10.0.0.1"
+	conn.User = "root"
+	conn.Password = "first"
+	cfg.AddConnection(conn)
+	if got := cfg.Revisions(conn.ID); len(got) != 0 {
+		t.Errorf("Revisions() after add = %v, want none", got)
+	}
+
+	edited, _ := cfg.GetConnection(conn.ID)
+	edited.Host = "10.0.0.2"
+	edited.Password = "second"
+	if err := cfg.UpdateConnection(edited); err != nil {
+		t.Fatalf("UpdateConnection() error = %v", err)
+	}
+	// Saving without changes records nothing
+	if err := cfg.UpdateConnection(edited); err != nil {
+		t.Fatalf("UpdateConnection() error = %v", err)
+	}
+	if err := cfg.RenameConnection(conn.ID, "www"); err != nil {
+		t.Fatalf("RenameConnection() error = %v", err)
+	}
+
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	if err := reloaded.AutoUnlockIfNeeded(); err != nil {
+		t.Fatalf("AutoUnlockIfNeeded() error = %v", err)
+	}
+	revisions := reloaded.Revisions(conn.ID)
+	if len(revisions) != 2 {
+		t.Fatalf("Revisions() = %d, want 2", len(revisions))
+	}
+	if !slices.Equal(revisions[0].Fields, []string{"host", "password"}) || revisions[0].Connection.Password != "" {
+		t.Errorf("first revision = %v, password %q", revisions[0].Fields, revisions[0].Connection.Password)
+	}
+
+	changes, err := reloaded.RevisionChanges(conn.ID, 1)
+	if err != nil {
+		t.Fatalf("RevisionChanges() error = %v", err)
+	}
+	if len(changes) != 1 || changes[0].Field != "name" || changes[0].Before != "web" || changes[0].After != "www" {
+		t.Errorf("RevisionChanges() = %+v", changes)
+	}
+
+	restored, err := reloaded.RestoreRevision(conn.ID, 0)
+	if err != nil {
+		t.Fatalf("RestoreRevision() error = %v", err)
+	}
+	if restored.Name != "web" || restored.Host != "10.0.0.1" || restored.Password != "first" {
+		t.Errorf("restored = %s %s %q, want the first version", restored.Name, restored.Host, restored.Password)
+	}
+	if got := reloaded.Revisions(conn.ID); len(got) != 3 {
+		t.Errorf("Revisions() after restore = %d, want 3", len(got))
+	}
+	if _, err := reloaded.RestoreRevision(conn.ID, 5); err == nil {
+		t.Error("RestoreRevision() of a missing revision succeeded")
+	}
+
+	// Purging the connection drops its history
+	reloaded.DeleteConnection(conn.ID)
+	reloaded.PurgeConnection(conn.ID)
+	if got := reloaded.Revisions(conn.ID); len(got) != 0 {
+		t.Errorf("Revisions() after purge = %d, want none", len(got))
+	}
+}
+
+func TestManagerHistoryRekey(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if err := cfg.SetupMasterPassword("correct horse battery"); err != nil {
+		t.Fatalf("SetupMasterPassword failed: %v", err)
+	}
+
+	conn := model.NewConnection()
+	conn.Name = "web"
+	conn.Host = "10.0.0.1"
+	conn.User = "root"
+	conn.Password = "first"
+	cfg.AddConnection(conn)
+	edited, _ := cfg.GetConnection(conn.ID)
+	edited.Password = "second"
+	if err := cfg.UpdateConnection(edited); err != nil {
+		t.Fatalf("UpdateConnection() error = %v", err)
+	}
+
+	// Revisions recorded under the old key restore after each rekey
+	tests := []struct {
+		name  string
+		rekey func() error
+		want  string
+	}{
+		{"change password", func() error { return cfg.ChangePassword("correct horse battery", "purple monkey dishwasher") }, "first"},
+		{"disable password", func() error { return cfg.DisablePassword("purple monkey dishwasher") }, "second"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rekey(); err != nil {
+				t.Fatalf("rekey error = %v", err)
+			}
+			revisions := cfg.Revisions(conn.ID)
+			restored, err := cfg.RestoreRevision(conn.ID, len(revisions)-1)
+			if err != nil {
+				t.Fatalf("RestoreRevision() error = %v", err)
+			}
+			if restored.Password != tt.want {
+				t.Errorf("restored password = %q, want %q", restored.Password, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatePath(t *testing.T) {
+	if got := StatePath("/home/a/.config/gossh/config.yaml"); got != "/home/a/.config/gossh/config.state.yaml" {
+		t.Errorf("StatePath() = %q", got)
+	}
+}
+
+func TestHistoryPath(t *testing.T) {
+	if got := HistoryPath("/home/a/.config/gossh/config.yaml"); got != "/home/a/.config/gossh/config.history.yaml" {
+		t.Errorf("HistoryPath() = %q", got)
+	}
+}
+
+func TestRunsPath(t *testing.T) {
+	if got := RunsPath("/home/a/.config/gossh/config.yaml"); got != "/home/a/.config/gossh/config.runs.yaml" {
+		t.Errorf("RunsPath() = %q", got)
+	}
+}
+
+func TestManagerValidate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	key := filepath.Join(home, "id_ed25519")
+	if err := os.WriteFile(key, []byte("key"), 0600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+
+	m := &Manager{config: model.NewConfig()}
+	m.config.Connections = []model.Connection{
+		{Name: "ok", Host: "10.0.0.1", User: "root", Port: 22, AuthType: model.AuthKey, KeyPath: key},
+		{Name: "home", Host: "10.0.0.5", User: "root", Port: 22, AuthType: model.AuthKey, KeyPath: "~/id_ed25519"},
+		{Name: "nokey", Host: "10.0.0.2", User: "root", Port: 22, AuthType: model.AuthKey, KeyPath: key + ".missing"},
+		{Name: "dup", Host: "10.0.0.3", User: "root", Port: 22},
+		{Name: "dup", Host: "10.0.0.4", User: "root", Port: 22},
+		{Name: "bad", Host: "10.0.0.256", Port: 22, JumpHosts: []string{"gone"}},
+	}
+
+	got := make(map[string][]string)
+	for _, p := range m.Validate() {
+		got[p.Connection.Name] = append(got[p.Connection.Name], p.Err.Error())
+	}
+
+	if len(got["ok"]) != 0 {
+		t.Errorf("ok: unexpected problems %v", got["ok"])
+	}
+	if len(got["home"]) != 0 {
+		t.Errorf("home: unexpected problems %v for a key path under ~", got["home"])
+	}
+	if len(got["nokey"]) != 1 || !contains(got["nokey"][0], "key file") {
+		t.Errorf("nokey: problems = %v, want an unreadable key file", got["nokey"])
+	}
+	if len(got["dup"]) != 2 || !contains(got["dup"][0], "used by 2 connections") {
+		t.Errorf("dup: problems = %v, want a duplicate name for each", got["dup"])
+	}
+	if len(got["bad"]) != 3 {
+		t.Errorf("bad: problems = %v, want invalid host, missing user and missing jump host", got["bad"])
+	}
+}
+
+func TestPurgeExpiredTrash(t *testing.T) {
+	old := time.Now().Add(-40 * 24 * time.Hour)
+	recent := time.Now().Add(-time.Hour)
+
+	m := &Manager{config: model.NewConfig()}
+	m.config.Trash = []model.Connection{
+		{ID: "old", Name: "old", DeletedAt: &old},
+		{ID: "recent", Name: "recent", DeletedAt: &recent},
+	}
+
+	if purged := m.purgeExpiredTrash(); purged != 1 {
+		t.Errorf("Expected 1 purged, got %d", purged)
+	}
+	if len(m.config.Trash) != 1 || m.config.Trash[0].ID != "recent" {
+		t.Errorf("Expected only the recent connection to remain, got %+v", m.config.Trash)
+	}
+}
+
 func contains(s, substr string) bool {
 	for i := 0; i <= len(s)-len(substr); i++ {
 		if s[i:i+len(substr)] == substr {
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"gossh/internal/model"
+)
+
+// Duplicates returns the sets of stored connections that log in to the same
+// user on the same host and port
+func (m *Manager) Duplicates() [][]model.Connection {
+	return model.FindDuplicates(m.Connections())
+}
+
+// Dedupe merges the connections with the given IDs into the one with
+// keepID, see model.MergeDuplicates, and moves them to the trash. Jump
+// hosts and workspaces naming a merged connection are pointed at the
+// survivor.
+func (m *Manager) Dedupe(keepID string, mergeIDs []string) (model.Connection, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keep := -1
+	var duplicates []model.Connection
+	for i, c := range m.config.Connections {
+		switch {
+		case c.ID == keepID:
+			keep = i
+		case slices.Contains(mergeIDs, c.ID):
+			duplicates = append(duplicates, c)
+		}
+	}
+	if keep < 0 || len(duplicates) != len(mergeIDs) {
+		return model.Connection{}, errors.New("connection not found")
+	}
+
+	stored := m.config.Connections[keep]
+	merged := model.MergeDuplicates(stored, duplicates)
+	merged.UpdatedAt = time.Now()
+	m.recordRevisionUnlocked(stored, merged)
+	m.config.Connections[keep] = merged
+
+	now := time.Now()
+	m.config.Connections = slices.DeleteFunc(m.config.Connections, func(c model.Connection) bool {
+		return slices.Contains(mergeIDs, c.ID)
+	})
+	for _, d := range duplicates {
+		d.DeletedAt = &now
+		m.config.Trash = append(m.config.Trash, d)
+	}
+
+	for _, d := range duplicates {
+		m.renameReferencesUnlocked(d.Name, merged.Name)
+	}
+
+	return merged, m.saveUnlocked()
+}
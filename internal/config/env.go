@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gossh/internal/model"
+)
+
+// Environment variables layered over the config file. One that is set wins
+// over the file, which wins over the defaults. Overrides are never saved.
+const (
+	EnvConfigDir = "GOSSH_CONFIG_DIR" // Directory holding config.yaml, known_hosts and locales
+	EnvLang      = "GOSSH_LANG"       // Language code, e.g. "zh"
+	EnvTheme     = "GOSSH_THEME"      // "dark" or "light"
+	EnvTimeout   = "GOSSH_TIMEOUT"    // Connection timeout in seconds
+)
+
+// getenv returns an environment variable without surrounding spaces
+func getenv(name string) string {
+	return strings.TrimSpace(os.Getenv(name))
+}
+
+// envTimeout returns the GOSSH_TIMEOUT override in seconds, if it is set to
+// a positive number
+func envTimeout() (int, bool) {
+	seconds, err := strconv.Atoi(getenv(EnvTimeout))
+	return seconds, err == nil && seconds > 0
+}
+
+// applyEnv returns settings with the environment overrides applied
+func applyEnv(s model.Settings) model.Settings {
+	if lang := getenv(EnvLang); lang != "" {
+		s.Language = lang
+	}
+	if theme := getenv(EnvTheme); slices.Contains(model.Themes, theme) {
+		s.Theme = theme
+	}
+	if seconds, ok := envTimeout(); ok {
+		s.ConnectionTimeout = seconds
+	}
+	return s
+}
+
+// CheckEnv reports an override set to a value gossh can't use. Such
+// overrides are ignored, leaving the config file's setting in place.
+func CheckEnv() error {
+	if v := getenv(EnvTheme); v != "" && !slices.Contains(model.Themes, v) {
+		return fmt.Errorf("%s: unknown theme %q, want one of %s", EnvTheme, v, strings.Join(model.Themes, ", "))
+	}
+	if v := getenv(EnvTimeout); v != "" {
+		if _, ok := envTimeout(); !ok {
+			return fmt.Errorf("%s: %q is not a positive number of seconds", EnvTimeout, v)
+		}
+	}
+	return nil
+}
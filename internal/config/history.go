@@ -0,0 +1,285 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"gossh/internal/crypto"
+	"gossh/internal/model"
+)
+
+// FieldChange is a field an edit changed
+type FieldChange struct {
+	Field  string // Field name as in the config file
+	Before string
+	After  string
+	Secret bool // Values are secrets and must not be shown
+}
+
+// changedFields returns the names of the fields that differ between two
+// versions of a connection, in form order
+func changedFields(before, after model.Connection) []string {
+	var fields []string
+	for _, f := range mergeFields {
+		if f.get(&before) != f.get(&after) {
+			fields = append(fields, f.name)
+		}
+	}
+	return fields
+}
+
+// revisionAuthor returns the local user name recorded with a revision
+func revisionAuthor() string {
+	u, err := user.Current()
+	if err != nil {
+		return os.Getenv("USER")
+	}
+	// Windows names come as DOMAIN\user
+	name := u.Username
+	if i := strings.LastIndex(name, "\\"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// recordRevisionUnlocked keeps before as a revision of the connection if
+// after changes any of its fields (caller must hold lock). The history file
+// is written with the next save.
+func (m *Manager) recordRevisionUnlocked(before, after model.Connection) {
+	fields := changedFields(before, after)
+	if len(fields) == 0 {
+		return
+	}
+
+	before.Password = ""
+	before.KeyPassword = ""
+	before.Jumps = nil
+	// A restore keeps the connection's attachments as they are
+	before.Attachments = nil
+	before.SetState(model.ConnectionState{})
+
+	if m.history.Connections == nil {
+		m.history.Connections = make(map[string][]model.Revision)
+	}
+	revisions := append(m.history.Connections[before.ID], model.Revision{
+		At:         time.Now(),
+		By:         revisionAuthor(),
+		Fields:     fields,
+		Connection: before,
+	})
+	if len(revisions) > model.MaxRevisions {
+		revisions = revisions[len(revisions)-model.MaxRevisions:]
+	}
+	m.history.Connections[before.ID] = revisions
+	m.historyDirty = true
+}
+
+// rekeyHistoryUnlocked re-encrypts the passwords kept in revisions for next,
+// which is about to replace the crypto service (caller must hold lock), so
+// they can still be restored. Nothing changes unless all could be
+// re-encrypted; a secret the current key can't decrypt either is dropped,
+// as it could not be restored anyway.
+func (m *Manager) rekeyHistoryUnlocked(next *crypto.CryptoService) error {
+	type rekeyed struct {
+		field *string
+		value string
+	}
+	var done []rekeyed
+	rekey := func(field *string) error {
+		if *field == "" {
+			return nil
+		}
+		if m.cryptoService == nil {
+			return ErrLocked
+		}
+		plain, err := m.cryptoService.Decrypt(*field)
+		if err != nil {
+			done = append(done, rekeyed{field, ""})
+			return nil
+		}
+		encrypted, err := next.Encrypt(plain)
+		if err != nil {
+			return err
+		}
+		done = append(done, rekeyed{field, encrypted})
+		return nil
+	}
+
+	for _, revisions := range m.history.Connections {
+		for i := range revisions {
+			c := &revisions[i].Connection
+			if err := rekey(&c.EncryptedPassword); err != nil {
+				return err
+			}
+			if err := rekey(&c.EncryptedKeyPassphrase); err != nil {
+				return err
+			}
+		}
+	}
+	for _, r := range done {
+		*r.field = r.value
+	}
+	if len(done) > 0 {
+		m.historyDirty = true
+	}
+	return nil
+}
+
+// saveHistoryUnlocked writes the history file if revisions were recorded
+// or dropped since it was last written (caller must hold lock). Revisions
+// of purged connections are dropped.
+func (m *Manager) saveHistoryUnlocked() error {
+	for id := range m.history.Connections {
+		if !slices.ContainsFunc(m.storedConnections(), func(c *model.Connection) bool { return c.ID == id }) {
+			delete(m.history.Connections, id)
+			m.historyDirty = true
+		}
+	}
+	if !m.historyDirty {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(m.path), 0700); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(&m.history)
+	if err != nil {
+		return err
+	}
+	if err := WritePrivateFile(HistoryPath(m.path), data); err != nil {
+		return err
+	}
+	m.historyDirty = false
+	return nil
+}
+
+// loadHistoryUnlocked reads the history file, if there is one (caller must
+// hold lock)
+func (m *Manager) loadHistoryUnlocked() error {
+	m.history = model.History{}
+	data, err := os.ReadFile(HistoryPath(m.path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := yaml.Unmarshal(data, &m.history); err != nil {
+		return fmt.Errorf("failed to read %s: %w", HistoryPath(m.path), err)
+	}
+	return nil
+}
+
+// Revisions returns the revisions of a connection, oldest first
+func (m *Manager) Revisions(id string) []model.Revision {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.history.Connections[id])
+}
+
+// RevisionChanges returns what the edit that replaced a connection's
+// revision at index changed: from the revision to the next one, or to the
+// connection as it is for the latest
+func (m *Manager) RevisionChanges(id string, index int) ([]FieldChange, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	revisions := m.history.Connections[id]
+	if index < 0 || index >= len(revisions) {
+		return nil, errors.New("revision not found")
+	}
+	before := revisions[index]
+	var after model.Connection
+	if index+1 < len(revisions) {
+		after = revisions[index+1].Connection
+	} else {
+		i := slices.IndexFunc(m.config.Connections, func(c model.Connection) bool { return c.ID == id })
+		if i < 0 {
+			return nil, errors.New("connection not found")
+		}
+		after = m.config.Connections[i]
+	}
+
+	changes := make([]FieldChange, 0, len(before.Fields))
+	for _, f := range mergeFields {
+		if !slices.Contains(before.Fields, f.name) {
+			continue
+		}
+		change := FieldChange{Field: f.name, Secret: f.secret}
+		if f.secret {
+			// Revisions keep secrets only encrypted
+			change.Before, change.After = encryptedSecret(f.name, &before.Connection), encryptedSecret(f.name, &after)
+		} else {
+			change.Before, change.After = f.get(&before.Connection), f.get(&after)
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// encryptedSecret returns the encrypted value of a secret field
+func encryptedSecret(field string, c *model.Connection) string {
+	if field == "key_passphrase" {
+		return c.EncryptedKeyPassphrase
+	}
+	return c.EncryptedPassword
+}
+
+// RestoreRevision returns a connection to its revision at index. The
+// connection keeps its state, and the restore is itself recorded, so it
+// can be undone.
+func (m *Manager) RestoreRevision(id string, index int) (model.Connection, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	revisions := m.history.Connections[id]
+	if index < 0 || index >= len(revisions) {
+		return model.Connection{}, errors.New("revision not found")
+	}
+	i := slices.IndexFunc(m.config.Connections, func(c model.Connection) bool { return c.ID == id })
+	if i < 0 {
+		return model.Connection{}, errors.New("connection not found")
+	}
+	current := m.config.Connections[i]
+
+	restored := revisions[index].Connection
+	if err := restored.Validate(); err != nil {
+		return model.Connection{}, err
+	}
+	for _, c := range m.config.Connections {
+		if c.ID != id && slices.ContainsFunc(restored.Names(), c.HasName) {
+			return model.Connection{}, errors.New("connection with that name already exists")
+		}
+	}
+	if m.cryptoService != nil {
+		var err error
+		if restored.EncryptedPassword != "" {
+			if restored.Password, err = m.cryptoService.Decrypt(restored.EncryptedPassword); err != nil {
+				return model.Connection{}, err
+			}
+		}
+		if restored.EncryptedKeyPassphrase != "" {
+			if restored.KeyPassword, err = m.cryptoService.Decrypt(restored.EncryptedKeyPassphrase); err != nil {
+				return model.Connection{}, err
+			}
+		}
+	}
+	restored.SetState(current.State())
+
+	if err := m.updateUnlocked(restored); err != nil {
+		return model.Connection{}, err
+	}
+
+	// Keep jump hosts and workspaces naming this one pointing at it
+	if restored.Name != current.Name {
+		m.renameReferencesUnlocked(current.Name, restored.Name)
+	}
+	return m.withJumps(m.config.Connections[i]), m.saveUnlocked()
+}
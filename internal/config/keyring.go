@@ -0,0 +1,131 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"gossh/internal/crypto"
+	"gossh/internal/keyring"
+)
+
+// ErrNotRemembered is returned by UnlockFromKeyring when the master key was
+// not stored with RememberKey
+var ErrNotRemembered = errors.New("master key is not remembered on this machine")
+
+// RememberKey stores the master key in the OS keyring, wrapped with a key
+// derived from this machine, so CLI commands unlock without the password.
+// The config must be unlocked with the password.
+func (m *Manager) RememberKey() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.config.Settings.PasswordProtectionEnabled {
+		return ErrNotPasswordProtected
+	}
+	if !m.unlocked || m.cryptoService == nil {
+		return errors.New("config is locked")
+	}
+
+	secret, err := wrapKey(m.cryptoService.Key(), m.config.Settings.EncryptionSalt)
+	if err != nil {
+		return err
+	}
+	if err := keyring.Set(m.path, secret); err != nil {
+		return err
+	}
+	_ = m.auditUnlocked(AuditRemember, "")
+
+	m.config.Settings.RememberKey = true
+	return m.saveUnlocked()
+}
+
+// UnlockFromKeyring unlocks with the master key RememberKey stored
+func (m *Manager) UnlockFromKeyring() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.config.Settings.RememberKey {
+		return ErrNotRemembered
+	}
+	secret, err := keyring.Get(m.path)
+	if err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return ErrNotRemembered
+		}
+		return err
+	}
+	key, err := unwrapKey(secret, m.config.Settings.EncryptionSalt)
+	if err != nil {
+		return err
+	}
+	return m.unlockWithKeyUnlocked(key, "with the master key from the OS keyring")
+}
+
+// ForgetKey removes the master key RememberKey stored from the OS keyring.
+// It doesn't need the config unlocked.
+func (m *Manager) ForgetKey() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.forgetKeyUnlocked(); err != nil {
+		return err
+	}
+	return m.saveUnlocked()
+}
+
+// forgetKeyUnlocked removes the remembered master key, if any (caller must
+// hold lock and save)
+func (m *Manager) forgetKeyUnlocked() error {
+	if err := keyring.Delete(m.path); err != nil && m.config.Settings.RememberKey {
+		return err
+	}
+	if m.config.Settings.RememberKey {
+		_ = m.auditUnlocked(AuditForget, "")
+	}
+	m.config.Settings.RememberKey = false
+	return nil
+}
+
+// wrapKey encrypts the master key, and the salt it was derived with, under
+// the key of this machine, so a copy of the keyring entry is no use on
+// another machine
+func wrapKey(key []byte, salt string) (string, error) {
+	service, err := machineCrypto()
+	if err != nil {
+		return "", err
+	}
+	return service.Encrypt(salt + ":" + base64.StdEncoding.EncodeToString(key))
+}
+
+// unwrapKey reverses wrapKey, failing for a key derived with another salt,
+// i.e. before the master password changed
+func unwrapKey(secret, salt string) ([]byte, error) {
+	service, err := machineCrypto()
+	if err != nil {
+		return nil, err
+	}
+	plain, err := service.Decrypt(secret)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	wrappedSalt, encoded, ok := strings.Cut(plain, ":")
+	if !ok || wrappedSalt != salt {
+		return nil, ErrInvalidKey
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
+
+// machineCrypto returns the crypto service of this machine's key, as used
+// without a master password
+func machineCrypto() (*crypto.CryptoService, error) {
+	machineKey, err := crypto.DeriveKeyFromMachine()
+	if err != nil {
+		machineKey = []byte(crypto.GetMachineID())
+	}
+	return crypto.NewCryptoServiceWithKey(machineKey, "")
+}
@@ -0,0 +1,220 @@
+package config
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gossh/internal/model"
+)
+
+// FieldDiff is a field that differs between a stored connection and the
+// imported one it matches
+type FieldDiff struct {
+	Field        string // Field name as in the config file
+	Local        string
+	Incoming     string
+	Secret       bool // Values are secrets and must not be shown
+	TakeIncoming bool // Resolution: replace the local value with the incoming one
+}
+
+// ImportConflict is an imported connection that matches a stored one but
+// differs from it
+type ImportConflict struct {
+	Local    model.Connection
+	Incoming model.Connection
+	Diffs    []FieldDiff
+}
+
+// MergePlan is what merging imported connections would do. Conflicts are
+// resolved by setting TakeIncoming on their diffs before ApplyMerge.
+type MergePlan struct {
+	New       []model.Connection
+	Conflicts []ImportConflict
+	Unchanged int // Imported connections identical to a stored one
+}
+
+// mergeField reads and copies one connection field for merging
+type mergeField struct {
+	name   string
+	secret bool
+	get    func(c *model.Connection) string
+	set    func(dst, src *model.Connection)
+}
+
+// mergeFields lists the fields an import can change, in form order. ID,
+// timestamps and state always stay local.
+var mergeFields = []mergeField{
+	{name: "name", get: func(c *model.Connection) string { return c.Name }, set: func(d, s *model.Connection) { d.Name = s.Name }},
+	{name: "aliases", get: func(c *model.Connection) string { return strings.Join(c.Aliases, ", ") }, set: func(d, s *model.Connection) { d.Aliases = s.Aliases }},
+	{name: "host", get: func(c *model.Connection) string { return c.Host }, set: func(d, s *model.Connection) { d.Host = s.Host }},
+	{name: "type", get: func(c *model.Connection) string { return string(c.Type) }, set: func(d, s *model.Connection) { d.Type = s.Type }},
+	{name: "port", get: func(c *model.Connection) string { return strconv.Itoa(c.Port) }, set: func(d, s *model.Connection) { d.Port = s.Port }},
+	{name: "addresses", get: func(c *model.Connection) string { return strings.Join(c.Addresses, ", ") }, set: func(d, s *model.Connection) { d.Addresses = s.Addresses }},
+	{name: "user", get: func(c *model.Connection) string { return c.User }, set: func(d, s *model.Connection) { d.User = s.User }},
+	{name: "auth_type", get: func(c *model.Connection) string { return string(c.AuthType) }, set: func(d, s *model.Connection) { d.AuthType = s.AuthType }},
+	{name: "password", secret: true, get: func(c *model.Connection) string { return c.Password }, set: func(d, s *model.Connection) { d.Password = s.Password }},
+	{name: "key_path", get: func(c *model.Connection) string { return c.KeyPath }, set: func(d, s *model.Connection) { d.KeyPath = s.KeyPath }},
+	{name: "key_passphrase", secret: true, get: func(c *model.Connection) string { return c.KeyPassword }, set: func(d, s *model.Connection) { d.KeyPassword = s.KeyPassword }},
+	{name: "agent_key", get: func(c *model.Connection) string { return c.AgentKey }, set: func(d, s *model.Connection) { d.AgentKey = s.AgentKey }},
+	{name: "gssapi", get: func(c *model.Connection) string { return strconv.FormatBool(c.GSSAPI) }, set: func(d, s *model.Connection) { d.GSSAPI = s.GSSAPI }},
+	{name: "group", get: func(c *model.Connection) string { return c.Group }, set: func(d, s *model.Connection) { d.Group = s.Group }},
+	{name: "tags", get: func(c *model.Connection) string { return strings.Join(c.Tags, ", ") }, set: func(d, s *model.Connection) { d.Tags = s.Tags }},
+	{name: "jump_hosts", get: func(c *model.Connection) string { return strings.Join(c.JumpHosts, ", ") }, set: func(d, s *model.Connection) { d.JumpHosts = s.JumpHosts }},
+	{name: "bind_address", get: func(c *model.Connection) string { return c.BindAddress }, set: func(d, s *model.Connection) { d.BindAddress = s.BindAddress }},
+	{name: "term", get: func(c *model.Connection) string { return c.Term }, set: func(d, s *model.Connection) { d.Term = s.Term }},
+	{name: "locale", get: func(c *model.Connection) string { return c.Locale }, set: func(d, s *model.Connection) { d.Locale = s.Locale }},
+	{name: "local_dir", get: func(c *model.Connection) string { return c.LocalDir }, set: func(d, s *model.Connection) { d.LocalDir = s.LocalDir }},
+	{name: "remote_dir", get: func(c *model.Connection) string { return c.RemoteDir }, set: func(d, s *model.Connection) { d.RemoteDir = s.RemoteDir }},
+	{name: "startup_command", get: func(c *model.Connection) string { return c.StartupCommand }, set: func(d, s *model.Connection) { d.StartupCommand = s.StartupCommand }},
+	{name: "pre_connect", get: func(c *model.Connection) string { return c.PreConnect }, set: func(d, s *model.Connection) { d.PreConnect = s.PreConnect }},
+	{name: "post_disconnect", get: func(c *model.Connection) string { return c.PostDisconnect }, set: func(d, s *model.Connection) { d.PostDisconnect = s.PostDisconnect }},
+	{name: "notes", get: func(c *model.Connection) string { return c.Notes }, set: func(d, s *model.Connection) { d.Notes = s.Notes }},
+	{name: "quiet_login", get: func(c *model.Connection) string { return strconv.FormatBool(c.QuietLogin) }, set: func(d, s *model.Connection) { d.QuietLogin = s.QuietLogin }},
+	{name: "show_banner", get: func(c *model.Connection) string { return strconv.FormatBool(c.ShowBanner) }, set: func(d, s *model.Connection) { d.ShowBanner = s.ShowBanner }},
+	{name: "password_rotate_after", get: func(c *model.Connection) string { return strconv.Itoa(c.PasswordRotateAfter) }, set: func(d, s *model.Connection) { d.PasswordRotateAfter = s.PasswordRotateAfter }},
+	{name: "expires_at", get: func(c *model.Connection) string {
+		if c.ExpiresAt == nil {
+			return ""
+		}
+		return c.ExpiresAt.Format(time.DateOnly)
+	}, set: func(d, s *model.Connection) { d.ExpiresAt = s.ExpiresAt }},
+}
+
+// DiffConnections returns the fields in which incoming differs from local.
+// A secret the import does not carry is not a difference: the local one is
+// kept. Each diff takes the incoming value unless it is empty, zero or off,
+// as fields a format cannot express read back.
+func DiffConnections(local, incoming model.Connection) []FieldDiff {
+	var diffs []FieldDiff
+	for _, f := range mergeFields {
+		l, in := f.get(&local), f.get(&incoming)
+		if l == in || (f.secret && in == "") {
+			continue
+		}
+		diffs = append(diffs, FieldDiff{
+			Field:        f.name,
+			Local:        l,
+			Incoming:     in,
+			Secret:       f.secret,
+			TakeIncoming: !zeroValue(in),
+		})
+	}
+	return diffs
+}
+
+// zeroValue reports whether a field value is what an unset field reads as
+func zeroValue(value string) bool {
+	return value == "" || value == "0" || value == "false"
+}
+
+// MergeConnection returns local with the diffs that take the incoming value
+// applied from incoming
+func MergeConnection(local, incoming model.Connection, diffs []FieldDiff) model.Connection {
+	for _, d := range diffs {
+		if !d.TakeIncoming {
+			continue
+		}
+		for _, f := range mergeFields {
+			if f.name == d.Field {
+				f.set(&local, &incoming)
+			}
+		}
+	}
+	// An imported name may be one of the kept aliases
+	local.Aliases = slices.DeleteFunc(slices.Clone(local.Aliases), func(a string) bool {
+		return a == local.Name
+	})
+	return local
+}
+
+// matchImported finds the stored connection an imported one stands for: the
+// one with its name, else one it names as alias or that names it as alias,
+// else one with the same user, host and port. Connections in used are
+// already taken by an earlier import.
+func matchImported(stored []model.Connection, conn model.Connection, used map[string]bool) int {
+	matches := []func(c *model.Connection) bool{
+		func(c *model.Connection) bool { return c.Name == conn.Name },
+		func(c *model.Connection) bool { return c.HasName(conn.Name) || conn.HasName(c.Name) },
+		func(c *model.Connection) bool {
+			return c.Host == conn.Host && c.Port == conn.Port && c.User == conn.User
+		},
+	}
+	for _, match := range matches {
+		for i := range stored {
+			if !used[stored[i].ID] && match(&stored[i]) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// PlanMerge compares imported connections with the stored ones without
+// changing anything. Unmatched connections are new; matched ones that
+// differ are conflicts, ready to resolve field by field.
+func (m *Manager) PlanMerge(connections []model.Connection) MergePlan {
+	stored := m.Connections()
+	used := make(map[string]bool)
+
+	var plan MergePlan
+	for _, conn := range connections {
+		i := matchImported(stored, conn, used)
+		if i < 0 {
+			plan.New = append(plan.New, conn)
+			continue
+		}
+		used[stored[i].ID] = true
+		diffs := DiffConnections(stored[i], conn)
+		if len(diffs) == 0 {
+			plan.Unchanged++
+			continue
+		}
+		plan.Conflicts = append(plan.Conflicts, ImportConflict{Local: stored[i], Incoming: conn, Diffs: diffs})
+	}
+	return plan
+}
+
+// ApplyMerge adds the plan's new connections and merges its conflicts as
+// resolved. It returns how many connections were added and how many took
+// at least one imported value.
+func (m *Manager) ApplyMerge(plan MergePlan) (added, merged int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, conn := range plan.New {
+		m.config.Connections = append(m.config.Connections, m.importedUnlocked(conn))
+		added++
+	}
+
+	for _, c := range plan.Conflicts {
+		if !slices.ContainsFunc(c.Diffs, func(d FieldDiff) bool { return d.TakeIncoming }) {
+			continue // Everything stays local
+		}
+		for i, stored := range m.config.Connections {
+			if stored.ID != c.Local.ID {
+				continue
+			}
+			conn := MergeConnection(stored, c.Incoming, c.Diffs)
+			if conn.Password != stored.Password {
+				conn.EncryptedPassword = m.encryptUnlocked(conn.Password)
+			}
+			if conn.KeyPassword != stored.KeyPassword {
+				conn.EncryptedKeyPassphrase = m.encryptUnlocked(conn.KeyPassword)
+			}
+			conn.UpdatedAt = time.Now()
+			m.recordRevisionUnlocked(stored, conn)
+			m.config.Connections[i] = conn
+			merged++
+		}
+	}
+
+	if added+merged == 0 {
+		return 0, 0, nil
+	}
+	if err := m.saveUnlocked(); err != nil {
+		return 0, 0, err
+	}
+	return added, merged, nil
+}
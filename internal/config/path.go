@@ -3,17 +3,36 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"strings"
 )
 
 const (
 	appName        = "gossh"
 	configFile     = "config.yaml"
 	knownHostsFile = "known_hosts"
+	localesDir     = "locales"
+	pluginsDir     = "plugins"
 )
 
-// ConfigDir returns the configuration directory path
+// configPath is the config file chosen with --config, empty for the
+// default one in ConfigDir
+var configPath string
+
+// SetConfigPath makes ConfigPath return path, e.g. from --config. An empty
+// path restores the default.
+func SetConfigPath(path string) {
+	configPath = path
+}
+
+// ConfigDir returns the configuration directory path, GOSSH_CONFIG_DIR
+// when it is set
 func ConfigDir() (string, error) {
+	if dir := getenv(EnvConfigDir); dir != "" {
+		return filepath.Abs(ExpandHome(dir))
+	}
+
 	var baseDir string
 
 	switch runtime.GOOS {
@@ -36,8 +55,13 @@ func ConfigDir() (string, error) {
 	return filepath.Join(baseDir, appName), nil
 }
 
-// ConfigPath returns the full path to the config file
+// ConfigPath returns the full path to the config file, the one set with
+// SetConfigPath if any
 func ConfigPath() (string, error) {
+	if configPath != "" {
+		return filepath.Abs(ExpandHome(configPath))
+	}
+
 	dir, err := ConfigDir()
 	if err != nil {
 		return "", err
@@ -45,6 +69,46 @@ func ConfigPath() (string, error) {
 	return filepath.Join(dir, configFile), nil
 }
 
+// StatePath returns the path of the state file kept next to the config file
+// at configPath, e.g. config.state.yaml for config.yaml
+func StatePath(configPath string) string {
+	ext := filepath.Ext(configPath)
+	return strings.TrimSuffix(configPath, ext) + ".state" + ext
+}
+
+// HistoryPath returns the path of the file keeping connection revisions
+// next to the config file at configPath, e.g. config.history.yaml
+func HistoryPath(configPath string) string {
+	ext := filepath.Ext(configPath)
+	return strings.TrimSuffix(configPath, ext) + ".history" + ext
+}
+
+// RunsPath returns the path of the file keeping the latest batch runs next
+// to the config file at configPath, e.g. config.runs.yaml
+func RunsPath(configPath string) string {
+	ext := filepath.Ext(configPath)
+	return strings.TrimSuffix(configPath, ext) + ".runs" + ext
+}
+
+// AuditPath returns the path of the audit log kept next to the config
+// file at configPath, e.g. config.audit.log for config.yaml
+func AuditPath(configPath string) string {
+	return strings.TrimSuffix(configPath, filepath.Ext(configPath)) + ".audit.log"
+}
+
+// AttachmentsDir returns the directory of the encrypted connection
+// attachments next to the config file at configPath, e.g.
+// config.attachments for config.yaml
+func AttachmentsDir(configPath string) string {
+	return strings.TrimSuffix(configPath, filepath.Ext(configPath)) + ".attachments"
+}
+
+// LockPath returns the path of the file `gossh lock` touches next to the
+// config file at configPath, e.g. config.lock for config.yaml
+func LockPath(configPath string) string {
+	return strings.TrimSuffix(configPath, filepath.Ext(configPath)) + ".lock"
+}
+
 // GetKnownHostsPath returns the path to the known_hosts file
 func GetKnownHostsPath() string {
 	dir, err := ConfigDir()
@@ -55,6 +119,24 @@ func GetKnownHostsPath() string {
 	return filepath.Join(dir, knownHostsFile)
 }
 
+// LocalesDir returns the directory scanned for external translation files
+func LocalesDir() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, localesDir), nil
+}
+
+// PluginsDir returns the directory holding the gossh-<command> plugins
+func PluginsDir() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, pluginsDir), nil
+}
+
 // EnsureConfigDir creates the config directory if it doesn't exist
 func EnsureConfigDir() error {
 	dir, err := ConfigDir()
@@ -63,3 +145,47 @@ func EnsureConfigDir() error {
 	}
 	return os.MkdirAll(dir, 0700)
 }
+
+// ExpandHome expands a leading "~/" to the user's home directory. On
+// Windows "~\" works too, as do variables such as %USERPROFILE%.
+func ExpandHome(path string) string {
+	return expandHome(path, runtime.GOOS == "windows")
+}
+
+// windowsVar matches a %NAME% environment variable reference
+var windowsVar = regexp.MustCompile(`%([A-Za-z_][A-Za-z0-9_()]*)%`)
+
+func expandHome(path string, windows bool) string {
+	if windows {
+		// Unset variables are left as they are, like cmd.exe does
+		path = windowsVar.ReplaceAllStringFunc(path, func(ref string) string {
+			if value, ok := os.LookupEnv(ref[1 : len(ref)-1]); ok {
+				return value
+			}
+			return ref
+		})
+	}
+	if path == "~" || strings.HasPrefix(path, "~/") || windows && strings.HasPrefix(path, `~\`) {
+		home, err := os.UserHomeDir()
+		if err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
+
+// ContractHome replaces the user's home directory at the start of path
+// with "~", the reverse of ExpandHome
+func ContractHome(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if path == home {
+		return "~"
+	}
+	if rel, ok := strings.CutPrefix(path, home+string(filepath.Separator)); ok {
+		return "~/" + filepath.ToSlash(rel)
+	}
+	return path
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// ErrInsecurePermissions is wrapped by PermissionError
+var ErrInsecurePermissions = errors.New("insecure permissions")
+
+// PermissionError says a file holding secrets, or the directory it would
+// go in, is open to other users. It wraps ErrInsecurePermissions.
+type PermissionError struct {
+	Path string
+	Mode os.FileMode
+	Key  bool // Path is a private key
+}
+
+func (e PermissionError) Error() string {
+	if e.Key {
+		return fmt.Sprintf("permissions %04o for private key %s are too open: run chmod 600 %s", e.Mode.Perm(), e.Path, e.Path)
+	}
+	return fmt.Sprintf("%s is accessible by other users (mode %04o)", e.Path, e.Mode.Perm())
+}
+
+func (e PermissionError) Unwrap() error {
+	return ErrInsecurePermissions
+}
+
+// CheckPrivateDir returns a PermissionError when other users can list or
+// write to dir, so a file of secrets written there could be found or
+// swapped. Windows modes don't say, so there it always returns nil.
+func CheckPrivateDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if info.Mode().Perm()&0006 != 0 {
+		return PermissionError{Path: dir, Mode: info.Mode()}
+	}
+	return nil
+}
+
+// CheckKeyFile returns a PermissionError when the group or other users
+// can access the private key at path, which OpenSSH refuses to use. A key
+// file that doesn't exist is not checked.
+func CheckKeyFile(path string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	path = ExpandHome(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return PermissionError{Path: path, Mode: info.Mode(), Key: true}
+	}
+	return nil
+}
+
+// OpenPrivateFile opens path like os.OpenFile, creating it readable and
+// writable by the user only. An existing file is restricted to that too,
+// as its old mode is otherwise kept.
+func OpenPrivateFile(path string, flag int) (*os.File, error) {
+	f, err := os.OpenFile(path, flag, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if runtime.GOOS != "windows" {
+		if err := f.Chmod(0600); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// WritePrivateFile writes data to path like os.WriteFile, leaving the file
+// readable and writable by the user only, also when it existed already
+func WritePrivateFile(path string, data []byte) error {
+	f, err := OpenPrivateFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
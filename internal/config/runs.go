@@ -0,0 +1,91 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"gossh/internal/model"
+)
+
+// readRunsUnlocked reads the runs file, if there is one (caller must hold
+// lock)
+func (m *Manager) readRunsUnlocked() (model.Runs, error) {
+	var runs model.Runs
+	data, err := os.ReadFile(RunsPath(m.path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return runs, nil
+		}
+		return runs, err
+	}
+	if err := yaml.Unmarshal(data, &runs); err != nil {
+		return runs, fmt.Errorf("failed to read %s: %w", RunsPath(m.path), err)
+	}
+	return runs, nil
+}
+
+// RecordRun adds a finished batch run to the runs file, dropping the oldest
+// beyond model.MaxRuns
+func (m *Manager) RecordRun(run model.Run) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	runs, err := m.readRunsUnlocked()
+	if err != nil {
+		return err
+	}
+	runs.Runs = append(runs.Runs, run)
+	if len(runs.Runs) > model.MaxRuns {
+		runs.Runs = runs.Runs[len(runs.Runs)-model.MaxRuns:]
+	}
+
+	if err := os.MkdirAll(filepath.Dir(m.path), 0700); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(&runs)
+	if err != nil {
+		return err
+	}
+	return WritePrivateFile(RunsPath(m.path), data)
+}
+
+// Runs returns the latest batch runs, oldest first
+func (m *Manager) Runs() ([]model.Run, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	runs, err := m.readRunsUnlocked()
+	return runs.Runs, err
+}
+
+// FindRun returns the batch run whose ID starts with id, which must be
+// unique, or the latest run for an empty id
+func (m *Manager) FindRun(id string) (model.Run, error) {
+	runs, err := m.Runs()
+	if err != nil {
+		return model.Run{}, err
+	}
+	if id == "" {
+		if len(runs) == 0 {
+			return model.Run{}, errors.New("run not found")
+		}
+		return runs[len(runs)-1], nil
+	}
+
+	var found []model.Run
+	for _, r := range runs {
+		if strings.HasPrefix(r.ID, id) {
+			found = append(found, r)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return model.Run{}, errors.New("run not found")
+	case 1:
+		return found[0], nil
+	}
+	return model.Run{}, errors.New("more than one run matches that ID")
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"gossh/internal/model"
+)
+
+// Schedules returns the scheduled jobs
+func (m *Manager) Schedules() []model.ScheduledJob {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.config.Schedules)
+}
+
+// AddSchedule saves a new scheduled job
+func (m *Manager) AddSchedule(job model.ScheduledJob) error {
+	if err := job.Validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Schedules = append(m.config.Schedules, job)
+	return m.saveUnlocked()
+}
+
+// findScheduleUnlocked returns the index of the job whose ID starts with
+// id, which must be unique (caller must hold lock)
+func (m *Manager) findScheduleUnlocked(id string) (int, error) {
+	found := -1
+	for i, j := range m.config.Schedules {
+		if id != "" && strings.HasPrefix(j.ID, id) {
+			if found >= 0 {
+				return -1, errors.New("more than one scheduled job matches that ID")
+			}
+			found = i
+		}
+	}
+	if found < 0 {
+		return -1, errors.New("scheduled job not found")
+	}
+	return found, nil
+}
+
+// DeleteSchedule removes the scheduled job whose ID starts with id
+func (m *Manager) DeleteSchedule(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i, err := m.findScheduleUnlocked(id)
+	if err != nil {
+		return err
+	}
+	m.config.Schedules = slices.Delete(slices.Clone(m.config.Schedules), i, i+1)
+	return m.saveUnlocked()
+}
+
+// RecordScheduleRun records when a scheduled job last ran and on how many
+// hosts it failed
+func (m *Manager) RecordScheduleRun(id string, at time.Time, failed int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := slices.IndexFunc(m.config.Schedules, func(j model.ScheduledJob) bool { return j.ID == id })
+	if i < 0 {
+		return errors.New("scheduled job not found")
+	}
+	m.config.Schedules[i].LastRun = &at
+	m.config.Schedules[i].LastFailed = failed
+	return m.saveUnlocked()
+}
+
+// Reload reads the config file again, for long-running commands that must
+// see changes made meanwhile. An unlocked manager stays unlocked.
+func (m *Manager) Reload() error {
+	if err := m.Load(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cryptoService != nil {
+		m.decryptSecrets()
+	}
+	return nil
+}
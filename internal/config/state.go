@@ -0,0 +1,108 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// stateWriteDelay is how long the background writer waits after a status
+// update before writing, so a burst of them, e.g. a health check of every
+// connection, is written once
+var stateWriteDelay = 200 * time.Millisecond
+
+// DeferStateWrites makes status updates (UpdateConnectionStatus,
+// RecordSession, UpdateHealthStatus and SetFacts) return without writing
+// the state file, which a background writer does instead, once for updates
+// coming close together. It suits the TUI, which the writes would block.
+// Failed writes are retried with the next update; their errors are sent on
+// the returned channel, dropped when the receiver falls behind. FlushState
+// writes what is pending, e.g. before exiting.
+func (m *Manager) DeferStateWrites() <-chan error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stateWake == nil {
+		m.stateWake = make(chan struct{}, 1)
+		m.stateErrs = make(chan error, 1)
+		go m.runStateWriter(stateWriteDelay)
+	}
+	return m.stateErrs
+}
+
+// FlushState writes the status updates the background writer has not
+// written yet
+func (m *Manager) FlushState() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.stateDirty {
+		return nil
+	}
+	return m.saveStateUnlocked()
+}
+
+// stateChangedUnlocked saves the state after a status update, or leaves it
+// to the background writer (caller must hold lock)
+func (m *Manager) stateChangedUnlocked() error {
+	if m.stateWake == nil {
+		return m.saveStateUnlocked()
+	}
+	m.stateDirty = true
+	select {
+	case m.stateWake <- struct{}{}:
+	default:
+		// The writer is already due to run
+	}
+	return nil
+}
+
+// runStateWriter writes the state delay after being woken, until the
+// process exits. The snapshot is taken under the lock, the file written
+// outside it.
+func (m *Manager) runStateWriter(delay time.Duration) {
+	for range m.stateWake {
+		time.Sleep(delay)
+
+		m.mu.Lock()
+		if !m.stateDirty {
+			// Written by a full save meanwhile
+			m.mu.Unlock()
+			continue
+		}
+		data, seq, err := m.snapshotStateUnlocked()
+		m.mu.Unlock()
+
+		if err == nil {
+			err = m.writeState(data, seq)
+		}
+		if err != nil {
+			m.mu.Lock()
+			m.stateDirty = true
+			m.mu.Unlock()
+			select {
+			case m.stateErrs <- err:
+			default:
+			}
+		}
+	}
+}
+
+// writeState writes a snapshot of the state, unless a newer one has been
+// written already: the background writer and saves race to write theirs.
+func (m *Manager) writeState(data []byte, seq uint64) error {
+	m.writeMu.Lock()
+	defer m.writeMu.Unlock()
+
+	if seq <= m.stateWritten {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0700); err != nil {
+		return err
+	}
+	if err := WritePrivateFile(StatePath(m.path), data); err != nil {
+		return err
+	}
+	m.stateWritten = seq
+	return nil
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+	"gossh/internal/model"
+	"gossh/internal/sshconfig"
+)
+
+// TransferFormat is the file format used for import and export
+type TransferFormat string
+
+const (
+	FormatYAML      TransferFormat = "yaml"
+	FormatSSHConfig TransferFormat = "ssh_config"
+)
+
+// TransferFormats lists the supported import/export formats
+var TransferFormats = []TransferFormat{FormatYAML, FormatSSHConfig}
+
+// ExportData is the on-disk format of exported connections
+type ExportData struct {
+	Version     string             `yaml:"version"`
+	Connections []model.Connection `yaml:"connections"`
+}
+
+// TransferResult summarizes an import or export
+type TransferResult struct {
+	Path     string
+	Total    int
+	Imported int
+	Merged   int
+	Skipped  int
+	Exported int
+}
+
+// Export writes all connections to path in the given format.
+// Encrypted fields are dropped so the file does not depend on the master password.
+// The file is readable by the user only. As passwords are then in plain
+// text, a file with any is not written into a directory other users can
+// list unless force is set.
+func (m *Manager) Export(path string, format TransferFormat, version string, force bool) (TransferResult, error) {
+	path = ExpandHome(path)
+	connections := m.Connections()
+
+	var data []byte
+	switch format {
+	case FormatYAML:
+		exportData := ExportData{
+			Version:     version,
+			Connections: make([]model.Connection, len(connections)),
+		}
+		for i, conn := range connections {
+			exportData.Connections[i] = conn
+			exportData.Connections[i].EncryptedPassword = ""
+			exportData.Connections[i].EncryptedKeyPassphrase = ""
+			exportData.Connections[i].Attachments = nil
+		}
+
+		var err error
+		data, err = yaml.Marshal(&exportData)
+		if err != nil {
+			return TransferResult{}, fmt.Errorf("failed to marshal config: %w", err)
+		}
+	case FormatSSHConfig:
+		// Without the executable, password connections fall back to
+		// plain Host blocks
+		stdio, _ := m.StdioCommand()
+		data = []byte(sshconfig.Format(connections, stdio))
+	default:
+		return TransferResult{}, fmt.Errorf("unsupported format: %s", format)
+	}
+
+	if !force && format == FormatYAML && slices.ContainsFunc(connections, hasSecrets) {
+		if err := CheckPrivateDir(filepath.Dir(path)); err != nil {
+			return TransferResult{}, err
+		}
+	}
+	if err := WritePrivateFile(path, data); err != nil {
+		return TransferResult{}, fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return TransferResult{Path: path, Total: len(connections), Exported: len(connections)}, nil
+}
+
+// hasSecrets reports whether conn holds a password or key passphrase
+func hasSecrets(conn model.Connection) bool {
+	return conn.Password != "" || conn.KeyPassword != ""
+}
+
+// Import reads connections from path in the given format and adds them.
+// Connections whose name already exists are replaced only when overwrite is set.
+func (m *Manager) Import(path string, format TransferFormat, overwrite bool) (TransferResult, error) {
+	path = ExpandHome(path)
+	connections, err := ReadImport(path, format)
+	if err != nil {
+		return TransferResult{}, err
+	}
+
+	imported, err := m.ImportConnections(connections, overwrite)
+	if err != nil {
+		return TransferResult{}, fmt.Errorf("failed to import: %w", err)
+	}
+
+	return TransferResult{
+		Path:     path,
+		Total:    len(connections),
+		Imported: imported,
+		Skipped:  len(connections) - imported,
+	}, nil
+}
+
+// ReadImport reads the connections in a file of the given format
+func ReadImport(path string, format TransferFormat) ([]model.Connection, error) {
+	switch format {
+	case FormatYAML:
+		return ReadExportFile(path)
+	case FormatSSHConfig:
+		connections, err := sshconfig.NewParser().ParseFile(ExpandHome(path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse SSH config: %w", err)
+		}
+		return connections, nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// ReadExportFile reads connections from a file written by Export
+func ReadExportFile(path string) ([]model.Connection, error) {
+	data, err := os.ReadFile(ExpandHome(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var importData ExportData
+	if err := yaml.Unmarshal(data, &importData); err != nil {
+		return nil, fmt.Errorf("failed to parse file: %w", err)
+	}
+	return importData.Connections, nil
+}
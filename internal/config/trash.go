@@ -0,0 +1,112 @@
+package config
+
+import (
+	"errors"
+	"time"
+
+	"gossh/internal/model"
+)
+
+// storedConnections returns pointers to every stored connection, including
+// the trash, so credentials are decrypted and re-encrypted consistently
+func (m *Manager) storedConnections() []*model.Connection {
+	conns := make([]*model.Connection, 0, len(m.config.Connections)+len(m.config.Trash))
+	for i := range m.config.Connections {
+		conns = append(conns, &m.config.Connections[i])
+	}
+	for i := range m.config.Trash {
+		conns = append(conns, &m.config.Trash[i])
+	}
+	return conns
+}
+
+// purgeExpiredTrash drops trashed connections older than the retention period
+// (caller must hold lock). The change is persisted with the next save.
+func (m *Manager) purgeExpiredTrash() int {
+	cutoff := time.Now().Add(-m.config.Settings.TrashRetention())
+	kept := m.config.Trash[:0]
+	purged := 0
+	for _, c := range m.config.Trash {
+		if c.DeletedAt != nil && c.DeletedAt.Before(cutoff) {
+			purged++
+			continue
+		}
+		kept = append(kept, c)
+	}
+	m.config.Trash = kept
+	return purged
+}
+
+// TrashedConnections returns the connections in the trash
+func (m *Manager) TrashedConnections() []model.Connection {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]model.Connection, len(m.config.Trash))
+	copy(result, m.config.Trash)
+	return result
+}
+
+// TrashExpiry returns when a trashed connection will be purged
+func (m *Manager) TrashExpiry(conn model.Connection) time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if conn.DeletedAt == nil {
+		return time.Time{}
+	}
+	return conn.DeletedAt.Add(m.config.Settings.TrashRetention())
+}
+
+// RestoreConnection moves a connection from the trash back to the list
+func (m *Manager) RestoreConnection(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, c := range m.config.Trash {
+		if c.ID != id {
+			continue
+		}
+		for _, existing := range m.config.Connections {
+			if existing.Name == c.Name {
+				return errors.New("connection with that name already exists")
+			}
+		}
+
+		c.DeletedAt = nil
+		c.UpdatedAt = time.Now()
+		m.config.Trash = append(m.config.Trash[:i], m.config.Trash[i+1:]...)
+		m.config.Connections = append(m.config.Connections, c)
+		return m.saveUnlocked()
+	}
+
+	return errors.New("connection not found")
+}
+
+// PurgeConnection permanently removes a connection from the trash
+func (m *Manager) PurgeConnection(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, c := range m.config.Trash {
+		if c.ID == id {
+			m.config.Trash = append(m.config.Trash[:i], m.config.Trash[i+1:]...)
+			return m.saveUnlocked()
+		}
+	}
+
+	return errors.New("connection not found")
+}
+
+// EmptyTrash permanently removes every connection in the trash
+func (m *Manager) EmptyTrash() (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count := len(m.config.Trash)
+	if count == 0 {
+		return 0, nil
+	}
+	m.config.Trash = nil
+	return count, m.saveUnlocked()
+}
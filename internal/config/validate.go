@@ -0,0 +1,70 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gossh/internal/model"
+)
+
+// Problem is something wrong with a stored connection
+type Problem struct {
+	Connection model.Connection
+	Err        error
+}
+
+// Validate checks every stored connection, including what saving one does
+// not catch: key files that cannot be read or that other users can
+// access, names and aliases used more
+// than once and jump hosts that no longer resolve. Problems are returned in
+// list order.
+func (m *Manager) Validate() []Problem {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make(map[string]int, len(m.config.Connections))
+	for _, c := range m.config.Connections {
+		for _, n := range c.Names() {
+			names[n]++
+		}
+	}
+
+	var problems []Problem
+	for _, c := range m.config.Connections {
+		add := func(err error) {
+			problems = append(problems, Problem{Connection: c, Err: err})
+		}
+
+		for _, err := range c.Problems() {
+			add(err)
+		}
+		if n := names[c.Name]; n > 1 && c.Name != "" {
+			add(fmt.Errorf("name is used by %d connections", n))
+		}
+		for _, alias := range c.Aliases {
+			if n := names[alias]; n > 1 && alias != "" {
+				add(fmt.Errorf("alias %s is used by %d connections", alias, n))
+			}
+		}
+		if c.AuthType == model.AuthKey && c.KeyPath != "" {
+			if f, err := os.Open(ExpandHome(c.KeyPath)); err == nil {
+				f.Close()
+				if err := CheckKeyFile(c.KeyPath); err != nil {
+					add(err)
+				}
+			} else {
+				// The path is already in the message
+				var pathErr *os.PathError
+				if errors.As(err, &pathErr) {
+					err = pathErr.Err
+				}
+				add(fmt.Errorf("key file %s cannot be read: %w", c.KeyPath, err))
+			}
+		}
+		if _, err := model.ResolveJumps(c, m.config.Connections); err != nil {
+			add(err)
+		}
+	}
+	return problems
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"gossh/internal/model"
+)
+
+// Workspaces returns the saved workspaces
+func (m *Manager) Workspaces() []model.Workspace {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.config.Workspaces)
+}
+
+// Workspace returns the workspace with the given name
+func (m *Manager) Workspace(name string) (model.Workspace, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, w := range m.config.Workspaces {
+		if w.Name == name {
+			return w, true
+		}
+	}
+	return model.Workspace{}, false
+}
+
+// SaveWorkspace saves a workspace, replacing the one saved under its name
+// before
+func (m *Manager) SaveWorkspace(workspace model.Workspace) error {
+	if err := workspace.Validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, w := range m.config.Workspaces {
+		if w.Name == workspace.Name {
+			m.config.Workspaces[i] = workspace
+			return m.saveUnlocked()
+		}
+	}
+	m.config.Workspaces = append(m.config.Workspaces, workspace)
+	return m.saveUnlocked()
+}
+
+// DeleteWorkspace removes the workspace with the given name
+func (m *Manager) DeleteWorkspace(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := slices.IndexFunc(m.config.Workspaces, func(w model.Workspace) bool { return w.Name == name })
+	if i < 0 {
+		return errors.New("workspace not found")
+	}
+	m.config.Workspaces = slices.Delete(slices.Clone(m.config.Workspaces), i, i+1)
+	return m.saveUnlocked()
+}
+
+// renameReferencesUnlocked points jump hosts and workspaces naming a
+// connection at its new name (caller must hold lock)
+func (m *Manager) renameReferencesUnlocked(oldName, newName string) {
+	for i := range m.config.Connections {
+		for j, jump := range m.config.Connections[i].JumpHosts {
+			if strings.EqualFold(jump, oldName) {
+				m.config.Connections[i].JumpHosts[j] = newName
+			}
+		}
+	}
+	for i := range m.config.Workspaces {
+		m.config.Workspaces[i].RenameConnection(oldName, newName)
+	}
+}
@@ -0,0 +1,56 @@
+package crypto
+
+// commonPasswords ranks the most common passwords, followed by words
+// and names people build passwords from. Lower ranks are guessed first.
+var commonPasswords = map[string]int{
+	"123456": 1, "password": 2, "12345678": 3, "qwerty": 4, "123456789": 5, "12345": 6, "1234": 7,
+	"111111": 8, "1234567": 9, "dragon": 10, "123123": 11, "baseball": 12, "abc123": 13,
+	"football": 14, "monkey": 15, "letmein": 16, "696969": 17, "shadow": 18, "master": 19,
+	"666666": 20, "qwertyuiop": 21, "123321": 22, "mustang": 23, "1234567890": 24, "michael": 25,
+	"654321": 26, "superman": 27, "1qaz2wsx": 28, "7777777": 29, "121212": 30, "000000": 31,
+	"qazwsx": 32, "123qwe": 33, "killer": 34, "trustno1": 35, "jordan": 36, "jennifer": 37,
+	"zxcvbnm": 38, "asdfgh": 39, "hunter": 40, "buster": 41, "soccer": 42, "harley": 43, "batman": 44,
+	"andrew": 45, "tigger": 46, "sunshine": 47, "iloveyou": 48, "2000": 49, "charlie": 50,
+	"robert": 51, "thomas": 52, "hockey": 53, "ranger": 54, "daniel": 55, "starwars": 56,
+	"klaster": 57, "112233": 58, "george": 59, "computer": 60, "michelle": 61, "jessica": 62,
+	"pepper": 63, "1111": 64, "zxcvbn": 65, "555555": 66, "11111111": 67, "131313": 68, "freedom": 69,
+	"777777": 70, "pass": 71, "maggie": 72, "159753": 73, "aaaaaa": 74, "ginger": 75, "princess": 76,
+	"joshua": 77, "cheese": 78, "amanda": 79, "summer": 80, "love": 81, "ashley": 82, "nicole": 83,
+	"chelsea": 84, "biteme": 85, "matthew": 86, "access": 87, "yankees": 88, "987654321": 89,
+	"dallas": 90, "austin": 91, "thunder": 92, "taylor": 93, "matrix": 94, "mobilemail": 95,
+	"mom": 96, "monitor": 97, "monitoring": 98, "montana": 99, "moon": 100, "moscow": 101,
+	"welcome": 102, "admin": 103, "administrator": 104, "root": 105, "toor": 106, "changeme": 107,
+	"secret": 108, "passw0rd": 109, "login": 110, "guest": 111, "test": 112, "tester": 113,
+	"default": 114, "letmein1": 115, "welcome1": 116, "password1": 117, "password123": 118,
+	"qwerty123": 119, "admin123": 120, "root123": 121, "hello": 122, "hello123": 123,
+	"iloveyou1": 124, "princess1": 125, "monkey1": 126, "dragon1": 127, "football1": 128,
+	"baseball1": 129, "master1": 130, "shadow1": 131, "flower": 132, "hannah": 133, "samantha": 134,
+	"liverpool": 135, "arsenal": 136, "whatever": 137, "lovely": 138, "family": 139, "friends": 140,
+	"forever": 141, "blessed": 142, "angel": 143, "angels": 144, "jesus": 145, "christ": 146,
+	"banana": 147, "orange": 148, "apple": 149, "purple": 150, "yellow": 151, "silver": 152,
+	"golden": 153, "diamond": 154, "secret1": 155, "ninja": 156, "pokemon": 157, "minecraft": 158,
+	"fortnite": 159, "google": 160, "facebook": 161, "linux": 162, "ubuntu": 163, "windows": 164,
+	"server": 165, "database": 166, "gossh": 167, "ssh": 168, "sshd": 169, "system": 170,
+	"security": 171, "correct": 172, "horse": 173, "battery": 174, "staple": 175, "winter": 176,
+	"spring": 177, "autumn": 178, "october": 179, "november": 180, "december": 181, "january": 182,
+	"february": 183, "march": 184, "april": 185, "june": 186, "july": 187, "august": 188,
+	"september": 189, "monday": 190, "friday": 191, "sunday": 192, "house": 193, "money": 194,
+	"happy": 195, "smile": 196, "music": 197, "dance": 198, "cookie": 199, "coffee": 200,
+	"chocolate": 201, "pizza": 202, "tiger": 203, "lion": 204, "eagle": 205, "falcon": 206,
+	"wolf": 207, "bear": 208, "dolphin": 209, "phoenix": 210, "spider": 211, "rabbit": 212,
+	"red": 213, "blue": 214, "green": 215, "black": 216, "white": 217, "pink": 218, "gray": 219,
+	"brown": 220, "one": 221, "two": 222, "three": 223, "four": 224, "five": 225, "six": 226,
+	"seven": 227, "eight": 228, "nine": 229, "ten": 230, "the": 231, "and": 232, "you": 233,
+	"that": 234, "this": 235, "with": 236, "have": 237, "from": 238, "they": 239, "will": 240,
+	"your": 241, "what": 242, "when": 243, "make": 244, "time": 245, "life": 246, "world": 247,
+	"home": 248, "work": 249, "school": 250, "game": 251, "night": 252, "water": 253, "city": 254,
+	"country": 255, "people": 256, "company": 257, "office": 258, "business": 259, "manager": 260,
+	"user": 261, "users": 262, "backup": 263, "private": 264, "public": 265, "network": 266,
+	"internet": 267, "server1": 268, "cisco": 269, "oracle": 270, "mysql": 271, "postgres": 272,
+	"redis": 273, "docker": 274, "kubernetes": 275, "london": 276, "paris": 277, "berlin": 278,
+	"tokyo": 279, "china": 280, "india": 281, "america": 282, "canada": 283, "mexico": 284,
+	"brazil": 285, "john": 286, "david": 287, "james": 288, "peter": 289, "paul": 290, "mark": 291,
+	"chris": 292, "alex": 293, "anna": 294, "maria": 295, "sarah": 296, "emma": 297, "qwer": 298,
+	"asdf": 299, "zxcv": 300, "1q2w3e4r": 301, "1q2w3e": 302, "147258369": 303, "147258": 304,
+	"159357": 305, "741852963": 306, "zaq12wsx": 307,
+}
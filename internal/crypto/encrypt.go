@@ -19,9 +19,10 @@ var (
 	ErrDecryptionFailed  = errors.New("decryption failed")
 )
 
-// Encryptor provides AES-256-GCM encryption/decryption
+// Encryptor provides AES-256-GCM encryption/decryption. It is safe for
+// concurrent use, so many secrets can be decrypted at once.
 type Encryptor struct {
-	key []byte
+	aead cipher.AEAD // Built once from the key, not for every secret
 }
 
 // NewEncryptor creates a new encryptor with the given key
@@ -30,7 +31,18 @@ func NewEncryptor(key []byte) (*Encryptor, error) {
 	if len(key) != 32 {
 		return nil, fmt.Errorf("key must be 32 bytes, got %d", len(key))
 	}
-	return &Encryptor{key: key}, nil
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	return &Encryptor{aead: gcm}, nil
 }
 
 // Encrypt encrypts plaintext using AES-256-GCM
@@ -40,23 +52,13 @@ func (e *Encryptor) Encrypt(plaintext string) (string, error) {
 		return "", nil
 	}
 
-	block, err := aes.NewCipher(e.key)
-	if err != nil {
-		return "", fmt.Errorf("failed to create cipher: %w", err)
-	}
-
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		return "", fmt.Errorf("failed to create GCM: %w", err)
-	}
-
 	nonce := make([]byte, nonceSize)
 	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
 		return "", fmt.Errorf("failed to generate nonce: %w", err)
 	}
 
 	// Seal appends the ciphertext to nonce
-	ciphertext := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	ciphertext := e.aead.Seal(nonce, nonce, []byte(plaintext), nil)
 
 	return base64.StdEncoding.EncodeToString(ciphertext), nil
 }
@@ -76,20 +78,10 @@ func (e *Encryptor) Decrypt(ciphertext string) (string, error) {
 		return "", ErrInvalidCiphertext
 	}
 
-	block, err := aes.NewCipher(e.key)
-	if err != nil {
-		return "", fmt.Errorf("failed to create cipher: %w", err)
-	}
-
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		return "", fmt.Errorf("failed to create GCM: %w", err)
-	}
-
 	nonce := data[:nonceSize]
 	ciphertextBytes := data[nonceSize:]
 
-	plaintext, err := gcm.Open(nil, nonce, ciphertextBytes, nil)
+	plaintext, err := e.aead.Open(nil, nonce, ciphertextBytes, nil)
 	if err != nil {
 		return "", ErrDecryptionFailed
 	}
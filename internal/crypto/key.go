@@ -31,6 +31,7 @@ func DeriveKey(password string, salt string) ([]byte, error) {
 // CryptoService manages encryption/decryption with a master password
 type CryptoService struct {
 	encryptor *Encryptor
+	key       []byte
 	salt      string
 }
 
@@ -48,6 +49,7 @@ func NewCryptoService(masterPassword, salt string) (*CryptoService, error) {
 
 	return &CryptoService{
 		encryptor: encryptor,
+		key:       key,
 		salt:      salt,
 	}, nil
 }
@@ -71,10 +73,17 @@ func NewCryptoServiceWithKey(key []byte, salt string) (*CryptoService, error) {
 
 	return &CryptoService{
 		encryptor: encryptor,
+		key:       finalKey,
 		salt:      salt,
 	}, nil
 }
 
+// Key returns the 256-bit key the service encrypts with, as derived from
+// the master password
+func (c *CryptoService) Key() []byte {
+	return c.key
+}
+
 // Encrypt encrypts plaintext
 func (c *CryptoService) Encrypt(plaintext string) (string, error) {
 	return c.encryptor.Encrypt(plaintext)
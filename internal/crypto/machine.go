@@ -1,11 +1,13 @@
 package crypto
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
 	"os"
 	"runtime"
 	"strings"
+	"sync"
 )
 
 // GetMachineID returns a unique identifier for the current machine
@@ -115,8 +117,16 @@ func hashBytes(b []byte) string {
 	return hex.EncodeToString(hash[:16]) // Use first 16 bytes
 }
 
-// DeriveKeyFromMachine derives an encryption key from machine characteristics
+// DeriveKeyFromMachine derives an encryption key from machine characteristics.
+// The key only depends on the machine, so it is derived once per process and
+// each caller gets its own copy.
 func DeriveKeyFromMachine() ([]byte, error) {
+	key, err := machineKey()
+	return bytes.Clone(key), err
+}
+
+// machineKey runs the Argon2 derivation behind DeriveKeyFromMachine once
+var machineKey = sync.OnceValues(func() ([]byte, error) {
 	machineID := GetMachineID()
 	
 	// Use a fixed salt for machine-based key derivation
@@ -131,4 +141,4 @@ func DeriveKeyFromMachine() ([]byte, error) {
 	}
 	
 	return key, nil
-}
+})
@@ -107,61 +107,3 @@ func decodeHash(encodedHash string) (*argonParams, []byte, []byte, error) {
 
 	return params, salt, hash, nil
 }
-
-// PasswordStrength returns a strength score (0-4) and description
-func PasswordStrength(password string) (int, string) {
-	score := 0
-	length := len(password)
-
-	// Length score
-	if length >= 8 {
-		score++
-	}
-	if length >= 12 {
-		score++
-	}
-
-	// Character variety
-	hasLower := false
-	hasUpper := false
-	hasDigit := false
-	hasSpecial := false
-
-	for _, c := range password {
-		switch {
-		case c >= 'a' && c <= 'z':
-			hasLower = true
-		case c >= 'A' && c <= 'Z':
-			hasUpper = true
-		case c >= '0' && c <= '9':
-			hasDigit = true
-		default:
-			hasSpecial = true
-		}
-	}
-
-	if hasLower && hasUpper {
-		score++
-	}
-	if hasDigit {
-		score++
-	}
-	if hasSpecial {
-		score++
-	}
-
-	// Cap at 4
-	if score > 4 {
-		score = 4
-	}
-
-	descriptions := []string{
-		"Very Weak",
-		"Weak",
-		"Fair",
-		"Strong",
-		"Very Strong",
-	}
-
-	return score, descriptions[score]
-}
@@ -1,6 +1,7 @@
 package crypto
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -62,12 +63,16 @@ func TestPasswordStrength(t *testing.T) {
 	}{
 		{"empty", "", 0, 0},
 		{"short", "abc", 0, 1},
-		{"only lowercase", "abcdefgh", 1, 2},
-		{"lower + upper", "Abcdefgh", 2, 3},
-		{"lower + upper + digit", "Abcdefg1", 3, 4},
-		{"all types short", "Abc1!", 2, 3},
-		{"all types long", "Abcdefg1!@#", 4, 5},
-		{"very strong", "MyP@ssw0rd!2024", 4, 5},
+		{"sequence", "abcdefgh", 0, 0},
+		{"capitalized sequence", "Abcdefg1", 0, 1},
+		{"keyboard run", "qwertyuiop", 0, 0},
+		{"repeats", "abcabcabcabc", 0, 0},
+		{"common password with leet", "P@ssw0rd", 0, 0},
+		{"common word and year", "Summer2024!", 1, 2},
+		{"decorated common password", "MyP@ssw0rd!2024", 2, 3},
+		{"random short", "Xk9#mQ2z", 3, 3},
+		{"random long", "kT7#pL2@vN9q", 4, 4},
+		{"unrelated words", "correct horse battery staple", 4, 4},
 	}
 
 	for _, tt := range tests {
@@ -107,3 +112,37 @@ func TestSamePasswordDifferentHashes(t *testing.T) {
 		t.Error("Both hashes should verify with the original password")
 	}
 }
+
+func TestPasswordPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   PasswordPolicy
+		password string
+		rule     string // Rule broken, empty when the password is accepted
+	}{
+		{"too short", DefaultPasswordPolicy, "kT7#pL2", "length"},
+		{"longer minimum", PasswordPolicy{MinLength: 16}, "kT7#pL2@vN9q", "length"},
+		{"common", PasswordPolicy{}, "password123", "common"},
+		{"common with leet", PasswordPolicy{}, "P@ssw0rd", "common"},
+		{"common allowed", PasswordPolicy{AllowCommon: true}, "P@ssw0rd", ""},
+		{"blocklist", PasswordPolicy{Blocklist: []string{"Acme"}}, "acme-ops-2024!", "common"},
+		{"classes", PasswordPolicy{MinClasses: 3}, "correcthorsebattery", "classes"},
+		{"score", DefaultPasswordPolicy, "Summer2024!", "score"},
+		{"strong", DefaultPasswordPolicy, "correct horse battery staple", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Check(tt.password)
+			var pe PolicyError
+			switch {
+			case tt.rule == "" && err != nil:
+				t.Errorf("Check(%q) = %v, want nil", tt.password, err)
+			case tt.rule != "" && (!errors.As(err, &pe) || pe.Rule != tt.rule):
+				t.Errorf("Check(%q) = %v, want the %s rule broken", tt.password, err, tt.rule)
+			case tt.rule != "" && !errors.Is(err, ErrPasswordTooWeak):
+				t.Errorf("Check(%q) = %v, want it to wrap ErrPasswordTooWeak", tt.password, err)
+			}
+		})
+	}
+}
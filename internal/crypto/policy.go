@@ -0,0 +1,113 @@
+package crypto
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// MinPasswordLength is the shortest master password any policy allows
+const MinPasswordLength = 8
+
+// PasswordPolicy is what a master password must meet before it is set,
+// from settings.password_policy in the config file
+type PasswordPolicy struct {
+	MinLength   int      `yaml:"min_length,omitempty"`   // Characters; MinPasswordLength at least
+	MinClasses  int      `yaml:"min_classes,omitempty"`  // Of lower case, upper case, digits and symbols
+	MinScore    int      `yaml:"min_score,omitempty"`    // PasswordStrength score, 0-4
+	AllowCommon bool     `yaml:"allow_common,omitempty"` // Accept common passwords such as letmein
+	Blocklist   []string `yaml:"blocklist,omitempty"`    // Words passwords may not contain, e.g. the company name
+}
+
+// DefaultPasswordPolicy is the policy of new configs: at least a fair
+// strength score and no common passwords
+var DefaultPasswordPolicy = PasswordPolicy{MinLength: MinPasswordLength, MinScore: 2}
+
+// PolicyError says which rule of a PasswordPolicy a password breaks. It
+// wraps ErrPasswordTooWeak.
+type PolicyError struct {
+	Rule string // "length", "classes", "common" or "score"
+	Min  int    // Length, classes or score required
+}
+
+func (e PolicyError) Error() string {
+	switch e.Rule {
+	case "length":
+		return fmt.Sprintf("password too weak: minimum %d characters required", e.Min)
+	case "classes":
+		return fmt.Sprintf("password too weak: use at least %d of lower case, upper case, digits and symbols", e.Min)
+	case "common":
+		return "password too weak: it is a common password or contains a blocked word"
+	}
+	return "password too weak: it is too easy to guess"
+}
+
+func (e PolicyError) Unwrap() error {
+	return ErrPasswordTooWeak
+}
+
+// Check returns a PolicyError for the first rule password breaks, or nil
+func (p PasswordPolicy) Check(password string) error {
+	minLength := max(p.MinLength, MinPasswordLength)
+	if len([]rune(password)) < minLength {
+		return PolicyError{Rule: "length", Min: minLength}
+	}
+	if p.MinClasses > 0 && characterClasses(password) < p.MinClasses {
+		return PolicyError{Rule: "classes", Min: min(p.MinClasses, 4)}
+	}
+	if p.blocked(password) {
+		return PolicyError{Rule: "common"}
+	}
+	if p.MinScore > 0 {
+		if score, _ := PasswordStrength(password); score < p.MinScore {
+			return PolicyError{Rule: "score", Min: min(p.MinScore, 4)}
+		}
+	}
+	return nil
+}
+
+// blocked reports whether password contains a word of the blocklist or,
+// unless AllowCommon is set, is a common password, ignoring case and leet
+// substitutions
+func (p PasswordPolicy) blocked(password string) bool {
+	lower := strings.ToLower(password)
+	plain := strings.Map(func(r rune) rune {
+		if l, ok := leetSubstitutions[r]; ok {
+			return l
+		}
+		return r
+	}, lower)
+	if !p.AllowCommon {
+		if _, ok := commonPasswords[lower]; ok {
+			return true
+		}
+		if _, ok := commonPasswords[plain]; ok {
+			return true
+		}
+	}
+	for _, word := range p.Blocklist {
+		if word = strings.ToLower(word); word != "" && (strings.Contains(lower, word) || strings.Contains(plain, word)) {
+			return true
+		}
+	}
+	return false
+}
+
+// characterClasses counts the kinds of characters in password: lower
+// case, upper case, digits and symbols
+func characterClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
@@ -0,0 +1,275 @@
+package crypto
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Guess counts that separate the strength scores, as in zxcvbn: a password
+// guessed within 10^3 tries scores 0, within 10^6 scores 1, and so on
+var scoreThresholds = []float64{1e3, 1e6, 1e8, 1e10}
+
+// bruteforceCardinality is the guesses per character no pattern explains
+const bruteforceCardinality = 10
+
+// minMatchGuesses keeps a pattern from being cheaper than guessing its
+// characters would be, so short matches don't split strong passwords
+const minMatchGuesses = 50
+
+// maxEstimateLength bounds the part of a password that is analyzed; the
+// rest counts as guessed character by character
+const maxEstimateLength = 100
+
+// leetSubstitutions maps characters commonly swapped into words back to
+// the letters they stand for
+var leetSubstitutions = map[rune]rune{
+	'4': 'a', '@': 'a', '8': 'b', '(': 'c', '3': 'e', '6': 'g', '1': 'i',
+	'!': 'i', '|': 'l', '0': 'o', '$': 's', '5': 's', '7': 't', '+': 't', '2': 'z',
+}
+
+// keyboardRows are runs of adjacent keys people type as passwords
+var keyboardRows = []string{
+	"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./",
+	"~!@#$%^&*()_+", "qwertzuiop", "azertyuiop", "qsdfghjklm", "wxcvbn",
+	"1qaz2wsx3edc4rfv5tgb6yhn7ujm8ik9ol0p",
+}
+
+// EstimateGuesses estimates how many guesses an attacker who knows common
+// passwords, words and patterns needs to find password, in the manner of
+// zxcvbn. Each stretch of the password is explained by the cheapest of the
+// patterns it matches: a common password or word (also with capitals,
+// leet substitutions or reversed), a sequence such as abc or 9876, a run
+// of keyboard keys, a repeated character or block, or a year. What no
+// pattern explains is counted as guessed character by character.
+func EstimateGuesses(password string) float64 {
+	runes := []rune(password)
+	extra := 0
+	if len(runes) > maxEstimateLength {
+		extra = len(runes) - maxEstimateLength
+		runes = runes[:maxEstimateLength]
+	}
+	n := len(runes)
+
+	// best[i] is the fewest guesses for the first i characters
+	best := make([]float64, n+1)
+	best[0] = 1
+	matches := findMatches(runes)
+	for end := 1; end <= n; end++ {
+		best[end] = best[end-1] * bruteforceCardinality
+		for _, m := range matches[end] {
+			if g := best[m.start] * m.guesses; g < best[end] {
+				best[end] = g
+			}
+		}
+	}
+	return best[n] * math.Pow(bruteforceCardinality, float64(extra))
+}
+
+// PasswordStrength returns a strength score (0-4) and description, from
+// the guesses EstimateGuesses needs
+func PasswordStrength(password string) (int, string) {
+	guesses := EstimateGuesses(password)
+	score := 0
+	for _, threshold := range scoreThresholds {
+		if guesses >= threshold {
+			score++
+		}
+	}
+
+	descriptions := []string{
+		"Very Weak",
+		"Weak",
+		"Fair",
+		"Strong",
+		"Very Strong",
+	}
+
+	return score, descriptions[score]
+}
+
+// match is a stretch of a password that a pattern explains
+type match struct {
+	start   int
+	guesses float64
+}
+
+// findMatches returns the pattern matches of runes by the index after
+// their last character
+func findMatches(runes []rune) map[int][]match {
+	matches := make(map[int][]match)
+	add := func(start, end int, guesses float64) {
+		matches[end] = append(matches[end], match{start: start, guesses: max(guesses, minMatchGuesses)})
+	}
+
+	n := len(runes)
+	lower := []rune(strings.ToLower(string(runes)))
+	unleet := make([]rune, n)
+	for i, r := range lower {
+		if l, ok := leetSubstitutions[r]; ok {
+			unleet[i] = l
+		} else {
+			unleet[i] = r
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		for j := i + 1; j <= n; j++ {
+			// Common passwords and words, as typed or with leet undone
+			word := string(lower[i:j])
+			if rank, ok := commonRank(word); ok {
+				add(i, j, float64(rank)*capitalVariations(runes[i:j]))
+			}
+			if rank, ok := commonRank(reverse(word)); ok {
+				add(i, j, 2*float64(rank)*capitalVariations(runes[i:j]))
+			}
+			if plain := string(unleet[i:j]); plain != word {
+				if rank, ok := commonRank(plain); ok {
+					add(i, j, float64(rank)*capitalVariations(runes[i:j])*leetVariations(lower[i:j]))
+				}
+			}
+			if j-i < 3 {
+				continue
+			}
+			if g, ok := sequenceGuesses(lower[i:j]); ok {
+				add(i, j, g)
+			}
+			if g, ok := keyboardGuesses(string(lower[i:j])); ok {
+				add(i, j, g)
+			}
+			if g, ok := repeatGuesses(runes[i:j]); ok {
+				add(i, j, g)
+			}
+		}
+		// Years
+		if i+4 <= n {
+			if year := string(runes[i : i+4]); year >= "1900" && year <= "2049" && isDigits(year) {
+				add(i, i+4, 150)
+			}
+		}
+	}
+	return matches
+}
+
+// commonRank returns how high s ranks among common passwords and words
+func commonRank(s string) (int, bool) {
+	if len(s) < 3 {
+		return 0, false
+	}
+	rank, ok := commonPasswords[s]
+	return rank, ok
+}
+
+// capitalVariations is the factor capitals add to guessing a word: none
+// for all lower case, little for a capital first letter or all capitals,
+// more for capitals elsewhere
+func capitalVariations(word []rune) float64 {
+	upper, lower := 0, 0
+	for _, r := range word {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+	switch {
+	case upper == 0:
+		return 1
+	case lower == 0 || upper == 1 && unicode.IsUpper(word[0]):
+		return 2
+	}
+	return math.Pow(2, float64(min(upper, lower)+1))
+}
+
+// leetVariations is the factor leet substitutions add to guessing a word
+func leetVariations(word []rune) float64 {
+	subs := 0
+	for _, r := range word {
+		if _, ok := leetSubstitutions[r]; ok {
+			subs++
+		}
+	}
+	if subs == 0 {
+		return 1
+	}
+	return math.Pow(2, float64(subs))
+}
+
+// sequenceGuesses explains runs such as abcd, 2468 or zyx, whose characters
+// step by the same small amount
+func sequenceGuesses(s []rune) (float64, bool) {
+	delta := int(s[1]) - int(s[0])
+	if delta == 0 || delta > 3 || delta < -3 {
+		return 0, false
+	}
+	for i := 2; i < len(s); i++ {
+		if int(s[i])-int(s[i-1]) != delta {
+			return 0, false
+		}
+	}
+	base := 26.0
+	switch {
+	case strings.ContainsRune("aAzZ019", s[0]):
+		base = 4 // Obvious starts
+	case unicode.IsDigit(s[0]):
+		base = 10
+	}
+	if delta < 0 {
+		base *= 2
+	}
+	if delta != 1 && delta != -1 {
+		base *= 2
+	}
+	return base * float64(len(s)), true
+}
+
+// keyboardGuesses explains runs of adjacent keys such as qwerty or asdf,
+// typed either way
+func keyboardGuesses(s string) (float64, bool) {
+	if len(s) < 4 {
+		return 0, false
+	}
+	for _, row := range keyboardRows {
+		if strings.Contains(row, s) || strings.Contains(row, reverse(s)) {
+			return 40 * float64(len(s)), true
+		}
+	}
+	return 0, false
+}
+
+// repeatGuesses explains a character or block typed several times, such as
+// aaaa or abcabc, as guessing the block once then the count
+func repeatGuesses(s []rune) (float64, bool) {
+	for size := 1; size <= len(s)/2; size++ {
+		if len(s)%size != 0 {
+			continue
+		}
+		block := s[:size]
+		repeated := true
+		for i := size; i < len(s) && repeated; i += size {
+			repeated = string(s[i:i+size]) == string(block)
+		}
+		if repeated {
+			return EstimateGuesses(string(block)) * float64(len(s)/size), true
+		}
+	}
+	return 0, false
+}
+
+func reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
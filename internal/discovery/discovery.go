@@ -0,0 +1,170 @@
+// Package discovery resolves service references used as connection hosts,
+// so a connection can follow the instances of a service instead of one
+// fixed address
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Service reference prefixes for a connection's host
+const (
+	SRVPrefix    = "srv:"    // DNS SRV record, e.g. srv:_ssh._tcp.web.example.com
+	ConsulPrefix = "consul:" // Consul service name, e.g. consul:web
+)
+
+// defaultConsulAddr is used when CONSUL_HTTP_ADDR is not set
+const defaultConsulAddr = "http://127.0.0.1:8500"
+
+// ErrNoInstances is returned when a service has no instances to connect to
+var ErrNoInstances = errors.New("service has no instances")
+
+// Instance is one resolved node of a service
+type Instance struct {
+	Host string
+	Port int
+}
+
+// Addr returns the instance as host:port
+func (i Instance) Addr() string {
+	return net.JoinHostPort(i.Host, strconv.Itoa(i.Port))
+}
+
+// IsService reports whether host is a service reference rather than an
+// address
+func IsService(host string) bool {
+	return strings.HasPrefix(host, SRVPrefix) || strings.HasPrefix(host, ConsulPrefix)
+}
+
+// Resolver resolves service references
+type Resolver struct {
+	ConsulAddr  string // Consul HTTP API address
+	ConsulToken string // Consul ACL token, if any
+	client      *http.Client
+	lookupSRV   func(ctx context.Context, name string) ([]*net.SRV, error)
+}
+
+// NewResolver creates a resolver using the system DNS resolver and the
+// Consul agent named by CONSUL_HTTP_ADDR and CONSUL_HTTP_TOKEN, like the
+// consul CLI
+func NewResolver() *Resolver {
+	addr := os.Getenv("CONSUL_HTTP_ADDR")
+	if addr == "" {
+		addr = defaultConsulAddr
+	}
+	return &Resolver{
+		ConsulAddr:  addr,
+		ConsulToken: os.Getenv("CONSUL_HTTP_TOKEN"),
+		client:      &http.Client{Timeout: 10 * time.Second},
+		lookupSRV: func(ctx context.Context, name string) ([]*net.SRV, error) {
+			_, srvs, err := net.DefaultResolver.LookupSRV(ctx, "", "", name)
+			return srvs, err
+		},
+	}
+}
+
+// DefaultResolver is used when connecting to services
+var DefaultResolver = NewResolver()
+
+// Resolve returns the instances of a service reference, in the order they
+// should be tried: by priority and weight for SRV records, as listed by
+// Consul for Consul services, which only returns instances passing their
+// health checks
+func (r *Resolver) Resolve(ctx context.Context, ref string) ([]Instance, error) {
+	var instances []Instance
+	var err error
+	switch {
+	case strings.HasPrefix(ref, SRVPrefix):
+		instances, err = r.resolveSRV(ctx, strings.TrimPrefix(ref, SRVPrefix))
+	case strings.HasPrefix(ref, ConsulPrefix):
+		instances, err = r.resolveConsul(ctx, strings.TrimPrefix(ref, ConsulPrefix))
+	default:
+		return nil, fmt.Errorf("%s: not a service reference", ref)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("%s: %w", ref, ErrNoInstances)
+	}
+	return instances, nil
+}
+
+// resolveSRV looks up a DNS SRV record such as _ssh._tcp.web.example.com
+func (r *Resolver) resolveSRV(ctx context.Context, name string) ([]Instance, error) {
+	srvs, err := r.lookupSRV(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	var instances []Instance
+	for _, srv := range srvs {
+		// A lone "." target means the service is decidedly not available
+		host := strings.TrimSuffix(srv.Target, ".")
+		if host == "" {
+			continue
+		}
+		instances = append(instances, Instance{Host: host, Port: int(srv.Port)})
+	}
+	return instances, nil
+}
+
+// consulEntry is the part of a Consul health API entry we use
+type consulEntry struct {
+	Node struct {
+		Address string
+	}
+	Service struct {
+		Address string
+		Port    int
+	}
+}
+
+// resolveConsul asks the Consul agent for the healthy instances of a service
+func (r *Resolver) resolveConsul(ctx context.Context, name string) ([]Instance, error) {
+	base := r.ConsulAddr
+	if !strings.Contains(base, "://") {
+		base = "http://" + base
+	}
+	endpoint := strings.TrimSuffix(base, "/") + "/v1/health/service/" + url.PathEscape(name) + "?passing=true"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if r.ConsulToken != "" {
+		req.Header.Set("X-Consul-Token", r.ConsulToken)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("consul returned %s", resp.Status)
+	}
+
+	var entries []consulEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("invalid consul response: %w", err)
+	}
+	instances := make([]Instance, 0, len(entries))
+	for _, e := range entries {
+		// Services registered without an address use their node's
+		host := e.Service.Address
+		if host == "" {
+			host = e.Node.Address
+		}
+		instances = append(instances, Instance{Host: host, Port: e.Service.Port})
+	}
+	return instances, nil
+}
@@ -0,0 +1,97 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsService(t *testing.T) {
+	tests := map[string]bool{
+		"srv:_ssh._tcp.web.example.com": true,
+		"consul:web":                    true,
+		"web.example.com":               false,
+		"10.0.0.1":                      false,
+	}
+	for host, want := range tests {
+		if got := IsService(host); got != want {
+			t.Errorf("IsService(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestResolveSRV(t *testing.T) {
+	r := NewResolver()
+	var asked string
+	r.lookupSRV = func(_ context.Context, name string) ([]*net.SRV, error) {
+		asked = name
+		return []*net.SRV{
+			{Target: "web1.example.com.", Port: 22},
+			{Target: ".", Port: 0},
+			{Target: "web2.example.com.", Port: 2222},
+		}, nil
+	}
+
+	instances, err := r.Resolve(context.Background(), "srv:_ssh._tcp.web.example.com")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if asked != "_ssh._tcp.web.example.com" {
+		t.Errorf("looked up %q, want the name without prefix", asked)
+	}
+	want := []Instance{{"web1.example.com", 22}, {"web2.example.com", 2222}}
+	if len(instances) != len(want) || instances[0] != want[0] || instances[1] != want[1] {
+		t.Errorf("Resolve() = %v, want %v", instances, want)
+	}
+	if got := instances[1].Addr(); got != "web2.example.com:2222" {
+		t.Errorf("Addr() = %q", got)
+	}
+}
+
+func TestResolveConsul(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/v1/health/service/web" || req.URL.Query().Get("passing") == "" {
+			http.NotFound(w, req)
+			return
+		}
+		if req.Header.Get("X-Consul-Token") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`[
+			{"Node": {"Address": "10.0.0.1"}, "Service": {"Address": "", "Port": 22}},
+			{"Node": {"Address": "10.0.0.2"}, "Service": {"Address": "10.1.0.2", "Port": 2222}}
+		]`))
+	}))
+	defer server.Close()
+
+	r := NewResolver()
+	r.ConsulAddr = server.Listener.Addr().String() // Without scheme, like CONSUL_HTTP_ADDR often is
+	r.ConsulToken = "secret"
+
+	instances, err := r.Resolve(context.Background(), "consul:web")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	want := []Instance{{"10.0.0.1", 22}, {"10.1.0.2", 2222}}
+	if len(instances) != len(want) || instances[0] != want[0] || instances[1] != want[1] {
+		t.Errorf("Resolve() = %v, want %v", instances, want)
+	}
+
+	if _, err := r.Resolve(context.Background(), "consul:db"); err == nil {
+		t.Error("Resolve should fail for an unknown service")
+	}
+}
+
+func TestResolveNoInstances(t *testing.T) {
+	r := NewResolver()
+	r.lookupSRV = func(context.Context, string) ([]*net.SRV, error) {
+		return []*net.SRV{{Target: ".", Port: 0}}, nil
+	}
+	if _, err := r.Resolve(context.Background(), "srv:_ssh._tcp.gone.example.com"); !errors.Is(err, ErrNoInstances) {
+		t.Errorf("Resolve() error = %v, want ErrNoInstances", err)
+	}
+}
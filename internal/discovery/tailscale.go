@@ -0,0 +1,161 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gossh/internal/model"
+)
+
+// DefaultTailscaleSocket is where tailscaled serves its local API on Linux
+const DefaultTailscaleSocket = "/var/run/tailscale/tailscaled.sock"
+
+// TailnetGroup is the group discovered Tailscale peers are imported into
+const TailnetGroup = "Tailnet"
+
+// TailscalePeer is a node of the tailnet as reported by tailscaled
+type TailscalePeer struct {
+	HostName     string
+	DNSName      string // MagicDNS name, e.g. web.tail1234.ts.net.
+	TailscaleIPs []string
+	Tags         []string // ACL tags, e.g. tag:prod
+	OS           string
+	Online       bool
+}
+
+// Name returns the peer's short MagicDNS name, or its host name when
+// MagicDNS is off
+func (p TailscalePeer) Name() string {
+	if name, _, _ := strings.Cut(p.DNSName, "."); name != "" {
+		return name
+	}
+	return p.HostName
+}
+
+// Host returns the address to reach the peer at: its MagicDNS name, or its
+// first Tailscale IP when MagicDNS is off
+func (p TailscalePeer) Host() string {
+	if host := strings.TrimSuffix(p.DNSName, "."); host != "" {
+		return host
+	}
+	if len(p.TailscaleIPs) > 0 {
+		return p.TailscaleIPs[0]
+	}
+	return p.HostName
+}
+
+// tailscaleStatus is the part of the local API status we use
+type tailscaleStatus struct {
+	Peer map[string]TailscalePeer
+}
+
+// TailscalePeers asks the local tailscaled for the peers of the tailnet,
+// sorted by name
+func TailscalePeers(ctx context.Context, socket string) ([]TailscalePeer, error) {
+	if socket == "" {
+		socket = DefaultTailscaleSocket
+	}
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+
+	// The host is ignored; tailscaled only checks it is this one
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://local-tailscaled.sock/localapi/v0/status", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach tailscaled at %s: %w", socket, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tailscaled returned %s", resp.Status)
+	}
+
+	var status tailscaleStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("invalid tailscaled response: %w", err)
+	}
+	peers := make([]TailscalePeer, 0, len(status.Peer))
+	for _, p := range status.Peer {
+		peers = append(peers, p)
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].Name() < peers[j].Name()
+	})
+	return peers, nil
+}
+
+// TailscaleConnections turns peers into connections in the Tailnet group,
+// logging in as user. ACL tags become gossh tags without their "tag:"
+// prefix.
+func TailscaleConnections(peers []TailscalePeer, user string) []model.Connection {
+	connections := make([]model.Connection, 0, len(peers))
+	for _, p := range peers {
+		conn := model.NewConnection()
+		conn.Name = p.Name()
+		conn.Host = p.Host()
+		conn.User = user
+		conn.Group = TailnetGroup
+		for _, tag := range p.Tags {
+			conn.Tags = append(conn.Tags, strings.TrimPrefix(tag, "tag:"))
+		}
+		connections = append(connections, conn)
+	}
+	return connections
+}
+
+// MergeTailnet compares discovered connections with existing ones by name.
+// Peers not known yet are returned as added. Known Tailnet connections whose
+// host or tags changed are returned as updated, keeping everything else,
+// e.g. credentials, and any tags added by hand. Connections with the same
+// name outside the Tailnet group are left alone and counted as skipped.
+func MergeTailnet(existing, discovered []model.Connection) (added, updated []model.Connection, skipped int) {
+	byName := make(map[string]model.Connection, len(existing))
+	for _, c := range existing {
+		byName[strings.ToLower(c.Name)] = c
+	}
+
+	for _, d := range discovered {
+		c, ok := byName[strings.ToLower(d.Name)]
+		if !ok {
+			added = append(added, d)
+			continue
+		}
+		if c.Group != TailnetGroup {
+			skipped++
+			continue
+		}
+
+		changed := false
+		c.Tags = slices.Clone(c.Tags)
+		if c.Host != d.Host {
+			c.Host = d.Host
+			changed = true
+		}
+		for _, tag := range d.Tags {
+			if !slices.Contains(c.Tags, tag) {
+				c.Tags = append(c.Tags, tag)
+				changed = true
+			}
+		}
+		if changed {
+			updated = append(updated, c)
+		}
+	}
+	return added, updated, skipped
+}
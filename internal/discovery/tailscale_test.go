@@ -0,0 +1,81 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"gossh/internal/model"
+)
+
+func TestTailscalePeers(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "tailscaled.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/localapi/v0/status" {
+			http.NotFound(w, req)
+			return
+		}
+		_, _ = w.Write([]byte(`{"Peer": {
+			"nodekey:2": {"HostName": "web-01", "DNSName": "web.tail1234.ts.net.", "TailscaleIPs": ["100.64.0.2"], "Tags": ["tag:prod", "tag:web"], "Online": true},
+			"nodekey:1": {"HostName": "laptop", "DNSName": "", "TailscaleIPs": ["100.64.0.9"]}
+		}}`))
+	})}
+	go func() { _ = server.Serve(listener) }()
+	defer server.Close()
+
+	peers, err := TailscalePeers(context.Background(), socket)
+	if err != nil {
+		t.Fatalf("TailscalePeers failed: %v", err)
+	}
+	if len(peers) != 2 || peers[0].Name() != "laptop" || peers[1].Name() != "web" {
+		t.Fatalf("TailscalePeers() = %+v, want laptop and web sorted by name", peers)
+	}
+	if peers[0].Host() != "100.64.0.9" || peers[1].Host() != "web.tail1234.ts.net" {
+		t.Errorf("hosts = %q, %q; want the IP without MagicDNS, else the MagicDNS name", peers[0].Host(), peers[1].Host())
+	}
+
+	conns := TailscaleConnections(peers, "ops")
+	web := conns[1]
+	if web.Group != TailnetGroup || web.User != "ops" || len(web.Tags) != 2 || web.Tags[0] != "prod" {
+		t.Errorf("TailscaleConnections() web = %+v", web)
+	}
+
+	if _, err := TailscalePeers(context.Background(), filepath.Join(t.TempDir(), "missing.sock")); err == nil {
+		t.Error("TailscalePeers should fail without tailscaled")
+	}
+}
+
+func TestMergeTailnet(t *testing.T) {
+	existing := []model.Connection{
+		{Name: "web", Host: "web.old.ts.net", Group: TailnetGroup, Tags: []string{"pinned"}, KeyPath: "/keys/id"},
+		{Name: "db", Host: "db.tail1234.ts.net", Group: TailnetGroup, Tags: []string{"prod"}},
+		{Name: "laptop", Host: "192.168.1.9", Group: "Home"},
+	}
+	discovered := []model.Connection{
+		{Name: "web", Host: "web.tail1234.ts.net", Group: TailnetGroup, Tags: []string{"prod"}},
+		{Name: "db", Host: "db.tail1234.ts.net", Group: TailnetGroup, Tags: []string{"prod"}},
+		{Name: "Laptop", Host: "laptop.tail1234.ts.net", Group: TailnetGroup},
+		{Name: "nas", Host: "nas.tail1234.ts.net", Group: TailnetGroup},
+	}
+
+	added, updated, skipped := MergeTailnet(existing, discovered)
+	if len(added) != 1 || added[0].Name != "nas" {
+		t.Errorf("added = %+v, want nas", added)
+	}
+	if skipped != 1 {
+		t.Errorf("skipped = %d, want 1 (laptop outside the Tailnet group)", skipped)
+	}
+	if len(updated) != 1 {
+		t.Fatalf("updated = %+v, want web only", updated)
+	}
+	web := updated[0]
+	if web.Host != "web.tail1234.ts.net" || web.KeyPath != "/keys/id" || len(web.Tags) != 2 {
+		t.Errorf("updated web = %+v, want new host with credentials and tags kept", web)
+	}
+}
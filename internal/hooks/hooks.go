@@ -0,0 +1,263 @@
+// Package hooks delivers event notifications to user-configured commands
+// and webhooks
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+
+	"gossh/internal/model"
+)
+
+// Event identifies what happened
+type Event string
+
+const (
+	EventExecComplete  Event = "exec.complete"  // A batch exec finished
+	EventHealthChange  Event = "health.change"  // A connection became reachable or unreachable
+	EventHostKeyChange Event = "hostkey.change" // A server presented a different host key
+
+	EventPreConnect     Event = "session.pre_connect"     // A session is about to start
+	EventPostDisconnect Event = "session.post_disconnect" // A session ended
+)
+
+// defaultTimeout bounds how long a single hook may run
+const defaultTimeout = 10 * time.Second
+
+// connectionTimeout bounds a connection's own pre_connect and
+// post_disconnect commands, which may bring up a VPN or the like
+const connectionTimeout = time.Minute
+
+// Payload is the JSON document delivered to hooks. Text holds a one-line
+// summary so Slack-compatible webhooks can display it as is.
+type Payload struct {
+	Event      Event          `json:"event"`
+	Time       time.Time      `json:"time"`
+	Text       string         `json:"text"`
+	Connection string         `json:"connection,omitempty"`
+	Host       string         `json:"host,omitempty"`
+	Data       map[string]any `json:"data,omitempty"`
+}
+
+// NewPayload creates a payload for an event, optionally about a connection
+func NewPayload(event Event, conn *model.Connection, text string) Payload {
+	p := Payload{
+		Event: event,
+		Time:  time.Now(),
+		Text:  text,
+	}
+	if conn != nil {
+		p.Connection = conn.Name
+		p.Host = conn.Host
+	}
+	return p
+}
+
+// Dispatcher runs the hooks matching each fired event
+type Dispatcher struct {
+	hooks  []model.Hook
+	client *http.Client
+}
+
+// NewDispatcher creates a dispatcher for the configured hooks
+func NewDispatcher(hooks []model.Hook) *Dispatcher {
+	return &Dispatcher{
+		hooks:  hooks,
+		client: &http.Client{},
+	}
+}
+
+// Matching returns the hooks subscribed to an event
+func (d *Dispatcher) Matching(event Event) []model.Hook {
+	var matched []model.Hook
+	for _, h := range d.hooks {
+		if h.Event == string(event) || h.Event == "*" {
+			matched = append(matched, h)
+		}
+	}
+	return matched
+}
+
+// Fire runs every hook subscribed to the payload's event concurrently and
+// waits for them to finish. Failures are returned, not fatal.
+func (d *Dispatcher) Fire(p Payload) []error {
+	if d == nil {
+		return nil
+	}
+	matched := d.Matching(p.Event)
+	if len(matched) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		return []error{err}
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, h := range matched {
+		wg.Add(1)
+		go func(h model.Hook) {
+			defer wg.Done()
+			if err := d.run(h, p, body); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(h)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// run delivers the payload to a single hook
+func (d *Dispatcher) run(h model.Hook, p Payload, body []byte) error {
+	timeout := defaultTimeout
+	if h.Timeout > 0 {
+		timeout = time.Duration(h.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if h.Command != "" {
+		if err := runCommand(ctx, h.Command, p, body); err != nil {
+			return fmt.Errorf("hook %q: %w", h.Command, err)
+		}
+	}
+	if h.URL != "" {
+		if err := d.post(ctx, h.URL, body); err != nil {
+			return fmt.Errorf("hook %s: %w", h.URL, err)
+		}
+	}
+	return nil
+}
+
+// runCommand runs a shell command with the payload on stdin and the
+// key fields in the environment
+func runCommand(ctx context.Context, command string, p Payload, body []byte) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(),
+		"GOSSH_EVENT="+string(p.Event),
+		"GOSSH_TEXT="+p.Text,
+		"GOSSH_CONNECTION="+p.Connection,
+		"GOSSH_HOST="+p.Host,
+	)
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if len(out) > 0 {
+			return fmt.Errorf("%w: %s", err, bytes.TrimSpace(out))
+		}
+		return err
+	}
+	return nil
+}
+
+// RunConnectionCommand runs a connection's pre_connect or post_disconnect
+// command with the payload of the session event, as hook commands get it
+func RunConnectionCommand(command string, p Payload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), connectionTimeout)
+	defer cancel()
+	return runCommand(ctx, command, p, body)
+}
+
+// post sends the payload as JSON to a webhook URL
+func (d *Dispatcher) post(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gossh")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// HealthChange builds the payload for a health status transition. It
+// reports false when the status is unchanged or there is no earlier result
+// to compare against, so the first check of a host never alerts.
+func HealthChange(conn model.Connection, previous, current model.ConnStatus, cause error) (Payload, bool) {
+	if previous == current || previous == "" || previous == model.ConnStatusUnknown {
+		return Payload{}, false
+	}
+
+	addr := fmt.Sprintf("%s:%d", conn.Host, conn.Port)
+	text := fmt.Sprintf("%s (%s) is reachable again", conn.Name, addr)
+	if current == model.ConnStatusFailed {
+		text = fmt.Sprintf("%s (%s) is unreachable", conn.Name, addr)
+		if cause != nil {
+			text += ": " + cause.Error()
+		}
+	}
+
+	p := NewPayload(EventHealthChange, &conn, text)
+	p.Data = map[string]any{
+		"previous": string(previous),
+		"status":   string(current),
+	}
+	return p, true
+}
+
+// HostKeyChange builds the payload for conn presenting the host key with
+// fingerprint instead of the previous one
+func HostKeyChange(conn model.Connection, previous, fingerprint string) Payload {
+	p := NewPayload(EventHostKeyChange, &conn,
+		fmt.Sprintf("%s (%s:%d) presented a different host key", conn.Name, conn.Host, conn.Port))
+	p.Data = map[string]any{
+		"previous":    previous,
+		"fingerprint": fingerprint,
+	}
+	return p
+}
+
+// PreConnect builds the payload for a session about to start on conn
+func PreConnect(conn model.Connection) Payload {
+	text := fmt.Sprintf("Connecting to %s (%s@%s:%d)", conn.Name, conn.User, conn.Host, conn.Port)
+	return NewPayload(EventPreConnect, &conn, text)
+}
+
+// PostDisconnect builds the payload for a session on conn that ended with
+// exit code code, or with cause when the connection failed
+func PostDisconnect(conn model.Connection, code int, cause error) Payload {
+	text := fmt.Sprintf("Session on %s exited %d", conn.Name, code)
+	data := map[string]any{"exit_code": code}
+	if cause != nil {
+		text = fmt.Sprintf("Session on %s failed: %v", conn.Name, cause)
+		data = map[string]any{"error": cause.Error()}
+	}
+	p := NewPayload(EventPostDisconnect, &conn, text)
+	p.Data = data
+	return p
+}
@@ -0,0 +1,155 @@
+package hooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"gossh/internal/model"
+)
+
+func TestDispatcherMatching(t *testing.T) {
+	d := NewDispatcher([]model.Hook{
+		{Event: "exec.complete", Command: "a"},
+		{Event: "*", Command: "b"},
+		{Event: "health.change", Command: "c"},
+	})
+
+	got := d.Matching(EventExecComplete)
+	if len(got) != 2 || got[0].Command != "a" || got[1].Command != "b" {
+		t.Errorf("Matching(exec.complete) = %+v, want hooks a and b", got)
+	}
+	if got := d.Matching(EventHostKeyChange); len(got) != 1 {
+		t.Errorf("Matching(hostkey.change) returned %d hooks, want 1", len(got))
+	}
+}
+
+func TestDispatcherPostsWebhook(t *testing.T) {
+	var received Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	d := NewDispatcher([]model.Hook{{Event: "health.change", URL: server.URL}})
+	conn := model.Connection{Name: "web", Host: "10.0.0.1"}
+	if errs := d.Fire(NewPayload(EventHealthChange, &conn, "web is down")); len(errs) != 0 {
+		t.Fatalf("Fire() errors = %v", errs)
+	}
+
+	if received.Event != EventHealthChange || received.Text != "web is down" || received.Connection != "web" {
+		t.Errorf("received payload = %+v", received)
+	}
+}
+
+func TestDispatcherWebhookError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	d := NewDispatcher([]model.Hook{{Event: "*", URL: server.URL}})
+	if errs := d.Fire(NewPayload(EventExecComplete, nil, "done")); len(errs) != 1 {
+		t.Errorf("Fire() returned %d errors, want 1", len(errs))
+	}
+}
+
+func TestDispatcherRunsCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+
+	out := filepath.Join(t.TempDir(), "out")
+	d := NewDispatcher([]model.Hook{{
+		Event:   "exec.complete",
+		Command: `printf '%s ' "$GOSSH_EVENT" > ` + out + ` && cat >> ` + out,
+	}})
+	if errs := d.Fire(NewPayload(EventExecComplete, nil, "3 hosts ok")); len(errs) != 0 {
+		t.Fatalf("Fire() errors = %v", errs)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("Failed to read hook output: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "exec.complete {") || !strings.Contains(string(data), `"text":"3 hosts ok"`) {
+		t.Errorf("hook output = %q", data)
+	}
+}
+
+func TestDispatcherNoHooks(t *testing.T) {
+	var d *Dispatcher
+	if errs := d.Fire(NewPayload(EventExecComplete, nil, "")); errs != nil {
+		t.Errorf("nil dispatcher Fire() = %v, want nil", errs)
+	}
+}
+
+func TestHealthChange(t *testing.T) {
+	conn := model.Connection{Name: "web", Host: "10.0.0.1", Port: 22}
+
+	tests := []struct {
+		name     string
+		previous model.ConnStatus
+		current  model.ConnStatus
+		want     bool
+	}{
+		{"first check", "", model.ConnStatusFailed, false},
+		{"unknown before", model.ConnStatusUnknown, model.ConnStatusFailed, false},
+		{"unchanged", model.ConnStatusSuccess, model.ConnStatusSuccess, false},
+		{"went down", model.ConnStatusSuccess, model.ConnStatusFailed, true},
+		{"came back", model.ConnStatusFailed, model.ConnStatusSuccess, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := HealthChange(conn, tt.previous, tt.current, nil)
+			if ok != tt.want {
+				t.Fatalf("HealthChange() ok = %v, want %v", ok, tt.want)
+			}
+			if ok && (p.Event != EventHealthChange || p.Data["status"] != string(tt.current)) {
+				t.Errorf("HealthChange() payload = %+v", p)
+			}
+		})
+	}
+}
+
+func TestHostKeyChange(t *testing.T) {
+	conn := model.Connection{Name: "web", Host: "10.0.0.1", Port: 22}
+	p := HostKeyChange(conn, "SHA256:old", "SHA256:new")
+	if p.Event != EventHostKeyChange || p.Connection != "web" || p.Data["previous"] != "SHA256:old" || p.Data["fingerprint"] != "SHA256:new" {
+		t.Errorf("HostKeyChange() payload = %+v", p)
+	}
+}
+
+func TestRunConnectionCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+
+	conn := model.Connection{Name: "web", Host: "10.0.0.1", Port: 22}
+	out := filepath.Join(t.TempDir(), "out")
+	p := PostDisconnect(conn, 130, nil)
+	if err := RunConnectionCommand(`printf '%s %s ' "$GOSSH_EVENT" "$GOSSH_CONNECTION" > `+out+` && cat >> `+out, p); err != nil {
+		t.Fatalf("RunConnectionCommand failed: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("Failed to read command output: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "session.post_disconnect web {") || !strings.Contains(string(data), `"exit_code":130`) {
+		t.Errorf("command output = %q", data)
+	}
+
+	err = RunConnectionCommand("echo no vpn >&2; exit 1", PreConnect(conn))
+	if err == nil || !strings.Contains(err.Error(), "no vpn") {
+		t.Errorf("failing command error = %v, want it to carry the command's output", err)
+	}
+}
@@ -1,7 +1,15 @@
 package i18n
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
+
+	"gopkg.in/yaml.v3"
 )
 
 // Language represents a supported language
@@ -10,6 +18,10 @@ type Language string
 const (
 	LangEN Language = "en"
 	LangZH Language = "zh"
+	LangJA Language = "ja"
+	LangES Language = "es"
+	LangDE Language = "de"
+	LangRU Language = "ru"
 )
 
 var (
@@ -17,12 +29,31 @@ var (
 	mu          sync.RWMutex
 )
 
-// translations holds all translation messages
+// translations holds all translation messages. English and Chinese carry
+// every key; the other built-in languages leave out the cli.* output of
+// commands, which T shows in English for them.
 var translations = map[Language]map[string]string{
 	LangEN: messagesEN,
 	LangZH: messagesZH,
+	LangJA: messagesJA,
+	LangES: messagesES,
+	LangDE: messagesDE,
+	LangRU: messagesRU,
+}
+
+// languageNames holds the display name of each registered language
+var languageNames = map[Language]string{
+	LangEN: "English",
+	LangZH: "中文",
+	LangJA: "日本語",
+	LangES: "Español",
+	LangDE: "Deutsch",
+	LangRU: "Русский",
 }
 
+// builtinOrder is the display order of the built-in languages
+var builtinOrder = []Language{LangEN, LangZH, LangJA, LangES, LangDE, LangRU}
+
 // SetLanguage sets the current language
 func SetLanguage(lang Language) {
 	mu.Lock()
@@ -83,19 +114,169 @@ func TWithLang(key string, lang Language) string {
 	return key
 }
 
-// SupportedLanguages returns a list of supported languages
+// SupportedLanguages returns a list of supported languages.
+// Built-in languages come first, followed by runtime-registered ones sorted by code.
 func SupportedLanguages() []Language {
-	return []Language{LangEN, LangZH}
+	mu.RLock()
+	defer mu.RUnlock()
+
+	langs := make([]Language, 0, len(translations))
+	builtin := make(map[Language]bool, len(builtinOrder))
+	for _, lang := range builtinOrder {
+		builtin[lang] = true
+		langs = append(langs, lang)
+	}
+
+	var extra []Language
+	for lang := range translations {
+		if !builtin[lang] {
+			extra = append(extra, lang)
+		}
+	}
+	sort.Slice(extra, func(i, j int) bool { return extra[i] < extra[j] })
+
+	return append(langs, extra...)
 }
 
 // LanguageName returns the display name of a language
 func LanguageName(lang Language) string {
-	switch lang {
-	case LangEN:
-		return "English"
-	case LangZH:
-		return "中文"
+	mu.RLock()
+	defer mu.RUnlock()
+	if name, ok := languageNames[lang]; ok && name != "" {
+		return name
+	}
+	return string(lang)
+}
+
+// Register adds or extends a language at runtime.
+// Messages are merged over any existing translations for the language.
+func Register(lang Language, name string, messages map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	msgs, ok := translations[lang]
+	if !ok {
+		msgs = make(map[string]string, len(messages))
+		translations[lang] = msgs
+	}
+	for k, v := range messages {
+		msgs[k] = v
+	}
+
+	if name != "" {
+		languageNames[lang] = name
+	} else if _, ok := languageNames[lang]; !ok {
+		languageNames[lang] = string(lang)
+	}
+}
+
+// localeFile is the on-disk format of an external translation file
+type localeFile struct {
+	Name     string            `yaml:"name" json:"name"`
+	Messages map[string]string `yaml:"messages" json:"messages"`
+}
+
+// LoadFile loads a translation file (YAML or JSON). The file name without
+// extension is used as the language code, e.g. "fr.yaml" registers "fr".
+func LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var lf localeFile
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".json":
+		err = json.Unmarshal(data, &lf)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &lf)
 	default:
-		return string(lang)
+		return fmt.Errorf("unsupported locale file: %s", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	code := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if code == "" {
+		return fmt.Errorf("invalid locale file name: %s", path)
+	}
+
+	Register(Language(code), lf.Name, lf.Messages)
+	return nil
+}
+
+// LoadDir loads every *.yaml, *.yml and *.json translation file in dir.
+// A missing directory is not an error. Files that fail to parse are skipped
+// and reported in the returned error.
+func LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var failed []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		if err := LoadFile(filepath.Join(dir, entry.Name())); err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to load locales: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// DetectLanguage returns the supported language matching the LC_ALL,
+// LC_MESSAGES or LANG environment variables, falling back to English
+func DetectLanguage() Language {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+		if lang, ok := parseLocale(value); ok {
+			return lang
+		}
+	}
+	return LangEN
+}
+
+// parseLocale maps a POSIX locale such as "zh_CN.UTF-8" to a registered language
+func parseLocale(locale string) (Language, bool) {
+	if locale == "C" || locale == "POSIX" {
+		return "", false
+	}
+
+	// Strip encoding and modifier: zh_CN.UTF-8@euro -> zh_CN
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	locale = strings.ReplaceAll(locale, "-", "_")
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	// Prefer an exact match (e.g. a registered "pt_BR"), then the language part
+	if _, ok := translations[Language(locale)]; ok {
+		return Language(locale), true
+	}
+	base := strings.ToLower(strings.SplitN(locale, "_", 2)[0])
+	if _, ok := translations[Language(base)]; ok {
+		return Language(base), true
 	}
+	return "", false
 }
@@ -1,6 +1,10 @@
 package i18n
 
 import (
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -101,8 +105,11 @@ func TestTWithLang(t *testing.T) {
 func TestSupportedLanguages(t *testing.T) {
 	langs := SupportedLanguages()
 
-	if len(langs) != 2 {
-		t.Errorf("SupportedLanguages() returned %d languages, want 2", len(langs))
+	if len(langs) < 6 {
+		t.Errorf("SupportedLanguages() returned %d languages, want at least 6", len(langs))
+	}
+	if langs[0] != LangEN {
+		t.Errorf("SupportedLanguages()[0] = %v, want %v", langs[0], LangEN)
 	}
 
 	hasEN := false
@@ -131,6 +138,10 @@ func TestLanguageName(t *testing.T) {
 	}{
 		{LangEN, "English"},
 		{LangZH, "中文"},
+		{LangJA, "日本語"},
+		{LangES, "Español"},
+		{LangDE, "Deutsch"},
+		{LangRU, "Русский"},
 		{Language("unknown"), "unknown"},
 	}
 
@@ -159,3 +170,137 @@ func TestAllKeysExistInBothLanguages(t *testing.T) {
 		}
 	}
 }
+
+func TestBuiltinLanguagesUseKnownKeys(t *testing.T) {
+	for _, lang := range []Language{LangJA, LangES, LangDE, LangRU} {
+		for key := range translations[lang] {
+			if _, ok := messagesEN[key]; !ok {
+				t.Errorf("Key %q exists in %s but not in English", key, lang)
+			}
+		}
+	}
+}
+
+// partialPrefix starts the keys the built-in languages other than English
+// and Chinese leave to the English fallback
+const partialPrefix = "cli."
+
+func TestCatalogKeys(t *testing.T) {
+	for _, lang := range builtinOrder {
+		if lang == LangEN {
+			continue
+		}
+		msgs := translations[lang]
+		for key := range messagesEN {
+			partial := lang != LangZH && strings.HasPrefix(key, partialPrefix)
+			if _, ok := msgs[key]; !ok && !partial {
+				t.Errorf("%s: missing %s", lang, key)
+			}
+		}
+		for key := range msgs {
+			if _, ok := messagesEN[key]; !ok {
+				t.Errorf("%s: %s is not an English key", lang, key)
+			}
+		}
+	}
+}
+
+// restoreLanguages puts the registered languages back as they were once
+// the test ends, so languages it loads don't leak into other tests
+func restoreLanguages(t *testing.T) {
+	mu.Lock()
+	saved := make(map[Language]map[string]string, len(translations))
+	for lang, msgs := range translations {
+		saved[lang] = maps.Clone(msgs)
+	}
+	names := maps.Clone(languageNames)
+	mu.Unlock()
+
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		translations = saved
+		languageNames = names
+	})
+}
+
+func TestLoadDir(t *testing.T) {
+	original := GetLanguage()
+	defer SetLanguage(original)
+	restoreLanguages(t)
+
+	dir := t.TempDir()
+	yamlData := "name: Français\nmessages:\n  menu.quit: Quitter\n"
+	if err := os.WriteFile(filepath.Join(dir, "fr.yaml"), []byte(yamlData), 0600); err != nil {
+		t.Fatal(err)
+	}
+	jsonData := `{"name": "Italiano", "messages": {"menu.quit": "Esci"}}`
+	if err := os.WriteFile(filepath.Join(dir, "it.json"), []byte(jsonData), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.txt"), []byte("ignored"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LoadDir(dir); err != nil {
+		t.Fatalf("LoadDir returned error: %v", err)
+	}
+
+	if got := TWithLang("menu.quit", "fr"); got != "Quitter" {
+		t.Errorf("TWithLang(fr) = %q, want %q", got, "Quitter")
+	}
+	if got := TWithLang("menu.quit", "it"); got != "Esci" {
+		t.Errorf("TWithLang(it) = %q, want %q", got, "Esci")
+	}
+	if got := LanguageName("fr"); got != "Français" {
+		t.Errorf("LanguageName(fr) = %q, want %q", got, "Français")
+	}
+
+	// Missing keys fall back to English
+	SetLanguage("fr")
+	if got := T("app.welcome"); got != "Welcome to GoSSH" {
+		t.Errorf("T(app.welcome) = %q, want English fallback", got)
+	}
+
+	// Missing directory is not an error
+	if err := LoadDir(filepath.Join(dir, "missing")); err != nil {
+		t.Errorf("LoadDir on missing dir returned error: %v", err)
+	}
+}
+
+func TestLoadDirInvalidFile(t *testing.T) {
+	restoreLanguages(t)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "xx.json"), []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadDir(dir); err == nil {
+		t.Error("Expected error for invalid locale file")
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name  string
+		lcAll string
+		lang  string
+		want  Language
+	}{
+		{"LANG with encoding", "", "zh_CN.UTF-8", LangZH},
+		{"LC_ALL takes precedence", "de_DE.UTF-8", "ja_JP.UTF-8", LangDE},
+		{"C locale falls back", "", "C", LangEN},
+		{"unsupported locale", "", "xx_XX.UTF-8", LangEN},
+		{"unset", "", "", LangEN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LC_ALL", tt.lcAll)
+			t.Setenv("LC_MESSAGES", "")
+			t.Setenv("LANG", tt.lang)
+			if got := DetectLanguage(); got != tt.want {
+				t.Errorf("DetectLanguage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,565 @@
+package i18n
+
+// messagesDE contains German translations. It leaves out the cli.* output of
+// commands, which is shown in English (see TestCatalogKeys).
+var messagesDE = map[string]string{
+	// App general
+	"app.name":    "GoSSH",
+	"app.version": "Version",
+	"app.welcome": "Willkommen bei GoSSH",
+
+	// Menu and navigation
+	"menu.connections": "Verbindungen",
+	"menu.settings":    "Einstellungen",
+	"menu.help":        "Hilfe",
+	"menu.quit":        "Beenden",
+
+	// Connection list
+	"list.title":              "SSH-Verbindungen",
+	"list.empty":              "Noch keine Verbindungen. Drücke 'a', um eine hinzuzufügen.",
+	"list.empty.search":       "Keine passenden Verbindungen.",
+	"list.search":             "Suchen",
+	"list.filter":             "Filter: %s (/ zum Suchen, esc zum Löschen)",
+	"list.filter.all":         "Alle",
+	"list.filter.group":       "Gruppe",
+	"list.total":              "Gesamt: %d Verbindungen",
+	"list.showing":            " (%d angezeigt)",
+	"list.ungrouped":          "Ohne Gruppe",
+	"list.status.unknown":     "?",
+	"list.status.ok":          "✓",
+	"list.status.fail":        "✗",
+	"list.status.checking":    "...",
+	"list.trashed":            "In den Papierkorb verschoben – in den Einstellungen wiederherstellbar",
+	"list.copied":             "Kopiert: %s",
+	"list.copy_failed":        "Kopieren fehlgeschlagen",
+	"list.stale.rotate":       "erneuern",
+	"list.stale.expired":      "abgelaufen",
+	"list.uptime":             "seit %s online",
+	"list.via":                "(über %s)",
+	"list.column.name":        "Name",
+	"list.column.host":        "Benutzer@Host",
+	"list.column.group":       "Gruppe",
+	"list.column.tags":        "Tags",
+	"list.column.last_seen":   "Zuletzt verbunden",
+	"list.column.status":      "Status",
+	"list.column.system":      "System",
+	"list.help":               "a:hinzufügen  e:bearbeiten  d:löschen  /:suchen  s:Einstellungen  t:testen  y:kopieren  enter:verbinden  ?:Hilfe  q:beenden",
+	"list.help.search":        "tippen zum Suchen  enter:bestätigen  esc:abbrechen",
+	"list.search.placeholder": "Suchen...",
+
+	// Connection form
+	"form.title.add":               "Verbindung hinzufügen",
+	"form.title.edit":              "Verbindung bearbeiten",
+	"form.name":                    "Name",
+	"form.name.hint":               "Ein Anzeigename für diese Verbindung",
+	"form.host":                    "Host",
+	"form.host.hint":               "Hostname oder IP-Adresse",
+	"form.type":                    "Protokoll",
+	"form.port":                    "Port",
+	"form.port.hint":               "SSH-Port (Standard: 22)",
+	"form.user":                    "Benutzername",
+	"form.user.hint":               "SSH-Benutzername",
+	"form.auth_type":               "Authentifizierung",
+	"form.auth.password":           "Passwort",
+	"form.auth.key":                "Privater Schlüssel",
+	"form.password":                "Passwort",
+	"form.password.hint":           "SSH-Passwort",
+	"form.key_path":                "Schlüsselpfad",
+	"form.key_path.hint":           "Pfad zur privaten Schlüsseldatei",
+	"form.key_passphrase":          "Schlüssel-Passphrase",
+	"form.key_pass.hint":           "Passphrase des privaten Schlüssels (falls vorhanden)",
+	"form.group":                   "Gruppe",
+	"form.group.hint":              "Verbindungsgruppe",
+	"form.tags":                    "Tags",
+	"form.tags.hint":               "Kommagetrennte Tags",
+	"form.startup_cmd":             "Startbefehl",
+	"form.notes":                   "Notizen",
+	"form.rotate_after":            "Erneuern nach",
+	"form.expires_at":              "Läuft ab",
+	"form.startup_cmd.hint":        "Befehl, der nach dem Verbinden ausgeführt wird",
+	"form.save":                    "Speichern",
+	"form.cancel":                  "Abbrechen",
+	"form.error.required":          "Dieses Feld ist erforderlich",
+	"form.error.port":              "Ungültige Portnummer",
+	"form.placeholder.name":        "Mein Server",
+	"form.placeholder.host":        "192.168.1.1 oder example.com",
+	"form.placeholder.optional":    "(optional)",
+	"form.placeholder.notes":       "Alles, was man über diesen Host wissen sollte",
+	"form.auth.opt.password":       "Passwort",
+	"form.auth.opt.key":            "Schlüssel",
+	"form.agent_key":               "Agent-Schlüssel",
+	"form.agent_key.any":           "beliebiger Schlüssel",
+	"form.agent_key.missing":       "%s (nicht im Agent)",
+	"form.note.toggle":             "(Leertaste zum Umschalten)",
+	"form.note.select":             "(Leertaste zum Auswählen)",
+	"form.note.optional":           "(optional)",
+	"form.note.revealed":           "(15 s sichtbar, ctrl+r blendet aus)",
+	"form.note.agent_key":          "(anzubietender ssh-agent-Schlüssel statt aller)",
+	"form.note.tags":               "(Leertaste oder Komma fügt hinzu, → vervollständigt)",
+	"form.aliases":                 "Aliasse",
+	"form.note.aliases":            "(weitere Namen zum Verbinden und Suchen)",
+	"form.addresses":               "Weitere Adressen",
+	"form.note.addresses":          "(der Reihe nach versucht, wenn der Host ausfällt)",
+	"form.note.key_path":           "(ctrl+o zum Durchsuchen)",
+	"form.note.host":               "(oder srv:_ssh._tcp.name / consul:dienst)",
+	"form.jump_hosts":              "Jump-Hosts",
+	"form.note.jump_hosts":         "(gespeicherte Verbindungen, der Reihe nach)",
+	"form.bind_address":            "Bind-Adresse",
+	"form.term":                    "Terminaltyp",
+	"form.locale":                  "Locale",
+	"form.local_dir":               "Lokales Verzeichnis",
+	"form.remote_dir":              "Entferntes Verzeichnis",
+	"form.note.bind_address":       "(lokale IP oder Schnittstelle, optional)",
+	"form.note.term":               "(anzufordernder TERM, Standard: lokal)",
+	"form.note.locale":             "(anzuforderndes LANG/LC_ALL, optional)",
+	"form.note.local_dir":          "(SFTP-Übertragungen beginnen hier, optional)",
+	"form.note.remote_dir":         "(SFTP öffnet hier, Standard: Home)",
+	"form.note.startup":            "(ein Befehl pro Zeile, läuft nach dem Verbinden)",
+	"form.pre_connect":             "Vor dem Verbinden",
+	"form.post_disconnect":         "Nach dem Trennen",
+	"form.note.pre_connect":        "(lokaler Befehl, die Sitzung startet, wenn er erfolgreich ist)",
+	"form.note.post_disconnect":    "(lokaler Befehl, läuft nach der Sitzung)",
+	"form.quiet_login":             "Stille Anmeldung",
+	"form.show_banner":             "Banner anzeigen",
+	"form.gssapi":                  "GSSAPI",
+	"form.note.quiet_login":        "(MOTD und letzte Anmeldung ausblenden)",
+	"form.note.show_banner":        "(zuerst den Hinweis des Servers anzeigen)",
+	"form.note.gssapi":             "(zuerst Kerberos versuchen, wenn ein Ticket vorhanden ist)",
+	"form.note.gssapi_unsupported": "(erfordert einen Build mit -tags gssapi)",
+	"form.opt.on":                  "an",
+	"form.opt.off":                 "aus",
+	"form.note.rotate_after":       "(Tage, optional)",
+	"form.help":                    "tab:nächstes Feld  enter:speichern  ctrl+t:testen  f1:Hilfe  esc:abbrechen",
+	"form.test.running":            "Verbindung wird getestet...",
+	"form.test.ok":                 "✓ Verbindung erfolgreich (%s)",
+	"form.test.host_key":           "Host-Schlüssel geändert",
+	"form.keys.title":              "Schlüsseldatei auswählen",
+	"form.keys.none":               "Keine privaten Schlüssel in %s gefunden",
+	"form.keys.encrypted":          "[verschlüsselt]",
+	"form.keys.help":               "↑/↓:bewegen  enter:auswählen  esc:abbrechen",
+
+	// Setup
+	"setup.title":                  "Willkommen bei GoSSH",
+	"setup.desc":                   "Wähle deinen Sicherheitsmodus:",
+	"setup.option.password":        "[1] Passwortschutz aktivieren (empfohlen)",
+	"setup.option.password.desc":   "Ein Master-Passwort festlegen, das bei jedem Start abgefragt wird",
+	"setup.option.nopassword":      "[2] Passwortschutz überspringen",
+	"setup.option.nopassword.desc": "Schneller Start, kein Passwort erforderlich",
+	"setup.password.title":         "Master-Passwort festlegen",
+	"setup.password.desc":          "Dieses Passwort verschlüsselt deine gespeicherten Zugangsdaten. Merke es dir!",
+	"setup.password.prompt":        "Master-Passwort eingeben",
+	"setup.password.confirm":       "Passwort bestätigen",
+	"setup.password.hint":          "Mindestens 8 Zeichen",
+	"setup.password.mismatch":      "Die Passwörter stimmen nicht überein",
+	"setup.password.weak":          "Das Passwort ist zu schwach",
+	"setup.password.strength":      "Passwortstärke",
+	"setup.complete":               "Einrichtung abgeschlossen!",
+	"setup.help.choose":            "↑/↓:auswählen  1/2:Schnellauswahl  enter:bestätigen  esc:beenden",
+	"setup.help.password":          "tab:nächstes Feld  enter:bestätigen  esc:zurück",
+	"setup.placeholder.password":   "Master-Passwort eingeben",
+	"setup.placeholder.confirm":    "Master-Passwort bestätigen",
+
+	// Unlock
+	"unlock.title":       "GoSSH gesperrt",
+	"unlock.prompt":      "Master-Passwort zum Entsperren eingeben:",
+	"unlock.label":       "Passwort:",
+	"unlock.error":       "Falsches Passwort",
+	"unlock.attempt":     "[Fehlversuche: %d]",
+	"unlock.attempts":    "verbleibende Versuche",
+	"unlock.failed":      "Zu viele Fehlversuche. Erneut versuchen in %s.",
+	"unlock.help":        "enter:entsperren  esc:beenden",
+	"unlock.placeholder": "Master-Passwort eingeben",
+	"lock.unprotected":   "Nichts zu sperren: lege zuerst in den Einstellungen ein Master-Passwort fest",
+
+	// Confirm dialog
+	"confirm.title":      "Bestätigen",
+	"confirm.delete":     "Verbindung löschen",
+	"confirm.delete.msg": "Diese Verbindung in den Papierkorb verschieben?",
+	"confirm.yes":        "Ja",
+	"confirm.no":         "Nein",
+	"confirm.help":       "y:ja  n:nein  tab:wechseln  enter:bestätigen  esc:abbrechen",
+	"confirm.default":    "Bist du sicher?",
+
+	// Help
+	"help.title":              "GoSSH-Hilfe",
+	"help.navigation":         "Navigation",
+	"help.connection":         "Verbindungsverwaltung",
+	"help.form":               "Formularnavigation",
+	"help.general":            "Allgemein",
+	"help.settings":           "Einstellungen",
+	"help.actions":            "Aktionen",
+	"help.key.up":             "Nach oben",
+	"help.key.down":           "Nach unten",
+	"help.key.top":            "Zum Anfang",
+	"help.key.bottom":         "Zum Ende",
+	"help.key.left":           "Nach links scrollen",
+	"help.key.right":          "Nach rechts scrollen",
+	"help.key.color_prev":     "Vorherige Farbe",
+	"help.key.color_next":     "Nächste Farbe",
+	"help.key.layout":         "Zwischen Zeilen und Tabelle wechseln",
+	"help.key.sort":           "Tabelle nach der nächsten Spalte sortieren",
+	"help.key.sort_reverse":   "Sortierreihenfolge umkehren",
+	"help.key.filters":        "Gespeicherte Filter",
+	"help.key.toggle_column":  "Spalte ein- oder ausblenden",
+	"help.key.rule_add":       "Gruppierungsregel hinzufügen",
+	"help.key.rule_delete":    "Ausgewählte Regel löschen",
+	"help.key.default_group":  "Standardgruppe festlegen",
+	"help.key.rule_preview":   "Regeln an bestehenden Verbindungen testen",
+	"help.key.rule_apply":     "Regeln auf bestehende Verbindungen anwenden",
+	"help.key.merge_local":    "Lokalen Wert behalten",
+	"help.key.merge_incoming": "Importierten Wert übernehmen",
+	"help.key.merge_apply":    "Mit den gewählten Werten importieren",
+	"help.key.dedupe_pick":    "Zu behaltende Verbindung wählen",
+	"help.key.dedupe_merge":   "Die anderen damit zusammenführen",
+	"help.key.search":         "Verbindungen suchen",
+	"help.key.connect":        "Mit ausgewähltem Server verbinden",
+	"help.key.enter":          "Verbinden / Auswählen",
+	"help.key.add":            "Neue Verbindung hinzufügen",
+	"help.key.edit":           "Ausgewählte Verbindung bearbeiten",
+	"help.key.delete":         "Ausgewählte Verbindung löschen",
+	"help.key.tab":            "Nächstes Feld",
+	"help.key.shifttab":       "Vorheriges Feld",
+	"help.key.space":          "Option umschalten / Auswahlliste öffnen",
+	"help.key.save":           "Speichern",
+	"help.key.cancel":         "Abbrechen",
+	"help.key.help":           "Diese Hilfe anzeigen",
+	"help.key.quit":           "Anwendung beenden",
+	"help.key.back":           "Zurück / Abbrechen",
+	"help.key.settings":       "Einstellungen",
+	"help.key.test":           "Verbindung testen",
+	"help.key.test_all":       "Alle Verbindungen im Hintergrund testen",
+	"help.key.broadcast":      "Gleichzeitig in die gelisteten Hosts tippen",
+	"help.key.exec":           "Einen Befehl auf den gelisteten Hosts ausführen",
+	"help.key.history":        "Änderungsverlauf der Verbindung",
+	"help.key.scrollback":     "Ausgabe der letzten Sitzung durchsuchen",
+	"help.key.plugins":        "Plugin für die Verbindung ausführen",
+	"help.key.details":        "Details und Anhänge",
+	"help.key.local":          "Lokalen Befehl ausführen",
+	"help.key.lock":           "Sperren: Schlüssel verwerfen, bis das Master-Passwort eingegeben wird",
+	"help.key.copy":           "SSH-Befehl kopieren",
+	"help.key.test_form":      "Eingegebene Verbindung testen",
+	"help.key.browse":         "Private Schlüsseldatei auswählen",
+	"help.key.reveal":         "Passwort und Schlüssel-Passphrase ein- oder ausblenden",
+	"help.key.open":           "Ausgewählten Eintrag öffnen",
+	"help.key.restore":        "Ausgewählte Verbindung wiederherstellen",
+	"help.key.purge":          "Endgültig löschen (zweimal drücken)",
+	"help.return":             "Beliebige Taste drücken, um zurückzukehren",
+	"help.scroll":             "↑/↓ blättern",
+
+	// Settings
+	"settings.title":                      "Einstellungen",
+	"settings.language":                   "Sprache",
+	"settings.security":                   "Sicherheit",
+	"settings.password.enable":            "Master-Passwort aktivieren",
+	"settings.password.change":            "Master-Passwort ändern",
+	"settings.password.disable":           "Master-Passwort deaktivieren",
+	"settings.about":                      "Über",
+	"settings.save":                       "Speichern",
+	"settings.cancel":                     "Abbrechen",
+	"settings.saved":                      "Einstellungen gespeichert",
+	"settings.help":                       "↑/↓: navigieren • enter: auswählen • ?: Hilfe • esc: zurück",
+	"settings.help.language":              "↑/↓: Sprache wählen • enter: bestätigen • esc: zurück",
+	"settings.help.password":              "tab/↑/↓: Feld wechseln • enter: bestätigen • esc: zurück",
+	"settings.help.password.disable":      "enter: bestätigen • esc: zurück",
+	"settings.import":                     "Verbindungen importieren",
+	"settings.export":                     "Verbindungen exportieren",
+	"settings.trash":                      "Papierkorb (%d)",
+	"settings.notify":                     "Benachrichtigungen: %s",
+	"settings.notify.off":                 "Aus",
+	"settings.notify.bell":                "Terminalglocke",
+	"settings.notify.desktop":             "Desktop",
+	"settings.keep_warm":                  "Verbindungen offen halten: %s",
+	"settings.keep_warm.off":              "Aus",
+	"settings.keep_warm.minutes":          "%d min",
+	"settings.scrollback":                 "Sitzungsausgabe behalten: %s",
+	"settings.key_cache":                  "Master-Schlüssel für die CLI merken: %s",
+	"settings.theme":                      "Design: %s",
+	"settings.theme.dark":                 "Dunkel",
+	"settings.theme.light":                "Hell",
+	"settings.connection":                 "Verbindungen & Terminal",
+	"settings.connection.title":           "Verbindungen & Terminal",
+	"settings.on":                         "An",
+	"settings.off":                        "Aus",
+	"settings.seconds":                    "%d s",
+	"settings.timeout":                    "Verbindungs-Timeout: %s",
+	"settings.timeout.prompt":             "Sekunden, die der Verbindungsaufbau dauern darf:",
+	"settings.default_port":               "Standardport: %d",
+	"settings.port.prompt":                "Port für neue SSH-Verbindungen:",
+	"settings.default_user":               "Standardbenutzer: %s",
+	"settings.default_user.none":          "keiner",
+	"settings.user.prompt":                "Benutzer für neue Verbindungen (leer für keinen):",
+	"settings.keepalive":                  "Keepalive-Intervall: %s",
+	"settings.keepalive.default":          "Standard (10 s)",
+	"settings.keepalive.prompt":           "Sekunden zwischen Keepalives in Sitzungen (0 für den Standard):",
+	"settings.health":                     "Hintergrund-Zustandsprüfung: %s",
+	"settings.health.off":                 "Aus",
+	"settings.health.minutes":             "alle %d min",
+	"settings.confirm_delete":             "Vor dem Löschen nachfragen: %s",
+	"settings.term":                       "Terminaltyp (TERM): %s",
+	"settings.term.local":                 "lokal",
+	"settings.term.prompt":                "TERM für Verbindungen ohne eigenen (leer für den lokalen):",
+	"settings.locale":                     "Locale: %s",
+	"settings.locale.none":                "unverändert",
+	"settings.locale.prompt":              "Locale für Verbindungen ohne eigene, z. B. C.UTF-8 (leer, um keine zu senden):",
+	"settings.rules":                      "Gruppierungsregeln (%d)",
+	"settings.rules.title":                "Gruppierungsregeln",
+	"settings.rules.empty":                "Keine Regeln. Drücke n, um Hosts wie \\.prod\\.example\\.com$ unter Production einzuordnen.",
+	"settings.rules.default":              "Standardgruppe: %s",
+	"settings.rules.default.none":         "keine",
+	"settings.rules.default.prompt":       "Gruppe für neue Verbindungen, die keine Regel einordnet (leer für keine):",
+	"settings.rules.pattern":              "Muster (regulärer Ausdruck für Name oder Host):",
+	"settings.rules.pattern.placeholder":  "z. B. \\.prod\\.example\\.com$",
+	"settings.rules.group":                "Gruppe:",
+	"settings.rules.tags":                 "Tags (kommagetrennt):",
+	"settings.rules.preview.title":        "Gruppierungsregeln: Probelauf",
+	"settings.rules.preview.empty":        "Die Regeln würden keine bestehende Verbindung ändern.",
+	"settings.rules.preview.count":        "%d bestehende Verbindungen würden sich ändern:",
+	"settings.rules.preview.more":         "… und %d weitere",
+	"settings.rules.applied":              "%d Verbindungen eingeordnet",
+	"settings.help.rules":                 "↑/↓: auswählen • n: hinzufügen • d: löschen • g: Standardgruppe • p: Probelauf • esc: zurück",
+	"settings.help.rules.add":             "tab: nächstes Feld • enter: speichern • esc: abbrechen",
+	"settings.help.rules.default":         "enter: speichern • esc: abbrechen",
+	"settings.help.rules.preview":         "enter: auf bestehende Verbindungen anwenden • esc: zurück",
+	"settings.help.value":                 "enter: speichern • esc: abbrechen",
+	"filters.title":                       "Gespeicherte Filter",
+	"filters.empty":                       "Keine gespeicherten Filter. Suche zuerst mit / und drücke dann hier n, um die Suche zu speichern.",
+	"filters.name":                        "Name:",
+	"filters.name.placeholder":            "z. B. prod web",
+	"filters.no_search":                   "Suche zuerst mit /, damit es etwas zu speichern gibt",
+	"filters.help":                        "enter:anwenden  n:aktuelle Suche speichern  d:löschen  esc:schließen",
+	"filters.help.naming":                 "enter:speichern  esc:abbrechen",
+	"plugins.title":                       "Plugins für %s",
+	"plugins.empty":                       "Keine Plugins. Lege ausführbare Dateien namens %s<befehl> in %s ab",
+	"plugins.help":                        "enter:ausführen  esc:schließen",
+	"details.title":                       "Details: %s",
+	"details.address":                     "Adresse",
+	"details.group":                       "Gruppe",
+	"details.tags":                        "Tags",
+	"details.password":                    "Passwort",
+	"details.key_passphrase":              "Schlüssel-Passphrase",
+	"details.agent_key":                   "Agent-Schlüssel",
+	"details.notes":                       "Notizen",
+	"details.attachments":                 "Anhänge",
+	"details.attachments.empty":           "Noch keine Anhänge",
+	"details.add.prompt":                  "Datei anhängen:",
+	"details.export.prompt":               "Exportieren nach:",
+	"details.added":                       "%s angehängt",
+	"details.deleted":                     "%s gelöscht",
+	"details.exported":                    "%s nach %s exportiert",
+	"details.exists":                      "Datei existiert, nicht überschrieben",
+	"details.not_file":                    "keine reguläre Datei",
+	"details.binary":                      "%s ist kein Text: exportiere die Datei, um sie zu öffnen",
+	"details.delete.confirm":              "%s löschen? (y/n)",
+	"details.revealed":                    "15 s sichtbar: ctrl+r blendet sie aus",
+	"details.help":                        "enter:ansehen  a:anhängen  x:exportieren  d:löschen  ctrl+r:Passwörter zeigen  esc:schließen",
+	"history.title":                       "Verlauf: %s",
+	"history.empty":                       "Noch keine Änderungen aufgezeichnet",
+	"history.restored":                    "Version vor der Änderung vom %s wiederhergestellt",
+	"history.help":                        "↑/↓: auswählen • r: diese Version wiederherstellen • esc: zurück",
+	"scrollback.title":                    "Ausgabe: %s",
+	"scrollback.position":                 "Zeilen %d-%d von %d",
+	"scrollback.dropped":                  "(ältere Ausgabe verworfen)",
+	"scrollback.empty":                    "Die Sitzung hat nichts ausgegeben",
+	"scrollback.help":                     "↑/↓/pgup/pgdn: blättern • g/G: Anfang/Ende • /: suchen • n/N: ältere/neuere Fundstelle • esc: zurück",
+	"scrollback.search.placeholder":       "zu suchender Text",
+	"scrollback.no_match":                 "Keine Zeile enthält \"%s\"",
+	"scrollback.off":                      "Sitzungsausgabe wird nicht behalten, aktiviere es in den Einstellungen",
+	"scrollback.none":                     "Noch keine Ausgabe einer Sitzung mit %s behalten",
+	"settings.trash.title":                "Papierkorb",
+	"settings.trash.empty":                "Der Papierkorb ist leer",
+	"settings.trash.info":                 "gelöscht %s · endgültig entfernt in %d Tagen",
+	"settings.trash.restored":             "'%s' wiederhergestellt",
+	"settings.trash.purged":               "'%s' endgültig gelöscht",
+	"settings.trash.confirm_purge":        "Drücke erneut p, um '%s' endgültig zu löschen",
+	"settings.groups":                     "Gruppenfarben",
+	"settings.groups.title":               "Gruppenfarben",
+	"settings.groups.empty":               "Es gibt keine Gruppen",
+	"settings.layout":                     "Listenlayout: %s",
+	"settings.layout.lines":               "Zeilen",
+	"settings.layout.table":               "Tabelle",
+	"settings.columns":                    "Tabellenspalten",
+	"settings.columns.note":               "Spalten erscheinen, wenn das Listenlayout Tabelle ist (v in der Liste drücken)",
+	"settings.transfer.path":              "Dateipfad",
+	"settings.transfer.format":            "Format",
+	"settings.transfer.format.yaml":       "GoSSH (YAML)",
+	"settings.transfer.format.ssh_config": "OpenSSH-Konfiguration",
+	"settings.transfer.note.import":       "Bereits vorhandene Verbindungen (nach Name, Alias oder Adresse) werden Feld für Feld zusammengeführt",
+	"settings.import.result":              "Import abgeschlossen",
+	"settings.export.result":              "Export abgeschlossen",
+	"settings.transfer.file":              "Datei",
+	"settings.transfer.imported":          "Importiert",
+	"settings.transfer.skipped":           "Übersprungen",
+	"settings.transfer.merged":            "Zusammengeführt",
+	"settings.merge.title":                "Importkonflikte: %d",
+	"settings.merge.summary":              "%d neue Verbindungen werden hinzugefügt und %d sind unverändert. Wähle für jedes Feld den Wert, der bleiben soll.",
+	"settings.help.merge":                 "↑/↓: auswählen • ←: lokal • →: importiert • Leertaste: umschalten • enter: importieren • esc: abbrechen",
+	"settings.duplicates":                 "Duplikate (%d)",
+	"settings.duplicates.title":           "Doppelte Verbindungen",
+	"settings.duplicates.empty":           "Keine zwei Verbindungen melden sich mit demselben Benutzer, Host und Port an.",
+	"settings.duplicates.keep":            "behalten",
+	"settings.duplicates.done":            "%d Verbindungen in %s zusammengeführt; die anderen liegen im Papierkorb",
+	"settings.help.duplicates":            "↑/↓: Duplikatgruppe • ←/→: behalten • enter: zusammenführen • esc: zurück",
+	"settings.transfer.exported":          "Exportiert",
+	"settings.help.transfer":              "tab: Feld wechseln • ←/→: Format • enter: ausführen • esc: zurück",
+	"settings.help.result":                "enter/esc: zurück",
+	"settings.help.trash":                 "↑/↓: auswählen • r/enter: wiederherstellen • p: endgültig löschen • esc: zurück",
+	"settings.help.groups":                "↑/↓: auswählen • ←/→: Farbe ändern • esc: zurück",
+	"settings.help.columns":               "↑/↓: auswählen • Leertaste: ein-/ausblenden • esc: zurück",
+
+	// Host key verification
+	"hostkey.title":       "Host-Schlüssel-Prüfung",
+	"hostkey.unknown":     "Unbekannter Host",
+	"hostkey.unknown.msg": "Die Echtheit des Hosts '%s' kann nicht festgestellt werden.",
+	"hostkey.fingerprint": "Fingerabdruck",
+	"hostkey.keytype":     "Schlüsseltyp",
+	"hostkey.trust":       "Diesem Host vertrauen und die Verbindung fortsetzen?",
+	"hostkey.changed":     "WARNUNG: Host-Schlüssel geändert!",
+	"hostkey.changed.msg": "Der Host-Schlüssel für '%s' hat sich geändert. Dies könnte auf einen Man-in-the-Middle-Angriff hindeuten!",
+	"hostkey.accept":      "Akzeptieren",
+	"hostkey.reject":      "Ablehnen",
+	"hostkey.update":      "Aktualisieren",
+	"hostkey.help":        "y:akzeptieren  n:ablehnen  enter:bestätigen",
+	"banner.title":        "Banner von %s",
+	"banner.help":         "enter:fortfahren  esc:abbrechen",
+	"hostkey.previous":    "Bisheriger Fingerabdruck:",
+
+	// Health check
+	"health.title":              "Verbindungstest",
+	"health.testing":            "Verbindung wird getestet...",
+	"health.all.progress":       "Prüfe Verbindungen... %d/%d",
+	"health.all.done":           "%d Verbindungen geprüft: %d erreichbar, %d nicht erreichbar",
+	"broadcast.title.all":       "Broadcast an %d Hosts",
+	"broadcast.title.single":    "Eingabe nur an %s",
+	"broadcast.help":            "ctrl+n/ctrl+p:Host wechseln  ctrl+t:alle/einer  ctrl+q:schließen",
+	"broadcast.confirm":         "Broadcast",
+	"broadcast.confirm.msg":     "Auf %d Hosts eine Shell öffnen und in alle gleichzeitig tippen?",
+	"broadcast.closed":          "Broadcast beendet",
+	"broadcast.dropped":         "%d Eingaben verworfen: dieser Host kommt nicht hinterher",
+	"exec.title":                "Auf %d Hosts ausführen",
+	"exec.command":              "Befehl:",
+	"exec.command.placeholder":  "uptime",
+	"exec.progress":             "%d/%d fertig · %d laufen · %d fehlgeschlagen",
+	"exec.eta":                  "noch etwa %s",
+	"exec.exit":                 "Exit %d",
+	"exec.finished":             "%d erfolgreich, %d fehlgeschlagen",
+	"exec.done":                 "Befehl beendet: %d erfolgreich, %d fehlgeschlagen",
+	"exec.stopping":             "Befehl wird auf den Hosts gestoppt...",
+	"exec.help.input":           "enter: ausführen • esc: abbrechen",
+	"exec.help.running":         "↑/↓: Host wählen • esc: stoppen",
+	"exec.help.done":            "↑/↓: Host wählen • esc: zurück",
+	"local.title":               "Lokaler Befehl",
+	"local.command":             "Befehl:",
+	"local.command.placeholder": "leer für eine Shell, exit zum Zurückkehren",
+	"local.help":                "enter: ausführen • esc: abbrechen",
+	"local.return":              "Enter drücken, um zu gossh zurückzukehren",
+	"local.exit":                "Lokaler Befehl beendet mit Status %d",
+	"health.checking":           "Prüfe...",
+	"health.reachable":          "Erreichbar",
+	"health.unreachable":        "Nicht erreichbar",
+	"health.auth_failed":        "Authentifizierung fehlgeschlagen",
+	"health.result.success":     "✓ Verbindung erfolgreich",
+	"health.result.fail":        "✗ Verbindung fehlgeschlagen",
+
+	// SFTP
+	"sftp.connected":   "SFTP mit %s verbunden",
+	"sftp.pwd":         "Aktuelles Verzeichnis: %s",
+	"sftp.uploading":   "Hochladen: %s",
+	"sftp.downloading": "Herunterladen: %s",
+	"sftp.progress":    "%d%% (%s / %s)",
+	"sftp.complete":    "Übertragung abgeschlossen",
+
+	// Import
+	"import.title":          "SSH-Konfiguration importieren",
+	"import.reading":        "Lese %s...",
+	"import.found":          "%d Verbindungen gefunden",
+	"import.importing":      "Importiere...",
+	"import.skip.duplicate": "Überspringe Duplikat: %s",
+	"import.complete":       "Import abgeschlossen: %d importiert, %d übersprungen",
+	"wizard.title":          "SSH-Hosts importieren",
+	"wizard.desc":           "%d neue Hosts in %s gefunden. Wähle die zu importierenden Hosts:",
+	"wizard.selected":       "%d von %d ausgewählt",
+	"wizard.help":           "↑/↓:bewegen  Leertaste:umschalten  a:alle  g:Gruppe wechseln  G:Gruppe für alle  enter:importieren  esc:überspringen",
+
+	// Errors
+	"error.connection":                  "Verbindung fehlgeschlagen",
+	"error.auth":                        "Authentifizierung fehlgeschlagen",
+	"error.timeout":                     "Zeitüberschreitung der Verbindung",
+	"error.unknown":                     "Unbekannter Fehler",
+	"error.validation.name":             "Name ist erforderlich",
+	"error.validation.host":             "Host ist erforderlich",
+	"error.validation.host_invalid":     "Host muss ein Hostname oder eine IP-Adresse sein",
+	"error.validation.aliases":          "Aliasse müssen einzelne Wörter und vom Namen verschieden sein",
+	"error.validation.addresses":        "weitere Adressen müssen Hostnamen oder IP-Adressen sein",
+	"error.validation.user":             "Benutzer ist erforderlich",
+	"error.validation.port":             "Port muss zwischen 1 und 65535 liegen",
+	"error.validation.key_path":         "Schlüsselpfad ist für Schlüssel-Authentifizierung erforderlich",
+	"error.validation.key_file":         "Schlüsseldatei ist nicht lesbar oder kein privater Schlüssel",
+	"error.validation.key_passphrase":   "der Schlüssel ist verschlüsselt, gib seine Passphrase ein",
+	"error.validation.agent_key":        "Agent-Schlüssel muss ein SHA256-Fingerabdruck sein, wie ssh-add -l ihn ausgibt",
+	"error.validation.term":             "Terminaltyp muss ein Wort sein, z. B. vt100",
+	"error.validation.locale":           "Locale muss ein Wort sein, z. B. C.UTF-8",
+	"error.validation.rotate_after":     "Erneuerungszeitraum muss eine positive Anzahl von Tagen sein",
+	"error.validation.expires_at":       "Ablaufdatum muss im Format JJJJ-MM-TT sein",
+	"error.validation.pattern":          "Muster muss ein gültiger regulärer Ausdruck sein",
+	"error.validation.rule":             "eine Regel braucht eine Gruppe oder Tags",
+	"error.validation.workspace":        "ein Arbeitsbereich braucht Verbindungen oder Tunnel",
+	"error.validation.tunnel":           "ein Tunnel braucht eine Verbindung, -L oder -R und eine Weiterleitungsangabe",
+	"error.validation.forward_profiles": "ein Weiterleitungsprofil braucht einen Namen ohne Leerzeichen und -L-, -R- oder -D-Angaben",
+	"error.validation.snippet":          "ein Snippet braucht einen Namen und einen Befehl",
+	"error.validation.cron":             "Zeitplan muss eine Cron-Angabe sein, z. B. 0 3 * * *",
+	"error.validation.command":          "Befehl ist erforderlich",
+	"error.validation.timeout":          "Timeout muss eine positive Anzahl von Sekunden sein",
+	"error.validation.interval":         "Intervall muss eine positive Zahl sein, oder 0 für den Standard",
+	"error.validation.theme":            "Design muss dark oder light sein",
+	"error.validation.default_user":     "Standardbenutzer muss ein Wort sein",
+	"error.password.invalid":            "ungültiges Passwort",
+	"error.password.weak":               "Passwort zu schwach: mindestens 8 Zeichen erforderlich",
+	"error.attachment.too_large":        "Anhänge sind auf %d KB begrenzt",
+	"error.attachment.empty":            "die Datei ist leer",
+	"error.attachment.exists":           "die Verbindung hat bereits einen Anhang dieses Namens",
+	"error.locked":                      "entsperre gossh zuerst",
+	"error.insecure_dir":                "%s ist für andere Benutzer lesbar: exportiere die Passwörter an einen privaten Ort",
+	"error.password.policy.length":      "Passwort zu schwach: mindestens %d Zeichen erforderlich",
+	"error.password.policy.classes":     "Passwort zu schwach: verwende mindestens %d aus Kleinbuchstaben, Großbuchstaben, Ziffern und Symbolen",
+	"error.password.policy.common":      "Passwort zu schwach: es ist ein gängiges Passwort oder enthält ein gesperrtes Wort",
+	"error.password.policy.score":       "Passwort zu leicht zu erraten: mach es länger oder nimm ein paar zusammenhanglose Wörter",
+
+	// Common
+	"common.loading":                 "Lade...",
+	"common.saving":                  "Speichere...",
+	"common.success":                 "Erfolg",
+	"common.error":                   "Fehler",
+	"common.back":                    "Zurück",
+	"common.next":                    "Weiter",
+	"common.done":                    "Fertig",
+	"common.connecting":              "Verbinde mit %s...",
+	"session.summary":                "%s: beendet mit %d nach %s",
+	"session.pre_connect.failed":     "pre_connect-Befehl fehlgeschlagen: %v",
+	"session.post_disconnect.failed": "post_disconnect-Befehl fehlgeschlagen: %v",
+	"session.suspended":              "%s angehalten — Enter darauf setzt die Sitzung fort",
+	"status.protected":               "Master-Passwort an",
+	"status.unprotected":             "kein Master-Passwort",
+	"status.sessions":                "angehaltene Sitzungen: %d",
+	"status.jobs":                    "Hintergrundaufgaben: %d",
+	"session.closed":                 "Verbindung zu %s geschlossen",
+	"common.conn_error":              "Verbindungsfehler: %s",
+	"common.state_error":             "Verbindungsstatus konnte nicht gespeichert werden: %v",
+	"report.title":                   "Verbindungsinventar",
+	"report.summary":                 "Erstellt %s: %d Host(s), %d mit Zugangsdaten, die Aufmerksamkeit brauchen.",
+	"report.hosts":                   "Hosts",
+	"report.groups":                  "Gruppen",
+	"report.tags":                    "Tags",
+	"report.name":                    "Name",
+	"report.address":                 "Adresse",
+	"report.group":                   "Gruppe",
+	"report.last_seen":               "Zuletzt verbunden",
+	"report.health":                  "Zustand",
+	"report.health.success":          "erreichbar",
+	"report.health.failed":           "nicht erreichbar",
+	"report.health.unknown":          "unbekannt",
+	"report.auth":                    "Auth",
+	"report.changed":                 "Zugangsdaten geändert",
+	"report.age":                     "Alter",
+	"report.credentials":             "Zugangsdaten",
+	"report.system":                  "System",
+}
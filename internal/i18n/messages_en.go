@@ -14,203 +14,989 @@ var messagesEN = map[string]string{
 	"menu.quit":        "Quit",
 
 	// Connection list
-	"list.title":           "SSH Connections",
-	"list.empty":           "No connections yet. Press 'a' to add one.",
-	"list.empty.search":    "No matching connections.",
-	"list.search":          "Search",
-	"list.filter":          "Filter: %s (press / to search, esc to clear)",
-	"list.filter.all":      "All",
-	"list.filter.group":    "Group",
-	"list.total":           "Total: %d connections",
-	"list.showing":         " (showing %d)",
-	"list.ungrouped":       "Ungrouped",
-	"list.status.unknown":  "?",
-	"list.status.ok":       "✓",
-	"list.status.fail":     "✗",
-	"list.status.checking": "...",
-	"list.help":            "a:add  e:edit  d:delete  /:search  s:settings  t:test  enter:connect  ?:help  q:quit",
-	"list.help.search":     "type to search  enter:confirm  esc:cancel",
+	"list.title":              "SSH Connections",
+	"list.empty":              "No connections yet. Press 'a' to add one.",
+	"list.empty.search":       "No matching connections.",
+	"list.search":             "Search",
+	"list.filter":             "Filter: %s (press / to search, esc to clear)",
+	"list.filter.all":         "All",
+	"list.filter.group":       "Group",
+	"list.total":              "Total: %d connections",
+	"list.showing":            " (showing %d)",
+	"list.ungrouped":          "Ungrouped",
+	"list.status.unknown":     "?",
+	"list.status.ok":          "✓",
+	"list.status.fail":        "✗",
+	"list.status.checking":    "...",
+	"list.trashed":            "Moved to trash — restore it from Settings",
+	"list.copied":             "Copied: %s",
+	"list.copy_failed":        "Copy failed",
+	"list.stale.rotate":       "rotate",
+	"list.stale.expired":      "expired",
+	"list.uptime":             "up %s",
+	"list.via":                "(via %s)",
+	"list.column.name":        "Name",
+	"list.column.host":        "User@Host",
+	"list.column.group":       "Group",
+	"list.column.tags":        "Tags",
+	"list.column.last_seen":   "Last seen",
+	"list.column.status":      "Status",
+	"list.column.system":      "System",
+	"list.help":               "a:add  e:edit  d:delete  /:search  s:settings  t:test  y:copy  enter:connect  ?:help  q:quit",
+	"list.help.search":        "type to search  enter:confirm  esc:cancel",
+	"list.search.placeholder": "Search...",
 
 	// Connection form
-	"form.title.add":       "Add Connection",
-	"form.title.edit":      "Edit Connection",
-	"form.name":            "Name",
-	"form.name.hint":       "A friendly name for this connection",
-	"form.host":            "Host",
-	"form.host.hint":       "Hostname or IP address",
-	"form.port":            "Port",
-	"form.port.hint":       "SSH port (default: 22)",
-	"form.user":            "Username",
-	"form.user.hint":       "SSH username",
-	"form.auth_type":       "Authentication",
-	"form.auth.password":   "Password",
-	"form.auth.key":        "Private Key",
-	"form.password":        "Password",
-	"form.password.hint":   "SSH password",
-	"form.key_path":        "Key Path",
-	"form.key_path.hint":   "Path to private key file",
-	"form.key_passphrase":  "Key Passphrase",
-	"form.key_pass.hint":   "Passphrase for private key (if any)",
-	"form.group":           "Group",
-	"form.group.hint":      "Connection group",
-	"form.tags":            "Tags",
-	"form.tags.hint":       "Comma-separated tags",
-	"form.startup_cmd":     "Startup Command",
-	"form.startup_cmd.hint":"Command to run after connection",
-	"form.save":            "Save",
-	"form.cancel":          "Cancel",
-	"form.error.required":  "This field is required",
-	"form.error.port":      "Invalid port number",
+	"form.title.add":               "Add Connection",
+	"form.title.edit":              "Edit Connection",
+	"form.name":                    "Name",
+	"form.name.hint":               "A friendly name for this connection",
+	"form.host":                    "Host",
+	"form.host.hint":               "Hostname or IP address",
+	"form.type":                    "Protocol",
+	"form.port":                    "Port",
+	"form.port.hint":               "SSH port (default: 22)",
+	"form.user":                    "Username",
+	"form.user.hint":               "SSH username",
+	"form.auth_type":               "Authentication",
+	"form.auth.password":           "Password",
+	"form.auth.key":                "Private Key",
+	"form.password":                "Password",
+	"form.password.hint":           "SSH password",
+	"form.key_path":                "Key Path",
+	"form.key_path.hint":           "Path to private key file",
+	"form.key_passphrase":          "Key Passphrase",
+	"form.key_pass.hint":           "Passphrase for private key (if any)",
+	"form.group":                   "Group",
+	"form.group.hint":              "Connection group",
+	"form.tags":                    "Tags",
+	"form.tags.hint":               "Comma-separated tags",
+	"form.startup_cmd":             "Startup Command",
+	"form.notes":                   "Notes",
+	"form.rotate_after":            "Rotate After",
+	"form.expires_at":              "Expires",
+	"form.startup_cmd.hint":        "Command to run after connection",
+	"form.save":                    "Save",
+	"form.cancel":                  "Cancel",
+	"form.error.required":          "This field is required",
+	"form.error.port":              "Invalid port number",
+	"form.placeholder.name":        "My Server",
+	"form.placeholder.host":        "192.168.1.1 or example.com",
+	"form.placeholder.optional":    "(optional)",
+	"form.placeholder.notes":       "Anything worth remembering about this host",
+	"form.auth.opt.password":       "password",
+	"form.auth.opt.key":            "key",
+	"form.agent_key":               "Agent Key",
+	"form.agent_key.any":           "any key",
+	"form.agent_key.missing":       "%s (not in the agent)",
+	"form.note.toggle":             "(space to toggle)",
+	"form.note.select":             "(space to choose)",
+	"form.note.optional":           "(optional)",
+	"form.note.revealed":           "(shown for 15s, ctrl+r hides it)",
+	"form.note.agent_key":          "(ssh-agent key to offer, instead of all)",
+	"form.note.tags":               "(space or comma adds, → completes)",
+	"form.aliases":                 "Aliases",
+	"form.note.aliases":            "(other names for connect and search)",
+	"form.addresses":               "Other Addresses",
+	"form.note.addresses":          "(tried in order if the host fails)",
+	"form.note.key_path":           "(ctrl+o to browse)",
+	"form.note.host":               "(or srv:_ssh._tcp.name / consul:service)",
+	"form.jump_hosts":              "Jump Hosts",
+	"form.note.jump_hosts":         "(saved connections, in order)",
+	"form.bind_address":            "Bind Address",
+	"form.term":                    "Terminal Type",
+	"form.locale":                  "Locale",
+	"form.local_dir":               "Local Dir",
+	"form.remote_dir":              "Remote Dir",
+	"form.note.bind_address":       "(local IP or interface, optional)",
+	"form.note.term":               "(TERM to request, default: local)",
+	"form.note.locale":             "(LANG/LC_ALL to request, optional)",
+	"form.note.local_dir":          "(SFTP transfers start here, optional)",
+	"form.note.remote_dir":         "(SFTP opens here, default: home)",
+	"form.note.startup":            "(one command per line, runs after connect)",
+	"form.pre_connect":             "Pre-connect",
+	"form.post_disconnect":         "Post-disconnect",
+	"form.note.pre_connect":        "(local command, the session starts once it succeeds)",
+	"form.note.post_disconnect":    "(local command, runs after the session)",
+	"form.quiet_login":             "Quiet Login",
+	"form.show_banner":             "Show Banner",
+	"form.gssapi":                  "GSSAPI",
+	"form.note.quiet_login":        "(hide MOTD and last login)",
+	"form.note.show_banner":        "(show the server's notice first)",
+	"form.note.gssapi":             "(try Kerberos first when a ticket is available)",
+	"form.note.gssapi_unsupported": "(needs a build with -tags gssapi)",
+	"form.opt.on":                  "on",
+	"form.opt.off":                 "off",
+	"form.note.rotate_after":       "(days, optional)",
+	"form.help":                    "tab:next field  enter:save  ctrl+t:test  f1:help  esc:cancel",
+	"form.test.running":            "Testing connection...",
+	"form.test.ok":                 "✓ Connection successful (%s)",
+	"form.test.host_key":           "Host key changed",
+	"form.keys.title":              "Select Key File",
+	"form.keys.none":               "No private keys found in %s",
+	"form.keys.encrypted":          "[encrypted]",
+	"form.keys.help":               "↑/↓:move  enter:select  esc:cancel",
 
 	// Setup
-	"setup.title":              "Welcome to GoSSH",
-	"setup.desc":               "Choose your security mode:",
-	"setup.option.password":    "[1] Enable Password Protection (Recommended)",
-	"setup.option.password.desc":"Set a master password, required on each start",
-	"setup.option.nopassword":  "[2] Skip Password Protection",
-	"setup.option.nopassword.desc":"Quick start, no password required",
-	"setup.password.title":     "Set Master Password",
-	"setup.password.desc":      "This password encrypts your saved credentials. Remember it!",
-	"setup.password.prompt":    "Enter Master Password",
-	"setup.password.confirm":   "Confirm Password",
-	"setup.password.hint":      "Minimum 8 characters",
-	"setup.password.mismatch":  "Passwords do not match",
-	"setup.password.weak":      "Password is too weak",
-	"setup.password.strength":  "Password Strength",
-	"setup.complete":           "Setup complete!",
-	"setup.help.choose":        "↑/↓:select  1/2:quick select  enter:confirm  esc:exit",
-	"setup.help.password":      "tab:next field  enter:confirm  esc:back",
+	"setup.title":                  "Welcome to GoSSH",
+	"setup.desc":                   "Choose your security mode:",
+	"setup.option.password":        "[1] Enable Password Protection (Recommended)",
+	"setup.option.password.desc":   "Set a master password, required on each start",
+	"setup.option.nopassword":      "[2] Skip Password Protection",
+	"setup.option.nopassword.desc": "Quick start, no password required",
+	"setup.password.title":         "Set Master Password",
+	"setup.password.desc":          "This password encrypts your saved credentials. Remember it!",
+	"setup.password.prompt":        "Enter Master Password",
+	"setup.password.confirm":       "Confirm Password",
+	"setup.password.hint":          "Minimum 8 characters",
+	"setup.password.mismatch":      "Passwords do not match",
+	"setup.password.weak":          "Password is too weak",
+	"setup.password.strength":      "Password Strength",
+	"setup.complete":               "Setup complete!",
+	"setup.help.choose":            "↑/↓:select  1/2:quick select  enter:confirm  esc:exit",
+	"setup.help.password":          "tab:next field  enter:confirm  esc:back",
+	"setup.placeholder.password":   "Enter master password",
+	"setup.placeholder.confirm":    "Confirm master password",
 
 	// Unlock
-	"unlock.title":         "GoSSH Locked",
-	"unlock.prompt":        "Enter master password to unlock:",
-	"unlock.label":         "Password:",
-	"unlock.error":         "Incorrect password",
-	"unlock.attempt":       "[Attempt %d/%d]",
-	"unlock.attempts":      "attempts remaining",
-	"unlock.failed":        "Too many failed attempts. Exiting.",
-	"unlock.help":          "enter:unlock  esc:exit",
+	"unlock.title":       "GoSSH Locked",
+	"unlock.prompt":      "Enter master password to unlock:",
+	"unlock.label":       "Password:",
+	"unlock.error":       "Incorrect password",
+	"unlock.attempt":     "[Failed attempts: %d]",
+	"unlock.attempts":    "attempts remaining",
+	"unlock.failed":      "Too many failed attempts. Try again in %s.",
+	"unlock.help":        "enter:unlock  esc:exit",
+	"unlock.placeholder": "Enter master password",
+	"lock.unprotected":   "Nothing to lock: set a master password in Settings first",
 
 	// Confirm dialog
-	"confirm.title":        "Confirm",
-	"confirm.delete":       "Delete Connection",
-	"confirm.delete.msg":   "Are you sure you want to delete this connection?",
-	"confirm.yes":          "Yes",
-	"confirm.no":           "No",
-	"confirm.help":         "y:yes  n:no  tab:toggle  enter:confirm  esc:cancel",
+	"confirm.title":      "Confirm",
+	"confirm.delete":     "Delete Connection",
+	"confirm.delete.msg": "Move this connection to the trash?",
+	"confirm.yes":        "Yes",
+	"confirm.no":         "No",
+	"confirm.help":       "y:yes  n:no  tab:toggle  enter:confirm  esc:cancel",
+	"confirm.default":    "Are you sure?",
 
 	// Help
-	"help.title":           "GoSSH Help",
-	"help.navigation":      "Navigation",
-	"help.connection":      "Connection Management",
-	"help.form":            "Form Navigation",
-	"help.search":          "Search Mode",
-	"help.general":         "General",
-	"help.cli":             "CLI Commands",
-	"help.actions":         "Actions",
-	"help.key.up":          "Move up",
-	"help.key.down":        "Move down",
-	"help.key.top":         "Jump to top",
-	"help.key.bottom":      "Jump to bottom",
-	"help.key.search":      "Search connections",
-	"help.key.connect":     "Connect to selected server",
-	"help.key.enter":       "Connect / Select",
-	"help.key.add":         "Add new connection",
-	"help.key.edit":        "Edit selected connection",
-	"help.key.delete":      "Delete selected connection",
-	"help.key.tab":         "Next field",
-	"help.key.shifttab":    "Previous field",
-	"help.key.space":       "Toggle auth method / Cycle group",
-	"help.key.save":        "Save",
-	"help.key.cancel":      "Cancel",
-	"help.key.type":        "Filter by name, host, user, group, or tags",
-	"help.key.confirm":     "Confirm search and connect",
-	"help.key.help":        "Show this help",
-	"help.key.quit":        "Quit application",
-	"help.key.back":        "Go back / Cancel",
-	"help.key.settings":    "Settings",
-	"help.key.test":        "Test connection",
-	"help.return":          "Press Esc or ? to return",
-	"help.cli.list":        "List all connections",
-	"help.cli.connect":     "Connect by name",
-	"help.cli.export":      "Export connections",
-	"help.cli.import":      "Import connections",
-	"help.cli.check":       "Check connection health",
-	"help.cli.sftp":        "Start SFTP session",
-	"help.cli.forward":     "Port forwarding",
-	"help.cli.exec":        "Batch execute commands",
+	"help.title":              "GoSSH Help",
+	"help.navigation":         "Navigation",
+	"help.connection":         "Connection Management",
+	"help.form":               "Form Navigation",
+	"help.general":            "General",
+	"help.settings":           "Settings",
+	"help.actions":            "Actions",
+	"help.key.up":             "Move up",
+	"help.key.down":           "Move down",
+	"help.key.top":            "Jump to top",
+	"help.key.bottom":         "Jump to bottom",
+	"help.key.left":           "Scroll left",
+	"help.key.right":          "Scroll right",
+	"help.key.color_prev":     "Previous color",
+	"help.key.color_next":     "Next color",
+	"help.key.layout":         "Switch between lines and table",
+	"help.key.sort":           "Sort the table by the next column",
+	"help.key.sort_reverse":   "Reverse the sort order",
+	"help.key.filters":        "Saved filters",
+	"help.key.toggle_column":  "Show or hide a column",
+	"help.key.rule_add":       "Add a grouping rule",
+	"help.key.rule_delete":    "Delete the selected rule",
+	"help.key.default_group":  "Set the default group",
+	"help.key.rule_preview":   "Preview the rules on existing connections",
+	"help.key.rule_apply":     "Apply the rules to existing connections",
+	"help.key.merge_local":    "Keep the local value",
+	"help.key.merge_incoming": "Take the imported value",
+	"help.key.merge_apply":    "Import with the chosen values",
+	"help.key.dedupe_pick":    "Choose the connection to keep",
+	"help.key.dedupe_merge":   "Merge the others into it",
+	"help.key.search":         "Search connections",
+	"help.key.connect":        "Connect to selected server",
+	"help.key.enter":          "Connect / Select",
+	"help.key.add":            "Add new connection",
+	"help.key.edit":           "Edit selected connection",
+	"help.key.delete":         "Delete selected connection",
+	"help.key.tab":            "Next field",
+	"help.key.shifttab":       "Previous field",
+	"help.key.space":          "Toggle an option / open a dropdown",
+	"help.key.save":           "Save",
+	"help.key.cancel":         "Cancel",
+	"help.key.help":           "Show this help",
+	"help.key.quit":           "Quit application",
+	"help.key.back":           "Go back / Cancel",
+	"help.key.settings":       "Settings",
+	"help.key.test":           "Test connection",
+	"help.key.test_all":       "Test all connections in the background",
+	"help.key.broadcast":      "Type into the listed hosts at once",
+	"help.key.exec":           "Run a command on the listed hosts",
+	"help.key.history":        "Edit history of the connection",
+	"help.key.scrollback":     "Search the output of the last session",
+	"help.key.plugins":        "Run a plugin on the connection",
+	"help.key.details":        "Details and attachments",
+	"help.key.local":          "Run a local command or shell",
+	"help.key.lock":           "Lock: forget the keys until the master password is entered",
+	"help.key.copy":           "Copy ssh command",
+	"help.key.test_form":      "Test the entered connection",
+	"help.key.browse":         "Pick a private key file",
+	"help.key.reveal":         "Show or hide the password and key passphrase",
+	"help.key.open":           "Open the selected item",
+	"help.key.restore":        "Restore the selected connection",
+	"help.key.purge":          "Delete permanently (press twice)",
+	"help.return":             "Press any key to return",
+	"help.scroll":             "↑/↓ scroll",
 
 	// Settings
-	"settings.title":           "Settings",
-	"settings.language":        "Language",
-	"settings.security":        "Security",
-	"settings.password.enable": "Enable Master Password",
-	"settings.password.change": "Change Master Password",
-	"settings.password.disable":"Disable Master Password",
-	"settings.about":           "About",
-	"settings.save":            "Save",
-	"settings.cancel":          "Cancel",
-	"settings.saved":           "Settings saved",
-	"settings.help":            "↑/↓: navigate • enter: select • esc: back",
-	"settings.help.language":   "↑/↓: select language • enter: confirm • esc: back",
-	"settings.help.password":   "tab/↑/↓: switch field • enter: confirm • esc: back",
-	"settings.help.password.disable": "enter: confirm • esc: back",
+	"settings.title":                      "Settings",
+	"settings.language":                   "Language",
+	"settings.security":                   "Security",
+	"settings.password.enable":            "Enable Master Password",
+	"settings.password.change":            "Change Master Password",
+	"settings.password.disable":           "Disable Master Password",
+	"settings.about":                      "About",
+	"settings.save":                       "Save",
+	"settings.cancel":                     "Cancel",
+	"settings.saved":                      "Settings saved",
+	"settings.help":                       "↑/↓: navigate • enter: select • ?: help • esc: back",
+	"settings.help.language":              "↑/↓: select language • enter: confirm • esc: back",
+	"settings.help.password":              "tab/↑/↓: switch field • enter: confirm • esc: back",
+	"settings.help.password.disable":      "enter: confirm • esc: back",
+	"settings.import":                     "Import Connections",
+	"settings.export":                     "Export Connections",
+	"settings.trash":                      "Trash (%d)",
+	"settings.notify":                     "Notifications: %s",
+	"settings.notify.off":                 "Off",
+	"settings.notify.bell":                "Terminal bell",
+	"settings.notify.desktop":             "Desktop",
+	"settings.keep_warm":                  "Keep connections warm: %s",
+	"settings.keep_warm.off":              "Off",
+	"settings.keep_warm.minutes":          "%d min",
+	"settings.scrollback":                 "Keep session output: %s",
+	"settings.key_cache":                  "Remember master key for CLI: %s",
+	"settings.theme":                      "Theme: %s",
+	"settings.theme.dark":                 "Dark",
+	"settings.theme.light":                "Light",
+	"settings.connection":                 "Connections & Terminal",
+	"settings.connection.title":           "Connections & Terminal",
+	"settings.on":                         "On",
+	"settings.off":                        "Off",
+	"settings.seconds":                    "%d s",
+	"settings.timeout":                    "Connection timeout: %s",
+	"settings.timeout.prompt":             "Seconds connecting to a host may take:",
+	"settings.default_port":               "Default port: %d",
+	"settings.port.prompt":                "Port filled in for new SSH connections:",
+	"settings.default_user":               "Default user: %s",
+	"settings.default_user.none":          "none",
+	"settings.user.prompt":                "User filled in for new connections (empty for none):",
+	"settings.keepalive":                  "Keepalive interval: %s",
+	"settings.keepalive.default":          "default (10 s)",
+	"settings.keepalive.prompt":           "Seconds between keepalives in sessions (0 for the default):",
+	"settings.health":                     "Background health check: %s",
+	"settings.health.off":                 "Off",
+	"settings.health.minutes":             "every %d min",
+	"settings.confirm_delete":             "Confirm before deleting: %s",
+	"settings.term":                       "Terminal type (TERM): %s",
+	"settings.term.local":                 "local",
+	"settings.term.prompt":                "TERM for connections that don't set one (empty for the local one):",
+	"settings.locale":                     "Locale: %s",
+	"settings.locale.none":                "unchanged",
+	"settings.locale.prompt":              "Locale for connections that don't set one, e.g. C.UTF-8 (empty to send none):",
+	"settings.rules":                      "Grouping rules (%d)",
+	"settings.rules.title":                "Grouping Rules",
+	"settings.rules.empty":                "No rules. Press n to file hosts such as \\.prod\\.example\\.com$ under Production.",
+	"settings.rules.default":              "Default group: %s",
+	"settings.rules.default.none":         "none",
+	"settings.rules.default.prompt":       "Group for new connections no rule files (empty for none):",
+	"settings.rules.pattern":              "Pattern (regular expression on name or host):",
+	"settings.rules.pattern.placeholder":  "e.g. \\.prod\\.example\\.com$",
+	"settings.rules.group":                "Group:",
+	"settings.rules.tags":                 "Tags (comma separated):",
+	"settings.rules.preview.title":        "Grouping Rules: Dry Run",
+	"settings.rules.preview.empty":        "The rules would change no existing connection.",
+	"settings.rules.preview.count":        "%d existing connections would change:",
+	"settings.rules.preview.more":         "… and %d more",
+	"settings.rules.applied":              "Filed %d connections",
+	"settings.help.rules":                 "↑/↓: select • n: add • d: delete • g: default group • p: dry run • esc: back",
+	"settings.help.rules.add":             "tab: next field • enter: save • esc: cancel",
+	"settings.help.rules.default":         "enter: save • esc: cancel",
+	"settings.help.rules.preview":         "enter: apply to existing connections • esc: back",
+	"settings.help.value":                 "enter: save • esc: cancel",
+	"filters.title":                       "Saved Filters",
+	"filters.empty":                       "No saved filters. Search with / first, then press n here to save it.",
+	"filters.name":                        "Name:",
+	"filters.name.placeholder":            "e.g. prod web",
+	"filters.no_search":                   "Search with / first to have something to save",
+	"filters.help":                        "enter:apply  n:save current search  d:delete  esc:close",
+	"filters.help.naming":                 "enter:save  esc:cancel",
+	"plugins.title":                       "Plugins for %s",
+	"plugins.empty":                       "No plugins. Put executables named %s<command> in %s",
+	"plugins.help":                        "enter:run  esc:close",
+	"details.title":                       "Details: %s",
+	"details.address":                     "Address",
+	"details.group":                       "Group",
+	"details.tags":                        "Tags",
+	"details.password":                    "Password",
+	"details.key_passphrase":              "Key Passphrase",
+	"details.agent_key":                   "Agent Key",
+	"details.notes":                       "Notes",
+	"details.attachments":                 "Attachments",
+	"details.attachments.empty":           "No attachments yet",
+	"details.add.prompt":                  "Attach file:",
+	"details.export.prompt":               "Export to:",
+	"details.added":                       "Attached %s",
+	"details.deleted":                     "Deleted %s",
+	"details.exported":                    "Exported %s to %s",
+	"details.exists":                      "file exists, not overwritten",
+	"details.not_file":                    "not a regular file",
+	"details.binary":                      "%s is not text: export it to open it",
+	"details.delete.confirm":              "Delete %s? (y/n)",
+	"details.revealed":                    "Shown for 15s: ctrl+r hides them",
+	"details.help":                        "enter:view  a:attach  x:export  d:delete  ctrl+r:show passwords  esc:close",
+	"history.title":                       "History: %s",
+	"history.empty":                       "No edits recorded yet",
+	"history.restored":                    "Restored the version from before the edit of %s",
+	"history.help":                        "↑/↓: select • r: restore this version • esc: back",
+	"scrollback.title":                    "Output: %s",
+	"scrollback.position":                 "lines %d-%d of %d",
+	"scrollback.dropped":                  "(older output dropped)",
+	"scrollback.empty":                    "The session printed nothing",
+	"scrollback.help":                     "↑/↓/pgup/pgdn: scroll • g/G: top/bottom • /: search • n/N: older/newer match • esc: back",
+	"scrollback.search.placeholder":       "text to find",
+	"scrollback.no_match":                 "No line contains \"%s\"",
+	"scrollback.off":                      "Session output is not kept, turn it on in Settings",
+	"scrollback.none":                     "No output kept of a session with %s yet",
+	"settings.trash.title":                "Trash",
+	"settings.trash.empty":                "The trash is empty",
+	"settings.trash.info":                 "deleted %s · purged in %d days",
+	"settings.trash.restored":             "Restored '%s'",
+	"settings.trash.purged":               "Permanently deleted '%s'",
+	"settings.trash.confirm_purge":        "Press p again to permanently delete '%s'",
+	"settings.groups":                     "Group Colors",
+	"settings.groups.title":               "Group Colors",
+	"settings.groups.empty":               "There are no groups",
+	"settings.layout":                     "List layout: %s",
+	"settings.layout.lines":               "Lines",
+	"settings.layout.table":               "Table",
+	"settings.columns":                    "Table Columns",
+	"settings.columns.note":               "Columns show when the list layout is Table (press v in the list)",
+	"settings.transfer.path":              "File path",
+	"settings.transfer.format":            "Format",
+	"settings.transfer.format.yaml":       "GoSSH (YAML)",
+	"settings.transfer.format.ssh_config": "OpenSSH config",
+	"settings.transfer.note.import":       "Connections that already exist, by name, alias or address, are merged field by field",
+	"settings.import.result":              "Import complete",
+	"settings.export.result":              "Export complete",
+	"settings.transfer.file":              "File",
+	"settings.transfer.imported":          "Imported",
+	"settings.transfer.skipped":           "Skipped",
+	"settings.transfer.merged":            "Merged",
+	"settings.merge.title":                "Import Conflicts: %d",
+	"settings.merge.summary":              "%d new connections will be added and %d are unchanged. Pick the value to keep for each field.",
+	"settings.help.merge":                 "↑/↓: select • ←: local • →: imported • space: toggle • enter: import • esc: cancel",
+	"settings.duplicates":                 "Duplicates (%d)",
+	"settings.duplicates.title":           "Duplicate Connections",
+	"settings.duplicates.empty":           "No two connections log in to the same user, host and port.",
+	"settings.duplicates.keep":            "keep",
+	"settings.duplicates.done":            "Merged %d connections into %s; the others are in the trash",
+	"settings.help.duplicates":            "↑/↓: set • ←/→: keep • enter: merge • esc: back",
+	"settings.transfer.exported":          "Exported",
+	"settings.help.transfer":              "tab: switch field • ←/→: format • enter: run • esc: back",
+	"settings.help.result":                "enter/esc: back",
+	"settings.help.trash":                 "↑/↓: select • r/enter: restore • p: purge • esc: back",
+	"settings.help.groups":                "↑/↓: select • ←/→: change color • esc: back",
+	"settings.help.columns":               "↑/↓: select • space: show/hide • esc: back",
 
 	// Host key verification
-	"hostkey.title":            "Host Key Verification",
-	"hostkey.unknown":          "Unknown Host",
-	"hostkey.unknown.msg":      "The authenticity of host '%s' can't be established.",
-	"hostkey.fingerprint":      "Fingerprint",
-	"hostkey.keytype":          "Key Type",
-	"hostkey.trust":            "Do you want to trust this host and continue connecting?",
-	"hostkey.changed":          "WARNING: Host Key Changed!",
-	"hostkey.changed.msg":      "The host key for '%s' has changed. This could indicate a man-in-the-middle attack!",
-	"hostkey.accept":           "Accept",
-	"hostkey.reject":           "Reject",
-	"hostkey.update":           "Update",
-	"hostkey.help":             "y:accept  n:reject  enter:confirm",
+	"hostkey.title":       "Host Key Verification",
+	"hostkey.unknown":     "Unknown Host",
+	"hostkey.unknown.msg": "The authenticity of host '%s' can't be established.",
+	"hostkey.fingerprint": "Fingerprint",
+	"hostkey.keytype":     "Key Type",
+	"hostkey.trust":       "Do you want to trust this host and continue connecting?",
+	"hostkey.changed":     "WARNING: Host Key Changed!",
+	"hostkey.changed.msg": "The host key for '%s' has changed. This could indicate a man-in-the-middle attack!",
+	"hostkey.accept":      "Accept",
+	"hostkey.reject":      "Reject",
+	"hostkey.update":      "Update",
+	"hostkey.help":        "y:accept  n:reject  enter:confirm",
+	"banner.title":        "Banner from %s",
+	"banner.help":         "enter:continue  esc:cancel",
+	"hostkey.previous":    "Previous fingerprint:",
 
 	// Health check
-	"health.title":             "Connection Test",
-	"health.testing":           "Testing connection...",
-	"health.checking":          "Checking...",
-	"health.reachable":         "Reachable",
-	"health.unreachable":       "Unreachable",
-	"health.auth_failed":       "Authentication failed",
-	"health.result.success":    "✓ Connection successful",
-	"health.result.fail":       "✗ Connection failed",
+	"health.title":              "Connection Test",
+	"health.testing":            "Testing connection...",
+	"health.all.progress":       "Checking connections... %d/%d",
+	"health.all.done":           "Checked %d connections: %d up, %d down",
+	"broadcast.title.all":       "Broadcast to %d hosts",
+	"broadcast.title.single":    "Typing to %s only",
+	"broadcast.help":            "ctrl+n/ctrl+p:switch host  ctrl+t:all/one host  ctrl+q:close",
+	"broadcast.confirm":         "Broadcast",
+	"broadcast.confirm.msg":     "Open a shell on %d hosts and type into all of them?",
+	"broadcast.closed":          "Broadcast closed",
+	"broadcast.dropped":         "%d inputs were dropped: this host is not keeping up",
+	"exec.title":                "Run on %d hosts",
+	"exec.command":              "Command:",
+	"exec.command.placeholder":  "uptime",
+	"exec.progress":             "%d/%d done · %d running · %d failed",
+	"exec.eta":                  "about %s left",
+	"exec.exit":                 "exit %d",
+	"exec.finished":             "%d succeeded, %d failed",
+	"exec.done":                 "Command finished: %d succeeded, %d failed",
+	"exec.stopping":             "Stopping the command on the hosts...",
+	"exec.help.input":           "enter: run • esc: cancel",
+	"exec.help.running":         "↑/↓: select host • esc: stop",
+	"exec.help.done":            "↑/↓: select host • esc: back",
+	"local.title":               "Local Command",
+	"local.command":             "Command:",
+	"local.command.placeholder": "empty for a shell, exit to return",
+	"local.help":                "enter: run • esc: cancel",
+	"local.return":              "Press Enter to return to gossh",
+	"local.exit":                "Local command exited with status %d",
+	"health.checking":           "Checking...",
+	"health.reachable":          "Reachable",
+	"health.unreachable":        "Unreachable",
+	"health.auth_failed":        "Authentication failed",
+	"health.result.success":     "✓ Connection successful",
+	"health.result.fail":        "✗ Connection failed",
 
 	// SFTP
-	"sftp.connected":           "SFTP connected to %s",
-	"sftp.pwd":                 "Current directory: %s",
-	"sftp.uploading":           "Uploading: %s",
-	"sftp.downloading":         "Downloading: %s",
-	"sftp.progress":            "%d%% (%s / %s)",
-	"sftp.complete":            "Transfer complete",
+	"sftp.connected":   "SFTP connected to %s",
+	"sftp.pwd":         "Current directory: %s",
+	"sftp.uploading":   "Uploading: %s",
+	"sftp.downloading": "Downloading: %s",
+	"sftp.progress":    "%d%% (%s / %s)",
+	"sftp.complete":    "Transfer complete",
 
 	// Import
-	"import.title":             "Import SSH Config",
-	"import.reading":           "Reading %s...",
-	"import.found":             "Found %d connections",
-	"import.importing":         "Importing...",
-	"import.skip.duplicate":    "Skipping duplicate: %s",
-	"import.complete":          "Import complete: %d imported, %d skipped",
+	"import.title":          "Import SSH Config",
+	"import.reading":        "Reading %s...",
+	"import.found":          "Found %d connections",
+	"import.importing":      "Importing...",
+	"import.skip.duplicate": "Skipping duplicate: %s",
+	"import.complete":       "Import complete: %d imported, %d skipped",
+	"wizard.title":          "Import SSH Hosts",
+	"wizard.desc":           "Found %d new hosts in %s. Choose the hosts to import:",
+	"wizard.selected":       "%d of %d selected",
+	"wizard.help":           "↑/↓:move  space:toggle  a:all  g:cycle group  G:group for all  enter:import  esc:skip",
 
 	// Errors
-	"error.connection":         "Connection failed",
-	"error.auth":               "Authentication failed",
-	"error.timeout":            "Connection timed out",
-	"error.unknown":            "Unknown error",
+	"error.connection":                  "Connection failed",
+	"error.auth":                        "Authentication failed",
+	"error.timeout":                     "Connection timed out",
+	"error.unknown":                     "Unknown error",
+	"error.validation.name":             "name is required",
+	"error.validation.host":             "host is required",
+	"error.validation.host_invalid":     "host must be a hostname or IP address",
+	"error.validation.aliases":          "aliases must be single words other than the name",
+	"error.validation.addresses":        "other addresses must be hostnames or IP addresses",
+	"error.validation.user":             "user is required",
+	"error.validation.port":             "port must be between 1 and 65535",
+	"error.validation.key_path":         "key path is required for key authentication",
+	"error.validation.key_file":         "key file cannot be read or is not a private key",
+	"error.validation.key_passphrase":   "the key is encrypted, enter its passphrase",
+	"error.validation.agent_key":        "agent key must be a SHA256 fingerprint, as ssh-add -l prints it",
+	"error.validation.term":             "terminal type must be one word, e.g. vt100",
+	"error.validation.locale":           "locale must be one word, e.g. C.UTF-8",
+	"error.validation.rotate_after":     "rotation period must be a positive number of days",
+	"error.validation.expires_at":       "expiry date must be in YYYY-MM-DD format",
+	"error.validation.pattern":          "pattern must be a valid regular expression",
+	"error.validation.rule":             "a rule needs a group or tags",
+	"error.validation.workspace":        "a workspace needs connections or tunnels",
+	"error.validation.tunnel":           "a tunnel needs a connection, -L or -R and a forward spec",
+	"error.validation.forward_profiles": "a forward profile needs a name without spaces and -L, -R or -D forward specs",
+	"error.validation.snippet":          "a snippet needs a name and a command",
+	"error.validation.cron":             "schedule must be a cron spec, e.g. 0 3 * * *",
+	"error.validation.command":          "command is required",
+	"error.validation.timeout":          "timeout must be a positive number of seconds",
+	"error.validation.interval":         "interval must be a positive number, or 0 for the default",
+	"error.validation.theme":            "theme must be dark or light",
+	"error.validation.default_user":     "default user must be one word",
+	"error.password.invalid":            "invalid password",
+	"error.password.weak":               "password too weak: minimum 8 characters required",
+	"error.attachment.too_large":        "attachments are limited to %d KB",
+	"error.attachment.empty":            "the file is empty",
+	"error.attachment.exists":           "the connection already has an attachment of that name",
+	"error.locked":                      "unlock gossh first",
+	"error.insecure_dir":                "%s can be read by other users: export the passwords somewhere private",
+	"error.password.policy.length":      "password too weak: minimum %d characters required",
+	"error.password.policy.classes":     "password too weak: use at least %d of lower case, upper case, digits and symbols",
+	"error.password.policy.common":      "password too weak: it is a common password or contains a blocked word",
+	"error.password.policy.score":       "password too easy to guess: make it longer, or use a few unrelated words",
 
 	// Common
-	"common.loading":           "Loading...",
-	"common.saving":            "Saving...",
-	"common.success":           "Success",
-	"common.error":             "Error",
-	"common.back":              "Back",
-	"common.next":              "Next",
-	"common.done":              "Done",
-	"common.connecting":        "Connecting to %s...",
-	"common.disconnected":      "Disconnected",
-	"common.conn_error":        "Connection error: %s",
+	"common.loading":                 "Loading...",
+	"common.saving":                  "Saving...",
+	"common.success":                 "Success",
+	"common.error":                   "Error",
+	"common.back":                    "Back",
+	"common.next":                    "Next",
+	"common.done":                    "Done",
+	"common.connecting":              "Connecting to %s...",
+	"session.summary":                "%s: exited %d after %s",
+	"session.pre_connect.failed":     "pre_connect command failed: %v",
+	"session.post_disconnect.failed": "post_disconnect command failed: %v",
+	"session.suspended":              "%s suspended — press enter on it to resume",
+	"status.protected":               "master password on",
+	"status.unprotected":             "no master password",
+	"status.sessions":                "suspended sessions: %d",
+	"status.jobs":                    "background jobs: %d",
+	"session.closed":                 "Connection to %s closed",
+	"common.conn_error":              "Connection error: %s",
+	"common.state_error":             "Failed to save connection status: %v",
+
+	// CLI
+	"cli.help.title":                   "GoSSH - TUI SSH Connection Manager v%s",
+	"cli.help.usage":                   "Usage:",
+	"cli.help.advanced":                "Advanced Commands (v1.2):",
+	"cli.help.tui":                     "Start the TUI application",
+	"cli.help.config_flag":             "Use another config file, for this command or the TUI",
+	"cli.help.help":                    "Show this help message",
+	"cli.help.version":                 "Show version information",
+	"cli.help.list":                    "List all connections",
+	"cli.help.connect":                 "Connect to a server by name",
+	"cli.help.connect.share":           "Mirror session output read-only to a socket or file",
+	"cli.help.connect.scrollback":      "Page through the session output after it ends",
+	"cli.help.connect.command":         "Run one command instead of a shell and exit with its status",
+	"cli.help.rm":                      "Move a connection to the trash (--purge deletes permanently)",
+	"cli.help.trash":                   "List, restore or purge deleted connections",
+	"cli.help.rename":                  "Rename a connection",
+	"cli.help.dedupe":                  "Merge connections to the same user, host and port",
+	"cli.help.dedupe.dry_run":          "Only list the duplicates",
+	"cli.help.dedupe.yes":              "Merge every set without asking",
+	"cli.help.history":                 "Show the edits of a connection, what one changed, or restore the version before it",
+	"cli.help.workspace":               "List the saved workspaces",
+	"cli.help.workspace.save":          "Save a workspace, replacing one of the same name",
+	"cli.help.workspace.sessions":      "Connections to open sessions to",
+	"cli.help.workspace.tunnel":        "A tunnel through a connection, as for ssh -L/-R",
+	"cli.help.workspace.snippet":       "A command to run on the connections later",
+	"cli.help.workspace.open":          "Start the tunnels and open the sessions (tmux windows inside tmux)",
+	"cli.help.workspace.run":           "Run a snippet on the workspace's connections",
+	"cli.help.workspace.rm":            "Remove a workspace",
+	"cli.help.schedule":                "List the scheduled commands",
+	"cli.help.schedule.add":            "Run a command on a cron spec, e.g. \"0 3 * * *\" or @daily",
+	"cli.help.schedule.filter":         "Hosts to run it on, as for exec (default: all)",
+	"cli.help.schedule.rm":             "Remove a scheduled command",
+	"cli.help.schedule.daemon":         "Run the scheduled commands when due, until interrupted",
+	"cli.help.export":                  "Export connections (default: connections.yaml)",
+	"cli.help.export.force":            "Write plain-text passwords into a directory other users can read",
+	"cli.help.import":                  "Import connections from file",
+	"cli.help.import_ssh":              "Import from SSH config file",
+	"cli.help.sftp":                    "Start SFTP session with a server",
+	"cli.help.sftp.parallel":           "Queued transfers run at once (default: 1)",
+	"cli.help.sftp.preserve":           "Keep links, owners (as root) and times; bare keeps all",
+	"cli.help.sftp.trash":              "rm and rmdir move files to ~/.gossh-trash instead of deleting them",
+	"cli.help.sftp.batch":              "Run the commands in a file (- for stdin) and exit, stopping at the first that fails",
+	"cli.help.sftp.exec":               "Run commands separated by ; the same way, e.g. \"put app.tar; chmod 644 app.tar\"",
+	"cli.help.forward":                 "Port forwarding (-L local, -R remote, -D SOCKS), any number at once",
+	"cli.help.forward.match":           "Select the server by name/host regex",
+	"cli.help.forward.save":            "Also save the forwards as a profile of the connection",
+	"cli.help.forward.profile":         "Start a saved profile, may be repeated",
+	"cli.help.forward.all_profiles":    "Start every saved profile of the connection",
+	"cli.help.forward.list":            "List the saved profiles of the connection",
+	"cli.help.forward.gateway":         "Let -R forwards listen on all of the server's interfaces",
+	"cli.help.forward.hint":            "Client command to show, {port} is the first forward's port, {port2} the second's",
+	"cli.help.forward.copy":            "Copy the client commands to the clipboard",
+	"cli.help.exec":                    "Execute command on multiple servers",
+	"cli.help.exec.group":              "Filter by group",
+	"cli.help.exec.tags":               "Filter by tags",
+	"cli.help.exec.names":              "Filter by names (globs like web-* allowed)",
+	"cli.help.exec.match":              "Filter by name/host regex",
+	"cli.help.exec.exclude_group":      "Skip servers in a group",
+	"cli.help.exec.exclude_tags":       "Skip servers with any of these tags",
+	"cli.help.exec.exclude_names":      "Skip servers by name (globs allowed)",
+	"cli.help.exec.timeout":            "Seconds each host may take, connecting and running (default: 30)",
+	"cli.help.exec.deadline":           "Limit for the whole run (default: none)",
+	"cli.help.exec.copy":               "Copy the collected output to the clipboard",
+	"cli.help.exec.diff":               "Group hosts by identical output and diff the variants",
+	"cli.help.exec.pty":                "Run in a pseudo-terminal, for commands that need one (sudo with requiretty)",
+	"cli.help.exec.strip_color":        "Drop colors and other terminal escapes from the output",
+	"cli.help.exec.env":                "Set an environment variable for the command (repeatable)",
+	"cli.help.exec.env_file":           "Set the variables of a .env file",
+	"cli.help.exec.output":             "Result format: text, json (one line per host), junit or markdown (default: text)",
+	"cli.help.exec.retry_last":         "Run again on the hosts the last run failed on",
+	"cli.help.exec.retry_failed":       "Run again on the hosts a run failed on (IDs are printed after each run)",
+	"cli.help.play":                    "Run the steps of a playbook in order, each on its target hosts",
+	"cli.help.play.dry_run":            "Only show the steps and the hosts they select",
+	"cli.help.play.yes":                "Run without asking to continue",
+	"cli.help.push":                    "Upload a file to many hosts in parallel",
+	"cli.help.push.filter":             "Select hosts like exec",
+	"cli.help.push.mode":               "Mode of the remote file, e.g. 0644 (default: local mode)",
+	"cli.help.push.owner":              "Change the remote file's owner",
+	"cli.help.push.preserve_times":     "Keep the local file's access and modification times",
+	"cli.help.push.run":                "Run a command on each host after its upload",
+	"cli.help.push.parallel":           "Hosts served at once (default: 10)",
+	"cli.help.push.timeout":            "Per-host timeout (default: 120)",
+	"cli.help.tail":                    "Follow a remote file on many hosts at once",
+	"cli.help.tail.filter":             "Select hosts like exec",
+	"cli.help.tail.grep":               "Only show lines matching the pattern",
+	"cli.help.tail.lines":              "Lines of history to start with (default: 10)",
+	"cli.help.watch":                   "Upload local changes to a remote directory as they happen",
+	"cli.help.watch.exclude":           "Paths to skip, besides .git and editor swap files",
+	"cli.help.watch.delete":            "Remove remote files deleted locally",
+	"cli.help.watch.debounce":          "Wait for changes to settle this long (default: 300)",
+	"cli.help.archive":                 "Download a remote directory as a .tar.gz (- for stdout)",
+	"cli.help.extract":                 "Unpack a .tar.gz (- for stdin) into a remote directory",
+	"cli.help.check":                   "Health check connections",
+	"cli.help.check.all":               "Check all connections",
+	"cli.help.check.group":             "Check by group",
+	"cli.help.check.name":              "Check specific connection",
+	"cli.help.facts":                   "Gather the OS and uptime shown in the list",
+	"cli.help.facts.filter":            "Select hosts like exec (default: all)",
+	"cli.help.report":                  "Write an inventory report for audits",
+	"cli.help.report.format":           "md or html (default: from the file name, else md)",
+	"cli.help.report.output":           "Write to a file instead of stdout",
+	"cli.help.report.filter":           "Select hosts like exec (default: all)",
+	"cli.help.audit":                   "Report expired connections and credentials due for rotation",
+	"cli.help.audit.max_age":           "Rotation period for connections without their own (e.g. 90)",
+	"cli.help.audit.all":               "Include connections that are up to date",
+	"cli.help.validate":                "Check stored connections for problems",
+	"cli.help.monitor":                 "Health-check connections and serve Prometheus metrics",
+	"cli.help.monitor.listen":          "Metrics listen address (default: :9100)",
+	"cli.help.monitor.interval":        "Seconds between probes (default: 30)",
+	"cli.help.monitor.timeout":         "Per-probe timeout in seconds (default: 5)",
+	"cli.help.monitor.filter":          "Same target filters as exec",
+	"cli.help.lock":                    "Lock running gossh sessions until the master password is entered",
+	"cli.help.remember":                "Store the master key in the OS keyring so commands unlock on their own",
+	"cli.help.forget":                  "Remove the master key from the OS keyring",
+	"cli.help.plugins":                 "List the plugins, executables named gossh-<command> in the plugins directory",
+	"cli.help.plugin":                  "Run a plugin, given the named connection as JSON on stdin",
+	"cli.help.forwarding":              "Port Forwarding:",
+	"cli.help.forward.local":           "-L (Local Forward): Map remote port to local\n  Listens on <local-port> on your machine, traffic is forwarded through the\n  SSH server to <remote-host>:<remote-port>.\n  Use \"localhost\" as <remote-host> to access the server's own port.",
+	"cli.help.forward.remote":          "-R (Remote Forward): Map local port to remote\n  Listens on <remote-port> on the SSH server, traffic is forwarded back to\n  <local-host>:<local-port> on your machine.\n  Use \"localhost\" as <local-host> to expose your machine's own port.",
+	"cli.help.forward.dynamic":         "-D (Dynamic Forward): SOCKS proxy\n  Listens on <local-port> on your machine as a SOCKS4/5 proxy, each\n  connection is made by the SSH server to where the client asks.",
+	"cli.help.examples":                "Examples:",
+	"cli.help.example.mysql":           "Access remote server's MySQL (port 3306) from local port 3306",
+	"cli.help.example.expose":          "Expose local web service (port 80) as port 8080 on remote server",
+	"cli.help.navigation":              "TUI Navigation:",
+	"cli.help.config":                  "Config location:",
+	"cli.help.config.env":              "Set GOSSH_CONFIG_DIR to move the whole directory, or pass --config <path> for one file",
+	"cli.usage.connect":                "usage: gossh connect <name> [--share=<addr|unix:path|file>] [--scrollback] [-- <command...>]",
+	"cli.usage.sftp":                   "usage: gossh sftp <name> [--parallel=<n>] [--preserve[=links,owner,times]] [--trash] [--batch <file>] [-e <commands>]",
+	"cli.usage.import":                 "usage: gossh import <file> [--merge [--dry-run]] or gossh import --ssh-config [path]",
+	"cli.usage.rm":                     "usage: gossh rm <name> [--force] [--purge]",
+	"cli.usage.trash":                  "usage: gossh trash [list | restore <name> | purge <name> | empty]",
+	"cli.usage.audit":                  "usage: gossh audit-credentials [--max-age=<days>] [--group=<group>] [--all]",
+	"cli.usage.monitor":                "usage: gossh monitor [--listen=<addr>] [--interval=<seconds>] [--timeout=<seconds>] [--group=<group>] [--tags=<tags>] [--names=<names>] [--match=<regex>]",
+	"cli.usage.facts":                  "usage: gossh facts [--group=<group>] [--tags=<tags>] [--names=<names>] [--match=<regex>] [--exclude-*=...] [--timeout=<seconds>]",
+	"cli.usage.print_ssh_config":       "usage: gossh print-ssh-config [--group=<group>] [--tags=<tags>] [--names=<names>] [--match=<regex>] [--exclude-*=...]",
+	"cli.usage.stdio":                  "usage: gossh stdio <name> [<host>:<port>]",
+	"cli.usage.probe":                  "usage: gossh probe <name> --ports=<ports> [--host=<host>] [--timeout=<seconds>]",
+	"cli.usage.agent":                  "usage: gossh agent [list | pin <name> <fingerprint|comment> | unpin <name>]",
+	"cli.usage.report":                 "usage: gossh report [--format=md|html] [--output=<file>] [--max-age=<days>] [--group=<group>] [--tags=<tags>] [--names=<names>] [--match=<regex>] [--exclude-*=...]",
+	"cli.usage.rename":                 "usage: gossh rename <old> <new>",
+	"cli.usage.dedupe":                 "usage: gossh dedupe [--dry-run] [--yes]",
+	"cli.usage.history":                "usage: gossh history <name> [<n> [--restore]]",
+	"cli.usage.workspace":              "usage: gossh workspace [list | save <name> [<connection>...] [-L|-R <connection>=<spec>]... [--snippet <name>=<command>]... | open <name> | run <name> <snippet> | rm <name>]",
+	"cli.usage.schedule":               "usage: gossh schedule [list | add <cron> [--group=...|--tags=...|--names=...|--match=...]... [--timeout=<seconds>] <command> | rm <id> | daemon]",
+	"cli.usage.config":                 "usage: --config <path>",
+	"cli.usage.forward":                "usage: gossh forward <name|glob> [--match=<regex>] [-L/-R/-D <spec>]... [--save=<profile>] [--hint=<command>] [--profile=<profile>]... [--all-profiles] [--gateway] [--copy] [--list]\nExample: gossh forward myserver -L 8080:localhost:80 -D 1080",
+	"cli.usage.exec":                   "usage: gossh exec <command> [--group=<group>] [--tags=<tags>] [--names=<names>] [--match=<regex>] [--exclude-*=...] [--timeout=<seconds>] [--deadline=<seconds>] [--copy] [--diff] [--output=<text|json|junit|markdown>] [--retry-last | --retry-failed=<run-id>]",
+	"cli.usage.play":                   "usage: gossh play <playbook.yaml> [--dry-run] [--yes]",
+	"cli.usage.push":                   "usage: gossh push <local-file> <remote-path> [--group=<group>] [--tags=<tags>] [--names=<names>] [--match=<regex>] [--exclude-*=...] [--mode=<octal>] [--owner=<user[:group]>] [--preserve-times] [--run=<command>] [--parallel=<n>] [--timeout=<seconds>]",
+	"cli.usage.tail":                   "usage: gossh tail <remote-file> [--group=<group>] [--tags=<tags>] [--names=<names>] [--match=<regex>] [--exclude-*=...] [--grep=<regex>] [--lines=<n>]",
+	"cli.usage.watch":                  "usage: gossh watch <name> <local-dir> <remote-dir> [--exclude=<glob>[,<glob>...]] [--delete] [--debounce=<ms>]",
+	"cli.usage.archive":                "usage: gossh archive <name> <remote-dir> <local-file|->",
+	"cli.usage.extract":                "usage: gossh extract <name> <local-file|-> <remote-dir>",
+	"cli.error.not_found":              "connection '%s' not found",
+	"cli.error.telnet":                 "'%s' is a telnet connection, this needs SSH",
+	"cli.error.no_command":             "no command specified",
+	"cli.error.no_match":               "no matching connections found",
+	"cli.error.first_run":              "first run: please use TUI mode to complete setup",
+	"cli.error.match_none":             "no connection matches '%s'",
+	"cli.error.match_many":             "pattern matches %d connections (%s), narrow it to one",
+	"cli.error.forward_type":           "invalid forward type: %s (use -L, -R or -D)",
+	"cli.password.prompt":              "Enter master password: ",
+	"cli.confirm.continue":             "Continue? [y/N]: ",
+	"cli.confirm.proceed":              "Proceed? [Y/n]: ",
+	"cli.aborted":                      "Aborted.",
+	"cli.warning":                      "Warning: %v",
+	"cli.key_cache.failed":             "Warning: the master key is not remembered: %v",
+	"cli.no_connections":               "No connections found.",
+	"cli.export.done":                  "Exported %d connections to %s",
+	"cli.export.insecure_dir":          "refusing to export passwords: %v\nUse --force to write there anyway",
+	"cli.warning.key_mode":             "WARNING: %v",
+	"cli.import.overwrite":             "Overwrite existing connections with same name? [y/N]: ",
+	"cli.import.done":                  "Imported %d connections from %s",
+	"cli.help.import.merge":            "Merge into matching connections field by field instead of asking to overwrite",
+	"cli.help.import.dry_run":          "With --merge, show the changes without saving",
+	"cli.import.merge.kept":            "kept, the file has none",
+	"cli.import.merge.summary":         "%d new, %d differ, %d unchanged",
+	"cli.import.merge.done":            "Added %d and merged %d connections from %s",
+	"cli.import.ssh.none":              "No connections found in SSH config.",
+	"cli.import.ssh.found":             "Found %d connections in SSH config.",
+	"cli.import.ssh.exists":            "All %d connections already exist, nothing to import.",
+	"cli.import.ssh.pending":           "Will import %d new connections (%d skipped as duplicates).",
+	"cli.import.ssh.cancelled":         "Import cancelled.",
+	"cli.import.ssh.add_failed":        "Warning: failed to add %s: %v",
+	"cli.import.ssh.done":              "Successfully imported %d connections.",
+	"cli.import.ssh.key":               "KEY",
+	"cli.import.ssh.action":            "ACTION",
+	"cli.import.ssh.action.add":        "import",
+	"cli.import.ssh.action.skip":       "skip (exists)",
+	"cli.import.ssh.dry_run":           "Dry run: no changes were made.",
+	"cli.help.import_ssh.dry_run":      "Preview the import without saving",
+	"cli.help.print_ssh_config":        "Print OpenSSH Host entries that connect through gossh stdio, for ssh, scp and IDEs",
+	"cli.help.print_ssh_config.filter": "Only the matching connections",
+	"cli.help.stdio":                   "Connect stdin and stdout to the connection's SSH port, through its jump hosts (ProxyCommand)",
+	"cli.help.stdio.addr":              "Connect to this address from the host instead, logged in to it (:<port> is the host itself)",
+	"cli.help.agent":                   "List the keys in ssh-agent, with the connections pinned to each",
+	"cli.help.agent.pin":               "Log in to the connection with only this agent key, no key file needed",
+	"cli.help.agent.key":               "Fingerprint (SHA256:...) or comment of the key, as gossh agent lists it",
+	"cli.help.agent.unpin":             "Offer all the agent's keys again",
+	"cli.help.probe":                   "Check which ports the host can reach, without opening a shell",
+	"cli.help.probe.ports":             "Ports and ranges to check",
+	"cli.help.probe.host":              "Check this host's ports from the host instead of its own (default localhost)",
+	"cli.help.probe.timeout":           "How long a port has to answer (default 5)",
+	"cli.help.discover":                "Import Tailscale peers into the Tailnet group (run again to refresh)",
+	"cli.help.discover.socket":         "tailscaled socket (default: /var/run/tailscale/tailscaled.sock)",
+	"cli.help.discover.user":           "Login user for new peers (default: your user name)",
+	"cli.help.discover.key":            "Private key for new peers (default: password or Tailscale SSH)",
+	"cli.help.discover.dry_run":        "Preview the changes without saving",
+	"cli.usage.discover":               "usage: gossh discover tailscale [--socket=<path>] [--user=<name>] [--key=<path>] [--dry-run]",
+	"cli.discover.none":                "No peers found in the tailnet.",
+	"cli.discover.found":               "Found %d peers in the tailnet.",
+	"cli.discover.tags":                "TAGS",
+	"cli.discover.action.add":          "add",
+	"cli.discover.action.update":       "update",
+	"cli.discover.action.keep":         "unchanged",
+	"cli.discover.pending":             "Will add %d and update %d connections (%d skipped: name used outside the Tailnet group).",
+	"cli.discover.up_to_date":          "Tailnet connections are up to date.",
+	"cli.discover.done":                "Saved %d connections.",
+	"cli.check.no_match":               "No connections match the filter.",
+	"cli.check.checking":               "Checking %d connection(s)...",
+	"cli.check.reachable":              "reachable",
+	"cli.probe.probing":                "Probing %d port(s) of %s from %s...",
+	"cli.probe.open":                   "open",
+	"cli.probe.closed":                 "closed",
+	"cli.probe.timeout":                "timeout",
+	"cli.probe.forbidden":              "blocked",
+	"cli.probe.no_answer":              "no answer within %s",
+	"cli.probe.summary":                "%d of %d port(s) open",
+	"cli.agent.empty":                  "The agent holds no keys. Add one with ssh-add.",
+	"cli.agent.pinned_by":              "pinned by %s",
+	"cli.agent.pinned":                 "Pinned %s to %s (%s)",
+	"cli.agent.unpinned":               "%s offers all the agent's keys again",
+	"cli.agent.not_pinned":             "%s is not pinned to an agent key",
+	"cli.agent.no_key_file":            "%s has no key file: set one, or pin another agent key",
+	"cli.agent.key_not_found":          "no key in the agent has the fingerprint or comment %q",
+	"cli.agent.key_ambiguous":          "several keys in the agent have the comment %q: give the fingerprint",
+	"cli.facts.gathering":              "Gathering system facts from %d connection(s)...",
+	"cli.list.name":                    "NAME",
+	"cli.list.host":                    "HOST",
+	"cli.list.port":                    "PORT",
+	"cli.list.group":                   "GROUP",
+	"cli.list.total":                   "Total: %d connections",
+	"cli.rm.confirm":                   "Move connection '%s' (%s@%s:%d) to the trash? [y/N]: ",
+	"cli.rm.done":                      "Moved connection '%s' to the trash (restore with: gossh trash restore %[1]s)",
+	"cli.rm.confirm_purge":             "Permanently delete connection '%s' (%s@%s:%d)? [y/N]: ",
+	"cli.rm.purged":                    "Permanently deleted connection '%s'",
+	"cli.trash.empty":                  "Trash is empty",
+	"cli.trash.deleted":                "DELETED",
+	"cli.trash.expires":                "PURGED ON",
+	"cli.trash.not_found":              "connection '%s' not found in trash",
+	"cli.trash.restored":               "Restored connection '%s'",
+	"cli.trash.emptied":                "Permanently deleted %d connection(s)",
+	"cli.audit.changed":                "CHANGED",
+	"cli.audit.age":                    "AGE",
+	"cli.audit.due":                    "DUE",
+	"cli.audit.status":                 "STATUS",
+	"cli.audit.state.ok":               "ok",
+	"cli.audit.state.rotate":           "rotation overdue",
+	"cli.audit.state.expired":          "expired",
+	"cli.audit.summary":                "%d of %d connection(s) need attention",
+	"cli.audit.failed":                 "credential audit failed: %d connection(s) need attention",
+	"cli.validate.problem":             "PROBLEM",
+	"cli.validate.ok":                  "All %d connection(s) are valid",
+	"cli.validate.summary":             "%d problem(s) in %d of %d connection(s)",
+	"cli.validate.failed":              "validation failed: %d problem(s) found",
+	"cli.monitor.start":                "Probing %d connection(s) every %s, serving metrics on %s/metrics (Ctrl+C to stop)",
+	"cli.hook.failed":                  "warning: %v",
+	"cli.rename.done":                  "Renamed connection '%s' to '%s'",
+	"cli.lock.done":                    "Locked: running gossh sessions ask for the master password again",
+	"cli.lock.unprotected":             "nothing to lock: no master password is set (enable one in Settings)",
+	"cli.remember.done":                "Master key stored in the OS keyring: commands on this machine unlock without the password. Run 'gossh forget' to revoke it.",
+	"cli.plugins.none":                 "No plugins in %s. Plugins are executables named %s<command>.",
+	"cli.plugin.failed":                "plugin %s: %v",
+	"cli.remember.unprotected":         "nothing to remember: no master password is set",
+	"cli.forget.done":                  "Master key removed from the OS keyring",
+	"cli.dedupe.none":                  "No duplicate connections found",
+	"cli.dedupe.set":                   "Duplicate set %d of %d: %s",
+	"cli.dedupe.never":                 "never",
+	"cli.dedupe.sessions":              "%d sessions",
+	"cli.dedupe.confirm":               "Merge into %s (marked *)? [Y/n]: ",
+	"cli.dedupe.merged":                "Merged %d connections into %s",
+	"cli.dedupe.done":                  "%d duplicates merged; they are in the trash until purged",
+	"cli.history.empty":                "No edits of '%s' recorded yet",
+	"cli.history.when":                 "When",
+	"cli.history.by":                   "By",
+	"cli.history.fields":               "Changed",
+	"cli.history.not_found":            "no edit %s of '%s'; run gossh history <name> to list them",
+	"cli.history.edit":                 "Edit %d, %s by %s:",
+	"cli.history.restored":             "Restored '%s' to the version before edit %d",
+	"cli.workspace.empty":              "No workspaces saved. Save one with gossh workspace save <name> ...",
+	"cli.workspace.saved":              "Saved workspace '%s'",
+	"cli.workspace.removed":            "Removed workspace '%s'",
+	"cli.workspace.not_found":          "workspace '%s' not found",
+	"cli.workspace.no_snippet":         "no snippet '%s' in workspace '%s'",
+	"cli.workspace.sessions":           "sessions:",
+	"cli.workspace.tunnel":             "tunnel:",
+	"cli.workspace.snippet":            "snippet:",
+	"cli.workspace.window":             "Opened %s in a new tmux window",
+	"cli.workspace.session":            "Session %d of %d: %s (%s@%s:%d)",
+	"cli.workspace.tunnels_active":     "Tunnels active. Press Ctrl+C to stop.",
+	"cli.schedule.empty":               "No scheduled commands. Add one with gossh schedule add <cron> <command>",
+	"cli.schedule.added":               "Scheduled command %s, next run at %s",
+	"cli.schedule.removed":             "Removed scheduled command %s",
+	"cli.schedule.not_found":           "no single scheduled command with ID %s",
+	"cli.schedule.hosts":               "hosts:",
+	"cli.schedule.all_hosts":           "all connections",
+	"cli.schedule.next":                "next run:",
+	"cli.schedule.last":                "last run:",
+	"cli.schedule.last_run":            "%s, %d failed",
+	"cli.schedule.never":               "never",
+	"cli.schedule.daemon":              "Running %d scheduled commands when due. Press Ctrl+C to stop.",
+	"cli.schedule.stopping":            "Stopping, waiting for running commands...",
+	"cli.schedule.ran":                 "%d succeeded, %d failed (run %s)",
+	"cli.connect.connecting":           "Connecting to %s (%s@%s:%d)...",
+	"cli.connect.share.socket":         "Sharing session output read-only on %s. Watch with: %s",
+	"cli.connect.share.file":           "Mirroring session output to %s",
+	"cli.sftp.starting":                "Starting SFTP session to %s (%s@%s:%d)...",
+	"cli.sftp.connected":               "Connected. Type 'help' for available commands.",
+	"cli.sftp.local_dir":               "Local files are read from and saved to %s",
+	"cli.sftp.remote_dir_failed":       "Staying in the home directory, cannot open %s: %v",
+	"cli.sftp.commands":                "Commands:",
+	"cli.sftp.cmd.ls":                  "List directory",
+	"cli.sftp.cmd.cd":                  "Change directory",
+	"cli.sftp.cmd.pwd":                 "Print working directory",
+	"cli.sftp.cmd.copy":                "Copy the absolute remote path to the clipboard",
+	"cli.sftp.copied":                  "Copied %s to clipboard",
+	"cli.sftp.notify.failed":           "Transfer of %s failed",
+	"cli.sftp.cmd.get":                 "Download file, or directory with -r",
+	"cli.sftp.cmd.put":                 "Upload file, or directory with -r",
+	"cli.sftp.cmd.mkdir":               "Create directory",
+	"cli.sftp.cmd.rm":                  "Remove file, -f without asking",
+	"cli.sftp.cmd.rmdir":               "Remove directory, -f without asking",
+	"cli.sftp.cmd.empty_trash":         "Permanently delete what is in ~/.gossh-trash",
+	"cli.sftp.cmd.chmod":               "Change permissions, e.g. 644 or u+x; just a path shows them",
+	"cli.sftp.cmd.chown":               "Change owner and group, by name or number",
+	"cli.sftp.cmd.local":               "Run a local command, or just ! for a local shell",
+	"cli.sftp.cmd.du":                  "Show what takes up space in a directory",
+	"cli.sftp.cmd.queue":               "Queue a download or upload to run in the background",
+	"cli.sftp.cmd.jobs":                "List queued transfers and their progress",
+	"cli.sftp.cmd.pause":               "Pause a queued transfer, or all of them",
+	"cli.sftp.cmd.resume":              "Resume a paused transfer, or all of them",
+	"cli.sftp.cmd.cancel":              "Cancel a queued transfer, or all of them",
+	"cli.sftp.cmd.exit":                "Exit SFTP",
+	"cli.sftp.usage":                   "Usage: %s",
+	"cli.sftp.downloaded":              "Downloaded %s -> %s",
+	"cli.sftp.uploaded":                "Uploaded %s -> %s",
+	"cli.sftp.mkdir_done":              "Created directory %s",
+	"cli.sftp.rm_done":                 "Removed %s",
+	"cli.sftp.rmdir_done":              "Removed directory %s",
+	"cli.sftp.confirm.delete":          "Delete %s",
+	"cli.sftp.confirm.delete_dir":      "Delete %s with %d file(s) and %d subdirectory(ies)",
+	"cli.sftp.confirm.ask":             "%s? [y/N]: ",
+	"cli.sftp.confirm.trash":           "%s (to the trash)? [y/N]: ",
+	"cli.sftp.confirm.empty_trash":     "Permanently delete %d item(s) in %s? [y/N]: ",
+	"cli.sftp.trashed":                 "Moved %s to %s",
+	"cli.sftp.trash.empty":             "The trash is empty",
+	"cli.sftp.trash.emptied":           "Deleted %d item(s) from the trash",
+	"cli.sftp.chown_done":              "Changed owner of %s to %s",
+	"cli.sftp.changed":                 "Changed %d item(s) under %s",
+	"cli.sftp.local_exit":              "Local command exited with status %d",
+	"cli.sftp.perm.read":               "read",
+	"cli.sftp.perm.write":              "write",
+	"cli.sftp.perm.execute":            "execute",
+	"cli.sftp.perm.owner":              "owner",
+	"cli.sftp.perm.group":              "group",
+	"cli.sftp.perm.other":              "other",
+	"cli.sftp.du.computing":            "Computing sizes in %s...",
+	"cli.sftp.du.more":                 "... %d more, %s",
+	"cli.sftp.du.total":                "Total: %s in %d entries",
+	"cli.sftp.queue.added":             "Queued #%d: %s %s",
+	"cli.sftp.queue.empty":             "No transfers queued",
+	"cli.sftp.queue.no_transfer":       "No unfinished transfer %s",
+	"cli.sftp.queue.drained":           "Transfer queue finished: %d done, %d failed, %d canceled (%s in %s)",
+	"cli.sftp.queue.closing":           "Canceling %d unfinished transfer(s)...",
+	"cli.sftp.goodbye":                 "Goodbye!",
+	"cli.sftp.unknown":                 "Unknown command: %s. Type 'help' for available commands.",
+	"cli.sftp.batch.no_confirm":        "no (nothing is asked in batch mode, use -f)",
+	"cli.sftp.batch.stopped":           "Stopped: %s failed",
+	"cli.sftp.batch.queue_failed":      "%d queued transfer(s) failed",
+	"cli.forward.setup":                "Setting up port forwarding to %s (%s@%s:%d)...",
+	"cli.forward.active":               "Port forwarding active. Press Ctrl+C to stop.",
+	"cli.forward.stopping":             "Stopping port forwarding...",
+	"cli.forward.saved":                "Saved profile %s (%d forwards) for %s",
+	"cli.forward.save_nothing":         "--save needs -L, -R or -D forwards to save",
+	"cli.forward.no_profiles":          "No forward profiles saved for %s",
+	"cli.forward.no_profile":           "No forward profile %s for %s",
+	"cli.forward.gateway_needed":       "%s would listen on all interfaces of the server, where other hosts can reach it; pass --gateway to allow it",
+	"cli.forward.assigned":             "Remote port: %d on %s",
+	"cli.forward.local_port":           "Local port: %d",
+	"cli.forward.moved":                "Port %d is in use, listening on %d instead",
+	"cli.forward.hint":                 "Connect with:",
+	"cli.forward.hint_copied":          "Copied to clipboard",
+	"cli.forward.no_hint":              "No client command to copy, save one with --hint",
+	"cli.forward.gateway":              "Open to other hosts at %s, if the server's sshd has GatewayPorts clientspecified",
+	"cli.forward.profile":              "PROFILE",
+	"cli.forward.rule":                 "FORWARD",
+	"cli.forward.connections":          "OPEN / TOTAL",
+	"cli.forward.traffic":              "TRAFFIC",
+	"cli.exec.targets":                 "Executing command on %d server(s):",
+	"cli.exec.command":                 "Command: %s",
+	"cli.exec.timeout":                 "Timeout per host: %v",
+	"cli.exec.deadline":                "Deadline: %v",
+	"cli.exec.pty":                     "Pseudo-terminal: %dx%d",
+	"cli.exec.env":                     "Environment: %s",
+	"cli.exec.diff_output":             "--diff only works with text output",
+	"cli.exec.interrupted":             "Interrupted: remote commands were stopped. Partial results:",
+	"cli.exec.copied":                  "Output copied to clipboard",
+	"cli.exec.notify":                  "gossh exec finished: %d succeeded, %d failed",
+	"cli.play.plan":                    "Playbook %s: %d step(s)",
+	"cli.play.hosts":                   "on %d host(s): %s",
+	"cli.play.no_hosts":                "step %d (%s) matches no connections",
+	"cli.play.skipped":                 "skipped, its condition holds on no host",
+	"cli.play.skipped_hosts":           "skipped on %d host(s) where its condition does not hold",
+	"cli.play.continuing":              "failed on %d host(s), continuing",
+	"cli.play.failed":                  "Playbook %s stopped: step %d (%s) failed on %d host(s)",
+	"cli.play.done":                    "Playbook %s finished: %d step(s), %d tolerated failure(s)",
+	"cli.exec.retry_hint":              "%d host(s) failed. Retry them with: gossh exec --retry-last (run %s)",
+	"cli.exec.retrying":                "Retrying run %s from %s: %d of %d host(s) failed",
+	"cli.exec.no_run":                  "no run '%s' to retry",
+	"cli.exec.no_last_run":             "no earlier run to retry",
+	"cli.exec.nothing_to_retry":        "run %s did not fail on any host",
+	"cli.push.targets":                 "Pushing %s to %s on %d host(s):",
+	"cli.push.summary":                 "gossh push finished: %d succeeded, %d failed",
+	"cli.push.invalid_mode":            "invalid mode %q, use octal such as 0644",
+	"cli.push.not_file":                "%s is a directory, push takes a single file",
+	"cli.tail.following":               "Following %s on %d host(s), press Ctrl+C to stop",
+	"cli.watch.watching":               "Watching %s for changes to upload to %s:%s, press Ctrl+C to stop",
+	"cli.watch.stopped":                "Stopped watching: %d uploaded, %d failed",
+	"cli.archive.starting":             "Archiving %s:%s to %s",
+	"cli.archive.done":                 "Archived %s to %s (%s in %s)",
+	"cli.archive.failed":               "Archiving %s failed",
+	"cli.extract.starting":             "Extracting %s to %s:%s",
+	"cli.extract.done":                 "Extracted %s to %s (%s in %s)",
+	"cli.extract.failed":               "Extracting %s failed",
+
+	// Inventory report
+	"report.title":          "Connection Inventory",
+	"report.summary":        "Generated %s: %d host(s), %d with credentials needing attention.",
+	"report.hosts":          "Hosts",
+	"report.groups":         "Groups",
+	"report.tags":           "Tags",
+	"report.name":           "Name",
+	"report.address":        "Address",
+	"report.group":          "Group",
+	"report.last_seen":      "Last connected",
+	"report.health":         "Health",
+	"report.health.success": "up",
+	"report.health.failed":  "down",
+	"report.health.unknown": "unknown",
+	"report.auth":           "Auth",
+	"report.changed":        "Credentials changed",
+	"report.age":            "Age",
+	"report.credentials":    "Credentials",
+	"report.system":         "System",
+	"cli.report.done":       "Wrote report of %d host(s) to %s",
 }
@@ -0,0 +1,565 @@
+package i18n
+
+// messagesES contains Spanish translations. It leaves out the cli.* output of
+// commands, which is shown in English (see TestCatalogKeys).
+var messagesES = map[string]string{
+	// App general
+	"app.name":    "GoSSH",
+	"app.version": "Versión",
+	"app.welcome": "Bienvenido a GoSSH",
+
+	// Menu and navigation
+	"menu.connections": "Conexiones",
+	"menu.settings":    "Ajustes",
+	"menu.help":        "Ayuda",
+	"menu.quit":        "Salir",
+
+	// Connection list
+	"list.title":              "Conexiones SSH",
+	"list.empty":              "Aún no hay conexiones. Pulsa 'a' para añadir una.",
+	"list.empty.search":       "No hay conexiones coincidentes.",
+	"list.search":             "Buscar",
+	"list.filter":             "Filtro: %s (pulsa / para buscar, esc para limpiar)",
+	"list.filter.all":         "Todas",
+	"list.filter.group":       "Grupo",
+	"list.total":              "Total: %d conexiones",
+	"list.showing":            " (mostrando %d)",
+	"list.ungrouped":          "Sin grupo",
+	"list.status.unknown":     "?",
+	"list.status.ok":          "✓",
+	"list.status.fail":        "✗",
+	"list.status.checking":    "...",
+	"list.trashed":            "Movida a la papelera: restáurala desde Ajustes",
+	"list.copied":             "Copiado: %s",
+	"list.copy_failed":        "Error al copiar",
+	"list.stale.rotate":       "renovar",
+	"list.stale.expired":      "caducada",
+	"list.uptime":             "activo %s",
+	"list.via":                "(vía %s)",
+	"list.column.name":        "Nombre",
+	"list.column.host":        "Usuario@Host",
+	"list.column.group":       "Grupo",
+	"list.column.tags":        "Etiquetas",
+	"list.column.last_seen":   "Última conexión",
+	"list.column.status":      "Estado",
+	"list.column.system":      "Sistema",
+	"list.help":               "a:añadir  e:editar  d:eliminar  /:buscar  s:ajustes  t:probar  y:copiar  enter:conectar  ?:ayuda  q:salir",
+	"list.help.search":        "escribe para buscar  enter:confirmar  esc:cancelar",
+	"list.search.placeholder": "Buscar...",
+
+	// Connection form
+	"form.title.add":               "Añadir conexión",
+	"form.title.edit":              "Editar conexión",
+	"form.name":                    "Nombre",
+	"form.name.hint":               "Un nombre descriptivo para esta conexión",
+	"form.host":                    "Host",
+	"form.host.hint":               "Nombre de host o dirección IP",
+	"form.type":                    "Protocolo",
+	"form.port":                    "Puerto",
+	"form.port.hint":               "Puerto SSH (predeterminado: 22)",
+	"form.user":                    "Usuario",
+	"form.user.hint":               "Usuario SSH",
+	"form.auth_type":               "Autenticación",
+	"form.auth.password":           "Contraseña",
+	"form.auth.key":                "Clave privada",
+	"form.password":                "Contraseña",
+	"form.password.hint":           "Contraseña SSH",
+	"form.key_path":                "Ruta de la clave",
+	"form.key_path.hint":           "Ruta al archivo de clave privada",
+	"form.key_passphrase":          "Frase de la clave",
+	"form.key_pass.hint":           "Frase de paso de la clave privada (si tiene)",
+	"form.group":                   "Grupo",
+	"form.group.hint":              "Grupo de la conexión",
+	"form.tags":                    "Etiquetas",
+	"form.tags.hint":               "Etiquetas separadas por comas",
+	"form.startup_cmd":             "Comando inicial",
+	"form.notes":                   "Notas",
+	"form.rotate_after":            "Renovar tras",
+	"form.expires_at":              "Caduca",
+	"form.startup_cmd.hint":        "Comando a ejecutar tras conectar",
+	"form.save":                    "Guardar",
+	"form.cancel":                  "Cancelar",
+	"form.error.required":          "Este campo es obligatorio",
+	"form.error.port":              "Número de puerto no válido",
+	"form.placeholder.name":        "Mi servidor",
+	"form.placeholder.host":        "192.168.1.1 o example.com",
+	"form.placeholder.optional":    "(opcional)",
+	"form.placeholder.notes":       "Cualquier cosa que merezca recordar de este host",
+	"form.auth.opt.password":       "contraseña",
+	"form.auth.opt.key":            "clave",
+	"form.agent_key":               "Clave del agente",
+	"form.agent_key.any":           "cualquier clave",
+	"form.agent_key.missing":       "%s (no está en el agente)",
+	"form.note.toggle":             "(espacio para alternar)",
+	"form.note.select":             "(espacio para elegir)",
+	"form.note.optional":           "(opcional)",
+	"form.note.revealed":           "(visible 15 s, ctrl+r la oculta)",
+	"form.note.agent_key":          "(clave de ssh-agent a ofrecer, en lugar de todas)",
+	"form.note.tags":               "(espacio o coma añade, → completa)",
+	"form.aliases":                 "Alias",
+	"form.note.aliases":            "(otros nombres para conectar y buscar)",
+	"form.addresses":               "Otras direcciones",
+	"form.note.addresses":          "(se prueban en orden si el host falla)",
+	"form.note.key_path":           "(ctrl+o para explorar)",
+	"form.note.host":               "(o srv:_ssh._tcp.nombre / consul:servicio)",
+	"form.jump_hosts":              "Hosts de salto",
+	"form.note.jump_hosts":         "(conexiones guardadas, en orden)",
+	"form.bind_address":            "Dirección de origen",
+	"form.term":                    "Tipo de terminal",
+	"form.locale":                  "Locale",
+	"form.local_dir":               "Directorio local",
+	"form.remote_dir":              "Directorio remoto",
+	"form.note.bind_address":       "(IP o interfaz local, opcional)",
+	"form.note.term":               "(TERM a solicitar, por defecto: el local)",
+	"form.note.locale":             "(LANG/LC_ALL a solicitar, opcional)",
+	"form.note.local_dir":          "(las transferencias SFTP empiezan aquí, opcional)",
+	"form.note.remote_dir":         "(SFTP abre aquí, por defecto: home)",
+	"form.note.startup":            "(un comando por línea, se ejecuta al conectar)",
+	"form.pre_connect":             "Antes de conectar",
+	"form.post_disconnect":         "Tras desconectar",
+	"form.note.pre_connect":        "(comando local, la sesión empieza si tiene éxito)",
+	"form.note.post_disconnect":    "(comando local, se ejecuta tras la sesión)",
+	"form.quiet_login":             "Inicio silencioso",
+	"form.show_banner":             "Mostrar banner",
+	"form.gssapi":                  "GSSAPI",
+	"form.note.quiet_login":        "(oculta el MOTD y el último inicio de sesión)",
+	"form.note.show_banner":        "(muestra primero el aviso del servidor)",
+	"form.note.gssapi":             "(prueba Kerberos primero si hay un ticket)",
+	"form.note.gssapi_unsupported": "(requiere compilar con -tags gssapi)",
+	"form.opt.on":                  "sí",
+	"form.opt.off":                 "no",
+	"form.note.rotate_after":       "(días, opcional)",
+	"form.help":                    "tab:siguiente campo  enter:guardar  ctrl+t:probar  f1:ayuda  esc:cancelar",
+	"form.test.running":            "Probando la conexión...",
+	"form.test.ok":                 "✓ Conexión correcta (%s)",
+	"form.test.host_key":           "La clave del host ha cambiado",
+	"form.keys.title":              "Seleccionar archivo de clave",
+	"form.keys.none":               "No se encontraron claves privadas en %s",
+	"form.keys.encrypted":          "[cifrada]",
+	"form.keys.help":               "↑/↓:mover  enter:seleccionar  esc:cancelar",
+
+	// Setup
+	"setup.title":                  "Bienvenido a GoSSH",
+	"setup.desc":                   "Elige tu modo de seguridad:",
+	"setup.option.password":        "[1] Activar protección con contraseña (recomendado)",
+	"setup.option.password.desc":   "Define una contraseña maestra que se pedirá en cada inicio",
+	"setup.option.nopassword":      "[2] Omitir protección con contraseña",
+	"setup.option.nopassword.desc": "Inicio rápido, sin contraseña",
+	"setup.password.title":         "Definir contraseña maestra",
+	"setup.password.desc":          "Esta contraseña cifra tus credenciales guardadas. ¡Recuérdala!",
+	"setup.password.prompt":        "Introduce la contraseña maestra",
+	"setup.password.confirm":       "Confirmar contraseña",
+	"setup.password.hint":          "Mínimo 8 caracteres",
+	"setup.password.mismatch":      "Las contraseñas no coinciden",
+	"setup.password.weak":          "La contraseña es demasiado débil",
+	"setup.password.strength":      "Seguridad de la contraseña",
+	"setup.complete":               "¡Configuración completada!",
+	"setup.help.choose":            "↑/↓:seleccionar  1/2:selección rápida  enter:confirmar  esc:salir",
+	"setup.help.password":          "tab:siguiente campo  enter:confirmar  esc:volver",
+	"setup.placeholder.password":   "Introduce la contraseña maestra",
+	"setup.placeholder.confirm":    "Confirma la contraseña maestra",
+
+	// Unlock
+	"unlock.title":       "GoSSH bloqueado",
+	"unlock.prompt":      "Introduce la contraseña maestra para desbloquear:",
+	"unlock.label":       "Contraseña:",
+	"unlock.error":       "Contraseña incorrecta",
+	"unlock.attempt":     "[Intentos fallidos: %d]",
+	"unlock.attempts":    "intentos restantes",
+	"unlock.failed":      "Demasiados intentos fallidos. Inténtalo de nuevo en %s.",
+	"unlock.help":        "enter:desbloquear  esc:salir",
+	"unlock.placeholder": "Introduce la contraseña maestra",
+	"lock.unprotected":   "Nada que bloquear: define primero una contraseña maestra en Ajustes",
+
+	// Confirm dialog
+	"confirm.title":      "Confirmar",
+	"confirm.delete":     "Eliminar conexión",
+	"confirm.delete.msg": "¿Mover esta conexión a la papelera?",
+	"confirm.yes":        "Sí",
+	"confirm.no":         "No",
+	"confirm.help":       "y:sí  n:no  tab:alternar  enter:confirmar  esc:cancelar",
+	"confirm.default":    "¿Estás seguro?",
+
+	// Help
+	"help.title":              "Ayuda de GoSSH",
+	"help.navigation":         "Navegación",
+	"help.connection":         "Gestión de conexiones",
+	"help.form":               "Navegación del formulario",
+	"help.general":            "General",
+	"help.settings":           "Configuración",
+	"help.actions":            "Acciones",
+	"help.key.up":             "Subir",
+	"help.key.down":           "Bajar",
+	"help.key.top":            "Ir al principio",
+	"help.key.bottom":         "Ir al final",
+	"help.key.left":           "Desplazar a la izquierda",
+	"help.key.right":          "Desplazar a la derecha",
+	"help.key.color_prev":     "Color anterior",
+	"help.key.color_next":     "Color siguiente",
+	"help.key.layout":         "Alternar entre líneas y tabla",
+	"help.key.sort":           "Ordenar la tabla por la siguiente columna",
+	"help.key.sort_reverse":   "Invertir el orden",
+	"help.key.filters":        "Filtros guardados",
+	"help.key.toggle_column":  "Mostrar u ocultar una columna",
+	"help.key.rule_add":       "Añadir una regla de agrupación",
+	"help.key.rule_delete":    "Eliminar la regla seleccionada",
+	"help.key.default_group":  "Definir el grupo predeterminado",
+	"help.key.rule_preview":   "Previsualizar las reglas en las conexiones existentes",
+	"help.key.rule_apply":     "Aplicar las reglas a las conexiones existentes",
+	"help.key.merge_local":    "Conservar el valor local",
+	"help.key.merge_incoming": "Usar el valor importado",
+	"help.key.merge_apply":    "Importar con los valores elegidos",
+	"help.key.dedupe_pick":    "Elegir la conexión que se conserva",
+	"help.key.dedupe_merge":   "Fusionar las demás en ella",
+	"help.key.search":         "Buscar conexiones",
+	"help.key.connect":        "Conectar al servidor seleccionado",
+	"help.key.enter":          "Conectar / Seleccionar",
+	"help.key.add":            "Añadir nueva conexión",
+	"help.key.edit":           "Editar conexión seleccionada",
+	"help.key.delete":         "Eliminar conexión seleccionada",
+	"help.key.tab":            "Campo siguiente",
+	"help.key.shifttab":       "Campo anterior",
+	"help.key.space":          "Alternar una opción / abrir una lista",
+	"help.key.save":           "Guardar",
+	"help.key.cancel":         "Cancelar",
+	"help.key.help":           "Mostrar esta ayuda",
+	"help.key.quit":           "Salir de la aplicación",
+	"help.key.back":           "Volver / Cancelar",
+	"help.key.settings":       "Ajustes",
+	"help.key.test":           "Probar conexión",
+	"help.key.test_all":       "Probar todas las conexiones en segundo plano",
+	"help.key.broadcast":      "Escribir en los hosts listados a la vez",
+	"help.key.exec":           "Ejecutar un comando en los hosts listados",
+	"help.key.history":        "Historial de cambios de la conexión",
+	"help.key.scrollback":     "Buscar en la salida de la última sesión",
+	"help.key.plugins":        "Ejecutar un plugin en la conexión",
+	"help.key.details":        "Detalles y adjuntos",
+	"help.key.local":          "Ejecutar un comando local o una shell",
+	"help.key.lock":           "Bloquear: olvidar las claves hasta introducir la contraseña maestra",
+	"help.key.copy":           "Copiar comando ssh",
+	"help.key.test_form":      "Probar la conexión introducida",
+	"help.key.browse":         "Elegir un archivo de clave privada",
+	"help.key.reveal":         "Mostrar u ocultar la contraseña y la frase de la clave",
+	"help.key.open":           "Abrir el elemento seleccionado",
+	"help.key.restore":        "Restaurar la conexión seleccionada",
+	"help.key.purge":          "Eliminar definitivamente (pulsar dos veces)",
+	"help.return":             "Pulsa cualquier tecla para volver",
+	"help.scroll":             "↑/↓ desplazar",
+
+	// Settings
+	"settings.title":                      "Ajustes",
+	"settings.language":                   "Idioma",
+	"settings.security":                   "Seguridad",
+	"settings.password.enable":            "Activar contraseña maestra",
+	"settings.password.change":            "Cambiar contraseña maestra",
+	"settings.password.disable":           "Desactivar contraseña maestra",
+	"settings.about":                      "Acerca de",
+	"settings.save":                       "Guardar",
+	"settings.cancel":                     "Cancelar",
+	"settings.saved":                      "Ajustes guardados",
+	"settings.help":                       "↑/↓: navegar • enter: seleccionar • ?: ayuda • esc: volver",
+	"settings.help.language":              "↑/↓: elegir idioma • enter: confirmar • esc: volver",
+	"settings.help.password":              "tab/↑/↓: cambiar campo • enter: confirmar • esc: volver",
+	"settings.help.password.disable":      "enter: confirmar • esc: volver",
+	"settings.import":                     "Importar conexiones",
+	"settings.export":                     "Exportar conexiones",
+	"settings.trash":                      "Papelera (%d)",
+	"settings.notify":                     "Notificaciones: %s",
+	"settings.notify.off":                 "Desactivadas",
+	"settings.notify.bell":                "Campana del terminal",
+	"settings.notify.desktop":             "Escritorio",
+	"settings.keep_warm":                  "Mantener conexiones abiertas: %s",
+	"settings.keep_warm.off":              "No",
+	"settings.keep_warm.minutes":          "%d min",
+	"settings.scrollback":                 "Guardar la salida de las sesiones: %s",
+	"settings.key_cache":                  "Recordar la clave maestra para la CLI: %s",
+	"settings.theme":                      "Tema: %s",
+	"settings.theme.dark":                 "Oscuro",
+	"settings.theme.light":                "Claro",
+	"settings.connection":                 "Conexiones y terminal",
+	"settings.connection.title":           "Conexiones y terminal",
+	"settings.on":                         "Sí",
+	"settings.off":                        "No",
+	"settings.seconds":                    "%d s",
+	"settings.timeout":                    "Tiempo de espera de conexión: %s",
+	"settings.timeout.prompt":             "Segundos que puede tardar la conexión a un host:",
+	"settings.default_port":               "Puerto por defecto: %d",
+	"settings.port.prompt":                "Puerto para las nuevas conexiones SSH:",
+	"settings.default_user":               "Usuario por defecto: %s",
+	"settings.default_user.none":          "ninguno",
+	"settings.user.prompt":                "Usuario para las nuevas conexiones (vacío para ninguno):",
+	"settings.keepalive":                  "Intervalo de keepalive: %s",
+	"settings.keepalive.default":          "por defecto (10 s)",
+	"settings.keepalive.prompt":           "Segundos entre keepalives en las sesiones (0 para el valor por defecto):",
+	"settings.health":                     "Comprobación en segundo plano: %s",
+	"settings.health.off":                 "No",
+	"settings.health.minutes":             "cada %d min",
+	"settings.confirm_delete":             "Confirmar antes de eliminar: %s",
+	"settings.term":                       "Tipo de terminal (TERM): %s",
+	"settings.term.local":                 "local",
+	"settings.term.prompt":                "TERM para las conexiones que no definen uno (vacío para el local):",
+	"settings.locale":                     "Locale: %s",
+	"settings.locale.none":                "sin cambios",
+	"settings.locale.prompt":              "Locale para las conexiones que no definen uno, p. ej. C.UTF-8 (vacío para no enviar ninguno):",
+	"settings.rules":                      "Reglas de agrupación (%d)",
+	"settings.rules.title":                "Reglas de agrupación",
+	"settings.rules.empty":                "No hay reglas. Pulsa n para archivar hosts como \\.prod\\.example\\.com$ en Production.",
+	"settings.rules.default":              "Grupo por defecto: %s",
+	"settings.rules.default.none":         "ninguno",
+	"settings.rules.default.prompt":       "Grupo para las nuevas conexiones que ninguna regla archiva (vacío para ninguno):",
+	"settings.rules.pattern":              "Patrón (expresión regular sobre el nombre o el host):",
+	"settings.rules.pattern.placeholder":  "p. ej. \\.prod\\.example\\.com$",
+	"settings.rules.group":                "Grupo:",
+	"settings.rules.tags":                 "Etiquetas (separadas por comas):",
+	"settings.rules.preview.title":        "Reglas de agrupación: simulación",
+	"settings.rules.preview.empty":        "Las reglas no cambiarían ninguna conexión existente.",
+	"settings.rules.preview.count":        "Cambiarían %d conexiones existentes:",
+	"settings.rules.preview.more":         "… y %d más",
+	"settings.rules.applied":              "%d conexiones archivadas",
+	"settings.help.rules":                 "↑/↓: seleccionar • n: añadir • d: eliminar • g: grupo por defecto • p: simular • esc: volver",
+	"settings.help.rules.add":             "tab: siguiente campo • enter: guardar • esc: cancelar",
+	"settings.help.rules.default":         "enter: guardar • esc: cancelar",
+	"settings.help.rules.preview":         "enter: aplicar a las conexiones existentes • esc: volver",
+	"settings.help.value":                 "enter: guardar • esc: cancelar",
+	"filters.title":                       "Filtros guardados",
+	"filters.empty":                       "No hay filtros guardados. Busca primero con / y luego pulsa n aquí para guardar la búsqueda.",
+	"filters.name":                        "Nombre:",
+	"filters.name.placeholder":            "p. ej. prod web",
+	"filters.no_search":                   "Busca primero con / para tener algo que guardar",
+	"filters.help":                        "enter:aplicar  n:guardar la búsqueda actual  d:eliminar  esc:cerrar",
+	"filters.help.naming":                 "enter:guardar  esc:cancelar",
+	"plugins.title":                       "Plugins para %s",
+	"plugins.empty":                       "No hay plugins. Coloca ejecutables llamados %s<comando> en %s",
+	"plugins.help":                        "enter:ejecutar  esc:cerrar",
+	"details.title":                       "Detalles: %s",
+	"details.address":                     "Dirección",
+	"details.group":                       "Grupo",
+	"details.tags":                        "Etiquetas",
+	"details.password":                    "Contraseña",
+	"details.key_passphrase":              "Frase de la clave",
+	"details.agent_key":                   "Clave del agente",
+	"details.notes":                       "Notas",
+	"details.attachments":                 "Adjuntos",
+	"details.attachments.empty":           "Aún no hay adjuntos",
+	"details.add.prompt":                  "Adjuntar archivo:",
+	"details.export.prompt":               "Exportar a:",
+	"details.added":                       "%s adjuntado",
+	"details.deleted":                     "%s eliminado",
+	"details.exported":                    "%s exportado a %s",
+	"details.exists":                      "el archivo existe, no se ha sobrescrito",
+	"details.not_file":                    "no es un archivo normal",
+	"details.binary":                      "%s no es texto: expórtalo para abrirlo",
+	"details.delete.confirm":              "¿Eliminar %s? (y/n)",
+	"details.revealed":                    "Visibles 15 s: ctrl+r las oculta",
+	"details.help":                        "enter:ver  a:adjuntar  x:exportar  d:eliminar  ctrl+r:mostrar contraseñas  esc:cerrar",
+	"history.title":                       "Historial: %s",
+	"history.empty":                       "Aún no hay cambios registrados",
+	"history.restored":                    "Restaurada la versión anterior al cambio de %s",
+	"history.help":                        "↑/↓: seleccionar • r: restaurar esta versión • esc: volver",
+	"scrollback.title":                    "Salida: %s",
+	"scrollback.position":                 "líneas %d-%d de %d",
+	"scrollback.dropped":                  "(se descartó la salida más antigua)",
+	"scrollback.empty":                    "La sesión no imprimió nada",
+	"scrollback.help":                     "↑/↓/pgup/pgdn: desplazar • g/G: inicio/fin • /: buscar • n/N: coincidencia anterior/siguiente • esc: volver",
+	"scrollback.search.placeholder":       "texto a buscar",
+	"scrollback.no_match":                 "Ninguna línea contiene \"%s\"",
+	"scrollback.off":                      "La salida de las sesiones no se guarda, actívalo en Ajustes",
+	"scrollback.none":                     "Aún no se ha guardado la salida de ninguna sesión con %s",
+	"settings.trash.title":                "Papelera",
+	"settings.trash.empty":                "La papelera está vacía",
+	"settings.trash.info":                 "eliminada %s · se purga en %d días",
+	"settings.trash.restored":             "'%s' restaurada",
+	"settings.trash.purged":               "'%s' eliminada definitivamente",
+	"settings.trash.confirm_purge":        "Pulsa p otra vez para eliminar '%s' definitivamente",
+	"settings.groups":                     "Colores de grupo",
+	"settings.groups.title":               "Colores de grupo",
+	"settings.groups.empty":               "No hay grupos",
+	"settings.layout":                     "Diseño de la lista: %s",
+	"settings.layout.lines":               "Líneas",
+	"settings.layout.table":               "Tabla",
+	"settings.columns":                    "Columnas de la tabla",
+	"settings.columns.note":               "Las columnas se muestran cuando el diseño de la lista es Tabla (pulsa v en la lista)",
+	"settings.transfer.path":              "Ruta del archivo",
+	"settings.transfer.format":            "Formato",
+	"settings.transfer.format.yaml":       "GoSSH (YAML)",
+	"settings.transfer.format.ssh_config": "Configuración de OpenSSH",
+	"settings.transfer.note.import":       "Las conexiones que ya existen, por nombre, alias o dirección, se combinan campo a campo",
+	"settings.import.result":              "Importación completada",
+	"settings.export.result":              "Exportación completada",
+	"settings.transfer.file":              "Archivo",
+	"settings.transfer.imported":          "Importadas",
+	"settings.transfer.skipped":           "Omitidas",
+	"settings.transfer.merged":            "Combinadas",
+	"settings.merge.title":                "Conflictos de importación: %d",
+	"settings.merge.summary":              "Se añadirán %d conexiones nuevas y %d no cambian. Elige el valor que se conserva en cada campo.",
+	"settings.help.merge":                 "↑/↓: seleccionar • ←: local • →: importado • espacio: alternar • enter: importar • esc: cancelar",
+	"settings.duplicates":                 "Duplicados (%d)",
+	"settings.duplicates.title":           "Conexiones duplicadas",
+	"settings.duplicates.empty":           "No hay dos conexiones que inicien sesión con el mismo usuario, host y puerto.",
+	"settings.duplicates.keep":            "conservar",
+	"settings.duplicates.done":            "%d conexiones combinadas en %s; las demás están en la papelera",
+	"settings.help.duplicates":            "↑/↓: grupo de duplicados • ←/→: conservar • enter: combinar • esc: volver",
+	"settings.transfer.exported":          "Exportadas",
+	"settings.help.transfer":              "tab: cambiar de campo • ←/→: formato • enter: ejecutar • esc: volver",
+	"settings.help.result":                "enter/esc: volver",
+	"settings.help.trash":                 "↑/↓: seleccionar • r/enter: restaurar • p: purgar • esc: volver",
+	"settings.help.groups":                "↑/↓: seleccionar • ←/→: cambiar color • esc: volver",
+	"settings.help.columns":               "↑/↓: seleccionar • espacio: mostrar/ocultar • esc: volver",
+
+	// Host key verification
+	"hostkey.title":       "Verificación de clave de host",
+	"hostkey.unknown":     "Host desconocido",
+	"hostkey.unknown.msg": "No se puede verificar la autenticidad del host '%s'.",
+	"hostkey.fingerprint": "Huella digital",
+	"hostkey.keytype":     "Tipo de clave",
+	"hostkey.trust":       "¿Confiar en este host y continuar la conexión?",
+	"hostkey.changed":     "ADVERTENCIA: ¡La clave del host ha cambiado!",
+	"hostkey.changed.msg": "La clave del host '%s' ha cambiado. ¡Podría tratarse de un ataque de intermediario!",
+	"hostkey.accept":      "Aceptar",
+	"hostkey.reject":      "Rechazar",
+	"hostkey.update":      "Actualizar",
+	"hostkey.help":        "y:aceptar  n:rechazar  enter:confirmar",
+	"banner.title":        "Banner de %s",
+	"banner.help":         "enter:continuar  esc:cancelar",
+	"hostkey.previous":    "Huella anterior:",
+
+	// Health check
+	"health.title":              "Prueba de conexión",
+	"health.testing":            "Probando conexión...",
+	"health.all.progress":       "Comprobando conexiones... %d/%d",
+	"health.all.done":           "%d conexiones comprobadas: %d activas, %d caídas",
+	"broadcast.title.all":       "Difusión a %d hosts",
+	"broadcast.title.single":    "Escribiendo solo en %s",
+	"broadcast.help":            "ctrl+n/ctrl+p:cambiar host  ctrl+t:todos/uno  ctrl+q:cerrar",
+	"broadcast.confirm":         "Difusión",
+	"broadcast.confirm.msg":     "¿Abrir una shell en %d hosts y escribir en todos a la vez?",
+	"broadcast.closed":          "Difusión cerrada",
+	"broadcast.dropped":         "Se descartaron %d entradas: este host no da abasto",
+	"exec.title":                "Ejecutar en %d hosts",
+	"exec.command":              "Comando:",
+	"exec.command.placeholder":  "uptime",
+	"exec.progress":             "%d/%d listos · %d en curso · %d con error",
+	"exec.eta":                  "quedan unos %s",
+	"exec.exit":                 "salida %d",
+	"exec.finished":             "%d correctos, %d con error",
+	"exec.done":                 "Comando terminado: %d correctos, %d con error",
+	"exec.stopping":             "Deteniendo el comando en los hosts...",
+	"exec.help.input":           "enter: ejecutar • esc: cancelar",
+	"exec.help.running":         "↑/↓: elegir host • esc: detener",
+	"exec.help.done":            "↑/↓: elegir host • esc: volver",
+	"local.title":               "Comando local",
+	"local.command":             "Comando:",
+	"local.command.placeholder": "vacío para una shell, exit para volver",
+	"local.help":                "enter: ejecutar • esc: cancelar",
+	"local.return":              "Pulsa Enter para volver a gossh",
+	"local.exit":                "El comando local terminó con estado %d",
+	"health.checking":           "Comprobando...",
+	"health.reachable":          "Accesible",
+	"health.unreachable":        "Inaccesible",
+	"health.auth_failed":        "Fallo de autenticación",
+	"health.result.success":     "✓ Conexión correcta",
+	"health.result.fail":        "✗ Conexión fallida",
+
+	// SFTP
+	"sftp.connected":   "SFTP conectado a %s",
+	"sftp.pwd":         "Directorio actual: %s",
+	"sftp.uploading":   "Subiendo: %s",
+	"sftp.downloading": "Descargando: %s",
+	"sftp.progress":    "%d%% (%s / %s)",
+	"sftp.complete":    "Transferencia completada",
+
+	// Import
+	"import.title":          "Importar configuración SSH",
+	"import.reading":        "Leyendo %s...",
+	"import.found":          "Se encontraron %d conexiones",
+	"import.importing":      "Importando...",
+	"import.skip.duplicate": "Omitiendo duplicado: %s",
+	"import.complete":       "Importación completada: %d importadas, %d omitidas",
+	"wizard.title":          "Importar hosts SSH",
+	"wizard.desc":           "Se encontraron %d hosts nuevos en %s. Elige los hosts a importar:",
+	"wizard.selected":       "%d de %d seleccionados",
+	"wizard.help":           "↑/↓:mover  espacio:alternar  a:todos  g:cambiar grupo  G:grupo para todos  enter:importar  esc:omitir",
+
+	// Errors
+	"error.connection":                  "Fallo de conexión",
+	"error.auth":                        "Fallo de autenticación",
+	"error.timeout":                     "Tiempo de conexión agotado",
+	"error.unknown":                     "Error desconocido",
+	"error.validation.name":             "el nombre es obligatorio",
+	"error.validation.host":             "el host es obligatorio",
+	"error.validation.host_invalid":     "el host debe ser un nombre de host o una dirección IP",
+	"error.validation.aliases":          "los alias deben ser palabras sueltas distintas del nombre",
+	"error.validation.addresses":        "las otras direcciones deben ser nombres de host o direcciones IP",
+	"error.validation.user":             "el usuario es obligatorio",
+	"error.validation.port":             "el puerto debe estar entre 1 y 65535",
+	"error.validation.key_path":         "la ruta de la clave es obligatoria para la autenticación por clave",
+	"error.validation.key_file":         "el archivo de clave no se puede leer o no es una clave privada",
+	"error.validation.key_passphrase":   "la clave está cifrada, introduce su frase de paso",
+	"error.validation.agent_key":        "la clave del agente debe ser una huella SHA256, tal como la imprime ssh-add -l",
+	"error.validation.term":             "el tipo de terminal debe ser una palabra, p. ej. vt100",
+	"error.validation.locale":           "el locale debe ser una palabra, p. ej. C.UTF-8",
+	"error.validation.rotate_after":     "el periodo de renovación debe ser un número positivo de días",
+	"error.validation.expires_at":       "la fecha de caducidad debe tener el formato AAAA-MM-DD",
+	"error.validation.pattern":          "el patrón debe ser una expresión regular válida",
+	"error.validation.rule":             "una regla necesita un grupo o etiquetas",
+	"error.validation.workspace":        "un espacio de trabajo necesita conexiones o túneles",
+	"error.validation.tunnel":           "un túnel necesita una conexión, -L o -R y una especificación de reenvío",
+	"error.validation.forward_profiles": "un perfil de reenvío necesita un nombre sin espacios y especificaciones -L, -R o -D",
+	"error.validation.snippet":          "un fragmento necesita un nombre y un comando",
+	"error.validation.cron":             "la programación debe ser una especificación cron, p. ej. 0 3 * * *",
+	"error.validation.command":          "el comando es obligatorio",
+	"error.validation.timeout":          "el tiempo de espera debe ser un número positivo de segundos",
+	"error.validation.interval":         "el intervalo debe ser un número positivo, o 0 para el valor por defecto",
+	"error.validation.theme":            "el tema debe ser dark o light",
+	"error.validation.default_user":     "el usuario por defecto debe ser una palabra",
+	"error.password.invalid":            "contraseña no válida",
+	"error.password.weak":               "contraseña demasiado débil: se requieren al menos 8 caracteres",
+	"error.attachment.too_large":        "los adjuntos están limitados a %d KB",
+	"error.attachment.empty":            "el archivo está vacío",
+	"error.attachment.exists":           "la conexión ya tiene un adjunto con ese nombre",
+	"error.locked":                      "desbloquea gossh primero",
+	"error.insecure_dir":                "otros usuarios pueden leer %s: exporta las contraseñas a un lugar privado",
+	"error.password.policy.length":      "contraseña demasiado débil: se requieren al menos %d caracteres",
+	"error.password.policy.classes":     "contraseña demasiado débil: usa al menos %d de minúsculas, mayúsculas, dígitos y símbolos",
+	"error.password.policy.common":      "contraseña demasiado débil: es una contraseña común o contiene una palabra bloqueada",
+	"error.password.policy.score":       "contraseña demasiado fácil de adivinar: hazla más larga o usa varias palabras sin relación",
+
+	// Common
+	"common.loading":                 "Cargando...",
+	"common.saving":                  "Guardando...",
+	"common.success":                 "Éxito",
+	"common.error":                   "Error",
+	"common.back":                    "Volver",
+	"common.next":                    "Siguiente",
+	"common.done":                    "Hecho",
+	"common.connecting":              "Conectando a %s...",
+	"session.summary":                "%s: salió con %d tras %s",
+	"session.pre_connect.failed":     "falló el comando pre_connect: %v",
+	"session.post_disconnect.failed": "falló el comando post_disconnect: %v",
+	"session.suspended":              "%s suspendida — pulsa enter sobre ella para reanudar",
+	"status.protected":               "contraseña maestra activa",
+	"status.unprotected":             "sin contraseña maestra",
+	"status.sessions":                "sesiones suspendidas: %d",
+	"status.jobs":                    "tareas en segundo plano: %d",
+	"session.closed":                 "Conexión con %s cerrada",
+	"common.conn_error":              "Error de conexión: %s",
+	"common.state_error":             "No se pudo guardar el estado de la conexión: %v",
+	"report.title":                   "Inventario de conexiones",
+	"report.summary":                 "Generado %s: %d host(s), %d con credenciales que requieren atención.",
+	"report.hosts":                   "Hosts",
+	"report.groups":                  "Grupos",
+	"report.tags":                    "Etiquetas",
+	"report.name":                    "Nombre",
+	"report.address":                 "Dirección",
+	"report.group":                   "Grupo",
+	"report.last_seen":               "Última conexión",
+	"report.health":                  "Estado",
+	"report.health.success":          "activo",
+	"report.health.failed":           "caído",
+	"report.health.unknown":          "desconocido",
+	"report.auth":                    "Autenticación",
+	"report.changed":                 "Credenciales cambiadas",
+	"report.age":                     "Antigüedad",
+	"report.credentials":             "Credenciales",
+	"report.system":                  "Sistema",
+}
@@ -0,0 +1,565 @@
+package i18n
+
+// messagesJA contains Japanese translations. It leaves out the cli.* output of
+// commands, which is shown in English (see TestCatalogKeys).
+var messagesJA = map[string]string{
+	// App general
+	"app.name":    "GoSSH",
+	"app.version": "バージョン",
+	"app.welcome": "GoSSH へようこそ",
+
+	// Menu and navigation
+	"menu.connections": "接続",
+	"menu.settings":    "設定",
+	"menu.help":        "ヘルプ",
+	"menu.quit":        "終了",
+
+	// Connection list
+	"list.title":              "SSH 接続",
+	"list.empty":              "接続がありません。'a' を押して追加してください。",
+	"list.empty.search":       "一致する接続がありません。",
+	"list.search":             "検索",
+	"list.filter":             "フィルター: %s (/ で検索、esc でクリア)",
+	"list.filter.all":         "すべて",
+	"list.filter.group":       "グループ",
+	"list.total":              "合計: %d 件の接続",
+	"list.showing":            " (%d 件を表示)",
+	"list.ungrouped":          "未分類",
+	"list.status.unknown":     "?",
+	"list.status.ok":          "✓",
+	"list.status.fail":        "✗",
+	"list.status.checking":    "...",
+	"list.trashed":            "ゴミ箱に移動しました。設定から復元できます",
+	"list.copied":             "コピーしました: %s",
+	"list.copy_failed":        "コピーに失敗しました",
+	"list.stale.rotate":       "要更新",
+	"list.stale.expired":      "期限切れ",
+	"list.uptime":             "稼働 %s",
+	"list.via":                "(%s 経由)",
+	"list.column.name":        "名前",
+	"list.column.host":        "ユーザー@ホスト",
+	"list.column.group":       "グループ",
+	"list.column.tags":        "タグ",
+	"list.column.last_seen":   "最終接続",
+	"list.column.status":      "状態",
+	"list.column.system":      "システム",
+	"list.help":               "a:追加  e:編集  d:削除  /:検索  s:設定  t:テスト  y:コピー  enter:接続  ?:ヘルプ  q:終了",
+	"list.help.search":        "入力して検索  enter:確定  esc:キャンセル",
+	"list.search.placeholder": "検索...",
+
+	// Connection form
+	"form.title.add":               "接続を追加",
+	"form.title.edit":              "接続を編集",
+	"form.name":                    "名前",
+	"form.name.hint":               "この接続の表示名",
+	"form.host":                    "ホスト",
+	"form.host.hint":               "ホスト名または IP アドレス",
+	"form.type":                    "プロトコル",
+	"form.port":                    "ポート",
+	"form.port.hint":               "SSH ポート (既定: 22)",
+	"form.user":                    "ユーザー名",
+	"form.user.hint":               "SSH ユーザー名",
+	"form.auth_type":               "認証方式",
+	"form.auth.password":           "パスワード",
+	"form.auth.key":                "秘密鍵",
+	"form.password":                "パスワード",
+	"form.password.hint":           "SSH パスワード",
+	"form.key_path":                "鍵のパス",
+	"form.key_path.hint":           "秘密鍵ファイルのパス",
+	"form.key_passphrase":          "鍵のパスフレーズ",
+	"form.key_pass.hint":           "秘密鍵のパスフレーズ (ある場合)",
+	"form.group":                   "グループ",
+	"form.group.hint":              "接続グループ",
+	"form.tags":                    "タグ",
+	"form.tags.hint":               "カンマ区切りのタグ",
+	"form.startup_cmd":             "起動コマンド",
+	"form.notes":                   "メモ",
+	"form.rotate_after":            "更新間隔",
+	"form.expires_at":              "有効期限",
+	"form.startup_cmd.hint":        "接続後に実行するコマンド",
+	"form.save":                    "保存",
+	"form.cancel":                  "キャンセル",
+	"form.error.required":          "この項目は必須です",
+	"form.error.port":              "無効なポート番号です",
+	"form.placeholder.name":        "マイサーバー",
+	"form.placeholder.host":        "192.168.1.1 または example.com",
+	"form.placeholder.optional":    "(任意)",
+	"form.placeholder.notes":       "このホストについて覚えておきたいこと",
+	"form.auth.opt.password":       "パスワード",
+	"form.auth.opt.key":            "鍵",
+	"form.agent_key":               "エージェント鍵",
+	"form.agent_key.any":           "任意の鍵",
+	"form.agent_key.missing":       "%s (エージェントにありません)",
+	"form.note.toggle":             "(スペースで切替)",
+	"form.note.select":             "(スペースで選択)",
+	"form.note.optional":           "(任意)",
+	"form.note.revealed":           "(15 秒間表示、ctrl+r で隠す)",
+	"form.note.agent_key":          "(すべてではなく提示する ssh-agent の鍵)",
+	"form.note.tags":               "(スペースかカンマで追加、→ で補完)",
+	"form.aliases":                 "別名",
+	"form.note.aliases":            "(接続と検索に使う別の名前)",
+	"form.addresses":               "その他のアドレス",
+	"form.note.addresses":          "(ホストに接続できないとき順に試行)",
+	"form.note.key_path":           "(ctrl+o で参照)",
+	"form.note.host":               "(または srv:_ssh._tcp.名前 / consul:サービス)",
+	"form.jump_hosts":              "踏み台ホスト",
+	"form.note.jump_hosts":         "(保存済みの接続、順番どおり)",
+	"form.bind_address":            "バインドアドレス",
+	"form.term":                    "端末タイプ",
+	"form.locale":                  "ロケール",
+	"form.local_dir":               "ローカルディレクトリ",
+	"form.remote_dir":              "リモートディレクトリ",
+	"form.note.bind_address":       "(ローカル IP またはインターフェース、任意)",
+	"form.note.term":               "(要求する TERM、既定: ローカル)",
+	"form.note.locale":             "(要求する LANG/LC_ALL、任意)",
+	"form.note.local_dir":          "(SFTP 転送の開始場所、任意)",
+	"form.note.remote_dir":         "(SFTP を開く場所、既定: ホーム)",
+	"form.note.startup":            "(1 行に 1 コマンド、接続後に実行)",
+	"form.pre_connect":             "接続前",
+	"form.post_disconnect":         "切断後",
+	"form.note.pre_connect":        "(ローカルコマンド、成功するとセッションを開始)",
+	"form.note.post_disconnect":    "(ローカルコマンド、セッション後に実行)",
+	"form.quiet_login":             "静かなログイン",
+	"form.show_banner":             "バナーを表示",
+	"form.gssapi":                  "GSSAPI",
+	"form.note.quiet_login":        "(MOTD と最終ログインを隠す)",
+	"form.note.show_banner":        "(まずサーバーの通知を表示)",
+	"form.note.gssapi":             "(チケットがあれば先に Kerberos を試す)",
+	"form.note.gssapi_unsupported": "(-tags gssapi 付きのビルドが必要)",
+	"form.opt.on":                  "オン",
+	"form.opt.off":                 "オフ",
+	"form.note.rotate_after":       "(日数、任意)",
+	"form.help":                    "tab:次の項目  enter:保存  ctrl+t:テスト  f1:ヘルプ  esc:キャンセル",
+	"form.test.running":            "接続をテストしています...",
+	"form.test.ok":                 "✓ 接続成功 (%s)",
+	"form.test.host_key":           "ホスト鍵が変更されています",
+	"form.keys.title":              "鍵ファイルを選択",
+	"form.keys.none":               "%s に秘密鍵が見つかりません",
+	"form.keys.encrypted":          "[暗号化]",
+	"form.keys.help":               "↑/↓:移動  enter:選択  esc:キャンセル",
+
+	// Setup
+	"setup.title":                  "GoSSH へようこそ",
+	"setup.desc":                   "セキュリティモードを選択してください:",
+	"setup.option.password":        "[1] パスワード保護を有効にする (推奨)",
+	"setup.option.password.desc":   "マスターパスワードを設定し、起動のたびに入力します",
+	"setup.option.nopassword":      "[2] パスワード保護をスキップ",
+	"setup.option.nopassword.desc": "すぐに開始でき、パスワードは不要です",
+	"setup.password.title":         "マスターパスワードの設定",
+	"setup.password.desc":          "このパスワードで保存された認証情報を暗号化します。忘れないでください!",
+	"setup.password.prompt":        "マスターパスワードを入力",
+	"setup.password.confirm":       "パスワードの確認",
+	"setup.password.hint":          "8 文字以上",
+	"setup.password.mismatch":      "パスワードが一致しません",
+	"setup.password.weak":          "パスワードが弱すぎます",
+	"setup.password.strength":      "パスワード強度",
+	"setup.complete":               "セットアップ完了!",
+	"setup.help.choose":            "↑/↓:選択  1/2:クイック選択  enter:確定  esc:終了",
+	"setup.help.password":          "tab:次の項目  enter:確定  esc:戻る",
+	"setup.placeholder.password":   "マスターパスワードを入力",
+	"setup.placeholder.confirm":    "マスターパスワードを再入力",
+
+	// Unlock
+	"unlock.title":       "GoSSH はロックされています",
+	"unlock.prompt":      "ロックを解除するにはマスターパスワードを入力してください:",
+	"unlock.label":       "パスワード:",
+	"unlock.error":       "パスワードが正しくありません",
+	"unlock.attempt":     "[失敗した試行: %d]",
+	"unlock.attempts":    "残り試行回数",
+	"unlock.failed":      "失敗が多すぎます。%s 後に再試行してください。",
+	"unlock.help":        "enter:解除  esc:終了",
+	"unlock.placeholder": "マスターパスワードを入力",
+	"lock.unprotected":   "ロックするものがありません。先に設定でマスターパスワードを設定してください",
+
+	// Confirm dialog
+	"confirm.title":      "確認",
+	"confirm.delete":     "接続を削除",
+	"confirm.delete.msg": "この接続をゴミ箱に移動しますか?",
+	"confirm.yes":        "はい",
+	"confirm.no":         "いいえ",
+	"confirm.help":       "y:はい  n:いいえ  tab:切替  enter:確定  esc:キャンセル",
+	"confirm.default":    "よろしいですか?",
+
+	// Help
+	"help.title":              "GoSSH ヘルプ",
+	"help.navigation":         "ナビゲーション",
+	"help.connection":         "接続管理",
+	"help.form":               "フォーム操作",
+	"help.general":            "一般",
+	"help.settings":           "設定",
+	"help.actions":            "操作",
+	"help.key.up":             "上へ移動",
+	"help.key.down":           "下へ移動",
+	"help.key.top":            "先頭へ移動",
+	"help.key.bottom":         "末尾へ移動",
+	"help.key.left":           "左にスクロール",
+	"help.key.right":          "右にスクロール",
+	"help.key.color_prev":     "前の色",
+	"help.key.color_next":     "次の色",
+	"help.key.layout":         "行表示と表形式を切り替え",
+	"help.key.sort":           "次の列で表を並べ替え",
+	"help.key.sort_reverse":   "並び順を反転",
+	"help.key.filters":        "保存したフィルター",
+	"help.key.toggle_column":  "列の表示/非表示",
+	"help.key.rule_add":       "グループ化ルールを追加",
+	"help.key.rule_delete":    "選択したルールを削除",
+	"help.key.default_group":  "既定のグループを設定",
+	"help.key.rule_preview":   "既存の接続でルールをプレビュー",
+	"help.key.rule_apply":     "既存の接続にルールを適用",
+	"help.key.merge_local":    "ローカルの値を残す",
+	"help.key.merge_incoming": "インポートした値を使う",
+	"help.key.merge_apply":    "選んだ値でインポート",
+	"help.key.dedupe_pick":    "残す接続を選ぶ",
+	"help.key.dedupe_merge":   "ほかの接続をそこに統合",
+	"help.key.search":         "接続を検索",
+	"help.key.connect":        "選択したサーバーに接続",
+	"help.key.enter":          "接続 / 選択",
+	"help.key.add":            "新しい接続を追加",
+	"help.key.edit":           "選択した接続を編集",
+	"help.key.delete":         "選択した接続を削除",
+	"help.key.tab":            "次の項目",
+	"help.key.shifttab":       "前の項目",
+	"help.key.space":          "オプションの切り替え / ドロップダウンを開く",
+	"help.key.save":           "保存",
+	"help.key.cancel":         "キャンセル",
+	"help.key.help":           "このヘルプを表示",
+	"help.key.quit":           "アプリケーションを終了",
+	"help.key.back":           "戻る / キャンセル",
+	"help.key.settings":       "設定",
+	"help.key.test":           "接続をテスト",
+	"help.key.test_all":       "すべての接続をバックグラウンドでテスト",
+	"help.key.broadcast":      "一覧のホストに同時に入力",
+	"help.key.exec":           "一覧のホストでコマンドを実行",
+	"help.key.history":        "接続の変更履歴",
+	"help.key.scrollback":     "前回のセッションの出力を検索",
+	"help.key.plugins":        "接続でプラグインを実行",
+	"help.key.details":        "詳細と添付ファイル",
+	"help.key.local":          "ローカルのコマンドまたはシェルを実行",
+	"help.key.lock":           "ロック：マスターパスワードを入力するまで鍵を破棄",
+	"help.key.copy":           "ssh コマンドをコピー",
+	"help.key.test_form":      "入力した接続をテスト",
+	"help.key.browse":         "秘密鍵ファイルを選択",
+	"help.key.reveal":         "パスワードと鍵のパスフレーズを表示/非表示",
+	"help.key.open":           "選択した項目を開く",
+	"help.key.restore":        "選択した接続を復元",
+	"help.key.purge":          "完全に削除（2回押す）",
+	"help.return":             "任意のキーで戻る",
+	"help.scroll":             "↑/↓ スクロール",
+
+	// Settings
+	"settings.title":                      "設定",
+	"settings.language":                   "言語",
+	"settings.security":                   "セキュリティ",
+	"settings.password.enable":            "マスターパスワードを有効化",
+	"settings.password.change":            "マスターパスワードを変更",
+	"settings.password.disable":           "マスターパスワードを無効化",
+	"settings.about":                      "情報",
+	"settings.save":                       "保存",
+	"settings.cancel":                     "キャンセル",
+	"settings.saved":                      "設定を保存しました",
+	"settings.help":                       "↑/↓: 移動 • enter: 選択 • ?: ヘルプ • esc: 戻る",
+	"settings.help.language":              "↑/↓: 言語を選択 • enter: 確定 • esc: 戻る",
+	"settings.help.password":              "tab/↑/↓: 項目切替 • enter: 確定 • esc: 戻る",
+	"settings.help.password.disable":      "enter: 確定 • esc: 戻る",
+	"settings.import":                     "接続をインポート",
+	"settings.export":                     "接続をエクスポート",
+	"settings.trash":                      "ゴミ箱 (%d)",
+	"settings.notify":                     "通知: %s",
+	"settings.notify.off":                 "オフ",
+	"settings.notify.bell":                "端末ベル",
+	"settings.notify.desktop":             "デスクトップ",
+	"settings.keep_warm":                  "接続を維持: %s",
+	"settings.keep_warm.off":              "オフ",
+	"settings.keep_warm.minutes":          "%d 分",
+	"settings.scrollback":                 "セッション出力を保持: %s",
+	"settings.key_cache":                  "CLI 用にマスター鍵を記憶: %s",
+	"settings.theme":                      "テーマ: %s",
+	"settings.theme.dark":                 "ダーク",
+	"settings.theme.light":                "ライト",
+	"settings.connection":                 "接続と端末",
+	"settings.connection.title":           "接続と端末",
+	"settings.on":                         "オン",
+	"settings.off":                        "オフ",
+	"settings.seconds":                    "%d 秒",
+	"settings.timeout":                    "接続タイムアウト: %s",
+	"settings.timeout.prompt":             "ホストへの接続にかけられる秒数:",
+	"settings.default_port":               "既定のポート: %d",
+	"settings.port.prompt":                "新しい SSH 接続に入れるポート:",
+	"settings.default_user":               "既定のユーザー: %s",
+	"settings.default_user.none":          "なし",
+	"settings.user.prompt":                "新しい接続に入れるユーザー (空でなし):",
+	"settings.keepalive":                  "キープアライブ間隔: %s",
+	"settings.keepalive.default":          "既定 (10 秒)",
+	"settings.keepalive.prompt":           "セッション中のキープアライブの間隔 (秒、0 で既定):",
+	"settings.health":                     "バックグラウンドの死活監視: %s",
+	"settings.health.off":                 "オフ",
+	"settings.health.minutes":             "%d 分ごと",
+	"settings.confirm_delete":             "削除前に確認: %s",
+	"settings.term":                       "端末タイプ (TERM): %s",
+	"settings.term.local":                 "ローカル",
+	"settings.term.prompt":                "TERM を指定していない接続の TERM (空でローカルのもの):",
+	"settings.locale":                     "ロケール: %s",
+	"settings.locale.none":                "変更しない",
+	"settings.locale.prompt":              "ロケールを指定していない接続のロケール、例 C.UTF-8 (空で送らない):",
+	"settings.rules":                      "グループ分けルール (%d)",
+	"settings.rules.title":                "グループ分けルール",
+	"settings.rules.empty":                "ルールはありません。n を押すと \\.prod\\.example\\.com$ のようなホストを Production に振り分けられます。",
+	"settings.rules.default":              "既定のグループ: %s",
+	"settings.rules.default.none":         "なし",
+	"settings.rules.default.prompt":       "どのルールにも当てはまらない新しい接続のグループ (空でなし):",
+	"settings.rules.pattern":              "パターン (名前またはホストに対する正規表現):",
+	"settings.rules.pattern.placeholder":  "例 \\.prod\\.example\\.com$",
+	"settings.rules.group":                "グループ:",
+	"settings.rules.tags":                 "タグ (カンマ区切り):",
+	"settings.rules.preview.title":        "グループ分けルール: 試行",
+	"settings.rules.preview.empty":        "ルールによって変わる既存の接続はありません。",
+	"settings.rules.preview.count":        "%d 件の既存の接続が変わります:",
+	"settings.rules.preview.more":         "… ほか %d 件",
+	"settings.rules.applied":              "%d 件の接続を振り分けました",
+	"settings.help.rules":                 "↑/↓: 選択 • n: 追加 • d: 削除 • g: 既定のグループ • p: 試行 • esc: 戻る",
+	"settings.help.rules.add":             "tab: 次の項目 • enter: 保存 • esc: キャンセル",
+	"settings.help.rules.default":         "enter: 保存 • esc: キャンセル",
+	"settings.help.rules.preview":         "enter: 既存の接続に適用 • esc: 戻る",
+	"settings.help.value":                 "enter: 保存 • esc: キャンセル",
+	"filters.title":                       "保存済みフィルター",
+	"filters.empty":                       "保存済みフィルターはありません。まず / で検索し、ここで n を押すと保存できます。",
+	"filters.name":                        "名前:",
+	"filters.name.placeholder":            "例 prod web",
+	"filters.no_search":                   "保存するには、まず / で検索してください",
+	"filters.help":                        "enter:適用  n:現在の検索を保存  d:削除  esc:閉じる",
+	"filters.help.naming":                 "enter:保存  esc:キャンセル",
+	"plugins.title":                       "%s のプラグイン",
+	"plugins.empty":                       "プラグインはありません。%s<コマンド> という名前の実行ファイルを %s に置いてください",
+	"plugins.help":                        "enter:実行  esc:閉じる",
+	"details.title":                       "詳細: %s",
+	"details.address":                     "アドレス",
+	"details.group":                       "グループ",
+	"details.tags":                        "タグ",
+	"details.password":                    "パスワード",
+	"details.key_passphrase":              "鍵のパスフレーズ",
+	"details.agent_key":                   "エージェント鍵",
+	"details.notes":                       "メモ",
+	"details.attachments":                 "添付ファイル",
+	"details.attachments.empty":           "添付ファイルはまだありません",
+	"details.add.prompt":                  "添付するファイル:",
+	"details.export.prompt":               "エクスポート先:",
+	"details.added":                       "%s を添付しました",
+	"details.deleted":                     "%s を削除しました",
+	"details.exported":                    "%s を %s にエクスポートしました",
+	"details.exists":                      "ファイルが存在するため上書きしませんでした",
+	"details.not_file":                    "通常のファイルではありません",
+	"details.binary":                      "%s はテキストではありません。開くにはエクスポートしてください",
+	"details.delete.confirm":              "%s を削除しますか? (y/n)",
+	"details.revealed":                    "15 秒間表示: ctrl+r で隠す",
+	"details.help":                        "enter:表示  a:添付  x:エクスポート  d:削除  ctrl+r:パスワードを表示  esc:閉じる",
+	"history.title":                       "履歴: %s",
+	"history.empty":                       "記録された変更はまだありません",
+	"history.restored":                    "%s の変更前のバージョンを復元しました",
+	"history.help":                        "↑/↓: 選択 • r: このバージョンを復元 • esc: 戻る",
+	"scrollback.title":                    "出力: %s",
+	"scrollback.position":                 "%d-%d 行目 / 全 %d 行",
+	"scrollback.dropped":                  "(古い出力は破棄されました)",
+	"scrollback.empty":                    "セッションは何も出力しませんでした",
+	"scrollback.help":                     "↑/↓/pgup/pgdn: スクロール • g/G: 先頭/末尾 • /: 検索 • n/N: 前/次の一致 • esc: 戻る",
+	"scrollback.search.placeholder":       "検索するテキスト",
+	"scrollback.no_match":                 "\"%s\" を含む行はありません",
+	"scrollback.off":                      "セッション出力は保持されていません。設定で有効にしてください",
+	"scrollback.none":                     "%s とのセッションの出力はまだ保持されていません",
+	"settings.trash.title":                "ゴミ箱",
+	"settings.trash.empty":                "ゴミ箱は空です",
+	"settings.trash.info":                 "%s に削除 · %d 日後に完全削除",
+	"settings.trash.restored":             "'%s' を復元しました",
+	"settings.trash.purged":               "'%s' を完全に削除しました",
+	"settings.trash.confirm_purge":        "もう一度 p を押すと '%s' を完全に削除します",
+	"settings.groups":                     "グループの色",
+	"settings.groups.title":               "グループの色",
+	"settings.groups.empty":               "グループはありません",
+	"settings.layout":                     "リストの表示形式: %s",
+	"settings.layout.lines":               "行",
+	"settings.layout.table":               "表",
+	"settings.columns":                    "表の列",
+	"settings.columns.note":               "列はリストの表示形式が表のときに表示されます (リストで v を押す)",
+	"settings.transfer.path":              "ファイルパス",
+	"settings.transfer.format":            "形式",
+	"settings.transfer.format.yaml":       "GoSSH (YAML)",
+	"settings.transfer.format.ssh_config": "OpenSSH の設定",
+	"settings.transfer.note.import":       "名前・別名・アドレスで既に存在する接続は、項目ごとに統合されます",
+	"settings.import.result":              "インポート完了",
+	"settings.export.result":              "エクスポート完了",
+	"settings.transfer.file":              "ファイル",
+	"settings.transfer.imported":          "インポート",
+	"settings.transfer.skipped":           "スキップ",
+	"settings.transfer.merged":            "統合",
+	"settings.merge.title":                "インポートの競合: %d",
+	"settings.merge.summary":              "%d 件の新しい接続が追加され、%d 件は変更ありません。項目ごとに残す値を選んでください。",
+	"settings.help.merge":                 "↑/↓: 選択 • ←: ローカル • →: インポート • スペース: 切替 • enter: インポート • esc: キャンセル",
+	"settings.duplicates":                 "重複 (%d)",
+	"settings.duplicates.title":           "重複した接続",
+	"settings.duplicates.empty":           "同じユーザー・ホスト・ポートにログインする接続はありません。",
+	"settings.duplicates.keep":            "残す",
+	"settings.duplicates.done":            "%d 件の接続を %s に統合しました。残りはゴミ箱にあります",
+	"settings.help.duplicates":            "↑/↓: 重複の組 • ←/→: 残す接続 • enter: 統合 • esc: 戻る",
+	"settings.transfer.exported":          "エクスポート",
+	"settings.help.transfer":              "tab: 項目を切替 • ←/→: 形式 • enter: 実行 • esc: 戻る",
+	"settings.help.result":                "enter/esc: 戻る",
+	"settings.help.trash":                 "↑/↓: 選択 • r/enter: 復元 • p: 完全削除 • esc: 戻る",
+	"settings.help.groups":                "↑/↓: 選択 • ←/→: 色を変更 • esc: 戻る",
+	"settings.help.columns":               "↑/↓: 選択 • スペース: 表示/非表示 • esc: 戻る",
+
+	// Host key verification
+	"hostkey.title":       "ホスト鍵の検証",
+	"hostkey.unknown":     "不明なホスト",
+	"hostkey.unknown.msg": "ホスト '%s' の真正性を確認できません。",
+	"hostkey.fingerprint": "フィンガープリント",
+	"hostkey.keytype":     "鍵の種類",
+	"hostkey.trust":       "このホストを信頼して接続を続けますか?",
+	"hostkey.changed":     "警告: ホスト鍵が変更されました!",
+	"hostkey.changed.msg": "'%s' のホスト鍵が変更されています。中間者攻撃の可能性があります!",
+	"hostkey.accept":      "承認",
+	"hostkey.reject":      "拒否",
+	"hostkey.update":      "更新",
+	"hostkey.help":        "y:承認  n:拒否  enter:確定",
+	"banner.title":        "%s のバナー",
+	"banner.help":         "enter:続行  esc:キャンセル",
+	"hostkey.previous":    "以前のフィンガープリント:",
+
+	// Health check
+	"health.title":              "接続テスト",
+	"health.testing":            "接続をテストしています...",
+	"health.all.progress":       "接続を確認中... %d/%d",
+	"health.all.done":           "%d 件の接続を確認：正常 %d 件、失敗 %d 件",
+	"broadcast.title.all":       "%d 台のホストにブロードキャスト",
+	"broadcast.title.single":    "%s のみに入力中",
+	"broadcast.help":            "ctrl+n/ctrl+p:ホスト切替  ctrl+t:全体/単一  ctrl+q:閉じる",
+	"broadcast.confirm":         "ブロードキャスト",
+	"broadcast.confirm.msg":     "%d 台のホストでシェルを開き、同時に入力しますか?",
+	"broadcast.closed":          "ブロードキャストを終了しました",
+	"broadcast.dropped":         "%d 件の入力を破棄しました：このホストの応答が追いついていません",
+	"exec.title":                "%d 台のホストで実行",
+	"exec.command":              "コマンド:",
+	"exec.command.placeholder":  "uptime",
+	"exec.progress":             "%d/%d 完了 · %d 実行中 · %d 失敗",
+	"exec.eta":                  "残り約 %s",
+	"exec.exit":                 "終了コード %d",
+	"exec.finished":             "%d 成功、%d 失敗",
+	"exec.done":                 "コマンド終了: %d 成功、%d 失敗",
+	"exec.stopping":             "ホストのコマンドを停止しています...",
+	"exec.help.input":           "enter: 実行 • esc: キャンセル",
+	"exec.help.running":         "↑/↓: ホストを選択 • esc: 停止",
+	"exec.help.done":            "↑/↓: ホストを選択 • esc: 戻る",
+	"local.title":               "ローカルコマンド",
+	"local.command":             "コマンド:",
+	"local.command.placeholder": "空でシェル、exit で戻る",
+	"local.help":                "enter: 実行 • esc: キャンセル",
+	"local.return":              "Enter を押すと gossh に戻ります",
+	"local.exit":                "ローカルコマンドが状態 %d で終了しました",
+	"health.checking":           "確認中...",
+	"health.reachable":          "到達可能",
+	"health.unreachable":        "到達不能",
+	"health.auth_failed":        "認証に失敗しました",
+	"health.result.success":     "✓ 接続に成功しました",
+	"health.result.fail":        "✗ 接続に失敗しました",
+
+	// SFTP
+	"sftp.connected":   "%s に SFTP 接続しました",
+	"sftp.pwd":         "現在のディレクトリ: %s",
+	"sftp.uploading":   "アップロード中: %s",
+	"sftp.downloading": "ダウンロード中: %s",
+	"sftp.progress":    "%d%% (%s / %s)",
+	"sftp.complete":    "転送完了",
+
+	// Import
+	"import.title":          "SSH 設定のインポート",
+	"import.reading":        "%s を読み込んでいます...",
+	"import.found":          "%d 件の接続が見つかりました",
+	"import.importing":      "インポート中...",
+	"import.skip.duplicate": "重複をスキップ: %s",
+	"import.complete":       "インポート完了: %d 件インポート、%d 件スキップ",
+	"wizard.title":          "SSH ホストをインポート",
+	"wizard.desc":           "%d 件の新しいホストが %s に見つかりました。インポートするホストを選んでください:",
+	"wizard.selected":       "%d / %d 件を選択",
+	"wizard.help":           "↑/↓:移動  スペース:切替  a:すべて  g:グループを切替  G:すべてのグループ  enter:インポート  esc:スキップ",
+
+	// Errors
+	"error.connection":                  "接続に失敗しました",
+	"error.auth":                        "認証に失敗しました",
+	"error.timeout":                     "接続がタイムアウトしました",
+	"error.unknown":                     "不明なエラー",
+	"error.validation.name":             "名前は必須です",
+	"error.validation.host":             "ホストは必須です",
+	"error.validation.host_invalid":     "ホストはホスト名か IP アドレスである必要があります",
+	"error.validation.aliases":          "別名は名前と異なる 1 語である必要があります",
+	"error.validation.addresses":        "その他のアドレスはホスト名か IP アドレスである必要があります",
+	"error.validation.user":             "ユーザーは必須です",
+	"error.validation.port":             "ポートは 1 から 65535 の間である必要があります",
+	"error.validation.key_path":         "鍵認証には鍵のパスが必要です",
+	"error.validation.key_file":         "鍵ファイルを読めないか、秘密鍵ではありません",
+	"error.validation.key_passphrase":   "鍵は暗号化されています。パスフレーズを入力してください",
+	"error.validation.agent_key":        "エージェント鍵は ssh-add -l が表示する SHA256 フィンガープリントである必要があります",
+	"error.validation.term":             "端末タイプは 1 語である必要があります (例 vt100)",
+	"error.validation.locale":           "ロケールは 1 語である必要があります (例 C.UTF-8)",
+	"error.validation.rotate_after":     "更新間隔は正の日数である必要があります",
+	"error.validation.expires_at":       "有効期限は YYYY-MM-DD 形式である必要があります",
+	"error.validation.pattern":          "パターンは有効な正規表現である必要があります",
+	"error.validation.rule":             "ルールにはグループかタグが必要です",
+	"error.validation.workspace":        "ワークスペースには接続かトンネルが必要です",
+	"error.validation.tunnel":           "トンネルには接続、-L か -R、転送の指定が必要です",
+	"error.validation.forward_profiles": "転送プロファイルには空白のない名前と -L、-R、-D の転送指定が必要です",
+	"error.validation.snippet":          "スニペットには名前とコマンドが必要です",
+	"error.validation.cron":             "スケジュールは cron 形式である必要があります (例 0 3 * * *)",
+	"error.validation.command":          "コマンドは必須です",
+	"error.validation.timeout":          "タイムアウトは正の秒数である必要があります",
+	"error.validation.interval":         "間隔は正の数か、既定の場合は 0 である必要があります",
+	"error.validation.theme":            "テーマは dark か light である必要があります",
+	"error.validation.default_user":     "既定のユーザーは 1 語である必要があります",
+	"error.password.invalid":            "パスワードが無効です",
+	"error.password.weak":               "パスワードが弱すぎます: 8 文字以上必要です",
+	"error.attachment.too_large":        "添付ファイルは %d KB までです",
+	"error.attachment.empty":            "ファイルが空です",
+	"error.attachment.exists":           "この接続には同じ名前の添付ファイルが既にあります",
+	"error.locked":                      "先に gossh のロックを解除してください",
+	"error.insecure_dir":                "%s は他のユーザーが読めます。パスワードは非公開の場所にエクスポートしてください",
+	"error.password.policy.length":      "パスワードが弱すぎます: %d 文字以上必要です",
+	"error.password.policy.classes":     "パスワードが弱すぎます: 小文字・大文字・数字・記号のうち %d 種類以上を使ってください",
+	"error.password.policy.common":      "パスワードが弱すぎます: よく使われるパスワードか、禁止語を含んでいます",
+	"error.password.policy.score":       "パスワードが推測されやすすぎます: 長くするか、関連のない単語をいくつか使ってください",
+
+	// Common
+	"common.loading":                 "読み込み中...",
+	"common.saving":                  "保存中...",
+	"common.success":                 "成功",
+	"common.error":                   "エラー",
+	"common.back":                    "戻る",
+	"common.next":                    "次へ",
+	"common.done":                    "完了",
+	"common.connecting":              "%s に接続しています...",
+	"session.summary":                "%s: 終了コード %d（%s）",
+	"session.pre_connect.failed":     "pre_connect コマンドが失敗しました: %v",
+	"session.post_disconnect.failed": "post_disconnect コマンドが失敗しました: %v",
+	"session.suspended":              "%s を一時停止しました — Enter で再開",
+	"status.protected":               "マスターパスワードあり",
+	"status.unprotected":             "マスターパスワードなし",
+	"status.sessions":                "中断中のセッション: %d",
+	"status.jobs":                    "バックグラウンドジョブ: %d",
+	"session.closed":                 "%s との接続を閉じました",
+	"common.conn_error":              "接続エラー: %s",
+	"common.state_error":             "接続状態を保存できませんでした: %v",
+	"report.title":                   "接続一覧",
+	"report.summary":                 "%s に作成: ホスト %d 台、うち %d 台の認証情報に対応が必要です。",
+	"report.hosts":                   "ホスト",
+	"report.groups":                  "グループ",
+	"report.tags":                    "タグ",
+	"report.name":                    "名前",
+	"report.address":                 "アドレス",
+	"report.group":                   "グループ",
+	"report.last_seen":               "最終接続",
+	"report.health":                  "死活",
+	"report.health.success":          "稼働",
+	"report.health.failed":           "停止",
+	"report.health.unknown":          "不明",
+	"report.auth":                    "認証",
+	"report.changed":                 "認証情報の変更",
+	"report.age":                     "経過",
+	"report.credentials":             "認証情報",
+	"report.system":                  "システム",
+}
@@ -0,0 +1,565 @@
+package i18n
+
+// messagesRU contains Russian translations. It leaves out the cli.* output of
+// commands, which is shown in English (see TestCatalogKeys).
+var messagesRU = map[string]string{
+	// App general
+	"app.name":    "GoSSH",
+	"app.version": "Версия",
+	"app.welcome": "Добро пожаловать в GoSSH",
+
+	// Menu and navigation
+	"menu.connections": "Подключения",
+	"menu.settings":    "Настройки",
+	"menu.help":        "Справка",
+	"menu.quit":        "Выход",
+
+	// Connection list
+	"list.title":              "SSH-подключения",
+	"list.empty":              "Подключений пока нет. Нажмите 'a', чтобы добавить.",
+	"list.empty.search":       "Нет подходящих подключений.",
+	"list.search":             "Поиск",
+	"list.filter":             "Фильтр: %s (/ — поиск, esc — сброс)",
+	"list.filter.all":         "Все",
+	"list.filter.group":       "Группа",
+	"list.total":              "Всего: %d подключений",
+	"list.showing":            " (показано %d)",
+	"list.ungrouped":          "Без группы",
+	"list.status.unknown":     "?",
+	"list.status.ok":          "✓",
+	"list.status.fail":        "✗",
+	"list.status.checking":    "...",
+	"list.trashed":            "Перемещено в корзину — восстановить можно в настройках",
+	"list.copied":             "Скопировано: %s",
+	"list.copy_failed":        "Не удалось скопировать",
+	"list.stale.rotate":       "сменить",
+	"list.stale.expired":      "истёк",
+	"list.uptime":             "работает %s",
+	"list.via":                "(через %s)",
+	"list.column.name":        "Имя",
+	"list.column.host":        "Пользователь@Хост",
+	"list.column.group":       "Группа",
+	"list.column.tags":        "Теги",
+	"list.column.last_seen":   "Последнее подключение",
+	"list.column.status":      "Статус",
+	"list.column.system":      "Система",
+	"list.help":               "a:добавить  e:изменить  d:удалить  /:поиск  s:настройки  t:проверить  y:копировать  enter:подключиться  ?:справка  q:выход",
+	"list.help.search":        "введите для поиска  enter:подтвердить  esc:отмена",
+	"list.search.placeholder": "Поиск...",
+
+	// Connection form
+	"form.title.add":               "Добавить подключение",
+	"form.title.edit":              "Изменить подключение",
+	"form.name":                    "Имя",
+	"form.name.hint":               "Понятное имя для этого подключения",
+	"form.host":                    "Хост",
+	"form.host.hint":               "Имя хоста или IP-адрес",
+	"form.type":                    "Протокол",
+	"form.port":                    "Порт",
+	"form.port.hint":               "SSH-порт (по умолчанию: 22)",
+	"form.user":                    "Пользователь",
+	"form.user.hint":               "Имя пользователя SSH",
+	"form.auth_type":               "Аутентификация",
+	"form.auth.password":           "Пароль",
+	"form.auth.key":                "Закрытый ключ",
+	"form.password":                "Пароль",
+	"form.password.hint":           "Пароль SSH",
+	"form.key_path":                "Путь к ключу",
+	"form.key_path.hint":           "Путь к файлу закрытого ключа",
+	"form.key_passphrase":          "Парольная фраза ключа",
+	"form.key_pass.hint":           "Парольная фраза закрытого ключа (если есть)",
+	"form.group":                   "Группа",
+	"form.group.hint":              "Группа подключения",
+	"form.tags":                    "Теги",
+	"form.tags.hint":               "Теги через запятую",
+	"form.startup_cmd":             "Команда запуска",
+	"form.notes":                   "Заметки",
+	"form.rotate_after":            "Сменить через",
+	"form.expires_at":              "Истекает",
+	"form.startup_cmd.hint":        "Команда, выполняемая после подключения",
+	"form.save":                    "Сохранить",
+	"form.cancel":                  "Отмена",
+	"form.error.required":          "Это поле обязательно",
+	"form.error.port":              "Неверный номер порта",
+	"form.placeholder.name":        "Мой сервер",
+	"form.placeholder.host":        "192.168.1.1 или example.com",
+	"form.placeholder.optional":    "(необязательно)",
+	"form.placeholder.notes":       "Всё, что стоит помнить об этом хосте",
+	"form.auth.opt.password":       "пароль",
+	"form.auth.opt.key":            "ключ",
+	"form.agent_key":               "Ключ агента",
+	"form.agent_key.any":           "любой ключ",
+	"form.agent_key.missing":       "%s (нет в агенте)",
+	"form.note.toggle":             "(пробел — переключить)",
+	"form.note.select":             "(пробел — выбрать)",
+	"form.note.optional":           "(необязательно)",
+	"form.note.revealed":           "(показан 15 с, ctrl+r скрывает)",
+	"form.note.agent_key":          "(какой ключ ssh-agent предлагать вместо всех)",
+	"form.note.tags":               "(пробел или запятая добавляет, → дополняет)",
+	"form.aliases":                 "Псевдонимы",
+	"form.note.aliases":            "(другие имена для подключения и поиска)",
+	"form.addresses":               "Другие адреса",
+	"form.note.addresses":          "(пробуются по порядку, если хост недоступен)",
+	"form.note.key_path":           "(ctrl+o — обзор)",
+	"form.note.host":               "(или srv:_ssh._tcp.имя / consul:сервис)",
+	"form.jump_hosts":              "Промежуточные хосты",
+	"form.note.jump_hosts":         "(сохранённые подключения, по порядку)",
+	"form.bind_address":            "Локальный адрес",
+	"form.term":                    "Тип терминала",
+	"form.locale":                  "Локаль",
+	"form.local_dir":               "Локальный каталог",
+	"form.remote_dir":              "Удалённый каталог",
+	"form.note.bind_address":       "(локальный IP или интерфейс, необязательно)",
+	"form.note.term":               "(запрашиваемый TERM, по умолчанию локальный)",
+	"form.note.locale":             "(запрашиваемые LANG/LC_ALL, необязательно)",
+	"form.note.local_dir":          "(отсюда начинаются передачи SFTP, необязательно)",
+	"form.note.remote_dir":         "(здесь открывается SFTP, по умолчанию домашний)",
+	"form.note.startup":            "(по одной команде в строке, выполняются после подключения)",
+	"form.pre_connect":             "Перед подключением",
+	"form.post_disconnect":         "После отключения",
+	"form.note.pre_connect":        "(локальная команда, сеанс начнётся после её успеха)",
+	"form.note.post_disconnect":    "(локальная команда, выполняется после сеанса)",
+	"form.quiet_login":             "Тихий вход",
+	"form.show_banner":             "Показывать баннер",
+	"form.gssapi":                  "GSSAPI",
+	"form.note.quiet_login":        "(скрыть MOTD и последний вход)",
+	"form.note.show_banner":        "(сначала показать уведомление сервера)",
+	"form.note.gssapi":             "(сначала пробовать Kerberos, если есть билет)",
+	"form.note.gssapi_unsupported": "(нужна сборка с -tags gssapi)",
+	"form.opt.on":                  "вкл",
+	"form.opt.off":                 "выкл",
+	"form.note.rotate_after":       "(дней, необязательно)",
+	"form.help":                    "tab:след. поле  enter:сохранить  ctrl+t:проверить  f1:справка  esc:отмена",
+	"form.test.running":            "Проверка подключения...",
+	"form.test.ok":                 "✓ Подключение успешно (%s)",
+	"form.test.host_key":           "Ключ хоста изменился",
+	"form.keys.title":              "Выбор файла ключа",
+	"form.keys.none":               "В %s не найдено закрытых ключей",
+	"form.keys.encrypted":          "[зашифрован]",
+	"form.keys.help":               "↑/↓:перемещение  enter:выбрать  esc:отмена",
+
+	// Setup
+	"setup.title":                  "Добро пожаловать в GoSSH",
+	"setup.desc":                   "Выберите режим безопасности:",
+	"setup.option.password":        "[1] Включить защиту паролем (рекомендуется)",
+	"setup.option.password.desc":   "Задать мастер-пароль, запрашиваемый при каждом запуске",
+	"setup.option.nopassword":      "[2] Пропустить защиту паролем",
+	"setup.option.nopassword.desc": "Быстрый старт, пароль не требуется",
+	"setup.password.title":         "Задайте мастер-пароль",
+	"setup.password.desc":          "Этот пароль шифрует сохранённые учётные данные. Не забудьте его!",
+	"setup.password.prompt":        "Введите мастер-пароль",
+	"setup.password.confirm":       "Подтвердите пароль",
+	"setup.password.hint":          "Минимум 8 символов",
+	"setup.password.mismatch":      "Пароли не совпадают",
+	"setup.password.weak":          "Пароль слишком слабый",
+	"setup.password.strength":      "Надёжность пароля",
+	"setup.complete":               "Настройка завершена!",
+	"setup.help.choose":            "↑/↓:выбор  1/2:быстрый выбор  enter:подтвердить  esc:выход",
+	"setup.help.password":          "tab:следующее поле  enter:подтвердить  esc:назад",
+	"setup.placeholder.password":   "Введите мастер-пароль",
+	"setup.placeholder.confirm":    "Подтвердите мастер-пароль",
+
+	// Unlock
+	"unlock.title":       "GoSSH заблокирован",
+	"unlock.prompt":      "Введите мастер-пароль для разблокировки:",
+	"unlock.label":       "Пароль:",
+	"unlock.error":       "Неверный пароль",
+	"unlock.attempt":     "[Неудачных попыток: %d]",
+	"unlock.attempts":    "осталось попыток",
+	"unlock.failed":      "Слишком много неудачных попыток. Повторите через %s.",
+	"unlock.help":        "enter:разблокировать  esc:выход",
+	"unlock.placeholder": "Введите мастер-пароль",
+	"lock.unprotected":   "Нечего блокировать: сначала задайте мастер-пароль в настройках",
+
+	// Confirm dialog
+	"confirm.title":      "Подтверждение",
+	"confirm.delete":     "Удалить подключение",
+	"confirm.delete.msg": "Переместить это подключение в корзину?",
+	"confirm.yes":        "Да",
+	"confirm.no":         "Нет",
+	"confirm.help":       "y:да  n:нет  tab:переключить  enter:подтвердить  esc:отмена",
+	"confirm.default":    "Вы уверены?",
+
+	// Help
+	"help.title":              "Справка GoSSH",
+	"help.navigation":         "Навигация",
+	"help.connection":         "Управление подключениями",
+	"help.form":               "Навигация по форме",
+	"help.general":            "Общее",
+	"help.settings":           "Настройки",
+	"help.actions":            "Действия",
+	"help.key.up":             "Вверх",
+	"help.key.down":           "Вниз",
+	"help.key.top":            "В начало",
+	"help.key.bottom":         "В конец",
+	"help.key.left":           "Прокрутить влево",
+	"help.key.right":          "Прокрутить вправо",
+	"help.key.color_prev":     "Предыдущий цвет",
+	"help.key.color_next":     "Следующий цвет",
+	"help.key.layout":         "Переключить строки и таблицу",
+	"help.key.sort":           "Сортировать таблицу по следующему столбцу",
+	"help.key.sort_reverse":   "Обратить порядок сортировки",
+	"help.key.filters":        "Сохранённые фильтры",
+	"help.key.toggle_column":  "Показать или скрыть столбец",
+	"help.key.rule_add":       "Добавить правило группировки",
+	"help.key.rule_delete":    "Удалить выбранное правило",
+	"help.key.default_group":  "Задать группу по умолчанию",
+	"help.key.rule_preview":   "Предпросмотр правил на существующих подключениях",
+	"help.key.rule_apply":     "Применить правила к существующим подключениям",
+	"help.key.merge_local":    "Оставить локальное значение",
+	"help.key.merge_incoming": "Взять импортированное значение",
+	"help.key.merge_apply":    "Импортировать с выбранными значениями",
+	"help.key.dedupe_pick":    "Выбрать подключение, которое останется",
+	"help.key.dedupe_merge":   "Объединить с ним остальные",
+	"help.key.search":         "Поиск подключений",
+	"help.key.connect":        "Подключиться к выбранному серверу",
+	"help.key.enter":          "Подключиться / Выбрать",
+	"help.key.add":            "Добавить подключение",
+	"help.key.edit":           "Изменить выбранное подключение",
+	"help.key.delete":         "Удалить выбранное подключение",
+	"help.key.tab":            "Следующее поле",
+	"help.key.shifttab":       "Предыдущее поле",
+	"help.key.space":          "Переключить параметр / открыть список",
+	"help.key.save":           "Сохранить",
+	"help.key.cancel":         "Отмена",
+	"help.key.help":           "Показать справку",
+	"help.key.quit":           "Выйти из приложения",
+	"help.key.back":           "Назад / Отмена",
+	"help.key.settings":       "Настройки",
+	"help.key.test":           "Проверить подключение",
+	"help.key.test_all":       "Проверить все подключения в фоне",
+	"help.key.broadcast":      "Вводить сразу во все хосты списка",
+	"help.key.exec":           "Выполнить команду на хостах списка",
+	"help.key.history":        "История изменений подключения",
+	"help.key.scrollback":     "Поиск в выводе последнего сеанса",
+	"help.key.plugins":        "Запустить плагин для подключения",
+	"help.key.details":        "Сведения и вложения",
+	"help.key.local":          "Выполнить локальную команду или оболочку",
+	"help.key.lock":           "Заблокировать: забыть ключи до ввода мастер-пароля",
+	"help.key.copy":           "Скопировать команду ssh",
+	"help.key.test_form":      "Проверить введённое подключение",
+	"help.key.browse":         "Выбрать файл закрытого ключа",
+	"help.key.reveal":         "Показать или скрыть пароль и парольную фразу ключа",
+	"help.key.open":           "Открыть выбранный пункт",
+	"help.key.restore":        "Восстановить выбранное подключение",
+	"help.key.purge":          "Удалить навсегда (нажать дважды)",
+	"help.return":             "Нажмите любую клавишу, чтобы вернуться",
+	"help.scroll":             "↑/↓ прокрутка",
+
+	// Settings
+	"settings.title":                      "Настройки",
+	"settings.language":                   "Язык",
+	"settings.security":                   "Безопасность",
+	"settings.password.enable":            "Включить мастер-пароль",
+	"settings.password.change":            "Изменить мастер-пароль",
+	"settings.password.disable":           "Отключить мастер-пароль",
+	"settings.about":                      "О программе",
+	"settings.save":                       "Сохранить",
+	"settings.cancel":                     "Отмена",
+	"settings.saved":                      "Настройки сохранены",
+	"settings.help":                       "↑/↓: навигация • enter: выбрать • ?: справка • esc: назад",
+	"settings.help.language":              "↑/↓: выбор языка • enter: подтвердить • esc: назад",
+	"settings.help.password":              "tab/↑/↓: сменить поле • enter: подтвердить • esc: назад",
+	"settings.help.password.disable":      "enter: подтвердить • esc: назад",
+	"settings.import":                     "Импорт подключений",
+	"settings.export":                     "Экспорт подключений",
+	"settings.trash":                      "Корзина (%d)",
+	"settings.notify":                     "Уведомления: %s",
+	"settings.notify.off":                 "Выкл",
+	"settings.notify.bell":                "Звонок терминала",
+	"settings.notify.desktop":             "Рабочий стол",
+	"settings.keep_warm":                  "Держать подключения открытыми: %s",
+	"settings.keep_warm.off":              "Выкл",
+	"settings.keep_warm.minutes":          "%d мин",
+	"settings.scrollback":                 "Сохранять вывод сеансов: %s",
+	"settings.key_cache":                  "Запоминать мастер-ключ для CLI: %s",
+	"settings.theme":                      "Тема: %s",
+	"settings.theme.dark":                 "Тёмная",
+	"settings.theme.light":                "Светлая",
+	"settings.connection":                 "Подключения и терминал",
+	"settings.connection.title":           "Подключения и терминал",
+	"settings.on":                         "Вкл",
+	"settings.off":                        "Выкл",
+	"settings.seconds":                    "%d с",
+	"settings.timeout":                    "Тайм-аут подключения: %s",
+	"settings.timeout.prompt":             "Сколько секунд может длиться подключение к хосту:",
+	"settings.default_port":               "Порт по умолчанию: %d",
+	"settings.port.prompt":                "Порт для новых SSH-подключений:",
+	"settings.default_user":               "Пользователь по умолчанию: %s",
+	"settings.default_user.none":          "нет",
+	"settings.user.prompt":                "Пользователь для новых подключений (пусто — без него):",
+	"settings.keepalive":                  "Интервал keepalive: %s",
+	"settings.keepalive.default":          "по умолчанию (10 с)",
+	"settings.keepalive.prompt":           "Секунд между keepalive в сеансах (0 — по умолчанию):",
+	"settings.health":                     "Фоновая проверка доступности: %s",
+	"settings.health.off":                 "Выкл",
+	"settings.health.minutes":             "каждые %d мин",
+	"settings.confirm_delete":             "Подтверждать удаление: %s",
+	"settings.term":                       "Тип терминала (TERM): %s",
+	"settings.term.local":                 "локальный",
+	"settings.term.prompt":                "TERM для подключений, где он не задан (пусто — локальный):",
+	"settings.locale":                     "Локаль: %s",
+	"settings.locale.none":                "без изменений",
+	"settings.locale.prompt":              "Локаль для подключений, где она не задана, например C.UTF-8 (пусто — не отправлять):",
+	"settings.rules":                      "Правила группировки (%d)",
+	"settings.rules.title":                "Правила группировки",
+	"settings.rules.empty":                "Правил нет. Нажмите n, чтобы относить хосты вроде \\.prod\\.example\\.com$ к группе Production.",
+	"settings.rules.default":              "Группа по умолчанию: %s",
+	"settings.rules.default.none":         "нет",
+	"settings.rules.default.prompt":       "Группа для новых подключений, не подпавших ни под одно правило (пусто — без группы):",
+	"settings.rules.pattern":              "Шаблон (регулярное выражение для имени или хоста):",
+	"settings.rules.pattern.placeholder":  "например \\.prod\\.example\\.com$",
+	"settings.rules.group":                "Группа:",
+	"settings.rules.tags":                 "Теги (через запятую):",
+	"settings.rules.preview.title":        "Правила группировки: пробный запуск",
+	"settings.rules.preview.empty":        "Правила не изменят ни одного существующего подключения.",
+	"settings.rules.preview.count":        "Изменится существующих подключений: %d",
+	"settings.rules.preview.more":         "… и ещё %d",
+	"settings.rules.applied":              "Распределено подключений: %d",
+	"settings.help.rules":                 "↑/↓: выбрать • n: добавить • d: удалить • g: группа по умолчанию • p: пробный запуск • esc: назад",
+	"settings.help.rules.add":             "tab: след. поле • enter: сохранить • esc: отмена",
+	"settings.help.rules.default":         "enter: сохранить • esc: отмена",
+	"settings.help.rules.preview":         "enter: применить к существующим подключениям • esc: назад",
+	"settings.help.value":                 "enter: сохранить • esc: отмена",
+	"filters.title":                       "Сохранённые фильтры",
+	"filters.empty":                       "Сохранённых фильтров нет. Сначала выполните поиск через /, затем нажмите здесь n, чтобы сохранить его.",
+	"filters.name":                        "Имя:",
+	"filters.name.placeholder":            "например prod web",
+	"filters.no_search":                   "Сначала выполните поиск через /, чтобы было что сохранить",
+	"filters.help":                        "enter:применить  n:сохранить текущий поиск  d:удалить  esc:закрыть",
+	"filters.help.naming":                 "enter:сохранить  esc:отмена",
+	"plugins.title":                       "Плагины для %s",
+	"plugins.empty":                       "Плагинов нет. Поместите исполняемые файлы с именами %s<команда> в %s",
+	"plugins.help":                        "enter:запустить  esc:закрыть",
+	"details.title":                       "Сведения: %s",
+	"details.address":                     "Адрес",
+	"details.group":                       "Группа",
+	"details.tags":                        "Теги",
+	"details.password":                    "Пароль",
+	"details.key_passphrase":              "Парольная фраза ключа",
+	"details.agent_key":                   "Ключ агента",
+	"details.notes":                       "Заметки",
+	"details.attachments":                 "Вложения",
+	"details.attachments.empty":           "Вложений пока нет",
+	"details.add.prompt":                  "Прикрепить файл:",
+	"details.export.prompt":               "Экспортировать в:",
+	"details.added":                       "Прикреплён %s",
+	"details.deleted":                     "Удалён %s",
+	"details.exported":                    "%s экспортирован в %s",
+	"details.exists":                      "файл существует, не перезаписан",
+	"details.not_file":                    "не обычный файл",
+	"details.binary":                      "%s — не текст: экспортируйте его, чтобы открыть",
+	"details.delete.confirm":              "Удалить %s? (y/n)",
+	"details.revealed":                    "Показаны 15 с: ctrl+r скрывает",
+	"details.help":                        "enter:просмотр  a:прикрепить  x:экспорт  d:удалить  ctrl+r:показать пароли  esc:закрыть",
+	"history.title":                       "История: %s",
+	"history.empty":                       "Изменений пока не записано",
+	"history.restored":                    "Восстановлена версия до изменения от %s",
+	"history.help":                        "↑/↓: выбрать • r: восстановить эту версию • esc: назад",
+	"scrollback.title":                    "Вывод: %s",
+	"scrollback.position":                 "строки %d-%d из %d",
+	"scrollback.dropped":                  "(более старый вывод отброшен)",
+	"scrollback.empty":                    "Сеанс ничего не вывел",
+	"scrollback.help":                     "↑/↓/pgup/pgdn: прокрутка • g/G: начало/конец • /: поиск • n/N: предыдущее/следующее совпадение • esc: назад",
+	"scrollback.search.placeholder":       "текст для поиска",
+	"scrollback.no_match":                 "Ни одна строка не содержит \"%s\"",
+	"scrollback.off":                      "Вывод сеансов не сохраняется, включите это в настройках",
+	"scrollback.none":                     "Вывод сеансов с %s ещё не сохранён",
+	"settings.trash.title":                "Корзина",
+	"settings.trash.empty":                "Корзина пуста",
+	"settings.trash.info":                 "удалено %s · окончательно через %d дн.",
+	"settings.trash.restored":             "'%s' восстановлено",
+	"settings.trash.purged":               "'%s' удалено навсегда",
+	"settings.trash.confirm_purge":        "Нажмите p ещё раз, чтобы удалить '%s' навсегда",
+	"settings.groups":                     "Цвета групп",
+	"settings.groups.title":               "Цвета групп",
+	"settings.groups.empty":               "Групп нет",
+	"settings.layout":                     "Вид списка: %s",
+	"settings.layout.lines":               "Строки",
+	"settings.layout.table":               "Таблица",
+	"settings.columns":                    "Столбцы таблицы",
+	"settings.columns.note":               "Столбцы видны, когда вид списка — Таблица (нажмите v в списке)",
+	"settings.transfer.path":              "Путь к файлу",
+	"settings.transfer.format":            "Формат",
+	"settings.transfer.format.yaml":       "GoSSH (YAML)",
+	"settings.transfer.format.ssh_config": "Конфигурация OpenSSH",
+	"settings.transfer.note.import":       "Уже существующие подключения (по имени, псевдониму или адресу) объединяются поле за полем",
+	"settings.import.result":              "Импорт завершён",
+	"settings.export.result":              "Экспорт завершён",
+	"settings.transfer.file":              "Файл",
+	"settings.transfer.imported":          "Импортировано",
+	"settings.transfer.skipped":           "Пропущено",
+	"settings.transfer.merged":            "Объединено",
+	"settings.merge.title":                "Конфликты импорта: %d",
+	"settings.merge.summary":              "Будет добавлено новых подключений: %d, без изменений: %d. Выберите, какое значение оставить в каждом поле.",
+	"settings.help.merge":                 "↑/↓: выбрать • ←: локальное • →: импортированное • пробел: переключить • enter: импорт • esc: отмена",
+	"settings.duplicates":                 "Дубликаты (%d)",
+	"settings.duplicates.title":           "Повторяющиеся подключения",
+	"settings.duplicates.empty":           "Нет подключений с одинаковыми пользователем, хостом и портом.",
+	"settings.duplicates.keep":            "оставить",
+	"settings.duplicates.done":            "Объединено подключений: %d, оставлено %s; остальные в корзине",
+	"settings.help.duplicates":            "↑/↓: набор дубликатов • ←/→: оставить • enter: объединить • esc: назад",
+	"settings.transfer.exported":          "Экспортировано",
+	"settings.help.transfer":              "tab: сменить поле • ←/→: формат • enter: выполнить • esc: назад",
+	"settings.help.result":                "enter/esc: назад",
+	"settings.help.trash":                 "↑/↓: выбрать • r/enter: восстановить • p: удалить навсегда • esc: назад",
+	"settings.help.groups":                "↑/↓: выбрать • ←/→: сменить цвет • esc: назад",
+	"settings.help.columns":               "↑/↓: выбрать • пробел: показать/скрыть • esc: назад",
+
+	// Host key verification
+	"hostkey.title":       "Проверка ключа хоста",
+	"hostkey.unknown":     "Неизвестный хост",
+	"hostkey.unknown.msg": "Подлинность хоста '%s' не может быть установлена.",
+	"hostkey.fingerprint": "Отпечаток",
+	"hostkey.keytype":     "Тип ключа",
+	"hostkey.trust":       "Доверять этому хосту и продолжить подключение?",
+	"hostkey.changed":     "ВНИМАНИЕ: ключ хоста изменился!",
+	"hostkey.changed.msg": "Ключ хоста '%s' изменился. Возможна атака «человек посередине»!",
+	"hostkey.accept":      "Принять",
+	"hostkey.reject":      "Отклонить",
+	"hostkey.update":      "Обновить",
+	"hostkey.help":        "y:принять  n:отклонить  enter:подтвердить",
+	"banner.title":        "Баннер %s",
+	"banner.help":         "enter:продолжить  esc:отмена",
+	"hostkey.previous":    "Прежний отпечаток:",
+
+	// Health check
+	"health.title":              "Проверка подключения",
+	"health.testing":            "Проверка подключения...",
+	"health.all.progress":       "Проверка подключений... %d/%d",
+	"health.all.done":           "Проверено подключений: %d, доступно %d, недоступно %d",
+	"broadcast.title.all":       "Трансляция на %d хостов",
+	"broadcast.title.single":    "Ввод только в %s",
+	"broadcast.help":            "ctrl+n/ctrl+p:сменить хост  ctrl+t:все/один  ctrl+q:закрыть",
+	"broadcast.confirm":         "Трансляция",
+	"broadcast.confirm.msg":     "Открыть оболочку на %d хостах и вводить во все сразу?",
+	"broadcast.closed":          "Трансляция закрыта",
+	"broadcast.dropped":         "Отброшено вводов: %d — хост не успевает их принимать",
+	"exec.title":                "Выполнить на %d хостах",
+	"exec.command":              "Команда:",
+	"exec.command.placeholder":  "uptime",
+	"exec.progress":             "%d/%d готово · %d выполняется · %d с ошибкой",
+	"exec.eta":                  "осталось около %s",
+	"exec.exit":                 "код %d",
+	"exec.finished":             "%d успешно, %d с ошибкой",
+	"exec.done":                 "Команда завершена: %d успешно, %d с ошибкой",
+	"exec.stopping":             "Остановка команды на хостах...",
+	"exec.help.input":           "enter: выполнить • esc: отмена",
+	"exec.help.running":         "↑/↓: выбрать хост • esc: остановить",
+	"exec.help.done":            "↑/↓: выбрать хост • esc: назад",
+	"local.title":               "Локальная команда",
+	"local.command":             "Команда:",
+	"local.command.placeholder": "пусто — оболочка, exit — вернуться",
+	"local.help":                "enter: выполнить • esc: отмена",
+	"local.return":              "Нажмите Enter, чтобы вернуться в gossh",
+	"local.exit":                "Локальная команда завершилась с кодом %d",
+	"health.checking":           "Проверка...",
+	"health.reachable":          "Доступен",
+	"health.unreachable":        "Недоступен",
+	"health.auth_failed":        "Ошибка аутентификации",
+	"health.result.success":     "✓ Подключение успешно",
+	"health.result.fail":        "✗ Ошибка подключения",
+
+	// SFTP
+	"sftp.connected":   "SFTP подключён к %s",
+	"sftp.pwd":         "Текущий каталог: %s",
+	"sftp.uploading":   "Загрузка на сервер: %s",
+	"sftp.downloading": "Скачивание: %s",
+	"sftp.progress":    "%d%% (%s / %s)",
+	"sftp.complete":    "Передача завершена",
+
+	// Import
+	"import.title":          "Импорт конфигурации SSH",
+	"import.reading":        "Чтение %s...",
+	"import.found":          "Найдено подключений: %d",
+	"import.importing":      "Импорт...",
+	"import.skip.duplicate": "Пропуск дубликата: %s",
+	"import.complete":       "Импорт завершён: импортировано %d, пропущено %d",
+	"wizard.title":          "Импорт SSH-хостов",
+	"wizard.desc":           "Найдено новых хостов: %d в %s. Выберите хосты для импорта:",
+	"wizard.selected":       "Выбрано %d из %d",
+	"wizard.help":           "↑/↓:перемещение  пробел:переключить  a:все  g:сменить группу  G:группа для всех  enter:импорт  esc:пропустить",
+
+	// Errors
+	"error.connection":                  "Ошибка подключения",
+	"error.auth":                        "Ошибка аутентификации",
+	"error.timeout":                     "Превышено время ожидания подключения",
+	"error.unknown":                     "Неизвестная ошибка",
+	"error.validation.name":             "имя обязательно",
+	"error.validation.host":             "хост обязателен",
+	"error.validation.host_invalid":     "хост должен быть именем хоста или IP-адресом",
+	"error.validation.aliases":          "псевдонимы должны быть отдельными словами, отличными от имени",
+	"error.validation.addresses":        "другие адреса должны быть именами хостов или IP-адресами",
+	"error.validation.user":             "пользователь обязателен",
+	"error.validation.port":             "порт должен быть от 1 до 65535",
+	"error.validation.key_path":         "для аутентификации по ключу нужен путь к ключу",
+	"error.validation.key_file":         "файл ключа не читается или не является закрытым ключом",
+	"error.validation.key_passphrase":   "ключ зашифрован, введите его парольную фразу",
+	"error.validation.agent_key":        "ключ агента должен быть отпечатком SHA256, как его выводит ssh-add -l",
+	"error.validation.term":             "тип терминала должен быть одним словом, например vt100",
+	"error.validation.locale":           "локаль должна быть одним словом, например C.UTF-8",
+	"error.validation.rotate_after":     "период смены должен быть положительным числом дней",
+	"error.validation.expires_at":       "дата истечения должна быть в формате ГГГГ-ММ-ДД",
+	"error.validation.pattern":          "шаблон должен быть допустимым регулярным выражением",
+	"error.validation.rule":             "правилу нужна группа или теги",
+	"error.validation.workspace":        "рабочему пространству нужны подключения или туннели",
+	"error.validation.tunnel":           "туннелю нужны подключение, -L или -R и описание переадресации",
+	"error.validation.forward_profiles": "профилю переадресации нужны имя без пробелов и описания -L, -R или -D",
+	"error.validation.snippet":          "фрагменту нужны имя и команда",
+	"error.validation.cron":             "расписание должно быть в формате cron, например 0 3 * * *",
+	"error.validation.command":          "команда обязательна",
+	"error.validation.timeout":          "тайм-аут должен быть положительным числом секунд",
+	"error.validation.interval":         "интервал должен быть положительным числом или 0 для значения по умолчанию",
+	"error.validation.theme":            "тема должна быть dark или light",
+	"error.validation.default_user":     "пользователь по умолчанию должен быть одним словом",
+	"error.password.invalid":            "неверный пароль",
+	"error.password.weak":               "слишком слабый пароль: нужно не менее 8 символов",
+	"error.attachment.too_large":        "вложения ограничены %d КБ",
+	"error.attachment.empty":            "файл пуст",
+	"error.attachment.exists":           "у подключения уже есть вложение с таким именем",
+	"error.locked":                      "сначала разблокируйте gossh",
+	"error.insecure_dir":                "%s доступен для чтения другим пользователям: экспортируйте пароли в закрытое место",
+	"error.password.policy.length":      "слишком слабый пароль: нужно не менее %d символов",
+	"error.password.policy.classes":     "слишком слабый пароль: используйте не менее %d из строчных, прописных, цифр и символов",
+	"error.password.policy.common":      "слишком слабый пароль: он распространён или содержит запрещённое слово",
+	"error.password.policy.score":       "пароль слишком легко угадать: сделайте его длиннее или используйте несколько несвязанных слов",
+
+	// Common
+	"common.loading":                 "Загрузка...",
+	"common.saving":                  "Сохранение...",
+	"common.success":                 "Успешно",
+	"common.error":                   "Ошибка",
+	"common.back":                    "Назад",
+	"common.next":                    "Далее",
+	"common.done":                    "Готово",
+	"common.connecting":              "Подключение к %s...",
+	"session.summary":                "%s: завершено с кодом %d через %s",
+	"session.pre_connect.failed":     "команда pre_connect завершилась ошибкой: %v",
+	"session.post_disconnect.failed": "команда post_disconnect завершилась ошибкой: %v",
+	"session.suspended":              "%s приостановлено — нажмите enter, чтобы продолжить",
+	"status.protected":               "мастер-пароль включён",
+	"status.unprotected":             "без мастер-пароля",
+	"status.sessions":                "приостановленных сеансов: %d",
+	"status.jobs":                    "фоновых задач: %d",
+	"session.closed":                 "Соединение с %s закрыто",
+	"common.conn_error":              "Ошибка подключения: %s",
+	"common.state_error":             "Не удалось сохранить состояние подключения: %v",
+	"report.title":                   "Инвентаризация подключений",
+	"report.summary":                 "Создано %s: хостов %d, из них %d с учётными данными, требующими внимания.",
+	"report.hosts":                   "Хосты",
+	"report.groups":                  "Группы",
+	"report.tags":                    "Теги",
+	"report.name":                    "Имя",
+	"report.address":                 "Адрес",
+	"report.group":                   "Группа",
+	"report.last_seen":               "Последнее подключение",
+	"report.health":                  "Доступность",
+	"report.health.success":          "доступен",
+	"report.health.failed":           "недоступен",
+	"report.health.unknown":          "неизвестно",
+	"report.auth":                    "Аутентификация",
+	"report.changed":                 "Учётные данные изменены",
+	"report.age":                     "Возраст",
+	"report.credentials":             "Учётные данные",
+	"report.system":                  "Система",
+}
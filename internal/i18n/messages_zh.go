@@ -14,203 +14,989 @@ var messagesZH = map[string]string{
 	"menu.quit":        "退出",
 
 	// Connection list
-	"list.title":           "SSH 连接管理",
-	"list.empty":           "暂无连接，按 'a' 添加新连接",
-	"list.empty.search":    "没有匹配的连接",
-	"list.search":          "搜索",
-	"list.filter":          "筛选: %s (按 / 搜索, esc 清除)",
-	"list.filter.all":      "全部",
-	"list.filter.group":    "分组",
-	"list.total":           "共 %d 个连接",
-	"list.showing":         " (显示 %d 个)",
-	"list.ungrouped":       "未分组",
-	"list.status.unknown":  "?",
-	"list.status.ok":       "✓",
-	"list.status.fail":     "✗",
-	"list.status.checking": "...",
-	"list.help":            "a:添加  e:编辑  d:删除  /:搜索  s:设置  t:测试  enter:连接  ?:帮助  q:退出",
-	"list.help.search":     "输入搜索  enter:确认  esc:取消",
+	"list.title":              "SSH 连接管理",
+	"list.empty":              "暂无连接，按 'a' 添加新连接",
+	"list.empty.search":       "没有匹配的连接",
+	"list.search":             "搜索",
+	"list.filter":             "筛选: %s (按 / 搜索, esc 清除)",
+	"list.filter.all":         "全部",
+	"list.filter.group":       "分组",
+	"list.total":              "共 %d 个连接",
+	"list.showing":            " (显示 %d 个)",
+	"list.ungrouped":          "未分组",
+	"list.status.unknown":     "?",
+	"list.status.ok":          "✓",
+	"list.status.fail":        "✗",
+	"list.status.checking":    "...",
+	"list.trashed":            "已移到回收站 — 可在设置中恢复",
+	"list.copied":             "已复制：%s",
+	"list.copy_failed":        "复制失败",
+	"list.stale.rotate":       "需轮换",
+	"list.stale.expired":      "已过期",
+	"list.uptime":             "已运行 %s",
+	"list.via":                "（经由 %s）",
+	"list.column.name":        "名称",
+	"list.column.host":        "用户@主机",
+	"list.column.group":       "分组",
+	"list.column.tags":        "标签",
+	"list.column.last_seen":   "最近连接",
+	"list.column.status":      "状态",
+	"list.column.system":      "系统",
+	"list.help":               "a:添加  e:编辑  d:删除  /:搜索  s:设置  t:测试  y:复制  enter:连接  ?:帮助  q:退出",
+	"list.help.search":        "输入搜索  enter:确认  esc:取消",
+	"list.search.placeholder": "搜索...",
 
 	// Connection form
-	"form.title.add":       "添加连接",
-	"form.title.edit":      "编辑连接",
-	"form.name":            "名称",
-	"form.name.hint":       "连接的显示名称",
-	"form.host":            "主机",
-	"form.host.hint":       "主机名或 IP 地址",
-	"form.port":            "端口",
-	"form.port.hint":       "SSH 端口（默认：22）",
-	"form.user":            "用户名",
-	"form.user.hint":       "SSH 登录用户名",
-	"form.auth_type":       "认证方式",
-	"form.auth.password":   "密码认证",
-	"form.auth.key":        "密钥认证",
-	"form.password":        "密码",
-	"form.password.hint":   "SSH 登录密码",
-	"form.key_path":        "密钥路径",
-	"form.key_path.hint":   "私钥文件路径",
-	"form.key_passphrase":  "密钥密码",
-	"form.key_pass.hint":   "私钥的保护密码（如有）",
-	"form.group":           "分组",
-	"form.group.hint":      "连接所属分组",
-	"form.tags":            "标签",
-	"form.tags.hint":       "逗号分隔的标签",
-	"form.startup_cmd":     "启动命令",
-	"form.startup_cmd.hint":"连接成功后执行的命令",
-	"form.save":            "保存",
-	"form.cancel":          "取消",
-	"form.error.required":  "此字段为必填项",
-	"form.error.port":      "端口号无效",
+	"form.title.add":               "添加连接",
+	"form.title.edit":              "编辑连接",
+	"form.name":                    "名称",
+	"form.name.hint":               "连接的显示名称",
+	"form.host":                    "主机",
+	"form.host.hint":               "主机名或 IP 地址",
+	"form.type":                    "协议",
+	"form.port":                    "端口",
+	"form.port.hint":               "SSH 端口（默认：22）",
+	"form.user":                    "用户名",
+	"form.user.hint":               "SSH 登录用户名",
+	"form.auth_type":               "认证方式",
+	"form.auth.password":           "密码认证",
+	"form.auth.key":                "密钥认证",
+	"form.password":                "密码",
+	"form.password.hint":           "SSH 登录密码",
+	"form.key_path":                "密钥路径",
+	"form.key_path.hint":           "私钥文件路径",
+	"form.key_passphrase":          "密钥密码",
+	"form.key_pass.hint":           "私钥的保护密码（如有）",
+	"form.group":                   "分组",
+	"form.group.hint":              "连接所属分组",
+	"form.tags":                    "标签",
+	"form.tags.hint":               "逗号分隔的标签",
+	"form.startup_cmd":             "启动命令",
+	"form.notes":                   "备注",
+	"form.rotate_after":            "轮换周期",
+	"form.expires_at":              "过期日期",
+	"form.startup_cmd.hint":        "连接成功后执行的命令",
+	"form.save":                    "保存",
+	"form.cancel":                  "取消",
+	"form.error.required":          "此字段为必填项",
+	"form.error.port":              "端口号无效",
+	"form.placeholder.name":        "我的服务器",
+	"form.placeholder.host":        "192.168.1.1 或 example.com",
+	"form.placeholder.optional":    "（可选）",
+	"form.placeholder.notes":       "关于此主机需要记住的信息",
+	"form.auth.opt.password":       "密码",
+	"form.auth.opt.key":            "密钥",
+	"form.agent_key":               "Agent 密钥",
+	"form.agent_key.any":           "任意密钥",
+	"form.agent_key.missing":       "%s（不在 agent 中）",
+	"form.note.toggle":             "（空格切换）",
+	"form.note.select":             "（空格选择）",
+	"form.note.optional":           "（可选）",
+	"form.note.revealed":           "（显示 15 秒，ctrl+r 隐藏）",
+	"form.note.agent_key":          "（只提供此 ssh-agent 密钥）",
+	"form.note.tags":               "（空格或逗号添加，→ 补全）",
+	"form.aliases":                 "别名",
+	"form.note.aliases":            "（连接和搜索时可用的其他名称）",
+	"form.addresses":               "备用地址",
+	"form.note.addresses":          "（主机不可达时按顺序尝试）",
+	"form.note.key_path":           "（ctrl+o 浏览）",
+	"form.note.host":               "（或 srv:_ssh._tcp.名称 / consul:服务）",
+	"form.jump_hosts":              "跳板机",
+	"form.note.jump_hosts":         "（已保存的连接，按顺序）",
+	"form.bind_address":            "绑定地址",
+	"form.term":                    "终端类型",
+	"form.locale":                  "区域设置",
+	"form.local_dir":               "本地目录",
+	"form.remote_dir":              "远程目录",
+	"form.note.bind_address":       "（本地 IP 或网卡，可选）",
+	"form.note.term":               "（请求的 TERM，默认使用本地值）",
+	"form.note.locale":             "（请求的 LANG/LC_ALL，可选）",
+	"form.note.local_dir":          "（SFTP 传输的本地起始目录，可选）",
+	"form.note.remote_dir":         "（SFTP 打开的目录，默认：主目录）",
+	"form.note.startup":            "（每行一条命令，连接后执行）",
+	"form.pre_connect":             "连接前命令",
+	"form.post_disconnect":         "断开后命令",
+	"form.note.pre_connect":        "（本地命令，成功后才开始会话）",
+	"form.note.post_disconnect":    "（本地命令，会话结束后执行）",
+	"form.quiet_login":             "静默登录",
+	"form.show_banner":             "显示横幅",
+	"form.gssapi":                  "GSSAPI",
+	"form.note.quiet_login":        "（隐藏 MOTD 和上次登录信息）",
+	"form.note.show_banner":        "（先显示服务器公告）",
+	"form.note.gssapi":             "（有 Kerberos 票据时优先使用）",
+	"form.note.gssapi_unsupported": "（需使用 -tags gssapi 构建）",
+	"form.opt.on":                  "开",
+	"form.opt.off":                 "关",
+	"form.note.rotate_after":       "（天，可选）",
+	"form.help":                    "tab:下一项  enter:保存  ctrl+t:测试  f1:帮助  esc:取消",
+	"form.test.running":            "正在测试连接...",
+	"form.test.ok":                 "✓ 连接成功（%s）",
+	"form.test.host_key":           "主机密钥已变更",
+	"form.keys.title":              "选择密钥文件",
+	"form.keys.none":               "在 %s 中未找到私钥",
+	"form.keys.encrypted":          "[已加密]",
+	"form.keys.help":               "↑/↓:移动  enter:选择  esc:取消",
 
 	// Setup
-	"setup.title":              "欢迎使用 GoSSH",
-	"setup.desc":               "请选择安全模式：",
-	"setup.option.password":    "[1] 启用密码保护（推荐）",
-	"setup.option.password.desc":"设置主密码，每次启动需要输入密码",
-	"setup.option.nopassword":  "[2] 跳过密码保护",
-	"setup.option.nopassword.desc":"快速启动，无需输入密码",
-	"setup.password.title":     "请设置主密码",
-	"setup.password.desc":      "此密码用于加密保存的凭证，请牢记！",
-	"setup.password.prompt":    "请输入主密码",
-	"setup.password.confirm":   "确认主密码",
-	"setup.password.hint":      "最少 8 个字符",
-	"setup.password.mismatch":  "两次输入的密码不一致",
-	"setup.password.weak":      "密码强度不足",
-	"setup.password.strength":  "密码强度",
-	"setup.complete":           "设置完成！",
-	"setup.help.choose":        "↑/↓:选择  1/2:快速选择  enter:确认  esc:退出",
-	"setup.help.password":      "tab:下一项  enter:确认  esc:返回",
+	"setup.title":                  "欢迎使用 GoSSH",
+	"setup.desc":                   "请选择安全模式：",
+	"setup.option.password":        "[1] 启用密码保护（推荐）",
+	"setup.option.password.desc":   "设置主密码，每次启动需要输入密码",
+	"setup.option.nopassword":      "[2] 跳过密码保护",
+	"setup.option.nopassword.desc": "快速启动，无需输入密码",
+	"setup.password.title":         "请设置主密码",
+	"setup.password.desc":          "此密码用于加密保存的凭证，请牢记！",
+	"setup.password.prompt":        "请输入主密码",
+	"setup.password.confirm":       "确认主密码",
+	"setup.password.hint":          "最少 8 个字符",
+	"setup.password.mismatch":      "两次输入的密码不一致",
+	"setup.password.weak":          "密码强度不足",
+	"setup.password.strength":      "密码强度",
+	"setup.complete":               "设置完成！",
+	"setup.help.choose":            "↑/↓:选择  1/2:快速选择  enter:确认  esc:退出",
+	"setup.help.password":          "tab:下一项  enter:确认  esc:返回",
+	"setup.placeholder.password":   "请输入主密码",
+	"setup.placeholder.confirm":    "请再次输入主密码",
 
 	// Unlock
-	"unlock.title":         "GoSSH 已锁定",
-	"unlock.prompt":        "请输入主密码以解锁：",
-	"unlock.label":         "密码：",
-	"unlock.error":         "密码错误",
-	"unlock.attempt":       "[尝试 %d/%d]",
-	"unlock.attempts":      "剩余尝试次数",
-	"unlock.failed":        "尝试次数过多，程序退出",
-	"unlock.help":          "enter:解锁  esc:退出",
+	"unlock.title":       "GoSSH 已锁定",
+	"unlock.prompt":      "请输入主密码以解锁：",
+	"unlock.label":       "密码：",
+	"unlock.error":       "密码错误",
+	"unlock.attempt":     "[失败次数：%d]",
+	"unlock.attempts":    "剩余尝试次数",
+	"unlock.failed":      "尝试次数过多，请在 %s 后重试",
+	"unlock.help":        "enter:解锁  esc:退出",
+	"unlock.placeholder": "请输入主密码",
+	"lock.unprotected":   "无需锁定：请先在设置中设置主密码",
 
 	// Confirm dialog
-	"confirm.title":        "确认",
-	"confirm.delete":       "删除连接",
-	"confirm.delete.msg":   "确定要删除此连接吗？",
-	"confirm.yes":          "是",
-	"confirm.no":           "否",
-	"confirm.help":         "y:是  n:否  tab:切换  enter:确认  esc:取消",
+	"confirm.title":      "确认",
+	"confirm.delete":     "删除连接",
+	"confirm.delete.msg": "确定将此连接移到回收站吗？",
+	"confirm.yes":        "是",
+	"confirm.no":         "否",
+	"confirm.help":       "y:是  n:否  tab:切换  enter:确认  esc:取消",
+	"confirm.default":    "确定吗？",
 
 	// Help
-	"help.title":           "GoSSH 帮助",
-	"help.navigation":      "导航",
-	"help.connection":      "连接管理",
-	"help.form":            "表单导航",
-	"help.search":          "搜索模式",
-	"help.general":         "通用",
-	"help.cli":             "命令行",
-	"help.actions":         "操作",
-	"help.key.up":          "向上移动",
-	"help.key.down":        "向下移动",
-	"help.key.top":         "跳到顶部",
-	"help.key.bottom":      "跳到底部",
-	"help.key.search":      "搜索连接",
-	"help.key.connect":     "连接到选中的服务器",
-	"help.key.enter":       "连接 / 选择",
-	"help.key.add":         "添加新连接",
-	"help.key.edit":        "编辑选中连接",
-	"help.key.delete":      "删除选中连接",
-	"help.key.tab":         "下一字段",
-	"help.key.shifttab":    "上一字段",
-	"help.key.space":       "切换认证方式 / 循环分组",
-	"help.key.save":        "保存",
-	"help.key.cancel":      "取消",
-	"help.key.type":        "按名称、主机、用户、分组或标签筛选",
-	"help.key.confirm":     "确认搜索并连接",
-	"help.key.help":        "显示帮助",
-	"help.key.quit":        "退出程序",
-	"help.key.back":        "返回 / 取消",
-	"help.key.settings":    "设置",
-	"help.key.test":        "测试连接",
-	"help.return":          "按 Esc 或 ? 返回",
-	"help.cli.list":        "列出所有连接",
-	"help.cli.connect":     "按名称连接",
-	"help.cli.export":      "导出连接",
-	"help.cli.import":      "导入连接",
-	"help.cli.check":       "检查连接健康状态",
-	"help.cli.sftp":        "启动 SFTP 会话",
-	"help.cli.forward":     "端口转发",
-	"help.cli.exec":        "批量执行命令",
+	"help.title":              "GoSSH 帮助",
+	"help.navigation":         "导航",
+	"help.connection":         "连接管理",
+	"help.form":               "表单导航",
+	"help.general":            "通用",
+	"help.settings":           "设置",
+	"help.actions":            "操作",
+	"help.key.up":             "向上移动",
+	"help.key.down":           "向下移动",
+	"help.key.top":            "跳到顶部",
+	"help.key.bottom":         "跳到底部",
+	"help.key.left":           "向左滚动",
+	"help.key.right":          "向右滚动",
+	"help.key.color_prev":     "上一个颜色",
+	"help.key.color_next":     "下一个颜色",
+	"help.key.layout":         "切换列表/表格布局",
+	"help.key.sort":           "按下一列排序表格",
+	"help.key.sort_reverse":   "反转排序方向",
+	"help.key.filters":        "已保存的筛选",
+	"help.key.toggle_column":  "显示或隐藏列",
+	"help.key.rule_add":       "添加分组规则",
+	"help.key.rule_delete":    "删除选中的规则",
+	"help.key.default_group":  "设置默认分组",
+	"help.key.rule_preview":   "预览规则对现有连接的影响",
+	"help.key.rule_apply":     "将规则应用到现有连接",
+	"help.key.merge_local":    "保留本地值",
+	"help.key.merge_incoming": "采用导入的值",
+	"help.key.merge_apply":    "按所选的值导入",
+	"help.key.dedupe_pick":    "选择要保留的连接",
+	"help.key.dedupe_merge":   "将其余连接合并到它",
+	"help.key.search":         "搜索连接",
+	"help.key.connect":        "连接到选中的服务器",
+	"help.key.enter":          "连接 / 选择",
+	"help.key.add":            "添加新连接",
+	"help.key.edit":           "编辑选中连接",
+	"help.key.delete":         "删除选中连接",
+	"help.key.tab":            "下一字段",
+	"help.key.shifttab":       "上一字段",
+	"help.key.space":          "切换选项 / 打开下拉列表",
+	"help.key.save":           "保存",
+	"help.key.cancel":         "取消",
+	"help.key.help":           "显示帮助",
+	"help.key.quit":           "退出程序",
+	"help.key.back":           "返回 / 取消",
+	"help.key.settings":       "设置",
+	"help.key.test":           "测试连接",
+	"help.key.test_all":       "在后台测试所有连接",
+	"help.key.broadcast":      "同时向列表中的主机输入",
+	"help.key.exec":           "在列表中的主机上执行命令",
+	"help.key.history":        "连接的修改历史",
+	"help.key.scrollback":     "查看上次会话的输出",
+	"help.key.plugins":        "对该连接运行插件",
+	"help.key.details":        "详情与附件",
+	"help.key.local":          "运行本地命令或 Shell",
+	"help.key.lock":           "锁定：在重新输入主密码前清除密钥",
+	"help.key.copy":           "复制 ssh 命令",
+	"help.key.test_form":      "测试输入的连接",
+	"help.key.browse":         "选择私钥文件",
+	"help.key.reveal":         "显示或隐藏密码和密钥密码",
+	"help.key.open":           "打开选中项",
+	"help.key.restore":        "恢复选中的连接",
+	"help.key.purge":          "永久删除（按两次）",
+	"help.return":             "按任意键返回",
+	"help.scroll":             "↑/↓ 滚动",
 
 	// Settings
-	"settings.title":           "设置",
-	"settings.language":        "语言",
-	"settings.security":        "安全设置",
-	"settings.password.enable": "启用主密码",
-	"settings.password.change": "修改主密码",
-	"settings.password.disable":"禁用主密码",
-	"settings.about":           "关于",
-	"settings.save":            "保存",
-	"settings.cancel":          "取消",
-	"settings.saved":           "设置已保存",
-	"settings.help":            "↑/↓: 导航 • enter: 选择 • esc: 返回",
-	"settings.help.language":   "↑/↓: 选择语言 • enter: 确认 • esc: 返回",
-	"settings.help.password":   "tab/↑/↓: 切换字段 • enter: 确认 • esc: 返回",
-	"settings.help.password.disable": "enter: 确认 • esc: 返回",
+	"settings.title":                      "设置",
+	"settings.language":                   "语言",
+	"settings.security":                   "安全设置",
+	"settings.password.enable":            "启用主密码",
+	"settings.password.change":            "修改主密码",
+	"settings.password.disable":           "禁用主密码",
+	"settings.about":                      "关于",
+	"settings.save":                       "保存",
+	"settings.cancel":                     "取消",
+	"settings.saved":                      "设置已保存",
+	"settings.help":                       "↑/↓: 导航 • enter: 选择 • ?: 帮助 • esc: 返回",
+	"settings.help.language":              "↑/↓: 选择语言 • enter: 确认 • esc: 返回",
+	"settings.help.password":              "tab/↑/↓: 切换字段 • enter: 确认 • esc: 返回",
+	"settings.help.password.disable":      "enter: 确认 • esc: 返回",
+	"settings.import":                     "导入连接",
+	"settings.export":                     "导出连接",
+	"settings.trash":                      "回收站 (%d)",
+	"settings.notify":                     "完成通知：%s",
+	"settings.notify.off":                 "关闭",
+	"settings.notify.bell":                "终端响铃",
+	"settings.notify.desktop":             "桌面通知",
+	"settings.keep_warm":                  "保持连接：%s",
+	"settings.keep_warm.off":              "关闭",
+	"settings.keep_warm.minutes":          "%d 分钟",
+	"settings.scrollback":                 "保留会话输出：%s",
+	"settings.key_cache":                  "命令行记住主密钥：%s",
+	"settings.theme":                      "主题：%s",
+	"settings.theme.dark":                 "深色",
+	"settings.theme.light":                "浅色",
+	"settings.connection":                 "连接与终端",
+	"settings.connection.title":           "连接与终端",
+	"settings.on":                         "开",
+	"settings.off":                        "关",
+	"settings.seconds":                    "%d 秒",
+	"settings.timeout":                    "连接超时：%s",
+	"settings.timeout.prompt":             "连接主机最多等待的秒数：",
+	"settings.default_port":               "默认端口：%d",
+	"settings.port.prompt":                "新建 SSH 连接时填入的端口：",
+	"settings.default_user":               "默认用户：%s",
+	"settings.default_user.none":          "无",
+	"settings.user.prompt":                "新建连接时填入的用户（留空则不填）：",
+	"settings.keepalive":                  "保活间隔：%s",
+	"settings.keepalive.default":          "默认（10 秒）",
+	"settings.keepalive.prompt":           "会话中发送保活请求的间隔秒数（0 为默认）：",
+	"settings.health":                     "后台健康检查：%s",
+	"settings.health.off":                 "关闭",
+	"settings.health.minutes":             "每 %d 分钟",
+	"settings.confirm_delete":             "删除前确认：%s",
+	"settings.term":                       "终端类型（TERM）：%s",
+	"settings.term.local":                 "本地",
+	"settings.term.prompt":                "未设置 TERM 的连接使用的值（留空则用本地的）：",
+	"settings.locale":                     "区域设置：%s",
+	"settings.locale.none":                "不变",
+	"settings.locale.prompt":              "未设置区域的连接使用的区域，例如 C.UTF-8（留空则不发送）：",
+	"settings.rules":                      "分组规则（%d）",
+	"settings.rules.title":                "分组规则",
+	"settings.rules.empty":                "还没有规则。按 n 添加，例如把 \\.prod\\.example\\.com$ 归入 Production。",
+	"settings.rules.default":              "默认分组：%s",
+	"settings.rules.default.none":         "无",
+	"settings.rules.default.prompt":       "没有规则匹配的新连接放入的分组（留空则不分组）：",
+	"settings.rules.pattern":              "模式（匹配名称或主机的正则表达式）：",
+	"settings.rules.pattern.placeholder":  "例如 \\.prod\\.example\\.com$",
+	"settings.rules.group":                "分组：",
+	"settings.rules.tags":                 "标签（逗号分隔）：",
+	"settings.rules.preview.title":        "分组规则：试运行",
+	"settings.rules.preview.empty":        "规则不会改变任何现有连接。",
+	"settings.rules.preview.count":        "将改变 %d 个现有连接：",
+	"settings.rules.preview.more":         "… 另有 %d 个",
+	"settings.rules.applied":              "已归类 %d 个连接",
+	"settings.help.rules":                 "↑/↓: 选择 • n: 添加 • d: 删除 • g: 默认分组 • p: 试运行 • esc: 返回",
+	"settings.help.rules.add":             "tab: 下一项 • enter: 保存 • esc: 取消",
+	"settings.help.rules.default":         "enter: 保存 • esc: 取消",
+	"settings.help.rules.preview":         "enter: 应用到现有连接 • esc: 返回",
+	"settings.help.value":                 "enter: 保存 • esc: 取消",
+	"filters.title":                       "已保存的筛选",
+	"filters.empty":                       "还没有保存的筛选。先用 / 搜索，再在这里按 n 保存。",
+	"filters.name":                        "名称：",
+	"filters.name.placeholder":            "例如 prod web",
+	"filters.no_search":                   "请先用 / 搜索，再保存",
+	"filters.help":                        "enter:应用  n:保存当前搜索  d:删除  esc:关闭",
+	"filters.help.naming":                 "enter:保存  esc:取消",
+	"plugins.title":                       "%s 的插件",
+	"plugins.empty":                       "没有插件。将名为 %s<命令> 的可执行文件放到 %s",
+	"plugins.help":                        "enter:运行  esc:关闭",
+	"details.title":                       "详情：%s",
+	"details.address":                     "地址",
+	"details.group":                       "分组",
+	"details.tags":                        "标签",
+	"details.password":                    "密码",
+	"details.key_passphrase":              "密钥密码",
+	"details.agent_key":                   "Agent 密钥",
+	"details.notes":                       "备注",
+	"details.attachments":                 "附件",
+	"details.attachments.empty":           "暂无附件",
+	"details.add.prompt":                  "附加文件：",
+	"details.export.prompt":               "导出到：",
+	"details.added":                       "已附加 %s",
+	"details.deleted":                     "已删除 %s",
+	"details.exported":                    "已将 %s 导出到 %s",
+	"details.exists":                      "文件已存在，未覆盖",
+	"details.not_file":                    "不是普通文件",
+	"details.binary":                      "%s 不是文本：请导出后打开",
+	"details.delete.confirm":              "删除 %s？(y/n)",
+	"details.revealed":                    "显示 15 秒：ctrl+r 隐藏",
+	"details.help":                        "enter:查看  a:附加  x:导出  d:删除  ctrl+r:显示密码  esc:关闭",
+	"history.title":                       "修改历史：%s",
+	"history.empty":                       "还没有修改记录",
+	"history.restored":                    "已恢复到 %s 那次修改之前的版本",
+	"history.help":                        "↑/↓: 选择 • r: 恢复此版本 • esc: 返回",
+	"scrollback.title":                    "会话输出：%s",
+	"scrollback.position":                 "第 %d-%d 行，共 %d 行",
+	"scrollback.dropped":                  "（更早的输出已丢弃）",
+	"scrollback.empty":                    "会话没有任何输出",
+	"scrollback.help":                     "↑/↓/pgup/pgdn: 滚动 • g/G: 顶部/底部 • /: 搜索 • n/N: 上一个/下一个匹配 • esc: 返回",
+	"scrollback.search.placeholder":       "要查找的文本",
+	"scrollback.no_match":                 "没有包含 \"%s\" 的行",
+	"scrollback.off":                      "未保留会话输出，请在设置中开启",
+	"scrollback.none":                     "还没有保留 %s 的会话输出",
+	"settings.trash.title":                "回收站",
+	"settings.trash.empty":                "回收站为空",
+	"settings.trash.info":                 "删除于 %s · %d 天后清除",
+	"settings.trash.restored":             "已恢复 '%s'",
+	"settings.trash.purged":               "已永久删除 '%s'",
+	"settings.trash.confirm_purge":        "再次按 p 永久删除 '%s'",
+	"settings.groups":                     "分组颜色",
+	"settings.groups.title":               "分组颜色",
+	"settings.groups.empty":               "没有分组",
+	"settings.layout":                     "列表布局：%s",
+	"settings.layout.lines":               "列表",
+	"settings.layout.table":               "表格",
+	"settings.columns":                    "表格列",
+	"settings.columns.note":               "列表布局为表格时显示这些列（在列表中按 v 切换）",
+	"settings.transfer.path":              "文件路径",
+	"settings.transfer.format":            "格式",
+	"settings.transfer.format.yaml":       "GoSSH (YAML)",
+	"settings.transfer.format.ssh_config": "OpenSSH 配置",
+	"settings.transfer.note.import":       "名称、别名或地址已存在的连接将逐字段合并",
+	"settings.import.result":              "导入完成",
+	"settings.export.result":              "导出完成",
+	"settings.transfer.file":              "文件",
+	"settings.transfer.imported":          "已导入",
+	"settings.transfer.skipped":           "已跳过",
+	"settings.transfer.merged":            "已合并",
+	"settings.merge.title":                "导入冲突：%d 个连接",
+	"settings.merge.summary":              "将新增 %d 个连接，%d 个无变化。请为每个字段选择要保留的值。",
+	"settings.help.merge":                 "↑/↓: 选择 • ←: 本地 • →: 导入 • space: 切换 • enter: 导入 • esc: 取消",
+	"settings.duplicates":                 "重复连接（%d）",
+	"settings.duplicates.title":           "重复连接",
+	"settings.duplicates.empty":           "没有登录到相同用户、主机和端口的连接。",
+	"settings.duplicates.keep":            "保留",
+	"settings.duplicates.done":            "已将 %d 个连接合并到 %s；其余的已移入回收站",
+	"settings.help.duplicates":            "↑/↓: 切换组 • ←/→: 保留 • enter: 合并 • esc: 返回",
+	"settings.transfer.exported":          "已导出",
+	"settings.help.transfer":              "tab: 切换字段 • ←/→: 格式 • enter: 执行 • esc: 返回",
+	"settings.help.result":                "enter/esc: 返回",
+	"settings.help.trash":                 "↑/↓: 选择 • r/enter: 恢复 • p: 永久删除 • esc: 返回",
+	"settings.help.groups":                "↑/↓: 选择 • ←/→: 切换颜色 • esc: 返回",
+	"settings.help.columns":               "↑/↓: 选择 • space: 显示/隐藏 • esc: 返回",
 
 	// Host key verification
-	"hostkey.title":            "主机密钥验证",
-	"hostkey.unknown":          "未知主机",
-	"hostkey.unknown.msg":      "无法验证主机 '%s' 的真实性",
-	"hostkey.fingerprint":      "指纹",
-	"hostkey.keytype":          "密钥类型",
-	"hostkey.trust":            "是否信任此主机并继续连接？",
-	"hostkey.changed":          "警告：主机密钥已变更！",
-	"hostkey.changed.msg":      "主机 '%s' 的密钥已变更，这可能表示存在中间人攻击！",
-	"hostkey.accept":           "接受",
-	"hostkey.reject":           "拒绝",
-	"hostkey.update":           "更新",
-	"hostkey.help":             "y:接受  n:拒绝  enter:确认",
+	"hostkey.title":       "主机密钥验证",
+	"hostkey.unknown":     "未知主机",
+	"hostkey.unknown.msg": "无法验证主机 '%s' 的真实性",
+	"hostkey.fingerprint": "指纹",
+	"hostkey.keytype":     "密钥类型",
+	"hostkey.trust":       "是否信任此主机并继续连接？",
+	"hostkey.changed":     "警告：主机密钥已变更！",
+	"hostkey.changed.msg": "主机 '%s' 的密钥已变更，这可能表示存在中间人攻击！",
+	"hostkey.accept":      "接受",
+	"hostkey.reject":      "拒绝",
+	"hostkey.update":      "更新",
+	"hostkey.help":        "y:接受  n:拒绝  enter:确认",
+	"banner.title":        "来自 %s 的横幅",
+	"banner.help":         "enter:继续  esc:取消",
+	"hostkey.previous":    "之前的指纹：",
 
 	// Health check
-	"health.title":             "连接测试",
-	"health.testing":           "正在测试连接...",
-	"health.checking":          "检测中...",
-	"health.reachable":         "可连接",
-	"health.unreachable":       "无法连接",
-	"health.auth_failed":       "认证失败",
-	"health.result.success":    "✓ 连接成功",
-	"health.result.fail":       "✗ 连接失败",
+	"health.title":              "连接测试",
+	"health.testing":            "正在测试连接...",
+	"health.all.progress":       "正在检查连接... %d/%d",
+	"health.all.done":           "已检查 %d 个连接：%d 个正常，%d 个失败",
+	"broadcast.title.all":       "广播到 %d 台主机",
+	"broadcast.title.single":    "仅输入到 %s",
+	"broadcast.help":            "ctrl+n/ctrl+p:切换主机  ctrl+t:全部/单台  ctrl+q:关闭",
+	"broadcast.confirm":         "广播",
+	"broadcast.confirm.msg":     "在 %d 台主机上打开 shell 并同时输入？",
+	"broadcast.closed":          "广播已关闭",
+	"broadcast.dropped":         "已丢弃 %d 次输入：该主机响应过慢",
+	"exec.title":                "在 %d 台主机上执行",
+	"exec.command":              "命令：",
+	"exec.command.placeholder":  "uptime",
+	"exec.progress":             "%d/%d 已完成 · %d 运行中 · %d 失败",
+	"exec.eta":                  "约剩 %s",
+	"exec.exit":                 "退出码 %d",
+	"exec.finished":             "%d 成功，%d 失败",
+	"exec.done":                 "命令已完成：%d 成功，%d 失败",
+	"exec.stopping":             "正在停止主机上的命令...",
+	"exec.help.input":           "enter: 执行 • esc: 取消",
+	"exec.help.running":         "↑/↓: 选择主机 • esc: 停止",
+	"exec.help.done":            "↑/↓: 选择主机 • esc: 返回",
+	"local.title":               "本地命令",
+	"local.command":             "命令：",
+	"local.command.placeholder": "留空打开 Shell，输入 exit 返回",
+	"local.help":                "enter: 运行 • esc: 取消",
+	"local.return":              "按回车键返回 gossh",
+	"local.exit":                "本地命令退出，状态码 %d",
+	"health.checking":           "检测中...",
+	"health.reachable":          "可连接",
+	"health.unreachable":        "无法连接",
+	"health.auth_failed":        "认证失败",
+	"health.result.success":     "✓ 连接成功",
+	"health.result.fail":        "✗ 连接失败",
 
 	// SFTP
-	"sftp.connected":           "SFTP 已连接到 %s",
-	"sftp.pwd":                 "当前目录：%s",
-	"sftp.uploading":           "上传中：%s",
-	"sftp.downloading":         "下载中：%s",
-	"sftp.progress":            "%d%% (%s / %s)",
-	"sftp.complete":            "传输完成",
+	"sftp.connected":   "SFTP 已连接到 %s",
+	"sftp.pwd":         "当前目录：%s",
+	"sftp.uploading":   "上传中：%s",
+	"sftp.downloading": "下载中：%s",
+	"sftp.progress":    "%d%% (%s / %s)",
+	"sftp.complete":    "传输完成",
 
 	// Import
-	"import.title":             "导入 SSH 配置",
-	"import.reading":           "正在读取 %s...",
-	"import.found":             "找到 %d 个连接",
-	"import.importing":         "导入中...",
-	"import.skip.duplicate":    "跳过重复项：%s",
-	"import.complete":          "导入完成：%d 个已导入，%d 个已跳过",
+	"import.title":          "导入 SSH 配置",
+	"import.reading":        "正在读取 %s...",
+	"import.found":          "找到 %d 个连接",
+	"import.importing":      "导入中...",
+	"import.skip.duplicate": "跳过重复项：%s",
+	"import.complete":       "导入完成：%d 个已导入，%d 个已跳过",
+	"wizard.title":          "导入 SSH 主机",
+	"wizard.desc":           "在 %[2]s 中发现 %[1]d 个新主机，请选择要导入的主机：",
+	"wizard.selected":       "已选择 %d / %d",
+	"wizard.help":           "↑/↓:移动  space:勾选  a:全选  g:切换分组  G:全部使用此分组  enter:导入  esc:跳过",
 
 	// Errors
-	"error.connection":         "连接失败",
-	"error.auth":               "认证失败",
-	"error.timeout":            "连接超时",
-	"error.unknown":            "未知错误",
+	"error.connection":                  "连接失败",
+	"error.auth":                        "认证失败",
+	"error.timeout":                     "连接超时",
+	"error.unknown":                     "未知错误",
+	"error.validation.name":             "名称为必填项",
+	"error.validation.host":             "主机为必填项",
+	"error.validation.host_invalid":     "主机必须是主机名或 IP 地址",
+	"error.validation.aliases":          "别名必须是与名称不同的单个词",
+	"error.validation.addresses":        "其他地址必须是主机名或 IP 地址",
+	"error.validation.user":             "用户名为必填项",
+	"error.validation.port":             "端口必须在 1 到 65535 之间",
+	"error.validation.key_path":         "密钥认证需要填写密钥路径",
+	"error.validation.key_file":         "无法读取密钥文件或该文件不是私钥",
+	"error.validation.key_passphrase":   "密钥已加密，请输入密钥密码",
+	"error.validation.agent_key":        "agent 密钥须为 SHA256 指纹，与 ssh-add -l 输出的一致",
+	"error.validation.term":             "终端类型必须是一个单词，例如 vt100",
+	"error.validation.locale":           "区域设置必须是一个单词，例如 C.UTF-8",
+	"error.validation.rotate_after":     "轮换周期必须是正整数天数",
+	"error.validation.expires_at":       "过期日期格式必须为 YYYY-MM-DD",
+	"error.validation.pattern":          "模式必须是有效的正则表达式",
+	"error.validation.rule":             "规则需要分组或标签",
+	"error.validation.workspace":        "工作区至少需要连接或隧道",
+	"error.validation.tunnel":           "隧道需要连接、-L 或 -R 以及转发规则",
+	"error.validation.forward_profiles": "转发配置需要不含空格的名称以及 -L、-R 或 -D 转发规则",
+	"error.validation.snippet":          "代码片段需要名称和命令",
+	"error.validation.cron":             "计划必须是 cron 表达式，例如 0 3 * * *",
+	"error.validation.command":          "命令不能为空",
+	"error.validation.timeout":          "超时必须是正的秒数",
+	"error.validation.interval":         "间隔必须是正数，或 0 表示默认",
+	"error.validation.theme":            "主题必须是 dark 或 light",
+	"error.validation.default_user":     "默认用户必须是一个单词",
+	"error.password.invalid":            "密码错误",
+	"error.password.weak":               "密码强度不足：至少需要 8 个字符",
+	"error.attachment.too_large":        "附件不能超过 %d KB",
+	"error.attachment.empty":            "文件为空",
+	"error.attachment.exists":           "该连接已有同名附件",
+	"error.locked":                      "请先解锁 gossh",
+	"error.insecure_dir":                "其他用户可以读取 %s：请将密码导出到私有位置",
+	"error.password.policy.length":      "密码强度不足：至少需要 %d 个字符",
+	"error.password.policy.classes":     "密码强度不足：至少包含小写字母、大写字母、数字和符号中的 %d 种",
+	"error.password.policy.common":      "密码强度不足：这是常见密码或包含被禁止的词",
+	"error.password.policy.score":       "密码太容易被猜到：请加长密码，或使用几个不相关的词",
 
 	// Common
-	"common.loading":           "加载中...",
-	"common.saving":            "保存中...",
-	"common.success":           "成功",
-	"common.error":             "错误",
-	"common.back":              "返回",
-	"common.next":              "下一步",
-	"common.done":              "完成",
-	"common.connecting":        "正在连接 %s...",
-	"common.disconnected":      "已断开连接",
-	"common.conn_error":        "连接错误: %s",
+	"common.loading":                 "加载中...",
+	"common.saving":                  "保存中...",
+	"common.success":                 "成功",
+	"common.error":                   "错误",
+	"common.back":                    "返回",
+	"common.next":                    "下一步",
+	"common.done":                    "完成",
+	"common.connecting":              "正在连接 %s...",
+	"session.summary":                "%s：退出码 %d，时长 %s",
+	"session.pre_connect.failed":     "pre_connect 命令失败：%v",
+	"session.post_disconnect.failed": "post_disconnect 命令失败：%v",
+	"session.suspended":              "%s 已挂起 — 在该连接上按回车恢复",
+	"status.protected":               "已启用主密码",
+	"status.unprotected":             "未设置主密码",
+	"status.sessions":                "挂起的会话: %d",
+	"status.jobs":                    "后台任务：%d",
+	"session.closed":                 "与 %s 的连接已关闭",
+	"common.conn_error":              "连接错误: %s",
+	"common.state_error":             "保存连接状态失败: %v",
+
+	// CLI
+	"cli.help.title":                   "GoSSH - 终端 SSH 连接管理器 v%s",
+	"cli.help.usage":                   "用法：",
+	"cli.help.advanced":                "高级命令 (v1.2)：",
+	"cli.help.tui":                     "启动 TUI 界面",
+	"cli.help.config_flag":             "使用其他配置文件，作用于本次命令或 TUI",
+	"cli.help.help":                    "显示此帮助信息",
+	"cli.help.version":                 "显示版本信息",
+	"cli.help.list":                    "列出所有连接",
+	"cli.help.connect":                 "按名称连接服务器",
+	"cli.help.connect.share":           "将会话输出以只读方式镜像到套接字或文件",
+	"cli.help.connect.scrollback":      "会话结束后分页查看其输出",
+	"cli.help.connect.command":         "只执行一条命令而不启动 shell，并以其退出码退出",
+	"cli.help.rm":                      "将连接移到回收站（--purge 永久删除）",
+	"cli.help.trash":                   "查看、恢复或清除已删除的连接",
+	"cli.help.rename":                  "重命名连接",
+	"cli.help.dedupe":                  "合并用户、主机和端口相同的连接",
+	"cli.help.dedupe.dry_run":          "只列出重复项",
+	"cli.help.dedupe.yes":              "不询问，合并所有重复项",
+	"cli.help.history":                 "查看连接的修改记录、某次修改的内容，或恢复到修改之前的版本",
+	"cli.help.workspace":               "列出已保存的工作区",
+	"cli.help.workspace.save":          "保存工作区，替换同名的工作区",
+	"cli.help.workspace.sessions":      "要打开会话的连接",
+	"cli.help.workspace.tunnel":        "经由某个连接的隧道，格式同 ssh -L/-R",
+	"cli.help.workspace.snippet":       "稍后在这些连接上运行的命令",
+	"cli.help.workspace.open":          "启动隧道并打开会话（在 tmux 中为新窗口）",
+	"cli.help.workspace.run":           "在工作区的连接上运行代码片段",
+	"cli.help.workspace.rm":            "删除工作区",
+	"cli.help.schedule":                "列出计划命令",
+	"cli.help.schedule.add":            "按 cron 表达式运行命令，例如 \"0 3 * * *\" 或 @daily",
+	"cli.help.schedule.filter":         "运行命令的主机，同 exec（默认：全部）",
+	"cli.help.schedule.rm":             "删除计划命令",
+	"cli.help.schedule.daemon":         "在到期时运行计划命令，直到被中断",
+	"cli.help.export":                  "导出连接（默认：connections.yaml）",
+	"cli.help.export.force":            "允许将明文密码写入其他用户可读的目录",
+	"cli.help.import":                  "从文件导入连接",
+	"cli.help.import_ssh":              "从 SSH 配置文件导入",
+	"cli.help.sftp":                    "与服务器建立 SFTP 会话",
+	"cli.help.sftp.parallel":           "同时运行的排队传输数（默认：1）",
+	"cli.help.sftp.preserve":           "保留符号链接、所有者（root 时）和时间；不带值时全部保留",
+	"cli.help.sftp.trash":              "rm 和 rmdir 将文件移到 ~/.gossh-trash 而不是删除",
+	"cli.help.sftp.batch":              "执行文件中的命令（- 表示标准输入）后退出，遇到第一个失败的命令即停止",
+	"cli.help.sftp.exec":               "以同样方式执行用 ; 分隔的命令，例如 \"put app.tar; chmod 644 app.tar\"",
+	"cli.help.forward":                 "端口转发（-L 本地，-R 远程，-D SOCKS），可同时指定多条",
+	"cli.help.forward.match":           "按名称/主机正则选择服务器",
+	"cli.help.forward.save":            "同时将这些转发保存为连接的转发配置",
+	"cli.help.forward.profile":         "启动已保存的转发配置，可重复指定",
+	"cli.help.forward.all_profiles":    "启动连接已保存的所有转发配置",
+	"cli.help.forward.list":            "列出连接已保存的转发配置",
+	"cli.help.forward.gateway":         "允许 -R 转发监听服务器的所有网络接口",
+	"cli.help.forward.hint":            "要显示的客户端命令，{port} 为第一条转发的端口，{port2} 为第二条的",
+	"cli.help.forward.copy":            "将客户端命令复制到剪贴板",
+	"cli.help.exec":                    "在多台服务器上执行命令",
+	"cli.help.exec.group":              "按分组筛选",
+	"cli.help.exec.tags":               "按标签筛选",
+	"cli.help.exec.names":              "按名称筛选（支持 web-* 等通配符）",
+	"cli.help.exec.match":              "按名称/主机正则筛选",
+	"cli.help.exec.exclude_group":      "跳过某分组中的服务器",
+	"cli.help.exec.exclude_tags":       "跳过带有任一标签的服务器",
+	"cli.help.exec.exclude_names":      "按名称跳过服务器（支持通配符）",
+	"cli.help.exec.timeout":            "每台主机连接并运行的秒数上限（默认：30）",
+	"cli.help.exec.deadline":           "整个运行的时限（默认：无）",
+	"cli.help.exec.copy":               "将汇总输出复制到剪贴板",
+	"cli.help.exec.diff":               "按相同输出对主机分组并比较各个变体",
+	"cli.help.exec.pty":                "在伪终端中运行，用于需要终端的命令（如启用 requiretty 的 sudo）",
+	"cli.help.exec.strip_color":        "去除输出中的颜色及其他终端转义序列",
+	"cli.help.exec.env":                "为命令设置环境变量（可重复）",
+	"cli.help.exec.env_file":           "设置 .env 文件中的变量",
+	"cli.help.exec.output":             "结果格式：text、json（每台主机一行）、junit 或 markdown（默认：text）",
+	"cli.help.exec.retry_last":         "在上次运行失败的主机上再次运行",
+	"cli.help.exec.retry_failed":       "在某次运行失败的主机上再次运行（每次运行后会显示其 ID）",
+	"cli.help.play":                    "按顺序执行 playbook 的各个步骤，每步在其目标主机上执行",
+	"cli.help.play.dry_run":            "仅显示各步骤及其选中的主机",
+	"cli.help.play.yes":                "不询问直接执行",
+	"cli.help.push":                    "并行上传文件到多台主机",
+	"cli.help.push.filter":             "像 exec 一样选择主机",
+	"cli.help.push.mode":               "远程文件权限，例如 0644（默认：本地权限）",
+	"cli.help.push.owner":              "修改远程文件的所有者",
+	"cli.help.push.preserve_times":     "保留本地文件的访问和修改时间",
+	"cli.help.push.run":                "每台主机上传后运行的命令",
+	"cli.help.push.parallel":           "同时处理的主机数（默认：10）",
+	"cli.help.push.timeout":            "每台主机的超时秒数（默认：120）",
+	"cli.help.tail":                    "同时跟踪多台主机上的远程文件",
+	"cli.help.tail.filter":             "像 exec 一样选择主机",
+	"cli.help.tail.grep":               "只显示匹配该模式的行",
+	"cli.help.tail.lines":              "开始时显示的历史行数（默认：10）",
+	"cli.help.watch":                   "实时将本地改动上传到远程目录",
+	"cli.help.watch.exclude":           "要跳过的路径，.git 和编辑器临时文件总会跳过",
+	"cli.help.watch.delete":            "删除本地已删除的远程文件",
+	"cli.help.watch.debounce":          "等待改动稳定的毫秒数（默认：300）",
+	"cli.help.archive":                 "将远程目录下载为 .tar.gz（- 表示标准输出）",
+	"cli.help.extract":                 "将 .tar.gz（- 表示标准输入）解压到远程目录",
+	"cli.help.check":                   "连接健康检查",
+	"cli.help.check.all":               "检查所有连接",
+	"cli.help.check.group":             "按分组检查",
+	"cli.help.check.name":              "检查指定连接",
+	"cli.help.facts":                   "收集列表中显示的系统和运行时间",
+	"cli.help.facts.filter":            "像 exec 一样选择主机（默认：全部）",
+	"cli.help.report":                  "生成用于审计的清单报告",
+	"cli.help.report.format":           "md 或 html（默认：按文件名，否则为 md）",
+	"cli.help.report.output":           "写入文件而不是标准输出",
+	"cli.help.report.filter":           "像 exec 一样选择主机（默认：全部）",
+	"cli.help.audit":                   "列出已过期的连接和需要轮换的凭据",
+	"cli.help.audit.max_age":           "未单独设置的连接使用的轮换周期（如 90）",
+	"cli.help.audit.all":               "同时列出状态正常的连接",
+	"cli.help.validate":                "检查已保存连接中的问题",
+	"cli.help.monitor":                 "对连接进行健康检查并提供 Prometheus 指标",
+	"cli.help.monitor.listen":          "指标监听地址（默认：:9100）",
+	"cli.help.monitor.interval":        "探测间隔秒数（默认：30）",
+	"cli.help.monitor.timeout":         "单次探测超时秒数（默认：5）",
+	"cli.help.monitor.filter":          "与 exec 相同的目标过滤选项",
+	"cli.help.lock":                    "锁定正在运行的 gossh，直到重新输入主密码",
+	"cli.help.remember":                "将主密钥保存到系统密钥环，命令可自动解锁",
+	"cli.help.forget":                  "从系统密钥环中删除主密钥",
+	"cli.help.plugins":                 "列出插件，即插件目录中名为 gossh-<命令> 的可执行文件",
+	"cli.help.plugin":                  "运行插件，指定的连接以 JSON 形式传入 stdin",
+	"cli.help.forwarding":              "端口转发：",
+	"cli.help.forward.local":           "-L（本地转发）：将远程端口映射到本地\n  在本机监听 <local-port>，流量经 SSH 服务器转发到\n  <remote-host>:<remote-port>。\n  将 <remote-host> 设为 \"localhost\" 可访问服务器自身的端口。",
+	"cli.help.forward.remote":          "-R（远程转发）：将本地端口映射到远程\n  在 SSH 服务器上监听 <remote-port>，流量转发回本机的\n  <local-host>:<local-port>。\n  将 <local-host> 设为 \"localhost\" 可暴露本机自身的端口。",
+	"cli.help.forward.dynamic":         "-D（动态转发）：SOCKS 代理\n  在本机 <local-port> 上提供 SOCKS4/5 代理，每个连接都由\n  SSH 服务器连接到客户端请求的地址。",
+	"cli.help.examples":                "示例：",
+	"cli.help.example.mysql":           "通过本地 3306 端口访问远程服务器的 MySQL（3306 端口）",
+	"cli.help.example.expose":          "将本地 Web 服务（80 端口）暴露为远程服务器的 8080 端口",
+	"cli.help.navigation":              "TUI 导航：",
+	"cli.help.config":                  "配置文件位置：",
+	"cli.help.config.env":              "设置 GOSSH_CONFIG_DIR 可移动整个目录，或用 --config <path> 指定单个文件",
+	"cli.usage.connect":                "用法：gossh connect <name> [--share=<addr|unix:path|file>] [--scrollback] [-- <command...>]",
+	"cli.usage.sftp":                   "用法：gossh sftp <name> [--parallel=<n>] [--preserve[=links,owner,times]] [--trash] [--batch <file>] [-e <commands>]",
+	"cli.usage.import":                 "用法：gossh import <file> [--merge [--dry-run]] 或 gossh import --ssh-config [path]",
+	"cli.usage.rm":                     "用法：gossh rm <name> [--force] [--purge]",
+	"cli.usage.trash":                  "用法：gossh trash [list | restore <name> | purge <name> | empty]",
+	"cli.usage.audit":                  "用法：gossh audit-credentials [--max-age=<days>] [--group=<group>] [--all]",
+	"cli.usage.monitor":                "用法：gossh monitor [--listen=<addr>] [--interval=<seconds>] [--timeout=<seconds>] [--group=<group>] [--tags=<tags>] [--names=<names>] [--match=<regex>]",
+	"cli.usage.facts":                  "用法：gossh facts [--group=<group>] [--tags=<tags>] [--names=<names>] [--match=<regex>] [--exclude-*=...] [--timeout=<seconds>]",
+	"cli.usage.print_ssh_config":       "用法：gossh print-ssh-config [--group=<group>] [--tags=<tags>] [--names=<names>] [--match=<regex>] [--exclude-*=...]",
+	"cli.usage.stdio":                  "用法：gossh stdio <name> [<host>:<port>]",
+	"cli.usage.probe":                  "用法：gossh probe <name> --ports=<ports> [--host=<host>] [--timeout=<seconds>]",
+	"cli.usage.agent":                  "用法：gossh agent [list | pin <name> <fingerprint|comment> | unpin <name>]",
+	"cli.usage.report":                 "用法：gossh report [--format=md|html] [--output=<file>] [--max-age=<days>] [--group=<group>] [--tags=<tags>] [--names=<names>] [--match=<regex>] [--exclude-*=...]",
+	"cli.usage.rename":                 "用法：gossh rename <old> <new>",
+	"cli.usage.dedupe":                 "用法：gossh dedupe [--dry-run] [--yes]",
+	"cli.usage.history":                "用法：gossh history <name> [<n> [--restore]]",
+	"cli.usage.workspace":              "用法：gossh workspace [list | save <name> [<connection>...] [-L|-R <connection>=<spec>]... [--snippet <name>=<command>]... | open <name> | run <name> <snippet> | rm <name>]",
+	"cli.usage.schedule":               "用法：gossh schedule [list | add <cron> [--group=...|--tags=...|--names=...|--match=...]... [--timeout=<seconds>] <command> | rm <id> | daemon]",
+	"cli.usage.config":                 "用法：--config <path>",
+	"cli.usage.forward":                "用法：gossh forward <name|glob> [--match=<regex>] [-L/-R/-D <spec>]... [--save=<profile>] [--hint=<command>] [--profile=<profile>]... [--all-profiles] [--gateway] [--copy] [--list]\n示例：gossh forward myserver -L 8080:localhost:80 -D 1080",
+	"cli.usage.exec":                   "用法：gossh exec <command> [--group=<group>] [--tags=<tags>] [--names=<names>] [--match=<regex>] [--exclude-*=...] [--timeout=<seconds>] [--deadline=<seconds>] [--copy] [--diff] [--output=<text|json|junit|markdown>] [--retry-last | --retry-failed=<run-id>]",
+	"cli.usage.play":                   "用法：gossh play <playbook.yaml> [--dry-run] [--yes]",
+	"cli.usage.push":                   "用法：gossh push <local-file> <remote-path> [--group=<group>] [--tags=<tags>] [--names=<names>] [--match=<regex>] [--exclude-*=...] [--mode=<octal>] [--owner=<user[:group]>] [--preserve-times] [--run=<command>] [--parallel=<n>] [--timeout=<seconds>]",
+	"cli.usage.tail":                   "用法：gossh tail <remote-file> [--group=<group>] [--tags=<tags>] [--names=<names>] [--match=<regex>] [--exclude-*=...] [--grep=<regex>] [--lines=<n>]",
+	"cli.usage.watch":                  "用法：gossh watch <name> <local-dir> <remote-dir> [--exclude=<glob>[,<glob>...]] [--delete] [--debounce=<ms>]",
+	"cli.usage.archive":                "用法：gossh archive <name> <remote-dir> <local-file|->",
+	"cli.usage.extract":                "用法：gossh extract <name> <local-file|-> <remote-dir>",
+	"cli.error.not_found":              "未找到连接 '%s'",
+	"cli.error.telnet":                 "'%s' 是 telnet 连接，此操作需要 SSH",
+	"cli.error.no_command":             "未指定命令",
+	"cli.error.no_match":               "没有匹配的连接",
+	"cli.error.first_run":              "首次运行：请先在 TUI 模式下完成设置",
+	"cli.error.match_none":             "没有连接匹配 '%s'",
+	"cli.error.match_many":             "模式匹配到 %d 个连接（%s），请缩小到一个",
+	"cli.error.forward_type":           "无效的转发类型：%s（请使用 -L、-R 或 -D）",
+	"cli.password.prompt":              "请输入主密码：",
+	"cli.confirm.continue":             "是否继续？[y/N]：",
+	"cli.confirm.proceed":              "是否执行？[Y/n]：",
+	"cli.aborted":                      "已取消。",
+	"cli.warning":                      "警告：%v",
+	"cli.key_cache.failed":             "警告：未能记住主密钥：%v",
+	"cli.no_connections":               "没有找到连接。",
+	"cli.export.done":                  "已导出 %d 个连接到 %s",
+	"cli.export.insecure_dir":          "拒绝导出密码：%v\n如仍要写入该位置，请使用 --force",
+	"cli.warning.key_mode":             "警告：%v",
+	"cli.import.overwrite":             "是否覆盖同名的现有连接？[y/N]：",
+	"cli.import.done":                  "已导入 %d 个连接（来自 %s）",
+	"cli.help.import.merge":            "逐字段合并到匹配的连接，而不是询问是否覆盖",
+	"cli.help.import.dry_run":          "与 --merge 一起使用，只显示变更而不保存",
+	"cli.import.merge.kept":            "保留，文件中没有该值",
+	"cli.import.merge.summary":         "新增 %d 个，有差异 %d 个，无变化 %d 个",
+	"cli.import.merge.done":            "已从 %[3]s 新增 %[1]d 个并合并 %[2]d 个连接",
+	"cli.import.ssh.none":              "SSH 配置中没有找到连接。",
+	"cli.import.ssh.found":             "在 SSH 配置中找到 %d 个连接。",
+	"cli.import.ssh.exists":            "全部 %d 个连接已存在，无需导入。",
+	"cli.import.ssh.pending":           "将导入 %d 个新连接（%d 个重复项已跳过）。",
+	"cli.import.ssh.cancelled":         "已取消导入。",
+	"cli.import.ssh.add_failed":        "警告：添加 %s 失败：%v",
+	"cli.import.ssh.done":              "成功导入 %d 个连接。",
+	"cli.import.ssh.key":               "密钥",
+	"cli.import.ssh.action":            "操作",
+	"cli.import.ssh.action.add":        "导入",
+	"cli.import.ssh.action.skip":       "跳过（已存在）",
+	"cli.import.ssh.dry_run":           "试运行：未做任何更改。",
+	"cli.help.import_ssh.dry_run":      "仅预览导入结果，不保存",
+	"cli.help.print_ssh_config":        "输出通过 gossh stdio 连接的 OpenSSH Host 配置，供 ssh、scp 和 IDE 使用",
+	"cli.help.print_ssh_config.filter": "仅输出匹配的连接",
+	"cli.help.stdio":                   "将标准输入输出接到连接的 SSH 端口，经由其跳板机（用作 ProxyCommand）",
+	"cli.help.stdio.addr":              "改为登录该主机后从主机连接此地址（:<port> 表示主机本身）",
+	"cli.help.agent":                   "列出 ssh-agent 中的密钥及固定使用各密钥的连接",
+	"cli.help.agent.pin":               "连接只使用此 agent 密钥登录，无需密钥文件",
+	"cli.help.agent.key":               "密钥的指纹（SHA256:...）或注释，与 gossh agent 列出的一致",
+	"cli.help.agent.unpin":             "重新提供 agent 中的所有密钥",
+	"cli.help.probe":                   "检查主机能连通哪些端口，无需打开 shell",
+	"cli.help.probe.ports":             "要检查的端口和端口范围",
+	"cli.help.probe.host":              "从主机检查此主机的端口，而非主机自身（默认 localhost）",
+	"cli.help.probe.timeout":           "端口应答的等待时间（默认 5 秒）",
+	"cli.help.discover":                "导入 Tailscale 节点到 Tailnet 分组（再次运行即可刷新）",
+	"cli.help.discover.socket":         "tailscaled 套接字（默认：/var/run/tailscale/tailscaled.sock）",
+	"cli.help.discover.user":           "新节点的登录用户（默认：当前用户名）",
+	"cli.help.discover.key":            "新节点使用的私钥（默认：密码或 Tailscale SSH）",
+	"cli.help.discover.dry_run":        "预览更改而不保存",
+	"cli.usage.discover":               "用法：gossh discover tailscale [--socket=<path>] [--user=<name>] [--key=<path>] [--dry-run]",
+	"cli.discover.none":                "tailnet 中未找到节点。",
+	"cli.discover.found":               "在 tailnet 中找到 %d 个节点。",
+	"cli.discover.tags":                "标签",
+	"cli.discover.action.add":          "添加",
+	"cli.discover.action.update":       "更新",
+	"cli.discover.action.keep":         "无变化",
+	"cli.discover.pending":             "将添加 %d 个、更新 %d 个连接（跳过 %d 个：名称已被 Tailnet 分组外的连接使用）。",
+	"cli.discover.up_to_date":          "Tailnet 连接已是最新。",
+	"cli.discover.done":                "已保存 %d 个连接。",
+	"cli.check.no_match":               "没有符合筛选条件的连接。",
+	"cli.check.checking":               "正在检查 %d 个连接...",
+	"cli.check.reachable":              "可连接",
+	"cli.probe.probing":                "正在从 %[3]s 探测 %[2]s 的 %[1]d 个端口...",
+	"cli.probe.open":                   "开放",
+	"cli.probe.closed":                 "关闭",
+	"cli.probe.timeout":                "超时",
+	"cli.probe.forbidden":              "被禁止",
+	"cli.probe.no_answer":              "%s 内无应答",
+	"cli.probe.summary":                "%d/%d 个端口开放",
+	"cli.agent.empty":                  "agent 中没有密钥，请用 ssh-add 添加。",
+	"cli.agent.pinned_by":              "固定使用的连接：%s",
+	"cli.agent.pinned":                 "已将 %s 固定为使用 %s（%s）",
+	"cli.agent.unpinned":               "%s 重新提供 agent 中的所有密钥",
+	"cli.agent.not_pinned":             "%s 未固定 agent 密钥",
+	"cli.agent.no_key_file":            "%s 没有密钥文件：请设置密钥文件，或固定其他 agent 密钥",
+	"cli.agent.key_not_found":          "agent 中没有指纹或注释为 %q 的密钥",
+	"cli.agent.key_ambiguous":          "agent 中有多个注释为 %q 的密钥：请使用指纹",
+	"cli.facts.gathering":              "正在从 %d 个连接收集系统信息...",
+	"cli.list.name":                    "名称",
+	"cli.list.host":                    "主机",
+	"cli.list.port":                    "端口",
+	"cli.list.group":                   "分组",
+	"cli.list.total":                   "共 %d 个连接",
+	"cli.rm.confirm":                   "将连接 '%s' (%s@%s:%d) 移到回收站？[y/N]：",
+	"cli.rm.done":                      "已将连接 '%s' 移到回收站（恢复：gossh trash restore %[1]s）",
+	"cli.rm.confirm_purge":             "永久删除连接 '%s' (%s@%s:%d)？[y/N]：",
+	"cli.rm.purged":                    "已永久删除连接 '%s'",
+	"cli.trash.empty":                  "回收站为空",
+	"cli.trash.deleted":                "删除时间",
+	"cli.trash.expires":                "清除日期",
+	"cli.trash.not_found":              "回收站中未找到连接 '%s'",
+	"cli.trash.restored":               "已恢复连接 '%s'",
+	"cli.trash.emptied":                "已永久删除 %d 个连接",
+	"cli.audit.changed":                "修改时间",
+	"cli.audit.age":                    "时长",
+	"cli.audit.due":                    "到期",
+	"cli.audit.status":                 "状态",
+	"cli.audit.state.ok":               "正常",
+	"cli.audit.state.rotate":           "需轮换",
+	"cli.audit.state.expired":          "已过期",
+	"cli.audit.summary":                "%d / %d 个连接需要处理",
+	"cli.audit.failed":                 "凭据审计未通过：%d 个连接需要处理",
+	"cli.validate.problem":             "问题",
+	"cli.validate.ok":                  "全部 %d 个连接均有效",
+	"cli.validate.summary":             "%d 个问题，涉及 %d/%d 个连接",
+	"cli.validate.failed":              "校验未通过：发现 %d 个问题",
+	"cli.monitor.start":                "每 %[2]s 探测 %[1]d 个连接，指标地址 %[3]s/metrics（Ctrl+C 停止）",
+	"cli.hook.failed":                  "警告：%v",
+	"cli.rename.done":                  "已将连接 '%s' 重命名为 '%s'",
+	"cli.lock.done":                    "已锁定：正在运行的 gossh 将重新要求输入主密码",
+	"cli.lock.unprotected":             "无需锁定：未设置主密码（可在设置中启用）",
+	"cli.remember.done":                "主密钥已保存到系统密钥环：本机上的命令无需密码即可解锁。运行 'gossh forget' 可撤销。",
+	"cli.plugins.none":                 "%s 中没有插件。插件是名为 %s<命令> 的可执行文件。",
+	"cli.plugin.failed":                "插件 %s：%v",
+	"cli.remember.unprotected":         "无需记住：未设置主密码",
+	"cli.forget.done":                  "已从系统密钥环中删除主密钥",
+	"cli.dedupe.none":                  "没有发现重复的连接",
+	"cli.dedupe.set":                   "重复组 %d/%d：%s",
+	"cli.dedupe.never":                 "从未",
+	"cli.dedupe.sessions":              "%d 次会话",
+	"cli.dedupe.confirm":               "合并到 %s（标记 *）？[Y/n]：",
+	"cli.dedupe.merged":                "已将 %d 个连接合并到 %s",
+	"cli.dedupe.done":                  "已合并 %d 个重复项；清空回收站前它们仍保留在回收站中",
+	"cli.history.empty":                "'%s' 还没有修改记录",
+	"cli.history.when":                 "时间",
+	"cli.history.by":                   "修改人",
+	"cli.history.fields":               "修改的字段",
+	"cli.history.not_found":            "'%[2]s' 没有第 %[1]s 次修改；运行 gossh history <name> 查看列表",
+	"cli.history.edit":                 "修改 %d，%s，由 %s：",
+	"cli.history.restored":             "已将 '%s' 恢复到修改 %d 之前的版本",
+	"cli.workspace.empty":              "还没有保存的工作区。用 gossh workspace save <name> ... 保存",
+	"cli.workspace.saved":              "已保存工作区 '%s'",
+	"cli.workspace.removed":            "已删除工作区 '%s'",
+	"cli.workspace.not_found":          "未找到工作区 '%s'",
+	"cli.workspace.no_snippet":         "工作区 '%[2]s' 中没有代码片段 '%[1]s'",
+	"cli.workspace.sessions":           "会话：",
+	"cli.workspace.tunnel":             "隧道：",
+	"cli.workspace.snippet":            "代码片段：",
+	"cli.workspace.window":             "已在新的 tmux 窗口中打开 %s",
+	"cli.workspace.session":            "会话 %d/%d：%s（%s@%s:%d）",
+	"cli.workspace.tunnels_active":     "隧道已启动。按 Ctrl+C 停止。",
+	"cli.schedule.empty":               "还没有计划命令。用 gossh schedule add <cron> <command> 添加",
+	"cli.schedule.added":               "已添加计划命令 %s，下次运行于 %s",
+	"cli.schedule.removed":             "已删除计划命令 %s",
+	"cli.schedule.not_found":           "没有唯一 ID 为 %s 的计划命令",
+	"cli.schedule.hosts":               "主机：",
+	"cli.schedule.all_hosts":           "全部连接",
+	"cli.schedule.next":                "下次运行：",
+	"cli.schedule.last":                "上次运行：",
+	"cli.schedule.last_run":            "%s，%d 个失败",
+	"cli.schedule.never":               "从未运行",
+	"cli.schedule.daemon":              "将在到期时运行 %d 个计划命令。按 Ctrl+C 停止。",
+	"cli.schedule.stopping":            "正在停止，等待运行中的命令……",
+	"cli.schedule.ran":                 "%d 个成功，%d 个失败（运行 %s）",
+	"cli.connect.connecting":           "正在连接 %s (%s@%s:%d)...",
+	"cli.connect.share.socket":         "正在 %s 上只读共享会话输出，观看方式：%s",
+	"cli.connect.share.file":           "正在将会话输出镜像到 %s",
+	"cli.sftp.starting":                "正在启动到 %s (%s@%s:%d) 的 SFTP 会话...",
+	"cli.sftp.connected":               "已连接。输入 'help' 查看可用命令。",
+	"cli.sftp.local_dir":               "本地文件从 %s 读取并保存到该目录",
+	"cli.sftp.remote_dir_failed":       "无法打开 %s，仍在主目录：%v",
+	"cli.sftp.commands":                "命令：",
+	"cli.sftp.cmd.ls":                  "列出目录",
+	"cli.sftp.cmd.cd":                  "切换目录",
+	"cli.sftp.cmd.pwd":                 "显示当前目录",
+	"cli.sftp.cmd.copy":                "将远程绝对路径复制到剪贴板",
+	"cli.sftp.copied":                  "已复制 %s 到剪贴板",
+	"cli.sftp.notify.failed":           "%s 传输失败",
+	"cli.sftp.cmd.get":                 "下载文件，加 -r 下载目录",
+	"cli.sftp.cmd.put":                 "上传文件，加 -r 上传目录",
+	"cli.sftp.cmd.mkdir":               "创建目录",
+	"cli.sftp.cmd.rm":                  "删除文件，-f 不询问",
+	"cli.sftp.cmd.rmdir":               "删除目录，-f 不询问",
+	"cli.sftp.cmd.empty_trash":         "永久删除 ~/.gossh-trash 中的内容",
+	"cli.sftp.cmd.chmod":               "修改权限，例如 644 或 u+x；只给路径时显示权限",
+	"cli.sftp.cmd.chown":               "修改所有者和组，可用名称或数字",
+	"cli.sftp.cmd.local":               "运行本地命令，只输入 ! 则打开本地 shell",
+	"cli.sftp.cmd.du":                  "显示目录中占用空间的内容",
+	"cli.sftp.cmd.queue":               "将下载或上传加入后台队列",
+	"cli.sftp.cmd.jobs":                "列出排队的传输及其进度",
+	"cli.sftp.cmd.pause":               "暂停一个排队的传输，或全部暂停",
+	"cli.sftp.cmd.resume":              "继续一个已暂停的传输，或全部继续",
+	"cli.sftp.cmd.cancel":              "取消一个排队的传输，或全部取消",
+	"cli.sftp.cmd.exit":                "退出 SFTP",
+	"cli.sftp.usage":                   "用法：%s",
+	"cli.sftp.downloaded":              "已下载 %s -> %s",
+	"cli.sftp.uploaded":                "已上传 %s -> %s",
+	"cli.sftp.mkdir_done":              "已创建目录 %s",
+	"cli.sftp.rm_done":                 "已删除 %s",
+	"cli.sftp.rmdir_done":              "已删除目录 %s",
+	"cli.sftp.confirm.delete":          "删除 %s",
+	"cli.sftp.confirm.delete_dir":      "删除 %s（含 %d 个文件和 %d 个子目录）",
+	"cli.sftp.confirm.ask":             "%s？[y/N]：",
+	"cli.sftp.confirm.trash":           "%s（移到回收站）？[y/N]：",
+	"cli.sftp.confirm.empty_trash":     "永久删除 %[2]s 中的 %[1]d 项？[y/N]：",
+	"cli.sftp.trashed":                 "已将 %s 移到 %s",
+	"cli.sftp.trash.empty":             "回收站是空的",
+	"cli.sftp.trash.emptied":           "已从回收站删除 %d 项",
+	"cli.sftp.chown_done":              "已将 %s 的所有者改为 %s",
+	"cli.sftp.changed":                 "已修改 %[2]s 下的 %[1]d 项",
+	"cli.sftp.local_exit":              "本地命令退出，状态码 %d",
+	"cli.sftp.perm.read":               "读",
+	"cli.sftp.perm.write":              "写",
+	"cli.sftp.perm.execute":            "执行",
+	"cli.sftp.perm.owner":              "所有者",
+	"cli.sftp.perm.group":              "组",
+	"cli.sftp.perm.other":              "其他",
+	"cli.sftp.du.computing":            "正在计算 %s 中的大小...",
+	"cli.sftp.du.more":                 "... 另有 %d 项，共 %s",
+	"cli.sftp.du.total":                "总计：%[2]d 项共 %[1]s",
+	"cli.sftp.queue.added":             "已加入队列 #%d：%s %s",
+	"cli.sftp.queue.empty":             "队列中没有传输",
+	"cli.sftp.queue.no_transfer":       "没有未完成的传输 %s",
+	"cli.sftp.queue.drained":           "传输队列已完成：%d 成功，%d 失败，%d 已取消（%s，用时 %s）",
+	"cli.sftp.queue.closing":           "正在取消 %d 个未完成的传输...",
+	"cli.sftp.goodbye":                 "再见！",
+	"cli.sftp.unknown":                 "未知命令：%s。输入 'help' 查看可用命令。",
+	"cli.sftp.batch.no_confirm":        "否（批处理模式不会询问，请使用 -f）",
+	"cli.sftp.batch.stopped":           "已停止：%s 失败",
+	"cli.sftp.batch.queue_failed":      "%d 个排队的传输失败",
+	"cli.forward.setup":                "正在设置到 %s (%s@%s:%d) 的端口转发...",
+	"cli.forward.active":               "端口转发已启动，按 Ctrl+C 停止。",
+	"cli.forward.stopping":             "正在停止端口转发...",
+	"cli.forward.saved":                "已为 %[3]s 保存转发配置 %[1]s（%[2]d 条转发）",
+	"cli.forward.save_nothing":         "--save 需要要保存的 -L、-R 或 -D 转发",
+	"cli.forward.no_profiles":          "%s 没有保存的转发配置",
+	"cli.forward.no_profile":           "%[2]s 没有名为 %[1]s 的转发配置",
+	"cli.forward.gateway_needed":       "%s 将监听服务器的所有网络接口，其他主机都能访问；如需允许请加上 --gateway",
+	"cli.forward.assigned":             "远程端口：%[2]s 上的 %[1]d",
+	"cli.forward.local_port":           "本地端口：%d",
+	"cli.forward.moved":                "端口 %d 已被占用，改为监听 %d",
+	"cli.forward.hint":                 "连接方式：",
+	"cli.forward.hint_copied":          "已复制到剪贴板",
+	"cli.forward.no_hint":              "没有可复制的客户端命令，请用 --hint 保存",
+	"cli.forward.gateway":              "其他主机可通过 %s 访问（需服务器 sshd 设置 GatewayPorts clientspecified）",
+	"cli.forward.profile":              "配置",
+	"cli.forward.rule":                 "转发",
+	"cli.forward.connections":          "当前 / 总计",
+	"cli.forward.traffic":              "流量",
+	"cli.exec.targets":                 "将在 %d 台服务器上执行命令：",
+	"cli.exec.command":                 "命令：%s",
+	"cli.exec.timeout":                 "每台主机超时：%v",
+	"cli.exec.deadline":                "总时限：%v",
+	"cli.exec.pty":                     "伪终端：%dx%d",
+	"cli.exec.env":                     "环境变量：%s",
+	"cli.exec.diff_output":             "--diff 只能用于文本输出",
+	"cli.exec.interrupted":             "已中断：远程命令已停止。部分结果：",
+	"cli.exec.copied":                  "输出已复制到剪贴板",
+	"cli.exec.notify":                  "gossh exec 已完成：%d 个成功，%d 个失败",
+	"cli.play.plan":                    "Playbook %s：%d 个步骤",
+	"cli.play.hosts":                   "在 %d 台主机上：%s",
+	"cli.play.no_hosts":                "步骤 %d（%s）没有匹配的连接",
+	"cli.play.skipped":                 "已跳过，条件在所有主机上均不成立",
+	"cli.play.skipped_hosts":           "在 %d 台条件不成立的主机上跳过",
+	"cli.play.continuing":              "在 %d 台主机上失败，继续执行",
+	"cli.play.failed":                  "Playbook %s 已停止：步骤 %d（%s）在 %d 台主机上失败",
+	"cli.play.done":                    "Playbook %s 已完成：%d 个步骤，容忍 %d 次失败",
+	"cli.exec.retry_hint":              "%d 台主机失败。重试：gossh exec --retry-last（运行 %s）",
+	"cli.exec.retrying":                "正在重试运行 %s（%s）：%d/%d 台主机失败",
+	"cli.exec.no_run":                  "没有可重试的运行 '%s'",
+	"cli.exec.no_last_run":             "没有可重试的先前运行",
+	"cli.exec.nothing_to_retry":        "运行 %s 没有失败的主机",
+	"cli.push.targets":                 "正在将 %s 推送到 %s，共 %d 台主机：",
+	"cli.push.summary":                 "gossh push 已完成：%d 个成功，%d 个失败",
+	"cli.push.invalid_mode":            "无效的权限 %q，请使用八进制，例如 0644",
+	"cli.push.not_file":                "%s 是目录，push 只能上传单个文件",
+	"cli.tail.following":               "正在跟踪 %s，共 %d 台主机，按 Ctrl+C 停止",
+	"cli.watch.watching":               "正在监视 %s 的改动并上传到 %s:%s，按 Ctrl+C 停止",
+	"cli.watch.stopped":                "已停止监视：上传 %d 个，失败 %d 个",
+	"cli.archive.starting":             "正在将 %s:%s 打包到 %s",
+	"cli.archive.done":                 "已将 %s 打包到 %s（%s，用时 %s）",
+	"cli.archive.failed":               "打包 %s 失败",
+	"cli.extract.starting":             "正在将 %s 解压到 %s:%s",
+	"cli.extract.done":                 "已将 %s 解压到 %s（%s，用时 %s）",
+	"cli.extract.failed":               "解压 %s 失败",
+
+	// Inventory report
+	"report.title":          "连接清单",
+	"report.summary":        "生成于 %s：共 %d 台主机，%d 台的凭据需要处理。",
+	"report.hosts":          "主机",
+	"report.groups":         "分组",
+	"report.tags":           "标签",
+	"report.name":           "名称",
+	"report.address":        "地址",
+	"report.group":          "分组",
+	"report.last_seen":      "上次连接",
+	"report.health":         "健康状态",
+	"report.health.success": "在线",
+	"report.health.failed":  "离线",
+	"report.health.unknown": "未知",
+	"report.auth":           "认证",
+	"report.changed":        "凭据修改时间",
+	"report.age":            "时长",
+	"report.credentials":    "凭据",
+	"report.system":         "系统",
+	"cli.report.done":       "已将 %d 台主机的报告写入 %s",
 }
@@ -0,0 +1,205 @@
+// Package jobs runs the long operations the interface starts in the
+// background. A job reports what it does as typed events, which the TUI
+// reads one at a time as messages, so nothing it starts blocks the screen.
+// The manager keeps track of the jobs running, to show and cancel them.
+//
+// Only work the TUI itself starts runs as a job: health checks, connection
+// tests and x runs. SFTP transfers have their own queue in the sftp
+// command, and port forwards live inside the terminal session that owns
+// the screen, so neither goes through here.
+package jobs
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"gossh/internal/ssh"
+)
+
+// Kind says what a job does
+type Kind string
+
+const (
+	KindHealth Kind = "health" // Health check of many connections
+	KindTest   Kind = "test"   // Test of one connection
+	KindExec   Kind = "exec"   // Command run on several hosts
+)
+
+// Event is something a job reports: one of the types below
+type Event interface {
+	event()
+}
+
+// HostChecked is the result of checking one connection
+type HostChecked struct {
+	Result ssh.HealthResult
+}
+
+// ExecProgress reports a host of a batch run starting or finishing
+type ExecProgress struct {
+	Progress ssh.BatchProgress
+}
+
+// Done is the last event of a job, with the error it ended with
+type Done struct {
+	Err error
+}
+
+func (HostChecked) event()  {}
+func (ExecProgress) event() {}
+func (Done) event()         {}
+
+// Func does the work of a job, reporting it with job.Emit. It should stop
+// when ctx is canceled.
+type Func func(ctx context.Context, job *Job) error
+
+// Job is an operation running in the background
+type Job struct {
+	id      int
+	kind    Kind
+	started time.Time
+	cancel  context.CancelFunc
+
+	mu     sync.Mutex
+	events []Event       // Emitted and not read yet
+	wake   chan struct{} // Signaled when events are added
+	closed bool          // Done has been emitted
+	done   int           // Units of work finished
+	total  int           // Units of work in all, 0 when not known
+}
+
+// ID returns the number the manager gave the job, unique while it runs
+func (j *Job) ID() int {
+	return j.id
+}
+
+// Kind returns what the job does
+func (j *Job) Kind() Kind {
+	return j.kind
+}
+
+// Started returns when the job started
+func (j *Job) Started() time.Time {
+	return j.started
+}
+
+// Progress returns how many units of work are done, and how many there are
+// in all or 0 when that isn't known
+func (j *Job) Progress() (done, total int) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.done, j.total
+}
+
+// SetProgress records how many units of work are done
+func (j *Job) SetProgress(done int) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.done = done
+}
+
+// Cancel asks the job to stop. Its events up to Done still arrive.
+func (j *Job) Cancel() {
+	j.cancel()
+}
+
+// Emit reports an event. It never blocks, so a job goes on while nobody
+// reads its events; they wait in order until read.
+func (j *Job) Emit(e Event) {
+	j.mu.Lock()
+	if j.closed {
+		j.mu.Unlock()
+		return
+	}
+	j.events = append(j.events, e)
+	if _, ok := e.(Done); ok {
+		j.closed = true
+	}
+	j.mu.Unlock()
+
+	select {
+	case j.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Next waits for the next event. It returns false once Done has been read.
+func (j *Job) Next() (Event, bool) {
+	for {
+		j.mu.Lock()
+		if len(j.events) > 0 {
+			e := j.events[0]
+			j.events = j.events[1:]
+			j.mu.Unlock()
+			return e, true
+		}
+		closed := j.closed
+		j.mu.Unlock()
+		if closed {
+			return nil, false
+		}
+		<-j.wake
+	}
+}
+
+// Manager starts jobs and keeps track of those running
+type Manager struct {
+	mu      sync.Mutex
+	nextID  int
+	running map[int]*Job
+}
+
+// NewManager creates a manager without jobs
+func NewManager() *Manager {
+	return &Manager{running: make(map[int]*Job)}
+}
+
+// Start runs fn as a job in the background. total is how many units of
+// work it has, for its progress, or 0 when that isn't known. The job emits
+// Done with what fn returned once it has finished.
+func (m *Manager) Start(kind Kind, total int, fn Func) *Job {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.mu.Lock()
+	m.nextID++
+	job := &Job{
+		id:      m.nextID,
+		kind:    kind,
+		started: time.Now(),
+		cancel:  cancel,
+		wake:    make(chan struct{}, 1),
+		total:   total,
+	}
+	m.running[job.id] = job
+	m.mu.Unlock()
+
+	go func() {
+		err := fn(ctx, job)
+		cancel()
+		m.mu.Lock()
+		delete(m.running, job.id)
+		m.mu.Unlock()
+		job.Emit(Done{Err: err})
+	}()
+	return job
+}
+
+// Running returns the jobs that have not finished, oldest first
+func (m *Manager) Running() []*Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	jobs := make([]*Job, 0, len(m.running))
+	for _, job := range m.running {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].id < jobs[k].id })
+	return jobs
+}
+
+// CancelAll asks every running job to stop
+func (m *Manager) CancelAll() {
+	for _, job := range m.Running() {
+		job.Cancel()
+	}
+}
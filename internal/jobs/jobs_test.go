@@ -0,0 +1,76 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gossh/internal/model"
+	"gossh/internal/ssh"
+)
+
+func TestJobEvents(t *testing.T) {
+	m := NewManager()
+	failed := errors.New("unreachable")
+	job := m.Start(KindHealth, 3, func(ctx context.Context, job *Job) error {
+		for i := 1; i <= 3; i++ {
+			job.SetProgress(i)
+			job.Emit(HostChecked{Result: ssh.HealthResult{Connection: model.Connection{Port: i}}})
+		}
+		return failed
+	})
+
+	// Nothing blocks the job while its events are not read
+	for _, want := range []int{1, 2, 3} {
+		e, ok := job.Next()
+		checked, isChecked := e.(HostChecked)
+		if !ok || !isChecked || checked.Result.Connection.Port != want {
+			t.Fatalf("event = %#v, %v, want the result of connection %d", e, ok, want)
+		}
+	}
+	e, ok := job.Next()
+	if done, isDone := e.(Done); !ok || !isDone || done.Err != failed {
+		t.Fatalf("last event = %#v, %v, want Done with the job's error", e, ok)
+	}
+	if e, ok := job.Next(); ok {
+		t.Errorf("event %#v after Done", e)
+	}
+	if done, total := job.Progress(); done != 3 || total != 3 {
+		t.Errorf("Progress() = %d, %d, want 3, 3", done, total)
+	}
+	if running := m.Running(); len(running) != 0 {
+		t.Errorf("Running() = %d jobs after the job finished", len(running))
+	}
+}
+
+func TestCancelAll(t *testing.T) {
+	m := NewManager()
+	start := func() *Job {
+		return m.Start(KindExec, 0, func(ctx context.Context, job *Job) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}
+	first, second := start(), start()
+	if running := m.Running(); len(running) != 2 || running[0] != first || running[1] != second {
+		t.Fatalf("Running() = %v, want both jobs oldest first", running)
+	}
+
+	m.CancelAll()
+	for _, job := range []*Job{first, second} {
+		finished := make(chan Event)
+		go func() {
+			e, _ := job.Next()
+			finished <- e
+		}()
+		select {
+		case e := <-finished:
+			if done, ok := e.(Done); !ok || !errors.Is(done.Err, context.Canceled) {
+				t.Errorf("job %d ended with %#v, want Done with context.Canceled", job.ID(), e)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("job %d did not stop when canceled", job.ID())
+		}
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package keycache
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detach starts the cache process in a session of its own, so it outlives
+// the command and the terminal that started it
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package keycache
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachedProcess is DETACHED_PROCESS: no console for the cache process
+const detachedProcess = 0x00000008
+
+// detach starts the cache process without a console and in a process group
+// of its own, so it outlives the command and the console that started it
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP | detachedProcess}
+}
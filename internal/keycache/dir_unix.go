@@ -0,0 +1,44 @@
+//go:build !windows
+
+package keycache
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// fallbackDir holds the sockets when XDG_RUNTIME_DIR is not set, one
+// directory per user in the shared temp dir
+func fallbackDir() string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("gossh-%d", os.Getuid()))
+}
+
+// checkDir makes sure dir is a directory of the user's own that other users
+// can't reach, not a symlink or a directory someone else made in its place
+func checkDir(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink != 0 || !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	if stat, ok := info.Sys().(*syscall.Stat_t); !ok || int(stat.Uid) != os.Getuid() {
+		return fmt.Errorf("%s is owned by another user", dir)
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return fmt.Errorf("%s is accessible by other users", dir)
+	}
+	return nil
+}
+
+// listenUnix listens on socket, created readable by the user alone so it
+// is never reachable by others, not even until a chmod
+func listenUnix(socket string) (net.Listener, error) {
+	umask := syscall.Umask(0177)
+	defer syscall.Umask(umask)
+	return net.Listen("unix", socket)
+}
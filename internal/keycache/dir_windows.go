@@ -0,0 +1,35 @@
+//go:build windows
+
+package keycache
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// fallbackDir holds the sockets when XDG_RUNTIME_DIR is not set. The temp
+// dir is in the user's profile on Windows, so it needs no user id, which
+// Windows doesn't have.
+func fallbackDir() string {
+	return filepath.Join(os.TempDir(), "gossh")
+}
+
+// checkDir makes sure dir is a real directory, not a symlink or junction.
+// Access is up to the ACLs the profile directory passes on.
+func checkDir(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&(os.ModeSymlink|os.ModeIrregular) != 0 || !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	return nil
+}
+
+// listenUnix listens on socket
+func listenUnix(socket string) (net.Listener, error) {
+	return net.Listen("unix", socket)
+}
@@ -0,0 +1,301 @@
+// Package keycache keeps the key derived from the master password in a
+// background process for a while, like sudo's timestamp, so CLI commands
+// run one after another ask for the password once. The process holds the
+// key encrypted under a key of its own that never leaves its memory, and
+// answers on a Unix socket only the user can reach.
+package keycache
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gossh/internal/crypto"
+)
+
+// AgentCommand is the hidden gossh subcommand that runs the cache process,
+// see Serve
+const AgentCommand = "__keycache"
+
+// ioTimeout bounds a request to the cache process
+const ioTimeout = 2 * time.Second
+
+// ErrNotCached is returned by Get when no key is cached for the config
+var ErrNotCached = errors.New("no cached key")
+
+// agentConfig is what Start hands the cache process on its stdin
+type agentConfig struct {
+	Socket string        `json:"socket"`
+	Key    []byte        `json:"key"`
+	Salt   string        `json:"salt"`
+	TTL    time.Duration `json:"ttl"`
+}
+
+type request struct {
+	Op   string `json:"op"` // "get" or "clear"
+	Salt string `json:"salt,omitempty"`
+}
+
+type response struct {
+	Key   []byte `json:"key,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// SocketPath returns the socket of the cache process for the config file at
+// configPath, in $XDG_RUNTIME_DIR or a directory of the temp dir only the
+// user can read
+func SocketPath(configPath string) (string, error) {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir != "" {
+		dir = filepath.Join(dir, "gossh")
+	} else {
+		dir = fallbackDir()
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	if err := checkDir(dir); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(configPath))
+	return filepath.Join(dir, "keycache-"+hex.EncodeToString(sum[:8])+".sock"), nil
+}
+
+// Start caches key, derived from the master password with salt, for the
+// config file at configPath in a new cache process, replacing the one
+// running. The key is forgotten once it goes unused for ttl.
+func Start(configPath string, key []byte, salt string, ttl time.Duration) error {
+	socket, err := SocketPath(configPath)
+	if err != nil {
+		return err
+	}
+	_ = Clear(configPath)
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(exe, AgentCommand)
+	detach(cmd)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start key cache: %w", err)
+	}
+	go cmd.Wait()
+
+	// The key goes through a pipe, never the command line or environment
+	err = json.NewEncoder(stdin).Encode(agentConfig{Socket: socket, Key: key, Salt: salt, TTL: ttl})
+	stdin.Close()
+	if err != nil {
+		return fmt.Errorf("failed to start key cache: %w", err)
+	}
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	if strings.TrimSpace(line) != "ready" {
+		if err == nil || err == io.EOF {
+			err = errors.New(strings.TrimSpace(line))
+		}
+		return fmt.Errorf("failed to start key cache: %w", err)
+	}
+	return nil
+}
+
+// Get returns the key cached for the config file at configPath, if it was
+// derived with salt. Each use restarts the cache's time to live.
+func Get(configPath, salt string) ([]byte, error) {
+	resp, err := call(configPath, request{Op: "get", Salt: salt})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return resp.Key, nil
+}
+
+// Clear forgets the key cached for the config file at configPath, if any
+func Clear(configPath string) error {
+	_, err := call(configPath, request{Op: "clear"})
+	if errors.Is(err, ErrNotCached) {
+		return nil
+	}
+	return err
+}
+
+// call sends req to the cache process and returns its response
+func call(configPath string, req request) (response, error) {
+	socket, err := SocketPath(configPath)
+	if err != nil {
+		return response{}, err
+	}
+	conn, err := net.DialTimeout("unix", socket, ioTimeout)
+	if err != nil {
+		return response{}, ErrNotCached
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(ioTimeout))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return response{}, err
+	}
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return response{}, err
+	}
+	return resp, nil
+}
+
+// Serve runs the cache process: it reads what Start sends from r, says
+// "ready" on w once it listens, then answers until the key goes unused for
+// its time to live or is cleared
+func Serve(r io.Reader, w io.Writer) error {
+	var cfg agentConfig
+	if err := json.NewDecoder(r).Decode(&cfg); err != nil {
+		fmt.Fprintln(w, err)
+		return err
+	}
+	a, err := newAgent(cfg.Key, cfg.Salt)
+	clear(cfg.Key)
+	if err != nil {
+		fmt.Fprintln(w, err)
+		return err
+	}
+
+	listener, err := listen(cfg.Socket)
+	if err != nil {
+		fmt.Fprintln(w, err)
+		return err
+	}
+	fmt.Fprintln(w, "ready")
+	return a.serve(listener, cfg.TTL)
+}
+
+// listen listens on socket, removing one left behind by a process that
+// didn't exit cleanly. The directory is checked again, as it may have been
+// replaced since the socket path was picked.
+func listen(socket string) (net.Listener, error) {
+	if err := checkDir(filepath.Dir(socket)); err != nil {
+		return nil, err
+	}
+	_ = os.Remove(socket)
+	listener, err := listenUnix(socket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", socket, err)
+	}
+	return listener, nil
+}
+
+// agent holds the cached key sealed under a key generated for the process
+type agent struct {
+	sealer *crypto.Encryptor
+	sealed string
+	salt   string
+}
+
+func newAgent(key []byte, salt string) (*agent, error) {
+	processKey, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	sealer, err := crypto.NewEncryptor(processKey)
+	clear(processKey)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := sealer.Encrypt(base64.StdEncoding.EncodeToString(key))
+	if err != nil {
+		return nil, err
+	}
+	return &agent{sealer: sealer, sealed: sealed, salt: salt}, nil
+}
+
+// serve answers requests on listener until the key goes unused for ttl
+// or is cleared
+func (a *agent) serve(listener net.Listener, ttl time.Duration) error {
+	defer listener.Close()
+
+	conns := make(chan net.Conn)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				close(conns)
+				return
+			}
+			conns <- conn
+		}
+	}()
+
+	expire := time.NewTimer(ttl)
+	defer expire.Stop()
+	for {
+		select {
+		case <-expire.C:
+			return nil
+		case conn, ok := <-conns:
+			if !ok {
+				return nil
+			}
+			used, cleared := a.handle(conn)
+			if cleared {
+				return nil
+			}
+			if used {
+				expire.Reset(ttl)
+			}
+		}
+	}
+}
+
+// handle answers one request, reporting whether the key was handed out or
+// cleared
+func (a *agent) handle(conn net.Conn) (used, cleared bool) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(ioTimeout))
+
+	var req request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		return false, false
+	}
+
+	var resp response
+	switch req.Op {
+	case "get":
+		if req.Salt != a.salt {
+			resp.Error = "the cached key is for another master password"
+			break
+		}
+		encoded, err := a.sealer.Decrypt(a.sealed)
+		if err == nil {
+			resp.Key, err = base64.StdEncoding.DecodeString(encoded)
+		}
+		if err != nil {
+			resp.Error = err.Error()
+			break
+		}
+		used = true
+	case "clear":
+		cleared = true
+	default:
+		resp.Error = fmt.Sprintf("unknown request %q", req.Op)
+	}
+	_ = json.NewEncoder(conn).Encode(resp)
+	return used, cleared
+}
@@ -0,0 +1,148 @@
+package keycache
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// serve runs a cache process for configPath in the test, as Start would in
+// a new one
+func serve(t *testing.T, configPath string, key []byte, salt string, ttl time.Duration) <-chan error {
+	t.Helper()
+	socket, err := SocketPath(configPath)
+	if err != nil {
+		t.Fatalf("SocketPath failed: %v", err)
+	}
+	var in bytes.Buffer
+	if err := json.NewEncoder(&in).Encode(agentConfig{Socket: socket, Key: key, Salt: salt, TTL: ttl}); err != nil {
+		t.Fatal(err)
+	}
+	r, w := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(&in, w)
+		w.Close()
+	}()
+	ready := make([]byte, len("ready\n"))
+	if _, err := io.ReadFull(r, ready); err != nil || string(ready) != "ready\n" {
+		t.Fatalf("Serve said %q, %v; want ready", ready, err)
+	}
+	go io.Copy(io.Discard, r)
+	return done
+}
+
+func TestCache(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	key := bytes.Repeat([]byte{7}, 32)
+
+	if _, err := Get(configPath, "salt"); !errors.Is(err, ErrNotCached) {
+		t.Fatalf("Get before caching error = %v, want %v", err, ErrNotCached)
+	}
+
+	done := serve(t, configPath, bytes.Clone(key), "salt", time.Minute)
+	got, err := Get(configPath, "salt")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if !bytes.Equal(got, key) {
+		t.Errorf("Get = %x, want %x", got, key)
+	}
+	if _, err := Get(configPath, "other salt"); err == nil {
+		t.Error("Get with another salt returned the key")
+	}
+	if _, err := Get(filepath.Join(t.TempDir(), "other.yaml"), "salt"); !errors.Is(err, ErrNotCached) {
+		t.Errorf("Get for another config error = %v, want %v", err, ErrNotCached)
+	}
+
+	if err := Clear(configPath); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Serve failed: %v", err)
+	}
+	if _, err := Get(configPath, "salt"); !errors.Is(err, ErrNotCached) {
+		t.Errorf("Get after Clear error = %v, want %v", err, ErrNotCached)
+	}
+	if err := Clear(configPath); err != nil {
+		t.Errorf("Clear without a cache failed: %v", err)
+	}
+}
+
+func TestCacheExpires(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	done := serve(t, configPath, bytes.Repeat([]byte{7}, 32), "salt", 50*time.Millisecond)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("cache still running after its time to live")
+	}
+	if _, err := Get(configPath, "salt"); !errors.Is(err, ErrNotCached) {
+		t.Errorf("Get after expiry error = %v, want %v", err, ErrNotCached)
+	}
+}
+
+func TestSocketPathPrivate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits don't apply on Windows")
+	}
+	dir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "gossh"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(filepath.Join(dir, "gossh"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SocketPath("config.yaml"); err == nil {
+		t.Error("SocketPath accepted a directory other users can read")
+	}
+}
+
+func TestSocketPathSymlink(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	target := filepath.Join(dir, "elsewhere")
+	if err := os.Mkdir(target, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, filepath.Join(dir, "gossh")); err != nil {
+		t.Skipf("cannot create symlinks: %v", err)
+	}
+	if _, err := SocketPath("config.yaml"); err == nil {
+		t.Error("SocketPath accepted a symlinked directory")
+	}
+}
+
+func TestSocketPrivate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits don't apply on Windows")
+	}
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	done := serve(t, "config.yaml", bytes.Repeat([]byte{1}, 32), "salt", time.Minute)
+	defer func() {
+		_ = Clear("config.yaml")
+		<-done
+	}()
+
+	socket, _ := SocketPath("config.yaml")
+	info, err := os.Stat(socket)
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm&0077 != 0 {
+		t.Errorf("socket mode = %v, want no access for other users", perm)
+	}
+}
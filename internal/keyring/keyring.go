@@ -0,0 +1,35 @@
+// Package keyring stores secrets in the operating system's keyring: the
+// macOS keychain, the Secret Service (GNOME Keyring, KWallet) through
+// secret-tool, or the Windows Credential Manager
+package keyring
+
+import "errors"
+
+// service names gossh's entries in the keyring
+const service = "gossh"
+
+var (
+	// ErrNotFound is returned for an account with no secret stored
+	ErrNotFound = errors.New("secret not found in keyring")
+	// ErrUnavailable is returned when the system has no usable keyring
+	ErrUnavailable = errors.New("no keyring available")
+)
+
+// Set stores secret for account, replacing the one stored before
+func Set(account, secret string) error {
+	return set(account, secret)
+}
+
+// Get returns the secret stored for account
+func Get(account string) (string, error) {
+	return get(account)
+}
+
+// Delete removes the secret stored for account. Deleting one that isn't
+// there is not an error.
+func Delete(account string) error {
+	if err := del(account); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return nil
+}
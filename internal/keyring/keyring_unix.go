@@ -0,0 +1,52 @@
+//go:build !darwin && !windows
+
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+func set(account, secret string) error {
+	// secret-tool reads the secret from stdin, never the command line
+	cmd := exec.Command("secret-tool", "store", "--label=gossh master key", "service", service, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return secretToolError(err, out)
+	}
+	return nil
+}
+
+func get(account string) (string, error) {
+	cmd := exec.Command("secret-tool", "lookup", "service", service, "account", account)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		// secret-tool says nothing and fails for a missing secret
+		if stderr.Len() == 0 && len(out) == 0 && !errors.Is(err, exec.ErrNotFound) {
+			return "", ErrNotFound
+		}
+		return "", secretToolError(err, []byte(stderr.String()))
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func del(account string) error {
+	if out, err := exec.Command("secret-tool", "clear", "service", service, "account", account).CombinedOutput(); err != nil {
+		return secretToolError(err, out)
+	}
+	return nil
+}
+
+func secretToolError(err error, out []byte) error {
+	if errors.Is(err, exec.ErrNotFound) {
+		return fmt.Errorf("%w: install secret-tool (libsecret-tools)", ErrUnavailable)
+	}
+	if msg := strings.TrimSpace(string(out)); msg != "" {
+		return fmt.Errorf("secret-tool: %s", msg)
+	}
+	return fmt.Errorf("secret-tool: %w", err)
+}
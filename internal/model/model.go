@@ -1,9 +1,23 @@
 package model
 
 import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+	"gossh/internal/crypto"
+	"gossh/internal/schedule"
 )
 
 // AuthType represents the authentication method
@@ -14,6 +28,14 @@ const (
 	AuthKey      AuthType = "key"
 )
 
+// ConnectionType is the protocol used to reach a host
+type ConnectionType string
+
+const (
+	ConnTypeSSH    ConnectionType = "ssh"
+	ConnTypeTelnet ConnectionType = "telnet" // For devices without SSH, e.g. serial console servers
+)
+
 // ConnStatus represents the connection status
 type ConnStatus string
 
@@ -25,26 +47,51 @@ const (
 
 // Connection represents an SSH connection configuration
 type Connection struct {
-	ID                     string     `yaml:"id"`
-	Name                   string     `yaml:"name"`
-	Host                   string     `yaml:"host"`
-	Port                   int        `yaml:"port"`
-	User                   string     `yaml:"user"`
-	AuthType               AuthType   `yaml:"auth_type"`
-	AuthMethod             AuthType   `yaml:"auth_method"` // Deprecated: use AuthType
-	Password               string     `yaml:"password,omitempty"`               // Plain text (for runtime use)
-	EncryptedPassword      string     `yaml:"encrypted_password,omitempty"`      // AES-256-GCM encrypted
-	KeyPath                string     `yaml:"key_path,omitempty"`
-	KeyPassword            string     `yaml:"key_password,omitempty"`            // Plain text (for runtime use)
-	EncryptedKeyPassphrase string     `yaml:"encrypted_key_passphrase,omitempty"` // AES-256-GCM encrypted
-	Group                  string     `yaml:"group,omitempty"`
-	Tags                   []string   `yaml:"tags,omitempty"`
-	StartupCommand         string     `yaml:"startup_command,omitempty"`
-	LastConnected          *time.Time `yaml:"last_connected,omitempty"`
-	LastStatus             ConnStatus `yaml:"last_status"`
-	HealthStatus           ConnStatus `yaml:"health_status,omitempty"` // For health check results
-	CreatedAt              time.Time  `yaml:"created_at"`
-	UpdatedAt              time.Time  `yaml:"updated_at"`
+	ID                     string           `yaml:"id"`
+	Name                   string           `yaml:"name"`
+	Aliases                []string         `yaml:"aliases,omitempty"` // Other names the connection answers to
+	Host                   string           `yaml:"host"`
+	Port                   int              `yaml:"port"`
+	Addresses              []string         `yaml:"addresses,omitempty"`    // Other addresses of the host, tried in order after Host
+	LastAddress            string           `yaml:"last_address,omitempty"` // Address the last session reached the host at
+	Type                   ConnectionType   `yaml:"type,omitempty"`         // Empty means SSH
+	BindAddress            string           `yaml:"bind_address,omitempty"` // Local IP or interface to connect from
+	Term                   string           `yaml:"term,omitempty"`         // TERM to request instead of the local one
+	Locale                 string           `yaml:"locale,omitempty"`       // LANG and LC_ALL to request, e.g. C.UTF-8
+	LocalDir               string           `yaml:"local_dir,omitempty"`    // Local directory SFTP transfers start in
+	RemoteDir              string           `yaml:"remote_dir,omitempty"`   // Remote directory SFTP sessions open in
+	User                   string           `yaml:"user"`
+	AuthType               AuthType         `yaml:"auth_type"`
+	Password               string           `yaml:"password,omitempty"`           // Plain text (for runtime use)
+	EncryptedPassword      string           `yaml:"encrypted_password,omitempty"` // AES-256-GCM encrypted
+	KeyPath                string           `yaml:"key_path,omitempty"`
+	KeyPassword            string           `yaml:"key_password,omitempty"`             // Plain text (for runtime use)
+	EncryptedKeyPassphrase string           `yaml:"encrypted_key_passphrase,omitempty"` // AES-256-GCM encrypted
+	AgentKey               string           `yaml:"agent_key,omitempty"`                // SHA256 fingerprint of the only ssh-agent key to offer
+	GSSAPI                 bool             `yaml:"gssapi,omitempty"`                   // Try Kerberos before the auth method, when a ticket is available
+	Group                  string           `yaml:"group,omitempty"`
+	Tags                   []string         `yaml:"tags,omitempty"`
+	StartupCommand         string           `yaml:"startup_command,omitempty"` // One command per line
+	PreConnect             string           `yaml:"pre_connect,omitempty"`     // Local command run before a session; failing stops the session
+	PostDisconnect         string           `yaml:"post_disconnect,omitempty"` // Local command run after a session ends
+	Notes                  string           `yaml:"notes,omitempty"`
+	QuietLogin             bool             `yaml:"quiet_login,omitempty"`      // Skip the MOTD and last login notice
+	ShowBanner             bool             `yaml:"show_banner,omitempty"`      // Show the server's pre-login banner before connecting
+	JumpHosts              []string         `yaml:"jump_hosts,omitempty"`       // Saved connections to hop through, first to last
+	ForwardProfiles        []ForwardProfile `yaml:"forward_profiles,omitempty"` // Sets of port forwards started together
+	Attachments            []Attachment     `yaml:"attachments,omitempty"`      // Encrypted files kept with the connection, see config.Manager.AddAttachment
+	Jumps                  []Connection     `yaml:"-"`                          // JumpHosts resolved by config.Manager
+	LastConnected          *time.Time       `yaml:"last_connected,omitempty"`
+	LastStatus             ConnStatus       `yaml:"last_status,omitempty"`
+	HealthStatus           ConnStatus       `yaml:"health_status,omitempty"` // For health check results
+	CreatedAt              time.Time        `yaml:"created_at"`
+	UpdatedAt              time.Time        `yaml:"updated_at"`
+	DeletedAt              *time.Time       `yaml:"deleted_at,omitempty"`            // Set while the connection is in the trash
+	PasswordRotateAfter    int              `yaml:"password_rotate_after,omitempty"` // Days before credentials should be rotated
+	PasswordChangedAt      *time.Time       `yaml:"password_changed_at,omitempty"`
+	ExpiresAt              *time.Time       `yaml:"expires_at,omitempty"` // Access end date, e.g. for contractors
+	History                []SessionRecord  `yaml:"history,omitempty"`    // Most recent sessions, newest last
+	Facts                  *HostFacts       `yaml:"facts,omitempty"`      // System info from the last session or gossh facts
 }
 
 // NewConnection creates a new connection with defaults
@@ -52,57 +99,967 @@ func NewConnection() Connection {
 	return Connection{
 		ID:         uuid.New().String(),
 		Port:       22,
-		AuthMethod: AuthPassword,
+		AuthType:   AuthPassword,
 		LastStatus: ConnStatusUnknown,
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
 }
 
-// Validate checks if the connection has required fields
+// UnmarshalYAML reads a connection, taking the auth type from the
+// auth_method key written by older versions when it is set, since that is
+// the one they authenticated with
+func (c *Connection) UnmarshalYAML(value *yaml.Node) error {
+	type plain Connection
+	if err := value.Decode((*plain)(c)); err != nil {
+		return err
+	}
+	var legacy struct {
+		AuthMethod AuthType `yaml:"auth_method"`
+	}
+	if err := value.Decode(&legacy); err != nil {
+		return err
+	}
+	if legacy.AuthMethod != "" {
+		c.AuthType = legacy.AuthMethod
+	}
+	return nil
+}
+
+// Validate checks if the connection has required fields and returns the
+// first problem found
 func (c *Connection) Validate() error {
+	if problems := c.Problems(); len(problems) > 0 {
+		return problems[0]
+	}
+	return nil
+}
+
+// Problems returns every problem Validate would report, in field order
+func (c *Connection) Problems() []error {
+	var problems []error
 	if c.Name == "" {
-		return ErrNameRequired
+		problems = append(problems, ErrNameRequired)
+	}
+	for _, alias := range c.Aliases {
+		if alias == "" || alias == c.Name || strings.ContainsFunc(alias, unicode.IsSpace) {
+			problems = append(problems, fmt.Errorf("%w: %q", ErrInvalidAlias, alias))
+			break
+		}
 	}
 	if c.Host == "" {
-		return ErrHostRequired
+		problems = append(problems, ErrHostRequired)
+	} else if !ValidHost(c.Host) {
+		problems = append(problems, ErrInvalidHost)
 	}
-	if c.User == "" {
-		return ErrUserRequired
+	for _, addr := range c.Addresses {
+		if !ValidHost(strings.TrimSpace(addr)) {
+			problems = append(problems, fmt.Errorf("%w: %s", ErrInvalidAddress, addr))
+		}
+	}
+	if c.User == "" && !c.IsTelnet() {
+		// Telnet devices prompt for the login themselves
+		problems = append(problems, ErrUserRequired)
 	}
 	if c.Port <= 0 || c.Port > 65535 {
-		return ErrInvalidPort
+		problems = append(problems, ErrInvalidPort)
+	}
+	if c.AuthType == AuthKey && c.KeyPath == "" && c.AgentKey == "" {
+		// A pinned agent key stands in for the key file
+		problems = append(problems, ErrKeyPathRequired)
+	}
+	if c.AgentKey != "" && !ValidFingerprint(c.AgentKey) {
+		problems = append(problems, ErrInvalidAgentKey)
+	}
+	if c.Term != "" && !ValidEnvValue(c.Term) {
+		problems = append(problems, ErrInvalidTerm)
+	}
+	if c.Locale != "" && !ValidEnvValue(c.Locale) {
+		problems = append(problems, ErrInvalidLocale)
+	}
+	if c.PasswordRotateAfter < 0 {
+		problems = append(problems, ErrInvalidRotation)
+	}
+	for _, p := range c.ForwardProfiles {
+		if err := p.Validate(); err != nil {
+			problems = append(problems, err)
+			break
+		}
+	}
+	return problems
+}
+
+// ForwardProfile is a named set of port forwards saved with a connection,
+// e.g. "db" for the database and its admin UI
+type ForwardProfile struct {
+	Name     string        `yaml:"name"`
+	Forwards []ForwardSpec `yaml:"forwards"`
+	Hint     string        `yaml:"hint,omitempty"` // Client command to use the forwards, see ExpandHint
+}
+
+// MaxAttachmentSize is the largest file that can be attached to a
+// connection: attachments are for keys, configs and notes, not backups
+const MaxAttachmentSize = 1 << 20
+
+// Attachment is a small file kept with a connection, e.g. a PEM, a VPN
+// config or a runbook snippet. Its content is stored encrypted in a file of
+// its own, with a key of its own that is stored encrypted like passwords.
+type Attachment struct {
+	ID           string    `yaml:"id"`
+	Name         string    `yaml:"name"`
+	Size         int       `yaml:"size"`
+	AddedAt      time.Time `yaml:"added_at"`
+	EncryptedKey string    `yaml:"encrypted_key"`
+}
+
+// ForwardSpec is one port forward of a profile
+type ForwardSpec struct {
+	Type string `yaml:"type"` // "local" (-L), "remote" (-R) or "dynamic" (-D)
+	Spec string `yaml:"spec"` // As given to ssh, e.g. 5432:localhost:5432 or 1080
+}
+
+// Validate checks that the profile has a name and complete forwards
+func (p ForwardProfile) Validate() error {
+	if p.Name == "" || strings.ContainsFunc(p.Name, unicode.IsSpace) || len(p.Forwards) == 0 {
+		return ErrInvalidForwardProfile
 	}
-	if c.AuthMethod == AuthKey && c.KeyPath == "" {
-		return ErrKeyPathRequired
+	for _, f := range p.Forwards {
+		if f.Spec == "" || (f.Type != "local" && f.Type != "remote" && f.Type != "dynamic") {
+			return ErrInvalidForwardProfile
+		}
 	}
 	return nil
 }
 
-// MatchesFilter checks if connection matches search filter
-func (c *Connection) MatchesFilter(filter string) bool {
-	if filter == "" {
-		return true
+// ExpandHint returns the profile's client command with the ports its
+// forwards listen on: {port} is the first forward's, {port2} the second's
+// and so on, e.g. "psql postgres://app@localhost:{port}/app"
+func (p ForwardProfile) ExpandHint(ports []int) string {
+	hint := p.Hint
+	// Backwards, so {port1} is not taken for {port12}
+	for i := len(ports); i >= 1; i-- {
+		hint = strings.ReplaceAll(hint, "{port"+strconv.Itoa(i)+"}", strconv.Itoa(ports[i-1]))
 	}
-	filter = toLower(filter)
-	if contains(toLower(c.Name), filter) {
-		return true
+	if len(ports) > 0 {
+		hint = strings.ReplaceAll(hint, "{port}", strconv.Itoa(ports[0]))
 	}
-	if contains(toLower(c.Host), filter) {
-		return true
+	return hint
+}
+
+// ForwardProfile returns the connection's forward profile with a name
+func (c *Connection) ForwardProfile(name string) (ForwardProfile, bool) {
+	for _, p := range c.ForwardProfiles {
+		if p.Name == name {
+			return p, true
+		}
 	}
-	if contains(toLower(c.User), filter) {
-		return true
+	return ForwardProfile{}, false
+}
+
+// SetForwardProfile adds a forward profile, replacing one with the same name
+func (c *Connection) SetForwardProfile(profile ForwardProfile) {
+	for i, p := range c.ForwardProfiles {
+		if p.Name == profile.Name {
+			c.ForwardProfiles[i] = profile
+			return
+		}
 	}
-	if contains(toLower(c.Group), filter) {
+	c.ForwardProfiles = append(c.ForwardProfiles, profile)
+}
+
+// ValidEnvValue reports whether s can be sent as a TERM or locale value:
+// printable ASCII without spaces, quotes or "="
+func ValidEnvValue(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r <= ' ' || r > '~' || strings.ContainsRune(`"'=`, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidFingerprint reports whether s is a SHA256 key fingerprint as
+// OpenSSH prints it: SHA256: and the hash in unpadded base64
+func ValidFingerprint(s string) bool {
+	hash, ok := strings.CutPrefix(s, "SHA256:")
+	if !ok {
+		return false
+	}
+	sum, err := base64.RawStdEncoding.DecodeString(hash)
+	return err == nil && len(sum) == sha256.Size
+}
+
+// LocaleEnv returns the locale variables to request for the session, none
+// when the connection doesn't override the locale
+func (c *Connection) LocaleEnv() map[string]string {
+	if c.Locale == "" {
+		return nil
+	}
+	return map[string]string{"LANG": c.Locale, "LC_ALL": c.Locale}
+}
+
+// ValidHost reports whether host is an IP address, a hostname made of
+// letters, digits, hyphens and underscores, or a service reference such as
+// srv:_ssh._tcp.example.com or consul:web
+func ValidHost(host string) bool {
+	for _, prefix := range []string{"srv:", "consul:"} {
+		if strings.HasPrefix(host, prefix) {
+			return validHostname(strings.TrimPrefix(host, prefix))
+		}
+	}
+	if _, err := netip.ParseAddr(host); err == nil {
 		return true
 	}
-	for _, tag := range c.Tags {
-		if contains(toLower(tag), filter) {
-			return true
+	return validHostname(host)
+}
+
+// validHostname checks a DNS name label by label, allowing a trailing dot
+func validHostname(name string) bool {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" || len(name) > 253 {
+		return false
+	}
+	numeric := true
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			switch {
+			case r >= '0' && r <= '9':
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '-', r == '_':
+				numeric = false
+			default:
+				return false
+			}
 		}
 	}
-	return false
+	// All-numeric names are malformed IPv4 addresses, e.g. 10.0.0.256
+	return !numeric
+}
+
+// ValidColor reports whether color is a hex color, #rgb or #rrggbb
+func ValidColor(color string) bool {
+	hex, ok := strings.CutPrefix(color, "#")
+	if !ok || len(hex) != 3 && len(hex) != 6 {
+		return false
+	}
+	for _, r := range hex {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Candidates returns the addresses to try, host:port each: Host first, then
+// the other addresses in order, without duplicates
+func (c *Connection) Candidates() []string {
+	seen := make(map[string]bool)
+	var addrs []string
+	for _, host := range append([]string{c.Host}, c.Addresses...) {
+		host = strings.TrimSpace(host)
+		if host == "" || seen[host] {
+			continue
+		}
+		seen[host] = true
+		addrs = append(addrs, net.JoinHostPort(host, strconv.Itoa(c.Port)))
+	}
+	return addrs
+}
+
+// Names returns the name of the connection followed by its aliases
+func (c *Connection) Names() []string {
+	return append([]string{c.Name}, c.Aliases...)
+}
+
+// HasName reports whether name is the name of the connection or one of its
+// aliases
+func (c *Connection) HasName(name string) bool {
+	return c.Name == name || slices.Contains(c.Aliases, name)
+}
+
+// IsTelnet reports whether the connection uses telnet instead of SSH
+func (c *Connection) IsTelnet() bool {
+	return c.Type == ConnTypeTelnet
+}
+
+// ResolveJumps looks up conn's jump hosts by name in all and returns the
+// full chain of hops, first to last. Jump hosts with jump hosts of their own
+// are expanded in place, so a bastion that is itself behind a bastion works.
+func ResolveJumps(conn Connection, all []Connection) ([]Connection, error) {
+	return resolveJumps(conn, all, map[string]bool{conn.ID: true})
+}
+
+func resolveJumps(conn Connection, all []Connection, path map[string]bool) ([]Connection, error) {
+	var hops []Connection
+	for _, name := range conn.JumpHosts {
+		var hop *Connection
+		for i := range all {
+			if strings.EqualFold(all[i].Name, name) {
+				hop = &all[i]
+				break
+			}
+		}
+		if hop == nil {
+			return nil, fmt.Errorf("%w: %s", ErrJumpNotFound, name)
+		}
+		if path[hop.ID] {
+			return nil, fmt.Errorf("%w: %s", ErrJumpLoop, name)
+		}
+
+		path[hop.ID] = true
+		via, err := resolveJumps(*hop, all, path)
+		delete(path, hop.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		resolved := *hop
+		resolved.Jumps = nil
+		hops = append(append(hops, via...), resolved)
+	}
+	return hops, nil
+}
+
+// DefaultPort returns the standard port for a connection type
+func DefaultPort(t ConnectionType) int {
+	if t == ConnTypeTelnet {
+		return 23
+	}
+	return 22
+}
+
+// CredentialState describes how fresh a connection's credentials are
+type CredentialState string
+
+const (
+	CredentialOK      CredentialState = "ok"
+	CredentialRotate  CredentialState = "rotate"  // Older than the rotation period
+	CredentialExpired CredentialState = "expired" // Past ExpiresAt
+)
+
+// CredentialsChangedAt returns when the credentials were last set,
+// falling back to the creation time for connections that predate tracking
+func (c *Connection) CredentialsChangedAt() time.Time {
+	if c.PasswordChangedAt != nil {
+		return *c.PasswordChangedAt
+	}
+	return c.CreatedAt
+}
+
+// RotationDue returns when the credentials should be rotated. The connection's
+// own PasswordRotateAfter wins over defaultDays; zero means no rotation policy.
+func (c *Connection) RotationDue(defaultDays int) time.Time {
+	days := c.PasswordRotateAfter
+	if days <= 0 {
+		days = defaultDays
+	}
+	if days <= 0 {
+		return time.Time{}
+	}
+	return c.CredentialsChangedAt().AddDate(0, 0, days)
+}
+
+// Credentials reports the credential state at the given time
+func (c *Connection) Credentials(now time.Time, defaultDays int) CredentialState {
+	if c.ExpiresAt != nil && !now.Before(*c.ExpiresAt) {
+		return CredentialExpired
+	}
+	if due := c.RotationDue(defaultDays); !due.IsZero() && !now.Before(due) {
+		return CredentialRotate
+	}
+	return CredentialOK
+}
+
+// IsStale returns true if the connection has expired or its credentials
+// are overdue for rotation under its own policy
+func (c *Connection) IsStale(now time.Time) bool {
+	return c.Credentials(now, 0) != CredentialOK
+}
+
+// SSHCommand returns the equivalent OpenSSH command line,
+// e.g. "ssh -p 2222 -i ~/.ssh/id_ed25519 user@host", or the telnet command
+// line for telnet connections
+func (c *Connection) SSHCommand() string {
+	if c.IsTelnet() {
+		return "telnet " + ShellQuote(c.Host) + " " + strconv.Itoa(c.Port)
+	}
+	var args []string
+	if c.Term != "" {
+		args = append(args, "TERM="+ShellQuote(c.Term))
+	}
+	args = append(args, "ssh")
+	if c.Port != 0 && c.Port != 22 {
+		args = append(args, "-p", strconv.Itoa(c.Port))
+	}
+	if c.AuthType == AuthKey && c.KeyPath != "" {
+		args = append(args, "-i", ShellQuote(c.KeyPath))
+	}
+	if c.GSSAPI {
+		args = append(args, "-o", "GSSAPIAuthentication=yes")
+	}
+	if c.Locale != "" {
+		args = append(args, "-o", ShellQuote("SetEnv=LANG="+c.Locale+" LC_ALL="+c.Locale))
+	}
+	if c.BindAddress != "" {
+		// OpenSSH takes an address with -b and an interface with -B
+		flag := "-B"
+		if net.ParseIP(c.BindAddress) != nil {
+			flag = "-b"
+		}
+		args = append(args, flag, ShellQuote(c.BindAddress))
+	}
+	if len(c.Jumps) > 0 {
+		jumps := make([]string, len(c.Jumps))
+		for i, hop := range c.Jumps {
+			jump := hop.Host
+			if hop.Port != 0 && hop.Port != 22 {
+				if strings.Contains(jump, ":") {
+					jump = "[" + jump + "]"
+				}
+				jump += ":" + strconv.Itoa(hop.Port)
+			}
+			if hop.User != "" {
+				jump = hop.User + "@" + jump
+			}
+			jumps[i] = jump
+		}
+		args = append(args, "-J", ShellQuote(strings.Join(jumps, ",")))
+	}
+	target := c.Host
+	if c.User != "" {
+		target = c.User + "@" + c.Host
+	}
+	return strings.Join(append(args, ShellQuote(target)), " ")
+}
+
+// ShellQuote quotes s for a POSIX shell when it contains special characters
+func ShellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("@%+=:,./-_~", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// MatchesFilter checks if connection matches search filter, a query as
+// described at ParseQuery
+func (c *Connection) MatchesFilter(filter string) bool {
+	doc := newSearchDoc(c)
+	return doc.matches(ParseQuery(filter))
+}
+
+// SearchFields lists the fields a search term can be scoped to with
+// field:value
+var SearchFields = []string{"name", "host", "user", "group", "tag", "port", "note"}
+
+// searchSep separates values in a search document, so a term never
+// matches across two of them
+const searchSep = "\x00"
+
+// SearchTerm is one word of a search query
+type SearchTerm struct {
+	Field  string // One of SearchFields, or empty to search all of them
+	Value  string // Lowercased
+	Negate bool   // Match the connections the term does not match
+}
+
+// ParseQuery splits a search query into terms, all of which must match.
+// A term is a word found in any field, or field:value to look in one
+// field, e.g. tag:nginx or port:2222 (ports match exactly, the rest as
+// substrings). A leading "-" negates a term and double quotes keep spaces
+// in a value, as in -group:"eu west". Words with an unknown field, like
+// fe80::1, are searched as they are.
+func ParseQuery(query string) []SearchTerm {
+	var terms []SearchTerm
+	for _, word := range splitQuery(toLower(query)) {
+		var term SearchTerm
+		if rest, ok := strings.CutPrefix(word, "-"); ok {
+			term.Negate = true
+			word = rest
+		}
+		if field, value, ok := strings.Cut(word, ":"); ok && slices.Contains(SearchFields, field) {
+			term.Field = field
+			word = value
+		}
+		// A term still being typed, like "tag:" or "-", matches everything
+		if word == "" {
+			continue
+		}
+		term.Value = word
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// splitQuery splits a query at spaces outside double quotes, dropping the
+// quotes
+func splitQuery(query string) []string {
+	var words []string
+	var word strings.Builder
+	quoted, inWord := false, false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inWord = true
+		case r == ' ' && !quoted:
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+			}
+			inWord = false
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+// searchDoc holds a connection's lowercased fields as search terms see them
+type searchDoc struct {
+	all                                  string // Every field, for terms without one
+	name, host, user, group, tags, notes string
+	port                                 string
+}
+
+// newSearchDoc builds the search document of c
+func newSearchDoc(c *Connection) searchDoc {
+	d := searchDoc{
+		name:  toLower(strings.Join(c.Names(), searchSep)),
+		host:  toLower(c.Host),
+		user:  toLower(c.User),
+		group: toLower(c.Group),
+		tags:  toLower(strings.Join(c.Tags, searchSep)),
+		notes: toLower(c.Notes),
+		port:  strconv.Itoa(c.Port),
+	}
+	d.all = strings.Join([]string{d.name, d.host, d.user, d.group, d.tags, d.notes}, searchSep)
+	return d
+}
+
+// matchesTerm reports whether the document matches a term, ignoring its
+// negation
+func (d *searchDoc) matchesTerm(t SearchTerm) bool {
+	switch t.Field {
+	case "name":
+		return strings.Contains(d.name, t.Value)
+	case "host":
+		return strings.Contains(d.host, t.Value)
+	case "user":
+		return strings.Contains(d.user, t.Value)
+	case "group":
+		return strings.Contains(d.group, t.Value)
+	case "tag":
+		return strings.Contains(d.tags, t.Value)
+	case "note":
+		return strings.Contains(d.notes, t.Value)
+	case "port":
+		return d.port == t.Value
+	}
+	return strings.Contains(d.all, t.Value)
+}
+
+// matches reports whether the document matches every term
+func (d *searchDoc) matches(terms []SearchTerm) bool {
+	for _, t := range terms {
+		if d.matchesTerm(t) == t.Negate {
+			return false
+		}
+	}
+	return true
+}
+
+// narrows reports whether every connection matching terms also matches
+// prev, as when the query has only been typed further
+func narrows(terms, prev []SearchTerm) bool {
+	if len(terms) < len(prev) {
+		return false
+	}
+	for i, p := range prev {
+		t := terms[i]
+		if t.Field != p.Field || t.Negate != p.Negate {
+			return false
+		}
+		// A longer substring matches fewer connections, unless negated
+		if t.Value != p.Value && (p.Negate || p.Field == "port" || !strings.Contains(t.Value, p.Value)) {
+			return false
+		}
+	}
+	return true
+}
+
+// SearchIndex keeps the lowercased search fields of each connection, so
+// filtering thousands of connections on every keystroke is a plain
+// substring scan
+type SearchIndex struct {
+	docs      []searchDoc
+	lastTerms []SearchTerm // Terms of the last Match and the connections it
+	lastHits  []int        // found, which narrow the scan while typing on
+}
+
+// NewSearchIndex indexes conns, which must not change while it is used
+func NewSearchIndex(conns []Connection) *SearchIndex {
+	docs := make([]searchDoc, len(conns))
+	for i := range conns {
+		docs[i] = newSearchDoc(&conns[i])
+	}
+	return &SearchIndex{docs: docs}
+}
+
+// Match returns the positions of the indexed connections that match query,
+// in order. It finds the same connections as MatchesFilter.
+func (x *SearchIndex) Match(query string) []int {
+	terms := ParseQuery(query)
+
+	hits := []int{}
+	if x.lastHits != nil && narrows(terms, x.lastTerms) {
+		for _, i := range x.lastHits {
+			if x.docs[i].matches(terms) {
+				hits = append(hits, i)
+			}
+		}
+	} else {
+		for i := range x.docs {
+			if x.docs[i].matches(terms) {
+				hits = append(hits, i)
+			}
+		}
+	}
+
+	x.lastTerms, x.lastHits = terms, hits
+	return hits
+}
+
+// MaxHistory is how many sessions are kept per connection
+const MaxHistory = 20
+
+// SessionRecord describes one finished session
+type SessionRecord struct {
+	StartedAt time.Time     `yaml:"started_at"`
+	Duration  time.Duration `yaml:"duration"`
+	ExitCode  int           `yaml:"exit_code"`
+	Command   string        `yaml:"command,omitempty"` // Empty for interactive shells
+	Address   string        `yaml:"address,omitempty"` // Address the host was reached at
+}
+
+// ConnectionState is what gossh learns about a connection by using it, as
+// opposed to how it is configured. It is saved in a state file of its own,
+// so recording a session does not rewrite the whole config.
+type ConnectionState struct {
+	LastConnected *time.Time      `yaml:"last_connected,omitempty"`
+	LastStatus    ConnStatus      `yaml:"last_status,omitempty"`
+	LastAddress   string          `yaml:"last_address,omitempty"`
+	HealthStatus  ConnStatus      `yaml:"health_status,omitempty"`
+	History       []SessionRecord `yaml:"history,omitempty"`
+	Facts         *HostFacts      `yaml:"facts,omitempty"`
+}
+
+// IsZero reports whether nothing has been learned about the connection yet
+func (s ConnectionState) IsZero() bool {
+	return s.LastConnected == nil && (s.LastStatus == "" || s.LastStatus == ConnStatusUnknown) &&
+		s.LastAddress == "" && s.HealthStatus == "" && len(s.History) == 0 && s.Facts == nil
+}
+
+// State returns the connection's state
+func (c *Connection) State() ConnectionState {
+	return ConnectionState{
+		LastConnected: c.LastConnected,
+		LastStatus:    c.LastStatus,
+		LastAddress:   c.LastAddress,
+		HealthStatus:  c.HealthStatus,
+		History:       c.History,
+		Facts:         c.Facts,
+	}
+}
+
+// SetState replaces the connection's state
+func (c *Connection) SetState(s ConnectionState) {
+	c.LastConnected = s.LastConnected
+	c.LastStatus = s.LastStatus
+	c.LastAddress = s.LastAddress
+	c.HealthStatus = s.HealthStatus
+	c.History = s.History
+	c.Facts = s.Facts
+}
+
+// State is the state file: each connection's state by connection ID
+type State struct {
+	Connections map[string]ConnectionState `yaml:"connections"`
+	Unlock      UnlockAttempts             `yaml:"unlock,omitempty"` // Failed master password attempts
+}
+
+// FreeUnlockAttempts is how many wrong master passwords may be entered in a
+// row before each further attempt has to wait
+const FreeUnlockAttempts = 3
+
+// Delays between unlock attempts: the first after FreeUnlockAttempts
+// failures, doubling with each further failure up to the longest
+const (
+	firstUnlockDelay = 10 * time.Second
+	maxUnlockDelay   = time.Hour
+)
+
+// UnlockAttempts counts the wrong master passwords entered since the last
+// unlock, kept in the state file so restarting gossh doesn't reset it
+type UnlockAttempts struct {
+	Failures    int       `yaml:"failures,omitempty"`
+	LastFailure time.Time `yaml:"last_failure,omitempty"`
+}
+
+// Delay returns how long after the last failure the next attempt must
+// wait
+func (a UnlockAttempts) Delay() time.Duration {
+	if a.Failures < FreeUnlockAttempts {
+		return 0
+	}
+	delay := firstUnlockDelay
+	for i := FreeUnlockAttempts; i < a.Failures && delay < maxUnlockDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxUnlockDelay)
+}
+
+// Wait returns how long from now the next attempt must wait, zero if it
+// may be made at once
+func (a UnlockAttempts) Wait(now time.Time) time.Duration {
+	return max(a.LastFailure.Add(a.Delay()).Sub(now), 0)
+}
+
+// LastSession returns the most recent session, if any
+func (c *Connection) LastSession() (SessionRecord, bool) {
+	if len(c.History) == 0 {
+		return SessionRecord{}, false
+	}
+	return c.History[len(c.History)-1], true
+}
+
+// DuplicateKey identifies the account a connection logs in to: the same
+// user on the same host and port
+func (c *Connection) DuplicateKey() string {
+	return c.User + "@" + net.JoinHostPort(strings.ToLower(c.Host), strconv.Itoa(c.Port))
+}
+
+// FindDuplicates returns the sets of connections that share a duplicate
+// key, in list order. Connections without a duplicate are left out.
+func FindDuplicates(connections []Connection) [][]Connection {
+	index := make(map[string]int)
+	var sets [][]Connection
+	for _, c := range connections {
+		key := c.DuplicateKey()
+		if i, ok := index[key]; ok {
+			sets[i] = append(sets[i], c)
+			continue
+		}
+		index[key] = len(sets)
+		sets = append(sets, []Connection{c})
+	}
+	return slices.DeleteFunc(sets, func(set []Connection) bool { return len(set) < 2 })
+}
+
+// PickSurvivor returns the index of the connection in a duplicate set that
+// should absorb the others: the one connected to last, else the first
+func PickSurvivor(set []Connection) int {
+	best := 0
+	for i, c := range set {
+		if c.LastConnected != nil && (set[best].LastConnected == nil || c.LastConnected.After(*set[best].LastConnected)) {
+			best = i
+		}
+	}
+	return best
+}
+
+// MergeDuplicates folds duplicates into keep: their names become aliases,
+// tags, aliases, addresses and notes are combined, sessions are merged into
+// one history, and a group or secret keep lacks is taken from the first
+// duplicate that has one. The newest state wins.
+func MergeDuplicates(keep Connection, duplicates []Connection) Connection {
+	addAll := func(list []string, items ...string) []string {
+		for _, item := range items {
+			if item != "" && !slices.Contains(list, item) {
+				list = append(list, item)
+			}
+		}
+		return list
+	}
+
+	keep.Tags = slices.Clone(keep.Tags)
+	keep.Aliases = slices.Clone(keep.Aliases)
+	keep.Addresses = slices.Clone(keep.Addresses)
+	keep.History = slices.Clone(keep.History)
+	notes := addAll(nil, keep.Notes)
+	for _, d := range duplicates {
+		keep.Tags = addAll(keep.Tags, d.Tags...)
+		keep.Aliases = addAll(keep.Aliases, d.Names()...)
+		keep.Addresses = addAll(keep.Addresses, d.Addresses...)
+		notes = addAll(notes, d.Notes)
+		keep.History = append(keep.History, d.History...)
+
+		if keep.Group == "" {
+			keep.Group = d.Group
+		}
+		if keep.Password == "" && d.Password != "" {
+			keep.Password, keep.EncryptedPassword = d.Password, d.EncryptedPassword
+		}
+		if keep.KeyPassword == "" && d.KeyPassword != "" {
+			keep.KeyPassword, keep.EncryptedKeyPassphrase = d.KeyPassword, d.EncryptedKeyPassphrase
+		}
+		if d.CreatedAt.Before(keep.CreatedAt) {
+			keep.CreatedAt = d.CreatedAt
+		}
+		if d.LastConnected != nil && (keep.LastConnected == nil || d.LastConnected.After(*keep.LastConnected)) {
+			keep.LastConnected, keep.LastStatus, keep.LastAddress = d.LastConnected, d.LastStatus, d.LastAddress
+		}
+		if d.Facts != nil && (keep.Facts == nil || d.Facts.GatheredAt.After(keep.Facts.GatheredAt)) {
+			keep.Facts = d.Facts
+		}
+	}
+	keep.Aliases = slices.DeleteFunc(keep.Aliases, func(a string) bool { return a == keep.Name })
+	keep.Notes = strings.Join(notes, "\n")
+
+	slices.SortStableFunc(keep.History, func(a, b SessionRecord) int { return a.StartedAt.Compare(b.StartedAt) })
+	if len(keep.History) > MaxHistory {
+		keep.History = keep.History[len(keep.History)-MaxHistory:]
+	}
+	return keep
+}
+
+// MaxRevisions is how many revisions are kept per connection
+const MaxRevisions = 20
+
+// Revision is a connection as it was before an edit
+type Revision struct {
+	At         time.Time  `yaml:"at"`
+	By         string     `yaml:"by,omitempty"` // Local user who made the edit
+	Fields     []string   `yaml:"fields"`       // Fields the edit changed, as named in the config file
+	Connection Connection `yaml:"connection"`   // Without plain text secrets or state
+}
+
+// History is the history file: each connection's revisions by connection
+// ID, oldest first
+type History struct {
+	Connections map[string][]Revision `yaml:"connections"`
+}
+
+// MaxRuns is how many batch runs are remembered for retrying their failures
+const MaxRuns = 20
+
+// Run is a finished batch run, kept so the hosts it failed on can be
+// retried
+type Run struct {
+	ID      string    `yaml:"id"`
+	At      time.Time `yaml:"at"`
+	Command string    `yaml:"command"`
+	Hosts   int       `yaml:"hosts"`            // Hosts it ran on
+	Failed  []string  `yaml:"failed,omitempty"` // Names of the hosts it failed on, services as <name>/<host>
+}
+
+// NewRun creates a run of command on hosts hosts that failed on the named
+// ones
+func NewRun(command string, hosts int, failed []string) Run {
+	return Run{
+		ID:      uuid.New().String(),
+		At:      time.Now(),
+		Command: command,
+		Hosts:   hosts,
+		Failed:  failed,
+	}
+}
+
+// ShortID returns the first characters of the run's ID, enough to tell
+// runs apart
+func (r Run) ShortID() string {
+	if len(r.ID) > 8 {
+		return r.ID[:8]
+	}
+	return r.ID
+}
+
+// Runs is the runs file: the latest batch runs, oldest first
+type Runs struct {
+	Runs []Run `yaml:"runs"`
+}
+
+// HostFacts describes the system a host runs, as gathered from its
+// os-release file, uname and uptime
+type HostFacts struct {
+	OSID       string     `yaml:"os_id,omitempty"`      // os-release ID, e.g. "centos", or the uname system
+	OSName     string     `yaml:"os_name,omitempty"`    // os-release NAME, e.g. "CentOS Linux"
+	OSVersion  string     `yaml:"os_version,omitempty"` // os-release VERSION_ID, e.g. "7"
+	Kernel     string     `yaml:"kernel,omitempty"`     // uname -sr, e.g. "Linux 3.10.0"
+	BootedAt   *time.Time `yaml:"booted_at,omitempty"`
+	GatheredAt time.Time  `yaml:"gathered_at"`
+}
+
+// osNames shortens the names of common systems, by os-release ID
+var osNames = map[string]string{
+	"almalinux":     "AlmaLinux",
+	"amzn":          "Amazon Linux",
+	"ol":            "Oracle Linux",
+	"opensuse-leap": "openSUSE Leap",
+	"rhel":          "RHEL",
+	"sles":          "SLES",
+}
+
+// OS returns a short name and version for the system, e.g. "CentOS 7"
+func (f *HostFacts) OS() string {
+	name, ok := osNames[f.OSID]
+	if !ok {
+		name = f.OSName
+		for _, suffix := range []string{" GNU/Linux", " Linux"} {
+			name = strings.TrimSuffix(name, suffix)
+		}
+	}
+	if name == "" {
+		name = f.OSID
+	}
+	if f.OSVersion == "" {
+		return name
+	}
+	return strings.TrimSpace(name + " " + f.OSVersion)
+}
+
+// Uptime returns how long the host has been up at now, assuming it hasn't
+// rebooted since the facts were gathered. It is zero when unknown.
+func (f *HostFacts) Uptime(now time.Time) time.Duration {
+	if f.BootedAt == nil || now.Before(*f.BootedAt) {
+		return 0
+	}
+	return now.Sub(*f.BootedAt)
+}
+
+// ShortDuration formats d compactly with at most two units, e.g. 42s, 14m,
+// 2h5m or 3d4h
+func ShortDuration(d time.Duration) string {
+	if d < time.Minute {
+		return strconv.Itoa(int(d.Seconds())) + "s"
+	}
+	if d < time.Hour {
+		return strconv.Itoa(int(d.Minutes())) + "m"
+	}
+
+	days := int(d / (24 * time.Hour))
+	hours := int(d/time.Hour) % 24
+	minutes := int(d/time.Minute) % 60
+	if days > 0 {
+		if hours == 0 {
+			return strconv.Itoa(days) + "d"
+		}
+		return strconv.Itoa(days) + "d" + strconv.Itoa(hours) + "h"
+	}
+	if minutes == 0 {
+		return strconv.Itoa(hours) + "h"
+	}
+	return strconv.Itoa(hours) + "h" + strconv.Itoa(minutes) + "m"
 }
 
 // Group represents a connection group
@@ -113,14 +1070,245 @@ type Group struct {
 
 // Settings represents application settings
 type Settings struct {
-	MasterPasswordHash        string `yaml:"master_password_hash,omitempty"`
-	EncryptionSalt            string `yaml:"encryption_salt,omitempty"`
-	PasswordProtectionEnabled bool   `yaml:"password_protection_enabled"`
-	Initialized               bool   `yaml:"initialized"` // True after first-time setup
-	ConnectionTimeout         int    `yaml:"connection_timeout"`
-	DefaultPort               int    `yaml:"default_port"`
-	Theme                     string `yaml:"theme"`
-	Language                  string `yaml:"language,omitempty"` // "en" or "zh"
+	MasterPasswordHash        string                `yaml:"master_password_hash,omitempty"`
+	EncryptionSalt            string                `yaml:"encryption_salt,omitempty"`
+	KeyCheck                  string                `yaml:"key_check,omitempty"` // A known text encrypted with the master key, to verify keys against
+	PasswordProtectionEnabled bool                  `yaml:"password_protection_enabled"`
+	Initialized               bool                  `yaml:"initialized"` // True after first-time setup
+	ConnectionTimeout         int                   `yaml:"connection_timeout"`
+	DefaultPort               int                   `yaml:"default_port"`
+	Theme                     string                `yaml:"theme"`
+	Language                  string                `yaml:"language,omitempty"` // Language code, e.g. "en" or "zh"
+	TrashRetentionDays        int                   `yaml:"trash_retention_days,omitempty"`
+	Notify                    NotifyMode            `yaml:"notify,omitempty"`
+	NotifyAfterSeconds        int                   `yaml:"notify_after_seconds,omitempty"` // Only notify for tasks at least this long
+	ListLayout                ListLayout            `yaml:"list_layout,omitempty"`
+	ListColumns               []ListColumn          `yaml:"list_columns,omitempty"` // Table columns shown; empty means all
+	ListSort                  ListColumn            `yaml:"list_sort,omitempty"`    // Table column sorted by; empty keeps the config order
+	ListSortDesc              bool                  `yaml:"list_sort_desc,omitempty"`
+	KeepWarmMinutes           int                   `yaml:"keep_warm_minutes,omitempty"`    // Keep connections open this long after their last use; 0 closes them
+	SavedFilters              []SavedFilter         `yaml:"saved_filters,omitempty"`        // Named search queries for the list
+	DefaultGroup              string                `yaml:"default_group,omitempty"`        // Group for new connections no rule files elsewhere
+	GroupRules                []GroupRule           `yaml:"group_rules,omitempty"`          // Filing rules for added and imported connections
+	DefaultUser               string                `yaml:"default_user,omitempty"`         // User filled in for new connections
+	KeepaliveSeconds          int                   `yaml:"keepalive_seconds,omitempty"`    // Interval of session keepalives; 0 uses the built-in one
+	NoConfirmDelete           bool                  `yaml:"no_confirm_delete,omitempty"`    // Move connections to the trash without asking
+	HealthCheckMinutes        int                   `yaml:"health_check_minutes,omitempty"` // Check all connections in the background this often; 0 never
+	Term                      string                `yaml:"term,omitempty"`                 // TERM for sessions whose connection sets none
+	Locale                    string                `yaml:"locale,omitempty"`               // Locale for sessions whose connection sets none
+	PasswordPolicy            crypto.PasswordPolicy `yaml:"password_policy,omitempty"`      // What a new master password must meet
+	KeyCacheMinutes           int                   `yaml:"key_cache_minutes,omitempty"`    // Remember the master key for CLI commands this long after its last use; 0 asks every time
+	RememberKey               bool                  `yaml:"remember_key,omitempty"`         // The master key is in the OS keyring of a machine, see gossh remember
+	ScrollbackKB              int                   `yaml:"scrollback_kb,omitempty"`        // Keep this much of each session's output to look through after it ends; 0 keeps none
+	PluginSecrets             []string              `yaml:"plugin_secrets,omitempty"`       // Plugins given the connection's password and key passphrase
+}
+
+// SavedFilter is a search query saved under a name, to filter the list
+// with again from the filter menu
+type SavedFilter struct {
+	Name  string `yaml:"name"`
+	Query string `yaml:"query"`
+}
+
+// GroupRule files connections whose name or host matches Pattern, a
+// regular expression matched regardless of case, under Group and adds
+// Tags to them
+type GroupRule struct {
+	Pattern string   `yaml:"pattern"`
+	Group   string   `yaml:"group,omitempty"`
+	Tags    []string `yaml:"tags,omitempty"`
+}
+
+// Validate checks that the pattern compiles and the rule does something
+func (r GroupRule) Validate() error {
+	if r.Pattern == "" {
+		return ErrInvalidPattern
+	}
+	if _, err := regexp.Compile("(?i)" + r.Pattern); err != nil {
+		return ErrInvalidPattern
+	}
+	if r.Group == "" && len(r.Tags) == 0 {
+		return ErrRuleEmpty
+	}
+	return nil
+}
+
+// Matches reports whether the rule applies to c. A pattern that does not
+// compile matches nothing.
+func (r GroupRule) Matches(c Connection) bool {
+	if r.Pattern == "" {
+		return false
+	}
+	re, err := regexp.Compile("(?i)" + r.Pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(c.Name) || re.MatchString(c.Host)
+}
+
+// ApplyGroupRules returns c filed by the rules: a connection without a
+// group takes the group of the first matching rule that names one, or
+// defaultGroup when none does, and every matching rule adds its tags.
+// A group set by hand is kept.
+func ApplyGroupRules(c Connection, rules []GroupRule, defaultGroup string) Connection {
+	tags := slices.Clone(c.Tags)
+	for _, r := range rules {
+		if !r.Matches(c) {
+			continue
+		}
+		if c.Group == "" && r.Group != "" {
+			c.Group = r.Group
+		}
+		for _, tag := range r.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	if c.Group == "" {
+		c.Group = defaultGroup
+	}
+	c.Tags = tags
+	return c
+}
+
+// GroupChange is what the grouping rules would change on a connection
+type GroupChange struct {
+	Connection Connection
+	Group      string   // The new group, empty when it stays
+	Tags       []string // Tags the rules add
+}
+
+// PreviewGroupRules returns the changes the rules would make to the
+// connections, without making them
+func PreviewGroupRules(connections []Connection, rules []GroupRule, defaultGroup string) []GroupChange {
+	var changes []GroupChange
+	for _, c := range connections {
+		filed := ApplyGroupRules(c, rules, defaultGroup)
+		change := GroupChange{Connection: c}
+		if filed.Group != c.Group {
+			change.Group = filed.Group
+		}
+		change.Tags = filed.Tags[len(c.Tags):]
+		if change.Group != "" || len(change.Tags) > 0 {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// Workspace is a named set of connections, tunnels and snippets opened
+// together, e.g. a database tunnel and the web hosts of a release
+type Workspace struct {
+	Name        string            `yaml:"name"`
+	Connections []string          `yaml:"connections,omitempty"` // Names of the connections to open sessions to
+	Tunnels     []WorkspaceTunnel `yaml:"tunnels,omitempty"`
+	Snippets    []Snippet         `yaml:"snippets,omitempty"`
+}
+
+// WorkspaceTunnel is a port forward a workspace starts
+type WorkspaceTunnel struct {
+	Connection string `yaml:"connection"`
+	Type       string `yaml:"type"` // "local" (-L) or "remote" (-R)
+	Spec       string `yaml:"spec"` // As given to ssh -L/-R, e.g. 5432:localhost:5432
+}
+
+// Snippet is a command saved under a name, run on a workspace's connections
+type Snippet struct {
+	Name    string `yaml:"name"`
+	Command string `yaml:"command"`
+}
+
+// Validate checks that the workspace opens something and every tunnel and
+// snippet is complete
+func (w Workspace) Validate() error {
+	if w.Name == "" {
+		return ErrNameRequired
+	}
+	if len(w.Connections) == 0 && len(w.Tunnels) == 0 {
+		return ErrWorkspaceEmpty
+	}
+	for _, t := range w.Tunnels {
+		if t.Connection == "" || t.Spec == "" || (t.Type != "local" && t.Type != "remote") {
+			return ErrInvalidTunnel
+		}
+	}
+	for _, s := range w.Snippets {
+		if s.Name == "" || s.Command == "" {
+			return ErrInvalidSnippet
+		}
+	}
+	return nil
+}
+
+// Snippet returns the snippet with the given name
+func (w Workspace) Snippet(name string) (Snippet, bool) {
+	for _, s := range w.Snippets {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Snippet{}, false
+}
+
+// RenameConnection points the workspace's sessions and tunnels at a
+// renamed connection
+func (w *Workspace) RenameConnection(oldName, newName string) {
+	for i, name := range w.Connections {
+		if strings.EqualFold(name, oldName) {
+			w.Connections[i] = newName
+		}
+	}
+	for i, t := range w.Tunnels {
+		if strings.EqualFold(t.Connection, oldName) {
+			w.Tunnels[i].Connection = newName
+		}
+	}
+}
+
+// ScheduledJob is a batch command run on a cron spec by gossh schedule
+// daemon
+type ScheduledJob struct {
+	ID         string     `yaml:"id"`
+	Cron       string     `yaml:"cron"` // minute hour day-of-month month day-of-week, e.g. 0 3 * * *
+	Command    string     `yaml:"command"`
+	Targets    []string   `yaml:"targets,omitempty"` // gossh exec target flags, e.g. --group=Backups
+	Timeout    int        `yaml:"timeout,omitempty"` // Seconds per host, 0 for the default
+	LastRun    *time.Time `yaml:"last_run,omitempty"`
+	LastFailed int        `yaml:"last_failed,omitempty"` // Hosts the last run failed on
+}
+
+// NewScheduledJob creates a job running command on the cron spec
+func NewScheduledJob(cron, command string, targets []string) ScheduledJob {
+	return ScheduledJob{
+		ID:      uuid.New().String(),
+		Cron:    cron,
+		Command: command,
+		Targets: targets,
+	}
+}
+
+// ShortID returns the first characters of the job's ID, enough to tell
+// jobs apart
+func (j ScheduledJob) ShortID() string {
+	if len(j.ID) > 8 {
+		return j.ID[:8]
+	}
+	return j.ID
+}
+
+// Validate checks that the job has a valid cron spec and a command
+func (j ScheduledJob) Validate() error {
+	if _, err := schedule.Parse(j.Cron); err != nil {
+		return ErrInvalidCron
+	}
+	if strings.TrimSpace(j.Command) == "" {
+		return ErrCommandRequired
+	}
+	if j.Timeout < 0 {
+		return ErrInvalidTimeout
+	}
+	return nil
 }
 
 // NewSettings creates default settings
@@ -132,7 +1320,263 @@ func NewSettings() Settings {
 		DefaultPort:               22,
 		Theme:                     "dark",
 		Language:                  "en",
+		TrashRetentionDays:        DefaultTrashRetentionDays,
+		PasswordPolicy:            crypto.DefaultPasswordPolicy,
+	}
+}
+
+// Themes lists the color themes: dark for dark terminal backgrounds, the
+// default, and light for light ones
+var Themes = []string{"dark", "light"}
+
+// ConnectTimeout returns how long connecting to a host may take, or zero
+// to use the built-in timeout
+func (s *Settings) ConnectTimeout() time.Duration {
+	if s.ConnectionTimeout <= 0 {
+		return 0
+	}
+	return time.Duration(s.ConnectionTimeout) * time.Second
+}
+
+// KeepWarmChoices lists the keep-warm times in minutes that Settings cycles
+// through, starting with off
+var KeepWarmChoices = []int{0, 5, 15, 60}
+
+// KeepWarm returns how long a connection stays open after its last session
+// ends, or zero to close it at once
+func (s *Settings) KeepWarm() time.Duration {
+	if s.KeepWarmMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(s.KeepWarmMinutes) * time.Minute
+}
+
+// HealthCheckChoices lists the background health check intervals in minutes
+// that Settings cycles through, starting with off
+var HealthCheckChoices = []int{0, 5, 15, 60}
+
+// HealthCheck returns how often all connections are checked in the
+// background, or zero to only check them on request
+func (s *Settings) HealthCheck() time.Duration {
+	if s.HealthCheckMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(s.HealthCheckMinutes) * time.Minute
+}
+
+// KeyCacheChoices lists the key cache times in minutes that Settings cycles
+// through, starting with off
+var KeyCacheChoices = []int{0, 5, 15, 60}
+
+// KeyCache returns how long CLI commands reuse the master key after it was
+// last used, or zero to ask for the master password every time
+func (s *Settings) KeyCache() time.Duration {
+	if s.KeyCacheMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(s.KeyCacheMinutes) * time.Minute
+}
+
+// ScrollbackChoices lists the scrollback sizes in KB that Settings cycles
+// through, starting with off
+var ScrollbackChoices = []int{0, 256, 1024, 4096}
+
+// DefaultScrollbackKB is the scrollback kept when asked for with the
+// setting off, e.g. by gossh connect --scrollback
+const DefaultScrollbackKB = 256
+
+// Scrollback returns how many bytes of a session's output are kept to look
+// through after it ends, or zero to keep none
+func (s *Settings) Scrollback() int {
+	if s.ScrollbackKB <= 0 {
+		return 0
+	}
+	return s.ScrollbackKB * 1024
+}
+
+// Keepalive returns how often sessions send keepalives, or zero to use the
+// built-in interval
+func (s *Settings) Keepalive() time.Duration {
+	if s.KeepaliveSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(s.KeepaliveSeconds) * time.Second
+}
+
+// ConfirmDelete reports whether deleting a connection asks first
+func (s *Settings) ConfirmDelete() bool {
+	return !s.NoConfirmDelete
+}
+
+// NewConnectionPort returns the port filled in for new connections of type
+// t: the default_port setting for SSH, the protocol's port otherwise
+func (s *Settings) NewConnectionPort(t ConnectionType) int {
+	if t == ConnTypeTelnet || s.DefaultPort <= 0 || s.DefaultPort > 65535 {
+		return DefaultPort(t)
+	}
+	return s.DefaultPort
+}
+
+// NotifyMode is how the user is told that a long task has finished
+type NotifyMode string
+
+const (
+	NotifyOff     NotifyMode = "off"
+	NotifyBell    NotifyMode = "bell"    // Terminal bell plus a status line
+	NotifyDesktop NotifyMode = "desktop" // Native desktop notification
+)
+
+// NotifyModes lists the notification modes in the order Settings cycles them
+var NotifyModes = []NotifyMode{NotifyOff, NotifyBell, NotifyDesktop}
+
+// DefaultNotifyAfterSeconds is how long a task must run before it notifies
+const DefaultNotifyAfterSeconds = 10
+
+// NotifyMode returns the configured notification mode (bell by default)
+func (s *Settings) NotifyMode() NotifyMode {
+	if s.Notify == "" {
+		return NotifyBell
+	}
+	return s.Notify
+}
+
+// NotifyAfter returns how long a task must run before it notifies
+func (s *Settings) NotifyAfter() time.Duration {
+	seconds := s.NotifyAfterSeconds
+	if seconds <= 0 {
+		seconds = DefaultNotifyAfterSeconds
 	}
+	return time.Duration(seconds) * time.Second
+}
+
+// DefaultTrashRetentionDays is how long deleted connections stay in the trash
+const DefaultTrashRetentionDays = 30
+
+// TrashRetention returns how long deleted connections are kept in the trash
+func (s *Settings) TrashRetention() time.Duration {
+	days := s.TrashRetentionDays
+	if days <= 0 {
+		days = DefaultTrashRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// ListLayout is how the connection list is drawn
+type ListLayout string
+
+const (
+	LayoutLines ListLayout = "lines" // One line per connection, grouped
+	LayoutTable ListLayout = "table" // Aligned, sortable columns under a header
+)
+
+// ListLayouts lists the layouts in the order Settings cycles them
+var ListLayouts = []ListLayout{LayoutLines, LayoutTable}
+
+// ListColumn is a column of the table layout
+type ListColumn string
+
+const (
+	ColumnName     ListColumn = "name"
+	ColumnHost     ListColumn = "host" // user@host:port
+	ColumnGroup    ListColumn = "group"
+	ColumnTags     ListColumn = "tags"
+	ColumnLastSeen ListColumn = "last_seen"
+	ColumnStatus   ListColumn = "status"
+	ColumnSystem   ListColumn = "system" // OS and uptime from the host's facts
+)
+
+// ListColumns lists the table columns in the order they are shown
+var ListColumns = []ListColumn{ColumnName, ColumnHost, ColumnGroup, ColumnTags, ColumnLastSeen, ColumnStatus, ColumnSystem}
+
+// Layout returns the configured list layout (lines by default)
+func (s *Settings) Layout() ListLayout {
+	if s.ListLayout == "" {
+		return LayoutLines
+	}
+	return s.ListLayout
+}
+
+// TableColumns returns the table columns to show, in display order. The
+// name column is always shown.
+func (s *Settings) TableColumns() []ListColumn {
+	if len(s.ListColumns) == 0 {
+		return ListColumns
+	}
+	var columns []ListColumn
+	for _, c := range ListColumns {
+		if c == ColumnName || slices.Contains(s.ListColumns, c) {
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+// SortConnections sorts connections by a table column, keeping the order
+// of connections that compare equal. Ties on other columns than the name
+// are broken by name.
+func SortConnections(conns []Connection, by ListColumn, desc bool) {
+	slices.SortStableFunc(conns, func(a, b Connection) int {
+		c := compareColumn(a, b, by)
+		if c == 0 && by != ColumnName {
+			c = compareColumn(a, b, ColumnName)
+		}
+		if desc {
+			return -c
+		}
+		return c
+	})
+}
+
+// compareColumn orders two connections by the value of one column
+func compareColumn(a, b Connection, by ListColumn) int {
+	switch by {
+	case ColumnName:
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	case ColumnHost:
+		return cmp.Or(
+			strings.Compare(strings.ToLower(a.Host), strings.ToLower(b.Host)),
+			strings.Compare(a.User, b.User),
+			cmp.Compare(a.Port, b.Port),
+		)
+	case ColumnGroup:
+		return strings.Compare(strings.ToLower(a.Group), strings.ToLower(b.Group))
+	case ColumnTags:
+		return strings.Compare(strings.Join(a.Tags, ","), strings.Join(b.Tags, ","))
+	case ColumnLastSeen:
+		// Never connected sorts first
+		var at, bt time.Time
+		if a.LastConnected != nil {
+			at = *a.LastConnected
+		}
+		if b.LastConnected != nil {
+			bt = *b.LastConnected
+		}
+		return at.Compare(bt)
+	case ColumnStatus:
+		return cmp.Compare(statusRank(a.LastStatus), statusRank(b.LastStatus))
+	case ColumnSystem:
+		// Hosts without facts sort first
+		var aos, bos string
+		if a.Facts != nil {
+			aos = a.Facts.OS()
+		}
+		if b.Facts != nil {
+			bos = b.Facts.OS()
+		}
+		return strings.Compare(strings.ToLower(aos), strings.ToLower(bos))
+	}
+	return 0
+}
+
+// statusRank orders statuses from working to unknown
+func statusRank(status ConnStatus) int {
+	switch status {
+	case ConnStatusSuccess:
+		return 0
+	case ConnStatusFailed:
+		return 1
+	}
+	return 2
 }
 
 // IsPasswordSet returns true if master password has been set
@@ -147,16 +1591,51 @@ func (s *Settings) NeedsUnlock() bool {
 
 // Config represents the application configuration
 type Config struct {
-	Version     string       `yaml:"version"`
-	Settings    Settings     `yaml:"settings"`
-	Groups      []Group      `yaml:"groups"`
-	Connections []Connection `yaml:"connections"`
+	Version     string         `yaml:"version"`
+	Settings    Settings       `yaml:"settings"`
+	Groups      []Group        `yaml:"groups"`
+	Connections []Connection   `yaml:"connections"`
+	Trash       []Connection   `yaml:"trash,omitempty"` // Deleted connections awaiting purge
+	Hooks       []Hook         `yaml:"hooks,omitempty"`
+	Workspaces  []Workspace    `yaml:"workspaces,omitempty"`
+	Schedules   []ScheduledJob `yaml:"schedules,omitempty"`
+}
+
+// Hook runs a local command or POSTs to a URL when an event fires
+type Hook struct {
+	Event   string `yaml:"event"`             // Event name, or "*" for every event
+	Command string `yaml:"command,omitempty"` // Run via the shell with the payload on stdin
+	URL     string `yaml:"url,omitempty"`     // Receives the payload as a JSON POST
+	Timeout int    `yaml:"timeout,omitempty"` // Seconds, defaults to 10
+}
+
+// ConfigVersion is the version of the config file format. Config files of
+// older versions are migrated when loaded.
+const ConfigVersion = "1.2"
+
+// OlderVersion reports whether config version a predates b, comparing the
+// dot-separated numbers in turn. A missing version is older than any.
+func OlderVersion(a, b string) bool {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			return x < y
+		}
+	}
+	return false
 }
 
 // NewConfig creates a new config with defaults
 func NewConfig() Config {
 	return Config{
-		Version:  "1.0",
+		Version:  ConfigVersion,
 		Settings: NewSettings(),
 		Groups: []Group{
 			{Name: "Production", Color: "#ff6b6b"},
@@ -201,11 +1680,35 @@ func (e ValidationError) Error() string {
 }
 
 var (
-	ErrNameRequired    = ValidationError{Field: "name", Message: "name is required"}
-	ErrHostRequired    = ValidationError{Field: "host", Message: "host is required"}
-	ErrUserRequired    = ValidationError{Field: "user", Message: "user is required"}
-	ErrInvalidPort     = ValidationError{Field: "port", Message: "port must be between 1 and 65535"}
-	ErrKeyPathRequired = ValidationError{Field: "key_path", Message: "key path is required for key authentication"}
+	ErrNameRequired          = ValidationError{Field: "name", Message: "name is required"}
+	ErrHostRequired          = ValidationError{Field: "host", Message: "host is required"}
+	ErrInvalidHost           = ValidationError{Field: "host_invalid", Message: "host must be a hostname or IP address"}
+	ErrInvalidAddress        = ValidationError{Field: "addresses", Message: "other addresses must be hostnames or IP addresses"}
+	ErrInvalidAlias          = ValidationError{Field: "aliases", Message: "aliases must be single words other than the name"}
+	ErrUserRequired          = ValidationError{Field: "user", Message: "user is required"}
+	ErrInvalidPort           = ValidationError{Field: "port", Message: "port must be between 1 and 65535"}
+	ErrKeyPathRequired       = ValidationError{Field: "key_path", Message: "key path is required for key authentication"}
+	ErrKeyUnreadable         = ValidationError{Field: "key_file", Message: "key file cannot be read or is not a private key"}
+	ErrKeyPassphrase         = ValidationError{Field: "key_passphrase", Message: "the key is encrypted, enter its passphrase"}
+	ErrInvalidAgentKey       = ValidationError{Field: "agent_key", Message: "agent key must be a SHA256 fingerprint, as ssh-add -l prints it"}
+	ErrInvalidTerm           = ValidationError{Field: "term", Message: "terminal type must be one word, e.g. vt100"}
+	ErrInvalidLocale         = ValidationError{Field: "locale", Message: "locale must be one word, e.g. C.UTF-8"}
+	ErrInvalidRotation       = ValidationError{Field: "rotate_after", Message: "rotation period must be a positive number of days"}
+	ErrInvalidExpiry         = ValidationError{Field: "expires_at", Message: "expiry date must be in YYYY-MM-DD format"}
+	ErrJumpNotFound          = ValidationError{Field: "jump_hosts", Message: "jump host not found"}
+	ErrJumpLoop              = ValidationError{Field: "jump_hosts", Message: "jump hosts lead back to the connection"}
+	ErrInvalidPattern        = ValidationError{Field: "pattern", Message: "pattern must be a valid regular expression"}
+	ErrRuleEmpty             = ValidationError{Field: "rule", Message: "a rule needs a group or tags"}
+	ErrWorkspaceEmpty        = ValidationError{Field: "workspace", Message: "a workspace needs connections or tunnels"}
+	ErrInvalidTunnel         = ValidationError{Field: "tunnel", Message: "a tunnel needs a connection, -L or -R and a forward spec"}
+	ErrInvalidForwardProfile = ValidationError{Field: "forward_profiles", Message: "a forward profile needs a name without spaces and -L, -R or -D forward specs"}
+	ErrInvalidSnippet        = ValidationError{Field: "snippet", Message: "a snippet needs a name and a command"}
+	ErrInvalidCron           = ValidationError{Field: "cron", Message: "schedule must be a cron spec, e.g. 0 3 * * *"}
+	ErrCommandRequired       = ValidationError{Field: "command", Message: "command is required"}
+	ErrInvalidTimeout        = ValidationError{Field: "timeout", Message: "timeout must be a positive number of seconds"}
+	ErrInvalidInterval       = ValidationError{Field: "interval", Message: "interval must be a positive number, or 0 for the default"}
+	ErrInvalidTheme          = ValidationError{Field: "theme", Message: "theme must be dark or light"}
+	ErrInvalidDefaultUser    = ValidationError{Field: "default_user", Message: "default user must be one word"}
 )
 
 // Helper functions for case-insensitive matching
@@ -220,18 +1723,3 @@ func toLower(s string) string {
 	}
 	return string(b)
 }
-
-func contains(s, substr string) bool {
-	if len(substr) == 0 {
-		return true
-	}
-	if len(substr) > len(s) {
-		return false
-	}
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
-		}
-	}
-	return false
-}
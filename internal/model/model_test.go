@@ -1,7 +1,14 @@
 package model
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestConnectionValidate(t *testing.T) {
@@ -13,23 +20,33 @@ func TestConnectionValidate(t *testing.T) {
 		{
 			name: "valid password auth",
 			conn: Connection{
-				Name:       "test",
-				Host:       "example.com",
-				User:       "admin",
-				Port:       22,
-				AuthMethod: AuthPassword,
+				Name:     "test",
+				Host:     "example.com",
+				User:     "admin",
+				Port:     22,
+				AuthType: AuthPassword,
 			},
 			wantErr: nil,
 		},
 		{
 			name: "valid key auth",
 			conn: Connection{
-				Name:       "test",
-				Host:       "example.com",
-				User:       "admin",
-				Port:       22,
-				AuthMethod: AuthKey,
-				KeyPath:    "/home/user/.ssh/id_rsa",
+				Name:     "test",
+				Host:     "example.com",
+				User:     "admin",
+				Port:     22,
+				AuthType: AuthKey,
+				KeyPath:  "/home/user/.ssh/id_rsa",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "telnet without user",
+			conn: Connection{
+				Name: "switch",
+				Host: "10.0.0.2",
+				Port: 23,
+				Type: ConnTypeTelnet,
 			},
 			wantErr: nil,
 		},
@@ -93,33 +110,157 @@ func TestConnectionValidate(t *testing.T) {
 		{
 			name: "key auth missing key path",
 			conn: Connection{
-				Name:       "test",
-				Host:       "example.com",
-				User:       "admin",
-				Port:       22,
-				AuthMethod: AuthKey,
+				Name:     "test",
+				Host:     "example.com",
+				User:     "admin",
+				Port:     22,
+				AuthType: AuthKey,
 			},
 			wantErr: ErrKeyPathRequired,
 		},
+		{
+			name: "key auth with a pinned agent key",
+			conn: Connection{
+				Name:     "test",
+				Host:     "example.com",
+				User:     "admin",
+				Port:     22,
+				AuthType: AuthKey,
+				AgentKey: "SHA256:uNiVztksCsDhcc0u9e8BujQXVUpKZIDTMczCvj3tD2s",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "invalid agent key",
+			conn: Connection{
+				Name:     "test",
+				Host:     "example.com",
+				User:     "admin",
+				Port:     22,
+				AuthType: AuthKey,
+				AgentKey: "me@laptop",
+			},
+			wantErr: ErrInvalidAgentKey,
+		},
+		{
+			name: "invalid host",
+			conn: Connection{
+				Name: "test",
+				Host: "admin@example.com",
+				User: "admin",
+				Port: 22,
+			},
+			wantErr: ErrInvalidHost,
+		},
+		{
+			name: "invalid other address",
+			conn: Connection{
+				Name:      "test",
+				Host:      "example.com",
+				Addresses: []string{"10.0.0.1", "10.0.0.300"},
+				User:      "admin",
+				Port:      22,
+			},
+			wantErr: ErrInvalidAddress,
+		},
+		{
+			name: "invalid term",
+			conn: Connection{
+				Name: "test",
+				Host: "example.com",
+				User: "admin",
+				Port: 22,
+				Term: "xterm 256",
+			},
+			wantErr: ErrInvalidTerm,
+		},
+		{
+			name: "invalid locale",
+			conn: Connection{
+				Name:   "test",
+				Host:   "example.com",
+				User:   "admin",
+				Port:   22,
+				Locale: "LANG=C",
+			},
+			wantErr: ErrInvalidLocale,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			err := tt.conn.Validate()
-			if err != tt.wantErr {
+			if !errors.Is(err, tt.wantErr) {
 				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
+func TestConnectionProblems(t *testing.T) {
+	conn := Connection{Name: "test", Host: "bad host", Port: 0}
+	problems := conn.Problems()
+	want := []error{ErrInvalidHost, ErrUserRequired, ErrInvalidPort}
+	if len(problems) != len(want) {
+		t.Fatalf("Problems() = %v, want %v", problems, want)
+	}
+	for i := range want {
+		if problems[i] != want[i] {
+			t.Errorf("Problems()[%d] = %v, want %v", i, problems[i], want[i])
+		}
+	}
+}
+
+func TestValidColor(t *testing.T) {
+	for _, color := range []string{"#ff6b6b", "#4ECDC4", "#fff"} {
+		if !ValidColor(color) {
+			t.Errorf("ValidColor(%q) = false, want true", color)
+		}
+	}
+	for _, color := range []string{"", "ff6b6b", "#ff6b6", "#ggg", "red"} {
+		if ValidColor(color) {
+			t.Errorf("ValidColor(%q) = true, want false", color)
+		}
+	}
+}
+
+func TestValidHost(t *testing.T) {
+	tests := map[string]bool{
+		"example.com":                    true,
+		"example.com.":                   true,
+		"web-01":                         true,
+		"my_host.internal":               true,
+		"10.0.0.1":                       true,
+		"2001:db8::1":                    true,
+		"fe80::1%eth0":                   true,
+		"srv:_ssh._tcp.example.com":      true,
+		"consul:web":                     true,
+		"":                               false,
+		"10.0.0.256":                     false,
+		"host name":                      false,
+		"user@example.com":               false,
+		"-web.example.com":               false,
+		"web..example.com":               false,
+		"example.com:22":                 false,
+		"consul:":                        false,
+		strings.Repeat("a", 64) + ".com": false,
+	}
+	for host, want := range tests {
+		if got := ValidHost(host); got != want {
+			t.Errorf("ValidHost(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
 func TestConnectionMatchesFilter(t *testing.T) {
 	conn := Connection{
 		Name:  "Production Web",
 		Host:  "web.example.com",
 		User:  "admin",
 		Group: "Production",
+		Port:  2222,
 		Tags:  []string{"web", "nginx"},
+		Notes: "Behind the office VPN",
 	}
 
 	tests := []struct {
@@ -134,8 +275,20 @@ func TestConnectionMatchesFilter(t *testing.T) {
 		{"match user", "admin", true},
 		{"match group", "production", true},
 		{"match tag", "nginx", true},
+		{"match notes", "vpn", true},
 		{"no match", "database", false},
 		{"partial match name", "web", true},
+		{"field", "tag:nginx", true},
+		{"field elsewhere only", "user:web", false},
+		{"group substring", "group:prod", true},
+		{"port exact", "port:2222", true},
+		{"port partial", "port:22", false},
+		{"all terms", "tag:nginx user:root", false},
+		{"negated", "-group:staging", true},
+		{"negated match", "-tag:web", false},
+		{"quoted", `note:"office vpn"`, true},
+		{"unknown field is text", "web.example.com:22", false},
+		{"unfinished field", "tag:", true},
 	}
 
 	for _, tt := range tests {
@@ -148,6 +301,196 @@ func TestConnectionMatchesFilter(t *testing.T) {
 	}
 }
 
+func TestSearchIndex(t *testing.T) {
+	conns := []Connection{
+		{Name: "web-1", Host: "10.0.0.1", Port: 22, Group: "Production", Tags: []string{"nginx"}},
+		{Name: "web-2", Host: "10.0.0.2", Port: 2222, User: "Deploy"},
+		{Name: "db", Host: "db.example.com", Port: 22, Notes: "Primary NGINX-free box"},
+	}
+	index := NewSearchIndex(conns)
+
+	// Typing narrows from the previous result, other edits rescan
+	queries := []string{"", "w", "WEB", "web-2", "nginx", "no match", "ng",
+		"tag:ng", "tag:nginx", "tag:nginx -", "tag:nginx -web", "-web", "-web-", "port:22", "port:2222", "web port:22"}
+	for _, filter := range queries {
+		var want []int
+		for i := range conns {
+			if conns[i].MatchesFilter(filter) {
+				want = append(want, i)
+			}
+		}
+		got := index.Match(filter)
+		if !slices.Equal(got, want) {
+			t.Errorf("Match(%q) = %v, want %v", filter, got, want)
+		}
+	}
+
+	// Fields are searched apart
+	if got := index.Match("productionnginx"); len(got) != 0 {
+		t.Errorf("Match() across fields = %v, want none", got)
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	got := ParseQuery(`Web -tag:NGINX group:"eu west" fe80::1 - port:`)
+	want := []SearchTerm{
+		{Value: "web"},
+		{Field: "tag", Value: "nginx", Negate: true},
+		{Field: "group", Value: "eu west"},
+		{Value: "fe80::1"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseQuery() = %+v, want %+v", got, want)
+	}
+}
+
+func TestConnectionAliases(t *testing.T) {
+	c := Connection{Name: "db-01", Aliases: []string{"db1", "db-primary"}, Host: "10.0.0.5", User: "root", Port: 22}
+
+	if !c.HasName("db-primary") || !c.HasName("db-01") || c.HasName("db") {
+		t.Error("HasName() does not match the name and aliases exactly")
+	}
+	if !c.MatchesFilter("name:primary") || !c.MatchesFilter("db1") {
+		t.Error("MatchesFilter() does not search aliases")
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	for _, aliases := range [][]string{{"db 1"}, {"db-01"}, {""}} {
+		c.Aliases = aliases
+		if err := c.Validate(); !errors.Is(err, ErrInvalidAlias) {
+			t.Errorf("Validate() with aliases %q = %v, want ErrInvalidAlias", aliases, err)
+		}
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	conns := []Connection{
+		{Name: "web", Host: "Web.example.com", Port: 22, User: "root"},
+		{Name: "db", Host: "db.example.com", Port: 22, User: "root"},
+		{Name: "web-old", Host: "web.example.com", Port: 22, User: "root"},
+		{Name: "web-deploy", Host: "web.example.com", Port: 22, User: "deploy"},
+		{Name: "web-2222", Host: "web.example.com", Port: 2222, User: "root"},
+	}
+
+	sets := FindDuplicates(conns)
+	if len(sets) != 1 || len(sets[0]) != 2 || sets[0][0].Name != "web" || sets[0][1].Name != "web-old" {
+		t.Errorf("FindDuplicates() = %v", sets)
+	}
+}
+
+func TestMergeDuplicates(t *testing.T) {
+	day := func(d int) *time.Time {
+		at := time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC)
+		return &at
+	}
+	keep := Connection{
+		Name: "web", Tags: []string{"web"}, Notes: "rack 4", CreatedAt: *day(5), LastConnected: day(6),
+		History: []SessionRecord{{StartedAt: *day(6)}},
+	}
+	old := Connection{
+		Name: "web-old", Aliases: []string{"web1"}, Group: "Production", Password: "secret",
+		Tags: []string{"legacy", "web"}, Notes: "rack 4", CreatedAt: *day(1), LastConnected: day(8), LastAddress: "10.0.0.1",
+		History: []SessionRecord{{StartedAt: *day(2)}, {StartedAt: *day(8)}},
+	}
+
+	if got := PickSurvivor([]Connection{keep, old}); got != 1 {
+		t.Errorf("PickSurvivor() = %d, want 1", got)
+	}
+
+	got := MergeDuplicates(keep, []Connection{old})
+	if got.Name != "web" || got.Group != "Production" || got.Password != "secret" || got.Notes != "rack 4" {
+		t.Errorf("MergeDuplicates() = %+v", got)
+	}
+	if !slices.Equal(got.Tags, []string{"web", "legacy"}) || !slices.Equal(got.Aliases, []string{"web-old", "web1"}) {
+		t.Errorf("tags = %v, aliases = %v", got.Tags, got.Aliases)
+	}
+	if !got.CreatedAt.Equal(*day(1)) || !got.LastConnected.Equal(*day(8)) || got.LastAddress != "10.0.0.1" {
+		t.Errorf("created %v, last connected %v via %q", got.CreatedAt, got.LastConnected, got.LastAddress)
+	}
+	if len(got.History) != 3 || !got.History[0].StartedAt.Equal(*day(2)) || !got.History[2].StartedAt.Equal(*day(8)) {
+		t.Errorf("history = %v", got.History)
+	}
+	if len(keep.Tags) != 1 || len(keep.History) != 1 {
+		t.Error("MergeDuplicates() changed its argument")
+	}
+}
+
+func TestApplyGroupRules(t *testing.T) {
+	rules := []GroupRule{
+		{Pattern: `\.prod\.example\.com$`, Group: "Production", Tags: []string{"prod"}},
+		{Pattern: "^web", Tags: []string{"web", "prod"}},
+		{Pattern: "(", Group: "Broken"},
+	}
+
+	tests := []struct {
+		name      string
+		conn      Connection
+		wantGroup string
+		wantTags  []string
+	}{
+		{"host match", Connection{Name: "api", Host: "API1.PROD.example.com"}, "Production", []string{"prod"}},
+		{"tags merge", Connection{Name: "web1", Host: "web1.prod.example.com", Tags: []string{"nginx"}}, "Production", []string{"nginx", "prod", "web"}},
+		{"kept group", Connection{Name: "db", Host: "db.prod.example.com", Group: "Databases"}, "Databases", []string{"prod"}},
+		{"default", Connection{Name: "nas", Host: "192.168.1.5"}, "Home", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ApplyGroupRules(tt.conn, rules, "Home")
+			if got.Group != tt.wantGroup || !slices.Equal(got.Tags, tt.wantTags) {
+				t.Errorf("ApplyGroupRules() = %q %v, want %q %v", got.Group, got.Tags, tt.wantGroup, tt.wantTags)
+			}
+		})
+	}
+
+	conns := []Connection{{Name: "web2", Host: "web2.prod.example.com"}, {Name: "db", Host: "db", Group: "Home"}}
+	changes := PreviewGroupRules(conns, rules, "Home")
+	if len(changes) != 1 || changes[0].Group != "Production" || !slices.Equal(changes[0].Tags, []string{"prod", "web"}) {
+		t.Errorf("PreviewGroupRules() = %+v", changes)
+	}
+	if conns[0].Group != "" || conns[0].Tags != nil {
+		t.Error("PreviewGroupRules() changed the connections")
+	}
+}
+
+func TestGroupRuleValidate(t *testing.T) {
+	tests := []struct {
+		rule GroupRule
+		want error
+	}{
+		{GroupRule{Pattern: "prod", Group: "Production"}, nil},
+		{GroupRule{Pattern: "prod", Tags: []string{"prod"}}, nil},
+		{GroupRule{Pattern: "prod"}, ErrRuleEmpty},
+		{GroupRule{Pattern: "[", Group: "Production"}, ErrInvalidPattern},
+		{GroupRule{Group: "Production"}, ErrInvalidPattern},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.Validate(); got != tt.want {
+			t.Errorf("Validate(%+v) = %v, want %v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func BenchmarkSearchIndex(b *testing.B) {
+	conns := make([]Connection, 5000)
+	for i := range conns {
+		conns[i] = Connection{
+			Name:  fmt.Sprintf("Web-Server-%04d", i),
+			Host:  fmt.Sprintf("10.%d.%d.%d", i/65536, i/256%256, i%256),
+			User:  "deploy",
+			Group: "Production",
+			Tags:  []string{"nginx", "eu-west"},
+			Notes: "Behind the office VPN",
+		}
+	}
+	index := NewSearchIndex(conns)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		index.Match([]string{"server-04", "10.0.1"}[i%2])
+	}
+}
+
 func TestNewConnection(t *testing.T) {
 	conn := NewConnection()
 
@@ -159,8 +502,8 @@ func TestNewConnection(t *testing.T) {
 		t.Errorf("NewConnection port = %d, want 22", conn.Port)
 	}
 
-	if conn.AuthType != "" && conn.AuthMethod != AuthPassword {
-		t.Errorf("NewConnection AuthMethod = %v, want %v", conn.AuthMethod, AuthPassword)
+	if conn.AuthType != AuthPassword {
+		t.Errorf("NewConnection AuthType = %v, want %v", conn.AuthType, AuthPassword)
 	}
 
 	if conn.LastStatus != ConnStatusUnknown {
@@ -168,6 +511,51 @@ func TestNewConnection(t *testing.T) {
 	}
 }
 
+func TestConnectionUnmarshalLegacyAuth(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want AuthType
+	}{
+		{"auth_type", "name: a\nauth_type: key\n", AuthKey},
+		{"legacy auth_method", "name: a\nauth_method: key\n", AuthKey},
+		{"auth_method wins", "name: a\nauth_type: key\nauth_method: password\n", AuthPassword},
+		{"empty auth_method", "name: a\nauth_type: key\nauth_method: \"\"\n", AuthKey},
+		{"empty auth_type", "name: a\nauth_type: \"\"\nauth_method: key\n", AuthKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conn Connection
+			if err := yaml.Unmarshal([]byte(tt.yaml), &conn); err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+			if conn.Name != "a" || conn.AuthType != tt.want {
+				t.Errorf("got name %q auth %q, want %q", conn.Name, conn.AuthType, tt.want)
+			}
+		})
+	}
+}
+
+func TestOlderVersion(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"1.0", "1.2", true},
+		{"1.1", "1.2", true},
+		{"", "1.2", true},
+		{"1.2", "1.2", false},
+		{"1.10", "1.2", false},
+		{"2.0", "1.2", false},
+	}
+	for _, tt := range tests {
+		if got := OlderVersion(tt.a, tt.b); got != tt.want {
+			t.Errorf("OlderVersion(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
 func TestNewSettings(t *testing.T) {
 	settings := NewSettings()
 
@@ -230,6 +618,300 @@ func TestSettingsNeedsUnlock(t *testing.T) {
 	}
 }
 
+func TestConnectionSSHCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		conn Connection
+		want string
+	}{
+		{
+			name: "default port",
+			conn: Connection{Host: "example.com", User: "root", Port: 22},
+			want: "ssh root@example.com",
+		},
+		{
+			name: "custom port",
+			conn: Connection{Host: "10.0.0.1", User: "deploy", Port: 2222},
+			want: "ssh -p 2222 deploy@10.0.0.1",
+		},
+		{
+			name: "key auth",
+			conn: Connection{Host: "10.0.0.1", User: "deploy", Port: 22, AuthType: AuthKey, KeyPath: "~/.ssh/id_ed25519"},
+			want: "ssh -i ~/.ssh/id_ed25519 deploy@10.0.0.1",
+		},
+		{
+			name: "quoted key path",
+			conn: Connection{Host: "10.0.0.1", User: "me", Port: 22, AuthType: AuthKey, KeyPath: "/keys/my key"},
+			want: "ssh -i '/keys/my key' me@10.0.0.1",
+		},
+		{
+			name: "term and locale",
+			conn: Connection{Host: "10.0.0.1", User: "admin", Port: 22, Term: "vt100", Locale: "C"},
+			want: "TERM=vt100 ssh -o 'SetEnv=LANG=C LC_ALL=C' admin@10.0.0.1",
+		},
+		{
+			name: "gssapi",
+			conn: Connection{Host: "10.0.0.1", User: "deploy", Port: 22, GSSAPI: true},
+			want: "ssh -o GSSAPIAuthentication=yes deploy@10.0.0.1",
+		},
+		{
+			name: "jump hosts",
+			conn: Connection{Host: "10.0.0.1", User: "root", Port: 22, Jumps: []Connection{
+				{Host: "bastion.example.com", User: "ops", Port: 22},
+				{Host: "::1", User: "ops", Port: 2222},
+			}},
+			want: "ssh -J 'ops@bastion.example.com,ops@[::1]:2222' root@10.0.0.1",
+		},
+		{
+			name: "bind address",
+			conn: Connection{Host: "10.0.0.1", User: "root", Port: 22, BindAddress: "192.168.1.20"},
+			want: "ssh -b 192.168.1.20 root@10.0.0.1",
+		},
+		{
+			name: "bind interface",
+			conn: Connection{Host: "10.0.0.1", User: "root", Port: 22, BindAddress: "wg0"},
+			want: "ssh -B wg0 root@10.0.0.1",
+		},
+		{
+			name: "telnet",
+			conn: Connection{Host: "10.0.0.2", Port: 23, Type: ConnTypeTelnet},
+			want: "telnet 10.0.0.2 23",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.conn.SSHCommand(); got != tt.want {
+				t.Errorf("SSHCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConnectionCredentials(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	changed := now.AddDate(0, 0, -100)
+	past := now.AddDate(0, 0, -1)
+	future := now.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name        string
+		conn        Connection
+		defaultDays int
+		want        CredentialState
+	}{
+		{
+			name: "no policy",
+			conn: Connection{PasswordChangedAt: &changed},
+			want: CredentialOK,
+		},
+		{
+			name: "within own rotation period",
+			conn: Connection{PasswordChangedAt: &changed, PasswordRotateAfter: 120},
+			want: CredentialOK,
+		},
+		{
+			name: "past own rotation period",
+			conn: Connection{PasswordChangedAt: &changed, PasswordRotateAfter: 90},
+			want: CredentialRotate,
+		},
+		{
+			name:        "past default rotation period",
+			conn:        Connection{PasswordChangedAt: &changed},
+			defaultDays: 90,
+			want:        CredentialRotate,
+		},
+		{
+			name:        "own period overrides default",
+			conn:        Connection{PasswordChangedAt: &changed, PasswordRotateAfter: 365},
+			defaultDays: 90,
+			want:        CredentialOK,
+		},
+		{
+			name: "falls back to creation time",
+			conn: Connection{CreatedAt: changed, PasswordRotateAfter: 90},
+			want: CredentialRotate,
+		},
+		{
+			name: "expired",
+			conn: Connection{PasswordChangedAt: &changed, ExpiresAt: &past},
+			want: CredentialExpired,
+		},
+		{
+			name: "expiry wins over rotation",
+			conn: Connection{PasswordChangedAt: &changed, PasswordRotateAfter: 90, ExpiresAt: &past},
+			want: CredentialExpired,
+		},
+		{
+			name: "not yet expired",
+			conn: Connection{PasswordChangedAt: &changed, ExpiresAt: &future},
+			want: CredentialOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.conn.Credentials(now, tt.defaultDays)
+			if got != tt.want {
+				t.Errorf("Credentials() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnlockAttemptsDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{FreeUnlockAttempts - 1, 0},
+		{FreeUnlockAttempts, 10 * time.Second},
+		{FreeUnlockAttempts + 1, 20 * time.Second},
+		{FreeUnlockAttempts + 3, 80 * time.Second},
+		{FreeUnlockAttempts + 100, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := (UnlockAttempts{Failures: tt.failures}).Delay(); got != tt.want {
+			t.Errorf("Delay() after %d failures = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+
+	now := time.Now()
+	a := UnlockAttempts{Failures: FreeUnlockAttempts, LastFailure: now.Add(-4 * time.Second)}
+	if got := a.Wait(now); got != 6*time.Second {
+		t.Errorf("Wait() 4s after the failure = %v, want 6s", got)
+	}
+	if got := a.Wait(now.Add(time.Minute)); got != 0 {
+		t.Errorf("Wait() after the delay = %v, want 0", got)
+	}
+}
+
+func TestSettingsNotify(t *testing.T) {
+	var s Settings
+	if s.NotifyMode() != NotifyBell {
+		t.Errorf("NotifyMode() = %q, want bell by default", s.NotifyMode())
+	}
+	if s.NotifyAfter() != DefaultNotifyAfterSeconds*time.Second {
+		t.Errorf("NotifyAfter() = %v, want default", s.NotifyAfter())
+	}
+
+	s.Notify = NotifyOff
+	s.NotifyAfterSeconds = 60
+	if s.NotifyMode() != NotifyOff || s.NotifyAfter() != time.Minute {
+		t.Errorf("NotifyMode(), NotifyAfter() = %q, %v; want off, 1m", s.NotifyMode(), s.NotifyAfter())
+	}
+}
+
+func TestSettingsListLayout(t *testing.T) {
+	var s Settings
+	if s.Layout() != LayoutLines {
+		t.Errorf("Layout() = %q, want lines by default", s.Layout())
+	}
+	if got := s.TableColumns(); !slices.Equal(got, ListColumns) {
+		t.Errorf("TableColumns() = %v, want all columns by default", got)
+	}
+
+	// Display order is fixed, the name is always shown and unknown
+	// columns are dropped
+	s.ListColumns = []ListColumn{ColumnStatus, ColumnHost, "uptime"}
+	want := []ListColumn{ColumnName, ColumnHost, ColumnStatus}
+	if got := s.TableColumns(); !slices.Equal(got, want) {
+		t.Errorf("TableColumns() = %v, want %v", got, want)
+	}
+}
+
+func TestSortConnections(t *testing.T) {
+	earlier := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+	conns := []Connection{
+		{Name: "web", Host: "b.example.com", Group: "Prod", LastConnected: &earlier, LastStatus: ConnStatusFailed},
+		{Name: "DB", Host: "a.example.com", Group: "Prod", LastConnected: &later, LastStatus: ConnStatusSuccess},
+		{Name: "cache", Host: "c.example.com", LastStatus: ConnStatusUnknown},
+	}
+	names := func() []string {
+		var names []string
+		for _, c := range conns {
+			names = append(names, c.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		by   ListColumn
+		desc bool
+		want []string
+	}{
+		{ColumnName, false, []string{"cache", "DB", "web"}},
+		{ColumnName, true, []string{"web", "DB", "cache"}},
+		{ColumnHost, false, []string{"DB", "web", "cache"}},
+		{ColumnGroup, false, []string{"cache", "DB", "web"}},
+		{ColumnLastSeen, true, []string{"DB", "web", "cache"}},
+		{ColumnStatus, false, []string{"DB", "web", "cache"}},
+	}
+	for _, tt := range tests {
+		SortConnections(conns, tt.by, tt.desc)
+		if got := names(); !slices.Equal(got, tt.want) {
+			t.Errorf("SortConnections(%s, desc=%v) = %v, want %v", tt.by, tt.desc, got, tt.want)
+		}
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	c := Connection{Host: "web.example.com", Port: 2222, Addresses: []string{"10.8.0.5", " 2001:db8::5 ", "web.example.com", ""}}
+	want := []string{"web.example.com:2222", "10.8.0.5:2222", "[2001:db8::5]:2222"}
+
+	got := c.Candidates()
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Candidates() = %v, want %v", got, want)
+	}
+}
+
+func TestResolveJumps(t *testing.T) {
+	all := []Connection{
+		{ID: "1", Name: "edge"},
+		{ID: "2", Name: "inner", JumpHosts: []string{"edge"}},
+		{ID: "3", Name: "db", JumpHosts: []string{"Inner"}},
+		{ID: "4", Name: "loop-a", JumpHosts: []string{"loop-b"}},
+		{ID: "5", Name: "loop-b", JumpHosts: []string{"loop-a"}},
+	}
+
+	hops, err := ResolveJumps(all[2], all)
+	if err != nil {
+		t.Fatalf("ResolveJumps() error = %v", err)
+	}
+	if len(hops) != 2 || hops[0].Name != "edge" || hops[1].Name != "inner" {
+		t.Errorf("ResolveJumps() = %v, want edge then inner", hops)
+	}
+
+	if _, err := ResolveJumps(all[3], all); !errors.Is(err, ErrJumpLoop) {
+		t.Errorf("ResolveJumps() error = %v, want ErrJumpLoop", err)
+	}
+	if _, err := ResolveJumps(Connection{JumpHosts: []string{"nowhere"}}, all); !errors.Is(err, ErrJumpNotFound) {
+		t.Errorf("ResolveJumps() error = %v, want ErrJumpNotFound", err)
+	}
+}
+
+func TestShortDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{42 * time.Second, "42s"},
+		{14*time.Minute + 30*time.Second, "14m"},
+		{2 * time.Hour, "2h"},
+		{2*time.Hour + 5*time.Minute, "2h5m"},
+		{27 * time.Hour, "1d3h"},
+		{48*time.Hour + 10*time.Minute, "2d"},
+	}
+
+	for _, tt := range tests {
+		if got := ShortDuration(tt.d); got != tt.want {
+			t.Errorf("ShortDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
 func TestConfigGetGroups(t *testing.T) {
 	cfg := Config{
 		Groups: []Group{
@@ -248,3 +930,93 @@ func TestConfigGetGroups(t *testing.T) {
 		t.Error("First group should be empty string (ungrouped)")
 	}
 }
+
+func TestWorkspaceValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		workspace Workspace
+		wantErr   error
+	}{
+		{"sessions", Workspace{Name: "w", Connections: []string{"web"}}, nil},
+		{"tunnel", Workspace{Name: "w", Tunnels: []WorkspaceTunnel{{Connection: "db", Type: "remote", Spec: "8080:localhost:80"}}}, nil},
+		{"no name", Workspace{Connections: []string{"web"}}, ErrNameRequired},
+		{"empty", Workspace{Name: "w", Snippets: []Snippet{{Name: "s", Command: "uptime"}}}, ErrWorkspaceEmpty},
+		{"tunnel type", Workspace{Name: "w", Tunnels: []WorkspaceTunnel{{Connection: "db", Type: "-L", Spec: "1:h:2"}}}, ErrInvalidTunnel},
+		{"snippet command", Workspace{Name: "w", Connections: []string{"web"}, Snippets: []Snippet{{Name: "s"}}}, ErrInvalidSnippet},
+	}
+	for _, tt := range tests {
+		if err := tt.workspace.Validate(); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: Validate() = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestForwardProfile(t *testing.T) {
+	db := ForwardProfile{Name: "db", Forwards: []ForwardSpec{{Type: "local", Spec: "5432:localhost:5432"}, {Type: "dynamic", Spec: "1080"}}}
+	tests := []struct {
+		name    string
+		profile ForwardProfile
+		wantErr error
+	}{
+		{"valid", db, nil},
+		{"no name", ForwardProfile{Forwards: db.Forwards}, ErrInvalidForwardProfile},
+		{"space in name", ForwardProfile{Name: "my db", Forwards: db.Forwards}, ErrInvalidForwardProfile},
+		{"no forwards", ForwardProfile{Name: "db"}, ErrInvalidForwardProfile},
+		{"forward type", ForwardProfile{Name: "db", Forwards: []ForwardSpec{{Type: "-L", Spec: "1:h:2"}}}, ErrInvalidForwardProfile},
+	}
+	for _, tt := range tests {
+		if err := tt.profile.Validate(); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: Validate() = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	var c Connection
+	c.SetForwardProfile(db)
+	c.SetForwardProfile(ForwardProfile{Name: "web", Forwards: []ForwardSpec{{Type: "local", Spec: "8080:localhost:80"}}})
+	c.SetForwardProfile(ForwardProfile{Name: "db", Forwards: db.Forwards[:1]})
+	if len(c.ForwardProfiles) != 2 {
+		t.Fatalf("SetForwardProfile kept %d profiles, want 2", len(c.ForwardProfiles))
+	}
+	if p, ok := c.ForwardProfile("db"); !ok || len(p.Forwards) != 1 {
+		t.Errorf("ForwardProfile(db) = %+v, %v, want the replaced profile", p, ok)
+	}
+	if _, ok := c.ForwardProfile("cache"); ok {
+		t.Error("ForwardProfile(cache) found a profile that was never saved")
+	}
+}
+
+func TestForwardProfileExpandHint(t *testing.T) {
+	tests := []struct {
+		hint  string
+		ports []int
+		want  string
+	}{
+		{"psql postgres://app@localhost:{port}/app", []int{5432}, "psql postgres://app@localhost:5432/app"},
+		{"curl -x socks5h://localhost:{port2} http://localhost:{port1}/", []int{8080, 1080}, "curl -x socks5h://localhost:1080 http://localhost:8080/"},
+		{"echo {port12} {port1}", []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, "echo 12 1"},
+		{"mysql -P {port3}", []int{3306}, "mysql -P {port3}"},
+	}
+	for _, tt := range tests {
+		if got := (ForwardProfile{Hint: tt.hint}).ExpandHint(tt.ports); got != tt.want {
+			t.Errorf("ExpandHint(%q, %v) = %q, want %q", tt.hint, tt.ports, got, tt.want)
+		}
+	}
+}
+
+func TestHostFactsOS(t *testing.T) {
+	tests := []struct {
+		facts HostFacts
+		want  string
+	}{
+		{HostFacts{OSID: "centos", OSName: "CentOS Linux", OSVersion: "7"}, "CentOS 7"},
+		{HostFacts{OSID: "debian", OSName: "Debian GNU/Linux", OSVersion: "12"}, "Debian 12"},
+		{HostFacts{OSID: "rhel", OSName: "Red Hat Enterprise Linux", OSVersion: "9.3"}, "RHEL 9.3"},
+		{HostFacts{OSID: "arch", OSName: "Arch Linux"}, "Arch"},
+		{HostFacts{OSID: "nixos"}, "nixos"},
+	}
+	for _, tt := range tests {
+		if got := tt.facts.OS(); got != tt.want {
+			t.Errorf("OS() of %+v = %q, want %q", tt.facts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+// Package monitor periodically probes connections and exposes the results
+// as Prometheus metrics
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gossh/internal/hooks"
+	"gossh/internal/i18n"
+	"gossh/internal/model"
+	"gossh/internal/ssh"
+)
+
+// ProbeFunc checks a connection and returns the handshake latency and
+// host key fingerprint
+type ProbeFunc func(conn model.Connection, timeout time.Duration) (time.Duration, string, error)
+
+// HandshakeProbe probes a connection with an unauthenticated SSH handshake.
+// Telnet connections have no handshake and only get a TCP connect.
+func HandshakeProbe(conn model.Connection, timeout time.Duration) (time.Duration, string, error) {
+	if conn.IsTelnet() {
+		start := time.Now()
+		if err := ssh.CheckConnection(conn, timeout); err != nil {
+			return 0, "", err
+		}
+		return time.Since(start), "", nil
+	}
+	return ssh.HandshakeConnection(conn, timeout)
+}
+
+// Result is the latest probe outcome for one connection
+type Result struct {
+	Connection  model.Connection
+	Up          bool
+	Latency     time.Duration
+	Error       error
+	ProbedAt    time.Time
+	LastSuccess time.Time
+	Fingerprint string
+	Probes      int
+	Failures    int
+	KeyChanges  int
+}
+
+// Monitor probes a set of connections on an interval
+type Monitor struct {
+	connections []model.Connection
+	interval    time.Duration
+	timeout     time.Duration
+	parallel    int
+	probe       ProbeFunc
+	hooks       *hooks.Dispatcher
+
+	mu      sync.RWMutex
+	results map[string]*Result
+}
+
+// NewMonitor creates a monitor for the given connections
+func NewMonitor(connections []model.Connection) *Monitor {
+	return &Monitor{
+		connections: connections,
+		interval:    30 * time.Second,
+		timeout:     5 * time.Second,
+		parallel:    10,
+		probe:       HandshakeProbe,
+		results:     make(map[string]*Result),
+	}
+}
+
+// SetInterval sets how often connections are probed
+func (m *Monitor) SetInterval(interval time.Duration) {
+	if interval > 0 {
+		m.interval = interval
+	}
+}
+
+// SetTimeout sets the per-probe timeout
+func (m *Monitor) SetTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		m.timeout = timeout
+	}
+}
+
+// SetProbe replaces the probe function
+func (m *Monitor) SetProbe(probe ProbeFunc) {
+	m.probe = probe
+}
+
+// SetHooks sets the dispatcher notified about health and host key changes
+func (m *Monitor) SetHooks(d *hooks.Dispatcher) {
+	m.hooks = d
+}
+
+// Run probes all connections immediately and then on every interval
+// until the context is cancelled
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.ProbeAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProbeAll probes every connection once
+func (m *Monitor) ProbeAll(ctx context.Context) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, m.parallel)
+
+	for _, conn := range m.connections {
+		wg.Add(1)
+		go func(c model.Connection) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			latency, fingerprint, err := m.probe(c, m.timeout)
+			for _, p := range m.record(c, latency, fingerprint, err) {
+				for _, hookErr := range m.hooks.Fire(p) {
+					fmt.Fprintf(os.Stderr, i18n.T("cli.hook.failed")+"\n", hookErr)
+				}
+			}
+		}(conn)
+	}
+
+	wg.Wait()
+}
+
+// record stores a probe outcome and returns the events it triggers
+func (m *Monitor) record(conn model.Connection, latency time.Duration, fingerprint string, err error) []hooks.Payload {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.results[conn.ID]
+	if !ok {
+		r = &Result{}
+		m.results[conn.ID] = r
+	}
+
+	previous := model.ConnStatus("")
+	if r.Probes > 0 {
+		previous = status(r.Up)
+	}
+
+	r.Connection = conn
+	r.ProbedAt = time.Now()
+	r.Probes++
+	r.Up = err == nil
+	r.Error = err
+
+	var events []hooks.Payload
+	if p, changed := hooks.HealthChange(conn, previous, status(r.Up), err); changed {
+		events = append(events, p)
+	}
+
+	if err != nil {
+		r.Failures++
+		r.Latency = 0
+		return events
+	}
+	r.Latency = latency
+	r.LastSuccess = r.ProbedAt
+
+	if r.Fingerprint != "" && fingerprint != r.Fingerprint {
+		r.KeyChanges++
+		events = append(events, hooks.HostKeyChange(conn, r.Fingerprint, fingerprint))
+	}
+	r.Fingerprint = fingerprint
+
+	return events
+}
+
+func status(up bool) model.ConnStatus {
+	if up {
+		return model.ConnStatusSuccess
+	}
+	return model.ConnStatusFailed
+}
+
+// Results returns the latest probe results sorted by connection name
+func (m *Monitor) Results() []Result {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	results := make([]Result, 0, len(m.results))
+	for _, r := range m.results {
+		results = append(results, *r)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Connection.Name < results[j].Connection.Name
+	})
+	return results
+}
+
+// metric describes one exposed metric family
+type metric struct {
+	name  string
+	help  string
+	kind  string
+	value func(r Result) (float64, bool)
+}
+
+var metrics = []metric{
+	{"gossh_up", "Whether the last SSH handshake succeeded (1) or failed (0).", "gauge",
+		func(r Result) (float64, bool) {
+			if r.Up {
+				return 1, true
+			}
+			return 0, true
+		}},
+	{"gossh_handshake_duration_seconds", "Duration of the last successful SSH handshake.", "gauge",
+		func(r Result) (float64, bool) { return r.Latency.Seconds(), r.Up }},
+	{"gossh_last_probe_timestamp_seconds", "Unix time of the last probe.", "gauge",
+		func(r Result) (float64, bool) { return unixSeconds(r.ProbedAt), true }},
+	{"gossh_last_success_timestamp_seconds", "Unix time of the last successful probe.", "gauge",
+		func(r Result) (float64, bool) { return unixSeconds(r.LastSuccess), !r.LastSuccess.IsZero() }},
+	{"gossh_probes_total", "Total number of probes.", "counter",
+		func(r Result) (float64, bool) { return float64(r.Probes), true }},
+	{"gossh_probe_failures_total", "Total number of failed probes.", "counter",
+		func(r Result) (float64, bool) { return float64(r.Failures), true }},
+	{"gossh_host_key_changes_total", "Number of times the server presented a different host key.", "counter",
+		func(r Result) (float64, bool) { return float64(r.KeyChanges), true }},
+}
+
+// WriteMetrics writes the results in the Prometheus text exposition format
+func (m *Monitor) WriteMetrics(w io.Writer) error {
+	results := m.Results()
+
+	var b strings.Builder
+	for _, mt := range metrics {
+		fmt.Fprintf(&b, "# HELP %s %s\n", mt.name, mt.help)
+		fmt.Fprintf(&b, "# TYPE %s %s\n", mt.name, mt.kind)
+		for _, r := range results {
+			if v, ok := mt.value(r); ok {
+				fmt.Fprintf(&b, "%s{%s} %s\n", mt.name, labels(r.Connection), strconv.FormatFloat(v, 'f', -1, 64))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ServeHTTP serves the metrics endpoint
+func (m *Monitor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = m.WriteMetrics(w)
+}
+
+// Handler returns an HTTP handler exposing /metrics and a /healthz liveness check
+func (m *Monitor) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok\n")
+	})
+	return mux
+}
+
+// labels formats the label set identifying a connection
+func labels(conn model.Connection) string {
+	return fmt.Sprintf(`name="%s",host="%s",port="%d",group="%s"`,
+		escapeLabel(conn.Name), escapeLabel(conn.Host), conn.Port, escapeLabel(conn.Group))
+}
+
+// escapeLabel escapes a label value per the exposition format
+func escapeLabel(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `"`, `\"`)
+	return strings.ReplaceAll(v, "\n", `\n`)
+}
+
+func unixSeconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / 1e9
+}
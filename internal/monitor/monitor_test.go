@@ -0,0 +1,133 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gossh/internal/hooks"
+	"gossh/internal/model"
+)
+
+func testConnections() []model.Connection {
+	return []model.Connection{
+		{ID: "1", Name: "web", Host: "10.0.0.1", Port: 22, Group: "Production"},
+		{ID: "2", Name: "db", Host: "10.0.0.2", Port: 2222},
+	}
+}
+
+func fakeProbe(conn model.Connection, timeout time.Duration) (time.Duration, string, error) {
+	if conn.Name == "db" {
+		return 0, "", errors.New("connection refused")
+	}
+	return 250 * time.Millisecond, "ssh-ed25519 SHA256:abc", nil
+}
+
+func TestMonitorProbeAll(t *testing.T) {
+	m := NewMonitor(testConnections())
+	m.SetProbe(fakeProbe)
+
+	m.ProbeAll(context.Background())
+	m.ProbeAll(context.Background())
+
+	results := m.Results()
+	if len(results) != 2 {
+		t.Fatalf("Results() returned %d results, want 2", len(results))
+	}
+
+	// Sorted by name
+	db, web := results[0], results[1]
+	if db.Up || db.Failures != 2 || !db.LastSuccess.IsZero() {
+		t.Errorf("db result = %+v, want down with 2 failures", db)
+	}
+	if !web.Up || web.Probes != 2 || web.Latency != 250*time.Millisecond || web.LastSuccess.IsZero() {
+		t.Errorf("web result = %+v, want up with latency", web)
+	}
+}
+
+func TestMonitorWriteMetrics(t *testing.T) {
+	m := NewMonitor(testConnections())
+	m.SetProbe(fakeProbe)
+	m.ProbeAll(context.Background())
+
+	var b strings.Builder
+	if err := m.WriteMetrics(&b); err != nil {
+		t.Fatalf("WriteMetrics() error = %v", err)
+	}
+	out := b.String()
+
+	want := []string{
+		"# TYPE gossh_up gauge",
+		`gossh_up{name="web",host="10.0.0.1",port="22",group="Production"} 1`,
+		`gossh_up{name="db",host="10.0.0.2",port="2222",group=""} 0`,
+		`gossh_handshake_duration_seconds{name="web",host="10.0.0.1",port="22",group="Production"} 0.25`,
+		`gossh_probe_failures_total{name="db",host="10.0.0.2",port="2222",group=""} 1`,
+		"# TYPE gossh_probes_total counter",
+	}
+	for _, w := range want {
+		if !strings.Contains(out, w) {
+			t.Errorf("metrics missing %q\n%s", w, out)
+		}
+	}
+
+	// Failed hosts have no latency or last-success sample
+	if strings.Contains(out, `gossh_handshake_duration_seconds{name="db"`) {
+		t.Error("metrics should not report latency for a failed probe")
+	}
+	if strings.Contains(out, `gossh_last_success_timestamp_seconds{name="db"`) {
+		t.Error("metrics should not report last success for a host that never succeeded")
+	}
+}
+
+func TestMonitorHandler(t *testing.T) {
+	m := NewMonitor(testConnections())
+	m.SetProbe(fakeProbe)
+	m.ProbeAll(context.Background())
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if rec.Code != 200 {
+		t.Fatalf("GET /metrics status = %d, want 200", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q, want text/plain", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "gossh_up{") {
+		t.Error("response body missing gossh_up")
+	}
+}
+
+func TestMonitorEvents(t *testing.T) {
+	conn := model.Connection{ID: "1", Name: "web", Host: "10.0.0.1", Port: 22}
+	m := NewMonitor([]model.Connection{conn})
+
+	// First probe only establishes a baseline
+	if events := m.record(conn, time.Millisecond, "key-a", nil); len(events) != 0 {
+		t.Fatalf("first probe events = %+v, want none", events)
+	}
+
+	events := m.record(conn, 0, "", errors.New("timeout"))
+	if len(events) != 1 || events[0].Event != hooks.EventHealthChange {
+		t.Fatalf("down events = %+v, want one health change", events)
+	}
+
+	events = m.record(conn, time.Millisecond, "key-b", nil)
+	if len(events) != 2 || events[0].Event != hooks.EventHealthChange || events[1].Event != hooks.EventHostKeyChange {
+		t.Fatalf("recovery events = %+v, want health and host key change", events)
+	}
+	if got := m.Results()[0].KeyChanges; got != 1 {
+		t.Errorf("KeyChanges = %d, want 1", got)
+	}
+}
+
+func TestEscapeLabel(t *testing.T) {
+	got := escapeLabel("a\"b\\c\nd")
+	want := `a\"b\\c\nd`
+	if got != want {
+		t.Errorf("escapeLabel() = %q, want %q", got, want)
+	}
+}
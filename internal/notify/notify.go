@@ -0,0 +1,70 @@
+// Package notify tells the user that a long-running task has finished
+package notify
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"gossh/internal/model"
+)
+
+// Send notifies the user according to mode. Desktop notifications fall back
+// to the terminal bell when no notifier is available.
+func Send(mode model.NotifyMode, title, body string) error {
+	switch mode {
+	case model.NotifyOff:
+		return nil
+	case model.NotifyDesktop:
+		if err := desktop(title, body); err == nil {
+			return nil
+		}
+	}
+	return bell()
+}
+
+// desktop shows a native notification
+func desktop(title, body string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(body), appleScriptString(title))
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		script := fmt.Sprintf(`[void][Reflection.Assembly]::LoadWithPartialName('System.Windows.Forms');`+
+			`$n=New-Object System.Windows.Forms.NotifyIcon;$n.Icon=[System.Drawing.SystemIcons]::Information;`+
+			`$n.Visible=$true;$n.ShowBalloonTip(5000,'%s','%s','Info');Start-Sleep 6;$n.Dispose()`,
+			powerShellString(title), powerShellString(body))
+		cmd = exec.Command("powershell", "-NoProfile", "-Command", script)
+		// The balloon has to stay alive for a few seconds; don't wait for it
+		return cmd.Start()
+	default:
+		cmd = exec.Command("notify-send", "--app-name=gossh", title, body)
+	}
+	return cmd.Run()
+}
+
+// bell rings the terminal bell on the controlling terminal
+func bell() error {
+	var out io.Writer = os.Stderr
+	if tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0); err == nil {
+		defer tty.Close()
+		out = tty
+	}
+	_, err := out.Write([]byte("\a"))
+	return err
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// powerShellString escapes s for a single-quoted PowerShell string
+func powerShellString(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
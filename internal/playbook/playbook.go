@@ -0,0 +1,216 @@
+// Package playbook runs saved pipelines of commands: steps run one after
+// another, each on the hosts its target filter selects, in parallel. A step
+// can register what it printed on each host for later steps, which get it
+// as a variable, and run only where an earlier step succeeded or failed.
+package playbook
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"gossh/internal/ssh"
+)
+
+// DefaultTimeout is how long a step has on each host unless the playbook
+// or the step says otherwise, in seconds
+const DefaultTimeout = 30
+
+// Playbook is a pipeline of commands, read from YAML:
+//
+//	name: deploy
+//	targets:
+//	  group: web
+//	env:
+//	  APP: shop
+//	steps:
+//	  - name: Current release
+//	    command: readlink /srv/$APP/current
+//	    register: previous
+//	  - command: /srv/$APP/deploy.sh
+//	    register: deploy
+//	    continue_on_error: true
+//	  - name: Roll back
+//	    command: ln -sfn "$previous" /srv/$APP/current
+//	    when: deploy.failed
+type Playbook struct {
+	Name    string            `yaml:"name,omitempty"`
+	Targets *Targets          `yaml:"targets,omitempty"` // Hosts of the steps that name none
+	Timeout int               `yaml:"timeout,omitempty"` // Seconds a step has on each host
+	Env     map[string]string `yaml:"env,omitempty"`     // Variables set for every step
+	Steps   []Step            `yaml:"steps"`
+}
+
+// Step is one command of a playbook
+type Step struct {
+	Name            string   `yaml:"name,omitempty"`
+	Command         string   `yaml:"command"`
+	Targets         *Targets `yaml:"targets,omitempty"`
+	Timeout         int      `yaml:"timeout,omitempty"`
+	ContinueOnError bool     `yaml:"continue_on_error,omitempty"` // Go on with the next steps when a host fails
+	Register        string   `yaml:"register,omitempty"`          // Variable later steps get the output in
+	When            string   `yaml:"when,omitempty"`              // <register>.succeeded or <register>.failed
+
+	when condition
+}
+
+// Title returns the name of the step, or its command when it has none
+func (s Step) Title() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Command
+}
+
+// Targets selects the hosts a step runs on, like the target flags of gossh
+// exec
+type Targets struct {
+	Group        string   `yaml:"group,omitempty"`
+	Tags         []string `yaml:"tags,omitempty"`
+	Names        []string `yaml:"names,omitempty"`
+	Match        string   `yaml:"match,omitempty"`
+	ExcludeGroup string   `yaml:"exclude_group,omitempty"`
+	ExcludeTags  []string `yaml:"exclude_tags,omitempty"`
+	ExcludeNames []string `yaml:"exclude_names,omitempty"`
+}
+
+// Filter returns the target filter of t
+func (t Targets) Filter() (ssh.TargetFilter, error) {
+	f := ssh.TargetFilter{
+		Group:        t.Group,
+		Tags:         t.Tags,
+		Names:        t.Names,
+		ExcludeGroup: t.ExcludeGroup,
+		ExcludeTags:  t.ExcludeTags,
+		ExcludeNames: t.ExcludeNames,
+	}
+	if t.Match != "" {
+		re, err := regexp.Compile(t.Match)
+		if err != nil {
+			return ssh.TargetFilter{}, fmt.Errorf("invalid match pattern: %w", err)
+		}
+		f.Match = re
+	}
+	return f, nil
+}
+
+// condition is a parsed when: the hosts where a registered step succeeded,
+// or failed
+type condition struct {
+	register string
+	failed   bool
+}
+
+// holds reports whether the condition holds on a host where the step it
+// names ended with err
+func (c condition) holds(err error) bool {
+	return (err != nil) == c.failed
+}
+
+// Load reads the playbook at path
+func Load(path string) (*Playbook, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if p.Name == "" {
+		p.Name = strings.TrimSuffix(strings.TrimSuffix(filepath.Base(path), ".yaml"), ".yml")
+	}
+	return p, nil
+}
+
+// Parse parses and checks a playbook. Unknown fields are errors, so a
+// misspelled one does not go unnoticed.
+func Parse(data []byte) (*Playbook, error) {
+	var p Playbook
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("playbook has no steps")
+		}
+		return nil, err
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// validate checks the playbook and parses its conditions
+func (p *Playbook) validate() error {
+	if len(p.Steps) == 0 {
+		return errors.New("playbook has no steps")
+	}
+	if p.Timeout < 0 {
+		return errors.New("timeout must be positive")
+	}
+	if p.Targets != nil {
+		if _, err := p.Targets.Filter(); err != nil {
+			return fmt.Errorf("targets: %w", err)
+		}
+	}
+	for name, value := range p.Env {
+		if _, _, err := ssh.ParseEnv(name + "=" + value); err != nil {
+			return fmt.Errorf("env: %w", err)
+		}
+	}
+
+	registered := make(map[string]bool)
+	for i := range p.Steps {
+		s := &p.Steps[i]
+		if err := s.validate(registered); err != nil {
+			return fmt.Errorf("step %d (%s): %w", i+1, s.Title(), err)
+		}
+		if s.Register != "" {
+			registered[s.Register] = true
+		}
+	}
+	return nil
+}
+
+// validate checks a step, given the names registered by the steps before
+func (s *Step) validate(registered map[string]bool) error {
+	s.Command = strings.TrimSpace(s.Command)
+	if s.Command == "" {
+		return errors.New("command is required")
+	}
+	if s.Timeout < 0 {
+		return errors.New("timeout must be positive")
+	}
+	if s.Targets != nil {
+		if _, err := s.Targets.Filter(); err != nil {
+			return fmt.Errorf("targets: %w", err)
+		}
+	}
+	if s.Register != "" {
+		// Registered output is passed on as a variable of that name
+		if _, _, err := ssh.ParseEnv(s.Register + "="); err != nil {
+			return fmt.Errorf("register: %q is not a valid variable name", s.Register)
+		}
+		if registered[s.Register] {
+			return fmt.Errorf("register: %q is registered by an earlier step", s.Register)
+		}
+	}
+	if s.When != "" {
+		name, state, _ := strings.Cut(strings.TrimSpace(s.When), ".")
+		switch {
+		case state != "succeeded" && state != "failed":
+			return fmt.Errorf("when: %q must be <register>.succeeded or <register>.failed", s.When)
+		case !registered[name]:
+			return fmt.Errorf("when: no earlier step registers %q", name)
+		}
+		s.when = condition{register: name, failed: state == "failed"}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package playbook
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const deploy = `
+targets:
+  group: web
+timeout: 60
+env:
+  APP: shop
+steps:
+  - name: Current release
+    command: readlink /srv/$APP/current
+    register: previous
+  - command: /srv/$APP/deploy.sh
+    register: deploy
+    continue_on_error: true
+    timeout: 300
+  - name: Roll back
+    command: ln -sfn "$previous" /srv/$APP/current
+    when: deploy.failed
+    targets:
+      names: ["web-*"]
+      exclude_names: [web-canary]
+`
+
+func TestParse(t *testing.T) {
+	p, err := Parse([]byte(deploy))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(p.Steps) != 3 || p.Env["APP"] != "shop" {
+		t.Fatalf("Parse() = %+v", p)
+	}
+	if got := p.Steps[1].Title(); got != "/srv/$APP/deploy.sh" {
+		t.Errorf("Title() of an unnamed step = %q, want its command", got)
+	}
+	if w := p.Steps[2].when; w.register != "deploy" || !w.failed {
+		t.Errorf("condition = %+v, want deploy failed", w)
+	}
+
+	if f := p.Filter(p.Steps[0]); f.Group != "web" {
+		t.Errorf("Filter() of a step without targets = %+v, want the playbook's", f)
+	}
+	if f := p.Filter(p.Steps[2]); f.Group != "" || len(f.Names) != 1 || len(f.ExcludeNames) != 1 {
+		t.Errorf("Filter() of a step with targets = %+v, want its own", f)
+	}
+	if got := p.StepTimeout(p.Steps[0]); got != time.Minute {
+		t.Errorf("StepTimeout() = %v, want the playbook's 1m", got)
+	}
+	if got := p.StepTimeout(p.Steps[1]); got != 5*time.Minute {
+		t.Errorf("StepTimeout() = %v, want the step's 5m", got)
+	}
+	if got := (&Playbook{}).StepTimeout(Step{}); got != DefaultTimeout*time.Second {
+		t.Errorf("StepTimeout() = %v, want the default", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"empty", ``, "no steps"},
+		{"no steps", "name: x\n", "no steps"},
+		{"no command", "steps:\n  - name: x\n", "command is required"},
+		{"misspelled field", "steps:\n  - command: x\n    continue-on-error: true\n", "continue-on-error"},
+		{"bad match", "steps:\n  - command: x\n    targets: {match: '('}\n", "match pattern"},
+		{"bad register", "steps:\n  - command: x\n    register: my-var\n", "variable name"},
+		{"register twice", "steps:\n  - {command: x, register: a}\n  - {command: y, register: a}\n", "earlier step"},
+		{"bad when", "steps:\n  - {command: x, register: a}\n  - {command: y, when: a}\n", "succeeded"},
+		{"when later", "steps:\n  - {command: y, when: a.failed}\n  - {command: x, register: a}\n", "no earlier step"},
+		{"bad env", "env: {my-var: x}\nsteps:\n  - command: x\n", "env"},
+		{"negative timeout", "steps:\n  - {command: x, timeout: -1}\n", "timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadNamesAfterFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deploy.yaml")
+	if err := os.WriteFile(path, []byte("steps:\n  - command: uptime\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if p.Name != "deploy" {
+		t.Errorf("Name = %q, want the file name", p.Name)
+	}
+}
@@ -0,0 +1,148 @@
+package playbook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gossh/internal/model"
+	"gossh/internal/ssh"
+)
+
+// StepError is the error of a play stopped by a step failing on some hosts
+type StepError struct {
+	Step   int // From 1
+	Title  string
+	Failed int // Hosts it failed on
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("step %d (%s) failed on %d host(s)", e.Step, e.Title, e.Failed)
+}
+
+// StepResult is what running a step did
+type StepResult struct {
+	Index   int // From 0
+	Step    Step
+	Skipped []model.Connection // Targeted hosts its condition left out
+	Results []ssh.BatchResult
+}
+
+// Failed returns how many hosts the step failed on
+func (r StepResult) Failed() int {
+	failed := 0
+	for _, res := range r.Results {
+		if res.Error != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Exec runs command on hosts with a per-host timeout, setting env on every
+// host and hostEnv on those it has an entry for, by ssh.TargetKey, like
+// ssh.BatchExecutor
+type Exec func(ctx context.Context, command string, timeout time.Duration, hosts []model.Connection, env map[string]string, hostEnv map[string]map[string]string) []ssh.BatchResult
+
+// Runner runs playbooks
+type Runner struct {
+	Targets func(ssh.TargetFilter) []model.Connection // Hosts a filter selects
+	Exec    Exec
+
+	// Start and Finish are called, when set, before a step runs on the
+	// hosts given and after it ran
+	Start  func(index int, step Step, hosts []model.Connection)
+	Finish func(StepResult)
+}
+
+// Filter returns the target filter of a step of p: its own, or else the
+// playbook's. Neither selects every host.
+func (p *Playbook) Filter(s Step) ssh.TargetFilter {
+	targets := s.Targets
+	if targets == nil {
+		targets = p.Targets
+	}
+	if targets == nil {
+		return ssh.TargetFilter{}
+	}
+	// Checked by Parse
+	f, _ := targets.Filter()
+	return f
+}
+
+// StepTimeout returns how long a step of p has on each host
+func (p *Playbook) StepTimeout(s Step) time.Duration {
+	switch {
+	case s.Timeout > 0:
+		return time.Duration(s.Timeout) * time.Second
+	case p.Timeout > 0:
+		return time.Duration(p.Timeout) * time.Second
+	}
+	return DefaultTimeout * time.Second
+}
+
+// Run runs the steps of p in order, returning what each that ran did. A
+// step failing on any host stops the play with a *StepError, unless it
+// continues on error; so does ctx being canceled, with its error.
+func (r Runner) Run(ctx context.Context, p *Playbook) ([]StepResult, error) {
+	// What registering steps printed, by register and ssh.TargetKey, as
+	// the instances of a service share its connection ID
+	registered := make(map[string]map[string]ssh.BatchResult)
+	var done []StepResult
+
+	for i, step := range p.Steps {
+		if err := ctx.Err(); err != nil {
+			return done, err
+		}
+		result := StepResult{Index: i, Step: step}
+
+		var hosts []model.Connection
+		for _, conn := range r.Targets(p.Filter(step)) {
+			if step.when.register != "" {
+				res, ok := registered[step.when.register][ssh.TargetKey(conn)]
+				if !ok || !step.when.holds(res.Error) {
+					result.Skipped = append(result.Skipped, conn)
+					continue
+				}
+			}
+			hosts = append(hosts, conn)
+		}
+
+		// Each host gets what the registering steps printed there
+		hostEnv := make(map[string]map[string]string)
+		for name, byHost := range registered {
+			for key, res := range byHost {
+				if hostEnv[key] == nil {
+					hostEnv[key] = make(map[string]string)
+				}
+				hostEnv[key][name] = strings.TrimRight(res.Output, "\r\n")
+			}
+		}
+
+		if r.Start != nil {
+			r.Start(i, step, hosts)
+		}
+		if len(hosts) > 0 {
+			result.Results = r.Exec(ctx, step.Command, p.StepTimeout(step), hosts, p.Env, hostEnv)
+		}
+		done = append(done, result)
+		if r.Finish != nil {
+			r.Finish(result)
+		}
+
+		if step.Register != "" {
+			registered[step.Register] = make(map[string]ssh.BatchResult, len(result.Results))
+			for _, res := range result.Results {
+				registered[step.Register][ssh.TargetKey(res.Connection)] = res
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return done, err
+		}
+		if failed := result.Failed(); failed > 0 && !step.ContinueOnError {
+			return done, &StepError{Step: i + 1, Title: step.Title(), Failed: failed}
+		}
+	}
+	return done, nil
+}
@@ -0,0 +1,180 @@
+package playbook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gossh/internal/discovery"
+	"gossh/internal/model"
+	"gossh/internal/ssh"
+)
+
+// fakeHosts runs commands on hosts by name: "fail" fails on the hosts named
+// in failOn, anything else prints the host's name
+type fakeHosts struct {
+	conns  []model.Connection
+	failOn map[string]bool
+	ran    []string // Command@host in order, hosts sorted
+	env    map[string]map[string]string
+}
+
+func newFakeHosts(names ...string) *fakeHosts {
+	f := &fakeHosts{failOn: make(map[string]bool), env: make(map[string]map[string]string)}
+	for _, name := range names {
+		f.conns = append(f.conns, model.Connection{ID: "id-" + name, Name: name, Group: "web"})
+	}
+	return f
+}
+
+func (f *fakeHosts) runner() Runner {
+	return Runner{
+		Targets: func(filter ssh.TargetFilter) []model.Connection { return filter.Apply(f.conns) },
+		Exec: func(_ context.Context, command string, _ time.Duration, hosts []model.Connection, env map[string]string, hostEnv map[string]map[string]string) []ssh.BatchResult {
+			var results []ssh.BatchResult
+			for _, h := range hosts {
+				f.ran = append(f.ran, command+"@"+h.Name)
+				merged := make(map[string]string)
+				for k, v := range env {
+					merged[k] = v
+				}
+				for k, v := range hostEnv[ssh.TargetKey(h)] {
+					merged[k] = v
+				}
+				f.env[command+"@"+h.Name] = merged
+
+				res := ssh.BatchResult{Connection: h, Output: h.Name + "\n"}
+				if command == "fail" && f.failOn[h.Name] {
+					res.Error, res.ExitCode = errors.New("exit status 1"), 1
+				}
+				results = append(results, res)
+			}
+			return results
+		},
+	}
+}
+
+func TestRunRegisterAndWhen(t *testing.T) {
+	hosts := newFakeHosts("web-1", "web-2", "db")
+	hosts.failOn["web-2"] = true
+	p, err := Parse([]byte(`
+targets: {group: web}
+env: {STAGE: prod}
+steps:
+  - {command: hostname, register: name, targets: {names: [web-1, web-2]}}
+  - {command: fail, register: check, continue_on_error: true, targets: {names: [web-1, web-2]}}
+  - {command: repair, when: check.failed}
+  - {command: report, when: check.succeeded}
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var started []int
+	r := hosts.runner()
+	r.Start = func(i int, _ Step, hosts []model.Connection) { started = append(started, len(hosts)) }
+	results, err := r.Run(context.Background(), p)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []string{"hostname@web-1", "hostname@web-2", "fail@web-1", "fail@web-2", "repair@web-2", "report@web-1"}
+	if !reflect.DeepEqual(hosts.ran, want) {
+		t.Errorf("ran %v, want %v", hosts.ran, want)
+	}
+	if !reflect.DeepEqual(started, []int{2, 2, 1, 1}) {
+		t.Errorf("Start got %v hosts per step, want 2, 2, 1, 1", started)
+	}
+	// db is in the group but never ran check, so neither condition holds there
+	if len(results) != 4 || len(results[2].Skipped) != 2 || results[1].Failed() != 1 {
+		t.Errorf("results = %+v", results)
+	}
+
+	env := hosts.env["repair@web-2"]
+	if env["name"] != "web-2" || env["check"] != "web-2" || env["STAGE"] != "prod" {
+		t.Errorf("env of a later step = %v, want the host's registered output and the playbook's variables", env)
+	}
+}
+
+func TestRunRegisterServiceInstances(t *testing.T) {
+	consul := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`[{"Node": {"Address": "10.0.0.1"}, "Service": {"Port": 22}},
+			{"Node": {"Address": "10.0.0.2"}, "Service": {"Port": 22}}]`))
+	}))
+	defer consul.Close()
+	old := discovery.DefaultResolver.ConsulAddr
+	discovery.DefaultResolver.ConsulAddr = consul.URL
+	defer func() { discovery.DefaultResolver.ConsulAddr = old }()
+
+	// Both instances keep the service's connection ID
+	hosts := &fakeHosts{
+		conns:  []model.Connection{{ID: "id-web", Name: "web", Host: "consul:web", Port: 22}},
+		failOn: map[string]bool{"web/10.0.0.2": true},
+		env:    make(map[string]map[string]string),
+	}
+	r := hosts.runner()
+	r.Targets = func(filter ssh.TargetFilter) []model.Connection {
+		return ssh.ExpandServices(context.Background(), filter.Apply(hosts.conns))
+	}
+	p, err := Parse([]byte(`
+steps:
+  - {command: hostname, register: name}
+  - {command: fail, register: check, continue_on_error: true}
+  - {command: repair, when: check.failed}
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, err := r.Run(context.Background(), p); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []string{"hostname@web/10.0.0.1", "hostname@web/10.0.0.2", "fail@web/10.0.0.1", "fail@web/10.0.0.2", "repair@web/10.0.0.2"}
+	if !reflect.DeepEqual(hosts.ran, want) {
+		t.Errorf("ran %v, want %v", hosts.ran, want)
+	}
+	if got := hosts.env["repair@web/10.0.0.2"]["name"]; got != "web/10.0.0.2" {
+		t.Errorf("registered name on the second instance = %q, want its own output", got)
+	}
+}
+
+func TestRunStopsOnFailure(t *testing.T) {
+	hosts := newFakeHosts("web-1", "web-2")
+	hosts.failOn["web-1"] = true
+	p, err := Parse([]byte("steps:\n  - command: fail\n    name: Check\n  - command: deploy\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	results, err := hosts.runner().Run(context.Background(), p)
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) || stepErr.Step != 1 || stepErr.Failed != 1 || stepErr.Title != "Check" {
+		t.Fatalf("Run() error = %v, want step 1 failing on 1 host", err)
+	}
+	if len(results) != 1 || strings.Contains(strings.Join(hosts.ran, " "), "deploy") {
+		t.Errorf("ran %v after a failed step", hosts.ran)
+	}
+}
+
+func TestRunStopsWhenCanceled(t *testing.T) {
+	hosts := newFakeHosts("web-1")
+	p, err := Parse([]byte("steps:\n  - command: a\n  - command: b\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := hosts.runner()
+	r.Finish = func(StepResult) { cancel() }
+	if _, err := r.Run(ctx, p); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, want context.Canceled", err)
+	}
+	if !reflect.DeepEqual(hosts.ran, []string{"a@web-1"}) {
+		t.Errorf("ran %v, want only the first step", hosts.ran)
+	}
+}
@@ -0,0 +1,192 @@
+// Package plugins runs the executables users drop into the plugins
+// directory as gossh subcommands: gossh-deploy becomes gossh deploy. A
+// plugin gets what it runs on as JSON on stdin.
+package plugins
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+
+	"gossh/internal/model"
+)
+
+// Prefix starts the file name of every plugin
+const Prefix = "gossh-"
+
+// Version is the version of the Input document, raised when a field
+// changes meaning
+const Version = 1
+
+// Plugin is an executable in the plugins directory
+type Plugin struct {
+	Name string // The subcommand, the file name without Prefix and extension
+	Path string
+}
+
+// Trusted reports whether p is one of the plugins named in trusted, which
+// get the password and key passphrase of the connection they run on
+func (p Plugin) Trusted(trusted []string) bool {
+	return slices.Contains(trusted, p.Name)
+}
+
+// windowsExts are the file extensions run as plugins on Windows
+var windowsExts = []string{".exe", ".bat", ".cmd"}
+
+// List returns the plugins in dir, sorted by name. A missing directory
+// holds none.
+func List(dir string) ([]Plugin, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var plugins []Plugin
+	for _, entry := range entries {
+		name, ok := pluginName(dir, entry)
+		if !ok {
+			continue
+		}
+		plugins = append(plugins, Plugin{Name: name, Path: filepath.Join(dir, entry.Name())})
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins, nil
+}
+
+// Find returns the plugin in dir that runs as the subcommand name
+func Find(dir, name string) (Plugin, bool) {
+	plugins, err := List(dir)
+	if err != nil {
+		return Plugin{}, false
+	}
+	for _, p := range plugins {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Plugin{}, false
+}
+
+// pluginName returns the subcommand an entry of dir runs as, if it is an
+// executable file named like a plugin
+func pluginName(dir string, entry fs.DirEntry) (string, bool) {
+	name, ok := strings.CutPrefix(entry.Name(), Prefix)
+	if !ok {
+		return "", false
+	}
+	// Stat follows symlinks, the usual way to install a plugin kept elsewhere
+	info, err := os.Stat(filepath.Join(dir, entry.Name()))
+	if err != nil || !info.Mode().IsRegular() {
+		return "", false
+	}
+
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(name))
+		for _, e := range windowsExts {
+			if ext == e {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+				return name, name != ""
+			}
+		}
+		return "", false
+	}
+	if info.Mode()&0111 == 0 {
+		return "", false
+	}
+	return name, name != ""
+}
+
+// Input is the JSON document a plugin reads from stdin
+type Input struct {
+	Version    int         `json:"version"`
+	Plugin     string      `json:"plugin"`
+	Args       []string    `json:"args"`                 // Arguments after the subcommand
+	ConfigPath string      `json:"config_path"`          // The config file gossh uses, for gossh --config
+	Connection *Connection `json:"connection,omitempty"` // The selected connection, if any
+}
+
+// Connection is a saved connection as plugins see it. The password and
+// key passphrase are only filled in for plugins trusted with secrets.
+type Connection struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	Aliases       []string `json:"aliases,omitempty"`
+	Type          string   `json:"type"`
+	Host          string   `json:"host"`
+	Port          int      `json:"port"`
+	Addresses     []string `json:"addresses,omitempty"`
+	User          string   `json:"user"`
+	AuthType      string   `json:"auth_type"`
+	KeyPath       string   `json:"key_path,omitempty"`
+	Password      string   `json:"password,omitempty"`
+	KeyPassphrase string   `json:"key_passphrase,omitempty"`
+	Group         string   `json:"group,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+	JumpHosts     []string `json:"jump_hosts,omitempty"`
+	Notes         string   `json:"notes,omitempty"`
+}
+
+// NewConnection returns conn as plugins see it, with its secrets when
+// secrets is set
+func NewConnection(conn model.Connection, secrets bool) *Connection {
+	c := &Connection{
+		ID:        conn.ID,
+		Name:      conn.Name,
+		Aliases:   conn.Aliases,
+		Type:      string(conn.Type),
+		Host:      conn.Host,
+		Port:      conn.Port,
+		Addresses: conn.Addresses,
+		User:      conn.User,
+		AuthType:  string(conn.AuthType),
+		KeyPath:   conn.KeyPath,
+		Group:     conn.Group,
+		Tags:      conn.Tags,
+		JumpHosts: conn.JumpHosts,
+		Notes:     conn.Notes,
+	}
+	if c.Type == "" {
+		c.Type = string(model.ConnTypeSSH)
+	}
+	if secrets {
+		c.Password = conn.Password
+		c.KeyPassphrase = conn.KeyPassword
+	}
+	return c
+}
+
+// Command returns the command running p with input on stdin and the
+// terminal's output. The selected connection's name and host are in the
+// environment as GOSSH_CONNECTION and GOSSH_HOST too, as for hooks.
+func Command(p Plugin, input Input) (*exec.Cmd, error) {
+	input.Version = Version
+	input.Plugin = p.Name
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(p.Path, input.Args...)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "GOSSH_PLUGIN="+p.Name)
+	if input.Connection != nil {
+		cmd.Env = append(cmd.Env,
+			"GOSSH_CONNECTION="+input.Connection.Name,
+			"GOSSH_HOST="+input.Connection.Host,
+		)
+	}
+	return cmd, nil
+}
@@ -0,0 +1,107 @@
+package plugins
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"gossh/internal/model"
+)
+
+// writePlugin writes an executable shell script to dir
+func writePlugin(t *testing.T, dir, file, script string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, file), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatalf("Failed to write plugin: %v", err)
+	}
+}
+
+func TestList(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses executable bits")
+	}
+
+	dir := t.TempDir()
+	writePlugin(t, dir, "gossh-deploy", "exit 0")
+	writePlugin(t, dir, "gossh-backup", "exit 0")
+	writePlugin(t, dir, "other-tool", "exit 0")
+	if err := os.WriteFile(filepath.Join(dir, "gossh-notes"), []byte("not executable"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "gossh-dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	plugins, err := List(dir)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	var names []string
+	for _, p := range plugins {
+		names = append(names, p.Name)
+	}
+	if strings.Join(names, ",") != "backup,deploy" {
+		t.Errorf("List() = %v, want backup and deploy", names)
+	}
+
+	if p, ok := Find(dir, "deploy"); !ok || p.Path != filepath.Join(dir, "gossh-deploy") {
+		t.Errorf("Find(deploy) = %+v, %v", p, ok)
+	}
+	if _, ok := Find(dir, "notes"); ok {
+		t.Error("Find() found a file that is not executable")
+	}
+	if plugins, err := List(filepath.Join(dir, "missing")); err != nil || plugins != nil {
+		t.Errorf("List() of a missing directory = %v, %v, want none", plugins, err)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	writePlugin(t, dir, "gossh-dump", `printf '%s %s %s\n' "$GOSSH_PLUGIN" "$GOSSH_CONNECTION" "$*" > `+out+` && cat >> `+out)
+	p, _ := Find(dir, "dump")
+
+	conn := model.Connection{ID: "1", Name: "web", Host: "10.0.0.1", Port: 22, User: "deploy", Password: "s3cret"}
+	for _, secrets := range []bool{false, true} {
+		cmd, err := Command(p, Input{Args: []string{"web", "--dry-run"}, Connection: NewConnection(conn, secrets)})
+		if err != nil {
+			t.Fatalf("Command failed: %v", err)
+		}
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("plugin failed: %v", err)
+		}
+
+		data, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatalf("Failed to read plugin output: %v", err)
+		}
+		header, body, _ := strings.Cut(string(data), "\n")
+		if header != "dump web web --dry-run" {
+			t.Errorf("plugin environment and args = %q", header)
+		}
+		var input Input
+		if err := json.Unmarshal([]byte(body), &input); err != nil {
+			t.Fatalf("plugin input is not JSON: %v", err)
+		}
+		if input.Version != Version || input.Plugin != "dump" || input.Connection == nil || input.Connection.Host != "10.0.0.1" {
+			t.Errorf("plugin input = %+v", input)
+		}
+		if got := input.Connection.Password; (got != "") != secrets {
+			t.Errorf("password with secrets %v = %q", secrets, got)
+		}
+	}
+}
+
+func TestTrusted(t *testing.T) {
+	p := Plugin{Name: "deploy"}
+	if !p.Trusted([]string{"backup", "deploy"}) || p.Trusted(nil) {
+		t.Error("Trusted() does not follow the list of trusted plugins")
+	}
+}
@@ -0,0 +1,307 @@
+// Package report renders an inventory of connections as Markdown or HTML,
+// e.g. to attach to an audit
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gossh/internal/i18n"
+	"gossh/internal/model"
+)
+
+// Format is an output format of the report
+type Format string
+
+const (
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+// ParseFormat parses a format name: md, markdown, html or htm
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "md", "markdown":
+		return FormatMarkdown, nil
+	case "html", "htm":
+		return FormatHTML, nil
+	}
+	return "", fmt.Errorf("unknown report format: %s", name)
+}
+
+// FormatFor returns the format a file name's extension asks for, Markdown
+// unless it is .html or .htm
+func FormatFor(path string) Format {
+	if format, err := ParseFormat(strings.TrimPrefix(filepath.Ext(path), ".")); err == nil {
+		return format
+	}
+	return FormatMarkdown
+}
+
+// dateLayout formats the dates in the report
+const dateLayout = "2006-01-02"
+
+// Host is one connection's row in the report
+type Host struct {
+	Name        string
+	Address     string // user@host:port
+	Group       string
+	Tags        []string
+	LastSeen    *time.Time
+	Health      model.ConnStatus
+	Auth        string // Key type, "password" or "telnet"
+	Changed     time.Time
+	AgeDays     int
+	Credentials model.CredentialState
+	System      string // OS from the gathered facts
+}
+
+// Count is how many hosts have a group or tag
+type Count struct {
+	Name  string
+	Hosts int
+}
+
+// Report is the inventory of a set of connections
+type Report struct {
+	GeneratedAt time.Time
+	Hosts       []Host
+	Groups      []Count
+	Tags        []Count
+	Stale       int // Hosts whose credentials are due for rotation or expired
+}
+
+// KeyTypeFunc returns the type of the private key at path, e.g.
+// ssh-ed25519, or "" when it can't be read
+type KeyTypeFunc func(path string) string
+
+// New builds the report for connections at now. maxAge is the default
+// credential rotation period in days, zero for none.
+func New(connections []model.Connection, now time.Time, maxAge int, keyType KeyTypeFunc) Report {
+	r := Report{GeneratedAt: now}
+	groups := make(map[string]int)
+	tags := make(map[string]int)
+
+	for _, conn := range connections {
+		host := Host{
+			Name:        conn.Name,
+			Address:     conn.User + "@" + net.JoinHostPort(conn.Host, strconv.Itoa(conn.Port)),
+			Group:       conn.Group,
+			Tags:        conn.Tags,
+			LastSeen:    conn.LastConnected,
+			Health:      conn.HealthStatus,
+			Changed:     conn.CredentialsChangedAt(),
+			Credentials: conn.Credentials(now, maxAge),
+		}
+		if conn.IsTelnet() {
+			host.Address = net.JoinHostPort(conn.Host, strconv.Itoa(conn.Port))
+		}
+		if host.Health == "" {
+			host.Health = model.ConnStatusUnknown
+		}
+		host.AgeDays = int(now.Sub(host.Changed).Hours() / 24)
+
+		switch {
+		case conn.IsTelnet():
+			host.Auth = "telnet"
+		case conn.AuthType == model.AuthPassword:
+			host.Auth = "password"
+		default:
+			host.Auth = keyType(conn.KeyPath)
+			if host.Auth == "" {
+				host.Auth = "key"
+			}
+		}
+		if conn.Facts != nil {
+			host.System = conn.Facts.OS()
+		}
+		if host.Credentials != model.CredentialOK {
+			r.Stale++
+		}
+
+		if conn.Group != "" {
+			groups[conn.Group]++
+		}
+		for _, tag := range conn.Tags {
+			tags[tag]++
+		}
+		r.Hosts = append(r.Hosts, host)
+	}
+
+	r.Groups = counts(groups)
+	r.Tags = counts(tags)
+	return r
+}
+
+// counts sorts a count map by name
+func counts(m map[string]int) []Count {
+	result := make([]Count, 0, len(m))
+	for name, n := range m {
+		result = append(result, Count{Name: name, Hosts: n})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Write renders the report in format
+func (r Report) Write(w io.Writer, format Format) error {
+	if format == FormatHTML {
+		return r.HTML(w)
+	}
+	return r.Markdown(w)
+}
+
+// headers returns the titles of the host table columns
+func headers() []string {
+	return []string{
+		i18n.T("report.name"), i18n.T("report.address"), i18n.T("report.group"), i18n.T("report.tags"),
+		i18n.T("report.last_seen"), i18n.T("report.health"), i18n.T("report.auth"),
+		i18n.T("report.changed"), i18n.T("report.age"), i18n.T("report.credentials"), i18n.T("report.system"),
+	}
+}
+
+// cells returns the text of a host's table cells, in the order of headers
+func (h Host) cells() []string {
+	lastSeen := "-"
+	if h.LastSeen != nil {
+		lastSeen = h.LastSeen.Local().Format(dateLayout)
+	}
+	return []string{
+		h.Name, h.Address, orDash(h.Group), orDash(strings.Join(h.Tags, ", ")),
+		lastSeen, i18n.T("report.health." + string(h.Health)), h.Auth,
+		h.Changed.Local().Format(dateLayout), strconv.Itoa(h.AgeDays) + "d",
+		i18n.T("cli.audit.state." + string(h.Credentials)), orDash(h.System),
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// Markdown renders the report as a Markdown document
+func (r Report) Markdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", i18n.T("report.title"))
+	fmt.Fprintf(&b, i18n.T("report.summary")+"\n\n", r.GeneratedAt.Local().Format("2006-01-02 15:04"), len(r.Hosts), r.Stale)
+
+	fmt.Fprintf(&b, "## %s\n\n", i18n.T("report.hosts"))
+	rows := make([][]string, len(r.Hosts))
+	for i, h := range r.Hosts {
+		rows[i] = h.cells()
+	}
+	writeTable(&b, headers(), rows)
+
+	for _, section := range []struct {
+		title  string
+		counts []Count
+	}{
+		{i18n.T("report.groups"), r.Groups},
+		{i18n.T("report.tags"), r.Tags},
+	} {
+		if len(section.counts) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", section.title)
+		rows := make([][]string, len(section.counts))
+		for i, c := range section.counts {
+			rows[i] = []string{c.Name, strconv.Itoa(c.Hosts)}
+		}
+		writeTable(&b, []string{i18n.T("report.name"), i18n.T("report.hosts")}, rows)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeTable writes a Markdown table, escaping pipes in the cells
+func writeTable(b *strings.Builder, header []string, rows [][]string) {
+	escape := strings.NewReplacer("|", `\|`, "\n", " ")
+	row := func(cells []string) {
+		b.WriteString("|")
+		for _, c := range cells {
+			b.WriteString(" " + escape.Replace(c) + " |")
+		}
+		b.WriteString("\n")
+	}
+	row(header)
+	b.WriteString(strings.Repeat("| --- ", len(header)) + "|\n")
+	for _, cells := range rows {
+		row(cells)
+	}
+}
+
+// htmlTemplate is a standalone page, styled inline so it can be attached
+// as a single file
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+th { background: #f0f0f0; }
+.stale { color: #b00020; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Summary}}</p>
+<h2>{{.HostsTitle}}</h2>
+<table>
+<tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr{{if .Stale}} class="stale"{{end}}>{{range .Cells}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>
+{{range .Sections}}<h2>{{.Title}}</h2>
+<table>
+<tr><th>{{$.NameTitle}}</th><th>{{$.HostsTitle}}</th></tr>
+{{range .Counts}}<tr><td>{{.Name}}</td><td>{{.Hosts}}</td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+`))
+
+// HTML renders the report as a standalone HTML page
+func (r Report) HTML(w io.Writer) error {
+	type row struct {
+		Cells []string
+		Stale bool
+	}
+	type section struct {
+		Title  string
+		Counts []Count
+	}
+	data := struct {
+		Title, Summary, HostsTitle, NameTitle string
+		Headers                               []string
+		Rows                                  []row
+		Sections                              []section
+	}{
+		Title:      i18n.T("report.title"),
+		Summary:    fmt.Sprintf(i18n.T("report.summary"), r.GeneratedAt.Local().Format("2006-01-02 15:04"), len(r.Hosts), r.Stale),
+		HostsTitle: i18n.T("report.hosts"),
+		NameTitle:  i18n.T("report.name"),
+		Headers:    headers(),
+	}
+	for _, h := range r.Hosts {
+		data.Rows = append(data.Rows, row{Cells: h.cells(), Stale: h.Credentials != model.CredentialOK})
+	}
+	if len(r.Groups) > 0 {
+		data.Sections = append(data.Sections, section{i18n.T("report.groups"), r.Groups})
+	}
+	if len(r.Tags) > 0 {
+		data.Sections = append(data.Sections, section{i18n.T("report.tags"), r.Tags})
+	}
+	return htmlTemplate.Execute(w, data)
+}
@@ -0,0 +1,84 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"gossh/internal/model"
+)
+
+func testConnections(now time.Time) []model.Connection {
+	old := now.AddDate(0, 0, -200)
+	return []model.Connection{
+		{Name: "web|1", Host: "10.0.0.1", User: "deploy", Port: 22, AuthType: model.AuthKey, KeyPath: "/keys/id",
+			Group: "Production", Tags: []string{"web"}, HealthStatus: model.ConnStatusSuccess, CreatedAt: now,
+			Facts: &model.HostFacts{OSID: "centos", OSName: "CentOS Linux", OSVersion: "7"}},
+		{Name: "<db>", Host: "db.example.com", User: "root", Port: 2222, AuthType: model.AuthPassword,
+			Group: "Production", Tags: []string{"db", "web"}, CreatedAt: old},
+	}
+}
+
+func TestNew(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	r := New(testConnections(now), now, 90, func(path string) string {
+		if path == "/keys/id" {
+			return "ssh-ed25519"
+		}
+		return ""
+	})
+
+	if len(r.Hosts) != 2 || r.Stale != 1 {
+		t.Fatalf("New() = %d hosts, %d stale; want 2 hosts, 1 stale", len(r.Hosts), r.Stale)
+	}
+	web, db := r.Hosts[0], r.Hosts[1]
+	if web.Auth != "ssh-ed25519" || web.System != "CentOS 7" || web.Address != "deploy@10.0.0.1:22" {
+		t.Errorf("Hosts[0] = %+v", web)
+	}
+	if db.Auth != "password" || db.Health != model.ConnStatusUnknown || db.AgeDays != 200 || db.Credentials != model.CredentialRotate {
+		t.Errorf("Hosts[1] = %+v", db)
+	}
+	if len(r.Groups) != 1 || r.Groups[0] != (Count{"Production", 2}) {
+		t.Errorf("Groups = %+v, want Production with 2 hosts", r.Groups)
+	}
+	if len(r.Tags) != 2 || r.Tags[0] != (Count{"db", 1}) || r.Tags[1] != (Count{"web", 2}) {
+		t.Errorf("Tags = %+v, want db 1, web 2", r.Tags)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	r := New(testConnections(now), now, 0, func(string) string { return "" })
+
+	var md bytes.Buffer
+	if err := r.Write(&md, FormatMarkdown); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !strings.Contains(md.String(), `| web\|1 |`) {
+		t.Errorf("Markdown should escape pipes in cells, got:\n%s", md.String())
+	}
+
+	var html bytes.Buffer
+	if err := r.Write(&html, FormatHTML); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !strings.Contains(html.String(), "&lt;db&gt;") || strings.Contains(html.String(), "<db>") {
+		t.Errorf("HTML should escape cells, got:\n%s", html.String())
+	}
+}
+
+func TestFormatFor(t *testing.T) {
+	tests := map[string]Format{
+		"inventory.html": FormatHTML,
+		"inventory.HTM":  FormatHTML,
+		"inventory.md":   FormatMarkdown,
+		"inventory.txt":  FormatMarkdown,
+		"":               FormatMarkdown,
+	}
+	for path, want := range tests {
+		if got := FormatFor(path); got != want {
+			t.Errorf("FormatFor(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{spec: "* * * * *"},
+		{spec: "0 3 * * *"},
+		{spec: "*/15 9-17 * * mon-fri"},
+		{spec: "0,30 0 1,15 jan,jul 0"},
+		{spec: "5/10 * * * 7"},
+		{spec: "@daily"},
+		{spec: "@Hourly"},
+		{spec: "", wantErr: true},
+		{spec: "0 3 * *", wantErr: true},
+		{spec: "60 * * * *", wantErr: true},
+		{spec: "* 24 * * *", wantErr: true},
+		{spec: "* * 0 * *", wantErr: true},
+		{spec: "* * * 13 *", wantErr: true},
+		{spec: "* * * * 8", wantErr: true},
+		{spec: "*/0 * * * *", wantErr: true},
+		{spec: "10-5 * * * *", wantErr: true},
+		{spec: "* * * foo *", wantErr: true},
+		{spec: "@never", wantErr: true},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSpecNext(t *testing.T) {
+	// A Wednesday
+	from := time.Date(2026, time.March, 4, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, time.March, 4, 10, 8, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2026, time.March, 5, 3, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, time.March, 4, 10, 15, 0, 0, time.UTC)},
+		{"0 9-17 * * mon-fri", time.Date(2026, time.March, 4, 11, 0, 0, 0, time.UTC)},
+		{"0 0 * * sun", time.Date(2026, time.March, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, time.March, 8, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// Both days restricted: either matches, so the 10th or the next Friday
+		{"0 0 10 * fri", time.Date(2026, time.March, 6, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 feb *", time.Time{}},
+	}
+	for _, tt := range tests {
+		spec, err := Parse(tt.spec)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tt.spec, err)
+		}
+		if got := spec.Next(from); !got.Equal(tt.want) {
+			t.Errorf("Parse(%q).Next() = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestSpecMatches(t *testing.T) {
+	spec, err := Parse("30 2 * * 1-5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	monday := time.Date(2026, time.March, 2, 2, 30, 45, 0, time.UTC)
+	if !spec.Matches(monday) {
+		t.Errorf("Matches(%v) = false, want true", monday)
+	}
+	if sunday := monday.AddDate(0, 0, -1); spec.Matches(sunday) {
+		t.Errorf("Matches(%v) = true, want false", sunday)
+	}
+	if later := monday.Add(time.Minute); spec.Matches(later) {
+		t.Errorf("Matches(%v) = true, want false", later)
+	}
+}
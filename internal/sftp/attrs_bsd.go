@@ -0,0 +1,18 @@
+//go:build darwin || freebsd || netbsd
+
+package sftp
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// localAttrs returns the access time, owner and group of a local file
+func localAttrs(info os.FileInfo) (atime time.Time, uid, gid int, ok bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return info.ModTime(), 0, 0, false
+	}
+	return time.Unix(stat.Atimespec.Unix()), int(stat.Uid), int(stat.Gid), true
+}
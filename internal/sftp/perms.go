@@ -0,0 +1,245 @@
+package sftp
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gossh/internal/model"
+)
+
+// ParseMode applies a chmod mode to the current mode of a file: octal, like
+// 755, or symbolic, like u+x,go-w or a=rX
+func ParseMode(spec string, current os.FileMode) (os.FileMode, error) {
+	if spec == "" {
+		return 0, fmt.Errorf("empty mode")
+	}
+	if n, err := strconv.ParseUint(spec, 8, 32); err == nil {
+		if n > 0o7777 {
+			return 0, fmt.Errorf("invalid mode: %s", spec)
+		}
+		return octalMode(uint32(n)), nil
+	}
+
+	mode := unixMode(current)
+	for _, clause := range strings.Split(spec, ",") {
+		who := strings.IndexAny(clause, "+-=")
+		if who < 0 {
+			return 0, fmt.Errorf("invalid mode: %s", spec)
+		}
+		var mask uint32
+		for _, c := range clause[:who] {
+			switch c {
+			case 'u':
+				mask |= 0o4700
+			case 'g':
+				mask |= 0o2070
+			case 'o':
+				mask |= 0o1007
+			case 'a':
+				mask |= 0o7777
+			default:
+				return 0, fmt.Errorf("invalid mode: %s", spec)
+			}
+		}
+		if mask == 0 {
+			mask = 0o7777
+		}
+
+		// Each operator applies the permissions after it, e.g. u+x-w
+		rest := clause[who:]
+		for rest != "" {
+			op := rest[0]
+			end := strings.IndexAny(rest[1:], "+-=")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			perms := rest[1 : end+1]
+			rest = rest[end+1:]
+
+			var bits uint32
+			for _, c := range perms {
+				switch c {
+				case 'r':
+					bits |= 0o444
+				case 'w':
+					bits |= 0o222
+				case 'x':
+					bits |= 0o111
+				case 'X':
+					// Execute only for directories and what is already
+					// executable by someone
+					if current.IsDir() || mode&0o111 != 0 {
+						bits |= 0o111
+					}
+				case 's':
+					bits |= 0o6000
+				case 't':
+					bits |= 0o1000
+				default:
+					return 0, fmt.Errorf("invalid mode: %s", spec)
+				}
+			}
+			bits &= mask
+			switch op {
+			case '+':
+				mode |= bits
+			case '-':
+				mode &^= bits
+			case '=':
+				mode = mode&^mask | bits
+			}
+		}
+	}
+	return octalMode(mode), nil
+}
+
+// unixMode returns the permission bits of mode as chmod numbers them
+func unixMode(mode os.FileMode) uint32 {
+	bits := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		bits |= 0o4000
+	}
+	if mode&os.ModeSetgid != 0 {
+		bits |= 0o2000
+	}
+	if mode&os.ModeSticky != 0 {
+		bits |= 0o1000
+	}
+	return bits
+}
+
+// octalMode returns the file mode of chmod's numbered permission bits
+func octalMode(bits uint32) os.FileMode {
+	mode := os.FileMode(bits & 0o777)
+	if bits&0o4000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if bits&0o2000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if bits&0o1000 != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode
+}
+
+// ModeString formats a mode like ls and chmod both show it, e.g.
+// "rwxr-xr-x (0755)"
+func ModeString(mode os.FileMode) string {
+	perm := []byte(mode.Perm().String()[1:])
+	bits := unixMode(mode)
+	for _, special := range []struct {
+		bit uint32
+		pos int
+		set byte
+	}{{0o4000, 2, 's'}, {0o2000, 5, 's'}, {0o1000, 8, 't'}} {
+		if bits&special.bit == 0 {
+			continue
+		}
+		if perm[special.pos] == 'x' {
+			perm[special.pos] = special.set
+		} else {
+			perm[special.pos] = special.set - 'a' + 'A'
+		}
+	}
+	return fmt.Sprintf("%s (%04o)", perm, bits)
+}
+
+// Chmod changes the mode of a remote path, and of everything below it when
+// recursive, returning how many it changed. Symbolic modes apply to each
+// file's own mode. Links are left alone, as changing them would change
+// what they point to.
+func (c *Client) Chmod(remotePath, spec string, recursive bool) (int, error) {
+	changed := 0
+	err := c.walk(c.resolvePath(remotePath), recursive, func(p string, info os.FileInfo) error {
+		if isLink(info) {
+			return nil
+		}
+		mode, err := ParseMode(spec, info.Mode())
+		if err != nil {
+			return err
+		}
+		if err := c.sftpClient.Chmod(p, mode); err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		changed++
+		return nil
+	})
+	return changed, err
+}
+
+// Chown changes the owner, and the group after a colon, of a remote path
+// and of everything below it when recursive. SFTP only takes numeric IDs,
+// so names are handed to chown on the server. It returns how many paths
+// changed, or -1 when chown did the changing and did not say.
+func (c *Client) Chown(remotePath, owner string, recursive bool) (int, error) {
+	remotePath = c.resolvePath(remotePath)
+	userPart, groupPart, _ := strings.Cut(owner, ":")
+	uid, uidErr := strconv.Atoi(userPart)
+	gid, gidErr := strconv.Atoi(groupPart)
+	if userPart == "" && groupPart == "" {
+		return 0, fmt.Errorf("invalid owner: %s", owner)
+	}
+	if userPart != "" && uidErr != nil || groupPart != "" && gidErr != nil {
+		command := "chown "
+		if recursive {
+			command += "-R "
+		}
+		out, err := run(c.sshClient, command+"-- "+model.ShellQuote(owner)+" "+model.ShellQuote(remotePath))
+		if err != nil {
+			if out = strings.TrimSpace(out); out != "" {
+				return 0, fmt.Errorf("%w: %s", err, out)
+			}
+			return 0, err
+		}
+		return -1, nil
+	}
+
+	changed := 0
+	err := c.walk(remotePath, recursive, func(p string, info os.FileInfo) error {
+		if isLink(info) {
+			return nil
+		}
+		_, fileUID, fileGID, _ := remoteAttrs(info)
+		newUID, newGID := fileUID, fileGID
+		if userPart != "" {
+			newUID = uid
+		}
+		if groupPart != "" {
+			newGID = gid
+		}
+		if err := c.sftpClient.Chown(p, newUID, newGID); err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		changed++
+		return nil
+	})
+	return changed, err
+}
+
+// walk calls fn with a remote path, and when recursive with everything
+// below it, parents first. It does not follow links.
+func (c *Client) walk(remotePath string, recursive bool, fn func(string, os.FileInfo) error) error {
+	info, err := c.sftpClient.Lstat(remotePath)
+	if err != nil {
+		return err
+	}
+	if err := fn(remotePath, info); err != nil {
+		return err
+	}
+	if !recursive || !info.IsDir() {
+		return nil
+	}
+	entries, err := c.sftpClient.ReadDir(remotePath)
+	if err != nil {
+		return fmt.Errorf("failed to read directory: %w", err)
+	}
+	for _, e := range entries {
+		if err := c.walk(c.sftpClient.Join(remotePath, e.Name()), true, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
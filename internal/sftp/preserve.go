@@ -0,0 +1,139 @@
+package sftp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+)
+
+// TransferOptions chooses what a transfer keeps of a file besides its
+// content and mode, like scp -p and rsync -lot
+type TransferOptions struct {
+	Links bool // Recreate symbolic links as links instead of copying what they point to
+	Owner bool // Keep the numeric owner and group, when the receiving side is root
+	Times bool // Keep access and modification times
+}
+
+// ParsePreserve parses a comma separated list of links, owner and times,
+// or "all" for every one of them
+func ParsePreserve(list string) (TransferOptions, error) {
+	var opts TransferOptions
+	for _, name := range strings.Split(list, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "all":
+			opts = TransferOptions{Links: true, Owner: true, Times: true}
+		case "links":
+			opts.Links = true
+		case "owner":
+			opts.Owner = true
+		case "times":
+			opts.Times = true
+		default:
+			return TransferOptions{}, fmt.Errorf("unknown attribute to preserve: %s", name)
+		}
+	}
+	return opts, nil
+}
+
+// isLink reports whether info describes a symbolic link
+func isLink(info os.FileInfo) bool {
+	return info.Mode()&os.ModeSymlink != 0
+}
+
+// remoteAttrs returns the access time, owner and group of a remote file,
+// falling back to its modification time when the server sent no more
+func remoteAttrs(info os.FileInfo) (atime time.Time, uid, gid int, ok bool) {
+	stat, ok := info.Sys().(*sftp.FileStat)
+	if !ok {
+		return info.ModTime(), 0, 0, false
+	}
+	return time.Unix(int64(stat.Atime), 0), int(stat.UID), int(stat.GID), true
+}
+
+// keepRemote gives a remote file the owner, mode and times of the local
+// file info describes. The owner goes first, as changing it can clear
+// setuid bits, and only as root, who alone may give files away. A mode
+// that cannot be set is only warned about, as it always was.
+func keepRemote(client *sftp.Client, remotePath string, info os.FileInfo, opts TransferOptions, root bool) error {
+	if opts.Owner && root {
+		if _, uid, gid, ok := localAttrs(info); ok {
+			if err := client.Chown(remotePath, uid, gid); err != nil {
+				return fmt.Errorf("failed to set owner: %w", err)
+			}
+		}
+	}
+	if err := client.Chmod(remotePath, info.Mode()); err != nil {
+		fmt.Printf("Warning: failed to set permissions: %v\n", err)
+	}
+	if opts.Times {
+		atime, _, _, _ := localAttrs(info)
+		if err := client.Chtimes(remotePath, atime, info.ModTime()); err != nil {
+			return fmt.Errorf("failed to set times: %w", err)
+		}
+	}
+	return nil
+}
+
+// keepLocal gives a local file the owner, mode and times of the remote
+// file info describes, like keepRemote
+func keepLocal(localPath string, info os.FileInfo, opts TransferOptions) error {
+	atime, uid, gid, ok := remoteAttrs(info)
+	if opts.Owner && ok && os.Geteuid() == 0 {
+		if err := os.Chown(localPath, uid, gid); err != nil {
+			return fmt.Errorf("failed to set owner: %w", err)
+		}
+	}
+	if err := os.Chmod(localPath, info.Mode()); err != nil {
+		fmt.Printf("Warning: failed to set permissions: %v\n", err)
+	}
+	if opts.Times {
+		if err := os.Chtimes(localPath, atime, info.ModTime()); err != nil {
+			return fmt.Errorf("failed to set times: %w", err)
+		}
+	}
+	return nil
+}
+
+// uploadLink recreates a local symbolic link at a remote path, replacing
+// what is there. The target is copied as it is, so a relative link still
+// points within the tree it came with.
+func uploadLink(client *sftp.Client, localPath, remotePath string) error {
+	target, err := os.Readlink(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to read link: %w", err)
+	}
+	_ = client.Remove(remotePath)
+	if err := client.Symlink(filepath.ToSlash(target), remotePath); err != nil {
+		return fmt.Errorf("failed to create link: %w", err)
+	}
+	return nil
+}
+
+// downloadLink recreates a remote symbolic link at a local path like
+// uploadLink, keeping its owner when asked and running as root. SFTP
+// cannot set the times of a link, so they are not kept.
+func downloadLink(client *sftp.Client, remotePath, localPath string, info os.FileInfo, opts TransferOptions) error {
+	target, err := client.ReadLink(remotePath)
+	if err != nil {
+		return fmt.Errorf("failed to read link: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return fmt.Errorf("failed to create local directory: %w", err)
+	}
+	if err := os.Remove(localPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to replace local file: %w", err)
+	}
+	if err := os.Symlink(filepath.FromSlash(target), localPath); err != nil {
+		return fmt.Errorf("failed to create link: %w", err)
+	}
+	if _, uid, gid, ok := remoteAttrs(info); opts.Owner && ok && os.Geteuid() == 0 {
+		if err := os.Lchown(localPath, uid, gid); err != nil {
+			return fmt.Errorf("failed to set owner: %w", err)
+		}
+	}
+	return nil
+}
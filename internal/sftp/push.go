@@ -0,0 +1,182 @@
+package sftp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"gossh/internal/config"
+	"gossh/internal/model"
+)
+
+// PushOptions controls how a file is distributed by Push
+type PushOptions struct {
+	Mode     os.FileMode   // Mode of the remote file; zero keeps the local file's
+	Owner    string        // user[:group] to chown the remote file to, if set
+	Times    bool          // Keep the local file's access and modification times
+	Command  string        // Run on each host after its upload, e.g. "nginx -s reload"
+	Parallel int           // Hosts served at once, default 10
+	Timeout  time.Duration // Per host, from connecting to the end of Command
+}
+
+// PushResult is the outcome of pushing to one host
+type PushResult struct {
+	Connection model.Connection
+	RemotePath string // Where the file was written
+	Output     string // Output of the owner change and Command
+	Error      error
+	Duration   time.Duration
+}
+
+// Push uploads localPath to remotePath on each connection, at most
+// opts.Parallel at a time. A remotePath ending in "/" or naming a directory
+// gets the local file name appended.
+func Push(ctx context.Context, connections []model.Connection, localPath, remotePath string, opts PushOptions) []PushResult {
+	localPath = config.ExpandHome(localPath)
+	parallel := opts.Parallel
+	if parallel <= 0 {
+		parallel = 10
+	}
+
+	results := make([]PushResult, len(connections))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallel)
+	for i, conn := range connections {
+		wg.Add(1)
+		go func(idx int, c model.Connection) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[idx] = PushResult{Connection: c, Error: ctx.Err()}
+				return
+			}
+			results[idx] = pushOne(ctx, c, localPath, remotePath, opts)
+		}(i, conn)
+	}
+	wg.Wait()
+	return results
+}
+
+// pushOne pushes to a single host. Closing the connection when the
+// timeout runs out aborts a transfer still going.
+func pushOne(ctx context.Context, conn model.Connection, localPath, remotePath string, opts PushOptions) PushResult {
+	start := time.Now()
+	result := PushResult{Connection: conn}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	client := NewClient(conn)
+	if err := client.Connect(); err != nil {
+		result.Error = fmt.Errorf("connection error: %w", err)
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	type outcome struct {
+		remote, output string
+		err            error
+	}
+	done := make(chan outcome, 1)
+	sftpClient, sshClient := client.sftpClient, client.sshClient
+	go func() {
+		remote, output, err := pushFile(sftpClient, sshClient, localPath, remotePath, opts)
+		done <- outcome{remote, output, err}
+	}()
+
+	select {
+	case o := <-done:
+		result.RemotePath, result.Output, result.Error = o.remote, o.output, o.err
+	case <-ctx.Done():
+		result.Error = ctx.Err()
+	}
+	client.Close()
+	result.Duration = time.Since(start)
+	return result
+}
+
+// pushFile uploads the file, sets its mode, times and owner and runs the
+// command, returning the remote path and the commands' output
+func pushFile(sftpClient *sftp.Client, sshClient *ssh.Client, localPath, remotePath string, opts PushOptions) (string, string, error) {
+	if info, err := sftpClient.Stat(remotePath); strings.HasSuffix(remotePath, "/") || err == nil && info.IsDir() {
+		remotePath = path.Join(remotePath, filepath.Base(localPath))
+	}
+
+	local, err := os.Open(localPath)
+	if err != nil {
+		return remotePath, "", fmt.Errorf("failed to open local file: %w", err)
+	}
+	defer local.Close()
+	localInfo, err := local.Stat()
+	if err != nil {
+		return remotePath, "", fmt.Errorf("failed to stat local file: %w", err)
+	}
+
+	remote, err := sftpClient.Create(remotePath)
+	if err != nil {
+		return remotePath, "", fmt.Errorf("failed to create remote file: %w", err)
+	}
+	if _, err := io.Copy(remote, local); err != nil {
+		remote.Close()
+		return remotePath, "", fmt.Errorf("failed to copy file: %w", err)
+	}
+	if err := remote.Close(); err != nil {
+		return remotePath, "", fmt.Errorf("failed to copy file: %w", err)
+	}
+
+	mode := opts.Mode
+	if mode == 0 {
+		mode = localInfo.Mode().Perm()
+	}
+	if err := sftpClient.Chmod(remotePath, mode); err != nil && opts.Mode != 0 {
+		// Keeping the local mode is best effort, an asked for mode is not
+		return remotePath, "", fmt.Errorf("failed to set mode: %w", err)
+	}
+	if opts.Times {
+		atime, _, _, _ := localAttrs(localInfo)
+		if err := sftpClient.Chtimes(remotePath, atime, localInfo.ModTime()); err != nil {
+			return remotePath, "", fmt.Errorf("failed to set times: %w", err)
+		}
+	}
+
+	// SFTP only takes numeric owners, so names go through chown
+	var output strings.Builder
+	if opts.Owner != "" {
+		out, err := run(sshClient, "chown "+model.ShellQuote(opts.Owner)+" "+model.ShellQuote(remotePath))
+		output.WriteString(out)
+		if err != nil {
+			return remotePath, output.String(), fmt.Errorf("failed to change owner: %w", err)
+		}
+	}
+	if opts.Command != "" {
+		out, err := run(sshClient, opts.Command)
+		output.WriteString(out)
+		if err != nil {
+			return remotePath, output.String(), fmt.Errorf("command failed: %w", err)
+		}
+	}
+	return remotePath, output.String(), nil
+}
+
+// run runs a command in a new session, returning its combined output
+func run(client *ssh.Client, command string) (string, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return "", fmt.Errorf("session error: %w", err)
+	}
+	defer session.Close()
+	out, err := session.CombinedOutput(command)
+	return string(out), err
+}
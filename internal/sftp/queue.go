@@ -0,0 +1,361 @@
+package sftp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrTransferCanceled is the error of a transfer stopped by Queue.Cancel
+var ErrTransferCanceled = errors.New("transfer canceled")
+
+// ErrNoTransfer is returned for a transfer ID the queue does not know, or
+// one that has already finished
+var ErrNoTransfer = errors.New("no such transfer")
+
+// Direction is which way a queued transfer copies
+type Direction string
+
+const (
+	DirectionUpload   Direction = "put"
+	DirectionDownload Direction = "get"
+)
+
+// TransferState is where a queued transfer has got to
+type TransferState string
+
+const (
+	TransferQueued   TransferState = "queued"
+	TransferRunning  TransferState = "running"
+	TransferPaused   TransferState = "paused"
+	TransferDone     TransferState = "done"
+	TransferFailed   TransferState = "failed"
+	TransferCanceled TransferState = "canceled"
+)
+
+// Finished reports whether a transfer in the state is over
+func (s TransferState) Finished() bool {
+	return s == TransferDone || s == TransferFailed || s == TransferCanceled
+}
+
+// Transfer is a snapshot of a queued transfer
+type Transfer struct {
+	ID          int
+	Direction   Direction
+	Local       string // Resolved local path
+	Remote      string // Resolved remote path
+	State       TransferState
+	Transferred int64
+	Total       int64 // Size in bytes, once the transfer has started
+	Err         error // Why it failed
+}
+
+// QueueSummary describes the transfers a queue ran since it last drained
+type QueueSummary struct {
+	Done     int
+	Canceled int
+	Failed   []Transfer
+	Bytes    int64 // Copied by the finished transfers
+	Elapsed  time.Duration
+}
+
+// queueItem is a transfer with the requests made of it
+type queueItem struct {
+	Transfer
+	started  bool // Holding a slot, even while paused
+	paused   bool
+	canceled bool
+}
+
+// Queue runs transfers over one SFTP client in the background, at most
+// parallel at a time in the order they were added. A paused transfer keeps
+// its slot if it had started; one still waiting is passed over until it is
+// resumed.
+type Queue struct {
+	client   *Client
+	parallel int
+	onDrain  func(QueueSummary)
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	items   []*queueItem
+	running int
+	batch   int       // Index of the first item since the queue last drained
+	since   time.Time // When that item was added
+	wg      sync.WaitGroup
+}
+
+// NewQueue creates a transfer queue running up to parallel transfers at a
+// time, at least one
+func NewQueue(client *Client, parallel int) *Queue {
+	q := &Queue{client: client, parallel: max(parallel, 1)}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// SetOnDrain sets a function called, from the transfer's goroutine, each
+// time the last unfinished transfer finishes
+func (q *Queue) SetOnDrain(fn func(QueueSummary)) {
+	q.onDrain = fn
+}
+
+// Add queues a transfer and returns its ID. Paths are resolved against the
+// client's current directories now, so a later cd does not change them.
+func (q *Queue) Add(dir Direction, localPath, remotePath string) int {
+	it := &queueItem{Transfer: Transfer{
+		Direction: dir,
+		Local:     q.client.localPath(localPath),
+		Remote:    q.client.resolvePath(remotePath),
+		State:     TransferQueued,
+	}}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.batch == len(q.items) {
+		q.since = time.Now()
+	}
+	it.ID = len(q.items) + 1
+	q.items = append(q.items, it)
+	q.startLocked()
+	return it.ID
+}
+
+// Transfers returns a snapshot of every transfer, in the order added
+func (q *Queue) Transfers() []Transfer {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	transfers := make([]Transfer, len(q.items))
+	for i, it := range q.items {
+		transfers[i] = it.snapshot()
+	}
+	return transfers
+}
+
+// Active counts the transfers that have not finished
+func (q *Queue) Active() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := 0
+	for _, it := range q.items {
+		if !it.State.Finished() {
+			n++
+		}
+	}
+	return n
+}
+
+// Pause holds a transfer at its next chunk, or keeps a waiting one from
+// starting
+func (q *Queue) Pause(id int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	it, err := q.findLocked(id)
+	if err != nil {
+		return err
+	}
+	it.paused = true
+	return nil
+}
+
+// Resume lets a paused transfer carry on
+func (q *Queue) Resume(id int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	it, err := q.findLocked(id)
+	if err != nil {
+		return err
+	}
+	it.paused = false
+	q.cond.Broadcast()
+	q.startLocked()
+	return nil
+}
+
+// Cancel stops a transfer, removing what it had copied
+func (q *Queue) Cancel(id int) error {
+	q.mu.Lock()
+	it, err := q.findLocked(id)
+	if err != nil {
+		q.mu.Unlock()
+		return err
+	}
+	it.canceled = true
+	q.cond.Broadcast()
+
+	var summary *QueueSummary
+	if !it.started {
+		// Never started, so there is nothing to stop or clean up
+		it.State = TransferCanceled
+		summary = q.drainedLocked()
+	}
+	onDrain := q.onDrain
+	q.mu.Unlock()
+
+	if summary != nil && onDrain != nil {
+		onDrain(*summary)
+	}
+	return nil
+}
+
+// Wait returns once every transfer has finished. A paused one holds it up
+// until it is resumed or canceled.
+func (q *Queue) Wait() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for slices.ContainsFunc(q.items, func(it *queueItem) bool { return !it.State.Finished() }) {
+		q.cond.Wait()
+	}
+}
+
+// Close cancels the unfinished transfers and waits for them to stop
+func (q *Queue) Close() {
+	q.mu.Lock()
+	for _, it := range q.items {
+		if !it.State.Finished() {
+			it.canceled = true
+			if !it.started {
+				it.State = TransferCanceled
+			}
+		}
+	}
+	q.onDrain = nil
+	q.cond.Broadcast()
+	q.mu.Unlock()
+	q.wg.Wait()
+}
+
+// findLocked returns the unfinished transfer with the ID (caller must hold
+// lock)
+func (q *Queue) findLocked(id int) (*queueItem, error) {
+	if id < 1 || id > len(q.items) || q.items[id-1].State.Finished() {
+		return nil, ErrNoTransfer
+	}
+	return q.items[id-1], nil
+}
+
+// startLocked starts waiting transfers while slots are free (caller must
+// hold lock)
+func (q *Queue) startLocked() {
+	for _, it := range q.items {
+		if q.running >= q.parallel {
+			return
+		}
+		if it.State == TransferQueued && !it.paused {
+			it.started = true
+			it.State = TransferRunning
+			q.running++
+			q.wg.Add(1)
+			go q.run(it)
+		}
+	}
+}
+
+// run copies one transfer and starts the next
+func (q *Queue) run(it *queueItem) {
+	defer q.wg.Done()
+
+	progress := func(transferred, total int64) {
+		q.mu.Lock()
+		it.Transferred, it.Total = transferred, total
+		q.mu.Unlock()
+	}
+	gate := func() error {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		for it.paused && !it.canceled {
+			q.cond.Wait()
+		}
+		if it.canceled {
+			return ErrTransferCanceled
+		}
+		return nil
+	}
+
+	var err error
+	if it.Direction == DirectionUpload {
+		err = q.client.upload(it.Local, it.Remote, progress, gate)
+	} else {
+		err = q.client.download(it.Remote, it.Local, progress, gate)
+	}
+	canceled := errors.Is(err, ErrTransferCanceled)
+	if canceled {
+		// Leave no partial file behind
+		if it.Direction == DirectionUpload {
+			_ = q.client.sftpClient.Remove(it.Remote)
+		} else {
+			_ = os.Remove(it.Local)
+		}
+	}
+
+	q.mu.Lock()
+	// One that finished before it noticed a cancel counts as done
+	switch {
+	case canceled:
+		it.State = TransferCanceled
+	case err != nil:
+		it.State, it.Err = TransferFailed, err
+	default:
+		it.State = TransferDone
+	}
+	q.running--
+	q.cond.Broadcast()
+	q.startLocked()
+	summary := q.drainedLocked()
+	onDrain := q.onDrain
+	q.mu.Unlock()
+
+	if summary != nil && onDrain != nil {
+		onDrain(*summary)
+	}
+}
+
+// drainedLocked returns the summary of the transfers since the queue last
+// drained if none is left unfinished, and starts a new batch (caller must
+// hold lock)
+func (q *Queue) drainedLocked() *QueueSummary {
+	batch := q.items[q.batch:]
+	if len(batch) == 0 || slices.ContainsFunc(batch, func(it *queueItem) bool { return !it.State.Finished() }) {
+		return nil
+	}
+
+	summary := QueueSummary{Elapsed: time.Since(q.since)}
+	for _, it := range batch {
+		switch it.State {
+		case TransferDone:
+			summary.Done++
+			summary.Bytes += it.Transferred
+		case TransferCanceled:
+			summary.Canceled++
+		case TransferFailed:
+			summary.Failed = append(summary.Failed, it.snapshot())
+		}
+	}
+	q.batch = len(q.items)
+	return &summary
+}
+
+// snapshot returns the transfer as it stands, paused ones shown as such
+func (it *queueItem) snapshot() Transfer {
+	t := it.Transfer
+	if it.paused && !t.State.Finished() {
+		t.State = TransferPaused
+	}
+	return t
+}
+
+// FormatSize formats a byte count for people, e.g. "1.5 MB"
+func FormatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
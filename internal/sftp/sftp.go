@@ -9,6 +9,7 @@ import (
 
 	"github.com/pkg/sftp"
 	"golang.org/x/crypto/ssh"
+	"gossh/internal/config"
 	"gossh/internal/model"
 	gossh "gossh/internal/ssh"
 )
@@ -19,12 +20,14 @@ type Client struct {
 	sshClient       *ssh.Client
 	sftpClient      *sftp.Client
 	currentDir      string // Track current working directory
+	localDir        string // Directory relative local paths are resolved against
+	opts            TransferOptions
 	hostKeyCallback ssh.HostKeyCallback
 }
 
 // NewClient creates a new SFTP client for a connection
 func NewClient(conn model.Connection) *Client {
-	return &Client{conn: conn}
+	return &Client{conn: conn, localDir: config.ExpandHome(conn.LocalDir)}
 }
 
 // SetHostKeyCallback sets the host key callback for verification
@@ -32,16 +35,22 @@ func (c *Client) SetHostKeyCallback(callback ssh.HostKeyCallback) {
 	c.hostKeyCallback = callback
 }
 
-// Connect establishes the SFTP connection using the factory function
+// SetTransferOptions sets what transfers keep of the files they copy
+func (c *Client) SetTransferOptions(opts TransferOptions) {
+	c.opts = opts
+}
+
+// Connect establishes the SFTP connection, reusing an open connection to
+// the same host when there is one
 func (c *Client) Connect() error {
-	sshClient, err := gossh.ConnectWithConnection(c.conn, c.hostKeyCallback)
+	sshClient, err := gossh.DefaultPool.Acquire(c.conn, c.hostKeyCallback)
 	if err != nil {
 		return err
 	}
 
 	sftpClient, err := sftp.NewClient(sshClient)
 	if err != nil {
-		sshClient.Close()
+		_ = gossh.DefaultPool.Release(sshClient)
 		return fmt.Errorf("failed to create SFTP client: %w", err)
 	}
 
@@ -60,101 +69,94 @@ func (c *Client) Close() error {
 		c.sftpClient.Close()
 	}
 	if c.sshClient != nil {
-		c.sshClient.Close()
+		_ = gossh.DefaultPool.Release(c.sshClient)
+		c.sshClient = nil
 	}
 	return nil
 }
 
 // Upload uploads a local file to the remote server
 func (c *Client) Upload(localPath, remotePath string) error {
-	// Expand local path
-	localPath = expandPath(localPath)
+	return c.upload(c.localPath(localPath), c.resolvePath(remotePath), nil, nil)
+}
 
-	// Open local file
-	localFile, err := os.Open(localPath)
-	if err != nil {
-		return fmt.Errorf("failed to open local file: %w", err)
-	}
-	defer localFile.Close()
+// Download downloads a remote file to the local machine
+func (c *Client) Download(remotePath, localPath string) error {
+	return c.download(c.resolvePath(remotePath), c.localPath(localPath), nil, nil)
+}
 
-	// Get local file info for permissions
-	localInfo, err := localFile.Stat()
+// UploadDir uploads a local directory and everything in it, like scp -r.
+// Each directory gets its mode, owner and times once its contents are in,
+// as writing into it would change them.
+func (c *Client) UploadDir(localPath, remotePath string) error {
+	return c.uploadTree(c.localPath(localPath), c.resolvePath(remotePath))
+}
+
+func (c *Client) uploadTree(localPath, remotePath string) error {
+	stat := os.Stat
+	if c.opts.Links {
+		stat = os.Lstat
+	}
+	info, err := stat(localPath)
 	if err != nil {
 		return fmt.Errorf("failed to stat local file: %w", err)
 	}
-
-	// Create remote file
-	remoteFile, err := c.sftpClient.Create(remotePath)
-	if err != nil {
-		return fmt.Errorf("failed to create remote file: %w", err)
+	if !info.IsDir() {
+		return c.upload(localPath, remotePath, nil, nil)
 	}
-	defer remoteFile.Close()
 
-	// Copy content
-	_, err = io.Copy(remoteFile, localFile)
-	if err != nil {
-		return fmt.Errorf("failed to copy file: %w", err)
+	if err := c.sftpClient.MkdirAll(remotePath); err != nil {
+		return fmt.Errorf("failed to create remote directory: %w", err)
 	}
-
-	// Set permissions
-	err = c.sftpClient.Chmod(remotePath, localInfo.Mode())
+	entries, err := os.ReadDir(localPath)
 	if err != nil {
-		// Non-fatal error
-		fmt.Printf("Warning: failed to set permissions: %v\n", err)
+		return fmt.Errorf("failed to read directory: %w", err)
 	}
-
-	return nil
+	for _, e := range entries {
+		if err := c.uploadTree(filepath.Join(localPath, e.Name()), c.sftpClient.Join(remotePath, e.Name())); err != nil {
+			return err
+		}
+	}
+	return keepRemote(c.sftpClient, remotePath, info, c.opts, c.isRoot())
 }
 
-// Download downloads a remote file to the local machine
-func (c *Client) Download(remotePath, localPath string) error {
-	// Expand local path
-	localPath = expandPath(localPath)
+// DownloadDir downloads a remote directory and everything in it, like
+// UploadDir
+func (c *Client) DownloadDir(remotePath, localPath string) error {
+	return c.downloadTree(c.resolvePath(remotePath), c.localPath(localPath))
+}
 
-	// Open remote file
-	remoteFile, err := c.sftpClient.Open(remotePath)
-	if err != nil {
-		return fmt.Errorf("failed to open remote file: %w", err)
+func (c *Client) downloadTree(remotePath, localPath string) error {
+	stat := c.sftpClient.Stat
+	if c.opts.Links {
+		stat = c.sftpClient.Lstat
 	}
-	defer remoteFile.Close()
-
-	// Get remote file info
-	remoteInfo, err := remoteFile.Stat()
+	info, err := stat(remotePath)
 	if err != nil {
 		return fmt.Errorf("failed to stat remote file: %w", err)
 	}
-
-	// Create local directory if needed
-	localDir := filepath.Dir(localPath)
-	if err := os.MkdirAll(localDir, 0755); err != nil {
-		return fmt.Errorf("failed to create local directory: %w", err)
+	if !info.IsDir() {
+		return c.download(remotePath, localPath, nil, nil)
 	}
 
-	// Create local file
-	localFile, err := os.Create(localPath)
-	if err != nil {
-		return fmt.Errorf("failed to create local file: %w", err)
+	if err := os.MkdirAll(localPath, 0755); err != nil {
+		return fmt.Errorf("failed to create local directory: %w", err)
 	}
-	defer localFile.Close()
-
-	// Copy content
-	_, err = io.Copy(localFile, remoteFile)
+	entries, err := c.sftpClient.ReadDir(remotePath)
 	if err != nil {
-		return fmt.Errorf("failed to copy file: %w", err)
+		return fmt.Errorf("failed to read directory: %w", err)
 	}
-
-	// Set permissions
-	err = os.Chmod(localPath, remoteInfo.Mode())
-	if err != nil {
-		fmt.Printf("Warning: failed to set permissions: %v\n", err)
+	for _, e := range entries {
+		if err := c.downloadTree(c.sftpClient.Join(remotePath, e.Name()), filepath.Join(localPath, e.Name())); err != nil {
+			return err
+		}
 	}
-
-	return nil
+	return keepLocal(localPath, info, c.opts)
 }
 
 // List lists files in a remote directory
 func (c *Client) List(remotePath string) ([]FileInfo, error) {
-	files, err := c.sftpClient.ReadDir(remotePath)
+	files, err := c.sftpClient.ReadDir(c.resolvePath(remotePath))
 	if err != nil {
 		return nil, fmt.Errorf("failed to read directory: %w", err)
 	}
@@ -175,21 +177,23 @@ func (c *Client) List(remotePath string) ([]FileInfo, error) {
 
 // Mkdir creates a remote directory
 func (c *Client) Mkdir(remotePath string) error {
-	return c.sftpClient.MkdirAll(remotePath)
+	return c.sftpClient.MkdirAll(c.resolvePath(remotePath))
 }
 
 // Remove removes a remote file
 func (c *Client) Remove(remotePath string) error {
-	return c.sftpClient.Remove(remotePath)
+	return c.sftpClient.Remove(c.resolvePath(remotePath))
 }
 
 // RemoveAll removes a remote directory and all its contents
 func (c *Client) RemoveAll(remotePath string) error {
-	return c.removeRecursive(remotePath)
+	return c.removeRecursive(c.resolvePath(remotePath))
 }
 
+// removeRecursive removes a path and everything below it. Links are
+// removed themselves, never what they point to.
 func (c *Client) removeRecursive(path string) error {
-	info, err := c.sftpClient.Stat(path)
+	info, err := c.sftpClient.Lstat(path)
 	if err != nil {
 		return err
 	}
@@ -200,7 +204,7 @@ func (c *Client) removeRecursive(path string) error {
 			return err
 		}
 		for _, f := range files {
-			if err := c.removeRecursive(filepath.Join(path, f.Name())); err != nil {
+			if err := c.removeRecursive(c.sftpClient.Join(path, f.Name())); err != nil {
 				return err
 			}
 		}
@@ -212,7 +216,7 @@ func (c *Client) removeRecursive(path string) error {
 
 // Stat returns file info for a remote path
 func (c *Client) Stat(remotePath string) (*FileInfo, error) {
-	info, err := c.sftpClient.Stat(remotePath)
+	info, err := c.sftpClient.Stat(c.resolvePath(remotePath))
 	if err != nil {
 		return nil, err
 	}
@@ -257,14 +261,51 @@ func (c *Client) CurrentDir() string {
 	return c.currentDir
 }
 
-// resolvePath resolves a path relative to the current directory
+// AbsPath resolves a path against the current directory to an absolute remote path
+func (c *Client) AbsPath(path string) (string, error) {
+	resolved := c.resolvePath(path)
+	if strings.HasPrefix(resolved, "/") {
+		return resolved, nil
+	}
+	wd, err := c.sftpClient.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return c.sftpClient.Join(wd, resolved), nil
+}
+
+// isRoot reports whether the connection logs in as root, who may give
+// files any owner
+func (c *Client) isRoot() bool {
+	return c.conn.User == "root"
+}
+
+// LocalDir returns the directory relative local paths are resolved
+// against, or "" for the working directory
+func (c *Client) LocalDir() string {
+	return c.localDir
+}
+
+// localPath expands ~ in a local path and resolves a relative one against
+// the connection's local directory, if it has one
+func (c *Client) localPath(path string) string {
+	path = config.ExpandHome(path)
+	if c.localDir != "" && !filepath.IsAbs(path) {
+		return filepath.Join(c.localDir, path)
+	}
+	return path
+}
+
+// resolvePath resolves a path relative to the current directory. Remote
+// paths use forward slashes, also when typed with backslashes on Windows.
 func (c *Client) resolvePath(path string) string {
 	if path == "" {
 		return c.currentDir
 	}
+	path = filepath.ToSlash(path)
 	// Absolute path
 	if strings.HasPrefix(path, "/") {
-		return filepath.Clean(path)
+		return c.sftpClient.Join(path)
 	}
 	// Handle ~ for home directory
 	if path == "~" || strings.HasPrefix(path, "~/") {
@@ -274,11 +315,11 @@ func (c *Client) resolvePath(path string) string {
 			return home
 		}
 		if strings.HasPrefix(path, "~/") {
-			return filepath.Join(home, path[2:])
+			return c.sftpClient.Join(home, path[2:])
 		}
 	}
 	// Relative path
-	return filepath.Clean(filepath.Join(c.currentDir, path))
+	return c.sftpClient.Join(c.currentDir, path)
 }
 
 // ProgressCallback is called during file transfer to report progress
@@ -286,10 +327,18 @@ type ProgressCallback func(transferred, total int64)
 
 // UploadWithProgress uploads a local file to the remote server with progress reporting
 func (c *Client) UploadWithProgress(localPath, remotePath string, progress ProgressCallback) error {
-	// Expand local path
-	localPath = expandPath(localPath)
-	// Resolve remote path
-	remotePath = c.resolvePath(remotePath)
+	return c.upload(c.localPath(localPath), c.resolvePath(remotePath), progress, nil)
+}
+
+// upload copies a local file to a remote path, both resolved already.
+// gate, if set, is called before each chunk and stops the copy with its
+// error.
+func (c *Client) upload(localPath, remotePath string, progress ProgressCallback, gate func() error) error {
+	if c.opts.Links {
+		if info, err := os.Lstat(localPath); err == nil && isLink(info) {
+			return uploadLink(c.sftpClient, localPath, remotePath)
+		}
+	}
 
 	// Open local file
 	localFile, err := os.Open(localPath)
@@ -317,6 +366,11 @@ func (c *Client) UploadWithProgress(localPath, remotePath string, progress Progr
 	buf := make([]byte, 32*1024) // 32KB buffer
 
 	for {
+		if gate != nil {
+			if err := gate(); err != nil {
+				return err
+			}
+		}
 		n, err := localFile.Read(buf)
 		if n > 0 {
 			written, writeErr := remoteFile.Write(buf[:n])
@@ -336,21 +390,23 @@ func (c *Client) UploadWithProgress(localPath, remotePath string, progress Progr
 		}
 	}
 
-	// Set permissions
-	err = c.sftpClient.Chmod(remotePath, localInfo.Mode())
-	if err != nil {
-		fmt.Printf("Warning: failed to set permissions: %v\n", err)
-	}
-
-	return nil
+	// Set owner, permissions and times
+	return keepRemote(c.sftpClient, remotePath, localInfo, c.opts, c.isRoot())
 }
 
 // DownloadWithProgress downloads a remote file to the local machine with progress reporting
 func (c *Client) DownloadWithProgress(remotePath, localPath string, progress ProgressCallback) error {
-	// Expand local path
-	localPath = expandPath(localPath)
-	// Resolve remote path
-	remotePath = c.resolvePath(remotePath)
+	return c.download(c.resolvePath(remotePath), c.localPath(localPath), progress, nil)
+}
+
+// download copies a remote file to a local path, both resolved already,
+// stopped like upload by gate
+func (c *Client) download(remotePath, localPath string, progress ProgressCallback, gate func() error) error {
+	if c.opts.Links {
+		if info, err := c.sftpClient.Lstat(remotePath); err == nil && isLink(info) {
+			return downloadLink(c.sftpClient, remotePath, localPath, info, c.opts)
+		}
+	}
 
 	// Open remote file
 	remoteFile, err := c.sftpClient.Open(remotePath)
@@ -384,6 +440,11 @@ func (c *Client) DownloadWithProgress(remotePath, localPath string, progress Pro
 	buf := make([]byte, 32*1024) // 32KB buffer
 
 	for {
+		if gate != nil {
+			if err := gate(); err != nil {
+				return err
+			}
+		}
 		n, err := remoteFile.Read(buf)
 		if n > 0 {
 			written, writeErr := localFile.Write(buf[:n])
@@ -403,13 +464,8 @@ func (c *Client) DownloadWithProgress(remotePath, localPath string, progress Pro
 		}
 	}
 
-	// Set permissions
-	err = os.Chmod(localPath, remoteInfo.Mode())
-	if err != nil {
-		fmt.Printf("Warning: failed to set permissions: %v\n", err)
-	}
-
-	return nil
+	// Set owner, permissions and times
+	return keepLocal(localPath, remoteInfo, c.opts)
 }
 
 // ListCurrentDir lists files in the current working directory
@@ -440,14 +496,3 @@ func (f FileInfo) String() string {
 		f.Name,
 	)
 }
-
-// expandPath expands ~ to home directory
-func expandPath(path string) string {
-	if strings.HasPrefix(path, "~/") {
-		home, err := os.UserHomeDir()
-		if err == nil {
-			return filepath.Join(home, path[2:])
-		}
-	}
-	return path
-}
@@ -0,0 +1,95 @@
+package sftp
+
+import (
+	"fmt"
+	"path"
+	"time"
+)
+
+// TrashDir is where Trash moves remote files, under the home directory
+const TrashDir = "~/.gossh-trash"
+
+// Count returns how many files and directories there are at and below a
+// remote path, without following symbolic links
+func (c *Client) Count(remotePath string) (files, dirs int, err error) {
+	remotePath = c.resolvePath(remotePath)
+	info, err := c.sftpClient.Lstat(remotePath)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !info.IsDir() {
+		return 1, 0, nil
+	}
+	return c.countTree(remotePath)
+}
+
+// countTree counts a directory and everything below it
+func (c *Client) countTree(dir string) (files, dirs int, err error) {
+	entries, err := c.sftpClient.ReadDir(dir)
+	if err != nil {
+		return 0, 0, err
+	}
+	dirs = 1
+	for _, e := range entries {
+		if !e.IsDir() {
+			files++
+			continue
+		}
+		f, d, err := c.countTree(c.sftpClient.Join(dir, e.Name()))
+		if err != nil {
+			return 0, 0, err
+		}
+		files += f
+		dirs += d
+	}
+	return files, dirs, nil
+}
+
+// Trash moves a remote file or directory into TrashDir instead of deleting
+// it and returns where it went. Names are prefixed with the time, so the
+// same name trashed twice keeps both. The move is a rename, so it fails
+// for files on another file system than the home directory.
+func (c *Client) Trash(remotePath string) (string, error) {
+	remotePath = c.resolvePath(remotePath)
+	trash := c.resolvePath(TrashDir)
+	if err := c.sftpClient.MkdirAll(trash); err != nil {
+		return "", fmt.Errorf("failed to create trash: %w", err)
+	}
+	target := c.sftpClient.Join(trash, time.Now().Format("20060102-150405")+"-"+path.Base(remotePath))
+	if err := c.sftpClient.Rename(remotePath, target); err != nil {
+		return "", fmt.Errorf("failed to move to trash: %w", err)
+	}
+	return target, nil
+}
+
+// TrashCount returns how many entries TrashDir holds, none when it does
+// not exist
+func (c *Client) TrashCount() (int, error) {
+	entries, err := c.sftpClient.ReadDir(c.resolvePath(TrashDir))
+	if err != nil {
+		if _, statErr := c.sftpClient.Stat(c.resolvePath(TrashDir)); statErr != nil {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// EmptyTrash deletes everything in TrashDir for good and returns how many
+// entries it removed
+func (c *Client) EmptyTrash() (int, error) {
+	trash := c.resolvePath(TrashDir)
+	entries, err := c.sftpClient.ReadDir(trash)
+	if err != nil {
+		if _, statErr := c.sftpClient.Stat(trash); statErr != nil {
+			return 0, nil
+		}
+		return 0, err
+	}
+	for i, e := range entries {
+		if err := c.removeRecursive(c.sftpClient.Join(trash, e.Name())); err != nil {
+			return i, err
+		}
+	}
+	return len(entries), nil
+}
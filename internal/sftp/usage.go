@@ -0,0 +1,119 @@
+package sftp
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gossh/internal/model"
+)
+
+// Usage is the space taken by one entry of a directory
+type Usage struct {
+	Name  string
+	Size  int64 // Apparent size in bytes, of everything below a directory
+	IsDir bool
+}
+
+// DiskUsage returns the size of each entry of a remote directory, largest
+// first. It asks du on the server, which is quick, and walks the tree over
+// SFTP when the server has no du that counts in bytes.
+func (c *Client) DiskUsage(remotePath string) ([]Usage, error) {
+	dir := c.resolvePath(remotePath)
+	info, err := c.sftpClient.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("not a directory: %s", dir)
+	}
+
+	usage, err := c.remoteDu(dir)
+	if err != nil {
+		usage, err = c.walkDu(dir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(usage, func(i, j int) bool {
+		if usage[i].Size != usage[j].Size {
+			return usage[i].Size > usage[j].Size
+		}
+		return usage[i].Name < usage[j].Name
+	})
+	return usage, nil
+}
+
+// remoteDu runs du -sb on each entry of dir. Entries du cannot read into
+// are counted as far as it got, like du itself does.
+func (c *Client) remoteDu(dir string) ([]Usage, error) {
+	out, err := run(c.sshClient, "cd "+model.ShellQuote(dir)+" && find . -mindepth 1 -maxdepth 1 -exec du -sb {} + 2>/dev/null")
+	usage := parseDu(out)
+	if err != nil && len(usage) == 0 {
+		return nil, err
+	}
+
+	// du only tells sizes, so directories are told apart over SFTP
+	entries, err := c.sftpClient.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+	dirs := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		dirs[e.Name()] = e.IsDir()
+	}
+	for i := range usage {
+		usage[i].IsDir = dirs[usage[i].Name]
+	}
+	return usage, nil
+}
+
+// parseDu reads "size<TAB>./name" lines of du output, skipping any other
+func parseDu(out string) []Usage {
+	var usage []Usage
+	for _, line := range strings.Split(out, "\n") {
+		size, name, ok := strings.Cut(line, "\t")
+		if !ok || !strings.HasPrefix(name, "./") {
+			continue
+		}
+		n, err := strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			continue
+		}
+		usage = append(usage, Usage{Name: strings.TrimPrefix(name, "./"), Size: n})
+	}
+	return usage
+}
+
+// walkDu adds up the sizes below each entry of dir over SFTP, without
+// following symbolic links. Directories it cannot read count as empty.
+func (c *Client) walkDu(dir string) ([]Usage, error) {
+	entries, err := c.sftpClient.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+	usage := make([]Usage, len(entries))
+	for i, e := range entries {
+		usage[i] = Usage{Name: e.Name(), Size: c.treeSize(c.sftpClient.Join(dir, e.Name()), e), IsDir: e.IsDir()}
+	}
+	return usage, nil
+}
+
+// treeSize returns the size of a remote file, or of everything below a
+// directory
+func (c *Client) treeSize(remotePath string, info os.FileInfo) int64 {
+	if !info.IsDir() {
+		return info.Size()
+	}
+	size := info.Size()
+	entries, err := c.sftpClient.ReadDir(remotePath)
+	if err != nil {
+		return size
+	}
+	for _, e := range entries {
+		size += c.treeSize(c.sftpClient.Join(remotePath, e.Name()), e)
+	}
+	return size
+}
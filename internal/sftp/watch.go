@@ -0,0 +1,210 @@
+package sftp
+
+import (
+	"context"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultWatchExclude are paths Watch is usually told to leave alone:
+// version control data and editors' swap and backup files
+var DefaultWatchExclude = []string{".git", ".svn", ".hg", ".DS_Store", "*.swp", "*.swx", "*~", ".#*"}
+
+// WatchOptions controls Watch
+type WatchOptions struct {
+	Exclude  []string      // Glob patterns of paths to leave alone, matched against the path relative to the watched directory and against each name in it
+	Interval time.Duration // How often the directory is scanned, default 500ms
+	Debounce time.Duration // How long changes must settle before they are uploaded, default 300ms
+	Delete   bool          // Remove remote files whose local file was removed
+}
+
+// WatchEvent reports one upload or removal made by Watch
+type WatchEvent struct {
+	Path    string // Relative to the watched directory, with forward slashes
+	Size    int64
+	Removed bool
+	Err     error
+}
+
+// watchEntry is what a scan records of a local file to notice changes
+type watchEntry struct {
+	size    int64
+	modTime time.Time
+	isDir   bool
+}
+
+// watchChange is a change waiting for the directory to settle: the file
+// as last scanned, or as it was before it was removed
+type watchChange struct {
+	watchEntry
+	removed bool
+}
+
+// Watch uploads files in a local directory to the same place under a
+// remote directory as they change, until ctx is done. The directory is
+// scanned every opts.Interval, which works the same on every platform and
+// file system, and changes are held until a scan finds nothing new for
+// opts.Debounce, so a save touching several files uploads each once.
+// report, if set, is called after each upload or removal.
+func (c *Client) Watch(ctx context.Context, localDir, remoteDir string, opts WatchOptions, report func(WatchEvent)) error {
+	if opts.Interval <= 0 {
+		opts.Interval = 500 * time.Millisecond
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = 300 * time.Millisecond
+	}
+	localDir = c.localPath(localDir)
+	remoteDir = c.resolvePath(remoteDir)
+	if report == nil {
+		report = func(WatchEvent) {}
+	}
+
+	last, err := scanTree(localDir, opts.Exclude)
+	if err != nil {
+		return err
+	}
+
+	pending := make(map[string]watchChange)
+	var changed time.Time
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := scanTree(localDir, opts.Exclude)
+		if err != nil {
+			return err
+		}
+		for rel, e := range current {
+			if old, ok := last[rel]; !ok || !e.isDir && (old.size != e.size || !old.modTime.Equal(e.modTime)) {
+				pending[rel] = watchChange{watchEntry: e}
+				changed = time.Now()
+			}
+		}
+		for rel, e := range last {
+			if _, ok := current[rel]; !ok {
+				pending[rel] = watchChange{watchEntry: e, removed: true}
+				changed = time.Now()
+			}
+		}
+		if len(pending) == 0 || time.Since(changed) < opts.Debounce {
+			last = current
+			continue
+		}
+
+		c.syncChanges(localDir, remoteDir, pending, opts, report)
+		last = current
+		pending = make(map[string]watchChange)
+	}
+}
+
+// syncChanges uploads the changed paths and removes the removed ones.
+// Parents sort before their contents, so directories are made first and,
+// going backwards, removed last.
+func (c *Client) syncChanges(localDir, remoteDir string, pending map[string]watchChange, opts WatchOptions, report func(WatchEvent)) {
+	paths := make([]string, 0, len(pending))
+	for rel := range pending {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+
+	for _, rel := range paths {
+		e := pending[rel]
+		if e.removed {
+			continue
+		}
+		remotePath := path.Join(remoteDir, rel)
+		if e.isDir {
+			if err := c.sftpClient.MkdirAll(remotePath); err != nil {
+				report(WatchEvent{Path: rel, Err: err})
+			}
+			continue
+		}
+		err := c.sftpClient.MkdirAll(path.Dir(remotePath))
+		if err == nil {
+			err = c.upload(filepath.Join(localDir, filepath.FromSlash(rel)), remotePath, nil, nil)
+		}
+		report(WatchEvent{Path: rel, Size: e.size, Err: err})
+	}
+
+	if !opts.Delete {
+		return
+	}
+	for i := len(paths) - 1; i >= 0; i-- {
+		rel, e := paths[i], pending[paths[i]]
+		if !e.removed {
+			continue
+		}
+		remotePath := path.Join(remoteDir, rel)
+		var err error
+		if e.isDir {
+			err = c.sftpClient.RemoveDirectory(remotePath)
+		} else {
+			err = c.sftpClient.Remove(remotePath)
+		}
+		report(WatchEvent{Path: rel, Removed: true, Err: err})
+	}
+}
+
+// scanTree records every file and directory below root that is not
+// excluded, by slash separated path relative to root. Files that vanish
+// while it runs are skipped rather than failing the scan.
+func scanTree(root string, exclude []string) (map[string]watchEntry, error) {
+	entries := make(map[string]watchEntry)
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root {
+				return err
+			}
+			return nil
+		}
+		if p == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if excluded(rel, exclude) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type()&fs.ModeSymlink != 0 || !d.IsDir() && !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		entries[rel] = watchEntry{size: info.Size(), modTime: info.ModTime(), isDir: d.IsDir()}
+		return nil
+	})
+	return entries, err
+}
+
+// excluded reports whether a relative path matches one of the patterns,
+// whole or by its last name, e.g. "*.log", ".git" or "build/*"
+func excluded(rel string, patterns []string) bool {
+	name := path.Base(rel)
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+// Package shell runs commands in the user's local shell, for quick looks at
+// local files without leaving gossh
+package shell
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// Command returns a command running line in the local shell with the
+// terminal's input and output, in dir unless it is empty. An empty line
+// starts an interactive shell.
+func Command(line, dir string) *exec.Cmd {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		comspec := os.Getenv("COMSPEC")
+		if comspec == "" {
+			comspec = "cmd.exe"
+		}
+		if line == "" {
+			cmd = exec.Command(comspec)
+		} else {
+			cmd = exec.Command(comspec, "/C", line)
+		}
+	} else {
+		sh := os.Getenv("SHELL")
+		if sh == "" {
+			sh = "/bin/sh"
+		}
+		if line == "" {
+			cmd = exec.Command(sh)
+		} else {
+			cmd = exec.Command(sh, "-c", line)
+		}
+	}
+	cmd.Dir = dir
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd
+}
+
+// ExitCode returns the exit status of a command that ran, or -1 when it
+// could not be started
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
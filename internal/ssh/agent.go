@@ -0,0 +1,107 @@
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// ErrNoAgent is returned by AgentKeys when no SSH agent is running
+var ErrNoAgent = errors.New("no SSH agent is running (SSH_AUTH_SOCK is not set or unreachable)")
+
+// ErrAgentKeyNotLoaded is returned for a connection pinned to an agent key
+// the agent does not hold, e.g. after the agent restarted
+var ErrAgentKeyNotLoaded = errors.New("the pinned agent key is not loaded, add it with ssh-add")
+
+// The agent connection is opened on first use and kept, since signers
+// returned by the agent sign through it
+var (
+	agentMu     sync.Mutex
+	agentConn   io.ReadWriteCloser
+	agentClient agent.ExtendedAgent
+)
+
+// AgentKey is a key held by the running SSH agent
+type AgentKey struct {
+	Type        string // e.g. ssh-ed25519
+	Fingerprint string // SHA256:..., as ssh-add -l prints it
+	Comment     string // Usually the file the key was added from
+}
+
+// AgentKeys returns the keys held by the running SSH agent, in the order it
+// offers them
+func AgentKeys() ([]AgentKey, error) {
+	agentMu.Lock()
+	defer agentMu.Unlock()
+
+	client, err := agentLocked()
+	if err != nil {
+		return nil, err
+	}
+	listed, err := client.List()
+	if err != nil {
+		dropAgentLocked()
+		return nil, fmt.Errorf("failed to list the agent's keys: %w", err)
+	}
+	keys := make([]AgentKey, len(listed))
+	for i, key := range listed {
+		keys[i] = AgentKey{
+			Type:        key.Type(),
+			Fingerprint: ssh.FingerprintSHA256(key),
+			Comment:     key.Comment,
+		}
+	}
+	return keys, nil
+}
+
+// agentSigners returns the keys held by the running SSH agent, the one at
+// SSH_AUTH_SOCK or, on Windows, the OpenSSH agent service. It returns none
+// when there is no agent. A fingerprint keeps only the key it matches.
+func agentSigners(fingerprint string) []ssh.Signer {
+	agentMu.Lock()
+	defer agentMu.Unlock()
+
+	client, err := agentLocked()
+	if err != nil {
+		return nil
+	}
+	signers, err := client.Signers()
+	if err != nil {
+		// The agent went away; dial again next time
+		dropAgentLocked()
+		return nil
+	}
+	if fingerprint == "" {
+		return signers
+	}
+	for _, signer := range signers {
+		if ssh.FingerprintSHA256(signer.PublicKey()) == fingerprint {
+			return []ssh.Signer{signer}
+		}
+	}
+	return nil
+}
+
+// agentLocked returns the agent client, connecting on first use (caller
+// must hold agentMu)
+func agentLocked() (agent.ExtendedAgent, error) {
+	if agentClient == nil {
+		conn, err := dialAgent()
+		if err != nil {
+			return nil, ErrNoAgent
+		}
+		agentConn = conn
+		agentClient = agent.NewClient(conn)
+	}
+	return agentClient, nil
+}
+
+// dropAgentLocked closes the agent connection (caller must hold agentMu)
+func dropAgentLocked() {
+	agentConn.Close()
+	agentConn, agentClient = nil, nil
+}
@@ -0,0 +1,135 @@
+//go:build !windows
+
+package ssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"gossh/internal/model"
+)
+
+// startTestAgent serves a keyring holding one new key at SSH_AUTH_SOCK,
+// returning its fingerprint
+func startTestAgent(t *testing.T) string {
+	t.Helper()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	keyring := agent.NewKeyring()
+	if err := keyring.Add(agent.AddedKey{PrivateKey: privateKey, Comment: "me@laptop"}); err != nil {
+		t.Fatalf("Failed to add key: %v", err)
+	}
+
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go agent.ServeAgent(keyring, conn)
+		}
+	}()
+
+	t.Setenv("SSH_AUTH_SOCK", socket)
+	resetAgent(t)
+
+	publicKey, err := ssh.NewPublicKey(privateKey.Public())
+	if err != nil {
+		t.Fatalf("Failed to get the public key: %v", err)
+	}
+	return ssh.FingerprintSHA256(publicKey)
+}
+
+// resetAgent drops the cached agent connection, now and after the test
+func resetAgent(t *testing.T) {
+	reset := func() {
+		agentMu.Lock()
+		defer agentMu.Unlock()
+		if agentConn != nil {
+			agentConn.Close()
+		}
+		agentConn, agentClient = nil, nil
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestAgentSigners(t *testing.T) {
+	startTestAgent(t)
+
+	if signers := agentSigners(""); len(signers) != 1 {
+		t.Fatalf("agentSigners() = %d keys, want 1", len(signers))
+	}
+
+	// A key file that can't be read falls back to the agent's keys
+	conn := model.Connection{AuthType: model.AuthKey, KeyPath: filepath.Join(t.TempDir(), "missing")}
+	methods, err := BuildAuthMethods(conn)
+	if err != nil || len(methods) != 1 {
+		t.Errorf("BuildAuthMethods() = %d methods, %v; want the agent's keys", len(methods), err)
+	}
+}
+
+func TestAgentKeys(t *testing.T) {
+	fingerprint := startTestAgent(t)
+
+	keys, err := AgentKeys()
+	if err != nil {
+		t.Fatalf("AgentKeys() error = %v", err)
+	}
+	want := AgentKey{Type: ssh.KeyAlgoED25519, Fingerprint: fingerprint, Comment: "me@laptop"}
+	if len(keys) != 1 || keys[0] != want {
+		t.Errorf("AgentKeys() = %+v, want %+v", keys, want)
+	}
+}
+
+func TestAgentKeyPinned(t *testing.T) {
+	fingerprint := startTestAgent(t)
+
+	if signers := agentSigners(fingerprint); len(signers) != 1 {
+		t.Errorf("agentSigners(pinned) = %d keys, want the pinned one", len(signers))
+	}
+
+	// A pinned key stands in for the key file
+	conn := model.Connection{AuthType: model.AuthKey, AgentKey: fingerprint}
+	if methods, err := BuildAuthMethods(conn); err != nil || len(methods) != 1 {
+		t.Errorf("BuildAuthMethods() = %d methods, %v; want the pinned key", len(methods), err)
+	}
+
+	// The agent's other keys are not offered in its place
+	conn.AgentKey = "SHA256:" + strings.Repeat("A", 43)
+	if _, err := BuildAuthMethods(conn); !errors.Is(err, ErrAgentKeyNotLoaded) {
+		t.Errorf("BuildAuthMethods() error = %v, want ErrAgentKeyNotLoaded", err)
+	}
+}
+
+func TestAgentSignersWithoutAgent(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	resetAgent(t)
+
+	if signers := agentSigners(""); len(signers) != 0 {
+		t.Errorf("agentSigners() = %d keys, want none without an agent", len(signers))
+	}
+	conn := model.Connection{AuthType: model.AuthKey, KeyPath: filepath.Join(t.TempDir(), "missing")}
+	if _, err := BuildAuthMethods(conn); err == nil {
+		t.Error("BuildAuthMethods() should fail for a missing key without an agent")
+	}
+	if _, err := AgentKeys(); !errors.Is(err, ErrNoAgent) {
+		t.Errorf("AgentKeys() error = %v, want ErrNoAgent", err)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+
+package ssh
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+)
+
+// dialAgent connects to the agent socket named by SSH_AUTH_SOCK
+func dialAgent() (io.ReadWriteCloser, error) {
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return nil, errors.New("SSH_AUTH_SOCK is not set")
+	}
+	return net.Dial("unix", socket)
+}
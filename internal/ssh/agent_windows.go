@@ -0,0 +1,23 @@
+//go:build windows
+
+package ssh
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// openSSHAgentPipe is where the Windows OpenSSH agent service listens
+const openSSHAgentPipe = `\\.\pipe\openssh-ssh-agent`
+
+// dialAgent opens the agent's named pipe: SSH_AUTH_SOCK when it names a
+// pipe, else the OpenSSH agent service's. A named pipe opens like a file.
+func dialAgent() (io.ReadWriteCloser, error) {
+	pipe := os.Getenv("SSH_AUTH_SOCK")
+	if !strings.HasPrefix(pipe, `\\.\pipe\`) {
+		// Unset, or a Unix socket left by Git Bash or WSL
+		pipe = openSSHAgentPipe
+	}
+	return os.OpenFile(pipe, os.O_RDWR, 0)
+}
@@ -0,0 +1,34 @@
+package ssh
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestArchiveCommands(t *testing.T) {
+	if got, want := ArchiveCommand("/var/www/my app"), "tar czf - -C '/var/www/my app' ."; got != want {
+		t.Errorf("ArchiveCommand() = %q, want %q", got, want)
+	}
+	if got, want := ExtractCommand("/srv/it's"), `mkdir -p '/srv/it'\''s' && tar xzf - -C '/srv/it'\''s'`; got != want {
+		t.Errorf("ExtractCommand() = %q, want %q", got, want)
+	}
+}
+
+func TestStreamCounter(t *testing.T) {
+	var counter StreamCounter
+	var buf bytes.Buffer
+	if _, err := io.Copy(countingWriter{w: &buf, counter: &counter}, countingReader{r: strings.NewReader("hello"), counter: &counter}); err != nil {
+		t.Fatal(err)
+	}
+	if got := counter.Bytes(); got != 10 {
+		t.Errorf("Bytes() = %d, want 10 for 5 read and 5 written", got)
+	}
+
+	// Counting is optional
+	var none *StreamCounter
+	if _, err := io.Copy(countingWriter{w: io.Discard}, strings.NewReader("x")); err != nil || none.Bytes() != 0 {
+		t.Errorf("nil counter: %v, %d", err, none.Bytes())
+	}
+}
@@ -1,9 +1,13 @@
 package ssh
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"strings"
 
 	"golang.org/x/crypto/ssh"
+	"gossh/internal/config"
 	"gossh/internal/model"
 )
 
@@ -11,26 +15,80 @@ import (
 func BuildAuthMethods(conn model.Connection) ([]ssh.AuthMethod, error) {
 	var methods []ssh.AuthMethod
 
-	switch conn.AuthMethod {
+	// GSSAPI goes first, like in OpenSSH, so a Kerberos ticket is used
+	// before any password or key
+	if method := gssapiAuth(conn); method != nil {
+		methods = append(methods, method)
+	}
+
+	switch conn.AuthType {
 	case model.AuthPassword:
 		methods = append(methods, ssh.Password(conn.Password))
 	case model.AuthKey:
-		keyAuth, err := loadKeyAuth(conn.KeyPath, conn.KeyPassword)
-		if err != nil {
+		// The agent's keys are offered after the key file's. The agent may
+		// hold the key when the file can't be used, e.g. an encrypted key
+		// whose passphrase isn't saved. Both go in one method, as the
+		// client tries each method once. A pinned agent key is the only
+		// one of the agent's offered, and may stand in for the key file.
+		var signer ssh.Signer
+		var err error
+		if conn.KeyPath != "" || conn.AgentKey == "" {
+			signer, err = loadKeySigner(conn.KeyPath, conn.KeyPassword)
+		}
+		fromAgent := agentSigners(conn.AgentKey)
+		if signer == nil && len(fromAgent) == 0 {
+			if conn.AgentKey != "" && err == nil {
+				err = fmt.Errorf("%w: %s", ErrAgentKeyNotLoaded, conn.AgentKey)
+			}
 			return nil, err
 		}
-		methods = append(methods, keyAuth)
+		var signers []ssh.Signer
+		if signer != nil {
+			signers = append(signers, signer)
+		}
+		methods = append(methods, ssh.PublicKeys(append(signers, fromAgent...)...))
 	}
 
 	return methods, nil
 }
 
-// loadKeyAuth loads a private key for authentication
-func loadKeyAuth(keyPath, passphrase string) (ssh.AuthMethod, error) {
-	key, err := os.ReadFile(keyPath)
+// errAuthMethods wraps failures to load a connection's credentials, e.g.
+// an unreadable private key
+var errAuthMethods = errors.New("failed to build auth methods")
+
+// IsAuthError reports whether err means the server refused the credentials
+// or the private key could not be loaded
+func IsAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+	// x/crypto/ssh has no error type for rejected credentials
+	return errors.Is(err, errAuthMethods) || strings.Contains(err.Error(), "unable to authenticate")
+}
+
+// keyWarning is told about private keys other users can access, see
+// SetKeyWarning
+var keyWarning func(error)
+
+// SetKeyWarning makes loading a private key that the group or other users
+// can access call warn with a config.PermissionError, as OpenSSH warns
+// about unprotected key files. Keys are loaded concurrently, e.g. by
+// BatchExecutor. nil turns the warning off.
+func SetKeyWarning(warn func(error)) {
+	keyWarning = warn
+}
+
+// loadKeySigner loads a private key for authentication
+func loadKeySigner(keyPath, passphrase string) (ssh.Signer, error) {
+	key, err := os.ReadFile(config.ExpandHome(keyPath))
 	if err != nil {
 		return nil, err
 	}
+	if keyWarning != nil {
+		if err := config.CheckKeyFile(keyPath); err != nil {
+			keyWarning(err)
+		}
+	}
 
 	var signer ssh.Signer
 	if passphrase != "" {
@@ -42,5 +100,5 @@ func loadKeyAuth(keyPath, passphrase string) (ssh.AuthMethod, error) {
 		return nil, err
 	}
 
-	return ssh.PublicKeys(signer), nil
+	return signer, nil
 }
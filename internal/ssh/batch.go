@@ -3,7 +3,12 @@ package ssh
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 
@@ -20,11 +25,62 @@ type BatchResult struct {
 	ExitCode   int
 }
 
+// ErrHostTimeout is the error of a host that took longer than the
+// per-host timeout
+var ErrHostTimeout = errors.New("host timed out")
+
+// ErrRunDeadline is the error of a host the run's overall deadline cut
+// short, or never got to
+var ErrRunDeadline = errors.New("run deadline exceeded")
+
+// ErrInterrupted is the error of a host a canceled run cut short, or never
+// got to, e.g. on Ctrl+C
+var ErrInterrupted = errors.New("run interrupted")
+
+// How long a remote command cut short gets to exit after SIGTERM, and then
+// after SIGKILL, before its connection is closed on it
+const (
+	termGrace = 2 * time.Second
+	killGrace = time.Second
+)
+
+// BatchProgress is how far a batch run has got
+type BatchProgress struct {
+	Total   int
+	Running int
+	Done    int // Finished hosts, failed ones included
+	Failed  int
+	Started time.Time
+
+	Host   int          // Index of the host that just started or finished
+	Result *BatchResult // Its result when it finished, nil when it started
+}
+
+// ETA estimates how long the rest of the run takes at the pace so far, 0
+// until a host has finished
+func (p BatchProgress) ETA() time.Duration {
+	if p.Done == 0 || p.Done >= p.Total {
+		return 0
+	}
+	elapsed := time.Since(p.Started)
+	return elapsed * time.Duration(p.Total-p.Done) / time.Duration(p.Done)
+}
+
 // BatchExecutor executes commands on multiple hosts
 type BatchExecutor struct {
 	connections []model.Connection
 	timeout     time.Duration
+	deadline    time.Duration
 	parallel    int
+	progress    func(BatchProgress)
+	ptyWidth    int // Columns of the pseudo-terminal, 0 for none
+	ptyHeight   int
+	stripColor  bool
+	env         map[string]string
+	hostEnv     map[string]map[string]string // By TargetKey
+
+	hostKeyCallback ssh.HostKeyCallback
+	pool            *Pool // Shares connections, and keeps them warm between runs
 }
 
 // NewBatchExecutor creates a new batch executor
@@ -33,14 +89,73 @@ func NewBatchExecutor(connections []model.Connection) *BatchExecutor {
 		connections: connections,
 		timeout:     30 * time.Second,
 		parallel:    10, // Default parallel connections
+		pool:        DefaultPool,
 	}
 }
 
-// SetTimeout sets the command timeout
+// SetTimeout sets how long each host may take, connecting and running the
+// command
 func (b *BatchExecutor) SetTimeout(timeout time.Duration) {
 	b.timeout = timeout
 }
 
+// SetDeadline sets how long the whole run may take, 0 for no limit beyond
+// the per-host timeouts
+func (b *BatchExecutor) SetDeadline(deadline time.Duration) {
+	b.deadline = deadline
+}
+
+// SetProgress sets a function called as each host starts and finishes,
+// one call at a time. It must not block for long, as the run waits for it.
+func (b *BatchExecutor) SetProgress(fn func(BatchProgress)) {
+	b.progress = fn
+}
+
+// SetPty runs the command in a pseudo-terminal of width columns and height
+// rows, for commands that need one, such as sudo with requiretty. Its
+// output and errors come back as one stream. A width of 0 turns it off.
+func (b *BatchExecutor) SetPty(width, height int) {
+	b.ptyWidth, b.ptyHeight = width, height
+}
+
+// SetStripColor makes the captured output plain text: colors and other
+// escape sequences are dropped, and redraws with carriage returns applied
+func (b *BatchExecutor) SetStripColor(strip bool) {
+	b.stripColor = strip
+}
+
+// SetEnv sets environment variables for the command on each host. They
+// are set for the session where the server accepts them, else exported at
+// the start of the command, which takes a POSIX shell.
+func (b *BatchExecutor) SetEnv(env map[string]string) {
+	b.env = env
+}
+
+// SetHostEnv sets environment variables for the command on some hosts,
+// keyed by TargetKey, e.g. what an earlier command printed there. They
+// are set like those of SetEnv, over which they win.
+func (b *BatchExecutor) SetHostEnv(env map[string]map[string]string) {
+	b.hostEnv = env
+}
+
+// envFor returns the variables to set for the command on conn
+func (b *BatchExecutor) envFor(conn model.Connection) map[string]string {
+	hostEnv := b.hostEnv[TargetKey(conn)]
+	if len(hostEnv) == 0 {
+		return b.env
+	}
+	env := make(map[string]string, len(b.env)+len(hostEnv))
+	maps.Copy(env, b.env)
+	maps.Copy(env, hostEnv)
+	return env
+}
+
+// SetHostKeyCallback sets the host key callback for verification. Without
+// one any host key is accepted.
+func (b *BatchExecutor) SetHostKeyCallback(callback ssh.HostKeyCallback) {
+	b.hostKeyCallback = callback
+}
+
 // SetParallel sets the max parallel connections
 func (b *BatchExecutor) SetParallel(n int) {
 	if n > 0 {
@@ -50,10 +165,41 @@ func (b *BatchExecutor) SetParallel(n int) {
 
 // Execute executes a command on all connections
 func (b *BatchExecutor) Execute(ctx context.Context, command string) []BatchResult {
+	if b.deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.deadline)
+		defer cancel()
+	}
+
 	results := make([]BatchResult, len(b.connections))
 	var wg sync.WaitGroup
 	sem := make(chan struct{}, b.parallel)
 
+	var mu sync.Mutex
+	progress := BatchProgress{Total: len(b.connections), Started: time.Now()}
+	running := make([]bool, len(b.connections))
+	report := func(idx int, result *BatchResult) {
+		if b.progress == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if result == nil {
+			running[idx] = true
+			progress.Running++
+		} else {
+			if running[idx] {
+				progress.Running--
+			}
+			progress.Done++
+			if result.Error != nil {
+				progress.Failed++
+			}
+		}
+		progress.Host, progress.Result = idx, result
+		b.progress(progress)
+	}
+
 	for i, conn := range b.connections {
 		wg.Add(1)
 		go func(idx int, c model.Connection) {
@@ -66,13 +212,16 @@ func (b *BatchExecutor) Execute(ctx context.Context, command string) []BatchResu
 			case <-ctx.Done():
 				results[idx] = BatchResult{
 					Connection: c,
-					Error:      ctx.Err(),
+					Error:      b.stopError(ctx),
 				}
+				report(idx, &results[idx])
 				return
 			}
 
 			// Execute command
+			report(idx, nil)
 			results[idx] = b.executeOne(ctx, c, command)
+			report(idx, &results[idx])
 		}(i, conn)
 	}
 
@@ -80,38 +229,84 @@ func (b *BatchExecutor) Execute(ctx context.Context, command string) []BatchResu
 	return results
 }
 
-// executeOne executes a command on a single connection
+// stopError returns why a host was cut short: the run's deadline or
+// cancellation in ctx, else its own timeout
+func (b *BatchExecutor) stopError(ctx context.Context) error {
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return ErrRunDeadline
+	case ctx.Err() != nil:
+		return ErrInterrupted
+	}
+	return fmt.Errorf("%w after %s", ErrHostTimeout, b.timeout)
+}
+
+// stopSession stops a remote command cut short: SIGTERM, then SIGKILL if it
+// has not exited within termGrace, then discarding the connection if it
+// still has not after killGrace. Servers that ignore signals only see the
+// close. It returns once done has the command's result, so its output is
+// complete.
+func (b *BatchExecutor) stopSession(client *ssh.Client, session *ssh.Session, done <-chan error) {
+	_ = session.Signal(ssh.SIGTERM)
+	select {
+	case <-done:
+		return
+	case <-time.After(termGrace):
+	}
+	_ = session.Signal(ssh.SIGKILL)
+	select {
+	case <-done:
+		return
+	case <-time.After(killGrace):
+	}
+	_ = b.pool.Discard(client)
+	<-done
+}
+
+// executeOne executes a command on a single connection, within the per-host
+// timeout
 func (b *BatchExecutor) executeOne(ctx context.Context, conn model.Connection, command string) BatchResult {
 	start := time.Now()
 	result := BatchResult{
 		Connection: conn,
 	}
 
-	// Build auth methods
-	authMethods, err := BuildAuthMethods(conn)
-	if err != nil {
-		result.Error = fmt.Errorf("auth error: %w", err)
-		result.Duration = time.Since(start)
-		return result
-	}
+	hostCtx, cancel := context.WithTimeout(ctx, b.timeout)
+	defer cancel()
 
-	// Create SSH config
-	config := &ssh.ClientConfig{
-		User:            conn.User,
-		Auth:            authMethods,
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-		Timeout:         b.timeout,
+	// Connect, through the jump hosts if any, or reuse a connection the pool
+	// keeps, once its host keys pass the check. The handshake has no
+	// timeout of its own, so the dial is abandoned when the host's time is
+	// up, releasing the client if it arrives later.
+	type dialed struct {
+		client *ssh.Client
+		err    error
 	}
-
-	// Connect
-	addr := fmt.Sprintf("%s:%d", conn.Host, conn.Port)
-	client, err := ssh.Dial("tcp", addr, config)
-	if err != nil {
-		result.Error = fmt.Errorf("connection error: %w", err)
+	dialCh := make(chan dialed, 1)
+	go func() {
+		client, err := b.pool.acquire(conn, b.timeout, b.hostKeyCallback)
+		dialCh <- dialed{client, err}
+	}()
+	var client *ssh.Client
+	select {
+	case d := <-dialCh:
+		if d.err != nil {
+			result.Error = fmt.Errorf("connection error: %w", d.err)
+			result.Duration = time.Since(start)
+			return result
+		}
+		client = d.client
+	case <-hostCtx.Done():
+		go func() {
+			if d := <-dialCh; d.client != nil {
+				_ = b.pool.Release(d.client)
+			}
+		}()
+		result.Error = b.stopError(ctx)
 		result.Duration = time.Since(start)
 		return result
 	}
-	defer client.Close()
+	defer func() { _ = b.pool.Release(client) }()
 
 	// Create session
 	session, err := client.NewSession()
@@ -122,6 +317,29 @@ func (b *BatchExecutor) executeOne(ctx context.Context, conn model.Connection, c
 	}
 	defer session.Close()
 
+	if b.ptyWidth > 0 {
+		// No echo, so input sent to a prompt stays out of the output
+		modes := ssh.TerminalModes{
+			ssh.ECHO:          0,
+			ssh.TTY_OP_ISPEED: 14400,
+			ssh.TTY_OP_OSPEED: 14400,
+		}
+		if err := session.RequestPty("xterm", b.ptyHeight, b.ptyWidth, modes); err != nil {
+			result.Error = fmt.Errorf("pty error: %w", err)
+			result.Duration = time.Since(start)
+			return result
+		}
+	}
+
+	env := b.envFor(conn)
+	var refused []string
+	for _, name := range envNames(env) {
+		if err := session.Setenv(name, env[name]); err != nil {
+			refused = append(refused, name)
+		}
+	}
+	command = exportCommand(command, env, refused)
+
 	// Set up output capture
 	var stdout, stderr bytes.Buffer
 	session.Stdout = &stdout
@@ -142,9 +360,9 @@ func (b *BatchExecutor) executeOne(ctx context.Context, conn model.Connection, c
 			}
 			result.Error = err
 		}
-	case <-ctx.Done():
-		_ = session.Signal(ssh.SIGTERM)
-		result.Error = ctx.Err()
+	case <-hostCtx.Done():
+		b.stopSession(client, session, done)
+		result.Error = b.stopError(ctx)
 	}
 
 	// Combine output
@@ -155,6 +373,12 @@ func (b *BatchExecutor) executeOne(ctx context.Context, conn model.Connection, c
 		}
 		output += stderr.String()
 	}
+	if b.stripColor {
+		output = PlainText([]byte(output))
+	} else if b.ptyWidth > 0 {
+		// The terminal ends lines with CRLF
+		output = strings.ReplaceAll(output, "\r\n", "\n")
+	}
 	result.Output = output
 	result.Duration = time.Since(start)
 
@@ -199,59 +423,160 @@ func FilterByTags(connections []model.Connection, tags []string) []model.Connect
 	return result
 }
 
-// FilterByNames filters connections by names
+// FilterByNames filters connections by names.
+// Names may contain glob patterns such as "web-*" or "db-?".
 func FilterByNames(connections []model.Connection, names []string) []model.Connection {
 	if len(names) == 0 {
 		return connections
 	}
 
 	nameSet := make(map[string]bool)
+	var patterns []string
 	for _, n := range names {
-		nameSet[n] = true
+		if isGlob(n) {
+			patterns = append(patterns, n)
+		} else {
+			nameSet[n] = true
+		}
 	}
 
 	var result []model.Connection
 	for _, c := range connections {
-		if nameSet[c.Name] {
+		if matchesNames(c, nameSet, patterns) {
 			result = append(result, c)
 		}
 	}
 	return result
 }
 
-// PrintResults prints batch execution results
-func PrintResults(results []BatchResult) {
-	fmt.Println("\n" + string(make([]byte, 80)))
-	fmt.Println("BATCH EXECUTION RESULTS")
-	fmt.Println(string(make([]byte, 80)))
+// FilterByMatch filters connections whose name or host matches the regular expression
+func FilterByMatch(connections []model.Connection, re *regexp.Regexp) []model.Connection {
+	if re == nil {
+		return connections
+	}
 
-	successCount := 0
-	failCount := 0
+	var result []model.Connection
+	for _, c := range connections {
+		if re.MatchString(c.Name) || re.MatchString(c.Host) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
 
-	for _, r := range results {
-		status := "✓"
-		if r.Error != nil {
-			status = "✗"
-			failCount++
-		} else {
-			successCount++
+// isGlob reports whether a name contains glob metacharacters
+func isGlob(name string) bool {
+	return strings.ContainsAny(name, "*?[")
+}
+
+// matchesNames reports whether the name or an alias of c is in names or
+// matches one of the glob patterns
+func matchesNames(c model.Connection, names map[string]bool, patterns []string) bool {
+	for _, n := range c.Names() {
+		if names[n] || matchesAnyGlob(n, patterns) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAnyGlob reports whether name matches any of the glob patterns
+func matchesAnyGlob(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, err := path.Match(p, name); err == nil && ok {
+			return true
 		}
+	}
+	return false
+}
 
-		fmt.Printf("\n%s [%s] %s@%s:%d (%.2fs)\n",
-			status, r.Connection.Name, r.Connection.User,
-			r.Connection.Host, r.Connection.Port,
-			r.Duration.Seconds())
-		fmt.Println(string(make([]byte, 40)))
+// ExcludeByGroup removes connections that belong to the given group
+func ExcludeByGroup(connections []model.Connection, group string) []model.Connection {
+	if group == "" {
+		return connections
+	}
 
-		if r.Error != nil {
-			fmt.Printf("Error: %v\n", r.Error)
+	var result []model.Connection
+	for _, c := range connections {
+		if c.Group != group {
+			result = append(result, c)
 		}
-		if r.Output != "" {
-			fmt.Println(r.Output)
+	}
+	return result
+}
+
+// ExcludeByTags removes connections carrying any of the given tags
+func ExcludeByTags(connections []model.Connection, tags []string) []model.Connection {
+	if len(tags) == 0 {
+		return connections
+	}
+
+	tagSet := make(map[string]bool)
+	for _, t := range tags {
+		tagSet[t] = true
+	}
+
+	var result []model.Connection
+	for _, c := range connections {
+		excluded := false
+		for _, t := range c.Tags {
+			if tagSet[t] {
+				excluded = true
+				break
+			}
 		}
+		if !excluded {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// ExcludeByNames removes connections matching any of the given names or globs
+func ExcludeByNames(connections []model.Connection, names []string) []model.Connection {
+	if len(names) == 0 {
+		return connections
 	}
 
-	fmt.Println("\n" + string(make([]byte, 80)))
-	fmt.Printf("Summary: %d succeeded, %d failed, %d total\n",
-		successCount, failCount, len(results))
+	nameSet := make(map[string]bool)
+	var patterns []string
+	for _, n := range names {
+		if isGlob(n) {
+			patterns = append(patterns, n)
+		} else {
+			nameSet[n] = true
+		}
+	}
+
+	var result []model.Connection
+	for _, c := range connections {
+		if !matchesNames(c, nameSet, patterns) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// TargetFilter describes which connections a batch command should run against
+type TargetFilter struct {
+	Group        string
+	Tags         []string
+	Names        []string
+	Match        *regexp.Regexp
+	ExcludeGroup string
+	ExcludeTags  []string
+	ExcludeNames []string
+}
+
+// Apply returns the connections selected by the filter.
+// Include filters are applied first, then exclusions.
+func (f TargetFilter) Apply(connections []model.Connection) []model.Connection {
+	connections = FilterByGroup(connections, f.Group)
+	connections = FilterByTags(connections, f.Tags)
+	connections = FilterByNames(connections, f.Names)
+	connections = FilterByMatch(connections, f.Match)
+	connections = ExcludeByGroup(connections, f.ExcludeGroup)
+	connections = ExcludeByTags(connections, f.ExcludeTags)
+	connections = ExcludeByNames(connections, f.ExcludeNames)
+	return connections
 }
@@ -0,0 +1,308 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"net"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"gossh/internal/model"
+)
+
+func testConnections() []model.Connection {
+	return []model.Connection{
+		{Name: "web-01", Host: "10.0.0.1", Group: "Production", Tags: []string{"web"}},
+		{Name: "web-02", Host: "10.0.0.2", Group: "Production", Tags: []string{"web", "canary"}},
+		{Name: "db-01", Aliases: []string{"db-primary"}, Host: "db.internal", Group: "Production", Tags: []string{"db"}},
+		{Name: "dev-box", Host: "10.1.0.1", Group: "Development"},
+	}
+}
+
+func connNames(conns []model.Connection) []string {
+	names := make([]string, len(conns))
+	for i, c := range conns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func TestFilterByNames(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		want  []string
+	}{
+		{"exact", []string{"db-01"}, []string{"db-01"}},
+		{"glob star", []string{"web-*"}, []string{"web-01", "web-02"}},
+		{"glob question", []string{"web-0?"}, []string{"web-01", "web-02"}},
+		{"mixed", []string{"db-01", "dev-*"}, []string{"db-01", "dev-box"}},
+		{"alias", []string{"db-primary"}, []string{"db-01"}},
+		{"alias glob", []string{"db-p*"}, []string{"db-01"}},
+		{"no match", []string{"cache-*"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := connNames(FilterByNames(testConnections(), tt.names))
+			if len(got) != len(tt.want) {
+				t.Fatalf("FilterByNames(%v) = %v, want %v", tt.names, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("FilterByNames(%v) = %v, want %v", tt.names, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterByMatch(t *testing.T) {
+	// Matches against host
+	got := connNames(FilterByMatch(testConnections(), regexp.MustCompile(`^10\.0\.`)))
+	if len(got) != 2 || got[0] != "web-01" || got[1] != "web-02" {
+		t.Errorf("host match = %v, want [web-01 web-02]", got)
+	}
+
+	// Matches against name
+	got = connNames(FilterByMatch(testConnections(), regexp.MustCompile(`^d`)))
+	if len(got) != 2 || got[0] != "db-01" || got[1] != "dev-box" {
+		t.Errorf("name match = %v, want [db-01 dev-box]", got)
+	}
+
+	// Nil regex keeps everything
+	if n := len(FilterByMatch(testConnections(), nil)); n != 4 {
+		t.Errorf("nil regex returned %d connections, want 4", n)
+	}
+}
+
+func TestTargetFilterExclusions(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter TargetFilter
+		want   []string
+	}{
+		{
+			name:   "group minus canary tag",
+			filter: TargetFilter{Group: "Production", ExcludeTags: []string{"canary"}},
+			want:   []string{"web-01", "db-01"},
+		},
+		{
+			name:   "exclude names glob",
+			filter: TargetFilter{ExcludeNames: []string{"web-*"}},
+			want:   []string{"db-01", "dev-box"},
+		},
+		{
+			name:   "exclude group",
+			filter: TargetFilter{ExcludeGroup: "Production"},
+			want:   []string{"dev-box"},
+		},
+		{
+			name:   "include and exclude names",
+			filter: TargetFilter{Names: []string{"web-*"}, ExcludeNames: []string{"web-02"}},
+			want:   []string{"web-01"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := connNames(tt.filter.Apply(testConnections()))
+			if len(got) != len(tt.want) {
+				t.Fatalf("Apply() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Apply() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// silentHost returns a connection to a local port that accepts TCP but never
+// answers the SSH handshake
+func silentHost(t *testing.T) model.Connection {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	n, _ := strconv.Atoi(port)
+	return model.Connection{Name: "silent", Host: "127.0.0.1", Port: n, User: "root", AuthType: model.AuthPassword, Password: "x"}
+}
+
+func TestBatchExecutorHostTimeout(t *testing.T) {
+	hosts := []model.Connection{silentHost(t), silentHost(t), silentHost(t)}
+	executor := NewBatchExecutor(hosts)
+	executor.SetTimeout(200 * time.Millisecond)
+	executor.SetParallel(1)
+
+	start := time.Now()
+	results := executor.Execute(context.Background(), "true")
+	for _, r := range results {
+		if !errors.Is(r.Error, ErrHostTimeout) {
+			t.Errorf("Execute() error = %v, want %v", r.Error, ErrHostTimeout)
+		}
+	}
+	// Each host gets its own timeout, one after another
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Execute() took %v, want about 600ms", elapsed)
+	}
+}
+
+func TestBatchExecutorDeadline(t *testing.T) {
+	hosts := []model.Connection{silentHost(t), silentHost(t)}
+	executor := NewBatchExecutor(hosts)
+	executor.SetTimeout(time.Minute)
+	executor.SetDeadline(200 * time.Millisecond)
+	executor.SetParallel(1)
+
+	start := time.Now()
+	results := executor.Execute(context.Background(), "true")
+	for _, r := range results {
+		if !errors.Is(r.Error, ErrRunDeadline) {
+			t.Errorf("Execute() error = %v, want %v", r.Error, ErrRunDeadline)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Execute() took %v, want about 200ms", elapsed)
+	}
+}
+
+func TestBatchExecutorInterrupted(t *testing.T) {
+	hosts := []model.Connection{silentHost(t), silentHost(t)}
+	executor := NewBatchExecutor(hosts)
+	executor.SetTimeout(time.Minute)
+	executor.SetParallel(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+	results := executor.Execute(ctx, "true")
+	for _, r := range results {
+		if !errors.Is(r.Error, ErrInterrupted) {
+			t.Errorf("Execute() error = %v, want %v", r.Error, ErrInterrupted)
+		}
+	}
+}
+
+func TestBatchExecutorProgress(t *testing.T) {
+	hosts := []model.Connection{silentHost(t), silentHost(t), silentHost(t)}
+	executor := NewBatchExecutor(hosts)
+	executor.SetTimeout(100 * time.Millisecond)
+	executor.SetParallel(2)
+
+	var updates []BatchProgress
+	executor.SetProgress(func(p BatchProgress) {
+		if p.Running > 2 {
+			t.Errorf("progress shows %d running, more than the 2 in parallel", p.Running)
+		}
+		updates = append(updates, p)
+	})
+	executor.Execute(context.Background(), "true")
+
+	// Every host starts and finishes
+	if len(updates) != 6 {
+		t.Fatalf("progress reported %d times, want 6", len(updates))
+	}
+	last := updates[len(updates)-1]
+	if last.Total != 3 || last.Done != 3 || last.Failed != 3 || last.Running != 0 || last.Result == nil {
+		t.Errorf("last progress = %+v, want 3 done and failed", last)
+	}
+	if last.ETA() != 0 {
+		t.Errorf("ETA() of a finished run = %v, want 0", last.ETA())
+	}
+}
+
+func TestBatchExecutorPty(t *testing.T) {
+	executor := NewBatchExecutor([]model.Connection{startExecServer(t)})
+	executor.SetPty(80, 24)
+	executor.SetStripColor(true)
+
+	results := executor.Execute(context.Background(), "echo \x1b[1;31mfailed\x1b[0m 3\r\x1b[Kdone")
+	if results[0].Error != nil {
+		t.Fatalf("Execute() error = %v", results[0].Error)
+	}
+	if want := "done\n"; results[0].Output != want {
+		t.Errorf("Execute() output = %q, want %q", results[0].Output, want)
+	}
+}
+
+func TestBatchExecutorJumpHosts(t *testing.T) {
+	bastion, target := startExecServer(t), startExecServer(t)
+	bastion.Name, target.Name = "bastion", "db"
+	target.JumpHosts = []string{"bastion"}
+	down := model.Connection{Name: "down", Host: "127.0.0.1", Port: closedPort(t), User: "test"}
+
+	tests := []struct {
+		name    string
+		jump    model.Connection
+		wantErr bool
+	}{
+		{"through bastion", bastion, false},
+		// The target answers directly, so only a run through the jump host fails
+		{"jump host down", down, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := target
+			conn.Jumps = []model.Connection{tt.jump}
+
+			results := NewBatchExecutor([]model.Connection{conn}).Execute(context.Background(), "exit 3")
+			var jumpErr *JumpError
+			if tt.wantErr {
+				if !errors.As(results[0].Error, &jumpErr) || jumpErr.Hop != 1 {
+					t.Errorf("Execute() error = %v, want hop 1 to fail", results[0].Error)
+				}
+				return
+			}
+			if results[0].ExitCode != 3 {
+				t.Errorf("Execute() exit code = %d (error %v), want 3", results[0].ExitCode, results[0].Error)
+			}
+		})
+	}
+}
+
+func TestBatchExecutorReusesWarmConnections(t *testing.T) {
+	conn := startExecServer(t)
+	dials := 0
+	p := countingPool(&dials)
+	p.SetIdleTimeout(time.Minute)
+	defer p.SetIdleTimeout(0)
+
+	run := func(hostKeyCallback ssh.HostKeyCallback) BatchResult {
+		executor := NewBatchExecutor([]model.Connection{conn})
+		executor.pool = p
+		executor.SetHostKeyCallback(hostKeyCallback)
+		return executor.Execute(context.Background(), "exit 2")[0]
+	}
+
+	for i := 0; i < 2; i++ {
+		if r := run(nil); r.ExitCode != 2 {
+			t.Fatalf("run %d exit code = %d (error %v), want 2", i+1, r.ExitCode, r.Error)
+		}
+	}
+	if dials != 1 || !p.Warm(conn) {
+		t.Errorf("dials = %d, warm = %v; want one dial kept warm", dials, p.Warm(conn))
+	}
+
+	// The warm connection's host key is checked again before it is reused
+	reject := func(string, net.Addr, ssh.PublicKey) error { return ErrHostKeyChanged }
+	if r := run(reject); !errors.Is(r.Error, ErrHostKeyChanged) {
+		t.Errorf("run with a rejecting callback error = %v, want ErrHostKeyChanged", r.Error)
+	}
+}
@@ -0,0 +1,59 @@
+package ssh
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// BindIP resolves a connection's bind address, either a local IP address
+// or the name of a network interface, to the IP outbound connections should
+// start from. An interface resolves to its first IPv4 address, or its first
+// IPv6 address when it has none. An empty bind address resolves to nil.
+func BindIP(bind string) (net.IP, error) {
+	if bind == "" {
+		return nil, nil
+	}
+	if ip := net.ParseIP(bind); ip != nil {
+		return ip, nil
+	}
+
+	iface, err := net.InterfaceByName(bind)
+	if err != nil {
+		return nil, fmt.Errorf("bind address %s: not an IP address or interface", bind)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("bind address %s: %w", bind, err)
+	}
+	var ipv6 net.IP
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if ip4 := ipNet.IP.To4(); ip4 != nil {
+			return ip4, nil
+		}
+		if ipv6 == nil && !ipNet.IP.IsLinkLocalUnicast() {
+			ipv6 = ipNet.IP
+		}
+	}
+	if ipv6 == nil {
+		return nil, fmt.Errorf("bind address %s: interface has no usable address", bind)
+	}
+	return ipv6, nil
+}
+
+// newDialer returns a dialer whose connections start from bind
+func newDialer(bind string, timeout time.Duration) (*net.Dialer, error) {
+	ip, err := BindIP(bind)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+	if ip != nil {
+		dialer.LocalAddr = &net.TCPAddr{IP: ip}
+	}
+	return dialer, nil
+}
@@ -0,0 +1,84 @@
+package ssh
+
+import (
+	"io"
+	"net"
+	"testing"
+)
+
+func TestBindIP(t *testing.T) {
+	if ip, err := BindIP(""); ip != nil || err != nil {
+		t.Errorf("BindIP(\"\") = %v, %v; want nil, nil", ip, err)
+	}
+	if ip, err := BindIP("127.0.0.1"); err != nil || !ip.Equal(net.IPv4(127, 0, 0, 1)) {
+		t.Errorf("BindIP(127.0.0.1) = %v, %v", ip, err)
+	}
+	if _, err := BindIP("no-such-iface0"); err == nil {
+		t.Error("BindIP should fail for an unknown interface")
+	}
+
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		t.Fatalf("Failed to list interfaces: %v", err)
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback == 0 {
+			continue
+		}
+		ip, err := BindIP(iface.Name)
+		if err != nil {
+			t.Fatalf("BindIP(%s) failed: %v", iface.Name, err)
+		}
+		if !ip.IsLoopback() {
+			t.Errorf("BindIP(%s) = %v, want a loopback address", iface.Name, ip)
+		}
+		break
+	}
+}
+
+func TestConnectBindAddress(t *testing.T) {
+	conn := startExecServer(t)
+	conn.BindAddress = "127.0.0.1"
+
+	client, err := ConnectWithConnection(conn, nil)
+	if err != nil {
+		t.Fatalf("ConnectWithConnection failed: %v", err)
+	}
+	defer client.Close()
+	if ip := client.LocalAddr().(*net.TCPAddr).IP; !ip.Equal(net.IPv4(127, 0, 0, 1)) {
+		t.Errorf("LocalAddr = %v, want 127.0.0.1", ip)
+	}
+
+	conn.BindAddress = "no-such-iface0"
+	if _, err := ConnectWithConnection(conn, nil); err == nil {
+		t.Error("ConnectWithConnection should fail for an unknown bind interface")
+	}
+}
+
+func TestLocalForwardListensOnBindAddress(t *testing.T) {
+	f := NewForwarder(startExecServer(t))
+	f.conn.BindAddress = "127.0.0.1"
+	f.SetOutput(io.Discard)
+	defer f.Stop()
+
+	implicit, err := ParsePortForward(ForwardLocal, "0:db:5432")
+	if err != nil {
+		t.Fatalf("ParsePortForward failed: %v", err)
+	}
+	explicit, err := ParsePortForward(ForwardLocal, "localhost:0:db:5432")
+	if err != nil {
+		t.Fatalf("ParsePortForward failed: %v", err)
+	}
+	for _, pf := range []*PortForward{implicit, explicit} {
+		if err := f.startLocalForward(pf); err != nil {
+			t.Fatalf("startLocalForward(%s) failed: %v", pf, err)
+		}
+	}
+
+	if implicit.LocalHost != "127.0.0.1" {
+		t.Errorf("implicit LocalHost = %q, want the bind address", implicit.LocalHost)
+	}
+	if explicit.LocalHost != "localhost" {
+		t.Errorf("explicit LocalHost = %q, want localhost", explicit.LocalHost)
+	}
+}
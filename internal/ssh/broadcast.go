@@ -0,0 +1,276 @@
+package ssh
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"gossh/internal/model"
+)
+
+// broadcastBuffer is how much recent output is kept per host
+const broadcastBuffer = 64 * 1024
+
+// broadcastInputQueue is how many keyboard inputs may wait for a host that
+// is slow to take them before more are dropped
+const broadcastInputQueue = 256
+
+// BroadcastHost is a snapshot of one host in a broadcast
+type BroadcastHost struct {
+	Connection model.Connection
+	Output     []byte // Most recent output
+	Connected  bool
+	Error      error // Why the session could not start or ended
+	Dropped    int   // Inputs dropped because the host did not keep up
+}
+
+// Broadcast runs interactive shells on several hosts at once and sends the
+// same keystrokes to all of them, like clusterSSH
+type Broadcast struct {
+	hosts   []*broadcastHost
+	updates chan struct{}
+	done    chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+}
+
+type broadcastHost struct {
+	conn    model.Connection
+	client  *Client
+	session *Session
+
+	mu        sync.Mutex
+	input     chan []byte // Drained into the shell's stdin by the host's writer
+	out       []byte
+	connected bool
+	err       error
+	dropped   int
+}
+
+// NewBroadcast creates a broadcast to connections
+func NewBroadcast(connections []model.Connection) *Broadcast {
+	b := &Broadcast{
+		updates: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	for _, conn := range connections {
+		b.hosts = append(b.hosts, &broadcastHost{conn: conn})
+	}
+	return b
+}
+
+// Start opens a shell on every host concurrently, with a PTY of the given
+// size. Hosts that fail are reported in Hosts; the rest keep running.
+func (b *Broadcast) Start(width, height int) {
+	for _, h := range b.hosts {
+		go func(h *broadcastHost) {
+			if err := b.startHost(h, width, height); err != nil {
+				h.fail(err)
+				b.notify()
+			}
+		}(h)
+	}
+}
+
+// startHost connects and starts the shell, then waits for it in the background
+func (b *Broadcast) startHost(h *broadcastHost, width, height int) error {
+	h.client = NewClient(h.conn)
+	if err := h.client.Connect(); err != nil {
+		return err
+	}
+
+	session, err := h.client.NewSession()
+	if err != nil {
+		h.client.Close()
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	if err := session.RequestPty("xterm", height, width); err != nil {
+		session.Close()
+		h.client.Close()
+		return fmt.Errorf("failed to request pty: %w", err)
+	}
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		session.Close()
+		h.client.Close()
+		return fmt.Errorf("failed to open stdin: %w", err)
+	}
+	out := &broadcastWriter{host: h, notify: b.notify}
+	session.SetStdout(out)
+	session.SetStderr(out)
+	if err := session.Shell(); err != nil {
+		session.Close()
+		h.client.Close()
+		return fmt.Errorf("failed to start shell: %w", err)
+	}
+
+	// Close may have been called while we were connecting
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		session.Close()
+		h.client.Close()
+		return nil
+	}
+
+	// Each host takes its input in a goroutine of its own, so a stalled
+	// host never holds up the others or the caller of Send
+	input := make(chan []byte, broadcastInputQueue)
+	go writeInput(stdin, input)
+
+	h.mu.Lock()
+	h.session = session
+	h.input = input
+	h.connected = true
+	h.mu.Unlock()
+	b.notify()
+
+	go func() {
+		err := session.Wait()
+		if _, exited := ExitStatus(err); exited {
+			err = nil
+		}
+		h.fail(err)
+		h.client.Close()
+		b.notify()
+	}()
+	return nil
+}
+
+// fail marks the host as no longer running
+func (h *broadcastHost) fail(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.connected = false
+	if h.input != nil {
+		close(h.input)
+		h.input = nil
+	}
+	if err != nil {
+		h.err = err
+	}
+}
+
+// writeInput writes each input to stdin until input is closed. Once a write
+// fails the rest is discarded, the session is ending anyway.
+func writeInput(stdin io.Writer, input <-chan []byte) {
+	var err error
+	for data := range input {
+		if err == nil {
+			_, err = stdin.Write(data)
+		}
+	}
+}
+
+// broadcastWriter collects a host's output
+type broadcastWriter struct {
+	host   *broadcastHost
+	notify func()
+}
+
+func (w *broadcastWriter) Write(p []byte) (int, error) {
+	h := w.host
+	h.mu.Lock()
+	h.out = append(h.out, p...)
+	if over := len(h.out) - broadcastBuffer; over > 0 {
+		h.out = append([]byte(nil), h.out[over:]...)
+	}
+	h.mu.Unlock()
+	w.notify()
+	return len(p), nil
+}
+
+// notify signals an update without blocking; updates are coalesced
+func (b *Broadcast) notify() {
+	select {
+	case b.updates <- struct{}{}:
+	default:
+	}
+}
+
+// Updates receives a value whenever output arrives or a host's state changes
+func (b *Broadcast) Updates() <-chan struct{} {
+	return b.updates
+}
+
+// Done is closed when the broadcast is closed
+func (b *Broadcast) Done() <-chan struct{} {
+	return b.done
+}
+
+// Send queues keyboard input for one host, or for every running host when
+// target is negative. It never blocks: input for a host whose queue is full
+// is dropped and counted in its Dropped.
+func (b *Broadcast) Send(data []byte, target int) {
+	data = append([]byte(nil), data...)
+	dropped := false
+	for i, h := range b.hosts {
+		if target >= 0 && i != target {
+			continue
+		}
+		h.mu.Lock()
+		if h.input != nil {
+			select {
+			case h.input <- data:
+			default:
+				h.dropped++
+				dropped = true
+			}
+		}
+		h.mu.Unlock()
+	}
+	if dropped {
+		b.notify()
+	}
+}
+
+// Resize changes the PTY size of every running host
+func (b *Broadcast) Resize(width, height int) {
+	for _, h := range b.hosts {
+		h.mu.Lock()
+		session := h.session
+		connected := h.connected
+		h.mu.Unlock()
+		if connected {
+			_ = session.WindowChange(height, width)
+		}
+	}
+}
+
+// Hosts returns a snapshot of every host, in the order they were given
+func (b *Broadcast) Hosts() []BroadcastHost {
+	hosts := make([]BroadcastHost, len(b.hosts))
+	for i, h := range b.hosts {
+		h.mu.Lock()
+		hosts[i] = BroadcastHost{
+			Connection: h.conn,
+			Output:     append([]byte(nil), h.out...),
+			Connected:  h.connected,
+			Error:      h.err,
+			Dropped:    h.dropped,
+		}
+		h.mu.Unlock()
+	}
+	return hosts
+}
+
+// Close ends every session
+func (b *Broadcast) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	close(b.done)
+
+	for _, h := range b.hosts {
+		h.mu.Lock()
+		session := h.session
+		h.mu.Unlock()
+		if session != nil {
+			_ = session.Close()
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package ssh
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gossh/internal/model"
+)
+
+// waitHosts waits for the broadcast's hosts to satisfy done
+func waitHosts(t *testing.T, b *Broadcast, done func([]BroadcastHost) bool) []BroadcastHost {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		hosts := b.Hosts()
+		if done(hosts) {
+			return hosts
+		}
+		select {
+		case <-b.Updates():
+		case <-timeout:
+			t.Fatalf("timed out, hosts = %+v", hosts)
+		}
+	}
+}
+
+func TestBroadcast(t *testing.T) {
+	first, second := startExecServer(t), startExecServer(t)
+	down := model.Connection{Name: "down", Host: "127.0.0.1", Port: 1, User: "test"}
+
+	b := NewBroadcast([]model.Connection{first, second, down})
+	defer b.Close()
+	b.Start(80, 24)
+
+	hosts := waitHosts(t, b, func(hosts []BroadcastHost) bool {
+		return hosts[0].Connected && hosts[1].Connected && hosts[2].Error != nil
+	})
+	if hosts[2].Connected {
+		t.Error("unreachable host should not be connected")
+	}
+
+	// Typed to every host
+	b.Send([]byte("uptime\r"), -1)
+	waitHosts(t, b, func(hosts []BroadcastHost) bool {
+		return strings.Contains(string(hosts[0].Output), "uptime") && strings.Contains(string(hosts[1].Output), "uptime")
+	})
+
+	// Typed to one host only
+	b.Send([]byte("whoami\r"), 1)
+	waitHosts(t, b, func(hosts []BroadcastHost) bool {
+		return strings.Contains(string(hosts[1].Output), "whoami")
+	})
+	if strings.Contains(string(b.Hosts()[0].Output), "whoami") {
+		t.Error("input for one host reached another")
+	}
+
+	// Shells that exit end cleanly
+	b.Send([]byte("exit\r"), -1)
+	hosts = waitHosts(t, b, func(hosts []BroadcastHost) bool {
+		return !hosts[0].Connected && !hosts[1].Connected
+	})
+	if hosts[0].Error != nil || hosts[1].Error != nil {
+		t.Errorf("exited shells reported errors: %v, %v", hosts[0].Error, hosts[1].Error)
+	}
+}
+
+func TestBroadcastSendDoesNotBlock(t *testing.T) {
+	b := NewBroadcast([]model.Connection{{Name: "stalled"}, {Name: "idle"}})
+	defer b.Close()
+
+	// A host that takes no input, with room for one queued input
+	stalled := b.hosts[0]
+	stalled.input = make(chan []byte, 1)
+	stalled.connected = true
+
+	sent := make(chan struct{})
+	go func() {
+		for range 3 {
+			b.Send([]byte("x"), -1)
+		}
+		close(sent)
+	}()
+	select {
+	case <-sent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Send blocked on a stalled host")
+	}
+
+	hosts := b.Hosts()
+	if hosts[0].Dropped != 2 {
+		t.Errorf("stalled host Dropped = %d, want 2", hosts[0].Dropped)
+	}
+	if hosts[1].Dropped != 0 {
+		t.Errorf("host that is not running Dropped = %d, want 0", hosts[1].Dropped)
+	}
+}
@@ -3,6 +3,7 @@ package ssh
 import (
 	"fmt"
 	"net"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/crypto/ssh"
@@ -13,11 +14,31 @@ const (
 	defaultTimeout = 30 * time.Second
 )
 
+// connectTimeoutSetting holds the connection_timeout setting, zero for the
+// default. The TUI changes it while background checks connect.
+var connectTimeoutSetting atomic.Int64
+
+// SetConnectTimeout sets how long connecting to a host may take, e.g. from
+// the connection_timeout setting. Zero restores the default.
+func SetConnectTimeout(timeout time.Duration) {
+	connectTimeoutSetting.Store(int64(max(timeout, 0)))
+}
+
+// connectTimeout bounds connecting to a host, from dialing to the end of
+// the SSH handshake
+func connectTimeout() time.Duration {
+	if timeout := time.Duration(connectTimeoutSetting.Load()); timeout > 0 {
+		return timeout
+	}
+	return defaultTimeout
+}
+
 // Client wraps an SSH client connection
 type Client struct {
 	conn            model.Connection
 	client          *ssh.Client
 	hostKeyCallback ssh.HostKeyCallback
+	address         string
 }
 
 // NewClient creates a new SSH client for a connection
@@ -30,13 +51,15 @@ func (c *Client) SetHostKeyCallback(callback ssh.HostKeyCallback) {
 	c.hostKeyCallback = callback
 }
 
-// Connect establishes the SSH connection using the factory function
+// Connect establishes the SSH connection, reusing an open connection to the
+// same host from DefaultPool when there is one
 func (c *Client) Connect() error {
-	client, err := ConnectWithConnection(c.conn, c.hostKeyCallback)
+	client, err := DefaultPool.Acquire(c.conn, c.hostKeyCallback)
 	if err != nil {
 		return err
 	}
 	c.client = client
+	c.address = DefaultPool.Address(client)
 	return nil
 }
 
@@ -48,10 +71,24 @@ func (c *Client) Conn() ssh.Conn {
 	return nil
 }
 
-// Close closes the SSH connection
+// Close releases the SSH connection. It is only closed once no other
+// feature is using it.
 func (c *Client) Close() error {
 	if c.client != nil {
-		return c.client.Close()
+		client := c.client
+		c.client = nil
+		return DefaultPool.Release(client)
+	}
+	return nil
+}
+
+// Kill closes the SSH connection for every feature sharing it, e.g. when
+// the connection hangs
+func (c *Client) Kill() error {
+	if c.client != nil {
+		client := c.client
+		c.client = nil
+		return DefaultPool.Discard(client)
 	}
 	return nil
 }
@@ -70,6 +107,21 @@ func (c *Client) NewSession() (*Session, error) {
 	return &Session{session: session}, nil
 }
 
+// Banner returns the pre-login banner the server sent, empty if it sent
+// none or the client is not connected
+func (c *Client) Banner() string {
+	if c.client != nil {
+		return DefaultPool.Banner(c.client)
+	}
+	return ""
+}
+
+// Address returns the address the host was reached at by the last Connect,
+// also after the connection is closed
+func (c *Client) Address() string {
+	return c.address
+}
+
 // LocalAddr returns the local address of the connection
 func (c *Client) LocalAddr() net.Addr {
 	if c.client != nil {
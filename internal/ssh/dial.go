@@ -0,0 +1,116 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// fallbackDelay is how long an address is tried alone before the next
+	// one is dialed alongside it
+	fallbackDelay = 300 * time.Millisecond
+
+	// addressTimeout bounds each address when a host has several, so an
+	// unreachable one does not hold up the rest for the full timeout
+	addressTimeout = 10 * time.Second
+)
+
+// dialFirst connects to the first of addrs that answers. Addresses are
+// tried in order, each getting a head start of fallbackDelay, so a fast
+// later address wins over one that hangs. It returns the address used.
+func dialFirst(dialer *net.Dialer, addrs []string) (net.Conn, string, error) {
+	if len(addrs) == 0 {
+		return nil, "", errors.New("no address to dial")
+	}
+	if len(addrs) == 1 {
+		conn, err := dialer.Dial("tcp", addrs[0])
+		return conn, addrs[0], err
+	}
+	if dialer.Timeout == 0 || dialer.Timeout > addressTimeout {
+		d := *dialer
+		d.Timeout = addressTimeout
+		dialer = &d
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type result struct {
+		conn net.Conn
+		addr string
+		err  error
+	}
+	results := make(chan result, len(addrs))
+	next, pending := 0, 0
+	start := func() {
+		addr := addrs[next]
+		next++
+		pending++
+		go func() {
+			conn, err := dialer.DialContext(ctx, "tcp", addr)
+			results <- result{conn, addr, err}
+		}()
+	}
+
+	var errs []error
+	start()
+	for pending > 0 {
+		var delay <-chan time.Time
+		if next < len(addrs) {
+			delay = time.After(fallbackDelay)
+		}
+		select {
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				// Close attempts that connect after the winner
+				go func(n int) {
+					for ; n > 0; n-- {
+						if late := <-results; late.conn != nil {
+							late.conn.Close()
+						}
+					}
+				}(pending)
+				return r.conn, r.addr, nil
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", r.addr, r.err))
+			if next < len(addrs) {
+				start()
+			}
+		case <-delay:
+			start()
+		}
+	}
+	return nil, "", errors.Join(errs...)
+}
+
+// dialSSH connects from bind to the first reachable of addrs and runs the
+// SSH handshake. Host keys are checked against hostAddr whichever address
+// answered, so the other addresses of a host need no known_hosts entries
+// of their own; an empty hostAddr checks them against the address used.
+// It returns the host part of the address used.
+func dialSSH(bind string, addrs []string, hostAddr string, config *ssh.ClientConfig) (*ssh.Client, string, error) {
+	dialer, err := newDialer(bind, config.Timeout)
+	if err != nil {
+		return nil, "", err
+	}
+	netConn, addr, err := dialFirst(dialer, addrs)
+	if err != nil {
+		return nil, "", err
+	}
+	if hostAddr == "" {
+		hostAddr = addr
+	}
+	c, chans, reqs, err := ssh.NewClientConn(netConn, hostAddr, config)
+	if err != nil {
+		netConn.Close()
+		return nil, "", err
+	}
+	host, _, _ := net.SplitHostPort(addr)
+	return ssh.NewClient(c, chans, reqs), host, nil
+}
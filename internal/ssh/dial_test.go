@@ -0,0 +1,85 @@
+package ssh
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// closedAddr returns an address nothing listens on
+func closedAddr(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+	return addr
+}
+
+// closedPort returns a local port nothing listens on
+func closedPort(t *testing.T) int {
+	t.Helper()
+	_, port, _ := net.SplitHostPort(closedAddr(t))
+	n, _ := strconv.Atoi(port)
+	return n
+}
+
+func TestDialFirstFallsBack(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	live := listener.Addr().String()
+	conn, addr, err := dialFirst(&net.Dialer{}, []string{closedAddr(t), live})
+	if err != nil {
+		t.Fatalf("dialFirst failed: %v", err)
+	}
+	defer conn.Close()
+	if addr != live {
+		t.Errorf("dialFirst used %s, want %s", addr, live)
+	}
+}
+
+func TestDialFirstAllFail(t *testing.T) {
+	a, b := closedAddr(t), closedAddr(t)
+	_, _, err := dialFirst(&net.Dialer{}, []string{a, b})
+	if err == nil {
+		t.Fatal("dialFirst should fail when no address answers")
+	}
+	if !strings.Contains(err.Error(), a) || !strings.Contains(err.Error(), b) {
+		t.Errorf("error %q should name every address", err)
+	}
+}
+
+func TestConnectFallbackAddress(t *testing.T) {
+	conn := startExecServer(t)
+	// Nothing listens on 127.0.0.2, so the host falls back to 127.0.0.1
+	conn.Addresses = []string{conn.Host}
+	conn.Host = "127.0.0.2"
+	primary := net.JoinHostPort(conn.Host, strconv.Itoa(conn.Port))
+
+	var checked string
+	client := NewClient(conn)
+	client.SetHostKeyCallback(func(hostname string, _ net.Addr, _ ssh.PublicKey) error {
+		checked = hostname
+		return nil
+	})
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer client.Close()
+
+	if got := client.Address(); got != "127.0.0.1" {
+		t.Errorf("Address() = %q, want 127.0.0.1", got)
+	}
+	if checked != primary {
+		t.Errorf("host key checked for %q, want the primary address %q", checked, primary)
+	}
+}
@@ -0,0 +1,253 @@
+package ssh
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// diffContext is how many unchanged lines a diff shows around each change
+const diffContext = 3
+
+// maxDiffCells bounds the line comparison table of a diff; larger outputs
+// are shown as replaced outright
+const maxDiffCells = 1 << 22
+
+// OutputGroup is a set of hosts a command gave the same result on
+type OutputGroup struct {
+	Output   string
+	ExitCode int
+	Error    string   // Why the hosts gave no result, e.g. a connection error
+	Hosts    []string // Connection names, in result order
+}
+
+// GroupOutputs groups batch results by identical output and exit status,
+// ignoring trailing newlines. Hosts that could not run the command are
+// grouped by error. The largest group comes first, ties in result order.
+func GroupOutputs(results []BatchResult) []OutputGroup {
+	var groups []OutputGroup
+	for _, r := range results {
+		g := OutputGroup{Output: strings.TrimRight(r.Output, "\n"), ExitCode: r.ExitCode}
+		// An exit status means the command ran and its output counts
+		if r.Error != nil && r.ExitCode == 0 {
+			g.Error = r.Error.Error()
+		}
+		i := slices.IndexFunc(groups, func(o OutputGroup) bool {
+			return o.Output == g.Output && o.ExitCode == g.ExitCode && o.Error == g.Error
+		})
+		if i < 0 {
+			groups = append(groups, g)
+			i = len(groups) - 1
+		}
+		groups[i].Hosts = append(groups[i].Hosts, r.Connection.Name)
+	}
+	slices.SortStableFunc(groups, func(a, b OutputGroup) int {
+		return cmp.Compare(len(b.Hosts), len(a.Hosts))
+	})
+	return groups
+}
+
+// diffOp is one line of a diff: kept (' '), removed ('-') or added ('+')
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// diffLines returns the edits turning a into b, by longest common
+// subsequence
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	ops := make([]diffOp, 0, n+m)
+	if n*m > maxDiffCells {
+		for _, l := range a {
+			ops = append(ops, diffOp{'-', l})
+		}
+		for _, l := range b {
+			ops = append(ops, diffOp{'+', l})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:]
+	lcs := make([][]int32, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// splitLines splits output into lines, none for empty output
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimRight(s, "\n"), "\n")
+}
+
+// UnifiedDiff returns a unified diff from a to b with three lines of
+// context, headed by their names, or "" if they are the same
+func UnifiedDiff(aName, bName, a, b string) string {
+	ops := diffLines(splitLines(a), splitLines(b))
+	if !slices.ContainsFunc(ops, func(op diffOp) bool { return op.kind != ' ' }) {
+		return ""
+	}
+
+	// Line numbers in a and b before each op
+	aPos := make([]int, len(ops)+1)
+	bPos := make([]int, len(ops)+1)
+	for k, op := range ops {
+		aPos[k+1], bPos[k+1] = aPos[k], bPos[k]
+		if op.kind != '+' {
+			aPos[k+1]++
+		}
+		if op.kind != '-' {
+			bPos[k+1]++
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", aName, bName)
+	for k := 0; k < len(ops); {
+		for k < len(ops) && ops[k].kind == ' ' {
+			k++
+		}
+		if k == len(ops) {
+			break
+		}
+
+		// A hunk runs on while the next change is close enough that
+		// their context would touch
+		last := k
+		for j := k + 1; j < len(ops) && j-last <= 2*diffContext+1; j++ {
+			if ops[j].kind != ' ' {
+				last = j
+			}
+		}
+		lo := max(k-diffContext, 0)
+		hi := min(last+diffContext+1, len(ops))
+
+		aCount, bCount := aPos[hi]-aPos[lo], bPos[hi]-bPos[lo]
+		aStart, bStart := aPos[lo], bPos[lo]
+		if aCount > 0 {
+			aStart++
+		}
+		if bCount > 0 {
+			bStart++
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", aStart, aCount, bStart, bCount)
+		for _, op := range ops[lo:hi] {
+			out.WriteByte(op.kind)
+			out.WriteString(op.line)
+			out.WriteByte('\n')
+		}
+		k = hi
+	}
+	return out.String()
+}
+
+// DiffRenderer writes batch results grouped by identical output: each
+// variant once with the hosts that gave it, then how every other variant
+// differs from the most common one
+type DiffRenderer struct{}
+
+// Render writes the results grouped by output
+func (DiffRenderer) Render(w io.Writer, command string, results []BatchResult) error {
+	groups := GroupOutputs(results)
+
+	var variants []OutputGroup
+	for _, g := range groups {
+		if g.Error == "" {
+			variants = append(variants, g)
+		}
+	}
+
+	for i, g := range variants {
+		status := "✓"
+		if g.ExitCode != 0 {
+			status = "✗"
+		}
+		fmt.Fprintf(w, "\n%s Variant %d: %s", status, i+1, hostList(g.Hosts))
+		if g.ExitCode != 0 {
+			fmt.Fprintf(w, ", exit %d", g.ExitCode)
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, strings.Repeat("─", 40))
+		if g.Output != "" {
+			fmt.Fprintln(w, g.Output)
+		}
+	}
+
+	for _, g := range groups {
+		if g.Error != "" {
+			fmt.Fprintf(w, "\n✗ Failed: %s\n", hostList(g.Hosts))
+			fmt.Fprintln(w, strings.Repeat("─", 40))
+			fmt.Fprintf(w, "Error: %s\n", g.Error)
+		}
+	}
+
+	if len(variants) > 1 {
+		fmt.Fprintln(w, "\n"+strings.Repeat("─", 80))
+		fmt.Fprintln(w, "DIFFERENCES FROM VARIANT 1")
+		for i, g := range variants[1:] {
+			fmt.Fprintln(w)
+			fmt.Fprint(w, UnifiedDiff("variant 1", fmt.Sprintf("variant %d", i+2), variants[0].Output, g.Output))
+		}
+	}
+
+	fmt.Fprintln(w, "\n"+strings.Repeat("─", 80))
+	_, err := fmt.Fprintf(w, "Summary: %d variant(s) across %d host(s), %d failed to run\n",
+		len(variants), len(results), len(results)-variantHosts(variants))
+	return err
+}
+
+// hostList formats the hosts of a group with their count
+func hostList(hosts []string) string {
+	noun := "hosts"
+	if len(hosts) == 1 {
+		noun = "host"
+	}
+	return fmt.Sprintf("%d %s (%s)", len(hosts), noun, strings.Join(hosts, ", "))
+}
+
+// variantHosts counts the hosts of the groups
+func variantHosts(groups []OutputGroup) int {
+	n := 0
+	for _, g := range groups {
+		n += len(g.Hosts)
+	}
+	return n
+}
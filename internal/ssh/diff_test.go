@@ -0,0 +1,81 @@
+package ssh
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"gossh/internal/model"
+)
+
+func TestGroupOutputs(t *testing.T) {
+	result := func(name, output string, exitCode int, err error) BatchResult {
+		return BatchResult{Connection: model.Connection{Name: name}, Output: output, ExitCode: exitCode, Error: err}
+	}
+	results := []BatchResult{
+		result("web-01", "ntp1\n", 0, nil),
+		result("web-02", "ntp2\n", 0, nil),
+		result("web-03", "ntp1", 0, nil),
+		result("web-04", "", 0, errors.New("connection refused")),
+		result("web-05", "ntp1\n", 1, errors.New("Process exited with status 1")),
+		result("web-06", "ntp2\n", 0, nil),
+		result("web-07", "ntp1\n", 0, nil),
+	}
+
+	groups := GroupOutputs(results)
+	want := []OutputGroup{
+		{Output: "ntp1", Hosts: []string{"web-01", "web-03", "web-07"}},
+		{Output: "ntp2", Hosts: []string{"web-02", "web-06"}},
+		{Error: "connection refused", Hosts: []string{"web-04"}},
+		{Output: "ntp1", ExitCode: 1, Hosts: []string{"web-05"}},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("GroupOutputs() = %+v, want %+v", groups, want)
+	}
+	for i, g := range groups {
+		w := want[i]
+		if g.Output != w.Output || g.ExitCode != w.ExitCode || g.Error != w.Error || !slices.Equal(g.Hosts, w.Hosts) {
+			t.Errorf("GroupOutputs()[%d] = %+v, want %+v", i, g, w)
+		}
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{
+			name: "same",
+			a:    "a\nb\n",
+			b:    "a\nb",
+			want: "",
+		},
+		{
+			name: "changed line",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9",
+			b:    "1\n2\n3\n4\nfive\n6\n7\n8\n9",
+			want: "--- a\n+++ b\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "separate hunks",
+			a:    "x\n1\n2\n3\n4\n5\n6\n7\n8\ny",
+			b:    "X\n1\n2\n3\n4\n5\n6\n7\n8\nY",
+			want: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n-x\n+X\n 1\n 2\n 3\n@@ -7,4 +7,4 @@\n 6\n 7\n 8\n-y\n+Y\n",
+		},
+		{
+			name: "added to empty",
+			a:    "",
+			b:    "new",
+			want: "--- a\n+++ b\n@@ -0,0 +1,1 @@\n+new\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnifiedDiff("a", "b", tt.a, tt.b); got != tt.want {
+				t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package ssh
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gossh/internal/model"
+)
+
+// envName is what a variable name given to a batch run may look like, so
+// it is safe to put unquoted in a shell command
+var envName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ParseEnv parses a KEY=VALUE pair of a variable to set for a batch run
+func ParseEnv(pair string) (name, value string, err error) {
+	name, value, ok := strings.Cut(pair, "=")
+	if !ok || !envName.MatchString(name) {
+		return "", "", fmt.Errorf("invalid environment variable %q, want KEY=VALUE", pair)
+	}
+	return name, value, nil
+}
+
+// ReadEnvFile reads the variables of a .env file into env: KEY=VALUE lines,
+// optionally after "export " and with the value in quotes. Blank lines and
+// lines starting with # are skipped.
+func ReadEnvFile(path string, env map[string]string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		name, value, err := ParseEnv(line)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		value = strings.TrimSpace(value)
+		switch {
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			if value, err = strconv.Unquote(value); err != nil {
+				return fmt.Errorf("%s:%d: invalid value of %s: %w", path, n, name, err)
+			}
+		}
+		env[name] = value
+	}
+	return scanner.Err()
+}
+
+// envNames returns the names of env in order
+func envNames(env map[string]string) []string {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// exportCommand prefixes command with an export of the variables in names,
+// for servers that refuse to set them for the session: sshd only accepts
+// those listed in its AcceptEnv
+func exportCommand(command string, env map[string]string, names []string) string {
+	if len(names) == 0 {
+		return command
+	}
+	assignments := make([]string, len(names))
+	for i, name := range names {
+		assignments[i] = name + "=" + model.ShellQuote(env[name])
+	}
+	return "export " + strings.Join(assignments, " ") + "; " + command
+}
@@ -0,0 +1,93 @@
+package ssh
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gossh/internal/model"
+)
+
+func TestParseEnv(t *testing.T) {
+	tests := []struct {
+		pair        string
+		name, value string
+		wantErr     bool
+	}{
+		{"STAGE=prod", "STAGE", "prod", false},
+		{"URL=http://x/?a=b", "URL", "http://x/?a=b", false},
+		{"EMPTY=", "EMPTY", "", false},
+		{"NOVALUE", "", "", true},
+		{"1ST=x", "", "", true},
+		{"A;rm -rf /=x", "", "", true},
+	}
+	for _, tt := range tests {
+		name, value, err := ParseEnv(tt.pair)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseEnv(%q) error = %v, wantErr %v", tt.pair, err, tt.wantErr)
+			continue
+		}
+		if name != tt.name || value != tt.value {
+			t.Errorf("ParseEnv(%q) = %q, %q, want %q, %q", tt.pair, name, value, tt.name, tt.value)
+		}
+	}
+}
+
+func TestReadEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	content := "# deploy settings\n\nSTAGE=prod\nexport REGION=eu-west-1\nGREETING=\"hello\\tworld\"\nQUOTED='it is $HOME'\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	env := map[string]string{"STAGE": "dev", "KEEP": "1"}
+	if err := ReadEnvFile(path, env); err != nil {
+		t.Fatalf("ReadEnvFile failed: %v", err)
+	}
+	want := map[string]string{"STAGE": "prod", "KEEP": "1", "REGION": "eu-west-1", "GREETING": "hello\tworld", "QUOTED": "it is $HOME"}
+	if len(env) != len(want) {
+		t.Errorf("ReadEnvFile gave %v, want %v", env, want)
+	}
+	for name, value := range want {
+		if env[name] != value {
+			t.Errorf("%s = %q, want %q", name, env[name], value)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("STAGE=prod\nnot a variable\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReadEnvFile(path, map[string]string{}); err == nil {
+		t.Error("ReadEnvFile accepted a line that is no variable")
+	}
+}
+
+func TestExportCommand(t *testing.T) {
+	env := map[string]string{"STAGE": "prod", "NOTE": "it's done"}
+	got := exportCommand("./deploy.sh", env, envNames(env))
+	if want := `export NOTE='it'\''s done' STAGE=prod; ./deploy.sh`; got != want {
+		t.Errorf("exportCommand() = %q, want %q", got, want)
+	}
+	if got := exportCommand("./deploy.sh", env, nil); got != "./deploy.sh" {
+		t.Errorf("exportCommand() with nothing refused = %q, want the command", got)
+	}
+}
+
+func TestHostEnv(t *testing.T) {
+	b := NewBatchExecutor(nil)
+	b.SetEnv(map[string]string{"STAGE": "prod", "VERSION": "1"})
+	web1 := model.Connection{ID: "web-1", Host: "10.0.0.1", Port: 22}
+	b.SetHostEnv(map[string]map[string]string{TargetKey(web1): {"VERSION": "2", "PID": "42"}})
+
+	got := b.envFor(web1)
+	if want := map[string]string{"STAGE": "prod", "VERSION": "2", "PID": "42"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("envFor(web-1) = %v, want %v", got, want)
+	}
+	if got := b.envFor(model.Connection{ID: "web-2", Host: "10.0.0.2", Port: 22}); !reflect.DeepEqual(got, map[string]string{"STAGE": "prod", "VERSION": "1"}) {
+		t.Errorf("envFor(web-2) = %v, want the shared variables", got)
+	}
+	if b.env["VERSION"] != "1" {
+		t.Error("envFor() changed the shared variables")
+	}
+}
@@ -0,0 +1,128 @@
+package ssh
+
+import (
+	"io"
+)
+
+// EscapeChar starts an OpenSSH-style escape sequence when typed at the
+// beginning of a line
+const EscapeChar = '~'
+
+// EscapeAction is a local command typed as an escape sequence
+type EscapeAction int
+
+const (
+	EscapeNone       EscapeAction = iota
+	EscapeDisconnect              // ~.  drop the connection, even if it hangs
+	EscapeCommand                 // ~C  open the command line to add forwards
+	EscapeSuspend                 // ~Z  or ~^Z, suspend the session
+	EscapeHelp                    // ~?  list the escape sequences
+)
+
+// escapeHelp lists the supported escape sequences
+const escapeHelp = "Supported escape sequences:\r\n" +
+	" ~.   - terminate connection\r\n" +
+	" ~C   - open a command line (-L/-R/-D to add forwards)\r\n" +
+	" ~Z   - suspend the session\r\n" +
+	" ~?   - this message\r\n" +
+	" ~~   - send the escape character\r\n" +
+	"(Note that escapes are only recognized immediately after newline.)\r\n"
+
+// EscapeFilter removes escape sequences from keyboard input. It keeps
+// state between calls, so sequences split across reads are still found.
+type EscapeFilter struct {
+	atLineStart bool
+	pending     bool
+}
+
+// NewEscapeFilter creates a filter that starts at the beginning of a line
+func NewEscapeFilter() *EscapeFilter {
+	return &EscapeFilter{atLineStart: true}
+}
+
+// Reset forgets any partial sequence, e.g. when a session is resumed
+func (f *EscapeFilter) Reset() {
+	f.atLineStart = true
+	f.pending = false
+}
+
+// Filter scans in up to the first escape sequence. It returns the bytes to
+// send to the remote side, the action found (EscapeNone if none) and the
+// unscanned remainder of in.
+func (f *EscapeFilter) Filter(in []byte) (out []byte, action EscapeAction, rest []byte) {
+	out = make([]byte, 0, len(in))
+
+	for i, b := range in {
+		if f.pending {
+			f.pending = false
+			switch b {
+			case '.':
+				action = EscapeDisconnect
+			case 'C':
+				action = EscapeCommand
+			case 'Z', 0x1a:
+				action = EscapeSuspend
+			case '?':
+				action = EscapeHelp
+			case EscapeChar:
+				out = append(out, EscapeChar)
+				f.atLineStart = false
+				continue
+			default:
+				// Not an escape after all: pass both characters through
+				out = append(out, EscapeChar, b)
+				f.atLineStart = isNewline(b)
+				continue
+			}
+			f.atLineStart = true
+			return out, action, in[i+1:]
+		}
+
+		if f.atLineStart && b == EscapeChar {
+			f.pending = true
+			continue
+		}
+
+		out = append(out, b)
+		f.atLineStart = isNewline(b)
+	}
+
+	return out, EscapeNone, nil
+}
+
+func isNewline(b byte) bool {
+	return b == '\r' || b == '\n'
+}
+
+// commandLine collects the text typed after ~C. The terminal is in raw
+// mode, so it echoes and handles backspace itself.
+type commandLine struct {
+	buf  []byte
+	echo io.Writer
+}
+
+// feed consumes input until Enter, Ctrl-C or Escape. It returns the
+// unconsumed input and, once finished, the line (empty when cancelled).
+func (c *commandLine) feed(in []byte) (rest []byte, line string, done bool) {
+	for i, b := range in {
+		switch b {
+		case '\r', '\n':
+			_, _ = c.echo.Write([]byte("\r\n"))
+			return in[i+1:], string(c.buf), true
+		case 0x03, 0x1b:
+			_, _ = c.echo.Write([]byte("\r\n"))
+			return in[i+1:], "", true
+		case 0x7f, 0x08:
+			if len(c.buf) > 0 {
+				c.buf = c.buf[:len(c.buf)-1]
+				_, _ = c.echo.Write([]byte("\b \b"))
+			}
+		default:
+			if b >= 0x20 {
+				c.buf = append(c.buf, b)
+				_, _ = c.echo.Write([]byte{b})
+			}
+		}
+	}
+	return nil, "", false
+}
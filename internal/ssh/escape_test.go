@@ -0,0 +1,96 @@
+package ssh
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEscapeFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantOut    string
+		wantAction EscapeAction
+		wantRest   string
+	}{
+		{"plain input", "ls -la\r", "ls -la\r", EscapeNone, ""},
+		{"disconnect at start", "~.", "", EscapeDisconnect, ""},
+		{"disconnect after newline", "exit\r~.more", "exit\r", EscapeDisconnect, "more"},
+		{"command", "~Cextra", "", EscapeCommand, "extra"},
+		{"suspend", "~Z", "", EscapeSuspend, ""},
+		{"suspend ctrl-z", "~\x1a", "", EscapeSuspend, ""},
+		{"help", "~?", "", EscapeHelp, ""},
+		{"literal tilde", "~~", "~", EscapeNone, ""},
+		{"not an escape", "~x", "~x", EscapeNone, ""},
+		{"mid-line tilde", "cd ~.", "cd ~.", EscapeNone, ""},
+		{"home directory", "~/bin\r", "~/bin\r", EscapeNone, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, action, rest := NewEscapeFilter().Filter([]byte(tt.input))
+			if string(out) != tt.wantOut || action != tt.wantAction || string(rest) != tt.wantRest {
+				t.Errorf("Filter(%q) = %q, %d, %q; want %q, %d, %q",
+					tt.input, out, action, rest, tt.wantOut, tt.wantAction, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestEscapeFilterAcrossReads(t *testing.T) {
+	f := NewEscapeFilter()
+
+	// The tilde is held back until the next key shows whether it is an escape
+	out, action, _ := f.Filter([]byte("~"))
+	if len(out) != 0 || action != EscapeNone {
+		t.Fatalf("Filter(~) = %q, %d; want nothing yet", out, action)
+	}
+	if _, action, _ := f.Filter([]byte(".")); action != EscapeDisconnect {
+		t.Errorf("Filter(.) action = %d, want EscapeDisconnect", action)
+	}
+
+	// Mid-line state carries over as well
+	f.Reset()
+	f.Filter([]byte("echo "))
+	if out, action, _ := f.Filter([]byte("~.")); string(out) != "~." || action != EscapeNone {
+		t.Errorf("Filter(~.) mid-line = %q, %d; want passthrough", out, action)
+	}
+}
+
+func TestCommandLine(t *testing.T) {
+	var echo strings.Builder
+	c := &commandLine{echo: &echo}
+
+	rest, _, done := c.feed([]byte("-L 80"))
+	if done || len(rest) != 0 {
+		t.Fatalf("feed() finished early")
+	}
+	rest, line, done := c.feed([]byte("88\x7f0:localhost:80\rls"))
+	if !done || line != "-L 8080:localhost:80" || string(rest) != "ls" {
+		t.Errorf("feed() = %q, %q, %v; want the edited line", rest, line, done)
+	}
+
+	c = &commandLine{echo: &echo}
+	if _, line, done := c.feed([]byte("-L 1\x03")); !done || line != "" {
+		t.Errorf("feed() after Ctrl-C = %q, %v; want cancelled", line, done)
+	}
+}
+
+func TestDetachableWriter(t *testing.T) {
+	var b strings.Builder
+	d := newDetachableWriter(&b)
+
+	d.Write([]byte("prompt$ "))
+	if b.Len() != 0 {
+		t.Fatalf("detached writer wrote %q", b.String())
+	}
+
+	d.Attach()
+	d.Write([]byte("ls\r\n"))
+	d.Detach()
+	d.Write([]byte("late"))
+
+	if b.String() != "prompt$ ls\r\n" {
+		t.Errorf("output = %q, want buffered output replayed on attach", b.String())
+	}
+}
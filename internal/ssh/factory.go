@@ -1,14 +1,20 @@
 package ssh
 
 import (
+	"errors"
 	"fmt"
 	"net"
+	"strconv"
 	"time"
 
 	"golang.org/x/crypto/ssh"
 	"gossh/internal/model"
 )
 
+// ErrTelnet is returned when an SSH feature such as exec, SFTP or port
+// forwarding is used with a telnet connection
+var ErrTelnet = errors.New("not available for telnet connections")
+
 // ConnectOptions holds options for creating an SSH connection
 type ConnectOptions struct {
 	Host            string
@@ -17,20 +23,29 @@ type ConnectOptions struct {
 	AuthMethods     []ssh.AuthMethod
 	Timeout         time.Duration
 	HostKeyCallback ssh.HostKeyCallback
+	BannerCallback  ssh.BannerCallback
+	BindAddress     string   // Local IP or interface to connect from
+	Addresses       []string // Other addresses of Host, tried in order after it
 }
 
 // DefaultConnectOptions returns default connection options
 func DefaultConnectOptions() ConnectOptions {
 	return ConnectOptions{
-		Timeout:         defaultTimeout,
+		Timeout:         connectTimeout(),
 		HostKeyCallback: ssh.InsecureIgnoreHostKey(), // Will be replaced by HostKey verification
 	}
 }
 
 // Connect creates an SSH client connection with the given options
 func Connect(opts ConnectOptions) (*ssh.Client, error) {
+	client, _, err := connect(opts)
+	return client, err
+}
+
+// connect is Connect, also returning the address the host was reached at
+func connect(opts ConnectOptions) (*ssh.Client, string, error) {
 	if opts.Timeout == 0 {
-		opts.Timeout = defaultTimeout
+		opts.Timeout = connectTimeout()
 	}
 	if opts.HostKeyCallback == nil {
 		opts.HostKeyCallback = ssh.InsecureIgnoreHostKey()
@@ -40,23 +55,54 @@ func Connect(opts ConnectOptions) (*ssh.Client, error) {
 		User:            opts.User,
 		Auth:            opts.AuthMethods,
 		HostKeyCallback: opts.HostKeyCallback,
+		BannerCallback:  opts.BannerCallback,
 		Timeout:         opts.Timeout,
 	}
 
-	addr := fmt.Sprintf("%s:%d", opts.Host, opts.Port)
-	client, err := ssh.Dial("tcp", addr, config)
+	addr := net.JoinHostPort(opts.Host, strconv.Itoa(opts.Port))
+	addrs, hostAddr, err := dialTargets(model.Connection{Host: opts.Host, Port: opts.Port, Addresses: opts.Addresses})
+	if err != nil {
+		return nil, "", err
+	}
+	client, host, err := dialSSH(opts.BindAddress, addrs, hostAddr, config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to dial %s: %w", addr, err)
+		return nil, "", fmt.Errorf("failed to dial %s: %w", addr, err)
 	}
 
-	return client, nil
+	return client, host, nil
 }
 
 // ConnectWithConnection creates an SSH connection using a model.Connection
 func ConnectWithConnection(conn model.Connection, hostKeyCallback ssh.HostKeyCallback) (*ssh.Client, error) {
+	return ConnectWithBanner(conn, hostKeyCallback, nil)
+}
+
+// ConnectWithBanner is ConnectWithConnection, passing the banner the server
+// sends before authentication (e.g. a bastion's legal notice) to banner
+func ConnectWithBanner(conn model.Connection, hostKeyCallback ssh.HostKeyCallback, banner ssh.BannerCallback) (*ssh.Client, error) {
+	client, _, err := connectConn(conn, hostKeyCallback, banner)
+	return client, err
+}
+
+// connectConn is ConnectWithBanner, also returning the address the host
+// was reached at
+func connectConn(conn model.Connection, hostKeyCallback ssh.HostKeyCallback, banner ssh.BannerCallback) (*ssh.Client, string, error) {
+	return connectWithin(conn, connectTimeout(), hostKeyCallback, banner)
+}
+
+// connectWithin is connectConn with timeout bounding each hop, from dialing
+// to the end of its handshake
+func connectWithin(conn model.Connection, timeout time.Duration, hostKeyCallback ssh.HostKeyCallback, banner ssh.BannerCallback) (*ssh.Client, string, error) {
+	if conn.IsTelnet() {
+		return nil, "", ErrTelnet
+	}
+	if len(conn.JumpHosts) > 0 {
+		return connectViaJumps(conn, timeout, hostKeyCallback, banner)
+	}
+
 	authMethods, err := BuildAuthMethods(conn)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build auth methods: %w", err)
+		return nil, "", fmt.Errorf("%w: %w", errAuthMethods, err)
 	}
 
 	opts := ConnectOptions{
@@ -64,15 +110,18 @@ func ConnectWithConnection(conn model.Connection, hostKeyCallback ssh.HostKeyCal
 		Port:            conn.Port,
 		User:            conn.User,
 		AuthMethods:     authMethods,
-		Timeout:         defaultTimeout,
+		Timeout:         timeout,
 		HostKeyCallback: hostKeyCallback,
+		BannerCallback:  banner,
+		BindAddress:     conn.BindAddress,
+		Addresses:       conn.Addresses,
 	}
 
 	if opts.HostKeyCallback == nil {
 		opts.HostKeyCallback = ssh.InsecureIgnoreHostKey()
 	}
 
-	return Connect(opts)
+	return connect(opts)
 }
 
 // QuickCheck performs a quick TCP connection check
@@ -80,7 +129,7 @@ func QuickCheck(host string, port int, timeout time.Duration) error {
 	if timeout == 0 {
 		timeout = 5 * time.Second
 	}
-	addr := fmt.Sprintf("%s:%d", host, port)
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
 	conn, err := net.DialTimeout("tcp", addr, timeout)
 	if err != nil {
 		return err
@@ -89,6 +138,50 @@ func QuickCheck(host string, port int, timeout time.Duration) error {
 	return nil
 }
 
+// errHandshakeDone aborts a probe once the host key has been received
+var errHandshakeDone = errors.New("handshake complete")
+
+// HandshakeCheck dials the server and runs the SSH key exchange without
+// authenticating, returning how long the handshake took and the host key
+// fingerprint. It needs no credentials and leaves no failed login attempts
+// in the server's logs.
+func HandshakeCheck(host string, port int, timeout time.Duration) (time.Duration, string, error) {
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return 0, "", err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(start.Add(timeout))
+
+	var latency time.Duration
+	var fingerprint string
+	config := &ssh.ClientConfig{
+		User: "gossh-probe",
+		HostKeyCallback: func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			// The key exchange has finished once the host key is presented
+			latency = time.Since(start)
+			fingerprint = FormatFingerprint(key)
+			return errHandshakeDone
+		},
+		Timeout: timeout,
+	}
+
+	_, _, _, err = ssh.NewClientConn(conn, addr, config)
+	if errors.Is(err, errHandshakeDone) {
+		return latency, fingerprint, nil
+	}
+	if err == nil {
+		return time.Since(start), fingerprint, nil
+	}
+	return 0, "", err
+}
+
 // FullCheck performs a complete SSH handshake check
 func FullCheck(conn model.Connection, hostKeyCallback ssh.HostKeyCallback) error {
 	client, err := ConnectWithConnection(conn, hostKeyCallback)
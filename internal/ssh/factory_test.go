@@ -0,0 +1,149 @@
+package ssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"gossh/internal/model"
+)
+
+// startTestServer runs an SSH server that accepts a single handshake
+func startTestServer(t *testing.T) (string, int) {
+	t.Helper()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _, _, _ = ssh.NewServerConn(conn, config)
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func TestHandshakeCheck(t *testing.T) {
+	host, port := startTestServer(t)
+
+	latency, fingerprint, err := HandshakeCheck(host, port, 5*time.Second)
+	if err != nil {
+		t.Fatalf("HandshakeCheck() error = %v", err)
+	}
+	if latency <= 0 {
+		t.Errorf("HandshakeCheck() latency = %v, want > 0", latency)
+	}
+	if !strings.Contains(fingerprint, "SHA256:") {
+		t.Errorf("HandshakeCheck() fingerprint = %q, want SHA256:...", fingerprint)
+	}
+}
+
+func TestHandshakeCheckUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	if _, _, err := HandshakeCheck("127.0.0.1", port, time.Second); err == nil {
+		t.Error("HandshakeCheck() expected error for closed port")
+	}
+}
+
+func TestQuickCheckIPv6(t *testing.T) {
+	listener, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback unavailable: %v", err)
+	}
+	defer listener.Close()
+
+	// A bare IPv6 address needs brackets once the port is added
+	port := listener.Addr().(*net.TCPAddr).Port
+	if err := QuickCheck("::1", port, time.Second); err != nil {
+		t.Errorf("QuickCheck(::1) error = %v", err)
+	}
+}
+
+func TestFullCheckAuthError(t *testing.T) {
+	conn := startExecServer(t)
+	if err := FullCheck(conn, nil); err != nil {
+		t.Fatalf("FullCheck() error = %v", err)
+	}
+
+	// The server only accepts passwords
+	_, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	block, err := ssh.MarshalPrivateKey(privateKey, "")
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+	conn.AuthType = model.AuthKey
+	conn.KeyPath = keyPath
+	if err := FullCheck(conn, nil); !IsAuthError(err) {
+		t.Errorf("FullCheck() with a refused key: error = %v, want an auth error", err)
+	}
+
+	conn.KeyPath = keyPath + ".missing"
+	if err := FullCheck(conn, nil); !IsAuthError(err) {
+		t.Errorf("FullCheck() with a missing key: error = %v, want an auth error", err)
+	}
+
+	if IsAuthError(errors.New("connection refused")) {
+		t.Error("IsAuthError() should be false for network errors")
+	}
+}
+
+func TestVerifyHostKeyCallback(t *testing.T) {
+	conn := startExecServer(t)
+	hkm := &HostKeyManager{
+		knownHosts: make(map[string]string),
+		filePath:   filepath.Join(t.TempDir(), "known_hosts"),
+	}
+
+	// New hosts are accepted without being recorded
+	if err := FullCheck(conn, VerifyHostKeyCallback(hkm)); err != nil {
+		t.Fatalf("FullCheck() for a new host: error = %v", err)
+	}
+	if len(hkm.knownHosts) != 0 {
+		t.Errorf("VerifyHostKeyCallback recorded %v, want nothing", hkm.knownHosts)
+	}
+
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+	signer, _ := ssh.NewSignerFromKey(otherKey)
+	hkm.knownHosts[formatHostPort(conn.Host, conn.Port)] = FormatFingerprint(signer.PublicKey())
+	if err := FullCheck(conn, VerifyHostKeyCallback(hkm)); !errors.Is(err, ErrHostKeyChanged) {
+		t.Errorf("FullCheck() for a changed key: error = %v, want ErrHostKeyChanged", err)
+	}
+}
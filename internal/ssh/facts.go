@@ -0,0 +1,113 @@
+package ssh
+
+import (
+	"bufio"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"gossh/internal/model"
+)
+
+// factsSeparator splits the parts of FactsCommand's output
+const factsSeparator = "--gossh-facts--"
+
+// FactsCommand prints the os-release file, the kernel and the uptime, or
+// the boot time on BSDs. It always succeeds so that hosts missing some of
+// them still report the rest.
+const FactsCommand = "cat /etc/os-release 2>/dev/null; echo " + factsSeparator +
+	"; uname -sr 2>/dev/null; echo " + factsSeparator +
+	"; cat /proc/uptime 2>/dev/null || sysctl -n kern.boottime 2>/dev/null; true"
+
+// errNoFacts is returned when a host's output has nothing recognizable,
+// e.g. a network appliance without a Unix shell
+var errNoFacts = errors.New("no system information in output")
+
+// ParseFacts reads the output of FactsCommand, gathered at now
+func ParseFacts(output string, now time.Time) (model.HostFacts, error) {
+	facts := model.HostFacts{GatheredAt: now}
+	parts := strings.Split(output, factsSeparator)
+	if len(parts) != 3 {
+		return facts, errNoFacts
+	}
+
+	release := parseOSRelease(parts[0])
+	facts.OSID = release["ID"]
+	facts.OSName = release["NAME"]
+	facts.OSVersion = release["VERSION_ID"]
+
+	facts.Kernel = strings.TrimSpace(parts[1])
+	if facts.OSID == "" && facts.Kernel != "" {
+		// No os-release, e.g. FreeBSD or macOS: name the system after uname
+		system, release, _ := strings.Cut(facts.Kernel, " ")
+		facts.OSID = strings.ToLower(system)
+		facts.OSName = system
+		facts.OSVersion = release
+	}
+
+	if booted, ok := parseBootTime(strings.TrimSpace(parts[2]), now); ok {
+		facts.BootedAt = &booted
+	}
+
+	if facts.OSID == "" && facts.BootedAt == nil {
+		return facts, errNoFacts
+	}
+	return facts, nil
+}
+
+// parseOSRelease reads the KEY=value lines of an os-release file
+func parseOSRelease(text string) map[string]string {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, `'"`)
+		}
+		values[key] = value
+	}
+	return values
+}
+
+// parseBootTime reads /proc/uptime ("350735.47 234388.90") or the
+// kern.boottime sysctl ("{ sec = 1700000000, usec = 0 } Tue Nov 14 ...")
+func parseBootTime(text string, now time.Time) (time.Time, bool) {
+	if rest, ok := strings.CutPrefix(text, "{ sec = "); ok {
+		sec, _, _ := strings.Cut(rest, ",")
+		unix, err := strconv.ParseInt(sec, 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return time.Unix(unix, 0), true
+	}
+
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return time.Time{}, false
+	}
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil || seconds < 0 {
+		return time.Time{}, false
+	}
+	return now.Add(-time.Duration(seconds) * time.Second), true
+}
+
+// gatherFacts runs FactsCommand in a new session on client
+func gatherFacts(client *ssh.Client) (model.HostFacts, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return model.HostFacts{}, err
+	}
+	defer session.Close()
+
+	// The command always exits 0, so what went wrong shows in the output
+	output, _ := session.Output(FactsCommand)
+	return ParseFacts(string(output), time.Now())
+}
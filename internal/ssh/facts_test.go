@@ -0,0 +1,47 @@
+package ssh
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseFacts(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	linux := `NAME="CentOS Linux"
+VERSION="7 (Core)"
+ID="centos"
+VERSION_ID="7"
+PRETTY_NAME="CentOS Linux 7 (Core)"
+` + factsSeparator + `
+Linux 3.10.0-1160.el7.x86_64
+` + factsSeparator + `
+86400.52 170000.10
+`
+	facts, err := ParseFacts(linux, now)
+	if err != nil {
+		t.Fatalf("ParseFacts() error = %v", err)
+	}
+	if facts.OSID != "centos" || facts.OS() != "CentOS 7" || facts.Kernel != "Linux 3.10.0-1160.el7.x86_64" {
+		t.Errorf("ParseFacts() = %+v, want CentOS 7", facts)
+	}
+	if got := facts.Uptime(now); got != 24*time.Hour {
+		t.Errorf("Uptime() = %v, want 24h", got)
+	}
+
+	// BSDs have no os-release and report the boot time instead
+	bsd := factsSeparator + "\nFreeBSD 14.0-RELEASE\n" + factsSeparator +
+		"\n{ sec = 1714478400, usec = 0 } Tue Apr 30 12:00:00 2024\n"
+	facts, err = ParseFacts(bsd, now)
+	if err != nil {
+		t.Fatalf("ParseFacts() error = %v", err)
+	}
+	if facts.OS() != "FreeBSD 14.0-RELEASE" || facts.Uptime(now) != 24*time.Hour {
+		t.Errorf("ParseFacts() = %+v, want FreeBSD up 24h", facts)
+	}
+
+	// A switch CLI echoes errors for every part
+	if _, err := ParseFacts("% Invalid input detected at '^' marker.\n", now); err == nil {
+		t.Error("ParseFacts() should fail without system information")
+	}
+}
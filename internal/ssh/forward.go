@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"golang.org/x/crypto/ssh"
 	"gossh/internal/model"
@@ -17,10 +19,35 @@ import (
 type ForwardType string
 
 const (
-	ForwardLocal  ForwardType = "local"  // -L local:remote
-	ForwardRemote ForwardType = "remote" // -R remote:local
+	ForwardLocal   ForwardType = "local"   // -L local:remote
+	ForwardRemote  ForwardType = "remote"  // -R remote:local
+	ForwardDynamic ForwardType = "dynamic" // -D local SOCKS proxy
 )
 
+// Flag returns the ssh option of the forward type, e.g. -L
+func (t ForwardType) Flag() string {
+	switch t {
+	case ForwardRemote:
+		return "-R"
+	case ForwardDynamic:
+		return "-D"
+	}
+	return "-L"
+}
+
+// ParseForwardFlag returns the forward type of an ssh option, -L, -R or -D
+func ParseForwardFlag(flag string) (ForwardType, bool) {
+	switch flag {
+	case "-L":
+		return ForwardLocal, true
+	case "-R":
+		return ForwardRemote, true
+	case "-D":
+		return ForwardDynamic, true
+	}
+	return "", false
+}
+
 // PortForward represents a port forwarding configuration
 type PortForward struct {
 	Type       ForwardType
@@ -28,12 +55,37 @@ type PortForward struct {
 	LocalPort  int
 	RemoteHost string
 	RemotePort int
+
+	RequestedPort int // Local port of the spec, when a -L or -D moved to a free one
+
+	defaultBind bool // The bind address, LocalHost of a -L or -D or RemoteHost of a -R, was not given in the spec
+
+	active atomic.Int64  // Connections open through the forward
+	total  atomic.Int64  // Connections since the forward started
+	bytes  StreamCounter // Bytes carried both ways, as they pass
+}
+
+// ForwardStats counts the traffic of a running forward
+type ForwardStats struct {
+	Active int64
+	Total  int64
+	Bytes  int64
+}
+
+// Stats returns the forward's counters
+func (pf *PortForward) Stats() ForwardStats {
+	return ForwardStats{Active: pf.active.Load(), Total: pf.total.Load(), Bytes: pf.bytes.Bytes()}
 }
 
 // ParsePortForward parses a port forward string like "8080:localhost:80"
 // For -L: spec is <local-port>:<remote-host>:<remote-port>
 // For -R: spec is <remote-port>:<local-host>:<local-port>
+// For -D: spec is [bind_address:]<local-port>
 func ParsePortForward(fwdType ForwardType, spec string) (*PortForward, error) {
+	if fwdType == ForwardDynamic {
+		return parseDynamicForward(spec)
+	}
+
 	// Parse spec: [bind_address:]port:host:hostport
 	parts := strings.Split(spec, ":")
 
@@ -78,6 +130,7 @@ func ParsePortForward(fwdType ForwardType, spec string) (*PortForward, error) {
 		pf.LocalPort = port1
 		pf.RemoteHost = bindHost
 		pf.RemotePort = port2
+		pf.defaultBind = len(parts) == 3
 		if len(parts) == 4 {
 			pf.LocalHost = parts[0]
 		}
@@ -87,16 +140,68 @@ func ParsePortForward(fwdType ForwardType, spec string) (*PortForward, error) {
 		pf.RemotePort = port1
 		pf.LocalHost = bindHost
 		pf.LocalPort = port2
+		pf.defaultBind = len(parts) == 3
 		if len(parts) == 4 {
 			pf.RemoteHost = parts[0]
+			// ssh's "all interfaces"
+			if pf.RemoteHost == "" || pf.RemoteHost == "*" {
+				pf.RemoteHost = "0.0.0.0"
+			}
 		}
 	}
 
 	return pf, nil
 }
 
+// ListenPort returns the port the forward listens on: the local port of a
+// -L or -D, the remote port of a -R
+func (pf *PortForward) ListenPort() int {
+	if pf.Type == ForwardRemote {
+		return pf.RemotePort
+	}
+	return pf.LocalPort
+}
+
+// IsGateway reports whether a -R forward listens on more than the server's
+// loopback interface, e.g. on 0.0.0.0, where other hosts can reach it.
+// sshd binds it so only with GatewayPorts set to clientspecified.
+func (pf *PortForward) IsGateway() bool {
+	if pf.Type != ForwardRemote || pf.RemoteHost == "localhost" {
+		return false
+	}
+	ip := net.ParseIP(pf.RemoteHost)
+	return ip == nil || !ip.IsLoopback()
+}
+
+// UseGateway makes a -R forward whose spec has no bind address listen on
+// all of the server's interfaces
+func (pf *PortForward) UseGateway() {
+	if pf.Type == ForwardRemote && pf.defaultBind {
+		pf.RemoteHost = "0.0.0.0"
+		pf.defaultBind = false
+	}
+}
+
+// parseDynamicForward parses the spec of a -D, [bind_address:]port
+func parseDynamicForward(spec string) (*PortForward, error) {
+	pf := &PortForward{Type: ForwardDynamic, LocalHost: "localhost", defaultBind: true}
+	port := spec
+	if i := strings.LastIndex(spec, ":"); i >= 0 {
+		pf.LocalHost, port, pf.defaultBind = spec[:i], spec[i+1:], false
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil || p < 0 || p > 65535 || pf.LocalHost == "" {
+		return nil, fmt.Errorf("invalid forward spec: %s (expected [bind:]port)", spec)
+	}
+	pf.LocalPort = p
+	return pf, nil
+}
+
 // String returns a string representation
 func (pf *PortForward) String() string {
+	if pf.Type == ForwardDynamic {
+		return fmt.Sprintf("-D %s:%d", pf.LocalHost, pf.LocalPort)
+	}
 	if pf.Type == ForwardLocal {
 		return fmt.Sprintf("-L %s:%d:%s:%d", pf.LocalHost, pf.LocalPort, pf.RemoteHost, pf.RemotePort)
 	}
@@ -114,6 +219,9 @@ type Forwarder struct {
 	mu              sync.Mutex
 	running         bool
 	hostKeyCallback ssh.HostKeyCallback
+	listeners       []net.Listener
+	out             io.Writer
+	autoPort        bool
 }
 
 // NewForwarder creates a new port forwarder
@@ -124,9 +232,21 @@ func NewForwarder(conn model.Connection) *Forwarder {
 		forwards: make([]*PortForward, 0),
 		ctx:      ctx,
 		cancel:   cancel,
+		out:      os.Stdout,
 	}
 }
 
+// SetOutput sets where forward status messages are printed
+func (f *Forwarder) SetOutput(w io.Writer) {
+	f.out = w
+}
+
+// SetAutoPort makes a -L or -D whose local port can't be listened on,
+// usually as it is in use, listen on a free port instead
+func (f *Forwarder) SetAutoPort(auto bool) {
+	f.autoPort = auto
+}
+
 // SetHostKeyCallback sets the host key callback for verification
 func (f *Forwarder) SetHostKeyCallback(callback ssh.HostKeyCallback) {
 	f.hostKeyCallback = callback
@@ -139,9 +259,10 @@ func (f *Forwarder) AddForward(pf *PortForward) {
 	f.forwards = append(f.forwards, pf)
 }
 
-// Connect establishes the SSH connection using the factory function
+// Connect establishes the SSH connection, reusing an open connection to the
+// same host from DefaultPool when there is one
 func (f *Forwarder) Connect() error {
-	client, err := ConnectWithConnection(f.conn, f.hostKeyCallback)
+	client, err := DefaultPool.Acquire(f.conn, f.hostKeyCallback)
 	if err != nil {
 		return err
 	}
@@ -160,27 +281,84 @@ func (f *Forwarder) Start() error {
 	f.mu.Unlock()
 
 	for _, pf := range f.forwards {
-		switch pf.Type {
-		case ForwardLocal:
-			if err := f.startLocalForward(pf); err != nil {
-				return err
-			}
-		case ForwardRemote:
-			if err := f.startRemoteForward(pf); err != nil {
-				return err
-			}
+		if err := f.startForward(pf); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// StartForward adds and starts a forward on a running forwarder
+func (f *Forwarder) StartForward(pf *PortForward) error {
+	f.AddForward(pf)
+	return f.startForward(pf)
+}
+
+func (f *Forwarder) startForward(pf *PortForward) error {
+	switch pf.Type {
+	case ForwardLocal:
+		return f.startLocalForward(pf)
+	case ForwardRemote:
+		return f.startRemoteForward(pf)
+	case ForwardDynamic:
+		return f.startDynamicForward(pf)
+	}
+	return fmt.Errorf("unknown forward type: %s", pf.Type)
+}
+
+// track remembers a listener so Stop can close it
+func (f *Forwarder) track(listener net.Listener) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.listeners = append(f.listeners, listener)
+}
+
+// Forwards returns the forwards added to the forwarder
+func (f *Forwarder) Forwards() []*PortForward {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*PortForward(nil), f.forwards...)
+}
+
+// listenLocal listens on the local side of a -L or -D. Without a bind
+// address in the spec it listens on the connection's bind address, if any.
+func (f *Forwarder) listenLocal(pf *PortForward) (net.Listener, string, error) {
+	if pf.defaultBind && f.conn.BindAddress != "" {
+		ip, err := BindIP(f.conn.BindAddress)
+		if err != nil {
+			return nil, "", err
+		}
+		pf.LocalHost = ip.String()
+		pf.defaultBind = false
+	}
+	localAddr := net.JoinHostPort(pf.LocalHost, strconv.Itoa(pf.LocalPort))
+	listener, err := net.Listen("tcp", localAddr)
+	if err != nil && f.autoPort && pf.LocalPort != 0 {
+		// Still failing on any port means the address itself is the problem
+		if l, autoErr := net.Listen("tcp", net.JoinHostPort(pf.LocalHost, "0")); autoErr == nil {
+			listener, err = l, nil
+			pf.RequestedPort = pf.LocalPort
+		}
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to listen on %s: %w", localAddr, err)
+	}
+	f.track(listener)
+
+	// With port 0 the system picked one
+	if addr, ok := listener.Addr().(*net.TCPAddr); ok && addr.Port != pf.LocalPort {
+		pf.LocalPort = addr.Port
+		localAddr = net.JoinHostPort(pf.LocalHost, strconv.Itoa(pf.LocalPort))
+	}
+	return listener, localAddr, nil
+}
+
 // startLocalForward starts a local port forward (-L)
 func (f *Forwarder) startLocalForward(pf *PortForward) error {
-	localAddr := fmt.Sprintf("%s:%d", pf.LocalHost, pf.LocalPort)
-	listener, err := net.Listen("tcp", localAddr)
+	listener, localAddr, err := f.listenLocal(pf)
 	if err != nil {
-		return fmt.Errorf("failed to listen on %s: %w", localAddr, err)
+		return err
 	}
 
 	f.wg.Add(1)
@@ -217,22 +395,28 @@ func (f *Forwarder) startLocalForward(pf *PortForward) error {
 				}
 				defer remoteConn.Close()
 
-				f.copyBidirectional(localConn, remoteConn)
+				f.copyBidirectional(pf, localConn, remoteConn)
 			}(conn)
 		}
 	}()
 
-	fmt.Printf("Local forward: %s -> [%s] -> %s:%d\n", localAddr, f.conn.Host, pf.RemoteHost, pf.RemotePort)
+	fmt.Fprintf(f.out, "Local forward: %s -> [%s] -> %s:%d\n", localAddr, f.conn.Host, pf.RemoteHost, pf.RemotePort)
 	return nil
 }
 
-// startRemoteForward starts a remote port forward (-R)
+// startRemoteForward starts a remote port forward (-R). With port 0 the
+// server picks the port, which is stored in pf.RemotePort.
 func (f *Forwarder) startRemoteForward(pf *PortForward) error {
-	remoteAddr := fmt.Sprintf("%s:%d", pf.RemoteHost, pf.RemotePort)
+	remoteAddr := net.JoinHostPort(pf.RemoteHost, strconv.Itoa(pf.RemotePort))
 	listener, err := f.client.Listen("tcp", remoteAddr)
 	if err != nil {
 		return fmt.Errorf("failed to listen on remote %s: %w", remoteAddr, err)
 	}
+	f.track(listener)
+	if addr, ok := listener.Addr().(*net.TCPAddr); ok && pf.RemotePort == 0 {
+		pf.RemotePort = addr.Port
+		remoteAddr = net.JoinHostPort(pf.RemoteHost, strconv.Itoa(pf.RemotePort))
+	}
 
 	f.wg.Add(1)
 	go func() {
@@ -268,28 +452,38 @@ func (f *Forwarder) startRemoteForward(pf *PortForward) error {
 				}
 				defer localConn.Close()
 
-				f.copyBidirectional(remoteConn, localConn)
+				f.copyBidirectional(pf, remoteConn, localConn)
 			}(conn)
 		}
 	}()
 
-	fmt.Printf("Remote forward: [%s] %s -> %s:%d\n", f.conn.Host, remoteAddr, pf.LocalHost, pf.LocalPort)
+	fmt.Fprintf(f.out, "Remote forward: [%s] %s -> %s:%d\n", f.conn.Host, remoteAddr, pf.LocalHost, pf.LocalPort)
 	return nil
 }
 
-// copyBidirectional copies data between two connections
-func (f *Forwarder) copyBidirectional(conn1, conn2 net.Conn) {
+// copyBidirectional copies data between two connections, counting it as
+// the forward's traffic
+func (f *Forwarder) copyBidirectional(pf *PortForward, conn1, conn2 net.Conn) {
+	pf.active.Add(1)
+	pf.total.Add(1)
+	defer pf.active.Add(-1)
+
 	var wg sync.WaitGroup
 	wg.Add(2)
 
+	// When either side finishes, close both so the other copy returns too
 	go func() {
 		defer wg.Done()
-		_, _ = io.Copy(conn1, conn2)
+		_, _ = io.Copy(countingWriter{w: conn1, counter: &pf.bytes}, conn2)
+		conn1.Close()
+		conn2.Close()
 	}()
 
 	go func() {
 		defer wg.Done()
-		_, _ = io.Copy(conn2, conn1)
+		_, _ = io.Copy(countingWriter{w: conn2, counter: &pf.bytes}, conn1)
+		conn1.Close()
+		conn2.Close()
 	}()
 
 	wg.Wait()
@@ -298,8 +492,15 @@ func (f *Forwarder) copyBidirectional(conn1, conn2 net.Conn) {
 // Stop stops all port forwards
 func (f *Forwarder) Stop() {
 	f.cancel()
+	f.mu.Lock()
+	for _, listener := range f.listeners {
+		_ = listener.Close()
+	}
+	f.listeners = nil
+	f.mu.Unlock()
 	if f.client != nil {
-		f.client.Close()
+		_ = DefaultPool.Release(f.client)
+		f.client = nil
 	}
 	f.wg.Wait()
 
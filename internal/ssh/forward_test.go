@@ -0,0 +1,248 @@
+package ssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"gossh/internal/model"
+)
+
+func TestRemoteForwardGateway(t *testing.T) {
+	tests := []struct {
+		spec        string
+		gateway     bool
+		withGateway string // RemoteHost after UseGateway
+	}{
+		{"8080:localhost:80", false, "0.0.0.0"},
+		{"localhost:8080:localhost:80", false, "localhost"},
+		{"127.0.0.1:8080:localhost:80", false, "127.0.0.1"},
+		{"0.0.0.0:8080:localhost:80", true, "0.0.0.0"},
+		{"*:8080:localhost:80", true, "0.0.0.0"},
+		{":8080:localhost:80", true, "0.0.0.0"},
+		{"10.0.0.5:8080:localhost:80", true, "10.0.0.5"},
+	}
+	for _, tt := range tests {
+		pf, err := ParsePortForward(ForwardRemote, tt.spec)
+		if err != nil {
+			t.Errorf("ParsePortForward(-R %q) failed: %v", tt.spec, err)
+			continue
+		}
+		if got := pf.IsGateway(); got != tt.gateway {
+			t.Errorf("IsGateway() of -R %q = %v, want %v", tt.spec, got, tt.gateway)
+		}
+		pf.UseGateway()
+		if pf.RemoteHost != tt.withGateway {
+			t.Errorf("RemoteHost of -R %q with UseGateway = %q, want %q", tt.spec, pf.RemoteHost, tt.withGateway)
+		}
+	}
+
+	local, err := ParsePortForward(ForwardLocal, "0.0.0.0:8080:db:5432")
+	if err != nil {
+		t.Fatalf("ParsePortForward failed: %v", err)
+	}
+	if local.IsGateway() {
+		t.Error("IsGateway() should only be true for remote forwards")
+	}
+}
+
+func TestLocalForwardAutoPort(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer taken.Close()
+	takenPort := taken.Addr().(*net.TCPAddr).Port
+
+	f := NewForwarder(model.Connection{})
+	f.SetOutput(io.Discard)
+	defer f.Stop()
+
+	inUse, err := ParsePortForward(ForwardLocal, "127.0.0.1:"+strconv.Itoa(takenPort)+":db:5432")
+	if err != nil {
+		t.Fatalf("ParsePortForward failed: %v", err)
+	}
+	if err := f.startLocalForward(inUse); err == nil {
+		t.Fatal("startLocalForward should fail on a port in use without SetAutoPort")
+	}
+
+	f.SetAutoPort(true)
+	if err := f.startLocalForward(inUse); err != nil {
+		t.Fatalf("startLocalForward with SetAutoPort failed: %v", err)
+	}
+	if inUse.RequestedPort != takenPort || inUse.LocalPort == takenPort || inUse.LocalPort == 0 {
+		t.Errorf("forward on a port in use: RequestedPort = %d, LocalPort = %d, want %d and a free port", inUse.RequestedPort, inUse.LocalPort, takenPort)
+	}
+
+	anyPort, err := ParsePortForward(ForwardDynamic, "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ParsePortForward failed: %v", err)
+	}
+	if err := f.startDynamicForward(anyPort); err != nil {
+		t.Fatalf("startDynamicForward failed: %v", err)
+	}
+	if anyPort.LocalPort == 0 || anyPort.RequestedPort != 0 {
+		t.Errorf("forward on port 0: LocalPort = %d, RequestedPort = %d, want the picked port and 0", anyPort.LocalPort, anyPort.RequestedPort)
+	}
+}
+
+func TestRemoteForwardPortZero(t *testing.T) {
+	// What the remote forward leads to
+	target, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer target.Close()
+	go func() {
+		for {
+			conn, err := target.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = io.WriteString(conn, "hello")
+			conn.Close()
+		}
+	}()
+	targetPort := target.Addr().(*net.TCPAddr).Port
+
+	f := NewForwarder(startForwardServer(t))
+	f.SetOutput(io.Discard)
+	if err := f.Connect(); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer f.Stop()
+	pf, err := ParsePortForward(ForwardRemote, "127.0.0.1:0:127.0.0.1:"+strconv.Itoa(targetPort))
+	if err != nil {
+		t.Fatalf("ParsePortForward failed: %v", err)
+	}
+	if err := f.StartForward(pf); err != nil {
+		t.Fatalf("StartForward failed: %v", err)
+	}
+	if pf.RemotePort == 0 {
+		t.Fatal("RemotePort should be the port the server picked")
+	}
+
+	// The test server listens where it says it does
+	conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(pf.RemotePort)))
+	if err != nil {
+		t.Fatalf("Failed to connect to the reported port %d: %v", pf.RemotePort, err)
+	}
+	defer conn.Close()
+	got, _ := io.ReadAll(conn)
+	if string(got) != "hello" {
+		t.Errorf("read %q through the forward, want hello", got)
+	}
+}
+
+// startForwardServer runs an SSH server that only serves remote forwards,
+// listening on the address asked for and picking a port for port 0
+func startForwardServer(t *testing.T) model.Connection {
+	t.Helper()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(ssh.ConnMetadata, []byte) (*ssh.Permissions, error) {
+			return nil, nil
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveRemoteForwards(t, conn, config)
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return model.Connection{
+		Name:     "forward",
+		Host:     addr.IP.String(),
+		Port:     addr.Port,
+		User:     "test",
+		AuthType: model.AuthPassword,
+		Password: "secret",
+	}
+}
+
+func serveRemoteForwards(t *testing.T, conn net.Conn, config *ssh.ServerConfig) {
+	defer conn.Close()
+	sshConn, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go func() {
+		for newChan := range chans {
+			_ = newChan.Reject(ssh.Prohibited, "only remote forwards")
+		}
+	}()
+
+	for req := range reqs {
+		if req.Type != "tcpip-forward" {
+			_ = req.Reply(false, nil)
+			continue
+		}
+		var bind struct {
+			Addr string
+			Port uint32
+		}
+		if err := ssh.Unmarshal(req.Payload, &bind); err != nil {
+			_ = req.Reply(false, nil)
+			continue
+		}
+		l, err := net.Listen("tcp", net.JoinHostPort(bind.Addr, strconv.Itoa(int(bind.Port))))
+		if err != nil {
+			_ = req.Reply(false, nil)
+			continue
+		}
+		t.Cleanup(func() { l.Close() })
+		port := uint32(l.Addr().(*net.TCPAddr).Port)
+		_ = req.Reply(true, ssh.Marshal(struct{ Port uint32 }{port}))
+
+		go func() {
+			for {
+				c, err := l.Accept()
+				if err != nil {
+					return
+				}
+				origin := c.RemoteAddr().(*net.TCPAddr)
+				ch, chReqs, err := sshConn.OpenChannel("forwarded-tcpip", ssh.Marshal(struct {
+					Addr       string
+					Port       uint32
+					OriginAddr string
+					OriginPort uint32
+				}{bind.Addr, port, origin.IP.String(), uint32(origin.Port)}))
+				if err != nil {
+					c.Close()
+					continue
+				}
+				go ssh.DiscardRequests(chReqs)
+				go func() {
+					defer c.Close()
+					defer ch.Close()
+					_, _ = io.Copy(c, ch)
+				}()
+			}
+		}()
+	}
+}
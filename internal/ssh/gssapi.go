@@ -0,0 +1,30 @@
+package ssh
+
+import (
+	"golang.org/x/crypto/ssh"
+	"gossh/internal/model"
+)
+
+// newGSSAPIClient returns a GSS-API client holding a Kerberos ticket for
+// host. It is set by builds with the gssapi tag and is nil otherwise.
+var newGSSAPIClient func(host string) (ssh.GSSAPIClient, error)
+
+// GSSAPISupported reports whether this build can use GSSAPI (Kerberos)
+// authentication
+func GSSAPISupported() bool {
+	return newGSSAPIClient != nil
+}
+
+// gssapiAuth returns the gssapi-with-mic method for conn, or nil when conn
+// doesn't ask for it, the build lacks support or no Kerberos ticket is
+// available. The connection's other method is then used alone.
+func gssapiAuth(conn model.Connection) ssh.AuthMethod {
+	if !conn.GSSAPI || newGSSAPIClient == nil {
+		return nil
+	}
+	client, err := newGSSAPIClient(conn.Host)
+	if err != nil {
+		return nil
+	}
+	return ssh.GSSAPIWithMICAuthMethod(client, conn.Host)
+}
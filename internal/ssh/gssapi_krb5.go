@@ -0,0 +1,107 @@
+//go:build gssapi
+
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jcmturner/gokrb5/v8/client"
+	krb5config "github.com/jcmturner/gokrb5/v8/config"
+	"github.com/jcmturner/gokrb5/v8/credentials"
+	"github.com/jcmturner/gokrb5/v8/gssapi"
+	"github.com/jcmturner/gokrb5/v8/messages"
+	"github.com/jcmturner/gokrb5/v8/spnego"
+	"github.com/jcmturner/gokrb5/v8/types"
+	"golang.org/x/crypto/ssh"
+)
+
+func init() {
+	newGSSAPIClient = newKrb5Client
+}
+
+// krb5Client authenticates with a service ticket obtained from the user's
+// Kerberos credential cache, as left by kinit
+type krb5Client struct {
+	client     *client.Client
+	ticket     messages.Ticket
+	sessionKey types.EncryptionKey
+}
+
+// newKrb5Client gets a ticket for the host service on host. It fails when
+// there is no valid ticket-granting ticket or the KDC can't be reached.
+func newKrb5Client(host string) (ssh.GSSAPIClient, error) {
+	conf, err := krb5config.Load(krb5ConfigPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Kerberos config: %w", err)
+	}
+	cache, err := credentials.LoadCCache(credentialCachePath())
+	if err != nil {
+		return nil, fmt.Errorf("no Kerberos ticket: %w", err)
+	}
+	cl, err := client.NewFromCCache(cache, conf, client.DisablePAFXFAST(true))
+	if err != nil {
+		return nil, fmt.Errorf("no Kerberos ticket: %w", err)
+	}
+	ticket, key, err := cl.GetServiceTicket("host/" + host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kerberos service ticket: %w", err)
+	}
+	return &krb5Client{client: cl, ticket: ticket, sessionKey: key}, nil
+}
+
+// InitSecContext returns the AP-REQ token. Mutual authentication isn't
+// asked for, so a single token completes the context.
+func (k *krb5Client) InitSecContext(target string, token []byte, isGSSDelegCreds bool) ([]byte, bool, error) {
+	if len(token) > 0 {
+		return nil, false, errors.New("unexpected GSS-API token from server")
+	}
+	flags := []int{gssapi.ContextFlagInteg}
+	if isGSSDelegCreds {
+		flags = append(flags, gssapi.ContextFlagDeleg)
+	}
+	apReq, err := spnego.NewKRB5TokenAPREQ(k.client, k.ticket, k.sessionKey, flags, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	out, err := apReq.Marshal()
+	if err != nil {
+		return nil, false, err
+	}
+	return out, false, nil
+}
+
+// GetMIC signs the session data with the ticket's session key
+func (k *krb5Client) GetMIC(micField []byte) ([]byte, error) {
+	mic, err := gssapi.NewInitiatorMICToken(micField, k.sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	return mic.Marshal()
+}
+
+// DeleteSecContext releases the Kerberos client
+func (k *krb5Client) DeleteSecContext() error {
+	k.client.Destroy()
+	return nil
+}
+
+// krb5ConfigPath returns the Kerberos config file, from KRB5_CONFIG like
+// the MIT tools
+func krb5ConfigPath() string {
+	if path := os.Getenv("KRB5_CONFIG"); path != "" {
+		return path
+	}
+	return "/etc/krb5.conf"
+}
+
+// credentialCachePath returns the credential cache file, from KRB5CCNAME
+// like the MIT tools. Only FILE caches can be read.
+func credentialCachePath() string {
+	if name := os.Getenv("KRB5CCNAME"); name != "" {
+		return strings.TrimPrefix(name, "FILE:")
+	}
+	return fmt.Sprintf("/tmp/krb5cc_%d", os.Getuid())
+}
@@ -0,0 +1,49 @@
+package ssh
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"gossh/internal/model"
+)
+
+// fakeGSSAPIClient stands in for a Kerberos client
+type fakeGSSAPIClient struct{}
+
+func (fakeGSSAPIClient) InitSecContext(string, []byte, bool) ([]byte, bool, error) {
+	return nil, false, nil
+}
+func (fakeGSSAPIClient) GetMIC([]byte) ([]byte, error) { return nil, nil }
+func (fakeGSSAPIClient) DeleteSecContext() error       { return nil }
+
+func TestBuildAuthMethodsGSSAPI(t *testing.T) {
+	saved := newGSSAPIClient
+	t.Cleanup(func() { newGSSAPIClient = saved })
+
+	conn := model.Connection{Host: "bastion", AuthType: model.AuthPassword, Password: "secret", GSSAPI: true}
+
+	newGSSAPIClient = func(host string) (ssh.GSSAPIClient, error) {
+		if host != "bastion" {
+			t.Errorf("newGSSAPIClient() host = %q, want bastion", host)
+		}
+		return fakeGSSAPIClient{}, nil
+	}
+	if methods, _ := BuildAuthMethods(conn); len(methods) != 2 {
+		t.Errorf("BuildAuthMethods() = %d methods, want GSSAPI and the password", len(methods))
+	}
+
+	// Without a ticket only the password is tried
+	newGSSAPIClient = func(string) (ssh.GSSAPIClient, error) {
+		return nil, errors.New("no ticket")
+	}
+	if methods, _ := BuildAuthMethods(conn); len(methods) != 1 {
+		t.Errorf("BuildAuthMethods() = %d methods, want the password alone", len(methods))
+	}
+
+	// Nor when the build has no GSSAPI support
+	newGSSAPIClient = nil
+	if methods, _ := BuildAuthMethods(conn); len(methods) != 1 {
+		t.Errorf("BuildAuthMethods() = %d methods, want the password alone", len(methods))
+	}
+}
@@ -0,0 +1,122 @@
+package ssh
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"gossh/internal/model"
+)
+
+// HealthResult is the outcome of checking one connection
+type HealthResult struct {
+	Connection model.Connection
+	Latency    time.Duration
+	Error      error
+}
+
+// HealthChecker checks many connections concurrently with a bounded number
+// of workers, reporting each result as soon as it is known
+type HealthChecker struct {
+	connections []model.Connection
+	timeout     time.Duration
+	parallel    int
+	check       func(host string, port int, timeout time.Duration) error
+}
+
+// NewHealthChecker creates a health checker for connections
+func NewHealthChecker(connections []model.Connection) *HealthChecker {
+	return &HealthChecker{
+		connections: connections,
+		timeout:     5 * time.Second,
+		parallel:    10, // Default parallel checks
+		check:       QuickCheck,
+	}
+}
+
+// SetTimeout sets the timeout for each check
+func (h *HealthChecker) SetTimeout(timeout time.Duration) {
+	h.timeout = timeout
+}
+
+// SetParallel sets the max parallel checks
+func (h *HealthChecker) SetParallel(n int) {
+	if n > 0 {
+		h.parallel = n
+	}
+}
+
+// Start checks all connections in the background. Results arrive on the
+// returned channel in completion order; it is closed once every connection
+// has been checked or ctx is canceled.
+func (h *HealthChecker) Start(ctx context.Context) <-chan HealthResult {
+	results := make(chan HealthResult, len(h.connections))
+	jobs := make(chan model.Connection)
+
+	var wg sync.WaitGroup
+	workers := min(h.parallel, len(h.connections))
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for conn := range jobs {
+				start := time.Now()
+				err := checkTargets(conn, h.timeout, h.check)
+				results <- HealthResult{Connection: conn, Latency: time.Since(start), Error: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(results)
+		defer wg.Wait()
+		defer close(jobs)
+
+		for _, conn := range h.connections {
+			select {
+			case jobs <- conn:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return results
+}
+
+// CheckConnection is QuickCheck for a connection
+func CheckConnection(conn model.Connection, timeout time.Duration) error {
+	return checkTargets(conn, timeout, QuickCheck)
+}
+
+// HandshakeConnection is HandshakeCheck for a connection, reporting the
+// first of its addresses or service instances that completes a handshake
+func HandshakeConnection(conn model.Connection, timeout time.Duration) (time.Duration, string, error) {
+	var latency time.Duration
+	var fingerprint string
+	err := checkTargets(conn, timeout, func(host string, port int, timeout time.Duration) error {
+		var err error
+		latency, fingerprint, err = HandshakeCheck(host, port, timeout)
+		return err
+	})
+	return latency, fingerprint, err
+}
+
+// checkTargets checks a connection's host, then its other addresses, e.g.
+// the VPN address when away. A service is up when any of its instances is.
+func checkTargets(conn model.Connection, timeout time.Duration, check func(host string, port int, timeout time.Duration) error) error {
+	addrs, _, err := dialTargets(conn)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		host, portStr, _ := net.SplitHostPort(addr)
+		port, _ := strconv.Atoi(portStr)
+		if err = check(host, port, timeout); err == nil {
+			return nil
+		}
+	}
+	return err
+}
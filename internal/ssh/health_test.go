@@ -0,0 +1,78 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"gossh/internal/model"
+)
+
+func healthConnections(n int) []model.Connection {
+	conns := make([]model.Connection, n)
+	for i := range conns {
+		conns[i] = model.Connection{ID: fmt.Sprint(i), Name: fmt.Sprintf("host-%d", i), Host: fmt.Sprintf("10.0.0.%d", i), Port: 22}
+	}
+	return conns
+}
+
+func TestHealthCheckerBoundsConcurrency(t *testing.T) {
+	h := NewHealthChecker(healthConnections(20))
+	h.SetParallel(3)
+
+	var mu sync.Mutex
+	running, peak := 0, 0
+	h.check = func(host string, port int, timeout time.Duration) error {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		if host == "10.0.0.7" {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	seen, failed := 0, 0
+	for r := range h.Start(context.Background()) {
+		seen++
+		if r.Error != nil {
+			failed++
+		}
+	}
+
+	if seen != 20 || failed != 1 {
+		t.Errorf("got %d results with %d failures, want 20 with 1", seen, failed)
+	}
+	if peak > 3 {
+		t.Errorf("peak concurrency = %d, want at most 3", peak)
+	}
+}
+
+func TestHealthCheckerCancel(t *testing.T) {
+	h := NewHealthChecker(healthConnections(50))
+	h.SetParallel(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.check = func(string, int, time.Duration) error {
+		cancel()
+		return nil
+	}
+
+	seen := 0
+	for range h.Start(ctx) {
+		seen++
+	}
+	if seen >= 50 {
+		t.Errorf("got %d results after cancel, want fewer than 50", seen)
+	}
+}
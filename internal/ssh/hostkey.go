@@ -4,10 +4,12 @@ import (
 	"bufio"
 	"crypto/sha256"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -24,6 +26,10 @@ const (
 	HostKeyChanged                       // Key has changed from known_hosts
 )
 
+// ErrHostKeyChanged is returned when a host presents a different key than
+// the one in known_hosts
+var ErrHostKeyChanged = errors.New("host key changed")
+
 // HostKeyResult contains the result of host key verification
 type HostKeyResult struct {
 	Status      HostKeyStatus
@@ -37,6 +43,7 @@ type HostKeyResult struct {
 type HostKeyManager struct {
 	knownHosts map[string]string // host:port -> key fingerprint
 	filePath   string
+	onChange   func(HostKeyResult) // Called when a host presents another key
 	mu         sync.RWMutex
 }
 
@@ -199,6 +206,25 @@ func (h *HostKeyManager) CheckHostKey(host string, port int, key ssh.PublicKey)
 	return result
 }
 
+// OnChange sets fn to be called whenever a host checked through a callback
+// of this manager presents a key other than the one on record, whether or
+// not the change is then accepted
+func (h *HostKeyManager) OnChange(fn func(HostKeyResult)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onChange = fn
+}
+
+// changed reports a changed host key to the OnChange function
+func (h *HostKeyManager) changed(result *HostKeyResult) {
+	h.mu.RLock()
+	fn := h.onChange
+	h.mu.RUnlock()
+	if fn != nil {
+		fn(*result)
+	}
+}
+
 // formatHostPort formats host and port for known_hosts
 func formatHostPort(host string, port int) string {
 	if port == 22 {
@@ -230,6 +256,9 @@ func CreateHostKeyCallback(hkm *HostKeyManager, handler HostKeyCallback) ssh.Hos
 		}
 
 		result := hkm.CheckHostKey(host, port, key)
+		if result.Status == HostKeyChanged {
+			hkm.changed(result)
+		}
 
 		switch result.Status {
 		case HostKeyOK:
@@ -240,7 +269,7 @@ func CreateHostKeyCallback(hkm *HostKeyManager, handler HostKeyCallback) ssh.Hos
 				if result.Status == HostKeyNew {
 					return fmt.Errorf("unknown host: %s", host)
 				}
-				return fmt.Errorf("host key changed for: %s", host)
+				return fmt.Errorf("%w for: %s", ErrHostKeyChanged, host)
 			}
 
 			accept, update := handler(result)
@@ -264,6 +293,28 @@ func CreateHostKeyCallback(hkm *HostKeyManager, handler HostKeyCallback) ssh.Hos
 	}
 }
 
+// VerifyHostKeyCallback checks host keys without recording any: hosts in
+// known_hosts must present the key on record, while new hosts are accepted.
+// It suits checking a connection before it is saved.
+func VerifyHostKeyCallback(hkm *HostKeyManager) ssh.HostKeyCallback {
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		host, portStr, err := net.SplitHostPort(remote.String())
+		if err != nil {
+			host = hostname
+		}
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			port = 22
+		}
+
+		if result := hkm.CheckHostKey(host, port, key); result.Status == HostKeyChanged {
+			hkm.changed(result)
+			return fmt.Errorf("%w for: %s", ErrHostKeyChanged, host)
+		}
+		return nil
+	}
+}
+
 // InsecureIgnoreHostKey returns a callback that accepts any host key (for testing only)
 func InsecureIgnoreHostKey() ssh.HostKeyCallback {
 	return ssh.InsecureIgnoreHostKey()
@@ -3,6 +3,8 @@ package ssh
 import (
 	"crypto/ed25519"
 	"crypto/rand"
+	"errors"
+	"net"
 	"os"
 	"path/filepath"
 	"testing"
@@ -169,6 +171,40 @@ func TestHostKeyChanged(t *testing.T) {
 	}
 }
 
+func TestHostKeyChangeReported(t *testing.T) {
+	hkm := &HostKeyManager{
+		knownHosts: make(map[string]string),
+		filePath:   filepath.Join(t.TempDir(), "known_hosts"),
+	}
+	_, privateKey1, _ := ed25519.GenerateKey(rand.Reader)
+	signer1, _ := ssh.NewSignerFromKey(privateKey1)
+	_, privateKey2, _ := ed25519.GenerateKey(rand.Reader)
+	signer2, _ := ssh.NewSignerFromKey(privateKey2)
+	hkm.AddHost("10.0.0.1", 22, signer1.PublicKey())
+
+	var changes []HostKeyResult
+	hkm.OnChange(func(result HostKeyResult) { changes = append(changes, result) })
+	remote := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 22}
+
+	callbacks := map[string]ssh.HostKeyCallback{
+		"verify":  VerifyHostKeyCallback(hkm),
+		"handler": CreateHostKeyCallback(hkm, nil),
+	}
+	for name, callback := range callbacks {
+		changes = nil
+		if err := callback("10.0.0.1:22", remote, signer1.PublicKey()); err != nil || len(changes) != 0 {
+			t.Errorf("%s: known key error = %v, %d changes reported", name, err, len(changes))
+		}
+		err := callback("10.0.0.1:22", remote, signer2.PublicKey())
+		if !errors.Is(err, ErrHostKeyChanged) {
+			t.Errorf("%s: changed key error = %v, want ErrHostKeyChanged", name, err)
+		}
+		if len(changes) != 1 || changes[0].OldKey != FormatFingerprint(signer1.PublicKey()) || changes[0].Fingerprint != FormatFingerprint(signer2.PublicKey()) {
+			t.Errorf("%s: changes reported = %+v, want the one change", name, changes)
+		}
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsAt(s, substr, 0))
 }
@@ -0,0 +1,132 @@
+package ssh
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"gossh/internal/model"
+)
+
+// JumpError reports which hop of a jump host chain failed. The target
+// itself is the last hop.
+type JumpError struct {
+	Hop  int // 1-based
+	Hops int
+	Conn model.Connection
+	Err  error
+}
+
+func (e *JumpError) Error() string {
+	addr := net.JoinHostPort(e.Conn.Host, strconv.Itoa(e.Conn.Port))
+	return fmt.Sprintf("hop %d/%d (%s, %s@%s): %v", e.Hop, e.Hops, e.Conn.Name, e.Conn.User, addr, e.Err)
+}
+
+func (e *JumpError) Unwrap() error {
+	return e.Err
+}
+
+// connectViaJumps connects to conn through its jump hosts, each hop dialed
+// through the connection to the one before it. The hops stay open for as
+// long as the connection to the target. It returns the address the target
+// was reached at.
+func connectViaJumps(conn model.Connection, timeout time.Duration, hostKeyCallback ssh.HostKeyCallback, banner ssh.BannerCallback) (*ssh.Client, string, error) {
+	if len(conn.Jumps) == 0 {
+		return nil, "", fmt.Errorf("%w: %s", model.ErrJumpNotFound, strings.Join(conn.JumpHosts, ", "))
+	}
+
+	chain := append(append([]model.Connection{}, conn.Jumps...), conn)
+	hops := make([]*ssh.Client, 0, len(chain))
+	var host string
+	closeHops := func() {
+		for i := len(hops) - 1; i >= 0; i-- {
+			hops[i].Close()
+		}
+	}
+
+	for i, hop := range chain {
+		var via *ssh.Client
+		if i > 0 {
+			via = hops[i-1]
+		}
+		// Only the target's banner is of interest
+		var hopBanner ssh.BannerCallback
+		if i == len(chain)-1 {
+			hopBanner = banner
+		}
+
+		client, addr, err := dialHop(via, hop, timeout, hostKeyCallback, hopBanner)
+		if err != nil {
+			closeHops()
+			return nil, "", &JumpError{Hop: i + 1, Hops: len(chain), Conn: hop, Err: err}
+		}
+		hops = append(hops, client)
+		host = addr
+	}
+
+	target := hops[len(hops)-1]
+	hops = hops[:len(hops)-1]
+	go func() {
+		_ = target.Wait()
+		closeHops()
+	}()
+	return target, host, nil
+}
+
+// dialHop connects to conn directly from its bind address, or through via
+// when it is set, trying its addresses in order. It returns the host part
+// of the address used.
+func dialHop(via *ssh.Client, conn model.Connection, timeout time.Duration, hostKeyCallback ssh.HostKeyCallback, banner ssh.BannerCallback) (*ssh.Client, string, error) {
+	if conn.IsTelnet() {
+		return nil, "", ErrTelnet
+	}
+	authMethods, err := BuildAuthMethods(conn)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %w", errAuthMethods, err)
+	}
+	if hostKeyCallback == nil {
+		hostKeyCallback = ssh.InsecureIgnoreHostKey()
+	}
+
+	config := &ssh.ClientConfig{
+		User:            conn.User,
+		Auth:            authMethods,
+		HostKeyCallback: hostKeyCallback,
+		BannerCallback:  banner,
+		Timeout:         timeout,
+	}
+	addrs, hostAddr, err := dialTargets(conn)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if via == nil {
+		return dialSSH(conn.BindAddress, addrs, hostAddr, config)
+	}
+
+	// Dials through a hop take no timeout, so addresses are tried one by one
+	var netConn net.Conn
+	var used string
+	for _, candidate := range addrs {
+		if netConn, err = via.Dial("tcp", candidate); err == nil {
+			used = candidate
+			break
+		}
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if hostAddr == "" {
+		hostAddr = used
+	}
+	c, chans, reqs, err := ssh.NewClientConn(netConn, hostAddr, config)
+	if err != nil {
+		netConn.Close()
+		return nil, "", err
+	}
+	host, _, _ := net.SplitHostPort(used)
+	return ssh.NewClient(c, chans, reqs), host, nil
+}
@@ -0,0 +1,69 @@
+package ssh
+
+import (
+	"errors"
+	"testing"
+
+	"gossh/internal/model"
+)
+
+func TestConnectViaJumps(t *testing.T) {
+	first, second, target := startExecServer(t), startExecServer(t), startExecServer(t)
+	first.Name, second.Name, target.Name = "bastion-a", "bastion-b", "db"
+	target.JumpHosts = []string{"bastion-a", "bastion-b"}
+	target.Jumps = []model.Connection{first, second}
+
+	client, err := ConnectWithConnection(target, nil)
+	if err != nil {
+		t.Fatalf("ConnectWithConnection() error = %v", err)
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		t.Fatalf("NewSession() error = %v", err)
+	}
+	defer session.Close()
+	if code, _ := ExitStatus(session.Run("exit 4")); code != 4 {
+		t.Errorf("exit status through jump hosts = %d, want 4", code)
+	}
+}
+
+func TestConnectViaJumpsAttributesFailure(t *testing.T) {
+	bastion, target := startExecServer(t), startExecServer(t)
+	down := model.Connection{Name: "down", Host: "127.0.0.1", Port: 1, User: "test"}
+
+	tests := []struct {
+		name    string
+		jumps   []model.Connection
+		wantHop int
+		wantFor string
+	}{
+		{"first hop down", []model.Connection{down, bastion}, 1, "down"},
+		{"second hop down", []model.Connection{bastion, down}, 2, "down"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := target
+			conn.JumpHosts = []string{"x"}
+			conn.Jumps = tt.jumps
+
+			_, err := ConnectWithConnection(conn, nil)
+			var jumpErr *JumpError
+			if !errors.As(err, &jumpErr) {
+				t.Fatalf("ConnectWithConnection() error = %v, want *JumpError", err)
+			}
+			if jumpErr.Hop != tt.wantHop || jumpErr.Hops != 3 || jumpErr.Conn.Name != tt.wantFor {
+				t.Errorf("failed hop = %d/%d (%s), want %d/3 (%s)", jumpErr.Hop, jumpErr.Hops, jumpErr.Conn.Name, tt.wantHop, tt.wantFor)
+			}
+		})
+	}
+}
+
+func TestConnectViaJumpsUnresolved(t *testing.T) {
+	conn := model.Connection{Name: "db", Host: "10.0.0.5", Port: 22, User: "root", JumpHosts: []string{"gone"}}
+	if _, err := ConnectWithConnection(conn, nil); !errors.Is(err, model.ErrJumpNotFound) {
+		t.Errorf("ConnectWithConnection() error = %v, want ErrJumpNotFound", err)
+	}
+}
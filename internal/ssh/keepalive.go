@@ -3,17 +3,36 @@ package ssh
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	gossh "golang.org/x/crypto/ssh"
 )
 
 const (
-	keepaliveInterval  = 10 * time.Second
-	keepaliveMaxFailed = 3
-	keepaliveRequest   = "keepalive@openssh.com"
+	defaultKeepaliveInterval = 10 * time.Second
+	keepaliveMaxFailed       = 3
+	keepaliveRequest         = "keepalive@openssh.com"
 )
 
+// keepaliveSetting holds the keepalive_seconds setting, zero for the
+// default. The TUI changes it while sessions send keepalives.
+var keepaliveSetting atomic.Int64
+
+// SetKeepaliveInterval sets how often sessions send keepalives, e.g. from
+// the keepalive_seconds setting. Zero restores the default.
+func SetKeepaliveInterval(interval time.Duration) {
+	keepaliveSetting.Store(int64(max(interval, 0)))
+}
+
+// keepaliveInterval is how often keepalives are sent
+func keepaliveInterval() time.Duration {
+	if interval := time.Duration(keepaliveSetting.Load()); interval > 0 {
+		return interval
+	}
+	return defaultKeepaliveInterval
+}
+
 // Keepalive sends periodic keepalive requests over an SSH connection
 // and closes the connection when it detects it is dead.
 type Keepalive struct {
@@ -52,7 +71,7 @@ func (k *Keepalive) DeadError() error {
 }
 
 func (k *Keepalive) loop() {
-	ticker := time.NewTicker(keepaliveInterval)
+	ticker := time.NewTicker(keepaliveInterval())
 	defer ticker.Stop()
 
 	failures := 0
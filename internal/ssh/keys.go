@@ -0,0 +1,92 @@
+package ssh
+
+import (
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/crypto/ssh"
+	"gossh/internal/config"
+)
+
+// maxKeySize bounds the files read when looking for private keys
+const maxKeySize = 64 << 10
+
+// KeyFile is a private key found on disk
+type KeyFile struct {
+	Path      string
+	Type      string // e.g. ssh-ed25519, empty when it cannot be told
+	Encrypted bool   // A passphrase is needed to use the key
+}
+
+// pemKeyTypes names the key type of legacy PEM keys, which only say it in
+// their header when encrypted
+var pemKeyTypes = map[string]string{
+	"RSA PRIVATE KEY": ssh.KeyAlgoRSA,
+	"DSA PRIVATE KEY": ssh.KeyAlgoDSA,
+	"EC PRIVATE KEY":  "ecdsa",
+}
+
+// InspectKey reads a private key file, reporting its type and whether it
+// is encrypted. It fails when the file cannot be read or holds no private
+// key. A leading "~/" is expanded.
+func InspectKey(path string) (KeyFile, error) {
+	path = config.ExpandHome(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return KeyFile{}, err
+	}
+	if info.IsDir() || info.Size() > maxKeySize {
+		return KeyFile{}, fmt.Errorf("%s: not a private key", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return KeyFile{}, err
+	}
+
+	key := KeyFile{Path: path}
+	signer, err := ssh.ParsePrivateKey(data)
+	var missing *ssh.PassphraseMissingError
+	switch {
+	case err == nil:
+		key.Type = signer.PublicKey().Type()
+	case errors.As(err, &missing):
+		key.Encrypted = true
+		if missing.PublicKey != nil {
+			key.Type = missing.PublicKey.Type()
+		} else if block, _ := pem.Decode(data); block != nil {
+			key.Type = pemKeyTypes[block.Type]
+		}
+	default:
+		return KeyFile{}, fmt.Errorf("%s: not a private key", path)
+	}
+	return key, nil
+}
+
+// FindKeys lists the private keys directly inside dirs, sorted by path.
+// Other files, e.g. public keys and known_hosts, and missing directories
+// are skipped.
+func FindKeys(dirs ...string) []KeyFile {
+	var keys []KeyFile
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if e.IsDir() || filepath.Ext(e.Name()) == ".pub" {
+				continue
+			}
+			if key, err := InspectKey(filepath.Join(dir, e.Name())); err == nil {
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Path < keys[j].Path
+	})
+	return keys
+}
@@ -0,0 +1,87 @@
+package ssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// writeKey writes a new ed25519 private key, encrypted when passphrase is set
+func writeKey(t *testing.T, path, passphrase string) {
+	t.Helper()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	var block *pem.Block
+	if passphrase != "" {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(privateKey, "", []byte(passphrase))
+	} else {
+		block, err = ssh.MarshalPrivateKey(privateKey, "")
+	}
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+}
+
+func TestInspectKey(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "id_ed25519")
+	encrypted := filepath.Join(dir, "id_encrypted")
+	writeKey(t, plain, "")
+	writeKey(t, encrypted, "secret")
+
+	key, err := InspectKey(plain)
+	if err != nil {
+		t.Fatalf("InspectKey() error = %v", err)
+	}
+	if key.Type != ssh.KeyAlgoED25519 || key.Encrypted {
+		t.Errorf("InspectKey() = %+v, want an unencrypted ed25519 key", key)
+	}
+
+	key, err = InspectKey(encrypted)
+	if err != nil {
+		t.Fatalf("InspectKey() error = %v", err)
+	}
+	if key.Type != ssh.KeyAlgoED25519 || !key.Encrypted {
+		t.Errorf("InspectKey() = %+v, want an encrypted ed25519 key", key)
+	}
+
+	notKey := filepath.Join(dir, "config")
+	os.WriteFile(notKey, []byte("Host *\n"), 0600)
+	if _, err := InspectKey(notKey); err == nil {
+		t.Error("InspectKey() should fail for a file that is not a key")
+	}
+	if _, err := InspectKey(filepath.Join(dir, "missing")); err == nil {
+		t.Error("InspectKey() should fail for a missing file")
+	}
+}
+
+func TestFindKeys(t *testing.T) {
+	dir := t.TempDir()
+	writeKey(t, filepath.Join(dir, "id_b"), "")
+	writeKey(t, filepath.Join(dir, "id_a"), "secret")
+	os.WriteFile(filepath.Join(dir, "id_a.pub"), []byte("ssh-ed25519 AAAA"), 0644)
+	os.WriteFile(filepath.Join(dir, "known_hosts"), []byte("example.com ssh-ed25519 AAAA\n"), 0644)
+	os.Mkdir(filepath.Join(dir, "sub"), 0755)
+
+	keys := FindKeys(dir, filepath.Join(dir, "missing"))
+	if len(keys) != 2 {
+		t.Fatalf("FindKeys() = %+v, want 2 keys", keys)
+	}
+	if filepath.Base(keys[0].Path) != "id_a" || !keys[0].Encrypted {
+		t.Errorf("keys[0] = %+v, want the encrypted id_a first", keys[0])
+	}
+	if filepath.Base(keys[1].Path) != "id_b" || keys[1].Encrypted {
+		t.Errorf("keys[1] = %+v, want the plain id_b", keys[1])
+	}
+}
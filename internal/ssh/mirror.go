@@ -0,0 +1,208 @@
+package ssh
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"gossh/internal/config"
+)
+
+// viewerBuffer is how many pending writes a viewer may lag behind before
+// it is dropped, so a slow viewer never stalls the session
+const viewerBuffer = 256
+
+// Mirror broadcasts session output to read-only viewers connected to a
+// local socket, or appends it to a file, similar to `script -f`
+type Mirror struct {
+	listener net.Listener
+	file     *os.File
+	network  string
+	addr     string
+
+	mu      sync.Mutex
+	viewers map[net.Conn]chan []byte
+	closed  bool
+}
+
+// NewMirror creates a mirror for a target:
+//
+//	tcp:127.0.0.1:7000, 127.0.0.1:7000 or :7000  TCP socket (bare ports bind to loopback)
+//	unix:/tmp/gossh.sock                       Unix socket
+//	file:/tmp/session.log or /tmp/session.log  Appended file
+func NewMirror(target string) (*Mirror, error) {
+	m := &Mirror{viewers: make(map[net.Conn]chan []byte)}
+
+	switch {
+	case strings.HasPrefix(target, "unix:"):
+		m.network, m.addr = "unix", strings.TrimPrefix(target, "unix:")
+	case strings.HasPrefix(target, "tcp:"):
+		m.network, m.addr = "tcp", strings.TrimPrefix(target, "tcp:")
+	case strings.HasPrefix(target, "file:"):
+		m.network, m.addr = "file", strings.TrimPrefix(target, "file:")
+	case isHostPort(target):
+		m.network, m.addr = "tcp", target
+	default:
+		m.network, m.addr = "file", target
+	}
+
+	if m.addr == "" {
+		return nil, fmt.Errorf("invalid share target: %q", target)
+	}
+
+	if m.network == "file" {
+		// Session output may hold secrets typed or shown in it
+		f, err := config.OpenPrivateFile(m.addr, os.O_CREATE|os.O_WRONLY|os.O_APPEND)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open share file: %w", err)
+		}
+		m.file = f
+		return m, nil
+	}
+
+	if m.network == "tcp" && strings.HasPrefix(m.addr, ":") {
+		// Sharing is meant for colleagues on this machine unless a host is given
+		m.addr = "127.0.0.1" + m.addr
+	}
+
+	listener, err := net.Listen(m.network, m.addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", m.addr, err)
+	}
+	if m.network == "unix" {
+		_ = os.Chmod(m.addr, 0600)
+	}
+	m.listener = listener
+	m.addr = listener.Addr().String()
+
+	go m.acceptLoop()
+	return m, nil
+}
+
+// isHostPort reports whether s looks like host:port rather than a file path
+func isHostPort(s string) bool {
+	_, port, err := net.SplitHostPort(s)
+	if err != nil || port == "" {
+		return false
+	}
+	for _, c := range port {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Network returns "tcp", "unix" or "file"
+func (m *Mirror) Network() string {
+	return m.network
+}
+
+// Addr returns the listening address or file path
+func (m *Mirror) Addr() string {
+	return m.addr
+}
+
+// Viewers returns the number of connected viewers
+func (m *Mirror) Viewers() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.viewers)
+}
+
+// acceptLoop accepts viewers until the listener is closed
+func (m *Mirror) acceptLoop() {
+	for {
+		conn, err := m.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		ch := make(chan []byte, viewerBuffer)
+		m.mu.Lock()
+		if m.closed {
+			m.mu.Unlock()
+			conn.Close()
+			return
+		}
+		m.viewers[conn] = ch
+		m.mu.Unlock()
+
+		go m.serveViewer(conn, ch)
+	}
+}
+
+// serveViewer streams output to one viewer. Nothing is ever read from the
+// connection, which keeps the share read-only.
+func (m *Mirror) serveViewer(conn net.Conn, ch chan []byte) {
+	defer conn.Close()
+	for data := range ch {
+		if _, err := conn.Write(data); err != nil {
+			m.dropViewer(conn)
+			return
+		}
+	}
+}
+
+// dropViewer disconnects a viewer (caller must not hold the lock)
+func (m *Mirror) dropViewer(conn net.Conn) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dropViewerUnlocked(conn)
+}
+
+func (m *Mirror) dropViewerUnlocked(conn net.Conn) {
+	if ch, ok := m.viewers[conn]; ok {
+		delete(m.viewers, conn)
+		close(ch)
+	}
+}
+
+// Write mirrors p to the file or every viewer. It never fails so it can sit
+// behind an io.MultiWriter next to the real terminal.
+func (m *Mirror) Write(p []byte) (int, error) {
+	if m.file != nil {
+		_, _ = m.file.Write(p)
+		return len(p), nil
+	}
+
+	data := make([]byte, len(p))
+	copy(data, p)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for conn, ch := range m.viewers {
+		select {
+		case ch <- data:
+		default:
+			// Viewer fell too far behind
+			m.dropViewerUnlocked(conn)
+		}
+	}
+	return len(p), nil
+}
+
+// Close stops sharing and disconnects all viewers
+func (m *Mirror) Close() error {
+	if m.file != nil {
+		return m.file.Close()
+	}
+
+	m.mu.Lock()
+	m.closed = true
+	for conn := range m.viewers {
+		m.dropViewerUnlocked(conn)
+	}
+	m.mu.Unlock()
+
+	err := m.listener.Close()
+	if m.network == "unix" {
+		_ = os.Remove(m.addr)
+	}
+	return err
+}
+
+var _ io.WriteCloser = (*Mirror)(nil)
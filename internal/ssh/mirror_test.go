@@ -0,0 +1,153 @@
+package ssh
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitForViewers polls until the mirror has n viewers
+func waitForViewers(t *testing.T, m *Mirror, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for m.Viewers() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Viewers() = %d, want %d", m.Viewers(), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestMirrorTCP(t *testing.T) {
+	m, err := NewMirror(":0")
+	if err != nil {
+		t.Fatalf("NewMirror() error = %v", err)
+	}
+	defer m.Close()
+
+	if m.Network() != "tcp" || !strings.HasPrefix(m.Addr(), "127.0.0.1:") {
+		t.Fatalf("NewMirror(:0) = %s %s, want loopback tcp", m.Network(), m.Addr())
+	}
+
+	viewer, err := net.Dial("tcp", m.Addr())
+	if err != nil {
+		t.Fatalf("Failed to connect viewer: %v", err)
+	}
+	defer viewer.Close()
+	waitForViewers(t, m, 1)
+
+	if _, err := m.Write([]byte("hello\r\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	m.Close()
+
+	got, _ := io.ReadAll(viewer)
+	if string(got) != "hello\r\n" {
+		t.Errorf("viewer received %q, want %q", got, "hello\r\n")
+	}
+}
+
+func TestMirrorUnix(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets")
+	}
+
+	path := filepath.Join(t.TempDir(), "share.sock")
+	m, err := NewMirror("unix:" + path)
+	if err != nil {
+		t.Fatalf("NewMirror() error = %v", err)
+	}
+
+	viewer, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Failed to connect viewer: %v", err)
+	}
+	defer viewer.Close()
+	waitForViewers(t, m, 1)
+
+	m.Write([]byte("ls\r\n"))
+	m.Close()
+
+	got, _ := io.ReadAll(viewer)
+	if string(got) != "ls\r\n" {
+		t.Errorf("viewer received %q, want %q", got, "ls\r\n")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Close() should remove the socket file")
+	}
+}
+
+func TestMirrorFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.log")
+	m, err := NewMirror(path)
+	if err != nil {
+		t.Fatalf("NewMirror() error = %v", err)
+	}
+	if m.Network() != "file" {
+		t.Fatalf("Network() = %s, want file", m.Network())
+	}
+
+	m.Write([]byte("one\n"))
+	m.Write([]byte("two\n"))
+	m.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read share file: %v", err)
+	}
+	if string(data) != "one\ntwo\n" {
+		t.Errorf("share file = %q, want %q", data, "one\ntwo\n")
+	}
+}
+
+func TestMirrorDropsSlowViewer(t *testing.T) {
+	m, err := NewMirror(":0")
+	if err != nil {
+		t.Fatalf("NewMirror() error = %v", err)
+	}
+	defer m.Close()
+
+	// A viewer that never reads eventually fills its buffer and the socket
+	viewer, err := net.Dial("tcp", m.Addr())
+	if err != nil {
+		t.Fatalf("Failed to connect viewer: %v", err)
+	}
+	defer viewer.Close()
+	waitForViewers(t, m, 1)
+
+	chunk := make([]byte, 64*1024)
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10*viewerBuffer; i++ {
+			m.Write(chunk)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Write() blocked on a slow viewer")
+	}
+	waitForViewers(t, m, 0)
+}
+
+func TestIsHostPort(t *testing.T) {
+	tests := map[string]bool{
+		":7000":             true,
+		"127.0.0.1:7000":    true,
+		"/tmp/session.log":  false,
+		"session.log":       false,
+		"C:\\logs\\out.txt": false,
+	}
+	for input, want := range tests {
+		if got := isHostPort(input); got != want {
+			t.Errorf("isHostPort(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package ssh
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// PlainLines turns terminal output into plain text lines: escape sequences
+// are dropped, and carriage returns and backspaces are applied
+func PlainLines(out []byte) []string {
+	var lines []string
+	var line []rune
+
+	s := string(out)
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+
+		switch {
+		case r == '\x1b':
+			i = skipEscape(s, i)
+		case r == '\n':
+			lines = append(lines, string(line))
+			line = line[:0]
+		case r == '\r':
+			// Redraws start over at the beginning of the line
+			if i < len(s) && s[i] != '\n' {
+				line = line[:0]
+			}
+		case r == '\b':
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+			}
+		case r == '\t':
+			line = append(line, ' ')
+		case r < ' ' || r == 0x7f:
+			// Other control characters, e.g. the bell
+		default:
+			line = append(line, r)
+		}
+	}
+	return append(lines, string(line))
+}
+
+// skipEscape returns the index just past the escape sequence whose ESC
+// ended at i
+func skipEscape(s string, i int) int {
+	if i >= len(s) {
+		return i
+	}
+	switch s[i] {
+	case '[':
+		// CSI: parameters up to a final byte in @..~
+		for i++; i < len(s); i++ {
+			if s[i] >= '@' && s[i] <= '~' {
+				return i + 1
+			}
+		}
+		return i
+	case ']':
+		// OSC: up to BEL or ESC \
+		for i++; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return i
+	}
+	// Two-character sequences such as ESC =
+	return i + 1
+}
+
+// PlainText is PlainLines joined back together, for output captured from a
+// pseudo-terminal
+func PlainText(out []byte) string {
+	return strings.Join(PlainLines(out), "\n")
+}
@@ -0,0 +1,336 @@
+package ssh
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"gossh/internal/model"
+)
+
+// Pool shares SSH connections between features, so an SFTP session or a
+// forward to a host that already has a session open reuses its connection
+// instead of dialing a new one. Connections are reference counted and
+// closed when the last user releases them, or kept warm for the idle
+// timeout so the next connect to the host skips dialing and authentication.
+type Pool struct {
+	mu      sync.Mutex
+	entries map[string]*poolEntry
+	idle    time.Duration // How long unused connections stay open; zero closes them at once
+	dial    func(conn model.Connection, timeout time.Duration, hostKeyCallback ssh.HostKeyCallback, banner ssh.BannerCallback) (*ssh.Client, string, error)
+}
+
+type poolEntry struct {
+	key     string
+	client  *ssh.Client
+	refs    int
+	banner  string
+	address string      // Address the host was reached at
+	expiry  *time.Timer // Closes the connection once it has been unused for the idle timeout
+	unused  time.Time   // When the last user released the connection
+	keys    []hostKey   // Host keys seen while dialing, jump hosts first
+}
+
+// hostKey is a host key the server presented, checked again with the host
+// key callback of each later user of the connection
+type hostKey struct {
+	hostname string
+	remote   net.Addr
+	key      ssh.PublicKey
+}
+
+// warmCheckTimeout bounds checking that a warm connection still answers
+// before it is handed out again
+const warmCheckTimeout = 5 * time.Second
+
+// DefaultPool is shared by Client, Forwarder and the SFTP client
+var DefaultPool = NewPool()
+
+// NewPool creates an empty connection pool
+func NewPool() *Pool {
+	return &Pool{
+		entries: make(map[string]*poolEntry),
+		dial:    connectWithin,
+	}
+}
+
+// SetIdleTimeout sets how long connections stay open after their last user
+// releases them. Zero closes them at once, including those kept open so far.
+func (p *Pool) SetIdleTimeout(idle time.Duration) {
+	p.mu.Lock()
+	p.idle = max(idle, 0)
+	var closing []*ssh.Client
+	for key, e := range p.entries {
+		if e.refs > 0 {
+			continue
+		}
+		if p.idle > 0 {
+			e.expiry.Reset(time.Until(e.unused.Add(p.idle)))
+			continue
+		}
+		e.expiry.Stop()
+		delete(p.entries, key)
+		closing = append(closing, e.client)
+	}
+	p.mu.Unlock()
+
+	for _, client := range closing {
+		_ = client.Close()
+	}
+}
+
+// PoolKey identifies connections that can be shared: same user, host, port,
+// bind address, jump hosts and credentials
+func PoolKey(conn model.Connection) string {
+	key := conn.User + "@" + net.JoinHostPort(conn.Host, strconv.Itoa(conn.Port))
+	if conn.BindAddress != "" {
+		key += " from " + conn.BindAddress
+	}
+	if len(conn.Jumps) > 0 {
+		hops := make([]string, len(conn.Jumps))
+		for i, hop := range conn.Jumps {
+			hops[i] = hop.User + "@" + net.JoinHostPort(hop.Host, strconv.Itoa(hop.Port))
+		}
+		key += " via " + strings.Join(hops, ",")
+	} else if len(conn.JumpHosts) > 0 {
+		// Not resolved: dialing fails, but must not reuse a direct connection
+		key += " via " + strings.Join(conn.JumpHosts, ",")
+	}
+	if conn.AuthType != "" {
+		key += " auth " + string(conn.AuthType)
+	}
+	if conn.KeyPath != "" {
+		key += " key " + conn.KeyPath
+	}
+	if conn.AgentKey != "" {
+		key += " agent " + conn.AgentKey
+	}
+	return key
+}
+
+// Acquire returns an open connection for conn, dialing only when there is
+// none yet. A shared connection is handed out only once hostKeyCallback
+// accepts the host keys it was dialed with. Every Acquire must be matched
+// by a Release.
+func (p *Pool) Acquire(conn model.Connection, hostKeyCallback ssh.HostKeyCallback) (*ssh.Client, error) {
+	return p.acquire(conn, connectTimeout(), hostKeyCallback)
+}
+
+// acquire is Acquire, with timeout bounding each hop of a dial
+func (p *Pool) acquire(conn model.Connection, timeout time.Duration, hostKeyCallback ssh.HostKeyCallback) (*ssh.Client, error) {
+	key := PoolKey(conn)
+
+	p.mu.Lock()
+	if e, ok := p.entries[key]; ok {
+		e.refs++
+		warm := e.refs == 1 && e.expiry != nil
+		if warm {
+			e.expiry.Stop()
+			e.expiry = nil
+		}
+		p.mu.Unlock()
+
+		if err := e.verify(hostKeyCallback); err != nil {
+			_ = p.Release(e.client)
+			return nil, err
+		}
+		// A warm connection sat unused without keepalives, so the host may
+		// have gone away in the meantime
+		if !warm || alive(e.client) {
+			return e.client, nil
+		}
+		_ = p.Discard(e.client)
+	} else {
+		p.mu.Unlock()
+	}
+
+	// Dial without holding the lock so other hosts are not blocked. The
+	// host keys are kept for the callbacks of later users.
+	var banner strings.Builder
+	var keysMu sync.Mutex
+	var keys []hostKey
+	record := func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		keysMu.Lock()
+		keys = append(keys, hostKey{hostname: hostname, remote: remote, key: key})
+		keysMu.Unlock()
+		if hostKeyCallback == nil {
+			return nil
+		}
+		return hostKeyCallback(hostname, remote, key)
+	}
+	client, address, err := p.dial(conn, timeout, record, func(message string) error {
+		banner.WriteString(message)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Another caller may have connected in the meantime
+	if e, ok := p.entries[key]; ok {
+		client.Close()
+		if err := e.verify(hostKeyCallback); err != nil {
+			return nil, err
+		}
+		e.refs++
+		return e.client, nil
+	}
+
+	keysMu.Lock()
+	e := &poolEntry{key: key, client: client, refs: 1, banner: banner.String(), address: address, keys: keys}
+	keysMu.Unlock()
+	p.entries[key] = e
+
+	// Forget connections that die, e.g. closed by keepalive or the server
+	go func() {
+		_ = client.Wait()
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.entries[key] == e {
+			delete(p.entries, key)
+		}
+	}()
+
+	return client, nil
+}
+
+// Release gives up one reference, closing the connection with the last one
+func (p *Pool) Release(client *ssh.Client) error {
+	p.mu.Lock()
+	e := p.lookup(client)
+	if e != nil {
+		e.refs--
+		if e.refs > 0 {
+			p.mu.Unlock()
+			return nil
+		}
+		if p.idle > 0 {
+			e.unused = time.Now()
+			e.expiry = time.AfterFunc(p.idle, func() { p.expire(e) })
+			p.mu.Unlock()
+			return nil
+		}
+		delete(p.entries, e.key)
+	}
+	p.mu.Unlock()
+
+	return client.Close()
+}
+
+// expire closes a warm connection that is still unused
+func (p *Pool) expire(e *poolEntry) {
+	p.mu.Lock()
+	// A timer stopped too late must not close a connection that has been
+	// used and released again since
+	if p.entries[e.key] != e || e.refs > 0 || time.Since(e.unused) < p.idle {
+		p.mu.Unlock()
+		return
+	}
+	delete(p.entries, e.key)
+	p.mu.Unlock()
+
+	_ = e.client.Close()
+}
+
+// Discard closes a connection for all of its users, e.g. when it hangs
+func (p *Pool) Discard(client *ssh.Client) error {
+	p.mu.Lock()
+	if e := p.lookup(client); e != nil {
+		if e.expiry != nil {
+			e.expiry.Stop()
+		}
+		delete(p.entries, e.key)
+	}
+	p.mu.Unlock()
+
+	return client.Close()
+}
+
+// Warm reports whether an unused connection for conn is being kept open
+func (p *Pool) Warm(conn model.Connection) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e, ok := p.entries[PoolKey(conn)]
+	return ok && e.refs == 0
+}
+
+// Refs returns how many users share the connection for conn
+func (p *Pool) Refs(conn model.Connection) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if e, ok := p.entries[PoolKey(conn)]; ok {
+		return e.refs
+	}
+	return 0
+}
+
+// Banner returns the pre-login banner the server sent when client was
+// dialed, empty if it sent none
+func (p *Pool) Banner(client *ssh.Client) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if e := p.lookup(client); e != nil {
+		return e.banner
+	}
+	return ""
+}
+
+// Address returns the address client reached its host at, which is one of
+// the connection's other addresses when the host itself did not answer
+func (p *Pool) Address(client *ssh.Client) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if e := p.lookup(client); e != nil {
+		return e.address
+	}
+	return ""
+}
+
+// verify checks the host keys the connection was dialed with against
+// hostKeyCallback, as dialing would have. nil accepts any key.
+func (e *poolEntry) verify(hostKeyCallback ssh.HostKeyCallback) error {
+	if hostKeyCallback == nil {
+		return nil
+	}
+	for _, k := range e.keys {
+		if err := hostKeyCallback(k.hostname, k.remote, k.key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// alive reports whether client answers a keepalive in time
+func alive(client *ssh.Client) bool {
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := client.SendRequest(keepaliveRequest, true, nil)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		return err == nil
+	case <-time.After(warmCheckTimeout):
+		return false
+	}
+}
+
+// lookup finds the entry for client (caller must hold the lock)
+func (p *Pool) lookup(client *ssh.Client) *poolEntry {
+	for _, e := range p.entries {
+		if e.client == client {
+			return e
+		}
+	}
+	return nil
+}
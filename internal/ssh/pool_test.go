@@ -0,0 +1,248 @@
+package ssh
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"gossh/internal/model"
+)
+
+// countingPool dials real connections and counts how often it does
+func countingPool(dials *int) *Pool {
+	p := NewPool()
+	p.dial = func(conn model.Connection, timeout time.Duration, hostKeyCallback ssh.HostKeyCallback, banner ssh.BannerCallback) (*ssh.Client, string, error) {
+		*dials++
+		return connectWithin(conn, timeout, hostKeyCallback, banner)
+	}
+	return p
+}
+
+func TestPoolSharesConnection(t *testing.T) {
+	conn := startExecServer(t)
+	dials := 0
+	p := countingPool(&dials)
+
+	first, err := p.Acquire(conn, nil)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	second, err := p.Acquire(conn, nil)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	if first != second || dials != 1 || p.Refs(conn) != 2 {
+		t.Fatalf("second Acquire() dials = %d, refs = %d; want a shared connection", dials, p.Refs(conn))
+	}
+
+	// The connection stays usable until the last user releases it
+	p.Release(first)
+	session, err := second.NewSession()
+	if err != nil {
+		t.Fatalf("NewSession() after one Release error = %v", err)
+	}
+	session.Close()
+
+	p.Release(second)
+	if p.Refs(conn) != 0 {
+		t.Errorf("Refs() = %d after final Release, want 0", p.Refs(conn))
+	}
+	if _, err := second.NewSession(); err == nil {
+		t.Error("connection should be closed after the final Release")
+	}
+
+	if _, err := p.Acquire(conn, nil); err != nil || dials != 2 {
+		t.Errorf("Acquire() after close dials = %d, %v; want a new connection", dials, err)
+	}
+}
+
+func TestPoolDiscard(t *testing.T) {
+	conn := startExecServer(t)
+	dials := 0
+	p := countingPool(&dials)
+
+	client, _ := p.Acquire(conn, nil)
+	p.Acquire(conn, nil)
+
+	p.Discard(client)
+	if p.Refs(conn) != 0 {
+		t.Errorf("Refs() = %d after Discard, want 0", p.Refs(conn))
+	}
+	if _, err := client.NewSession(); err == nil {
+		t.Error("Discard() should close the connection for every user")
+	}
+}
+
+func TestPoolKeepsWarm(t *testing.T) {
+	conn := startExecServer(t)
+	dials := 0
+	p := countingPool(&dials)
+	p.SetIdleTimeout(time.Minute)
+
+	first, err := p.Acquire(conn, nil)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	p.Release(first)
+	if !p.Warm(conn) || p.Refs(conn) != 0 {
+		t.Fatalf("Warm() = %v, Refs() = %d after Release; want an unused open connection", p.Warm(conn), p.Refs(conn))
+	}
+
+	second, err := p.Acquire(conn, nil)
+	if err != nil || second != first || dials != 1 {
+		t.Fatalf("Acquire() of a warm connection dials = %d, %v; want it reused", dials, err)
+	}
+	p.Release(second)
+
+	// Turning the timeout off closes what was kept open
+	p.SetIdleTimeout(0)
+	if p.Warm(conn) {
+		t.Error("Warm() after SetIdleTimeout(0) = true, want false")
+	}
+	if _, err := first.NewSession(); err == nil {
+		t.Error("SetIdleTimeout(0) should close unused connections")
+	}
+}
+
+func TestPoolIdleExpiry(t *testing.T) {
+	conn := startExecServer(t)
+	dials := 0
+	p := countingPool(&dials)
+	p.SetIdleTimeout(10 * time.Millisecond)
+
+	client, err := p.Acquire(conn, nil)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	p.Release(client)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for p.Warm(conn) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if p.Warm(conn) {
+		t.Fatal("connection still warm after the idle timeout")
+	}
+	if _, err := client.NewSession(); err == nil {
+		t.Error("an expired connection should be closed")
+	}
+}
+
+func TestPoolBanner(t *testing.T) {
+	conn := startExecServer(t)
+	dials := 0
+	p := countingPool(&dials)
+
+	first, err := p.Acquire(conn, nil)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	second, _ := p.Acquire(conn, nil)
+
+	// A shared connection keeps the banner from when it was dialed
+	if got := p.Banner(second); got != testBanner {
+		t.Errorf("Banner() = %q, want %q", got, testBanner)
+	}
+
+	p.Discard(first)
+	if got := p.Banner(first); got != "" {
+		t.Errorf("Banner() after Discard = %q, want empty", got)
+	}
+}
+
+func TestPoolKey(t *testing.T) {
+	a := model.Connection{User: "root", Host: "10.0.0.1", Port: 22}
+	b := model.Connection{User: "deploy", Host: "10.0.0.1", Port: 22}
+	c := model.Connection{User: "root", Host: "::1", Port: 2222}
+
+	if PoolKey(a) == PoolKey(b) {
+		t.Error("different users should not share a connection")
+	}
+	if got := PoolKey(c); got != "root@[::1]:2222" {
+		t.Errorf("PoolKey() = %q, want root@[::1]:2222", got)
+	}
+	d := a
+	d.BindAddress = "10.8.0.2"
+	if PoolKey(a) == PoolKey(d) {
+		t.Error("different bind addresses should not share a connection")
+	}
+
+	e := a
+	e.KeyPath = "~/.ssh/deploy"
+	f := a
+	f.AgentKey = "SHA256:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU"
+	g := a
+	g.AuthType = model.AuthPassword
+	for _, other := range []model.Connection{e, f, g} {
+		if PoolKey(a) == PoolKey(other) {
+			t.Errorf("PoolKey() = %q for different credentials, want distinct keys", PoolKey(other))
+		}
+	}
+
+	h := a
+	h.Jumps = []model.Connection{{User: "jump", Host: "bastion", Port: 22}}
+	i := a
+	i.Jumps = []model.Connection{{User: "jump", Host: "other-bastion", Port: 22}}
+	if PoolKey(a) == PoolKey(h) || PoolKey(h) == PoolKey(i) {
+		t.Error("different jump chains should not share a connection")
+	}
+}
+
+func TestPoolSeparatesJumpChains(t *testing.T) {
+	conn := startExecServer(t)
+	dials := 0
+	p := NewPool()
+	// Dial the target directly: only the jump chain in the key matters here
+	p.dial = func(c model.Connection, timeout time.Duration, hostKeyCallback ssh.HostKeyCallback, banner ssh.BannerCallback) (*ssh.Client, string, error) {
+		dials++
+		c.JumpHosts, c.Jumps = nil, nil
+		return connectWithin(c, timeout, hostKeyCallback, banner)
+	}
+
+	viaA, viaB := conn, conn
+	viaA.JumpHosts = []string{"bastion-a"}
+	viaB.JumpHosts = []string{"bastion-b"}
+
+	first, err := p.Acquire(viaA, nil)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer p.Release(first)
+	second, err := p.Acquire(viaB, nil)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer p.Release(second)
+	if first == second || dials != 2 {
+		t.Errorf("Acquire() via different jump hosts dials = %d, want separate clients", dials)
+	}
+}
+
+func TestPoolChecksHostKeyOnReuse(t *testing.T) {
+	conn := startExecServer(t)
+	dials := 0
+	p := countingPool(&dials)
+
+	client, err := p.Acquire(conn, nil)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer p.Release(client)
+
+	reject := func(string, net.Addr, ssh.PublicKey) error { return ErrHostKeyChanged }
+	if _, err := p.Acquire(conn, reject); !errors.Is(err, ErrHostKeyChanged) {
+		t.Fatalf("Acquire() with a rejecting callback error = %v, want ErrHostKeyChanged", err)
+	}
+	if p.Refs(conn) != 1 {
+		t.Errorf("Refs() = %d after a rejected Acquire, want 1", p.Refs(conn))
+	}
+
+	accept := func(string, net.Addr, ssh.PublicKey) error { return nil }
+	if reused, err := p.Acquire(conn, accept); err != nil || reused != client || dials != 1 {
+		t.Errorf("Acquire() with an accepting callback = %v, dials = %d; want the shared client", err, dials)
+	} else {
+		p.Release(reused)
+	}
+}
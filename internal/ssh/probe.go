@@ -0,0 +1,199 @@
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// MaxProbePorts bounds how many ports one probe checks
+const MaxProbePorts = 1024
+
+const (
+	// probeWorkers is how many ports are checked at once over the login
+	probeWorkers = 8
+	// bannerWait is how long an open port is given to greet, as SSH, SMTP,
+	// FTP and MySQL servers do
+	bannerWait = 500 * time.Millisecond
+	// maxBanner bounds the greeting kept, one line of it
+	maxBanner = 80
+)
+
+// PortState is what probing a port found
+type PortState int
+
+const (
+	PortOpen      PortState = iota
+	PortClosed              // The host could not connect: refused, unreachable, ...
+	PortTimeout             // No answer within the timeout
+	PortForbidden           // The SSH server does not allow forwarding
+)
+
+// PortResult is the outcome of probing one port
+type PortResult struct {
+	Port    int
+	State   PortState
+	Latency time.Duration // Until the port answered, when open
+	Banner  string        // First line the service sent unasked, if any
+	Reason  string        // Why the port is not open, as the server put it
+}
+
+// ParsePorts parses a list of ports and ranges like "22,80,8000-8010".
+// Duplicates are dropped, the order is kept.
+func ParsePorts(spec string) ([]int, error) {
+	var ports []int
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		first, last, isRange := strings.Cut(part, "-")
+		from, err := parsePort(first)
+		if err != nil {
+			return nil, err
+		}
+		to := from
+		if isRange {
+			if to, err = parsePort(last); err != nil {
+				return nil, err
+			}
+			if to < from {
+				return nil, fmt.Errorf("invalid port range %q", part)
+			}
+		}
+		for port := from; port <= to; port++ {
+			if seen[port] {
+				continue
+			}
+			if len(ports) == MaxProbePorts {
+				return nil, fmt.Errorf("at most %d ports can be probed at once", MaxProbePorts)
+			}
+			seen[port] = true
+			ports = append(ports, port)
+		}
+	}
+	if len(ports) == 0 {
+		return nil, errors.New("no ports given")
+	}
+	return ports, nil
+}
+
+// parsePort parses a port number, 1 to 65535
+func parsePort(s string) (int, error) {
+	port, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %q", s)
+	}
+	return port, nil
+}
+
+// ProbePorts checks which ports of host the server client is logged in to
+// can connect to, by opening a direct-tcpip channel (as ssh -L does) to
+// each. Nothing runs on the server and no shell is opened. host is as the
+// server resolves it, "localhost" being the server itself. A port not
+// answering within timeout is reported as such; results are in the order
+// of ports.
+func ProbePorts(client *ssh.Client, host string, ports []int, timeout time.Duration) []PortResult {
+	results := make([]PortResult, len(ports))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(probeWorkers, len(ports)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = probePort(client, host, ports[i], timeout)
+			}
+		}()
+	}
+	for i := range ports {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
+
+// probePort opens a channel to one port and reads what it sends first
+func probePort(client *ssh.Client, host string, port int, timeout time.Duration) PortResult {
+	result := PortResult{Port: port}
+	type dialed struct {
+		conn net.Conn
+		err  error
+	}
+	start := time.Now()
+	done := make(chan dialed, 1)
+	go func() {
+		conn, err := client.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+		done <- dialed{conn, err}
+	}()
+
+	var d dialed
+	select {
+	case d = <-done:
+	case <-time.After(timeout):
+		// The server may still connect later, the channel is closed then
+		go func() {
+			if d := <-done; d.err == nil {
+				d.conn.Close()
+			}
+		}()
+		result.State = PortTimeout
+		return result
+	}
+	if d.err != nil {
+		result.State, result.Reason = PortClosed, d.err.Error()
+		var openErr *ssh.OpenChannelError
+		if errors.As(d.err, &openErr) {
+			result.Reason = openErr.Message
+			if openErr.Reason == ssh.Prohibited {
+				result.State = PortForbidden
+			}
+		}
+		return result
+	}
+	result.State = PortOpen
+	result.Latency = time.Since(start)
+	result.Banner = readBanner(d.conn, bannerWait)
+	return result
+}
+
+// readBanner returns the first line conn sends within wait, cleaned up for
+// printing, and closes conn. Channels have no read deadline, so closing
+// conn is what ends a wait for a service that waits for the client.
+func readBanner(conn net.Conn, wait time.Duration) string {
+	read := make(chan string, 1)
+	go func() {
+		buf := make([]byte, 256)
+		n, _ := conn.Read(buf)
+		read <- string(buf[:n])
+	}()
+
+	var text string
+	select {
+	case text = <-read:
+	case <-time.After(wait):
+	}
+	conn.Close()
+
+	line, _, _ := strings.Cut(text, "\n")
+	line = strings.Map(func(r rune) rune {
+		if !unicode.IsPrint(r) {
+			return -1
+		}
+		return r
+	}, line)
+	line = strings.TrimSpace(line)
+	if len(line) > maxBanner {
+		line = strings.ToValidUTF8(line[:maxBanner], "") + "…"
+	}
+	return line
+}
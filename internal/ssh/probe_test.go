@@ -0,0 +1,97 @@
+package ssh
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParsePorts(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []int
+		wantErr bool
+	}{
+		{"80", []int{80}, false},
+		{"80,443, 5432", []int{80, 443, 5432}, false},
+		{"8000-8003,8001,22", []int{8000, 8001, 8002, 8003, 22}, false},
+		{"443,,", []int{443}, false},
+		{"", nil, true},
+		{"0", nil, true},
+		{"65536", nil, true},
+		{"http", nil, true},
+		{"90-80", nil, true},
+		{"1-2000", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePorts(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePorts(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParsePorts(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+// listenPort returns the port of a local service that greets its clients
+// with greeting, if any, and waits for them to hang up
+func listenPort(t *testing.T, greeting string) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if greeting != "" {
+					_, _ = conn.Write([]byte(greeting))
+				}
+				_, _ = conn.Read(make([]byte, 1))
+			}()
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestProbePorts(t *testing.T) {
+	conn := startExecServer(t)
+	client, err := ConnectWithConnection(conn, nil)
+	if err != nil {
+		t.Fatalf("ConnectWithConnection() error = %v", err)
+	}
+	defer client.Close()
+
+	greeting := listenPort(t, "SSH-2.0-OpenSSH_9.6\r\nmore\r\n")
+	silent := listenPort(t, "")
+	closed := closedPort(t)
+
+	start := time.Now()
+	results := ProbePorts(client, "127.0.0.1", []int{greeting, silent, closed}, 5*time.Second)
+	if len(results) != 3 {
+		t.Fatalf("ProbePorts() returned %d results, want 3", len(results))
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("ProbePorts() took %v, the silent port should only be waited on briefly", elapsed)
+	}
+
+	if r := results[0]; r.Port != greeting || r.State != PortOpen || r.Banner != "SSH-2.0-OpenSSH_9.6" {
+		t.Errorf("greeting port = %+v, want open with its first line", r)
+	}
+	if r := results[1]; r.Port != silent || r.State != PortOpen || r.Banner != "" {
+		t.Errorf("silent port = %+v, want open without a banner", r)
+	}
+	if r := results[2]; r.Port != closed || r.State != PortClosed || r.Reason == "" {
+		t.Errorf("closed port = %+v, want closed with a reason", r)
+	}
+}
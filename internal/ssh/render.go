@@ -0,0 +1,271 @@
+package ssh
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Renderer writes the results of running command on a batch of hosts
+type Renderer interface {
+	Render(w io.Writer, command string, results []BatchResult) error
+}
+
+// OutputFormat is a way of writing batch results
+type OutputFormat string
+
+const (
+	OutputText     OutputFormat = "text"
+	OutputJSON     OutputFormat = "json"
+	OutputJUnit    OutputFormat = "junit"
+	OutputMarkdown OutputFormat = "markdown"
+)
+
+// ParseOutputFormat parses a format name: text, json (or jsonl), junit (or
+// xml), md or markdown
+func ParseOutputFormat(name string) (OutputFormat, error) {
+	switch strings.ToLower(name) {
+	case "text":
+		return OutputText, nil
+	case "json", "jsonl":
+		return OutputJSON, nil
+	case "junit", "xml":
+		return OutputJUnit, nil
+	case "md", "markdown":
+		return OutputMarkdown, nil
+	}
+	return "", fmt.Errorf("unknown output format: %s", name)
+}
+
+// Renderer returns the renderer of the format
+func (f OutputFormat) Renderer() Renderer {
+	switch f {
+	case OutputJSON:
+		return JSONRenderer{}
+	case OutputJUnit:
+		return JUnitRenderer{}
+	case OutputMarkdown:
+		return MarkdownRenderer{}
+	}
+	return TextRenderer{}
+}
+
+// countFailed counts the hosts a batch run failed on
+func countFailed(results []BatchResult) int {
+	failed := 0
+	for _, r := range results {
+		if r.Error != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// TextRenderer writes each host's output under a header, for reading in a
+// terminal
+type TextRenderer struct{}
+
+// Render writes the results as text
+func (TextRenderer) Render(w io.Writer, command string, results []BatchResult) error {
+	fmt.Fprintln(w, "\n"+strings.Repeat("─", 80))
+	fmt.Fprintln(w, "BATCH EXECUTION RESULTS")
+	fmt.Fprintln(w, strings.Repeat("─", 80))
+
+	for _, r := range results {
+		status := "✓"
+		if r.Error != nil {
+			status = "✗"
+		}
+
+		fmt.Fprintf(w, "\n%s [%s] %s@%s:%d (%.2fs)\n",
+			status, r.Connection.Name, r.Connection.User,
+			r.Connection.Host, r.Connection.Port,
+			r.Duration.Seconds())
+		fmt.Fprintln(w, strings.Repeat("─", 40))
+
+		if r.Error != nil {
+			fmt.Fprintf(w, "Error: %v\n", r.Error)
+		}
+		if r.Output != "" {
+			fmt.Fprintln(w, r.Output)
+		}
+	}
+
+	failed := countFailed(results)
+	fmt.Fprintln(w, "\n"+strings.Repeat("─", 80))
+	_, err := fmt.Fprintf(w, "Summary: %d succeeded, %d failed, %d total\n",
+		len(results)-failed, failed, len(results))
+	return err
+}
+
+// jsonResult is one line of JSONRenderer output
+type jsonResult struct {
+	Name       string `json:"name"`
+	Host       string `json:"host"`
+	User       string `json:"user"`
+	Port       int    `json:"port"`
+	Command    string `json:"command"`
+	ExitCode   int    `json:"exit_code"`
+	DurationMs int64  `json:"duration_ms"`
+	Output     string `json:"output"`
+	Error      string `json:"error,omitempty"`
+}
+
+// JSONRenderer writes one JSON object per host and line, for scripts
+type JSONRenderer struct{}
+
+// Render writes the results as JSON lines
+func (JSONRenderer) Render(w io.Writer, command string, results []BatchResult) error {
+	enc := json.NewEncoder(w)
+	for _, r := range results {
+		line := jsonResult{
+			Name:       r.Connection.Name,
+			Host:       r.Connection.Host,
+			User:       r.Connection.User,
+			Port:       r.Connection.Port,
+			Command:    command,
+			ExitCode:   r.ExitCode,
+			DurationMs: r.Duration.Milliseconds(),
+			Output:     r.Output,
+		}
+		if r.Error != nil {
+			line.Error = r.Error.Error()
+		}
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// JUnit XML elements, as read by CI servers
+type (
+	junitSuites struct {
+		XMLName xml.Name     `xml:"testsuites"`
+		Suites  []junitSuite `xml:"testsuite"`
+	}
+	junitSuite struct {
+		Name     string      `xml:"name,attr"`
+		Tests    int         `xml:"tests,attr"`
+		Failures int         `xml:"failures,attr"`
+		Errors   int         `xml:"errors,attr"`
+		Time     string      `xml:"time,attr"`
+		Cases    []junitCase `xml:"testcase"`
+	}
+	junitCase struct {
+		Name      string        `xml:"name,attr"`
+		Classname string        `xml:"classname,attr"`
+		Time      string        `xml:"time,attr"`
+		Failure   *junitProblem `xml:"failure,omitempty"`
+		Error     *junitProblem `xml:"error,omitempty"`
+		SystemOut string        `xml:"system-out,omitempty"`
+	}
+	junitProblem struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+	}
+)
+
+// JUnitRenderer writes a JUnit XML report with a test case per host, so CI
+// servers show which hosts a command failed on. A non-zero exit status is
+// a failure; a host the command could not run on is an error.
+type JUnitRenderer struct{}
+
+// Render writes the results as JUnit XML
+func (JUnitRenderer) Render(w io.Writer, command string, results []BatchResult) error {
+	suite := junitSuite{
+		Name:  "gossh exec " + command,
+		Tests: len(results),
+	}
+	var total float64
+	for _, r := range results {
+		c := junitCase{
+			Name:      r.Connection.Name,
+			Classname: "gossh.exec",
+			Time:      fmt.Sprintf("%.3f", r.Duration.Seconds()),
+			SystemOut: r.Output,
+		}
+		switch {
+		case r.Error == nil:
+		case r.ExitCode != 0:
+			suite.Failures++
+			c.Failure = &junitProblem{Message: r.Error.Error(), Type: "exit"}
+		default:
+			suite.Errors++
+			c.Error = &junitProblem{Message: r.Error.Error(), Type: "connection"}
+		}
+		total += r.Duration.Seconds()
+		suite.Cases = append(suite.Cases, c)
+	}
+	suite.Time = fmt.Sprintf("%.3f", total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitSuites{Suites: []junitSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// MarkdownRenderer writes a summary table and each host's output, e.g. to
+// paste into a ticket
+type MarkdownRenderer struct{}
+
+// Render writes the results as Markdown
+func (MarkdownRenderer) Render(w io.Writer, command string, results []BatchResult) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# `%s`\n\n", strings.ReplaceAll(command, "`", "'"))
+
+	failed := countFailed(results)
+	fmt.Fprintf(&b, "%d succeeded, %d failed, %d total\n\n", len(results)-failed, failed, len(results))
+	b.WriteString("| Host | Address | Status | Duration |\n")
+	b.WriteString("|------|---------|--------|----------|\n")
+	for _, r := range results {
+		status := "✓"
+		if r.Error != nil {
+			status = "✗ " + r.Error.Error()
+		}
+		fmt.Fprintf(&b, "| %s | %s@%s:%d | %s | %.2fs |\n",
+			markdownCell(r.Connection.Name), markdownCell(r.Connection.User),
+			markdownCell(r.Connection.Host), r.Connection.Port,
+			markdownCell(status), r.Duration.Seconds())
+	}
+
+	for _, r := range results {
+		if r.Output == "" {
+			continue
+		}
+		fence := markdownFence(r.Output)
+		fmt.Fprintf(&b, "\n## %s\n\n%s\n%s\n%s\n", r.Connection.Name, fence, strings.TrimRight(r.Output, "\n"), fence)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell escapes a value for a table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// markdownFence returns a code fence longer than any backtick run in s, so
+// the output cannot close its block early
+func markdownFence(s string) string {
+	longest, run := 0, 0
+	for _, c := range s {
+		if c == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(longest+1, 3))
+}
@@ -0,0 +1,141 @@
+package ssh
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gossh/internal/model"
+)
+
+// renderResults is a batch run with a success, a failed command and a host
+// that could not be reached
+func renderResults() []BatchResult {
+	conn := func(name string) model.Connection {
+		return model.Connection{Name: name, Host: name + ".example.com", User: "deploy", Port: 22}
+	}
+	return []BatchResult{
+		{Connection: conn("web-01"), Output: "ok\n", Duration: 1500 * time.Millisecond},
+		{Connection: conn("web-02"), Output: "disk | full\n", ExitCode: 2, Error: errors.New("Process exited with status 2"), Duration: time.Second},
+		{Connection: conn("web-03"), Error: errors.New("connection refused")},
+	}
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    OutputFormat
+		wantErr bool
+	}{
+		{"text", OutputText, false},
+		{"JSON", OutputJSON, false},
+		{"jsonl", OutputJSON, false},
+		{"junit", OutputJUnit, false},
+		{"xml", OutputJUnit, false},
+		{"md", OutputMarkdown, false},
+		{"markdown", OutputMarkdown, false},
+		{"yaml", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseOutputFormat(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseOutputFormat(%q) = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestTextRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (TextRenderer{}).Render(&buf, "uptime", renderResults()); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if strings.ContainsRune(out, 0) {
+		t.Error("text output contains NUL bytes")
+	}
+	for _, want := range []string{"✓ [web-01] deploy@web-01.example.com:22 (1.50s)", "Error: connection refused", "Summary: 1 succeeded, 2 failed, 3 total"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("text output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestJSONRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (JSONRenderer{}).Render(&buf, "uptime", renderResults()); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+
+	var got jsonResult
+	if err := json.Unmarshal([]byte(lines[1]), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := jsonResult{
+		Name: "web-02", Host: "web-02.example.com", User: "deploy", Port: 22, Command: "uptime",
+		ExitCode: 2, DurationMs: 1000, Output: "disk | full\n", Error: "Process exited with status 2",
+	}
+	if got != want {
+		t.Errorf("line 2 = %+v, want %+v", got, want)
+	}
+	if strings.Contains(lines[0], `"error"`) {
+		t.Errorf("successful host has an error: %s", lines[0])
+	}
+}
+
+func TestJUnitRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (JUnitRenderer{}).Render(&buf, "uptime", renderResults()); err != nil {
+		t.Fatal(err)
+	}
+
+	var got junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+	if len(got.Suites) != 1 {
+		t.Fatalf("got %d suites, want 1", len(got.Suites))
+	}
+	s := got.Suites[0]
+	if s.Name != "gossh exec uptime" || s.Tests != 3 || s.Failures != 1 || s.Errors != 1 || s.Time != "2.500" {
+		t.Errorf("suite = %+v", s)
+	}
+	if c := s.Cases[0]; c.Failure != nil || c.Error != nil || c.SystemOut != "ok\n" {
+		t.Errorf("case web-01 = %+v, want a pass", c)
+	}
+	if c := s.Cases[1]; c.Failure == nil || c.Failure.Type != "exit" {
+		t.Errorf("case web-02 = %+v, want a failure", c)
+	}
+	if c := s.Cases[2]; c.Error == nil || c.Error.Message != "connection refused" {
+		t.Errorf("case web-03 = %+v, want an error", c)
+	}
+}
+
+func TestMarkdownRenderer(t *testing.T) {
+	results := renderResults()
+	results[0].Output = "```\ncode\n```\n"
+
+	var buf bytes.Buffer
+	if err := (MarkdownRenderer{}).Render(&buf, "uptime", results); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"# `uptime`",
+		"1 succeeded, 2 failed, 3 total",
+		"| web-01 | deploy@web-01.example.com:22 | ✓ | 1.50s |",
+		"| web-03 | deploy@web-03.example.com:22 | ✗ connection refused | 0.00s |",
+		"## web-01\n\n````\n```\ncode\n```\n````\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown lacks %q:\n%s", want, out)
+		}
+	}
+}
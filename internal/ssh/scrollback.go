@@ -0,0 +1,53 @@
+package ssh
+
+import "sync"
+
+// scrollbackSize is how much output new terminals keep, 0 for none
+var scrollbackSize int
+
+// SetScrollbackSize sets how many bytes of output terminals created from
+// now on keep to look through after their session ends, e.g. from the
+// scrollback_kb setting. Zero keeps none.
+func SetScrollbackSize(size int) {
+	scrollbackSize = max(size, 0)
+}
+
+// Scrollback keeps the most recent output of a session, up to its size.
+// Older output is dropped as new output comes in.
+type Scrollback struct {
+	mu      sync.Mutex
+	size    int
+	buf     []byte
+	dropped bool
+}
+
+// NewScrollback creates a scrollback of size bytes
+func NewScrollback(size int) *Scrollback {
+	return &Scrollback{size: size}
+}
+
+func (s *Scrollback) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.buf = append(s.buf, p...)
+	if len(s.buf) > s.size {
+		s.buf = append(s.buf[:0], s.buf[len(s.buf)-s.size:]...)
+		s.dropped = true
+	}
+	return len(p), nil
+}
+
+// Bytes returns a copy of the output kept
+func (s *Scrollback) Bytes() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]byte(nil), s.buf...)
+}
+
+// Dropped reports whether older output no longer fits
+func (s *Scrollback) Dropped() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
@@ -0,0 +1,54 @@
+package ssh
+
+import (
+	"bytes"
+	"testing"
+
+	"gossh/internal/model"
+)
+
+func TestScrollback(t *testing.T) {
+	sb := NewScrollback(10)
+	sb.Write([]byte("hello "))
+	if got := string(sb.Bytes()); got != "hello " || sb.Dropped() {
+		t.Errorf("Bytes() = %q, dropped %v, want %q kept whole", got, sb.Dropped(), "hello ")
+	}
+
+	sb.Write([]byte("world!"))
+	if got := string(sb.Bytes()); got != "llo world!" {
+		t.Errorf("Bytes() = %q, want the last 10 bytes %q", got, "llo world!")
+	}
+	if !sb.Dropped() {
+		t.Error("Dropped() = false after output no longer fit")
+	}
+
+	sb.Write(bytes.Repeat([]byte("x"), 25))
+	if got := string(sb.Bytes()); got != "xxxxxxxxxx" {
+		t.Errorf("Bytes() after a write larger than the scrollback = %q", got)
+	}
+}
+
+func TestTerminalScrollback(t *testing.T) {
+	defer SetScrollbackSize(0)
+
+	conn := model.Connection{Name: "web", Host: "127.0.0.1", Port: 22, User: "root"}
+	SetScrollbackSize(0)
+	term := NewTerminal(conn)
+	if term.Scrollback() != nil {
+		t.Error("terminal keeps a scrollback with the size at zero")
+	}
+
+	SetScrollbackSize(1024)
+	term = NewTerminal(conn)
+	if term.Scrollback() == nil {
+		t.Fatal("terminal keeps no scrollback")
+	}
+	var out bytes.Buffer
+	term.output(&out).Write([]byte("\x1b[31merror\x1b[0m: id 42\r\n"))
+	if got := PlainText(term.Scrollback().Bytes()); got != "error: id 42\n" {
+		t.Errorf("scrollback text = %q, want %q", got, "error: id 42\n")
+	}
+	if out.String() != "\x1b[31merror\x1b[0m: id 42\r\n" {
+		t.Errorf("output = %q, want it passed through", out.String())
+	}
+}
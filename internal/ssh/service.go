@@ -0,0 +1,66 @@
+package ssh
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"gossh/internal/discovery"
+	"gossh/internal/model"
+)
+
+// dialTargets returns the addresses to dial for conn, in order, and the
+// address its host key is checked against. A service host resolves to its
+// instances, each checked under its own address, so hostAddr is empty.
+func dialTargets(conn model.Connection) ([]string, string, error) {
+	if !discovery.IsService(conn.Host) {
+		return conn.Candidates(), net.JoinHostPort(conn.Host, strconv.Itoa(conn.Port)), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	instances, err := discovery.DefaultResolver.Resolve(ctx, conn.Host)
+	if err != nil {
+		return nil, "", err
+	}
+	addrs := make([]string, len(instances))
+	for i, inst := range instances {
+		addrs[i] = inst.Addr()
+	}
+	return addrs, "", nil
+}
+
+// TargetKey identifies a host of a run by connection ID and address, as
+// the instances ExpandServices makes of a service share the service's ID
+func TargetKey(conn model.Connection) string {
+	return conn.ID + "@" + net.JoinHostPort(conn.Host, strconv.Itoa(conn.Port))
+}
+
+// ExpandServices replaces each connection whose host is a service with one
+// connection per instance, named "<name>/<host>", so a command can run on
+// every node. The instances keep the service's ID; TargetKey tells them
+// apart. Services that fail to resolve are kept as they are and report
+// the error when connecting.
+func ExpandServices(ctx context.Context, connections []model.Connection) []model.Connection {
+	expanded := make([]model.Connection, 0, len(connections))
+	for _, conn := range connections {
+		if !discovery.IsService(conn.Host) {
+			expanded = append(expanded, conn)
+			continue
+		}
+		instances, err := discovery.DefaultResolver.Resolve(ctx, conn.Host)
+		if err != nil {
+			expanded = append(expanded, conn)
+			continue
+		}
+		for _, inst := range instances {
+			node := conn
+			node.Name = conn.Name + "/" + inst.Host
+			node.Host = inst.Host
+			node.Port = inst.Port
+			node.Addresses = nil
+			expanded = append(expanded, node)
+		}
+	}
+	return expanded
+}
@@ -0,0 +1,77 @@
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gossh/internal/discovery"
+	"gossh/internal/model"
+)
+
+// fakeConsul serves the instances of the "web" service from the default
+// resolver for the duration of the test
+func fakeConsul(t *testing.T, ports ...int) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/v1/health/service/web" {
+			http.NotFound(w, req)
+			return
+		}
+		body := "["
+		for i, port := range ports {
+			if i > 0 {
+				body += ","
+			}
+			body += fmt.Sprintf(`{"Node": {"Address": "127.0.0.1"}, "Service": {"Port": %d}}`, port)
+		}
+		_, _ = w.Write([]byte(body + "]"))
+	}))
+	t.Cleanup(server.Close)
+
+	old := discovery.DefaultResolver.ConsulAddr
+	discovery.DefaultResolver.ConsulAddr = server.URL
+	t.Cleanup(func() { discovery.DefaultResolver.ConsulAddr = old })
+}
+
+func TestConnectService(t *testing.T) {
+	conn := startExecServer(t)
+	// The first instance is down, so the second one is used
+	fakeConsul(t, closedPort(t), conn.Port)
+	conn.Host = "consul:web"
+
+	client := NewClient(conn)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer client.Close()
+	if got := client.Address(); got != "127.0.0.1" {
+		t.Errorf("Address() = %q, want the instance's address", got)
+	}
+}
+
+func TestExpandServices(t *testing.T) {
+	fakeConsul(t, 2201, 2202)
+
+	connections := []model.Connection{
+		{Name: "web", Host: "consul:web", Port: 22, User: "deploy", Addresses: []string{"10.0.0.9"}},
+		{Name: "db", Host: "10.0.0.5", Port: 22},
+		{Name: "gone", Host: "consul:gone", Port: 22},
+	}
+	got := ExpandServices(context.Background(), connections)
+
+	want := []string{"web/127.0.0.1:2201", "web/127.0.0.1:2202", "db:22", "gone:22"}
+	if len(got) != len(want) {
+		t.Fatalf("ExpandServices() returned %d connections, want %d", len(got), len(want))
+	}
+	for i, c := range got {
+		if name := fmt.Sprintf("%s:%d", c.Name, c.Port); name != want[i] {
+			t.Errorf("connection %d = %s, want %s", i, name, want[i])
+		}
+	}
+	if got[0].User != "deploy" || len(got[0].Addresses) != 0 {
+		t.Errorf("instances should keep the connection's settings but not its addresses: %+v", got[0])
+	}
+}
@@ -21,11 +21,22 @@ func (s *Session) Shell() error {
 	return s.session.Shell()
 }
 
+// Start runs a command on the remote host without waiting for it
+func (s *Session) Start(cmd string) error {
+	return s.session.Start(cmd)
+}
+
 // Wait waits for the session to finish
 func (s *Session) Wait() error {
 	return s.session.Wait()
 }
 
+// Setenv asks the server to set an environment variable for the session.
+// sshd refuses variables not listed in its AcceptEnv.
+func (s *Session) Setenv(name, value string) error {
+	return s.session.Setenv(name, value)
+}
+
 // RequestPty requests a pseudo-terminal
 func (s *Session) RequestPty(term string, height, width int) error {
 	modes := ssh.TerminalModes{
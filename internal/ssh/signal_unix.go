@@ -11,7 +11,7 @@ import (
 )
 
 // setupWindowResize sets up window resize signal handling on Unix systems
-func setupWindowResize(session *Session, fd int) func() {
+func setupWindowResize(session remoteShell, fd int) func() {
 	sigwinch := make(chan os.Signal, 1)
 	signal.Notify(sigwinch, syscall.SIGWINCH)
 
@@ -28,3 +28,9 @@ func setupWindowResize(session *Session, fd int) func() {
 		close(sigwinch)
 	}
 }
+
+// SuspendProcess stops gossh like Ctrl-Z would and returns once the shell
+// continues it with fg
+func SuspendProcess() {
+	_ = syscall.Kill(os.Getpid(), syscall.SIGTSTP)
+}
@@ -3,7 +3,10 @@
 package ssh
 
 // setupWindowResize is a no-op on Windows as SIGWINCH is not available
-func setupWindowResize(session *Session, fd int) func() {
+func setupWindowResize(session remoteShell, fd int) func() {
 	// Windows doesn't support SIGWINCH, return empty cleanup function
 	return func() {}
 }
+
+// SuspendProcess is a no-op on Windows, which has no job control
+func SuspendProcess() {}
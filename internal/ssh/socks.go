@@ -0,0 +1,231 @@
+package ssh
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// socksHandshakeTimeout bounds how long a client may take to say where to
+// connect, so idle connections to the proxy don't pile up
+const socksHandshakeTimeout = 30 * time.Second
+
+// errSOCKSCommand is returned for SOCKS requests other than CONNECT, which
+// is all ssh -D supports too
+var errSOCKSCommand = errors.New("only SOCKS CONNECT is supported")
+
+// startDynamicForward starts a SOCKS proxy on the local side (-D). Each
+// connection through it is opened by the server, to wherever the client
+// asks, like ssh -D.
+func (f *Forwarder) startDynamicForward(pf *PortForward) error {
+	listener, localAddr, err := f.listenLocal(pf)
+	if err != nil {
+		return err
+	}
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		defer listener.Close()
+
+		for {
+			select {
+			case <-f.ctx.Done():
+				return
+			default:
+			}
+
+			conn, err := listener.Accept()
+			if err != nil {
+				select {
+				case <-f.ctx.Done():
+					return
+				default:
+					continue
+				}
+			}
+
+			f.wg.Add(1)
+			go func(localConn net.Conn) {
+				defer f.wg.Done()
+				defer localConn.Close()
+				f.serveSOCKS(pf, localConn)
+			}(conn)
+		}
+	}()
+
+	fmt.Fprintf(f.out, "Dynamic forward: SOCKS proxy on %s -> [%s]\n", localAddr, f.conn.Host)
+	return nil
+}
+
+// serveSOCKS reads a SOCKS request from a client and connects it through
+// the server
+func (f *Forwarder) serveSOCKS(pf *PortForward, localConn net.Conn) {
+	_ = localConn.SetDeadline(time.Now().Add(socksHandshakeTimeout))
+	r := bufio.NewReader(localConn)
+	version, target, err := readSOCKSRequest(r, localConn)
+	if err != nil {
+		return
+	}
+
+	remoteConn, err := f.client.Dial("tcp", target)
+	if err != nil {
+		_ = writeSOCKSReply(localConn, version, false)
+		return
+	}
+	defer remoteConn.Close()
+	if err := writeSOCKSReply(localConn, version, true); err != nil {
+		return
+	}
+	_ = localConn.SetDeadline(time.Time{})
+
+	// The client may have sent data right after the request
+	if n := r.Buffered(); n > 0 {
+		buffered, _ := r.Peek(n)
+		if _, err := remoteConn.Write(buffered); err != nil {
+			return
+		}
+		pf.bytes.add(n)
+	}
+	f.copyBidirectional(pf, localConn, remoteConn)
+}
+
+// readSOCKSRequest reads a SOCKS4, SOCKS4a or SOCKS5 CONNECT request and
+// returns the protocol version and the host:port to connect to. SOCKS5
+// clients are told no authentication is needed on w; requests that can't
+// be served are answered with a failure.
+func readSOCKSRequest(r *bufio.Reader, w io.Writer) (byte, string, error) {
+	version, err := r.ReadByte()
+	if err != nil {
+		return 0, "", err
+	}
+	var target string
+	switch version {
+	case 4:
+		target, err = readSOCKS4(r, w)
+	case 5:
+		target, err = readSOCKS5(r, w)
+	default:
+		err = fmt.Errorf("unknown SOCKS version %d", version)
+	}
+	return version, target, err
+}
+
+// readSOCKS4 reads the rest of a SOCKS4 request after the version: command,
+// port, IPv4 address and user ID, followed by a host name for SOCKS4a
+func readSOCKS4(r *bufio.Reader, w io.Writer) (string, error) {
+	var head [7]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return "", err
+	}
+	if _, err := r.ReadString(0); err != nil { // User ID, ignored
+		return "", err
+	}
+	if head[0] != 1 {
+		_ = writeSOCKSReply(w, 4, false)
+		return "", errSOCKSCommand
+	}
+	port := binary.BigEndian.Uint16(head[1:3])
+	host := net.IP(head[3:7]).String()
+
+	// 0.0.0.x with x other than 0 means the host name follows (SOCKS4a)
+	if head[3] == 0 && head[4] == 0 && head[5] == 0 && head[6] != 0 {
+		name, err := r.ReadString(0)
+		if err != nil {
+			return "", err
+		}
+		host = name[:len(name)-1]
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(port))), nil
+}
+
+// readSOCKS5 reads the rest of a SOCKS5 request after the version: the
+// authentication methods, answered with "none", then the request itself
+func readSOCKS5(r *bufio.Reader, w io.Writer) (string, error) {
+	count, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	methods := make([]byte, count)
+	if _, err := io.ReadFull(r, methods); err != nil {
+		return "", err
+	}
+	noAuth := false
+	for _, m := range methods {
+		noAuth = noAuth || m == 0
+	}
+	if !noAuth {
+		_, _ = w.Write([]byte{5, 0xff})
+		return "", errors.New("SOCKS client requires authentication")
+	}
+	if _, err := w.Write([]byte{5, 0}); err != nil {
+		return "", err
+	}
+
+	var head [4]byte // Version, command, reserved, address type
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return "", err
+	}
+	if head[0] != 5 {
+		return "", fmt.Errorf("unknown SOCKS version %d", head[0])
+	}
+
+	var host string
+	switch head[3] {
+	case 1, 4: // IPv4, IPv6
+		ip := make(net.IP, 4)
+		if head[3] == 4 {
+			ip = make(net.IP, 16)
+		}
+		if _, err := io.ReadFull(r, ip); err != nil {
+			return "", err
+		}
+		host = ip.String()
+	case 3: // Host name
+		n, err := r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		name := make([]byte, n)
+		if _, err := io.ReadFull(r, name); err != nil {
+			return "", err
+		}
+		host = string(name)
+	default:
+		_, _ = w.Write([]byte{5, 8, 0, 1, 0, 0, 0, 0, 0, 0}) // Address type not supported
+		return "", fmt.Errorf("unknown SOCKS address type %d", head[3])
+	}
+	var port [2]byte
+	if _, err := io.ReadFull(r, port[:]); err != nil {
+		return "", err
+	}
+	if head[1] != 1 {
+		_, _ = w.Write([]byte{5, 7, 0, 1, 0, 0, 0, 0, 0, 0}) // Command not supported
+		return "", errSOCKSCommand
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port[:])))), nil
+}
+
+// writeSOCKSReply tells a client whether its connection was made. The
+// bound address is left empty, clients don't use it for CONNECT.
+func writeSOCKSReply(w io.Writer, version byte, ok bool) error {
+	var reply []byte
+	if version == 4 {
+		reply = []byte{0, 0x5b, 0, 0, 0, 0, 0, 0}
+		if ok {
+			reply[1] = 0x5a
+		}
+	} else {
+		reply = []byte{5, 1, 0, 1, 0, 0, 0, 0, 0, 0}
+		if ok {
+			reply[1] = 0
+		}
+	}
+	_, err := w.Write(reply)
+	return err
+}
@@ -0,0 +1,127 @@
+package ssh
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+)
+
+func TestParseDynamicForward(t *testing.T) {
+	tests := []struct {
+		spec    string
+		host    string
+		port    int
+		wantErr bool
+	}{
+		{"1080", "localhost", 1080, false},
+		{"127.0.0.1:1080", "127.0.0.1", 1080, false},
+		{"socks", "", 0, true},
+		{":1080", "", 0, true},
+		{"70000", "", 0, true},
+	}
+	for _, tt := range tests {
+		pf, err := ParsePortForward(ForwardDynamic, tt.spec)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParsePortForward(-D %q) should fail", tt.spec)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParsePortForward(-D %q) failed: %v", tt.spec, err)
+			continue
+		}
+		if pf.LocalHost != tt.host || pf.LocalPort != tt.port {
+			t.Errorf("ParsePortForward(-D %q) = %s:%d, want %s:%d", tt.spec, pf.LocalHost, pf.LocalPort, tt.host, tt.port)
+		}
+	}
+}
+
+func TestDynamicForward(t *testing.T) {
+	// An echo server for the proxy to reach through the SSH server
+	echo, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer echo.Close()
+	go func() {
+		for {
+			conn, err := echo.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+	echoPort := uint16(echo.Addr().(*net.TCPAddr).Port)
+
+	f := NewForwarder(startExecServer(t))
+	f.SetOutput(io.Discard)
+	if err := f.Connect(); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer f.Stop()
+	pf, err := ParsePortForward(ForwardDynamic, "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ParsePortForward failed: %v", err)
+	}
+	if err := f.StartForward(pf); err != nil {
+		t.Fatalf("StartForward failed: %v", err)
+	}
+	proxy := f.listeners[0].Addr().String()
+
+	port := binary.BigEndian.AppendUint16(nil, echoPort)
+	requests := map[string]struct {
+		request []byte
+		reply   int // Length of the replies before the echo
+	}{
+		"SOCKS5":  {append(append([]byte{5, 1, 0, 5, 1, 0, 3, 9}, "127.0.0.1"...), port...), 2 + 10},
+		"SOCKS4a": {append(append(append([]byte{4, 1}, port...), 0, 0, 0, 1, 0), append([]byte("127.0.0.1"), 0)...), 8},
+	}
+	for name, tt := range requests {
+		conn, err := net.Dial("tcp", proxy)
+		if err != nil {
+			t.Fatalf("%s: failed to connect to the proxy: %v", name, err)
+		}
+		// The payload is sent with the request, before any reply
+		if _, err := conn.Write(append(tt.request, "ping"...)); err != nil {
+			t.Fatalf("%s: write failed: %v", name, err)
+		}
+		got := make([]byte, tt.reply+4)
+		if _, err := io.ReadFull(conn, got); err != nil {
+			t.Fatalf("%s: read failed: %v", name, err)
+		}
+		conn.Close()
+
+		if name == "SOCKS5" && (got[1] != 0 || got[3] != 0) || name == "SOCKS4a" && got[1] != 0x5a {
+			t.Errorf("%s: proxy refused the connection: % x", name, got[:tt.reply])
+		}
+		if string(got[tt.reply:]) != "ping" {
+			t.Errorf("%s: echo = %q, want ping", name, got[tt.reply:])
+		}
+	}
+
+	if stats := pf.Stats(); stats.Total != 2 || stats.Bytes < 16 {
+		t.Errorf("Stats() = %+v, want 2 connections and the echoes counted", stats)
+	}
+}
+
+func TestSOCKSRequestOnlyConnect(t *testing.T) {
+	// SOCKS5 BIND to 127.0.0.1:80
+	request := []byte{5, 1, 0, 5, 2, 0, 1, 127, 0, 0, 1, 0, 80}
+	var reply bytes.Buffer
+	_, _, err := readSOCKSRequest(bufio.NewReader(bytes.NewReader(request)), &reply)
+	if !errors.Is(err, errSOCKSCommand) {
+		t.Fatalf("readSOCKSRequest(BIND) = %v, want %v", err, errSOCKSCommand)
+	}
+	if got := reply.Bytes(); len(got) != 12 || got[3] != 7 {
+		t.Errorf("reply = % x, want no authentication then command not supported", got)
+	}
+}
@@ -0,0 +1,91 @@
+package ssh
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"gossh/internal/model"
+)
+
+// TailLine is a line of output from one host, or the error that ended its
+// stream when Err is set
+type TailLine struct {
+	Connection model.Connection
+	Text       string
+	Err        error
+}
+
+// TailCommand returns the command that follows path on a host, starting
+// with its last lines and surviving log rotation
+func TailCommand(path string, lines int) string {
+	return fmt.Sprintf("tail -n %d -F %s", lines, model.ShellQuote(path))
+}
+
+// Tail runs command on every connection at once and sends each line it
+// prints, stdout and stderr alike, to out. It returns once ctx is done or
+// every command has ended, and closes out, which the caller must drain
+// until then.
+func Tail(ctx context.Context, connections []model.Connection, command string, out chan<- TailLine) {
+	var wg sync.WaitGroup
+	for _, conn := range connections {
+		wg.Add(1)
+		go func(c model.Connection) {
+			defer wg.Done()
+			if err := tailOne(ctx, c, command, out); err != nil && ctx.Err() == nil {
+				out <- TailLine{Connection: c, Err: err}
+			}
+		}(conn)
+	}
+	wg.Wait()
+	close(out)
+}
+
+// tailOne streams command's output from a single connection
+func tailOne(ctx context.Context, conn model.Connection, command string, out chan<- TailLine) error {
+	client := NewClient(conn)
+	if err := client.Connect(); err != nil {
+		return fmt.Errorf("connection error: %w", err)
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return fmt.Errorf("session error: %w", err)
+	}
+	defer session.Close()
+
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := session.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := session.Start(command); err != nil {
+		return err
+	}
+
+	// Closing the session is the only way to stop a command that never
+	// exits on its own, like tail -F
+	stop := context.AfterFunc(ctx, func() { session.Close() })
+	defer stop()
+
+	var readers sync.WaitGroup
+	for _, r := range []io.Reader{stdout, stderr} {
+		readers.Add(1)
+		go func(r io.Reader) {
+			defer readers.Done()
+			scanner := bufio.NewScanner(r)
+			scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+			for scanner.Scan() {
+				out <- TailLine{Connection: conn, Text: scanner.Text()}
+			}
+		}(r)
+	}
+	readers.Wait()
+	return session.Wait()
+}
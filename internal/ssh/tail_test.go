@@ -0,0 +1,55 @@
+package ssh
+
+import (
+	"context"
+	"testing"
+
+	"gossh/internal/model"
+)
+
+func TestTailCommand(t *testing.T) {
+	if got := TailCommand("/var/log/app's.log", 20); got != `tail -n 20 -F '/var/log/app'\''s.log'` {
+		t.Errorf("TailCommand() = %q", got)
+	}
+}
+
+func TestTail(t *testing.T) {
+	web := startExecServer(t)
+	web.Name = "web"
+	down := web
+	down.Name = "down"
+	down.Port = closedPort(t)
+
+	out := make(chan TailLine)
+	go Tail(context.Background(), []model.Connection{web, down}, `echo first\nsecond`, out)
+
+	var lines []string
+	var failed []string
+	for line := range out {
+		if line.Err != nil {
+			failed = append(failed, line.Connection.Name)
+			continue
+		}
+		lines = append(lines, line.Connection.Name+": "+line.Text)
+	}
+	if len(lines) != 2 || lines[0] != "web: first" || lines[1] != "web: second" {
+		t.Errorf("Tail() lines = %q", lines)
+	}
+	if len(failed) != 1 || failed[0] != "down" {
+		t.Errorf("Tail() errors from %q, want only down", failed)
+	}
+}
+
+func TestTailCancel(t *testing.T) {
+	conn := startExecServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	out := make(chan TailLine)
+	go Tail(ctx, []model.Connection{conn}, "echo never", out)
+	for line := range out {
+		if line.Err != nil {
+			t.Errorf("a cancelled tail should not report an error: %v", line.Err)
+		}
+	}
+}
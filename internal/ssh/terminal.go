@@ -1,32 +1,70 @@
 package ssh
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/muesli/cancelreader"
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/term"
 	"gossh/internal/model"
 )
 
+// ErrSuspended is returned by Run and Resume when the user typed ~Z. The
+// session keeps running in the background until Resume or Close.
+var ErrSuspended = errors.New("session suspended")
+
+// ErrDisconnected is returned when the user dropped the connection with ~.
+var ErrDisconnected = errors.New("disconnected by escape sequence")
+
 // Terminal handles interactive SSH terminal sessions
 type Terminal struct {
 	conn            model.Connection
 	client          *Client
 	startupTimeout  time.Duration
 	hostKeyCallback ssh.HostKeyCallback
+	mirror          io.Writer
+	scrollback      *Scrollback
+	address         string // Telnet only; SSH sessions ask the client
+
+	// Set while a session is running, including while it is suspended
+	session      remoteShell
+	stdin        io.WriteCloser
+	stdout       *detachableWriter
+	stderr       *detachableWriter
+	done         chan error
+	keepalive    *Keepalive
+	escape       *EscapeFilter
+	forwarder    *Forwarder
+	disconnected atomic.Bool
+	facts        atomic.Pointer[model.HostFacts]
+}
+
+// remoteShell is the remote end of an interactive session: an SSH session
+// or, for telnet connections, the telnet connection itself
+type remoteShell interface {
+	WindowChange(height, width int) error
+	Close() error
 }
 
 // NewTerminal creates a new terminal for a connection
 func NewTerminal(conn model.Connection) *Terminal {
-	return &Terminal{
+	t := &Terminal{
 		conn:           conn,
 		client:         NewClient(conn),
 		startupTimeout: 5 * time.Second,
 	}
+	if scrollbackSize > 0 {
+		t.scrollback = NewScrollback(scrollbackSize)
+	}
+	return t
 }
 
 // SetHostKeyCallback sets the host key callback for verification
@@ -40,26 +78,66 @@ func (t *Terminal) SetStartupTimeout(timeout time.Duration) {
 	t.startupTimeout = timeout
 }
 
-// Run starts an interactive terminal session
+// SetMirror copies all session output to w, e.g. a Mirror for read-only sharing
+func (t *Terminal) SetMirror(w io.Writer) {
+	t.mirror = w
+}
+
+// SetScrollback keeps the last size bytes of session output, for
+// Scrollback, whatever SetScrollbackSize says
+func (t *Terminal) SetScrollback(size int) {
+	t.scrollback = NewScrollback(size)
+}
+
+// Scrollback returns the most recent output of the session, nil when none
+// is kept
+func (t *Terminal) Scrollback() *Scrollback {
+	return t.scrollback
+}
+
+// output returns w, teed into the scrollback and the mirror when set
+func (t *Terminal) output(w io.Writer) io.Writer {
+	writers := []io.Writer{w}
+	if t.scrollback != nil {
+		writers = append(writers, t.scrollback)
+	}
+	if t.mirror != nil {
+		writers = append(writers, t.mirror)
+	}
+	if len(writers) == 1 {
+		return w
+	}
+	return io.MultiWriter(writers...)
+}
+
+// Run starts an interactive terminal session. Keyboard input is scanned
+// for escape sequences (see EscapeFilter); after ~Z it returns
+// ErrSuspended and the session can be picked up again with Resume.
 func (t *Terminal) Run() error {
+	// Set up terminal
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("stdin is not a terminal")
+	}
+	if t.conn.IsTelnet() {
+		return t.runTelnet()
+	}
+
 	// Connect to SSH server
 	if err := t.client.Connect(); err != nil {
 		return err
 	}
-	defer t.client.Close()
+	if t.conn.ShowBanner {
+		printBanner(os.Stderr, t.client.Banner())
+	}
 
 	// Create session
 	session, err := t.client.NewSession()
 	if err != nil {
+		t.client.Close()
 		return fmt.Errorf("failed to create session: %w", err)
 	}
-	defer session.Close()
-
-	// Set up terminal
-	fd := int(os.Stdin.Fd())
-	if !term.IsTerminal(fd) {
-		return fmt.Errorf("stdin is not a terminal")
-	}
+	t.session = session
 
 	// Get terminal size (use defaults if unavailable)
 	width, height := 80, 24
@@ -68,74 +146,451 @@ func (t *Terminal) Run() error {
 	}
 
 	// Request PTY
-	termType := os.Getenv("TERM")
-	if termType == "" {
-		termType = "xterm-256color"
+	if err := t.requestPty(session, height, width); err != nil {
+		return t.finish(fmt.Errorf("failed to request pty: %w", err))
 	}
-	if err := session.RequestPty(termType, height, width); err != nil {
-		return fmt.Errorf("failed to request pty: %w", err)
+
+	// Keyboard input goes through the escape filter, output through
+	// writers that can be detached while the session is suspended
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return t.finish(fmt.Errorf("failed to open stdin: %w", err))
+	}
+	t.stdin = stdin
+	t.stdout = newDetachableWriter(t.output(os.Stdout))
+	t.stderr = newDetachableWriter(t.output(os.Stderr))
+	session.SetStdout(t.stdout)
+	session.SetStderr(t.stderr)
+	t.escape = NewEscapeFilter()
+
+	// Start shell
+	if err := t.startShell(session); err != nil {
+		return t.finish(fmt.Errorf("failed to start shell: %w", err))
+	}
+
+	// Start keepalive to detect dead connections
+	t.keepalive = NewKeepalive(t.client.Conn())
+	t.keepalive.Start()
+
+	// System facts are gathered on a second channel while the shell runs
+	go t.gatherFacts(t.client.client)
+
+	t.done = make(chan error, 1)
+	go func() { t.done <- session.Wait() }()
+
+	// Execute startup command if configured
+	if t.conn.StartupCommand != "" {
+		go t.executeStartupCommand(stdin)
+	}
+
+	return t.attach()
+}
+
+// defaultTerm and defaultLocale are requested for connections that don't
+// set their own, before falling back to the local TERM and no locale. The
+// TUI changes them while sessions start, so they are read under the lock.
+var (
+	terminalDefaultsMu         sync.RWMutex
+	defaultTerm, defaultLocale string
+)
+
+// SetTerminalDefaults sets the TERM and locale requested for connections
+// that don't set their own, e.g. from the term and locale settings. Empty
+// values use the local TERM and leave the locale alone.
+func SetTerminalDefaults(term, locale string) {
+	terminalDefaultsMu.Lock()
+	defer terminalDefaultsMu.Unlock()
+	defaultTerm, defaultLocale = term, locale
+}
+
+// terminalDefaults returns the TERM and locale set with SetTerminalDefaults
+func terminalDefaults() (term, locale string) {
+	terminalDefaultsMu.RLock()
+	defer terminalDefaultsMu.RUnlock()
+	return defaultTerm, defaultLocale
+}
+
+// termType returns the TERM to request: the connection's override, the
+// term setting, or the local one
+func (t *Terminal) termType() string {
+	if t.conn.Term != "" {
+		return t.conn.Term
+	}
+	if term, _ := terminalDefaults(); term != "" {
+		return term
 	}
+	if termType := os.Getenv("TERM"); termType != "" {
+		return termType
+	}
+	return "xterm-256color"
+}
+
+// requestPty asks for the connection's locale, or the locale setting, if
+// either is set, then requests a pseudo-terminal. A server refusing the
+// locale variables is ignored, as OpenSSH does with SendEnv.
+func (t *Terminal) requestPty(session *Session, height, width int) error {
+	conn := t.conn
+	if conn.Locale == "" {
+		_, conn.Locale = terminalDefaults()
+	}
+	for name, value := range conn.LocaleEnv() {
+		_ = session.Setenv(name, value)
+	}
+	return session.RequestPty(t.termType(), height, width)
+}
+
+// gatherFacts runs FactsCommand on client, keeping the facts for Facts
+func (t *Terminal) gatherFacts(client *ssh.Client) {
+	if facts, err := gatherFacts(client); err == nil {
+		t.facts.Store(&facts)
+	}
+}
+
+// Facts returns the system facts gathered while the session ran, or nil
+// when they aren't in yet or the host has none to give
+func (t *Terminal) Facts() *model.HostFacts {
+	return t.facts.Load()
+}
+
+// Resume reattaches a session suspended with ~Z to the terminal
+func (t *Terminal) Resume() error {
+	if t.session == nil {
+		return errors.New("no suspended session")
+	}
+	t.escape.Reset()
+	return t.attach()
+}
+
+// Address returns the address the host was reached at, empty before the
+// session connects
+func (t *Terminal) Address() string {
+	if t.address != "" {
+		return t.address
+	}
+	return t.client.Address()
+}
+
+// Suspended reports whether a session is waiting to be resumed
+func (t *Terminal) Suspended() bool {
+	return t.session != nil
+}
+
+// attach connects the local terminal to the session until the session
+// ends or the user suspends it
+func (t *Terminal) attach() error {
+	fd := int(os.Stdin.Fd())
 
 	// Set raw mode
 	oldState, err := term.MakeRaw(fd)
 	if err != nil {
-		return fmt.Errorf("failed to set raw mode: %w", err)
+		return t.finish(fmt.Errorf("failed to set raw mode: %w", err))
 	}
-	defer func() { _ = term.Restore(fd, oldState) }()
 
-	// Connect stdin/stdout/stderr
-	session.SetStdin(os.Stdin)
-	session.SetStdout(os.Stdout)
-	session.SetStderr(os.Stderr)
+	// The window may have been resized while the session was suspended
+	if w, h, err := term.GetSize(fd); err == nil {
+		_ = t.session.WindowChange(h, w)
+	}
 
 	// Handle window resize (platform-specific)
-	cleanup := setupWindowResize(session, fd)
-	defer cleanup()
+	cleanup := setupWindowResize(t.session, fd)
 
-	// Start shell
-	if err := session.Shell(); err != nil {
-		return fmt.Errorf("failed to start shell: %w", err)
+	// A cancelable reader lets us stop reading stdin when the session ends
+	// or is suspended, instead of swallowing the next keystroke
+	in, err := cancelreader.NewReader(os.Stdin)
+	if err != nil {
+		cleanup()
+		_ = term.Restore(fd, oldState)
+		return t.finish(fmt.Errorf("failed to read stdin: %w", err))
 	}
 
-	// Start keepalive to detect dead connections
-	ka := NewKeepalive(t.client.Conn())
-	ka.Start()
-	defer ka.Stop()
+	t.stdout.Attach()
+	t.stderr.Attach()
 
-	// Execute startup command if configured
-	if t.conn.StartupCommand != "" {
-		go t.executeStartupCommand(session)
-	}
+	suspend := make(chan struct{})
+	pumped := make(chan struct{})
+	go func() {
+		t.pump(in, suspend)
+		close(pumped)
+	}()
 
 	// Wait for session to end
-	waitErr := session.Wait()
+	var waitErr error
+	suspended := false
+	select {
+	case waitErr = <-t.done:
+	case <-suspend:
+		suspended = true
+	}
+
+	t.stdout.Detach()
+	t.stderr.Detach()
+	in.Cancel()
+	<-pumped
+	_ = in.Close()
+	cleanup()
+	_ = term.Restore(fd, oldState)
 
 	// Ensure cursor moves to a new line after session ends
 	_, _ = os.Stdout.Write([]byte("\r\n"))
 
+	if suspended {
+		return ErrSuspended
+	}
+	return t.finish(waitErr)
+}
+
+// pump copies keyboard input to the session, acting on escape sequences
+func (t *Terminal) pump(in io.Reader, suspend chan<- struct{}) {
+	var cmd *commandLine
+	buf := make([]byte, 1024)
+
+	for {
+		n, err := in.Read(buf)
+		data := buf[:n]
+
+		for len(data) > 0 {
+			if cmd != nil {
+				var line string
+				var done bool
+				if data, line, done = cmd.feed(data); done {
+					cmd = nil
+					t.runEscapeCommand(line)
+				}
+				continue
+			}
+
+			out, action, rest := t.escape.Filter(data)
+			if len(out) > 0 {
+				if _, err := t.stdin.Write(out); err != nil {
+					return
+				}
+			}
+			data = rest
+
+			switch action {
+			case EscapeDisconnect:
+				t.disconnected.Store(true)
+				t.printLocal("Connection to %s closed.\r\n", t.conn.Host)
+				// Closing the connection makes Wait return even if the
+				// server stopped responding
+				_ = t.client.Kill()
+				if t.conn.IsTelnet() {
+					_ = t.session.Close()
+				}
+				return
+			case EscapeSuspend:
+				close(suspend)
+				return
+			case EscapeHelp:
+				t.printLocal("\r\n%s", escapeHelp)
+			case EscapeCommand:
+				if t.conn.IsTelnet() {
+					t.printLocal("\r\nPort forwarding is not available over telnet.\r\n")
+					continue
+				}
+				cmd = &commandLine{echo: os.Stdout}
+				t.printLocal("\r\nssh> ")
+			}
+		}
+
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				_ = t.stdin.Close()
+			}
+			return
+		}
+	}
+}
+
+// runEscapeCommand runs a line typed after ~C. Like OpenSSH it accepts
+// -L, -R and -D forward specs.
+func (t *Terminal) runEscapeCommand(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+
+	var fwdType ForwardType
+	switch {
+	case strings.HasPrefix(line, "-L"):
+		fwdType = ForwardLocal
+	case strings.HasPrefix(line, "-R"):
+		fwdType = ForwardRemote
+	case strings.HasPrefix(line, "-D"):
+		fwdType = ForwardDynamic
+	default:
+		t.printLocal("Commands:\r\n" +
+			"      -L[bind_address:]port:host:hostport    Request local forward\r\n" +
+			"      -R[bind_address:]port:host:hostport    Request remote forward\r\n" +
+			"      -D[bind_address:]port                  Request dynamic forward\r\n")
+		return
+	}
+
+	pf, err := ParsePortForward(fwdType, strings.TrimSpace(line[2:]))
+	if err != nil {
+		t.printLocal("%v\r\n", err)
+		return
+	}
+
+	if t.forwarder == nil {
+		// Shares the session's connection through DefaultPool
+		f := NewForwarder(t.conn)
+		f.SetHostKeyCallback(t.hostKeyCallback)
+		f.SetOutput(crlfWriter{os.Stdout})
+		if err := f.Connect(); err != nil {
+			t.printLocal("%v\r\n", err)
+			return
+		}
+		t.forwarder = f
+	}
+	if err := t.forwarder.StartForward(pf); err != nil {
+		t.printLocal("%v\r\n", err)
+	}
+}
+
+// printLocal writes gossh's own messages straight to the terminal, bypassing
+// the mirror
+func (t *Terminal) printLocal(format string, args ...any) {
+	fmt.Fprintf(os.Stdout, format, args...)
+}
+
+// finish tears down the session and returns the error to report for it
+func (t *Terminal) finish(waitErr error) error {
+	if t.keepalive != nil {
+		t.keepalive.Stop()
+	}
+	if t.session != nil {
+		_ = t.session.Close()
+		t.session = nil
+	}
+	_ = t.client.Close()
+	if t.forwarder != nil {
+		t.forwarder.Stop()
+		t.forwarder = nil
+	}
+
+	if t.disconnected.Load() {
+		return ErrDisconnected
+	}
 	// If keepalive detected a dead connection, report that instead
+	if t.keepalive != nil {
+		if deadErr := t.keepalive.DeadError(); deadErr != nil {
+			return fmt.Errorf("connection lost: %w", deadErr)
+		}
+	}
+	return waitErr
+}
+
+// RunCommand runs a single remote command instead of a shell, like
+// `ssh host cmd`. A PTY is only requested when stdin is a terminal, so the
+// command can also be used in pipes. A non-zero remote exit status is
+// returned as an *ssh.ExitError; see ExitStatus.
+func (t *Terminal) RunCommand(command string) error {
+	if err := t.client.Connect(); err != nil {
+		return err
+	}
+	defer t.client.Close()
+
+	session, err := t.client.NewSession()
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	defer session.Close()
+
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		width, height := 80, 24
+		if w, h, err := term.GetSize(fd); err == nil {
+			width, height = w, h
+		}
+		if err := t.requestPty(session, height, width); err != nil {
+			return fmt.Errorf("failed to request pty: %w", err)
+		}
+
+		oldState, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("failed to set raw mode: %w", err)
+		}
+		defer func() { _ = term.Restore(fd, oldState) }()
+
+		cleanup := setupWindowResize(session, fd)
+		defer cleanup()
+	}
+
+	// Copy stdin ourselves so Wait does not block on a stdin that never
+	// reaches EOF once the command has exited
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to open stdin: %w", err)
+	}
+	go func() {
+		_, _ = io.Copy(stdin, os.Stdin)
+		_ = stdin.Close()
+	}()
+	session.SetStdout(t.output(os.Stdout))
+	session.SetStderr(t.output(os.Stderr))
+
+	if err := session.Start(command); err != nil {
+		return fmt.Errorf("failed to start command: %w", err)
+	}
+
+	ka := NewKeepalive(t.client.Conn())
+	ka.Start()
+	defer ka.Stop()
+
+	waitErr := session.Wait()
+
 	if deadErr := ka.DeadError(); deadErr != nil {
 		return fmt.Errorf("connection lost: %w", deadErr)
 	}
 	return waitErr
 }
 
+// ExitStatus extracts the remote exit status from a session error. ok is
+// false when err is not an exit status, e.g. a connection failure.
+func ExitStatus(err error) (code int, ok bool) {
+	if err == nil {
+		return 0, true
+	}
+	var exitErr *ssh.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitStatus(), true
+	}
+	return 0, false
+}
+
 // executeStartupCommand sends the startup command to the shell
-func (t *Terminal) executeStartupCommand(session *Session) {
+func (t *Terminal) executeStartupCommand(stdin io.Writer) {
 	// Wait a moment for the shell to initialize
 	time.Sleep(500 * time.Millisecond)
 
 	// Send the command followed by newline
+	// Note: This writes through the PTY which simulates user input
 	cmd := strings.TrimSpace(t.conn.StartupCommand)
 	if cmd != "" {
-		// Write the command to stdin via the session
-		// Note: This writes through the PTY which simulates user input
-		stdinPipe, err := session.StdinPipe()
-		if err != nil {
-			return
-		}
-		_, _ = stdinPipe.Write([]byte(cmd + "\n"))
+		_, _ = stdin.Write([]byte(cmd + "\n"))
+	}
+}
+
+// quietShell starts the user's login shell as a command. sshd only prints
+// the MOTD and last login notice for sessions without one.
+const quietShell = `exec "${SHELL:-/bin/sh}" -l`
+
+// startShell starts the remote shell, without the login noise if the
+// connection asks for a quiet login
+func (t *Terminal) startShell(session *Session) error {
+	if t.conn.QuietLogin {
+		return session.Start(quietShell)
+	}
+	return session.Shell()
+}
+
+// printBanner writes the server's pre-login banner, ending with a newline
+func printBanner(w io.Writer, banner string) {
+	if banner == "" {
+		return
 	}
+	fmt.Fprintln(w, strings.TrimRight(banner, "\r\n"))
 }
 
 // RunWithIO runs an interactive session with custom IO
@@ -151,19 +606,22 @@ func (t *Terminal) RunWithIO(stdin io.Reader, stdout, stderr io.Writer, width, h
 	}
 	defer session.Close()
 
-	termType := os.Getenv("TERM")
-	if termType == "" {
-		termType = "xterm-256color"
-	}
-	if err := session.RequestPty(termType, height, width); err != nil {
+	if err := t.requestPty(session, height, width); err != nil {
 		return fmt.Errorf("failed to request pty: %w", err)
 	}
 
-	session.SetStdin(stdin)
-	session.SetStdout(stdout)
-	session.SetStderr(stderr)
-
-	if err := session.Shell(); err != nil {
+	stdinPipe, err := session.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to open stdin: %w", err)
+	}
+	go func() {
+		_, _ = io.Copy(stdinPipe, stdin)
+		_ = stdinPipe.Close()
+	}()
+	session.SetStdout(t.output(stdout))
+	session.SetStderr(t.output(stderr))
+
+	if err := t.startShell(session); err != nil {
 		return fmt.Errorf("failed to start shell: %w", err)
 	}
 
@@ -174,7 +632,7 @@ func (t *Terminal) RunWithIO(stdin io.Reader, stdout, stderr io.Writer, width, h
 
 	// Execute startup command if configured
 	if t.conn.StartupCommand != "" {
-		go t.executeStartupCommand(session)
+		go t.executeStartupCommand(stdinPipe)
 	}
 
 	waitErr := session.Wait()
@@ -188,10 +646,75 @@ func (t *Terminal) RunWithIO(stdin io.Reader, stdout, stderr io.Writer, width, h
 	return waitErr
 }
 
-// Close closes the terminal connection
+// Close closes the terminal connection, ending a suspended session
 func (t *Terminal) Close() error {
+	if t.session != nil {
+		_ = t.finish(nil)
+		return nil
+	}
 	if t.client != nil {
 		return t.client.Close()
 	}
 	return nil
 }
+
+// detachBuffer is how much output a suspended session keeps for resume
+const detachBuffer = 64 * 1024
+
+// detachableWriter passes output through while attached and keeps the most
+// recent output while detached, replaying it on Attach
+type detachableWriter struct {
+	mu       sync.Mutex
+	w        io.Writer
+	attached bool
+	buf      []byte
+}
+
+func newDetachableWriter(w io.Writer) *detachableWriter {
+	return &detachableWriter{w: w}
+}
+
+func (d *detachableWriter) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.attached {
+		return d.w.Write(p)
+	}
+	d.buf = append(d.buf, p...)
+	if len(d.buf) > detachBuffer {
+		d.buf = d.buf[len(d.buf)-detachBuffer:]
+	}
+	return len(p), nil
+}
+
+// Attach replays buffered output and resumes passing output through
+func (d *detachableWriter) Attach() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.buf) > 0 {
+		_, _ = d.w.Write(d.buf)
+		d.buf = nil
+	}
+	d.attached = true
+}
+
+// Detach starts buffering output
+func (d *detachableWriter) Detach() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.attached = false
+}
+
+// crlfWriter turns \n into \r\n for output written while in raw mode
+type crlfWriter struct {
+	w io.Writer
+}
+
+func (c crlfWriter) Write(p []byte) (int, error) {
+	if _, err := c.w.Write(bytes.ReplaceAll(p, []byte("\n"), []byte("\r\n"))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
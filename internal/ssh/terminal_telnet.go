@@ -0,0 +1,56 @@
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+
+	"gossh/internal/telnet"
+)
+
+// runTelnet runs an interactive session over telnet. It shares the rest of
+// the terminal plumbing with SSH sessions: escape sequences, suspend and
+// resume, window size updates and mirroring.
+func (t *Terminal) runTelnet() error {
+	dialer, err := newDialer(t.conn.BindAddress, connectTimeout())
+	if err != nil {
+		return err
+	}
+	addr := net.JoinHostPort(t.conn.Host, strconv.Itoa(t.conn.Port))
+	addrs, _, err := dialTargets(t.conn)
+	if err != nil {
+		return err
+	}
+	netConn, used, err := dialFirst(dialer, addrs)
+	if err != nil {
+		return fmt.Errorf("failed to dial %s: %w", addr, err)
+	}
+	t.address, _, _ = net.SplitHostPort(used)
+	conn := telnet.NewConn(netConn)
+	conn.SetTerminalType(t.termType())
+
+	t.session = conn
+	t.stdin = conn
+	t.stdout = newDetachableWriter(t.output(os.Stdout))
+	t.stderr = newDetachableWriter(t.output(os.Stderr))
+	t.escape = NewEscapeFilter()
+
+	t.done = make(chan error, 1)
+	go func() {
+		_, err := io.Copy(t.stdout, conn)
+		if errors.Is(err, net.ErrClosed) {
+			err = nil // Closed by us, e.g. with ~.
+		}
+		t.done <- err
+	}()
+
+	// Execute startup command if configured
+	if t.conn.StartupCommand != "" {
+		go t.executeStartupCommand(conn)
+	}
+
+	return t.attach()
+}
@@ -0,0 +1,222 @@
+package ssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"gossh/internal/model"
+)
+
+// testBanner is sent by startExecServer before authentication
+const testBanner = "Authorized use only\n"
+
+// startExecServer runs an SSH server whose only commands are `exit <code>`
+// and `echo <text>`.
+// It also echoes shells and forwards direct-tcpip channels, so it can serve
+// as a jump host.
+func startExecServer(t *testing.T) model.Connection {
+	t.Helper()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(ssh.ConnMetadata, []byte) (*ssh.Permissions, error) {
+			return nil, nil
+		},
+		BannerCallback: func(ssh.ConnMetadata) string {
+			return testBanner
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveExec(conn, config)
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return model.Connection{
+		Name:     "test",
+		Host:     addr.IP.String(),
+		Port:     addr.Port,
+		User:     "test",
+		AuthType: model.AuthPassword,
+		Password: "secret",
+	}
+}
+
+func serveExec(conn net.Conn, config *ssh.ServerConfig) {
+	defer conn.Close()
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChan := range chans {
+		if newChan.ChannelType() == "direct-tcpip" {
+			go serveDirectTCPIP(newChan)
+			continue
+		}
+		ch, requests, err := newChan.Accept()
+		if err != nil {
+			continue
+		}
+		go func() {
+			defer ch.Close()
+			for req := range requests {
+				if req.Type == "shell" {
+					_ = req.Reply(true, nil)
+					serveEcho(ch)
+					return
+				}
+				if req.Type != "exec" {
+					_ = req.Reply(req.Type == "pty-req", nil)
+					continue
+				}
+				command := string(req.Payload[4:])
+				_ = req.Reply(true, nil)
+
+				if text, ok := strings.CutPrefix(command, "echo "); ok {
+					_, _ = io.WriteString(ch, strings.ReplaceAll(text, `\n`, "\n")+"\n")
+				}
+				code, _ := strconv.Atoi(strings.TrimPrefix(command, "exit "))
+				status := make([]byte, 4)
+				binary.BigEndian.PutUint32(status, uint32(code))
+				_, _ = ch.SendRequest("exit-status", false, status)
+				return
+			}
+		}()
+	}
+}
+
+// serveDirectTCPIP forwards a channel to the address the client asked for
+func serveDirectTCPIP(newChan ssh.NewChannel) {
+	var target struct {
+		Host       string
+		Port       uint32
+		OriginHost string
+		OriginPort uint32
+	}
+	if err := ssh.Unmarshal(newChan.ExtraData(), &target); err != nil {
+		_ = newChan.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	conn, err := net.Dial("tcp", net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port))))
+	if err != nil {
+		_ = newChan.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	ch, requests, err := newChan.Accept()
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	go func() {
+		_, _ = io.Copy(ch, conn)
+		ch.Close()
+	}()
+	_, _ = io.Copy(conn, ch)
+	conn.Close()
+}
+
+// serveEcho is a shell that echoes its input until it reads "exit"
+func serveEcho(ch ssh.Channel) {
+	buf := make([]byte, 1024)
+	for {
+		n, err := ch.Read(buf)
+		if err != nil {
+			return
+		}
+		_, _ = ch.Write(buf[:n])
+		if strings.Contains(string(buf[:n]), "exit") {
+			_, _ = ch.SendRequest("exit-status", false, make([]byte, 4))
+			return
+		}
+	}
+}
+
+func TestTerminalRunCommand(t *testing.T) {
+	conn := startExecServer(t)
+
+	tests := []struct {
+		command string
+		want    int
+	}{
+		{"exit 0", 0},
+		{"exit 3", 3},
+		{"exit 130", 130},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			err := NewTerminal(conn).RunCommand(tt.command)
+			code, ok := ExitStatus(err)
+			if !ok {
+				t.Fatalf("RunCommand() error = %v, want exit status", err)
+			}
+			if code != tt.want {
+				t.Errorf("ExitStatus() = %d, want %d", code, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitStatusNotExit(t *testing.T) {
+	if _, ok := ExitStatus(errors.New("connection refused")); ok {
+		t.Error("ExitStatus() ok = true for a connection error")
+	}
+}
+
+func TestTerminalEscapeCommandForwards(t *testing.T) {
+	term := NewTerminal(startExecServer(t))
+	defer func() {
+		if term.forwarder != nil {
+			term.forwarder.Stop()
+		}
+	}()
+
+	// The test server refuses remote forwards, so only -L and -D are tried
+	for _, line := range []string{"-L127.0.0.1:0:127.0.0.1:22", "-D 127.0.0.1:0"} {
+		term.runEscapeCommand(line)
+	}
+	if term.forwarder == nil {
+		t.Fatal("no forward was started")
+	}
+	var types []ForwardType
+	for _, pf := range term.forwarder.forwards {
+		types = append(types, pf.Type)
+	}
+	want := []ForwardType{ForwardLocal, ForwardDynamic}
+	if len(types) != len(want) || types[0] != want[0] || types[1] != want[1] {
+		t.Errorf("forwards started = %v, want %v", types, want)
+	}
+}
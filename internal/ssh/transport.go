@@ -0,0 +1,107 @@
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"gossh/internal/model"
+)
+
+// DialTransport opens a plain connection to the SSH port of conn for
+// another SSH client to run its handshake over, e.g. ssh running gossh
+// stdio as its ProxyCommand. It is dialed like gossh connects to the host:
+// from its bind address, trying its addresses in order, and through its
+// jump hosts, which authenticate with their saved credentials and have
+// their keys checked with hostKeyCallback. The target's key is left to the
+// other client.
+func DialTransport(conn model.Connection, hostKeyCallback ssh.HostKeyCallback) (net.Conn, error) {
+	if conn.IsTelnet() {
+		return nil, ErrTelnet
+	}
+	addrs, _, err := dialTargets(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(conn.JumpHosts) == 0 {
+		dialer, err := newDialer(conn.BindAddress, connectTimeout())
+		if err != nil {
+			return nil, err
+		}
+		netConn, _, err := dialFirst(dialer, addrs)
+		return netConn, err
+	}
+	if len(conn.Jumps) == 0 {
+		return nil, fmt.Errorf("%w: %s", model.ErrJumpNotFound, strings.Join(conn.JumpHosts, ", "))
+	}
+
+	// The last jump host is connected to like a target with the rest as its
+	// jump hosts, then the target is dialed through it
+	hops := len(conn.Jumps) + 1
+	last := conn.Jumps[len(conn.Jumps)-1]
+	last.Jumps = conn.Jumps[:len(conn.Jumps)-1]
+	last.JumpHosts = nil
+	for _, hop := range last.Jumps {
+		last.JumpHosts = append(last.JumpHosts, hop.Name)
+	}
+	via, _, err := connectConn(last, hostKeyCallback, nil)
+	if err != nil {
+		var jumpErr *JumpError
+		if errors.As(err, &jumpErr) {
+			jumpErr.Hops = hops
+			return nil, jumpErr
+		}
+		return nil, &JumpError{Hop: hops - 1, Hops: hops, Conn: last, Err: err}
+	}
+
+	for _, addr := range addrs {
+		var netConn net.Conn
+		if netConn, err = via.Dial("tcp", addr); err == nil {
+			return &hopConn{Conn: netConn, via: via}, nil
+		}
+	}
+	via.Close()
+	return nil, &JumpError{Hop: hops, Hops: hops, Conn: conn, Err: err}
+}
+
+// DialThrough logs in to conn, through its jump hosts if it has any, and
+// opens a connection from it to addr, e.g. a database only the host can
+// reach. Host keys are checked with hostKeyCallback. The login is closed
+// with the returned connection.
+func DialThrough(conn model.Connection, hostKeyCallback ssh.HostKeyCallback, addr string) (net.Conn, error) {
+	client, _, err := connectConn(conn, hostKeyCallback, nil)
+	if err != nil {
+		return nil, err
+	}
+	netConn, err := client.Dial("tcp", addr)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("%s: %w", addr, err)
+	}
+	return &hopConn{Conn: netConn, via: client}, nil
+}
+
+// hopConn is a connection dialed through a jump host, which it closes with
+// itself
+type hopConn struct {
+	net.Conn
+	via *ssh.Client
+}
+
+func (c *hopConn) Close() error {
+	err := c.Conn.Close()
+	c.via.Close()
+	return err
+}
+
+// CloseWrite tells the other end nothing more will be sent, like
+// net.TCPConn's
+func (c *hopConn) CloseWrite() error {
+	if cw, ok := c.Conn.(interface{ CloseWrite() error }); ok {
+		return cw.CloseWrite()
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package ssh
+
+import (
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"gossh/internal/model"
+)
+
+func TestDialTransport(t *testing.T) {
+	first, second, target := startExecServer(t), startExecServer(t), startExecServer(t)
+	first.Name, second.Name, target.Name = "bastion-a", "bastion-b", "db"
+	viaJumps := target
+	viaJumps.JumpHosts = []string{"bastion-a", "bastion-b"}
+	viaJumps.Jumps = []model.Connection{first, second}
+
+	for _, conn := range []model.Connection{target, viaJumps} {
+		netConn, err := DialTransport(conn, nil)
+		if err != nil {
+			t.Fatalf("DialTransport(%d jumps) error = %v", len(conn.Jumps), err)
+		}
+
+		// The other client runs its own handshake over the transport
+		config := &ssh.ClientConfig{
+			User:            "test",
+			Auth:            []ssh.AuthMethod{ssh.Password("secret")},
+			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		}
+		c, chans, reqs, err := ssh.NewClientConn(netConn, "db", config)
+		if err != nil {
+			t.Fatalf("handshake over the transport (%d jumps) failed: %v", len(conn.Jumps), err)
+		}
+		client := ssh.NewClient(c, chans, reqs)
+		session, err := client.NewSession()
+		if err != nil {
+			t.Fatalf("NewSession() error = %v", err)
+		}
+		if code, _ := ExitStatus(session.Run("exit 4")); code != 4 {
+			t.Errorf("exit status over the transport (%d jumps) = %d, want 4", len(conn.Jumps), code)
+		}
+		client.Close()
+	}
+}
+
+func TestDialTransportAttributesFailure(t *testing.T) {
+	bastion, target := startExecServer(t), startExecServer(t)
+	down := model.Connection{Name: "down", Host: "127.0.0.1", Port: closedPort(t), User: "test"}
+
+	tests := []struct {
+		name    string
+		jumps   []model.Connection
+		target  model.Connection
+		wantHop int
+		wantFor string
+	}{
+		{"first hop down", []model.Connection{down, bastion}, target, 1, "down"},
+		{"last jump down", []model.Connection{bastion, down}, target, 2, "down"},
+		{"target down", []model.Connection{bastion}, down, 2, "down"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := tt.target
+			conn.JumpHosts = []string{"x"}
+			conn.Jumps = tt.jumps
+
+			_, err := DialTransport(conn, nil)
+			var jumpErr *JumpError
+			if !errors.As(err, &jumpErr) {
+				t.Fatalf("DialTransport() error = %v, want *JumpError", err)
+			}
+			hops := len(tt.jumps) + 1
+			if jumpErr.Hop != tt.wantHop || jumpErr.Hops != hops || jumpErr.Conn.Name != tt.wantFor {
+				t.Errorf("failed hop = %d/%d (%s), want %d/%d (%s)", jumpErr.Hop, jumpErr.Hops, jumpErr.Conn.Name, tt.wantHop, hops, tt.wantFor)
+			}
+		})
+	}
+}
+
+func TestDialThrough(t *testing.T) {
+	bastion, host := startExecServer(t), startExecServer(t)
+	host.JumpHosts = []string{"bastion"}
+	host.Jumps = []model.Connection{bastion}
+
+	// A service only reachable from the host, answering with what it gets
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		c, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		_, _ = io.Copy(c, c)
+	}()
+
+	netConn, err := DialThrough(host, nil, listener.Addr().String())
+	if err != nil {
+		t.Fatalf("DialThrough() error = %v", err)
+	}
+	defer netConn.Close()
+	if _, err := netConn.Write([]byte("ping")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	reply := make([]byte, 4)
+	if _, err := io.ReadFull(netConn, reply); err != nil || string(reply) != "ping" {
+		t.Errorf("reply = %q, %v, want ping", reply, err)
+	}
+
+	if _, err := DialThrough(host, nil, closedAddr(t)); err == nil {
+		t.Error("DialThrough() to a closed port should fail")
+	}
+}
@@ -27,6 +27,10 @@ type hostEntry struct {
 	user         string
 	port         int
 	identityFile string
+	proxyJump    []string
+	bindAddress  string
+	term         string
+	locale       string
 }
 
 // ParseFile parses an SSH config file and returns connections
@@ -114,6 +118,31 @@ func (p *Parser) parse(file *os.File) ([]model.Connection, error) {
 				}
 				current.identityFile = value
 			}
+		case "bindaddress", "bindinterface":
+			if current != nil {
+				current.bindAddress = value
+			}
+		case "setenv":
+			// Only TERM and the locale map to connection settings
+			if current != nil {
+				for _, pair := range strings.Fields(value) {
+					name, v, _ := strings.Cut(pair, "=")
+					switch name {
+					case "TERM":
+						current.term = v
+					case "LANG", "LC_ALL":
+						current.locale = v
+					}
+				}
+			}
+		case "proxyjump":
+			if current != nil && !strings.EqualFold(value, "none") {
+				for _, hop := range strings.Split(value, ",") {
+					if hop = strings.TrimSpace(hop); hop != "" {
+						current.proxyJump = append(current.proxyJump, hop)
+					}
+				}
+			}
 		}
 	}
 
@@ -178,6 +207,10 @@ func (p *Parser) entriesToConnections(entries []*hostEntry) []model.Connection {
 				User:    entry.user,
 				Group:   "Imported",
 			}
+			conn.JumpHosts = entry.proxyJump
+			conn.BindAddress = entry.bindAddress
+			conn.Term = entry.term
+			conn.Locale = entry.locale
 
 			// If no hostname specified, use the pattern as hostname
 			if conn.Host == "" {
@@ -201,7 +234,36 @@ func (p *Parser) entriesToConnections(entries []*hostEntry) []model.Connection {
 		}
 	}
 
-	return connections
+	return orderByJumps(connections)
+}
+
+// orderByJumps moves jump hosts ahead of the connections that use them, so
+// adding the connections one by one never refers to a host not added yet
+func orderByJumps(connections []model.Connection) []model.Connection {
+	byName := make(map[string]int, len(connections))
+	for i, c := range connections {
+		byName[strings.ToLower(c.Name)] = i
+	}
+
+	ordered := make([]model.Connection, 0, len(connections))
+	visited := make([]bool, len(connections))
+	var visit func(i int)
+	visit = func(i int) {
+		if visited[i] {
+			return
+		}
+		visited[i] = true
+		for _, hop := range connections[i].JumpHosts {
+			if j, ok := byName[strings.ToLower(hop)]; ok {
+				visit(j)
+			}
+		}
+		ordered = append(ordered, connections[i])
+	}
+	for i := range connections {
+		visit(i)
+	}
+	return ordered
 }
 
 // Merge merges imported connections with existing ones
@@ -3,6 +3,7 @@ package sshconfig
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"gossh/internal/model"
@@ -142,6 +143,51 @@ func TestParseFileHostAsHostname(t *testing.T) {
 	}
 }
 
+func TestParseFileProxyJump(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	content := `Host app
+    HostName 10.0.1.5
+    User deploy
+    ProxyJump inner, bastion
+
+Host inner
+    HostName 10.0.0.2
+    User ops
+    ProxyJump bastion
+
+Host bastion
+    HostName bastion.example.com
+    User ops
+    ProxyJump none
+`
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	connections, err := NewParser().ParseFile(configPath)
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+	if len(connections) != 3 {
+		t.Fatalf("Expected 3 connections, got %d", len(connections))
+	}
+
+	// Jump hosts come before the connections that use them
+	var names []string
+	for _, c := range connections {
+		names = append(names, c.Name)
+	}
+	if strings.Join(names, ",") != "bastion,inner,app" {
+		t.Errorf("Order = %v, want [bastion inner app]", names)
+	}
+	if got := connections[2].JumpHosts; len(got) != 2 || got[0] != "inner" || got[1] != "bastion" {
+		t.Errorf("app JumpHosts = %v, want [inner bastion]", got)
+	}
+	if got := connections[0].JumpHosts; len(got) != 0 {
+		t.Errorf("bastion JumpHosts = %v, want none", got)
+	}
+}
+
 func TestParseFileEmpty(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "config")
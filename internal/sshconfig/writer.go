@@ -0,0 +1,118 @@
+package sshconfig
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"gossh/internal/model"
+)
+
+// Format renders connections as OpenSSH config Host blocks. Key and agent
+// connections are left to ssh, which reads the key from IdentityFile or
+// the agent. OpenSSH takes no password from its config, so with a stdio
+// command, e.g. gossh stdio, password connections use it followed by the
+// connection's name as their ProxyCommand: gossh then reaches the host
+// with its saved credentials for the jump hosts, and only the host's own
+// password is left for ssh to ask for.
+func Format(connections []model.Connection, stdio []string) string {
+	var b strings.Builder
+	b.WriteString("# Exported by gossh\n")
+
+	for _, conn := range connections {
+		var proxy []string
+		if stdio != nil && conn.AuthType == model.AuthPassword && !conn.IsTelnet() {
+			proxy = append(stdio[:len(stdio):len(stdio)], conn.Name)
+		}
+		writeHost(&b, conn, hostAlias(conn.Name, conn.Host), proxy)
+	}
+
+	return b.String()
+}
+
+// FormatProxied renders connections as Host blocks whose ProxyCommand is
+// command followed by the connection's name, e.g. gossh stdio, which
+// reaches the host the way gossh does: from its bind address, trying its
+// addresses and through its jump hosts. Each block answers to the
+// connection's aliases too. Telnet connections are left out.
+func FormatProxied(connections []model.Connection, command []string) string {
+	var b strings.Builder
+	b.WriteString("# Generated by gossh print-ssh-config\n")
+
+	for _, conn := range connections {
+		if conn.IsTelnet() {
+			continue
+		}
+		patterns := []string{hostAlias(conn.Name, conn.Host)}
+		for _, alias := range conn.Aliases {
+			patterns = append(patterns, hostAlias(alias, alias))
+		}
+		writeHost(&b, conn, strings.Join(patterns, " "), append(command[:len(command):len(command)], conn.Name))
+	}
+
+	return b.String()
+}
+
+// writeHost writes the Host block of conn answering to patterns. With a
+// proxy command, connecting is left to it.
+func writeHost(b *strings.Builder, conn model.Connection, patterns string, proxy []string) {
+	b.WriteString("\n")
+	if conn.Group != "" {
+		fmt.Fprintf(b, "# group: %s\n", conn.Group)
+	}
+	fmt.Fprintf(b, "Host %s\n", patterns)
+	fmt.Fprintf(b, "    HostName %s\n", conn.Host)
+	if conn.User != "" {
+		fmt.Fprintf(b, "    User %s\n", conn.User)
+	}
+	if conn.Port != 0 && conn.Port != 22 {
+		fmt.Fprintf(b, "    Port %d\n", conn.Port)
+	}
+	if conn.KeyPath != "" {
+		fmt.Fprintf(b, "    IdentityFile %s\n", conn.KeyPath)
+	}
+	if conn.BindAddress != "" && proxy == nil {
+		if net.ParseIP(conn.BindAddress) != nil {
+			fmt.Fprintf(b, "    BindAddress %s\n", conn.BindAddress)
+		} else {
+			fmt.Fprintf(b, "    BindInterface %s\n", conn.BindAddress)
+		}
+	}
+	var env []string
+	if conn.Term != "" {
+		env = append(env, "TERM="+conn.Term)
+	}
+	for _, name := range []string{"LANG", "LC_ALL"} {
+		if value, ok := conn.LocaleEnv()[name]; ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	if len(env) > 0 {
+		fmt.Fprintf(b, "    SetEnv %s\n", strings.Join(env, " "))
+	}
+	switch {
+	case proxy != nil:
+		args := make([]string, len(proxy))
+		for i, arg := range proxy {
+			// ssh expands %h and the like in ProxyCommand, so a literal % is doubled
+			args[i] = strings.ReplaceAll(model.ShellQuote(arg), "%", "%%")
+		}
+		fmt.Fprintf(b, "    ProxyCommand %s\n", strings.Join(args, " "))
+	case len(conn.JumpHosts) > 0:
+		// Jump hosts are exported under their own Host aliases
+		hops := make([]string, len(conn.JumpHosts))
+		for i, name := range conn.JumpHosts {
+			hops[i] = hostAlias(name, name)
+		}
+		fmt.Fprintf(b, "    ProxyJump %s\n", strings.Join(hops, ","))
+	}
+}
+
+// hostAlias turns a connection name into a Host pattern, which cannot
+// contain whitespace
+func hostAlias(name, host string) string {
+	if alias := strings.Join(strings.Fields(name), "-"); alias != "" {
+		return alias
+	}
+	return host
+}
@@ -0,0 +1,101 @@
+package sshconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gossh/internal/model"
+)
+
+func TestFormatRoundTrip(t *testing.T) {
+	conns := []model.Connection{
+		{Name: "web server", Host: "10.0.0.1", User: "deploy", Port: 2222, KeyPath: "/keys/id_ed25519", Group: "Production", BindAddress: "wg0"},
+		{Name: "db", Host: "db.example.com", User: "root", Port: 22, JumpHosts: []string{"web server"}, Term: "vt100", Locale: "C"},
+	}
+
+	out := Format(conns, nil)
+	if !strings.Contains(out, "Host web-server\n") {
+		t.Errorf("Expected whitespace in name to be replaced, got:\n%s", out)
+	}
+	if !strings.Contains(out, "ProxyJump web-server\n") {
+		t.Errorf("Expected jump host to use its Host alias, got:\n%s", out)
+	}
+	if !strings.Contains(out, "BindInterface wg0\n") {
+		t.Errorf("Expected interface bind address, got:\n%s", out)
+	}
+	if !strings.Contains(out, "SetEnv TERM=vt100 LANG=C LC_ALL=C\n") {
+		t.Errorf("Expected TERM and locale overrides, got:\n%s", out)
+	}
+	if strings.Contains(out, "Port 22\n") {
+		t.Errorf("Default port should be omitted, got:\n%s", out)
+	}
+
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(out), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	parsed, err := NewParser().ParseFile(path)
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+	if len(parsed) != 2 {
+		t.Fatalf("Expected 2 connections, got %d", len(parsed))
+	}
+	if parsed[0].Host != "10.0.0.1" || parsed[0].Port != 2222 || parsed[0].KeyPath != "/keys/id_ed25519" || parsed[0].BindAddress != "wg0" {
+		t.Errorf("Unexpected first connection: %+v", parsed[0])
+	}
+	if parsed[1].Name != "db" || parsed[1].User != "root" || parsed[1].Port != 22 || len(parsed[1].JumpHosts) != 1 || parsed[1].Term != "vt100" || parsed[1].Locale != "C" {
+		t.Errorf("Unexpected second connection: %+v", parsed[1])
+	}
+}
+
+func TestFormatPasswordConnections(t *testing.T) {
+	conns := []model.Connection{
+		{Name: "web server", Host: "10.0.0.1", User: "deploy", AuthType: model.AuthPassword, JumpHosts: []string{"bastion"}},
+		{Name: "db", Host: "db.example.com", User: "root", AuthType: model.AuthKey, KeyPath: "/keys/id_ed25519"},
+		{Name: "switch", Host: "10.0.0.2", Port: 23, Type: model.ConnTypeTelnet, AuthType: model.AuthPassword},
+	}
+
+	out := Format(conns, []string{"/usr/bin/gossh", "--config", "/home/me/config.yaml", "stdio"})
+	if !strings.Contains(out, "    ProxyCommand /usr/bin/gossh --config /home/me/config.yaml stdio 'web server'\n") {
+		t.Errorf("Expected password connections to go through gossh stdio, got:\n%s", out)
+	}
+	if strings.Contains(out, "ProxyJump") {
+		t.Errorf("Jump hosts of password connections are the proxy's to handle, got:\n%s", out)
+	}
+	if strings.Contains(out, "stdio db") || strings.Contains(out, "stdio switch") {
+		t.Errorf("Key and telnet connections should not use the proxy, got:\n%s", out)
+	}
+
+	if out := Format(conns, nil); strings.Contains(out, "ProxyCommand") {
+		t.Errorf("Expected no ProxyCommand without a stdio command, got:\n%s", out)
+	}
+}
+
+func TestFormatProxied(t *testing.T) {
+	conns := []model.Connection{
+		{Name: "web server", Aliases: []string{"web1"}, Host: "10.0.0.1", User: "deploy", Port: 2222, KeyPath: "/keys/id_ed25519", BindAddress: "wg0"},
+		{Name: "db", Host: "db.example.com", User: "root", Port: 22, JumpHosts: []string{"web server"}},
+		{Name: "switch", Host: "10.0.0.2", Port: 23, Type: model.ConnTypeTelnet},
+	}
+
+	out := FormatProxied(conns, []string{"/opt/go ssh/gossh", "--config", "/home/me/100%.yaml", "stdio"})
+	if !strings.Contains(out, "Host web-server web1\n") {
+		t.Errorf("Expected the aliases in the Host line, got:\n%s", out)
+	}
+	if !strings.Contains(out, "    ProxyCommand '/opt/go ssh/gossh' --config /home/me/100%%.yaml stdio 'web server'\n") {
+		t.Errorf("Expected a quoted ProxyCommand with %% doubled, got:\n%s", out)
+	}
+	if !strings.Contains(out, "    ProxyCommand '/opt/go ssh/gossh' --config /home/me/100%%.yaml stdio db\n") {
+		t.Errorf("Expected a ProxyCommand for db, got:\n%s", out)
+	}
+	if strings.Contains(out, "ProxyJump") || strings.Contains(out, "BindInterface") {
+		t.Errorf("Jump hosts and bind addresses are the proxy's to handle, got:\n%s", out)
+	}
+	if strings.Contains(out, "switch") {
+		t.Errorf("Telnet connections should be left out, got:\n%s", out)
+	}
+}
@@ -0,0 +1,257 @@
+// Package telnet implements the client side of the telnet protocol, enough
+// to drive an interactive session on devices that offer no SSH
+package telnet
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Telnet commands (RFC 854)
+const (
+	cmdSE   byte = 240
+	cmdSB   byte = 250
+	cmdWILL byte = 251
+	cmdWONT byte = 252
+	cmdDO   byte = 253
+	cmdDONT byte = 254
+	cmdIAC  byte = 255
+)
+
+// Telnet options
+const (
+	optEcho  byte = 1  // RFC 857
+	optSGA   byte = 3  // Suppress go ahead, RFC 858
+	optTType byte = 24 // Terminal type, RFC 1091
+	optNAWS  byte = 31 // Window size, RFC 1073
+)
+
+// Terminal type subnegotiation
+const (
+	ttypeIS   byte = 0
+	ttypeSEND byte = 1
+)
+
+// Conn is a telnet connection. Read returns the data stream with protocol
+// commands removed and answered; Write escapes data so it is sent as is.
+type Conn struct {
+	conn     net.Conn
+	r        *bufio.Reader
+	termType string
+
+	mu     sync.Mutex // Serializes writes and guards the window size
+	width  int
+	height int
+	naws   bool // The server asked for window size updates
+}
+
+// Dial connects to a telnet server
+func Dial(host string, port int, timeout time.Duration) (*Conn, error) {
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", addr, err)
+	}
+	return NewConn(conn), nil
+}
+
+// NewConn runs the telnet protocol over an established connection
+func NewConn(conn net.Conn) *Conn {
+	return &Conn{
+		conn:     conn,
+		r:        bufio.NewReader(conn),
+		termType: "xterm-256color",
+		width:    80,
+		height:   24,
+	}
+}
+
+// SetTerminalType sets the terminal type reported to the server
+func (c *Conn) SetTerminalType(termType string) {
+	if termType != "" {
+		c.termType = termType
+	}
+}
+
+// Read reads session data, handling any protocol commands in between
+func (c *Conn) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if n > 0 && c.r.Buffered() == 0 {
+			break // Return what we have instead of blocking
+		}
+		b, err := c.r.ReadByte()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+		if b != cmdIAC {
+			p[n] = b
+			n++
+			continue
+		}
+		data, isData, err := c.command()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+		if isData {
+			p[n] = data
+			n++
+		}
+	}
+	return n, nil
+}
+
+// command handles the bytes after an IAC. An escaped IAC is data.
+func (c *Conn) command() (byte, bool, error) {
+	cmd, err := c.r.ReadByte()
+	if err != nil {
+		return 0, false, err
+	}
+
+	switch cmd {
+	case cmdIAC:
+		return cmdIAC, true, nil
+	case cmdWILL, cmdWONT, cmdDO, cmdDONT:
+		opt, err := c.r.ReadByte()
+		if err != nil {
+			return 0, false, err
+		}
+		return 0, false, c.negotiate(cmd, opt)
+	case cmdSB:
+		sub, err := c.subnegotiation()
+		if err != nil {
+			return 0, false, err
+		}
+		if len(sub) >= 2 && sub[0] == optTType && sub[1] == ttypeSEND {
+			reply := []byte{cmdIAC, cmdSB, optTType, ttypeIS}
+			reply = append(reply, c.termType...)
+			return 0, false, c.send(append(reply, cmdIAC, cmdSE))
+		}
+		return 0, false, nil
+	default:
+		// NOP, GA, AYT and friends carry no data
+		return 0, false, nil
+	}
+}
+
+// subnegotiation reads up to IAC SE
+func (c *Conn) subnegotiation() ([]byte, error) {
+	var sub []byte
+	for {
+		b, err := c.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b == cmdIAC {
+			next, err := c.r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			if next == cmdSE {
+				return sub, nil
+			}
+			b = next
+		}
+		sub = append(sub, b)
+	}
+}
+
+// negotiate answers an option request. The server may echo and suppress
+// go-ahead; we offer the terminal type and window size and refuse the rest.
+func (c *Conn) negotiate(cmd, opt byte) error {
+	switch cmd {
+	case cmdWILL:
+		if opt == optEcho || opt == optSGA {
+			return c.send([]byte{cmdIAC, cmdDO, opt})
+		}
+		return c.send([]byte{cmdIAC, cmdDONT, opt})
+	case cmdDO:
+		switch opt {
+		case optTType, optSGA:
+			return c.send([]byte{cmdIAC, cmdWILL, opt})
+		case optNAWS:
+			c.mu.Lock()
+			c.naws = true
+			c.mu.Unlock()
+			if err := c.send([]byte{cmdIAC, cmdWILL, opt}); err != nil {
+				return err
+			}
+			return c.sendWindowSize()
+		}
+		return c.send([]byte{cmdIAC, cmdWONT, opt})
+	}
+	// WONT and DONT need no answer: the option stays off
+	return nil
+}
+
+// Write sends session data, doubling IAC bytes so they are not taken as
+// commands
+func (c *Conn) Write(p []byte) (int, error) {
+	buf := make([]byte, 0, len(p))
+	for _, b := range p {
+		if b == cmdIAC {
+			buf = append(buf, cmdIAC)
+		}
+		buf = append(buf, b)
+	}
+	if err := c.send(buf); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// WindowChange reports a new window size. It is sent once the server asks
+// for window size updates, and remembered until then.
+func (c *Conn) WindowChange(height, width int) error {
+	c.mu.Lock()
+	c.width, c.height = width, height
+	naws := c.naws
+	c.mu.Unlock()
+
+	if !naws {
+		return nil
+	}
+	return c.sendWindowSize()
+}
+
+// sendWindowSize sends the NAWS subnegotiation, escaping IAC in the sizes
+func (c *Conn) sendWindowSize() error {
+	c.mu.Lock()
+	size := make([]byte, 4)
+	binary.BigEndian.PutUint16(size[0:], uint16(c.width))
+	binary.BigEndian.PutUint16(size[2:], uint16(c.height))
+	c.mu.Unlock()
+
+	msg := []byte{cmdIAC, cmdSB, optNAWS}
+	for _, b := range size {
+		if b == cmdIAC {
+			msg = append(msg, cmdIAC)
+		}
+		msg = append(msg, b)
+	}
+	return c.send(append(msg, cmdIAC, cmdSE))
+}
+
+// send writes raw protocol bytes
+func (c *Conn) send(b []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := c.conn.Write(b)
+	return err
+}
+
+// Close closes the connection
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
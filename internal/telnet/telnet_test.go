@@ -0,0 +1,103 @@
+package telnet
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+)
+
+// fakeServer connects a client Conn to the server end of a pipe, which
+// starts by sending send
+func fakeServer(t *testing.T, send []byte) (*Conn, net.Conn) {
+	t.Helper()
+	client, server := net.Pipe()
+	t.Cleanup(func() { client.Close(); server.Close() })
+
+	go func() {
+		_, _ = server.Write(send)
+	}()
+	return NewConn(client), server
+}
+
+// readN reads exactly n bytes from r
+func readN(t *testing.T, r io.Reader, n int) []byte {
+	t.Helper()
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return buf
+}
+
+func TestConnReadStripsCommands(t *testing.T) {
+	send := []byte{'h', 'i', cmdIAC, cmdWILL, optEcho, ' ', cmdIAC, cmdIAC, '!'}
+	c, server := fakeServer(t, send)
+
+	got := make(chan []byte)
+	go func() {
+		var data []byte
+		buf := make([]byte, 16)
+		for len(data) < 5 {
+			n, err := c.Read(buf)
+			if err != nil {
+				break
+			}
+			data = append(data, buf[:n]...)
+		}
+		got <- data
+	}()
+
+	// The server's WILL ECHO is accepted
+	if reply := readN(t, server, 3); !bytes.Equal(reply, []byte{cmdIAC, cmdDO, optEcho}) {
+		t.Errorf("reply to WILL ECHO = %v", reply)
+	}
+	if data := <-got; !bytes.Equal(data, []byte{'h', 'i', ' ', cmdIAC, '!'}) {
+		t.Errorf("Read() = %v, want data without commands", data)
+	}
+}
+
+func TestConnNegotiation(t *testing.T) {
+	send := []byte{
+		cmdIAC, cmdDO, optNAWS,
+		cmdIAC, cmdSB, optTType, ttypeSEND, cmdIAC, cmdSE,
+		cmdIAC, cmdDO, 39, // NEW-ENVIRON, which we refuse
+		'$',
+	}
+	c, server := fakeServer(t, send)
+	c.SetTerminalType("vt100")
+	_ = c.WindowChange(50, 132)
+
+	go func() {
+		buf := make([]byte, 16)
+		_, _ = c.Read(buf)
+	}()
+
+	want := [][]byte{
+		{cmdIAC, cmdWILL, optNAWS},
+		{cmdIAC, cmdSB, optNAWS, 0, 132, 0, 50, cmdIAC, cmdSE},
+		append(append([]byte{cmdIAC, cmdSB, optTType, ttypeIS}, "vt100"...), cmdIAC, cmdSE),
+		{cmdIAC, cmdWONT, 39},
+	}
+	for _, w := range want {
+		if got := readN(t, server, len(w)); !bytes.Equal(got, w) {
+			t.Errorf("sent %v, want %v", got, w)
+		}
+	}
+
+	// Once negotiated, size changes are sent right away, escaping IAC
+	go func() { _ = c.WindowChange(24, 255) }()
+	w := []byte{cmdIAC, cmdSB, optNAWS, 0, cmdIAC, cmdIAC, 0, 24, cmdIAC, cmdSE}
+	if got := readN(t, server, len(w)); !bytes.Equal(got, w) {
+		t.Errorf("WindowChange() sent %v, want %v", got, w)
+	}
+}
+
+func TestConnWriteEscapesIAC(t *testing.T) {
+	c, server := fakeServer(t, nil)
+
+	go func() { _, _ = c.Write([]byte{'a', cmdIAC, 'b'}) }()
+	if got := readN(t, server, 4); !bytes.Equal(got, []byte{'a', cmdIAC, cmdIAC, 'b'}) {
+		t.Errorf("Write() sent %v", got)
+	}
+}
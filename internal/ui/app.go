@@ -1,16 +1,33 @@
 package ui
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"os/exec"
+	"os/user"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"gossh/internal/clipboard"
 	"gossh/internal/config"
+	"gossh/internal/discovery"
+	"gossh/internal/hooks"
 	"gossh/internal/i18n"
+	"gossh/internal/jobs"
+	"gossh/internal/keycache"
 	"gossh/internal/model"
+	"gossh/internal/plugins"
+	"gossh/internal/shell"
 	"gossh/internal/ssh"
+	"gossh/internal/sshconfig"
 	"gossh/internal/ui/styles"
 	"gossh/internal/ui/views"
 )
@@ -29,130 +46,249 @@ const (
 	ViewSettings
 	ViewHostKey
 	ViewTesting
+	ViewWizard
+	ViewBanner
+	ViewBroadcast
+	ViewFilters
+	ViewHistory
+	ViewExec
+	ViewLocal
+	ViewScrollback
+	ViewPlugins
+	ViewDetails
 )
 
-// KeyMap defines the key bindings for the application
+// KeyMap defines the key bindings for the application. The help
+// descriptions are i18n keys, translated on the help screen.
 type KeyMap struct {
-	Up       key.Binding
-	Down     key.Binding
-	Enter    key.Binding
-	Add      key.Binding
-	Edit     key.Binding
-	Delete   key.Binding
-	Help     key.Binding
-	Quit     key.Binding
-	Back     key.Binding
-	Search   key.Binding
-	Confirm  key.Binding
-	Cancel   key.Binding
-	Settings key.Binding
-	Test     key.Binding
+	Up         key.Binding
+	Down       key.Binding
+	Enter      key.Binding
+	Add        key.Binding
+	Edit       key.Binding
+	Delete     key.Binding
+	Help       key.Binding
+	Quit       key.Binding
+	Back       key.Binding
+	Search     key.Binding
+	Confirm    key.Binding
+	Cancel     key.Binding
+	Settings   key.Binding
+	Test       key.Binding
+	Copy       key.Binding
+	TestAll    key.Binding
+	Broadcast  key.Binding
+	Exec       key.Binding
+	History    key.Binding
+	Local      key.Binding
+	Lock       key.Binding
+	Scrollback key.Binding
+	Plugins    key.Binding
+	Details    key.Binding
 }
 
 // DefaultKeyMap returns the default key bindings
 var DefaultKeyMap = KeyMap{
 	Up: key.NewBinding(
 		key.WithKeys("up", "k"),
-		key.WithHelp("up/k", "up"),
+		key.WithHelp("up/k", "help.key.up"),
 	),
 	Down: key.NewBinding(
 		key.WithKeys("down", "j"),
-		key.WithHelp("down/j", "down"),
+		key.WithHelp("down/j", "help.key.down"),
 	),
 	Enter: key.NewBinding(
 		key.WithKeys("enter"),
-		key.WithHelp("enter", "connect"),
+		key.WithHelp("enter", "help.key.connect"),
 	),
 	Add: key.NewBinding(
 		key.WithKeys("a"),
-		key.WithHelp("a", "add"),
+		key.WithHelp("a", "help.key.add"),
 	),
 	Edit: key.NewBinding(
 		key.WithKeys("e"),
-		key.WithHelp("e", "edit"),
+		key.WithHelp("e", "help.key.edit"),
 	),
 	Delete: key.NewBinding(
 		key.WithKeys("d"),
-		key.WithHelp("d", "delete"),
+		key.WithHelp("d", "help.key.delete"),
 	),
 	Help: key.NewBinding(
 		key.WithKeys("?"),
-		key.WithHelp("?", "help"),
+		key.WithHelp("?", "help.key.help"),
 	),
 	Quit: key.NewBinding(
 		key.WithKeys("q", "ctrl+c"),
-		key.WithHelp("q", "quit"),
+		key.WithHelp("q", "help.key.quit"),
 	),
 	Back: key.NewBinding(
 		key.WithKeys("esc"),
-		key.WithHelp("esc", "back"),
+		key.WithHelp("esc", "help.key.back"),
 	),
 	Search: key.NewBinding(
 		key.WithKeys("/"),
-		key.WithHelp("/", "search"),
+		key.WithHelp("/", "help.key.search"),
 	),
 	Confirm: key.NewBinding(
 		key.WithKeys("y"),
-		key.WithHelp("y", "confirm"),
+		key.WithHelp("y", "confirm.yes"),
 	),
 	Cancel: key.NewBinding(
 		key.WithKeys("n"),
-		key.WithHelp("n", "cancel"),
+		key.WithHelp("n", "confirm.no"),
 	),
 	Settings: key.NewBinding(
 		key.WithKeys("s"),
-		key.WithHelp("s", "settings"),
+		key.WithHelp("s", "help.key.settings"),
 	),
 	Test: key.NewBinding(
 		key.WithKeys("t"),
-		key.WithHelp("t", "test"),
+		key.WithHelp("t", "help.key.test"),
+	),
+	Copy: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "help.key.copy"),
+	),
+	TestAll: key.NewBinding(
+		key.WithKeys("ctrl+t"),
+		key.WithHelp("ctrl+t", "help.key.test_all"),
+	),
+	Broadcast: key.NewBinding(
+		key.WithKeys("ctrl+b"),
+		key.WithHelp("ctrl+b", "help.key.broadcast"),
+	),
+	Exec: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "help.key.exec"),
+	),
+	History: key.NewBinding(
+		key.WithKeys("H"),
+		key.WithHelp("H", "help.key.history"),
+	),
+	Local: key.NewBinding(
+		key.WithKeys("!"),
+		key.WithHelp("!", "help.key.local"),
+	),
+	Lock: key.NewBinding(
+		key.WithKeys("ctrl+l"),
+		key.WithHelp("ctrl+l", "help.key.lock"),
+	),
+	Scrollback: key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "help.key.scrollback"),
+	),
+	Plugins: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "help.key.plugins"),
+	),
+	Details: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "help.key.details"),
 	),
 }
 
+// helpSections lists the bindings of the connection list
+func (k KeyMap) helpSections() []views.HelpSection {
+	return []views.HelpSection{
+		views.DefaultListKeyMap.HelpSection(),
+		{
+			Title: i18n.T("help.connection"),
+			Keys:  []key.Binding{k.Add, k.Edit, k.Delete, k.Test, k.TestAll, k.Broadcast, k.Exec, k.Copy, k.History, k.Scrollback, k.Plugins, k.Details},
+		},
+		{
+			Title: i18n.T("help.general"),
+			Keys:  []key.Binding{k.Settings, k.Local, k.Lock, k.Help, k.Quit},
+		},
+	}
+}
+
+// Keys handled by the broadcast view itself; everything else is typed into
+// the hosts
+var (
+	broadcastNext   = key.NewBinding(key.WithKeys("ctrl+n"))
+	broadcastPrev   = key.NewBinding(key.WithKeys("ctrl+p"))
+	broadcastToggle = key.NewBinding(key.WithKeys("ctrl+t"))
+	broadcastClose  = key.NewBinding(key.WithKeys("ctrl+q"))
+)
+
 // Model is the main Bubbletea model
 type Model struct {
-	state     ViewState
-	setup     views.SetupModel
-	unlock    views.UnlockModel
-	list      views.ListModel
-	form      views.FormModel
-	confirm   views.ConfirmModel
-	help      views.HelpModel
-	settings  views.SettingsModel
-	hostkey   views.HostKeyModel
-	config    *config.Manager
-	keys      KeyMap
-	width     int
-	height    int
-	err       error
-	statusMsg string
-	deleteID  string
-	sshConn   model.Connection
-	version   string
+	state       ViewState
+	helpFrom    ViewState // View the help overlay was opened from
+	setup       views.SetupModel
+	unlock      views.UnlockModel
+	list        views.ListModel
+	form        views.FormModel
+	confirm     views.ConfirmModel
+	help        views.HelpModel
+	settings    views.SettingsModel
+	hostkey     views.HostKeyModel
+	wizard      views.WizardModel
+	broadcast   views.BroadcastModel
+	filters     views.FilterMenuModel
+	history     views.HistoryModel
+	exec        views.ExecModel
+	local       views.LocalModel
+	scrollback  views.ScrollbackModel
+	plugins     views.PluginMenuModel
+	details     views.DetailsModel
+	config      *config.Manager
+	keys        KeyMap
+	width       int
+	height      int
+	err         error
+	status      views.StatusBar
+	deleteID    string
+	sshConn     model.Connection
+	version     string
+	suspended   map[string]*sshExecModel   // Sessions suspended with ~Z, by connection ID
+	scrollbacks map[string]*ssh.Scrollback // Output of the last session, by connection ID
+	jobs        *jobs.Manager              // Operations running in the background
+	checks      *healthRun                 // Background health check in progress
+	banner      bannerMsg                  // Pre-login banner waiting to be acknowledged
+	bcast       *ssh.Broadcast             // Running broadcast session
+	bcastTo     []model.Connection         // Hosts waiting for the broadcast confirmation
+	batch       *batchRun                  // Command running on the exec view's hosts
+	healthGen   int                        // Generation of the periodic health check, to drop stale ticks
+	stateErrs   <-chan error               // Failed background writes of connection status
 }
 
 // NewModel creates a new app model
 func NewModel(cfg *config.Manager) Model {
 	m := Model{
-		setup:    views.NewSetupModel(),
-		unlock:   views.NewUnlockModel(),
-		list:     views.NewListModel(),
-		form:     views.NewFormModel(cfg.GroupNames()),
-		confirm:  views.NewConfirmModel(),
-		help:     views.NewHelpModel(),
-		settings: views.NewSettingsModel(cfg),
-		hostkey:  views.NewHostKeyModel(),
-		config:   cfg,
-		keys:     DefaultKeyMap,
-		version:  "1.2.0",
+		setup:       views.NewSetupModel(),
+		unlock:      views.NewUnlockModel(),
+		list:        views.NewListModel(),
+		form:        views.NewFormModel(cfg.GroupNames()),
+		confirm:     views.NewConfirmModel(),
+		help:        views.NewHelpModel(),
+		settings:    views.NewSettingsModel(cfg),
+		hostkey:     views.NewHostKeyModel(),
+		status:      views.NewStatusBar(),
+		config:      cfg,
+		keys:        DefaultKeyMap,
+		version:     "1.2.0",
+		suspended:   make(map[string]*sshExecModel),
+		scrollbacks: make(map[string]*ssh.Scrollback),
+		jobs:        jobs.NewManager(),
+		// Status updates after each connect and test are written in the
+		// background, so the disk does not hold up the screen
+		stateErrs: cfg.DeferStateWrites(),
 	}
 
+	m.list.SetGroups(cfg.Groups())
+	settings := cfg.GetSettings()
+	m.list.ApplySettings(settings)
+	m.form.SetDefaults(settings)
+	m.setup.SetPolicy(settings.PasswordPolicy)
+
 	// Determine initial state
 	if cfg.IsFirstRun() {
 		m.state = ViewSetup
 	} else if !cfg.IsUnlocked() {
 		// Password protection is enabled, need to unlock
 		m.state = ViewUnlock
+		m.unlock.SetAttempts(cfg.UnlockAttempts())
 	} else {
 		// Auto-unlock if password protection is disabled
 		_ = cfg.AutoUnlockIfNeeded()
@@ -172,15 +308,34 @@ func (m *Model) SetVersion(version string) {
 
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
-	return nil
+	return tea.Batch(m.healthTick(), m.unlockTick(), lockTick(), waitStateError(m.stateErrs))
 }
 
 // Update handles messages
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	if nm, ok := next.(Model); ok {
+		// Whatever view msg led to, a locked config shows the unlock view
+		if nm.state != ViewUnlock && !nm.config.IsUnlocked() {
+			nm = nm.locked()
+			next = nm
+		}
+		// Start the timeout of a toast shown while handling msg
+		if expire := nm.status.Schedule(); expire != nil {
+			return nm, tea.Batch(cmd, expire)
+		}
+	}
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m.status = m.status.Update(msg)
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.status.SetSize(msg.Width)
 		m.setup.SetSize(msg.Width, msg.Height)
 		m.unlock.SetSize(msg.Width, msg.Height)
 		m.list.SetSize(msg.Width, msg.Height)
@@ -188,14 +343,37 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.confirm.SetSize(msg.Width, msg.Height)
 		m.help.SetSize(msg.Width, msg.Height)
 		m.hostkey.SetSize(msg.Width, msg.Height)
+		m.wizard.SetSize(msg.Width, msg.Height)
+		m.broadcast.SetSize(msg.Width, msg.Height)
+		m.filters.SetSize(msg.Width, msg.Height)
+		m.history.SetSize(msg.Width, msg.Height)
+		m.exec.SetSize(msg.Width, msg.Height)
+		m.local.SetSize(msg.Width, msg.Height)
+		m.scrollback.SetSize(msg.Width, msg.Height)
+		m.plugins.SetSize(msg.Width, msg.Height)
+		m.details.SetSize(msg.Width, msg.Height)
+		if m.bcast != nil {
+			m.bcast.Resize(msg.Width, msg.Height-views.BroadcastChrome)
+		}
 		return m, nil
 
 	case tea.KeyMsg:
-		// Global quit on ctrl+c
-		if msg.String() == "ctrl+c" {
+		// Global quit on ctrl+c, except while it is typed into hosts
+		if msg.String() == "ctrl+c" && m.state != ViewBroadcast {
 			return m, tea.Quit
 		}
 
+		// Lock from any view but those where keys go to hosts or the
+		// config isn't unlocked yet
+		if key.Matches(msg, m.keys.Lock) && m.state != ViewBroadcast && m.state != ViewSetup && m.state != ViewUnlock {
+			if err := m.config.Lock(); err != nil {
+				m.status.Toast(i18n.T("lock.unprotected"))
+				return m, nil
+			}
+			_ = keycache.Clear(m.config.Path())
+			return m.locked(), nil
+		}
+
 		// Handle based on current view
 		switch m.state {
 		case ViewSetup:
@@ -214,32 +392,156 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateSettings(msg)
 		case ViewHostKey:
 			return m.updateHostKey(msg)
+		case ViewWizard:
+			return m.updateWizard(msg)
+		case ViewBanner:
+			return m.updateBanner(msg)
+		case ViewBroadcast:
+			return m.updateBroadcast(msg)
+		case ViewFilters:
+			return m.updateFilters(msg)
+		case ViewHistory:
+			return m.updateHistory(msg)
+		case ViewExec:
+			return m.updateExec(msg)
+		case ViewLocal:
+			return m.updateLocal(msg)
+		case ViewScrollback:
+			return m.updateScrollback(msg)
+		case ViewPlugins:
+			return m.updatePlugins(msg)
+		case ViewDetails:
+			return m.updateDetails(msg)
 		}
 
-	case sshDoneMsg:
-		m.state = ViewList
+	case jobMsg:
+		return m.updateJob(msg)
+
+	case batchTickMsg:
+		if msg.run != m.batch {
+			return m, nil
+		}
+		m.exec.Tick()
+		return m, m.batch.tick()
+
+	case broadcastMsg:
+		if msg.bcast != m.bcast {
+			return m, nil // From a broadcast that has been closed
+		}
+		m.broadcast.SetHosts(m.bcast.Hosts())
+		return m, waitBroadcast(m.bcast)
+
+	case bannerMsg:
+		if msg.err != nil && msg.local {
+			m.state = ViewList
+			m.status.Toast(fmt.Sprintf("%s: %v", i18n.T("common.error"), msg.err))
+			return m, nil
+		}
 		if msg.err != nil {
+			m.state = ViewList
 			m.err = msg.err
-			m.statusMsg = fmt.Sprintf(i18n.T("common.conn_error"), msg.err.Error())
-			_ = m.config.UpdateConnectionStatus(m.sshConn.ID, model.ConnStatusFailed)
-		} else {
-			m.statusMsg = i18n.T("common.disconnected")
-			_ = m.config.UpdateConnectionStatus(m.sshConn.ID, model.ConnStatusSuccess)
+			m.status.Toast(fmt.Sprintf(i18n.T("common.conn_error"), msg.err.Error()))
+			_ = m.config.UpdateConnectionStatus(msg.conn.ID, model.ConnStatusFailed)
+			m.list.SetConnections(m.config.Connections())
+			return m, nil
+		}
+		if msg.banner == "" {
+			return m, m.execSSH(msg.session())
+		}
+		m.banner = msg
+		m.state = ViewBanner
+		return m, nil
+
+	case localDoneMsg:
+		if code := shell.ExitCode(msg.err); code > 0 {
+			m.status.Toast(fmt.Sprintf(i18n.T("local.exit"), code))
+		} else if code < 0 {
+			m.status.Toast(fmt.Sprintf("%s: %v", i18n.T("common.error"), msg.err))
 		}
-		m.list.SetConnections(m.config.Connections())
 		return m, nil
 
-	case testResultMsg:
+	case sshDoneMsg:
 		m.state = ViewList
-		if msg.err != nil {
-			m.statusMsg = fmt.Sprintf("%s: %s - %s", i18n.T("health.result.fail"), msg.conn.Name, msg.err.Error())
-			_ = m.config.UpdateConnectionStatus(msg.conn.ID, model.ConnStatusFailed)
+		if errors.Is(msg.err, ssh.ErrSuspended) {
+			m.suspended[m.sshConn.ID] = msg.exec
+			m.status.Toast(fmt.Sprintf(i18n.T("session.suspended"), m.sshConn.Name))
+			return m, nil
+		}
+		if msg.exec.terminal == nil {
+			// The pre_connect command failed, so the session never started
+			m.status.Toast(fmt.Sprintf("%s: %v", i18n.T("common.error"), msg.err))
+			return m, nil
+		}
+		if facts := msg.exec.terminal.Facts(); facts != nil {
+			_ = m.config.SetFacts(m.sshConn.ID, *facts)
+		}
+		if scrollback := msg.exec.terminal.Scrollback(); scrollback != nil {
+			m.scrollbacks[m.sshConn.ID] = scrollback
+		}
+		if errors.Is(msg.err, ssh.ErrDisconnected) {
+			m.status.Toast(fmt.Sprintf(i18n.T("session.closed"), m.sshConn.Name))
+		} else if code, exited := ssh.ExitStatus(msg.err); exited {
+			// The shell ended normally, whatever its exit status
+			m.status.Toast(fmt.Sprintf(i18n.T("session.summary"), m.sshConn.Name, code, model.ShortDuration(msg.duration)))
+			_ = m.config.RecordSession(m.sshConn.ID, model.SessionRecord{
+				StartedAt: time.Now().Add(-msg.duration),
+				Duration:  msg.duration,
+				ExitCode:  code,
+				Address:   msg.exec.terminal.Address(),
+			})
 		} else {
-			m.statusMsg = fmt.Sprintf("%s: %s", i18n.T("health.result.success"), msg.conn.Name)
-			_ = m.config.UpdateConnectionStatus(msg.conn.ID, model.ConnStatusSuccess)
+			m.err = msg.err
+			m.status.Toast(fmt.Sprintf(i18n.T("common.conn_error"), msg.err.Error()))
+			_ = m.config.UpdateConnectionStatus(m.sshConn.ID, model.ConnStatusFailed)
+		}
+		if msg.exec.hookErr != nil {
+			// Outlasts the summary: what post_disconnect should have done is left undone
+			m.status.Toast(fmt.Sprintf("%s: %v", i18n.T("common.error"), msg.exec.hookErr))
 		}
 		m.list.SetConnections(m.config.Connections())
 		return m, nil
+
+	case views.RevealTimeoutMsg:
+		// Each view ignores the timeouts of the other
+		m.form, _ = m.form.Update(msg)
+		m.details = m.details.RevealTimeout(msg)
+		return m, nil
+
+	case formTestMsg:
+		if m.state == ViewForm {
+			m.form.SetTestResult(msg.elapsed, msg.err)
+		}
+		return m, nil
+
+	case lockTickMsg:
+		// gossh lock was run
+		if m.config.LockRequested() {
+			_ = m.config.Lock()
+		}
+		return m, lockTick()
+
+	case stateErrorMsg:
+		m.status.Toast(fmt.Sprintf(i18n.T("common.state_error"), msg.err))
+		return m, waitStateError(m.stateErrs)
+
+	case unlockTickMsg:
+		// Redraw the countdown until the next attempt may be made
+		if m.state == ViewUnlock {
+			return m, m.unlockTick()
+		}
+		return m, nil
+
+	case healthTickMsg:
+		if msg.gen != m.healthGen {
+			// Scheduled before the interval changed
+			return m, nil
+		}
+		tick := m.healthTick()
+		if m.state != ViewList || m.checks != nil {
+			return m, tick
+		}
+		next, cmd := m.checkAll(m.config.Connections())
+		return next, tea.Batch(cmd, tick)
 	}
 
 	return m, nil
@@ -263,10 +565,7 @@ func (m Model) updateSetup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					m.err = err
 					return m, nil
 				}
-				m.state = ViewList
-				m.list.SetConnections(m.config.Connections())
-				m.statusMsg = i18n.T("setup.complete")
-				m.err = nil
+				m.finishSetup()
 				return m, nil
 			}
 			// User chose to enable password protection, proceed to password entry
@@ -286,15 +585,96 @@ func (m Model) updateSetup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		m.finishSetup()
+		return m, nil
+
+	default:
+		var cmd tea.Cmd
+		m.setup, cmd = m.setup.Update(msg)
+		return m, cmd
+	}
+}
+
+// finishSetup leaves the setup view, offering the import wizard when
+// ~/.ssh/config or the local Tailscale daemon know hosts that are not
+// saved yet
+func (m *Model) finishSetup() {
+	m.status.Toast(i18n.T("setup.complete"))
+	m.err = nil
+	m.state = ViewList
+	m.list.SetConnections(m.config.Connections())
+
+	existing := m.config.Connections()
+	var found []model.Connection
+	var sources []string
+
+	if discovered, err := sshconfig.NewParser().ParseDefault(); err == nil {
+		newConns, _ := sshconfig.Merge(existing, discovered)
+		for i := range newConns {
+			// The wizard assigns groups; don't preselect "Imported"
+			newConns[i].Group = ""
+		}
+		if len(newConns) > 0 {
+			found = append(found, newConns...)
+			sources = append(sources, "~/.ssh/config")
+		}
+	}
+
+	// tailscaled answers on a local socket; when it isn't running the
+	// dial fails at once, and the timeout only bounds a hung daemon
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if peers, err := discovery.TailscalePeers(ctx, ""); err == nil {
+		added, _, _ := discovery.MergeTailnet(append(slices.Clone(existing), found...),
+			discovery.TailscaleConnections(peers, localUser()))
+		if len(added) > 0 {
+			found = append(found, added...)
+			sources = append(sources, "Tailscale")
+		}
+	}
+
+	if len(found) == 0 {
+		return
+	}
+
+	m.wizard = views.NewWizardModel(strings.Join(sources, ", "), found, m.config.GroupNames())
+	m.wizard.SetSize(m.width, m.height)
+	m.state = ViewWizard
+}
+
+// localUser returns the local user name, the default login for
+// discovered hosts that don't name one
+func localUser() string {
+	u, err := user.Current()
+	if err != nil {
+		return os.Getenv("USER")
+	}
+	return u.Username
+}
+
+func (m Model) updateWizard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
 		m.state = ViewList
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		imported := 0
+		for _, conn := range m.wizard.Selected() {
+			if err := m.config.AddConnection(conn); err != nil {
+				m.err = err
+				continue
+			}
+			imported++
+		}
+		m.status.Toast(fmt.Sprintf(i18n.T("import.complete"), imported, m.wizard.Total()-imported))
 		m.list.SetConnections(m.config.Connections())
-		m.statusMsg = i18n.T("setup.complete")
-		m.err = nil
+		m.state = ViewList
 		return m, nil
 
 	default:
 		var cmd tea.Cmd
-		m.setup, cmd = m.setup.Update(msg)
+		m.wizard, cmd = m.wizard.Update(msg)
 		return m, cmd
 	}
 }
@@ -305,21 +685,20 @@ func (m Model) updateUnlock(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, tea.Quit
 
 	case key.Matches(msg, m.keys.Enter):
+		if m.unlock.Waiting() {
+			return m, nil
+		}
 		password := m.unlock.GetPassword()
 		if err := m.config.Unlock(password); err != nil {
-			m.unlock.IncrementAttempts()
 			m.unlock.SetError(err)
 			m.unlock.Reset()
-
-			if m.unlock.MaxAttemptsReached() {
-				return m, tea.Quit
-			}
-			return m, nil
+			m.unlock.SetAttempts(m.config.UnlockAttempts())
+			return m, m.unlockTick()
 		}
 
 		m.state = ViewList
 		m.list.SetConnections(m.config.Connections())
-		m.statusMsg = i18n.T("common.success")
+		m.status.Toast(i18n.T("common.success"))
 		m.err = nil
 		return m, nil
 
@@ -345,6 +724,13 @@ func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				return m, m.connectSSH(conn)
 			}
 			return m, nil
+		case key.Matches(msg, m.keys.TestAll):
+			// Only the connections matching the search
+			return m.checkAll(m.list.Visible())
+		case key.Matches(msg, m.keys.Broadcast):
+			return m.confirmBroadcast(m.list.Visible())
+		case key.Matches(msg, m.keys.Exec):
+			return m.openExec(m.list.Visible())
 		default:
 			var cmd tea.Cmd
 			m.list, cmd = m.list.Update(msg)
@@ -362,12 +748,14 @@ func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case key.Matches(msg, m.keys.Add):
 		m.form.Reset()
+		m.form.SetKnownTags(m.config.TagNames())
 		m.state = ViewForm
 		return m, nil
 
 	case key.Matches(msg, m.keys.Edit):
 		if conn, ok := m.list.Selected(); ok {
 			m.form.Reset()
+			m.form.SetKnownTags(m.config.TagNames())
 			m.form.SetConnection(conn)
 			m.state = ViewForm
 		}
@@ -375,6 +763,9 @@ func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case key.Matches(msg, m.keys.Delete):
 		if conn, ok := m.list.Selected(); ok {
+			if settings := m.config.GetSettings(); !settings.ConfirmDelete() {
+				return m.trash(conn.ID), nil
+			}
 			m.deleteID = conn.ID
 			m.confirm.SetMessage(i18n.T("confirm.delete"), fmt.Sprintf("%s '%s'?", i18n.T("confirm.delete.msg"), conn.Name))
 			m.state = ViewConfirm
@@ -390,8 +781,7 @@ func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case key.Matches(msg, m.keys.Help):
-		m.state = ViewHelp
-		return m, nil
+		return m.showHelp(m.keys.helpSections()...)
 
 	case key.Matches(msg, m.keys.Settings):
 		m.settings = views.NewSettingsModel(m.config)
@@ -402,12 +792,117 @@ func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, m.keys.Test):
 		if conn, ok := m.list.Selected(); ok {
 			m.sshConn = conn
-			m.statusMsg = fmt.Sprintf("%s: %s", i18n.T("health.testing"), conn.Name)
+			m.status.Toast(fmt.Sprintf("%s: %s", i18n.T("health.testing"), conn.Name))
 			m.state = ViewTesting
 			return m, m.testConnection(conn)
 		}
 		return m, nil
 
+	case key.Matches(msg, m.keys.TestAll):
+		return m.checkAll(m.config.Connections())
+
+	case key.Matches(msg, m.keys.Broadcast):
+		return m.confirmBroadcast(m.list.Visible())
+
+	case key.Matches(msg, m.keys.Exec):
+		return m.openExec(m.list.Visible())
+
+	case key.Matches(msg, m.keys.Copy):
+		if conn, ok := m.list.Selected(); ok {
+			command := conn.SSHCommand()
+			if err := clipboard.Copy(command); err != nil {
+				m.status.Toast(fmt.Sprintf("%s: %v", i18n.T("list.copy_failed"), err))
+			} else {
+				m.status.Toast(fmt.Sprintf(i18n.T("list.copied"), command))
+			}
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Local):
+		m.local = views.NewLocalModel()
+		m.local.SetSize(m.width, m.height)
+		m.state = ViewLocal
+		return m, m.local.Init()
+
+	case key.Matches(msg, m.keys.History):
+		if conn, ok := m.list.Selected(); ok {
+			m.history = views.NewHistoryModel(m.config, conn)
+			m.history.SetSize(m.width, m.height)
+			m.state = ViewHistory
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Scrollback):
+		conn, ok := m.list.Selected()
+		if !ok {
+			return m, nil
+		}
+		scrollback, ok := m.scrollbacks[conn.ID]
+		switch {
+		case ok:
+			m.scrollback = views.NewScrollbackModel(conn.Name, scrollback)
+			m.scrollback.SetSize(m.width, m.height)
+			m.state = ViewScrollback
+		case m.config.GetSettings().ScrollbackKB == 0:
+			m.status.Toast(i18n.T("scrollback.off"))
+		default:
+			m.status.Toast(fmt.Sprintf(i18n.T("scrollback.none"), conn.Name))
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Details):
+		if conn, ok := m.list.Selected(); ok {
+			m.details = views.NewDetailsModel(m.config, conn)
+			m.details.SetSize(m.width, m.height)
+			m.state = ViewDetails
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Plugins):
+		conn, ok := m.list.Selected()
+		if !ok {
+			return m, nil
+		}
+		dir, err := config.PluginsDir()
+		if err != nil {
+			m.status.Toast(fmt.Sprintf("%s: %v", i18n.T("common.error"), err))
+			return m, nil
+		}
+		list, err := plugins.List(dir)
+		if err != nil {
+			m.status.Toast(fmt.Sprintf("%s: %v", i18n.T("common.error"), err))
+			return m, nil
+		}
+		m.sshConn = conn
+		m.plugins = views.NewPluginMenuModel(list, dir, conn.Name)
+		m.plugins.SetSize(m.width, m.height)
+		m.state = ViewPlugins
+		return m, nil
+
+	case key.Matches(msg, views.DefaultListKeyMap.Filters):
+		m.filters = views.NewFilterMenuModel(m.config, m.list.Filter())
+		m.filters.SetSize(m.width, m.height)
+		m.state = ViewFilters
+		return m, nil
+
+	case key.Matches(msg, views.DefaultListKeyMap.Layout):
+		m.list.ToggleLayout()
+		if err := m.config.SetListLayout(m.list.Layout()); err != nil {
+			m.err = err
+		}
+		return m, nil
+
+	case m.list.Layout() == model.LayoutTable && key.Matches(msg, views.DefaultListKeyMap.Sort, views.DefaultListKeyMap.Reverse):
+		if key.Matches(msg, views.DefaultListKeyMap.Sort) {
+			m.list.CycleSort()
+		} else {
+			m.list.ReverseSort()
+		}
+		if err := m.config.SetListSort(m.list.Sort()); err != nil {
+			m.err = err
+		}
+		return m, nil
+
 	default:
 		var cmd tea.Cmd
 		m.list, cmd = m.list.Update(msg)
@@ -417,29 +912,55 @@ func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 func (m Model) updateForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
+	case m.form.Captures(msg):
+		// e.g. enter in a multi-line field, esc in the key file picker
+		var cmd tea.Cmd
+		m.form, cmd = m.form.Update(msg)
+		return m, cmd
+
+	case key.Matches(msg, views.DefaultFormKeyMap.Help):
+		return m.showHelp(views.DefaultFormKeyMap.HelpSection())
+
 	case key.Matches(msg, m.keys.Back):
 		m.state = ViewList
 		return m, nil
 
+	case key.Matches(msg, views.DefaultFormKeyMap.Reveal):
+		shown, cmd := m.form.ToggleReveal()
+		name := m.form.Name()
+		for _, field := range shown {
+			_ = m.config.RecordReveal(name, field)
+		}
+		return m, cmd
+
+	case key.Matches(msg, views.DefaultFormKeyMap.Test):
+		conn, err := m.form.GetConnection()
+		if err != nil {
+			// The form shows the invalid fields
+			return m, nil
+		}
+		m.form.StartTest()
+		return m, m.testFormConnection(conn)
+
 	case key.Matches(msg, m.keys.Enter):
 		conn, err := m.form.GetConnection()
 		if err != nil {
-			m.err = err
+			// The form shows the invalid fields
 			return m, nil
 		}
 
 		if m.form.Editing {
 			if err := m.config.UpdateConnection(conn); err != nil {
-				m.err = err
+				m.form.SetError(err)
 				return m, nil
 			}
-			m.statusMsg = i18n.T("settings.saved")
+			m.status.Toast(i18n.T("settings.saved"))
 		} else {
 			if err := m.config.AddConnection(conn); err != nil {
-				m.err = err
+				m.form.SetError(err)
 				return m, nil
 			}
-			m.statusMsg = i18n.T("settings.saved")
+			m.status.Toast(i18n.T("settings.saved"))
 		}
 
 		m.list.SetConnections(m.config.Connections())
@@ -457,17 +978,22 @@ func (m Model) updateForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m Model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, m.keys.Back):
+		m.bcastTo = nil
+		m.state = ViewList
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter) && m.bcastTo != nil:
+		targets := m.bcastTo
+		m.bcastTo = nil
+		if m.confirm.IsConfirmed() {
+			return m.startBroadcast(targets)
+		}
 		m.state = ViewList
 		return m, nil
 
 	case key.Matches(msg, m.keys.Enter):
 		if m.confirm.IsConfirmed() {
-			if err := m.config.DeleteConnection(m.deleteID); err != nil {
-				m.err = err
-			} else {
-				m.statusMsg = i18n.T("common.success")
-				m.list.SetConnections(m.config.Connections())
-			}
+			m = m.trash(m.deleteID)
 		}
 		m.state = ViewList
 		return m, nil
@@ -479,23 +1005,134 @@ func (m Model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
-func (m Model) updateHelp(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	if key.Matches(msg, m.keys.Back) || key.Matches(msg, m.keys.Help) {
-		m.state = ViewList
+// trash moves a connection to the trash
+func (m Model) trash(id string) Model {
+	if err := m.config.DeleteConnection(id); err != nil {
+		m.err = err
+	} else {
+		m.status.Toast(i18n.T("list.trashed"))
+		m.list.SetConnections(m.config.Connections())
 	}
+	return m
+}
+
+// showHelp opens the help overlay over the current view, listing sections
+func (m Model) showHelp(sections ...views.HelpSection) (tea.Model, tea.Cmd) {
+	m.help.SetSections(sections...)
+	m.helpFrom = m.state
+	m.state = ViewHelp
 	return m, nil
 }
 
+func (m Model) updateHelp(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.help, cmd = m.help.Update(msg)
+	if m.help.Closed() {
+		m.state = m.helpFrom
+	}
+	return m, cmd
+}
+
 func (m Model) updateSettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, views.DefaultSettingsKeyMap.Help) && !m.settings.Typing() {
+		return m.showHelp(m.settings.HelpSections()...)
+	}
+
 	var cmd tea.Cmd
 	settingsModel, cmd := m.settings.Update(msg)
 	if sm, ok := settingsModel.(views.SettingsModel); ok {
 		m.settings = sm
 		// Check if user wants to go back
 		if m.settings.ShouldQuit() {
+			// Settings may have imported connections or recolored groups
+			m.list.SetConnections(m.config.Connections())
+			m.list.SetGroups(m.config.Groups())
+			settings := m.config.GetSettings()
+			m.list.ApplySettings(settings)
+			ssh.DefaultPool.SetIdleTimeout(settings.KeepWarm())
+			ssh.SetConnectTimeout(settings.ConnectTimeout())
+			ssh.SetKeepaliveInterval(settings.Keepalive())
+			ssh.SetTerminalDefaults(settings.Term, settings.Locale)
+			ssh.SetScrollbackSize(settings.Scrollback())
+			m.form = views.NewFormModel(m.config.GroupNames())
+			m.form.SetDefaults(settings)
+			m.form.SetSize(m.width, m.height)
 			m.state = ViewList
-			return m, nil
+			// The health check interval may have changed
+			m.healthGen++
+			return m, m.healthTick()
+		}
+	}
+	return m, cmd
+}
+
+func (m Model) updateFilters(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.filters, cmd = m.filters.Update(msg)
+	if m.filters.Done() {
+		if query := m.filters.Chosen(); query != "" {
+			m.list.SetFilter(query)
+		}
+		m.state = ViewList
+	}
+	return m, cmd
+}
+
+func (m Model) updatePlugins(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.plugins, cmd = m.plugins.Update(msg)
+	if !m.plugins.Done() {
+		return m, cmd
+	}
+	m.state = ViewList
+	plugin, ok := m.plugins.Chosen()
+	if !ok {
+		return m, nil
+	}
+	secrets := plugin.Trusted(m.config.GetSettings().PluginSecrets)
+	run, err := plugins.Command(plugin, plugins.Input{
+		Args:       []string{m.sshConn.Name},
+		ConfigPath: m.config.Path(),
+		Connection: plugins.NewConnection(m.sshConn, secrets),
+	})
+	if err != nil {
+		m.status.Toast(fmt.Sprintf("%s: %v", i18n.T("common.error"), err))
+		return m, nil
+	}
+	return m, tea.Exec(&localExecModel{cmd: run, wait: true}, func(err error) tea.Msg {
+		return localDoneMsg{err: err}
+	})
+}
+
+func (m Model) updateScrollback(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.scrollback, cmd = m.scrollback.Update(msg)
+	if m.scrollback.Done() {
+		m.scrollback = views.ScrollbackModel{}
+		m.state = ViewList
+	}
+	return m, cmd
+}
+
+func (m Model) updateDetails(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.details, cmd = m.details.Update(msg)
+	if m.details.Done() {
+		m.details = views.DetailsModel{}
+		m.list.SetConnections(m.config.Connections())
+		m.state = ViewList
+	}
+	return m, cmd
+}
+
+func (m Model) updateHistory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.history, cmd = m.history.Update(msg)
+	if m.history.Done() {
+		if m.history.Restored() {
+			m.list.SetConnections(m.config.Connections())
 		}
+		m.state = ViewList
 	}
 	return m, cmd
 }
@@ -512,47 +1149,659 @@ func (m Model) updateHostKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		// User rejected, go back to list
 		m.state = ViewList
-		m.statusMsg = i18n.T("hostkey.reject")
+		m.status.Toast(i18n.T("hostkey.reject"))
 	}
 
 	return m, cmd
 }
 
-// testResultMsg is sent when connection test completes
-type testResultMsg struct {
-	conn model.Connection
-	err  error
+func (m Model) updateBanner(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Enter):
+		m.state = ViewConnecting
+		return m, m.execSSH(m.banner.session())
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Quit):
+		m.banner.client.Close()
+		m.state = ViewList
+		m.status.Toast(fmt.Sprintf(i18n.T("session.closed"), m.banner.conn.Name))
+		// Undo what pre_connect did for the session that is not going to be
+		conn, hookList := m.banner.conn, m.config.Hooks()
+		return m, func() tea.Msg {
+			_ = postDisconnect(conn, hookList, nil)
+			return nil
+		}
+	}
+	return m, nil
 }
 
+// testConnection tests conn in the background, reporting the result as a
+// jobs.HostChecked event
 func (m Model) testConnection(conn model.Connection) tea.Cmd {
+	job := m.jobs.Start(jobs.KindTest, 1, func(ctx context.Context, job *jobs.Job) error {
+		start := time.Now()
+		err := ssh.CheckConnection(conn, 5*time.Second)
+		if ctx.Err() != nil {
+			return ctx.Err() // Locked meanwhile
+		}
+		job.SetProgress(1)
+		job.Emit(jobs.HostChecked{Result: ssh.HealthResult{Connection: conn, Latency: time.Since(start), Error: err}})
+		return nil
+	})
+	return waitJob(job)
+}
+
+// jobMsg carries an event of a background job
+type jobMsg struct {
+	job   *jobs.Job
+	event jobs.Event
+}
+
+// waitJob waits for the next event of job
+func waitJob(job *jobs.Job) tea.Cmd {
+	return func() tea.Msg {
+		e, ok := job.Next()
+		if !ok {
+			return nil
+		}
+		return jobMsg{job: job, event: e}
+	}
+}
+
+// updateJob handles an event of a background job and waits for the next
+// one. Jobs that have been replaced, such as a health check restarted with
+// other connections, are no longer followed.
+func (m Model) updateJob(msg jobMsg) (tea.Model, tea.Cmd) {
+	health := m.checks != nil && msg.job == m.checks.job
+	batch := m.batch != nil && msg.job == m.batch.job
+
+	switch e := msg.event.(type) {
+	case jobs.HostChecked:
+		result := e.Result
+		if msg.job.Kind() == jobs.KindTest {
+			if m.state == ViewTesting {
+				m.state = ViewList
+			}
+			if result.Error != nil {
+				m.status.Toast(fmt.Sprintf("%s: %s - %s", i18n.T("health.result.fail"), result.Connection.Name, result.Error.Error()))
+			} else {
+				m.status.Toast(fmt.Sprintf("%s: %s", i18n.T("health.result.success"), result.Connection.Name))
+			}
+			cmd := m.recordHealth(result.Connection, result.Error)
+			m.list.SetConnections(m.config.Connections())
+			return m, tea.Batch(cmd, waitJob(msg.job))
+		}
+		if !health {
+			return m, nil
+		}
+		if result.Error != nil {
+			m.checks.down++
+		} else {
+			m.checks.up++
+		}
+		m.list.SetChecking(result.Connection.ID, false)
+		cmd := m.recordHealth(result.Connection, result.Error)
+		m.list.SetConnections(m.config.Connections())
+		m.status.Toast(fmt.Sprintf(i18n.T("health.all.progress"), m.checks.up+m.checks.down, m.checks.total))
+		return m, tea.Batch(cmd, waitJob(msg.job))
+
+	case jobs.ExecProgress:
+		if !batch {
+			return m, nil // From a run whose view has been closed
+		}
+		m.exec.SetProgress(e.Progress)
+		return m, waitJob(msg.job)
+
+	case jobs.Done:
+		switch {
+		case health:
+			for _, conn := range m.checks.conns {
+				m.list.SetChecking(conn.ID, false)
+			}
+			m.status.Toast(fmt.Sprintf(i18n.T("health.all.done"), m.checks.total, m.checks.up, m.checks.down))
+			m.checks = nil
+		case batch:
+			return m.finishExec()
+		}
+	}
+	return m, nil
+}
+
+// formTestMsg is sent when a test of the values entered in the form completes
+type formTestMsg struct {
+	elapsed time.Duration
+	err     error
+}
+
+// testFormConnection logs in with the values entered in the form, before
+// they are saved. Known hosts must present the key on record, but nothing
+// is added to known_hosts.
+func (m Model) testFormConnection(conn model.Connection) tea.Cmd {
+	connections := m.config.Connections()
+	dispatcher := hooks.NewDispatcher(m.config.Hooks())
+	return func() tea.Msg {
+		start := time.Now()
+		if conn.IsTelnet() {
+			err := ssh.CheckConnection(conn, 5*time.Second)
+			return formTestMsg{elapsed: time.Since(start), err: err}
+		}
+
+		jumps, err := model.ResolveJumps(conn, connections)
+		if err != nil {
+			return formTestMsg{err: err}
+		}
+		conn.Jumps = jumps
+		hkm, err := ssh.NewHostKeyManager()
+		if err != nil {
+			return formTestMsg{err: err}
+		}
+		hkm.OnChange(func(result ssh.HostKeyResult) {
+			dispatcher.Fire(hooks.HostKeyChange(conn, result.OldKey, result.Fingerprint))
+		})
+		err = ssh.FullCheck(conn, ssh.VerifyHostKeyCallback(hkm))
+		return formTestMsg{elapsed: time.Since(start), err: err}
+	}
+}
+
+// recordHealth stores a health check result and fires hooks when the
+// connection went up or down
+func (m Model) recordHealth(conn model.Connection, err error) tea.Cmd {
+	status := model.ConnStatusSuccess
+	if err != nil {
+		status = model.ConnStatusFailed
+	}
+	_ = m.config.UpdateConnectionStatus(conn.ID, status)
+	previous, _ := m.config.UpdateHealthStatus(conn.ID, status)
+	if p, changed := hooks.HealthChange(conn, previous, status, err); changed {
+		return m.fireHooks(p)
+	}
+	return nil
+}
+
+// healthRun tracks a background check of many connections
+type healthRun struct {
+	job      *jobs.Job
+	conns    []model.Connection
+	total    int
+	up, down int
+}
+
+// checkAll tests conns concurrently in the background. The list icons
+// update as results arrive, so the interface stays usable meanwhile.
+func (m Model) checkAll(conns []model.Connection) (tea.Model, tea.Cmd) {
+	if len(conns) == 0 {
+		return m, nil
+	}
+	if m.checks != nil {
+		// Restart with the new selection
+		m.checks.job.Cancel()
+		for _, conn := range m.checks.conns {
+			m.list.SetChecking(conn.ID, false)
+		}
+	}
+
+	checker := ssh.NewHealthChecker(conns)
+	job := m.jobs.Start(jobs.KindHealth, len(conns), func(ctx context.Context, job *jobs.Job) error {
+		done := 0
+		for result := range checker.Start(ctx) {
+			done++
+			job.SetProgress(done)
+			job.Emit(jobs.HostChecked{Result: result})
+		}
+		return ctx.Err()
+	})
+	m.checks = &healthRun{
+		job:   job,
+		conns: conns,
+		total: len(conns),
+	}
+	for _, conn := range conns {
+		m.list.SetChecking(conn.ID, true)
+	}
+	m.status.Toast(fmt.Sprintf(i18n.T("health.all.progress"), 0, len(conns)))
+	return m, waitJob(job)
+}
+
+// locked returns to the unlock view after the config was locked, dropping
+// the connections and secrets the views hold
+func (m Model) locked() Model {
+	m.state = ViewUnlock
+	m.unlock.Reset()
+	m.unlock.SetError(nil)
+	m.unlock.SetAttempts(m.config.UnlockAttempts())
+	m.list.SetConnections(nil)
+	m.form.Reset()
+	// Views holding a connection, its password or an attachment's content
+	m.details = views.DetailsModel{}
+	m.history = views.HistoryModel{}
+	m.exec = views.ExecModel{}
+	m.sshConn = model.Connection{}
+	m.bcastTo = nil
+	if m.bcast != nil {
+		m.bcast.Close()
+		m.bcast = nil
+	}
+	// Session output may show secrets, and a suspended session would
+	// resume without the password
+	for id, c := range m.suspended {
+		delete(m.suspended, id)
+		go c.close()
+	}
+	clear(m.scrollbacks)
+	m.scrollback = views.ScrollbackModel{}
+	if m.banner.client != nil {
+		m.banner.client.Close()
+	}
+	m.banner = bannerMsg{}
+	// Nothing started before locking goes on, nor reports back
+	m.jobs.CancelAll()
+	m.checks = nil
+	m.batch = nil
+	m.err = nil
+	return m
+}
+
+// lockCheckInterval is how often the TUI looks for a gossh lock request
+const lockCheckInterval = 2 * time.Second
+
+// lockTickMsg checks whether gossh lock was run
+type lockTickMsg struct{}
+
+func lockTick() tea.Cmd {
+	return tea.Tick(lockCheckInterval, func(time.Time) tea.Msg {
+		return lockTickMsg{}
+	})
+}
+
+// stateErrorMsg reports a failed background write of connection status
+type stateErrorMsg struct {
+	err error
+}
+
+// waitStateError waits for the next failed write of connection status
+func waitStateError(errs <-chan error) tea.Cmd {
+	return func() tea.Msg {
+		return stateErrorMsg{<-errs}
+	}
+}
+
+// unlockTickMsg redraws the wait before the next unlock attempt
+type unlockTickMsg struct{}
+
+// unlockTick schedules the next redraw of the unlock countdown, while
+// unlocking has to wait after failed attempts
+func (m Model) unlockTick() tea.Cmd {
+	if !m.unlock.Waiting() {
+		return nil
+	}
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return unlockTickMsg{}
+	})
+}
+
+// healthTickMsg starts a periodic health check of all connections
+type healthTickMsg struct {
+	gen int
+}
+
+// healthTick schedules the next periodic health check, if the
+// health_check_minutes setting turns it on
+func (m Model) healthTick() tea.Cmd {
+	settings := m.config.GetSettings()
+	interval := settings.HealthCheck()
+	if interval <= 0 {
+		return nil
+	}
+	gen := m.healthGen
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return healthTickMsg{gen: gen}
+	})
+}
+
+// confirmBroadcast asks before typing into several hosts at once
+func (m Model) confirmBroadcast(conns []model.Connection) (tea.Model, tea.Cmd) {
+	if len(conns) == 0 {
+		return m, nil
+	}
+	m.bcastTo = conns
+	m.confirm.SetMessage(i18n.T("broadcast.confirm"), fmt.Sprintf(i18n.T("broadcast.confirm.msg"), len(conns)))
+	m.state = ViewConfirm
+	return m, nil
+}
+
+// startBroadcast opens shells on conns and mirrors keystrokes to them
+func (m Model) startBroadcast(conns []model.Connection) (tea.Model, tea.Cmd) {
+	m.bcast = ssh.NewBroadcast(conns)
+	m.bcast.Start(m.width, m.height-views.BroadcastChrome)
+	m.broadcast = views.NewBroadcastModel()
+	m.broadcast.SetSize(m.width, m.height)
+	m.broadcast.SetHosts(m.bcast.Hosts())
+	m.state = ViewBroadcast
+	return m, waitBroadcast(m.bcast)
+}
+
+func (m Model) updateBroadcast(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, broadcastNext):
+		m.broadcast.Next()
+	case key.Matches(msg, broadcastPrev):
+		m.broadcast.Prev()
+	case key.Matches(msg, broadcastToggle):
+		m.broadcast.ToggleSingle()
+	case key.Matches(msg, broadcastClose):
+		m.bcast.Close()
+		m.bcast = nil
+		m.state = ViewList
+		m.status.Toast(i18n.T("broadcast.closed"))
+	default:
+		if input := views.BroadcastInput(msg); input != nil {
+			m.bcast.Send(input, m.broadcast.Target())
+		}
+	}
+	return m, nil
+}
+
+// broadcastMsg is sent when a broadcast has new output or host states
+type broadcastMsg struct {
+	bcast *ssh.Broadcast
+}
+
+// waitBroadcast waits for the next broadcast update
+func waitBroadcast(b *ssh.Broadcast) tea.Cmd {
 	return func() tea.Msg {
-		err := ssh.QuickCheck(conn.Host, conn.Port, 5*time.Second)
-		return testResultMsg{conn: conn, err: err}
+		select {
+		case <-b.Updates():
+		case <-b.Done():
+		}
+		return broadcastMsg{bcast: b}
+	}
+}
+
+// batchRun tracks a command running on several hosts from the exec view
+type batchRun struct {
+	job     *jobs.Job
+	command string
+	results []ssh.BatchResult // Set once the job is done
+}
+
+// batchTickMsg turns the exec view's spinner
+type batchTickMsg struct {
+	run *batchRun
+}
+
+// tick schedules the next turn of the spinner
+func (r *batchRun) tick() tea.Cmd {
+	return tea.Tick(views.SpinnerInterval, func(time.Time) tea.Msg {
+		return batchTickMsg{run: r}
+	})
+}
+
+// openExec asks for a command to run on conns
+func (m Model) openExec(conns []model.Connection) (tea.Model, tea.Cmd) {
+	if len(conns) == 0 {
+		return m, nil
+	}
+	m.exec = views.NewExecModel(conns)
+	m.exec.SetSize(m.width, m.height)
+	m.state = ViewExec
+	return m, m.exec.Init()
+}
+
+// startExec runs the entered command on the exec view's hosts in the
+// background, following its progress on the view
+func (m Model) startExec(command string) (tea.Model, tea.Cmd) {
+	hosts := m.exec.Hosts()
+	run := &batchRun{command: command}
+	executor := ssh.NewBatchExecutor(hosts)
+	run.job = m.jobs.Start(jobs.KindExec, len(hosts), func(ctx context.Context, job *jobs.Job) error {
+		executor.SetProgress(func(p ssh.BatchProgress) {
+			job.SetProgress(p.Done)
+			job.Emit(jobs.ExecProgress{Progress: p})
+		})
+		run.results = executor.Execute(ctx, command)
+		return nil
+	})
+
+	m.batch = run
+	m.exec.Start()
+	return m, tea.Batch(waitJob(run.job), run.tick())
+}
+
+// finishExec shows the results of the exec view's run and keeps it for
+// retrying from the command line
+func (m Model) finishExec() (tea.Model, tea.Cmd) {
+	run := m.batch
+	m.batch = nil
+	m.exec.Finish()
+
+	var failed []string
+	for _, r := range run.results {
+		if r.Error != nil {
+			failed = append(failed, r.Connection.Name)
+		}
+	}
+	if err := m.config.RecordRun(model.NewRun(run.command, len(run.results), failed)); err != nil {
+		m.err = err
+	}
+	m.status.Toast(fmt.Sprintf(i18n.T("exec.done"), len(run.results)-len(failed), len(failed)))
+	return m, nil
+}
+
+func (m Model) updateExec(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back) && m.exec.Running():
+		// The hosts stop their commands and the results still come in
+		m.batch.job.Cancel()
+		m.status.Toast(i18n.T("exec.stopping"))
+		return m, nil
+	case key.Matches(msg, m.keys.Enter) && m.exec.Command() != "":
+		return m.startExec(m.exec.Command())
+	}
+
+	var cmd tea.Cmd
+	m.exec, cmd = m.exec.Update(msg)
+	if m.exec.Done() {
+		m.state = ViewList
+	}
+	return m, cmd
+}
+
+func (m Model) updateLocal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.local, cmd = m.local.Update(msg)
+	if !m.local.Done() {
+		return m, cmd
+	}
+	m.state = ViewList
+	if !m.local.Run() {
+		return m, nil
+	}
+	line := m.local.Command()
+	c := &localExecModel{cmd: shell.Command(line, ""), wait: line != ""}
+	return m, tea.Exec(c, func(err error) tea.Msg {
+		return localDoneMsg{err: err}
+	})
+}
+
+// localDoneMsg is sent when a local command or shell has ended
+type localDoneMsg struct {
+	err error
+}
+
+// localExecModel implements tea.ExecCommand for a local command or a
+// plugin. The command's output stays up until Enter is pressed, as the
+// interface takes the screen back straight away; a shell is left with exit.
+type localExecModel struct {
+	cmd  *exec.Cmd
+	wait bool // Wait for Enter once the command is done
+}
+
+func (c *localExecModel) Run() error {
+	err := c.cmd.Run()
+	if c.wait {
+		fmt.Print("\n" + i18n.T("local.return"))
+		_, _ = bufio.NewReader(os.Stdin).ReadString('\n')
+	}
+	return err
+}
+
+func (c *localExecModel) SetStdin(r io.Reader)  {}
+func (c *localExecModel) SetStdout(w io.Writer) {}
+func (c *localExecModel) SetStderr(w io.Writer) {}
+
+// fireHooks runs the configured hooks in the background. Failures are
+// ignored so a broken webhook never disturbs the interface.
+func (m Model) fireHooks(p hooks.Payload) tea.Cmd {
+	dispatcher := hooks.NewDispatcher(m.config.Hooks())
+	return func() tea.Msg {
+		dispatcher.Fire(p)
+		return nil
 	}
 }
 
 // sshDoneMsg is sent when SSH session ends
 type sshDoneMsg struct {
-	err error
+	err      error
+	duration time.Duration
+	exec     *sshExecModel
 }
 
+// connectSSH opens a session, or resumes the connection's suspended one.
+// Connections that show the server's banner are dialed first so it can be
+// read before the terminal takes over the screen.
 func (m Model) connectSSH(conn model.Connection) tea.Cmd {
-	c := &sshExecModel{
-		conn: conn,
+	c, ok := m.suspended[conn.ID]
+	if ok {
+		delete(m.suspended, conn.ID)
+	} else {
+		if conn.ShowBanner && !conn.IsTelnet() {
+			return fetchBanner(conn, m.config.Hooks())
+		}
+		c = &sshExecModel{
+			conn: conn,
+		}
 	}
+	return m.execSSH(c)
+}
+
+// execSSH hands the screen over to an SSH session
+func (m Model) execSSH(c *sshExecModel) tea.Cmd {
+	c.hooks = m.config.Hooks()
 	return tea.Exec(c, func(err error) tea.Msg {
-		return sshDoneMsg{err: err}
+		return sshDoneMsg{err: err, duration: time.Since(c.started), exec: c}
 	})
 }
 
+// bannerMsg is sent once a connection that shows its banner is dialed
+type bannerMsg struct {
+	conn   model.Connection
+	client *ssh.Client
+	banner string
+	err    error
+	local  bool // err is the pre_connect command's, the host was not dialed
+}
+
+// session returns the SSH session to run after the banner, holding on to
+// the dialed connection so the terminal reuses it
+func (msg bannerMsg) session() *sshExecModel {
+	conn := msg.conn
+	conn.ShowBanner = false // Already shown
+	return &sshExecModel{conn: conn, pinned: msg.client, preConnected: true}
+}
+
+// fetchBanner dials conn for its banner, once pre_connect has run
+func fetchBanner(conn model.Connection, hookList []model.Hook) tea.Cmd {
+	return func() tea.Msg {
+		if err := preConnect(conn, hookList); err != nil {
+			return bannerMsg{conn: conn, err: err, local: true}
+		}
+		client := ssh.NewClient(conn)
+		if err := client.Connect(); err != nil {
+			return bannerMsg{conn: conn, err: err}
+		}
+		return bannerMsg{conn: conn, client: client, banner: client.Banner()}
+	}
+}
+
 // sshExecModel implements tea.ExecCommand for SSH connections
 type sshExecModel struct {
-	conn model.Connection
+	conn         model.Connection
+	terminal     *ssh.Terminal
+	started      time.Time
+	pinned       *ssh.Client  // Connection dialed before the session, released once it starts
+	hooks        []model.Hook // Fired around the session with its pre_connect and post_disconnect
+	preConnected bool         // pre_connect ran before the banner was fetched
+	hookErr      error        // The post_disconnect command failed
 }
 
 func (c *sshExecModel) Run() error {
-	terminal := ssh.NewTerminal(c.conn)
-	return terminal.Run()
+	var err error
+	if c.terminal != nil && c.terminal.Suspended() {
+		err = c.terminal.Resume()
+	} else {
+		if !c.preConnected {
+			if err := preConnect(c.conn, c.hooks); err != nil {
+				return err
+			}
+		}
+		c.started = time.Now()
+		c.terminal = ssh.NewTerminal(c.conn)
+		err = c.terminal.Run()
+		if c.pinned != nil {
+			c.pinned.Close()
+			c.pinned = nil
+		}
+	}
+	if !errors.Is(err, ssh.ErrSuspended) {
+		c.hookErr = postDisconnect(c.conn, c.hooks, err)
+	}
+	return err
+}
+
+// preConnect fires the session.pre_connect hooks for a session about to
+// start on conn and runs the connection's pre_connect command, whose
+// failure keeps the session from starting
+func preConnect(conn model.Connection, hookList []model.Hook) error {
+	p := hooks.PreConnect(conn)
+	hooks.NewDispatcher(hookList).Fire(p)
+	if conn.PreConnect == "" {
+		return nil
+	}
+	if err := hooks.RunConnectionCommand(conn.PreConnect, p); err != nil {
+		return fmt.Errorf(i18n.T("session.pre_connect.failed"), err)
+	}
+	return nil
+}
+
+// postDisconnect runs the post_disconnect command of conn, whose session
+// ended with err, and fires the session.post_disconnect hooks. Only the
+// command's failure is returned; hooks never disturb the interface.
+func postDisconnect(conn model.Connection, hookList []model.Hook, err error) error {
+	code, cause := 255, error(nil) // ~. exits with 255, like OpenSSH
+	if !errors.Is(err, ssh.ErrDisconnected) {
+		var exited bool
+		if code, exited = ssh.ExitStatus(err); !exited {
+			code, cause = -1, err
+		}
+	}
+	p := hooks.PostDisconnect(conn, code, cause)
+	var cmdErr error
+	if conn.PostDisconnect != "" {
+		if err := hooks.RunConnectionCommand(conn.PostDisconnect, p); err != nil {
+			cmdErr = fmt.Errorf(i18n.T("session.post_disconnect.failed"), err)
+		}
+	}
+	hooks.NewDispatcher(hookList).Fire(p)
+	return cmdErr
+}
+
+// close ends a suspended session, as ~. would, running its
+// post_disconnect command and hooks
+func (c *sshExecModel) close() {
+	if c.terminal == nil {
+		return
+	}
+	_ = c.terminal.Close()
+	_ = postDisconnect(c.conn, c.hooks, ssh.ErrDisconnected)
 }
 
 func (c *sshExecModel) SetStdin(r io.Reader)  {}
@@ -571,23 +1820,53 @@ func (m Model) View() string {
 	case ViewConfirm:
 		return m.confirm.View()
 	case ViewHelp:
-		return m.help.View()
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.help.View())
 	case ViewSettings:
 		return m.settings.View()
 	case ViewHostKey:
 		return m.hostkey.View()
+	case ViewWizard:
+		return m.wizard.View()
 	case ViewConnecting:
 		return fmt.Sprintf(i18n.T("common.connecting"), m.sshConn.Host)
 	case ViewTesting:
 		return fmt.Sprintf("%s: %s", i18n.T("health.testing"), m.sshConn.Name)
+	case ViewBroadcast:
+		return m.broadcast.View()
+	case ViewFilters:
+		return m.filters.View()
+	case ViewPlugins:
+		return m.plugins.View()
+	case ViewHistory:
+		return m.history.View()
+	case ViewExec:
+		return m.exec.View()
+	case ViewLocal:
+		return m.local.View()
+	case ViewScrollback:
+		return m.scrollback.View()
+	case ViewDetails:
+		return m.details.View()
+	case ViewBanner:
+		var b strings.Builder
+		b.WriteString(styles.TitleStyle.Render(fmt.Sprintf(i18n.T("banner.title"), m.banner.conn.Host)))
+		b.WriteString("\n\n")
+		b.WriteString(strings.TrimRight(m.banner.banner, "\r\n"))
+		b.WriteString("\n\n")
+		b.WriteString(styles.HelpStyle.Render(i18n.T("banner.help")))
+		return views.Layout{Width: m.width, Height: m.height}.Dialog(b.String())
 	default:
 		view := m.list.View()
-		if m.statusMsg != "" {
-			view += "\n" + styles.DimStyle.Render(m.statusMsg)
-		}
 		if m.err != nil {
-			view += "\n" + styles.ErrorStyle.Render(i18n.T("common.error")+": "+m.err.Error())
+			view += "\n" + styles.ErrorStyle.Render(i18n.T("common.error")+": "+views.ErrorText(m.err))
 		}
+		view += "\n" + m.status.View(views.StatusInfo{
+			Profile:   config.ContractHome(m.config.Path()),
+			Protected: m.config.IsPasswordProtected(),
+			Sessions:  len(m.suspended),
+			Jobs:      len(m.jobs.Running()),
+			Filter:    m.list.Filter(),
+		})
 		return view
 	}
 }
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gossh/internal/config"
+	"gossh/internal/model"
+	"gossh/internal/ui/views"
+)
+
+func TestLockedClearsSecrets(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if err := cfg.SetupMasterPassword("correct horse battery"); err != nil {
+		t.Fatalf("SetupMasterPassword failed: %v", err)
+	}
+	conn := model.NewConnection()
+	conn.Name = "web"
+	conn.Host = "10.0.0.1"
+	conn.User = "root"
+	conn.Password = "hunter2-plaintext"
+	if err := cfg.AddConnection(conn); err != nil {
+		t.Fatalf("AddConnection failed: %v", err)
+	}
+	conn, _ = cfg.GetConnection(conn.ID)
+
+	m := NewModel(cfg)
+	m.details = views.NewDetailsModel(cfg, conn)
+	m.history = views.NewHistoryModel(cfg, conn)
+	m.exec = views.NewExecModel([]model.Connection{conn})
+	m.sshConn = conn
+	m.suspended[conn.ID] = &sshExecModel{conn: conn}
+	if !strings.Contains(fmt.Sprintf("%+v", m), conn.Password) {
+		t.Fatal("the views do not hold the password before locking")
+	}
+
+	if err := cfg.Lock(); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	m = m.locked()
+	if strings.Contains(fmt.Sprintf("%+v", m), conn.Password) {
+		t.Error("the password is still held after locking")
+	}
+	if len(m.suspended) != 0 {
+		t.Errorf("suspended sessions after locking = %d, want none", len(m.suspended))
+	}
+}
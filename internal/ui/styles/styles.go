@@ -2,6 +2,7 @@ package styles
 
 import (
 	"github.com/charmbracelet/lipgloss"
+	"gossh/internal/model"
 )
 
 var (
@@ -128,3 +129,51 @@ var (
 				Padding(0, 2).
 				MarginRight(1)
 )
+
+// SetTheme switches to the colors of a theme in model.Themes: "dark", the
+// default, for dark terminal backgrounds or "light" for light ones. Other
+// names keep the current colors.
+func SetTheme(theme string) {
+	statusBg := lipgloss.Color("#333333")
+	switch theme {
+	case "dark":
+		FgColor = lipgloss.Color("#EAEAEA")
+		BgColor = lipgloss.Color("#1A1A2E")
+	case "light":
+		FgColor = lipgloss.Color("#1A1A2E")
+		BgColor = lipgloss.Color("#FAFAFA")
+		statusBg = lipgloss.Color("#DDDDDD")
+	default:
+		return
+	}
+
+	// Text on the purple and grey backgrounds of focused inputs and buttons
+	// stays light in both themes
+	BaseStyle = BaseStyle.Foreground(FgColor)
+	NormalStyle = NormalStyle.Foreground(FgColor)
+	InputStyle = InputStyle.Foreground(FgColor)
+	StatusBarStyle = StatusBarStyle.Foreground(FgColor).Background(statusBg)
+}
+
+// GroupPalette is the Okabe-Ito palette, whose colors stay apart with the
+// common kinds of color blindness. Groups without a color of their own get
+// one from it, and it is offered when picking a group's color.
+var GroupPalette = []lipgloss.Color{
+	"#E69F00", // orange
+	"#56B4E9", // sky blue
+	"#009E73", // bluish green
+	"#F0E442", // yellow
+	"#0072B2", // blue
+	"#D55E00", // vermillion
+	"#CC79A7", // reddish purple
+	"#999999", // grey
+}
+
+// GroupColor returns the color to tint a group with: its own when it is a
+// hex color, else the palette color for its position
+func GroupColor(color string, index int) lipgloss.Color {
+	if model.ValidColor(color) {
+		return lipgloss.Color(color)
+	}
+	return GroupPalette[index%len(GroupPalette)]
+}
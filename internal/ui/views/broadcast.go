@@ -0,0 +1,182 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gossh/internal/i18n"
+	"gossh/internal/ssh"
+	"gossh/internal/ui/styles"
+)
+
+// BroadcastChrome is how many lines the broadcast view uses around the
+// focused host's output
+const BroadcastChrome = 5
+
+// BroadcastModel shows the hosts of a broadcast and the output of the
+// focused one
+type BroadcastModel struct {
+	hosts  []ssh.BroadcastHost
+	focus  int
+	single bool // Type to the focused host only
+	width  int
+	height int
+}
+
+// NewBroadcastModel creates a new broadcast view
+func NewBroadcastModel() BroadcastModel {
+	return BroadcastModel{}
+}
+
+// SetSize sets the view dimensions
+func (m *BroadcastModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// SetHosts updates the hosts shown
+func (m *BroadcastModel) SetHosts(hosts []ssh.BroadcastHost) {
+	m.hosts = hosts
+	if m.focus >= len(hosts) {
+		m.focus = 0
+	}
+}
+
+// Next focuses the next host
+func (m *BroadcastModel) Next() {
+	if len(m.hosts) > 0 {
+		m.focus = (m.focus + 1) % len(m.hosts)
+	}
+}
+
+// Prev focuses the previous host
+func (m *BroadcastModel) Prev() {
+	if len(m.hosts) > 0 {
+		m.focus = (m.focus + len(m.hosts) - 1) % len(m.hosts)
+	}
+}
+
+// ToggleSingle switches between typing to every host and to the focused
+// host only
+func (m *BroadcastModel) ToggleSingle() {
+	m.single = !m.single
+}
+
+// Target returns the host keystrokes go to, or -1 for every host
+func (m BroadcastModel) Target() int {
+	if m.single {
+		return m.focus
+	}
+	return -1
+}
+
+// View renders the broadcast view
+func (m BroadcastModel) View() string {
+	var b strings.Builder
+
+	title := fmt.Sprintf(i18n.T("broadcast.title.all"), len(m.hosts))
+	if m.single && m.focus < len(m.hosts) {
+		title = fmt.Sprintf(i18n.T("broadcast.title.single"), m.hosts[m.focus].Connection.Name)
+	}
+	b.WriteString(styles.TitleStyle.Render(title))
+	b.WriteString("\n")
+
+	// One tab per host with its state
+	tabs := make([]string, len(m.hosts))
+	for i, h := range m.hosts {
+		icon := styles.DimStyle.Render("○")
+		switch {
+		case h.Error != nil:
+			icon = styles.ErrorStyle.Render("✗")
+		case h.Connected && h.Dropped > 0:
+			icon = styles.WarningStyle.Render("●")
+		case h.Connected:
+			icon = styles.SuccessStyle.Render("●")
+		}
+		name := h.Connection.Name
+		if i == m.focus {
+			name = styles.SelectedStyle.Render("[" + name + "]")
+		}
+		tabs[i] = icon + " " + name
+	}
+	b.WriteString(strings.Join(tabs, "  "))
+	b.WriteString("\n\n")
+
+	// Output of the focused host
+	lines := make([]string, 0)
+	if m.focus < len(m.hosts) {
+		h := m.hosts[m.focus]
+		lines = ssh.PlainLines(h.Output)
+		if h.Dropped > 0 {
+			lines = append(lines, styles.WarningStyle.Render(fmt.Sprintf(i18n.T("broadcast.dropped"), h.Dropped)))
+		}
+		if h.Error != nil {
+			lines = append(lines, styles.ErrorStyle.Render(i18n.T("common.error")+": "+h.Error.Error()))
+		}
+	}
+	rows := max(m.height-BroadcastChrome, 1)
+	if len(lines) > rows {
+		lines = lines[len(lines)-rows:]
+	}
+	for _, line := range lines {
+		b.WriteString(truncate(line, m.width))
+		b.WriteString("\n")
+	}
+	for i := len(lines); i < rows; i++ {
+		b.WriteString("\n")
+	}
+
+	b.WriteString(styles.HelpStyle.Render(i18n.T("broadcast.help")))
+	return b.String()
+}
+
+// truncate cuts s to width runes; zero width means no limit
+func truncate(s string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	return string([]rune(s)[:width])
+}
+
+// BroadcastInput returns the bytes a terminal would send for a key
+func BroadcastInput(msg tea.KeyMsg) []byte {
+	var seq string
+	switch msg.Type {
+	case tea.KeyRunes:
+		seq = string(msg.Runes)
+	case tea.KeySpace:
+		seq = " "
+	case tea.KeyUp:
+		seq = "\x1b[A"
+	case tea.KeyDown:
+		seq = "\x1b[B"
+	case tea.KeyRight:
+		seq = "\x1b[C"
+	case tea.KeyLeft:
+		seq = "\x1b[D"
+	case tea.KeyHome:
+		seq = "\x1b[H"
+	case tea.KeyEnd:
+		seq = "\x1b[F"
+	case tea.KeyDelete:
+		seq = "\x1b[3~"
+	case tea.KeyPgUp:
+		seq = "\x1b[5~"
+	case tea.KeyPgDown:
+		seq = "\x1b[6~"
+	case tea.KeyShiftTab:
+		seq = "\x1b[Z"
+	default:
+		if msg.Type < 0 {
+			return nil // Keys without a common encoding, e.g. F-keys
+		}
+		// Control characters, enter, tab, esc and backspace are their own codes
+		seq = string(rune(msg.Type))
+	}
+	if msg.Alt {
+		seq = "\x1b" + seq
+	}
+	return []byte(seq)
+}
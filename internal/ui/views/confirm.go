@@ -45,8 +45,8 @@ type ConfirmModel struct {
 // NewConfirmModel creates a new confirm dialog
 func NewConfirmModel() ConfirmModel {
 	return ConfirmModel{
-		title:    "Confirm",
-		message:  "Are you sure?",
+		title:    i18n.T("confirm.title"),
+		message:  i18n.T("confirm.default"),
 		keys:     DefaultConfirmKeyMap,
 		selected: 0,
 	}
@@ -120,5 +120,5 @@ func (m ConfirmModel) View() string {
 	help := styles.HelpStyle.Render(i18n.T("confirm.help"))
 	b.WriteString(help)
 
-	return styles.DialogStyle.Render(b.String())
+	return Layout{Width: m.width, Height: m.height}.Dialog(b.String())
 }
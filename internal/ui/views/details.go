@@ -0,0 +1,434 @@
+package views
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"gossh/internal/config"
+	"gossh/internal/i18n"
+	"gossh/internal/model"
+	"gossh/internal/sftp"
+	"gossh/internal/ui/styles"
+)
+
+// DetailsKeyMap defines key bindings for the connection details view
+type DetailsKeyMap struct {
+	Up      key.Binding
+	Down    key.Binding
+	View    key.Binding
+	Add     key.Binding
+	Export  key.Binding
+	Delete  key.Binding
+	Confirm key.Binding
+	Reveal  key.Binding
+	Back    key.Binding
+}
+
+// DefaultDetailsKeyMap returns default connection details key bindings
+var DefaultDetailsKeyMap = DetailsKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+	),
+	View: key.NewBinding(
+		key.WithKeys("enter"),
+	),
+	Add: key.NewBinding(
+		key.WithKeys("a"),
+	),
+	Export: key.NewBinding(
+		key.WithKeys("x"),
+	),
+	Delete: key.NewBinding(
+		key.WithKeys("d"),
+	),
+	Confirm: key.NewBinding(
+		key.WithKeys("y"),
+	),
+	Reveal: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc", "q"),
+	),
+}
+
+// detailsPrompt is what the details view is asking for
+type detailsPrompt int
+
+const (
+	promptNone   detailsPrompt = iota
+	promptAdd                  // Path of a file to attach
+	promptExport               // Path to export the selected attachment to
+	promptDelete               // Whether to delete the selected attachment
+)
+
+// DetailsModel shows a connection with its attachments: files such as
+// runbooks or VPN configs kept encrypted with it. Text attachments open in
+// a pager; any can be exported to a file.
+type DetailsModel struct {
+	cfg     *config.Manager
+	conn    model.Connection
+	cursor  int
+	keys    DetailsKeyMap
+	prompt  detailsPrompt
+	input   textinput.Model
+	pager   *ScrollbackModel // Attachment being read
+	message string
+	failed  bool // The message is an error
+	shown   bool // The password and key passphrase are shown
+	shownID int  // Counts reveals, so an old timeout is ignored
+	width   int
+	height  int
+	done    bool
+}
+
+// NewDetailsModel creates the details view of conn
+func NewDetailsModel(cfg *config.Manager, conn model.Connection) DetailsModel {
+	input := textinput.New()
+	input.CharLimit = 4096
+	return DetailsModel{
+		cfg:   cfg,
+		conn:  conn,
+		keys:  DefaultDetailsKeyMap,
+		input: input,
+	}
+}
+
+// SetSize sets the view dimensions
+func (m *DetailsModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.input.Width = max(Layout{Width: width}.DialogWidth()-lipgloss.Width(m.input.Prompt)-1, minColumn)
+	if m.pager != nil {
+		m.pager.SetSize(width, height)
+	}
+}
+
+// Done reports whether the view was closed
+func (m DetailsModel) Done() bool {
+	return m.done
+}
+
+// selected returns the attachment under the cursor
+func (m DetailsModel) selected() (model.Attachment, bool) {
+	if m.cursor >= len(m.conn.Attachments) {
+		return model.Attachment{}, false
+	}
+	return m.conn.Attachments[m.cursor], true
+}
+
+// reload picks up the attachments after a change
+func (m *DetailsModel) reload() {
+	if conn, ok := m.cfg.GetConnection(m.conn.ID); ok {
+		m.conn = conn
+	}
+	m.cursor = min(m.cursor, max(len(m.conn.Attachments)-1, 0))
+}
+
+// fail shows err in place of the help
+func (m *DetailsModel) fail(err error) {
+	m.message = i18n.T("common.error") + ": " + ErrorText(err)
+	m.failed = true
+}
+
+// ask prompts for a path, starting from value
+func (m *DetailsModel) ask(prompt detailsPrompt, label, value string) tea.Cmd {
+	m.prompt = prompt
+	m.input.Prompt = label + " "
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	m.SetSize(m.width, m.height)
+	return m.input.Focus()
+}
+
+// RevealTimeout hides the secrets shown with ctrl+r when msg is the
+// timeout of the latest reveal
+func (m DetailsModel) RevealTimeout(msg RevealTimeoutMsg) DetailsModel {
+	if msg.view == revealerDetails && msg.id == m.shownID {
+		m.shown = false
+	}
+	return m
+}
+
+// toggleReveal shows the password and key passphrase, logging it, or hides
+// them when shown
+func (m *DetailsModel) toggleReveal() tea.Cmd {
+	if m.shown {
+		m.shown = false
+		return nil
+	}
+	if m.conn.Password == "" && m.conn.KeyPassword == "" {
+		return nil
+	}
+
+	m.shown = true
+	m.shownID++
+	if m.conn.Password != "" {
+		_ = m.cfg.RecordReveal(m.conn.Name, "password")
+	}
+	if m.conn.KeyPassword != "" {
+		_ = m.cfg.RecordReveal(m.conn.Name, "key_password")
+	}
+	return revealTimeout(revealerDetails, m.shownID)
+}
+
+// secret returns value as shown: masked unless revealed
+func (m DetailsModel) secret(value string) string {
+	if value == "" || m.shown {
+		return value
+	}
+	// Of a fixed length, not to give the length away
+	return strings.Repeat("•", 8)
+}
+
+// Update handles a key
+func (m DetailsModel) Update(msg tea.KeyMsg) (DetailsModel, tea.Cmd) {
+	if m.pager != nil {
+		pager, cmd := m.pager.Update(msg)
+		m.pager = &pager
+		if pager.Done() {
+			m.pager = nil
+		}
+		return m, cmd
+	}
+
+	switch m.prompt {
+	case promptAdd, promptExport:
+		switch msg.Type {
+		case tea.KeyEnter:
+			path := config.ExpandHome(strings.TrimSpace(m.input.Value()))
+			if path == "" {
+				return m, nil
+			}
+			prompt := m.prompt
+			m.prompt = promptNone
+			m.input.Blur()
+			if prompt == promptAdd {
+				m.add(path)
+			} else {
+				m.export(path)
+			}
+			return m, nil
+		case tea.KeyEsc:
+			m.prompt = promptNone
+			m.input.Blur()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	case promptDelete:
+		m.prompt = promptNone
+		if attachment, ok := m.selected(); ok && key.Matches(msg, m.keys.Confirm) {
+			if err := m.cfg.DeleteAttachment(m.conn.ID, attachment.ID); err != nil {
+				m.fail(err)
+			} else {
+				m.message = fmt.Sprintf(i18n.T("details.deleted"), attachment.Name)
+			}
+			m.reload()
+		}
+		return m, nil
+	}
+
+	m.message, m.failed = "", false
+	attachment, ok := m.selected()
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.done = true
+	case key.Matches(msg, m.keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.cursor < len(m.conn.Attachments)-1 {
+			m.cursor++
+		}
+	case key.Matches(msg, m.keys.Reveal):
+		return m, m.toggleReveal()
+	case key.Matches(msg, m.keys.Add):
+		return m, m.ask(promptAdd, i18n.T("details.add.prompt"), "")
+	case !ok:
+		// The rest act on the selected attachment
+	case key.Matches(msg, m.keys.View):
+		m.view(attachment)
+	case key.Matches(msg, m.keys.Export):
+		return m, m.ask(promptExport, i18n.T("details.export.prompt"), attachment.Name)
+	case key.Matches(msg, m.keys.Delete):
+		m.prompt = promptDelete
+	}
+	return m, nil
+}
+
+// add attaches the file at path
+func (m *DetailsModel) add(path string) {
+	info, err := os.Stat(path)
+	switch {
+	case err != nil:
+		m.fail(err)
+		return
+	case !info.Mode().IsRegular():
+		m.fail(fmt.Errorf("%s: %s", path, i18n.T("details.not_file")))
+		return
+	case info.Size() > model.MaxAttachmentSize:
+		// Checked before reading, the file could be anything
+		m.fail(config.ErrAttachmentTooLarge)
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	attachment, err := m.cfg.AddAttachment(m.conn.ID, filepath.Base(path), data)
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	m.reload()
+	m.cursor = len(m.conn.Attachments) - 1
+	m.message = fmt.Sprintf(i18n.T("details.added"), attachment.Name)
+}
+
+// export writes the selected attachment to path, or into it when path is
+// a directory. Existing files are left alone.
+func (m *DetailsModel) export(path string) {
+	attachment, ok := m.selected()
+	if !ok {
+		return
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, attachment.Name)
+	}
+	// Checked before decrypting too, so a refused export is not audited
+	if _, err := os.Lstat(path); err == nil {
+		m.fail(fmt.Errorf("%s: %s", path, i18n.T("details.exists")))
+		return
+	}
+	data, err := m.cfg.OpenAttachment(m.conn.ID, attachment.ID)
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	f, err := config.OpenPrivateFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL)
+	if errors.Is(err, os.ErrExist) {
+		m.fail(fmt.Errorf("%s: %s", path, i18n.T("details.exists")))
+		return
+	}
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	m.message = fmt.Sprintf(i18n.T("details.exported"), attachment.Name, config.ContractHome(path))
+}
+
+// view opens the attachment in the pager, if it is text
+func (m *DetailsModel) view(attachment model.Attachment) {
+	data, err := m.cfg.OpenAttachment(m.conn.ID, attachment.ID)
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	if !utf8.Valid(data) || bytes.IndexByte(data, 0) >= 0 {
+		m.message = fmt.Sprintf(i18n.T("details.binary"), attachment.Name)
+		return
+	}
+	pager := NewPagerModel(m.conn.Name+" / "+attachment.Name, data)
+	pager.SetSize(m.width, m.height)
+	m.pager = &pager
+}
+
+// View renders the details
+func (m DetailsModel) View() string {
+	if m.pager != nil {
+		return m.pager.View()
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.TitleStyle.Render(fmt.Sprintf(i18n.T("details.title"), m.conn.Name)))
+	b.WriteString("\n\n")
+
+	width := Layout{Width: m.width}.DialogWidth()
+	field := func(label, value string) {
+		if value == "" {
+			return
+		}
+		row := "  " + styles.LabelStyle.Render(label+":") + " "
+		b.WriteString(row + Ellipsis(value, width-lipgloss.Width(row)) + "\n")
+	}
+	field(i18n.T("details.address"), fmt.Sprintf("%s@%s:%d", m.conn.User, m.conn.Host, m.conn.Port))
+	field(i18n.T("details.group"), m.conn.Group)
+	field(i18n.T("details.tags"), strings.Join(m.conn.Tags, ", "))
+	field(i18n.T("details.password"), m.secret(m.conn.Password))
+	field(i18n.T("details.key_passphrase"), m.secret(m.conn.KeyPassword))
+	field(i18n.T("details.agent_key"), m.conn.AgentKey)
+	for i, line := range strings.Split(strings.TrimSpace(m.conn.Notes), "\n") {
+		label := i18n.T("details.notes")
+		if i > 0 {
+			// Continuation lines line up under the first
+			label = strings.Repeat(" ", lipgloss.Width(label))
+			b.WriteString("  " + label + "  " + Ellipsis(line, width-lipgloss.Width(label)-4) + "\n")
+			continue
+		}
+		field(label, line)
+	}
+
+	b.WriteString("\n" + styles.SubtitleStyle.Render(i18n.T("details.attachments")) + "\n")
+	if len(m.conn.Attachments) == 0 {
+		b.WriteString(styles.DimStyle.Render("  "+i18n.T("details.attachments.empty")) + "\n")
+	}
+	names := make([]string, len(m.conn.Attachments))
+	for i, a := range m.conn.Attachments {
+		names[i] = a.Name
+	}
+	nameWidth := Layout{Width: m.width}.Column(names, 0.4)
+	for i, a := range m.conn.Attachments {
+		cursor := "  "
+		style := lipgloss.NewStyle()
+		if i == m.cursor {
+			cursor = "▸ "
+			style = styles.SelectedStyle
+		}
+		name := style.Render(Pad(Ellipsis(a.Name, nameWidth), nameWidth))
+		info := fmt.Sprintf("%9s  %s", sftp.FormatSize(int64(a.Size)), a.AddedAt.Local().Format("2006-01-02 15:04"))
+		b.WriteString(cursor + name + "  " + styles.DimStyle.Render(info) + "\n")
+	}
+
+	b.WriteString("\n")
+	switch {
+	case m.prompt == promptAdd || m.prompt == promptExport:
+		b.WriteString(m.input.View())
+	case m.prompt == promptDelete:
+		attachment, _ := m.selected()
+		b.WriteString(styles.WarningStyle.Render(fmt.Sprintf(i18n.T("details.delete.confirm"), attachment.Name)))
+	case m.failed:
+		b.WriteString(styles.ErrorStyle.Render(m.message))
+	case m.message != "":
+		b.WriteString(styles.SuccessStyle.Render(m.message))
+	case m.shown:
+		b.WriteString(styles.WarningStyle.Render(i18n.T("details.revealed")))
+	default:
+		b.WriteString(styles.HelpStyle.Render(i18n.T("details.help")))
+	}
+
+	return Layout{Width: m.width, Height: m.height}.Dialog(b.String())
+}
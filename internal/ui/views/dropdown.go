@@ -0,0 +1,112 @@
+package views
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gossh/internal/ui/styles"
+)
+
+// Dropdown shows the selected one of a few options. Space or enter opens
+// the list to pick another.
+type Dropdown struct {
+	options  []string
+	selected int
+	cursor   int
+	open     bool
+}
+
+// NewDropdown creates a dropdown with the first option selected
+func NewDropdown(options ...string) Dropdown {
+	return Dropdown{options: options}
+}
+
+// Value returns the selected option
+func (d Dropdown) Value() string {
+	if d.selected >= len(d.options) {
+		return ""
+	}
+	return d.options[d.selected]
+}
+
+// Index returns the position of the selected option
+func (d Dropdown) Index() int {
+	return d.selected
+}
+
+// Select selects the option at i
+func (d *Dropdown) Select(i int) {
+	if i >= 0 && i < len(d.options) {
+		d.selected = i
+	}
+}
+
+// SelectValue selects the option equal to value, reporting whether there
+// is one
+func (d *Dropdown) SelectValue(value string) bool {
+	for i, o := range d.options {
+		if o == value {
+			d.selected = i
+			return true
+		}
+	}
+	return false
+}
+
+// IsOpen reports whether the list of options is shown
+func (d Dropdown) IsOpen() bool {
+	return d.open
+}
+
+// Update handles a key: space or enter opens the list; while open, up and
+// down move through it, space or enter pick and esc closes it
+func (d Dropdown) Update(msg tea.KeyMsg) Dropdown {
+	if !d.open {
+		switch msg.String() {
+		case " ", "enter":
+			d.open = true
+			d.cursor = d.selected
+		}
+		return d
+	}
+
+	switch msg.String() {
+	case "up", "k", "shift+tab":
+		if d.cursor > 0 {
+			d.cursor--
+		}
+	case "down", "j", "tab":
+		if d.cursor < len(d.options)-1 {
+			d.cursor++
+		}
+	case " ", "enter":
+		d.selected = d.cursor
+		d.open = false
+	case "esc":
+		d.open = false
+	}
+	return d
+}
+
+// View renders the selected option, followed by the list when open
+func (d Dropdown) View(focused bool) string {
+	value := "[" + d.Value() + " ▾]"
+	if focused {
+		value = styles.SelectedStyle.Render(value)
+	}
+	if !d.open {
+		return value
+	}
+
+	var b strings.Builder
+	b.WriteString(value)
+	for i, o := range d.options {
+		b.WriteString("\n")
+		if i == d.cursor {
+			b.WriteString(styles.SelectedStyle.Render("    > " + o))
+		} else {
+			b.WriteString("      " + o)
+		}
+	}
+	return b.String()
+}
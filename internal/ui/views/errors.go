@@ -0,0 +1,52 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+
+	"gossh/internal/config"
+	"gossh/internal/crypto"
+	"gossh/internal/i18n"
+	"gossh/internal/model"
+)
+
+// ErrorText returns a localized message for well-known errors,
+// falling back to the error's own text
+func ErrorText(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var ve model.ValidationError
+	var pe crypto.PolicyError
+	var perm config.PermissionError
+	switch {
+	case errors.As(err, &ve):
+		key := "error.validation." + ve.Field
+		if msg := i18n.T(key); msg != key {
+			return msg
+		}
+	case errors.Is(err, crypto.ErrInvalidPassword):
+		return i18n.T("error.password.invalid")
+	case errors.As(err, &pe):
+		if pe.Rule == "length" || pe.Rule == "classes" {
+			return fmt.Sprintf(i18n.T("error.password.policy."+pe.Rule), pe.Min)
+		}
+		return i18n.T("error.password.policy." + pe.Rule)
+	case errors.Is(err, crypto.ErrPasswordTooWeak):
+		return i18n.T("error.password.weak")
+	case errors.As(err, &perm) && !perm.Key:
+		return fmt.Sprintf(i18n.T("error.insecure_dir"), perm.Path)
+	case errors.Is(err, errPasswordMismatch):
+		return i18n.T("setup.password.mismatch")
+	case errors.Is(err, config.ErrAttachmentTooLarge):
+		return fmt.Sprintf(i18n.T("error.attachment.too_large"), model.MaxAttachmentSize/1024)
+	case errors.Is(err, config.ErrAttachmentEmpty):
+		return i18n.T("error.attachment.empty")
+	case errors.Is(err, config.ErrAttachmentExists):
+		return i18n.T("error.attachment.exists")
+	case errors.Is(err, config.ErrLocked):
+		return i18n.T("error.locked")
+	}
+	return err.Error()
+}
@@ -0,0 +1,271 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"gossh/internal/i18n"
+	"gossh/internal/model"
+	"gossh/internal/ssh"
+	"gossh/internal/ui/styles"
+)
+
+// SpinnerFrames animate work in progress, one frame per tick
+var SpinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// SpinnerInterval is how often a spinner turns
+const SpinnerInterval = 100 * time.Millisecond
+
+// ProgressLine describes how far a batch run has got: a spinner frame,
+// finished, running and failed hosts and the time left
+func ProgressLine(p ssh.BatchProgress, frame int) string {
+	line := SpinnerFrames[frame%len(SpinnerFrames)] + " " +
+		fmt.Sprintf(i18n.T("exec.progress"), p.Done, p.Total, p.Running, p.Failed)
+	if eta := p.ETA().Round(time.Second); eta > 0 {
+		line += " · " + fmt.Sprintf(i18n.T("exec.eta"), eta)
+	}
+	return line
+}
+
+// ExecKeyMap defines key bindings for the batch exec view
+type ExecKeyMap struct {
+	Up   key.Binding
+	Down key.Binding
+	Back key.Binding
+}
+
+// DefaultExecKeyMap returns default batch exec key bindings
+var DefaultExecKeyMap = ExecKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+	),
+}
+
+// ExecModel runs a command on several hosts: it asks for the command, then
+// shows how each host is getting on and, once they are done, the output of
+// the selected one
+type ExecModel struct {
+	hosts    []model.Connection
+	input    textinput.Model
+	keys     ExecKeyMap
+	running  bool
+	finished bool
+	progress ssh.BatchProgress
+	started  []bool
+	results  []*ssh.BatchResult
+	cursor   int
+	frame    int
+	width    int
+	height   int
+	done     bool
+}
+
+// NewExecModel creates the batch exec view for hosts
+func NewExecModel(hosts []model.Connection) ExecModel {
+	input := textinput.New()
+	input.Placeholder = i18n.T("exec.command.placeholder")
+	input.Width = 40
+	input.Focus()
+
+	return ExecModel{
+		hosts:    hosts,
+		input:    input,
+		keys:     DefaultExecKeyMap,
+		progress: ssh.BatchProgress{Total: len(hosts)},
+		started:  make([]bool, len(hosts)),
+		results:  make([]*ssh.BatchResult, len(hosts)),
+	}
+}
+
+// Init initializes the batch exec model
+func (m ExecModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// SetSize sets the view dimensions
+func (m *ExecModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.input.Width = max(Layout{Width: width}.DialogWidth()-lipgloss.Width(i18n.T("exec.command"))-4, minColumn)
+}
+
+// Done reports whether the view was closed
+func (m ExecModel) Done() bool {
+	return m.done
+}
+
+// Running reports whether the command is running
+func (m ExecModel) Running() bool {
+	return m.running
+}
+
+// Command returns the command entered, before it runs
+func (m ExecModel) Command() string {
+	if m.running || m.finished {
+		return ""
+	}
+	return strings.TrimSpace(m.input.Value())
+}
+
+// Hosts returns the hosts the command runs on
+func (m ExecModel) Hosts() []model.Connection {
+	return m.hosts
+}
+
+// Start switches to showing the progress of the command
+func (m *ExecModel) Start() {
+	m.running = true
+	m.input.Blur()
+}
+
+// SetProgress records how far the run has got
+func (m *ExecModel) SetProgress(p ssh.BatchProgress) {
+	m.progress = p
+	if p.Host < 0 || p.Host >= len(m.hosts) {
+		return
+	}
+	m.started[p.Host] = true
+	if p.Result != nil {
+		m.results[p.Host] = p.Result
+	}
+}
+
+// Tick turns the spinner
+func (m *ExecModel) Tick() {
+	m.frame++
+}
+
+// Finish shows the results of the finished run
+func (m *ExecModel) Finish() {
+	m.running = false
+	m.finished = true
+}
+
+// Update handles a key
+func (m ExecModel) Update(msg tea.KeyMsg) (ExecModel, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		// A running command is stopped by the caller and the view stays
+		// up for its results
+		if !m.running {
+			m.done = true
+		}
+		return m, nil
+	case m.running || m.finished:
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case key.Matches(msg, m.keys.Down):
+			if m.cursor < len(m.hosts)-1 {
+				m.cursor++
+			}
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// hostStatus renders how a host is getting on
+func (m ExecModel) hostStatus(i int) string {
+	r := m.results[i]
+	switch {
+	case r == nil && m.started[i]:
+		return styles.WarningStyle.Render(SpinnerFrames[m.frame%len(SpinnerFrames)])
+	case r == nil:
+		return styles.DimStyle.Render("·")
+	case r.Error == nil:
+		return styles.SuccessStyle.Render(fmt.Sprintf("✓ %.1fs", r.Duration.Seconds()))
+	case r.ExitCode != 0:
+		return styles.ErrorStyle.Render(fmt.Sprintf("✗ "+i18n.T("exec.exit"), r.ExitCode))
+	}
+	return styles.ErrorStyle.Render("✗ " + r.Error.Error())
+}
+
+// View renders the batch exec view
+func (m ExecModel) View() string {
+	var b strings.Builder
+	layout := Layout{Width: m.width, Height: m.height}
+	width := layout.DialogWidth()
+
+	b.WriteString(styles.TitleStyle.Render(fmt.Sprintf(i18n.T("exec.title"), len(m.hosts))))
+	b.WriteString("\n\n")
+
+	if !m.running && !m.finished {
+		b.WriteString(styles.LabelStyle.Render(i18n.T("exec.command")) + " " + m.input.View() + "\n\n")
+		b.WriteString(styles.DimStyle.Render(Ellipsis(hostNames(m.hosts), width)) + "\n\n")
+		b.WriteString(styles.HelpStyle.Render(i18n.T("exec.help.input")))
+		return layout.Dialog(b.String())
+	}
+
+	b.WriteString(styles.LabelStyle.Render(i18n.T("exec.command")) + " " + m.input.Value() + "\n")
+	if m.running {
+		b.WriteString(ProgressLine(m.progress, m.frame) + "\n\n")
+	} else {
+		b.WriteString(fmt.Sprintf(i18n.T("exec.finished"), m.progress.Done-m.progress.Failed, m.progress.Failed) + "\n\n")
+	}
+
+	// Hosts, scrolled to keep the cursor in view, leaving room for output
+	rows := len(m.hosts)
+	if avail := layout.DialogRows(); avail > 0 {
+		rows = min(rows, max((avail-8)/2, 3))
+	}
+	first := min(max(m.cursor-rows/2, 0), max(len(m.hosts)-rows, 0))
+	for i := first; i < first+rows && i < len(m.hosts); i++ {
+		cursor := "  "
+		name := Ellipsis(m.hosts[i].Name, 20)
+		if i == m.cursor {
+			cursor = "▸ "
+			name = styles.SelectedStyle.Render(name)
+		}
+		row := fmt.Sprintf("%s%s  %s", cursor, name+strings.Repeat(" ", max(20-lipgloss.Width(name), 0)), m.hostStatus(i))
+		b.WriteString(Ellipsis(row, width) + "\n")
+	}
+
+	// Output of the selected host
+	if r := m.results[m.cursor]; r != nil && r.Output != "" {
+		b.WriteString("\n")
+		lines := strings.Split(strings.TrimRight(r.Output, "\n"), "\n")
+		if avail := layout.DialogRows(); avail > 0 {
+			keep := max(avail-rows-8, 1)
+			if len(lines) > keep {
+				lines = lines[len(lines)-keep:]
+			}
+		}
+		for _, line := range lines {
+			b.WriteString(styles.DimStyle.Render(Ellipsis(line, width)) + "\n")
+		}
+	}
+
+	b.WriteString("\n")
+	if m.running {
+		b.WriteString(styles.HelpStyle.Render(i18n.T("exec.help.running")))
+	} else {
+		b.WriteString(styles.HelpStyle.Render(i18n.T("exec.help.done")))
+	}
+	return layout.Dialog(b.String())
+}
+
+// hostNames lists the names of hosts
+func hostNames(hosts []model.Connection) string {
+	names := make([]string, len(hosts))
+	for i, h := range hosts {
+		names[i] = h.Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,220 @@
+package views
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"gossh/internal/config"
+	"gossh/internal/i18n"
+	"gossh/internal/model"
+	"gossh/internal/ui/styles"
+)
+
+// FilterMenuKeyMap defines key bindings for the saved filter menu
+type FilterMenuKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Apply  key.Binding
+	Save   key.Binding
+	Delete key.Binding
+	Back   key.Binding
+}
+
+// DefaultFilterMenuKeyMap returns default saved filter menu key bindings
+var DefaultFilterMenuKeyMap = FilterMenuKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+	),
+	Apply: key.NewBinding(
+		key.WithKeys("enter"),
+	),
+	Save: key.NewBinding(
+		key.WithKeys("n"),
+	),
+	Delete: key.NewBinding(
+		key.WithKeys("d", "delete"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc", "q"),
+	),
+}
+
+// FilterMenuModel picks a saved filter for the list. It also saves the
+// list's current search under a name and deletes saved filters.
+type FilterMenuModel struct {
+	cfg       *config.Manager
+	query     string // The list's search, offered for saving
+	cursor    int
+	naming    bool
+	nameInput textinput.Model
+	keys      FilterMenuKeyMap
+	message   string
+	width     int
+	height    int
+	chosen    string
+	done      bool
+}
+
+// NewFilterMenuModel creates the menu for a list searched with query
+func NewFilterMenuModel(cfg *config.Manager, query string) FilterMenuModel {
+	input := textinput.New()
+	input.Placeholder = i18n.T("filters.name.placeholder")
+	input.CharLimit = 40
+	input.Width = 30
+
+	return FilterMenuModel{
+		cfg:       cfg,
+		query:     strings.TrimSpace(query),
+		nameInput: input,
+		keys:      DefaultFilterMenuKeyMap,
+	}
+}
+
+// SetSize sets the view dimensions
+func (m *FilterMenuModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Done reports whether the menu was closed
+func (m FilterMenuModel) Done() bool {
+	return m.done
+}
+
+// Chosen returns the query of the picked filter, empty when none was
+func (m FilterMenuModel) Chosen() string {
+	return m.chosen
+}
+
+// filters returns the saved filters
+func (m FilterMenuModel) filters() []model.SavedFilter {
+	return m.cfg.GetSettings().SavedFilters
+}
+
+// Update handles a key
+func (m FilterMenuModel) Update(msg tea.KeyMsg) (FilterMenuModel, tea.Cmd) {
+	if m.naming {
+		return m.updateNaming(msg)
+	}
+
+	filters := m.filters()
+	m.message = ""
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.done = true
+	case key.Matches(msg, m.keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.cursor < len(filters)-1 {
+			m.cursor++
+		}
+	case key.Matches(msg, m.keys.Apply):
+		if m.cursor < len(filters) {
+			m.chosen = filters[m.cursor].Query
+			m.done = true
+		}
+	case key.Matches(msg, m.keys.Save):
+		if m.query == "" {
+			m.message = i18n.T("filters.no_search")
+			return m, nil
+		}
+		m.naming = true
+		m.nameInput.SetValue("")
+		return m, m.nameInput.Focus()
+	case key.Matches(msg, m.keys.Delete):
+		if m.cursor < len(filters) {
+			if err := m.cfg.DeleteFilter(filters[m.cursor].Name); err != nil {
+				m.message = i18n.T("common.error") + ": " + ErrorText(err)
+			}
+			if m.cursor >= len(m.filters()) && m.cursor > 0 {
+				m.cursor--
+			}
+		}
+	}
+	return m, nil
+}
+
+// updateNaming handles a key while the name for the search is typed
+func (m FilterMenuModel) updateNaming(msg tea.KeyMsg) (FilterMenuModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.naming = false
+		m.nameInput.Blur()
+		return m, nil
+	case "enter":
+		name := strings.TrimSpace(m.nameInput.Value())
+		if name == "" {
+			m.message = i18n.T("form.error.required")
+			return m, nil
+		}
+		if err := m.cfg.SaveFilter(model.SavedFilter{Name: name, Query: m.query}); err != nil {
+			m.message = i18n.T("common.error") + ": " + ErrorText(err)
+			return m, nil
+		}
+		m.naming = false
+		m.nameInput.Blur()
+		for i, f := range m.filters() {
+			if f.Name == name {
+				m.cursor = i
+			}
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.nameInput, cmd = m.nameInput.Update(msg)
+	return m, cmd
+}
+
+// View renders the menu
+func (m FilterMenuModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.TitleStyle.Render(i18n.T("filters.title")))
+	b.WriteString("\n\n")
+
+	filters := m.filters()
+	if len(filters) == 0 {
+		b.WriteString(styles.DimStyle.Render("  "+i18n.T("filters.empty")) + "\n")
+	}
+	names := make([]string, len(filters))
+	for i, f := range filters {
+		names[i] = f.Name
+	}
+	width := Layout{Width: m.width}.Column(names, 0.3)
+	for i, f := range filters {
+		cursor := "  "
+		style := lipgloss.NewStyle()
+		if i == m.cursor {
+			cursor = "▸ "
+			style = styles.SelectedStyle
+		}
+		name := style.Render(lipgloss.NewStyle().Width(width).MaxWidth(width).Render(f.Name))
+		b.WriteString(cursor + name + "  " + styles.DimStyle.Render(f.Query) + "\n")
+	}
+
+	if m.naming {
+		b.WriteString("\n" + styles.LabelStyle.Render(i18n.T("filters.name")) + " " + m.nameInput.View() + "\n")
+		b.WriteString(styles.DimStyle.Render(m.query) + "\n")
+	}
+	if m.message != "" {
+		b.WriteString("\n" + styles.WarningStyle.Render(m.message) + "\n")
+	}
+
+	b.WriteString("\n")
+	if m.naming {
+		b.WriteString(styles.HelpStyle.Render(i18n.T("filters.help.naming")))
+	} else {
+		b.WriteString(styles.HelpStyle.Render(i18n.T("filters.help")))
+	}
+
+	return Layout{Width: m.width, Height: m.height}.Dialog(b.String())
+}
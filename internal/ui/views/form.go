@@ -1,42 +1,118 @@
 package views
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"gossh/internal/config"
+	"gossh/internal/i18n"
 	"gossh/internal/model"
+	"gossh/internal/ssh"
 	"gossh/internal/ui/styles"
 )
 
+// expiryLayout is the date format for the expiry field
+const expiryLayout = "2006-01-02"
+
+// revealDuration is how long ctrl+r shows the password and key passphrase
+// before they are hidden again
+const revealDuration = 15 * time.Second
+
+// revealer is the view secrets were shown in
+type revealer int
+
+const (
+	revealerForm revealer = iota
+	revealerDetails
+)
+
+// RevealTimeoutMsg hides the secrets shown with ctrl+r, unless they were
+// shown again since
+type RevealTimeoutMsg struct {
+	view revealer
+	id   int
+}
+
+// revealTimeout returns a command hiding the secrets of reveal id in view
+func revealTimeout(view revealer, id int) tea.Cmd {
+	return tea.Tick(revealDuration, func(time.Time) tea.Msg {
+		return RevealTimeoutMsg{view: view, id: id}
+	})
+}
+
 // FormKeyMap defines key bindings for the form view
 type FormKeyMap struct {
 	Tab      key.Binding
 	ShiftTab key.Binding
 	Enter    key.Binding
 	Escape   key.Binding
+	Toggle   key.Binding
+	Test     key.Binding
+	Browse   key.Binding
+	Reveal   key.Binding
+	Help     key.Binding
 }
 
 // DefaultFormKeyMap returns default form key bindings
 var DefaultFormKeyMap = FormKeyMap{
 	Tab: key.NewBinding(
 		key.WithKeys("tab"),
-		key.WithHelp("tab", "next field"),
+		key.WithHelp("tab", "help.key.tab"),
 	),
 	ShiftTab: key.NewBinding(
 		key.WithKeys("shift+tab"),
-		key.WithHelp("shift+tab", "prev field"),
+		key.WithHelp("shift+tab", "help.key.shifttab"),
 	),
 	Enter: key.NewBinding(
 		key.WithKeys("enter"),
-		key.WithHelp("enter", "save"),
+		key.WithHelp("enter", "help.key.save"),
 	),
 	Escape: key.NewBinding(
 		key.WithKeys("esc"),
-		key.WithHelp("esc", "cancel"),
+		key.WithHelp("esc", "help.key.cancel"),
+	),
+	Toggle: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "help.key.space"),
+	),
+	Test: key.NewBinding(
+		key.WithKeys("ctrl+t"),
+		key.WithHelp("ctrl+t", "help.key.test_form"),
+	),
+	Browse: key.NewBinding(
+		key.WithKeys("ctrl+o"),
+		key.WithHelp("ctrl+o", "help.key.browse"),
+	),
+	Reveal: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("ctrl+r", "help.key.reveal"),
 	),
+	// ? is typed into the fields, so help is on f1
+	Help: key.NewBinding(
+		key.WithKeys("f1"),
+		key.WithHelp("f1", "help.key.help"),
+	),
+}
+
+// HelpSection lists the form's bindings
+func (k FormKeyMap) HelpSection() HelpSection {
+	return HelpSection{
+		Title: i18n.T("help.form"),
+		Keys:  []key.Binding{k.Tab, k.ShiftTab, k.Toggle, k.Browse, k.Reveal, k.Test, k.Enter, k.Escape, k.Help},
+	}
 }
 
 // FormField represents the index of form fields
@@ -44,32 +120,75 @@ type FormField int
 
 const (
 	FieldName FormField = iota
+	FieldAliases
 	FieldHost
+	FieldType
 	FieldPort
+	FieldAddresses
 	FieldUser
 	FieldAuthMethod
 	FieldPassword
 	FieldKeyPath
 	FieldKeyPassword
+	FieldAgentKey
+	FieldGSSAPI
 	FieldGroup
 	FieldTags
+	FieldJumpHosts
+	FieldBindAddress
+	FieldTerm
+	FieldLocale
+	FieldLocalDir
+	FieldRemoteDir
 	FieldStartupCommand
+	FieldPreConnect
+	FieldPostDisconnect
+	FieldNotes
+	FieldQuietLogin
+	FieldShowBanner
+	FieldRotateAfter
+	FieldExpiresAt
 	FieldCount
 )
 
 // FormModel is the add/edit connection form
 type FormModel struct {
-	inputs       []textinput.Model
-	authMethod   model.AuthType
-	focusIndex   int
-	width        int
-	height       int
-	Editing      bool
-	editID       string
-	err          error
-	keys         FormKeyMap
-	groups       []string
-	groupIndex   int
+	inputs     []textinput.Model
+	widths     []int // Input widths for a wide enough terminal
+	connType   model.ConnectionType
+	authMethod model.AuthType
+	focusIndex int
+	width      int
+	height     int
+	Editing    bool
+	editID     string
+	forwards   []model.ForwardProfile // Kept as they are, the form does not edit them
+	err        error
+	keys       FormKeyMap
+	groups     []string
+	group      Dropdown
+	auth       Dropdown
+	agentKey   Dropdown
+	agentKeys  []string // Fingerprints of the agent key options, "" for any key
+	tags       TagEditor
+	startup    textarea.Model
+	notes      textarea.Model
+	gssapi     bool
+	quietLogin bool
+	showBanner bool
+	testing    bool               // A connection test is running
+	tested     bool               // A connection test has finished
+	testErr    error              // Why the last connection test failed
+	testTime   time.Duration      // How long the last successful test took
+	picker     *KeyPickerModel    // Open key file picker, if any
+	touched    map[FormField]bool // Fields edited or left, whose errors are shown
+	submitted  bool               // Saving was tried, so every error is shown
+	keyPath    string             // Key file last inspected
+	keyFile    ssh.KeyFile        // What the key file holds
+	keyErr     error              // Why the key file cannot be used
+	defaults   model.Settings     // Port and user filled in for new connections
+	revealed   bool               // The password and key passphrase are shown
+	revealID   int                // Counts reveals, so an old timeout is ignored
 }
 
 // NewFormModel creates a new form model
@@ -78,18 +197,29 @@ func NewFormModel(groups []string) FormModel {
 
 	// Name
 	inputs[FieldName] = textinput.New()
-	inputs[FieldName].Placeholder = "My Server"
+	inputs[FieldName].Placeholder = i18n.T("form.placeholder.name")
 	inputs[FieldName].CharLimit = 50
 	inputs[FieldName].Width = 30
 	inputs[FieldName].Prompt = ""
 
+	// Aliases
+	inputs[FieldAliases] = textinput.New()
+	inputs[FieldAliases].Placeholder = "db1, db-primary"
+	inputs[FieldAliases].CharLimit = 200
+	inputs[FieldAliases].Width = 40
+	inputs[FieldAliases].Prompt = ""
+
 	// Host
 	inputs[FieldHost] = textinput.New()
-	inputs[FieldHost].Placeholder = "192.168.1.1 or example.com"
+	inputs[FieldHost].Placeholder = i18n.T("form.placeholder.host")
 	inputs[FieldHost].CharLimit = 255
 	inputs[FieldHost].Width = 30
 	inputs[FieldHost].Prompt = ""
 
+	// Connection type (display only, toggle with space)
+	inputs[FieldType] = textinput.New()
+	inputs[FieldType].Prompt = ""
+
 	// Port
 	inputs[FieldPort] = textinput.New()
 	inputs[FieldPort].Placeholder = "22"
@@ -98,6 +228,13 @@ func NewFormModel(groups []string) FormModel {
 	inputs[FieldPort].Prompt = ""
 	inputs[FieldPort].SetValue("22")
 
+	// Other addresses
+	inputs[FieldAddresses] = textinput.New()
+	inputs[FieldAddresses].Placeholder = "10.8.0.5, 2001:db8::5"
+	inputs[FieldAddresses].CharLimit = 200
+	inputs[FieldAddresses].Width = 40
+	inputs[FieldAddresses].Prompt = ""
+
 	// User
 	inputs[FieldUser] = textinput.New()
 	inputs[FieldUser].Placeholder = "root"
@@ -105,13 +242,9 @@ func NewFormModel(groups []string) FormModel {
 	inputs[FieldUser].Width = 30
 	inputs[FieldUser].Prompt = ""
 
-	// Auth method (display only, toggle with space)
+	// Auth method (a dropdown)
 	inputs[FieldAuthMethod] = textinput.New()
-	inputs[FieldAuthMethod].Placeholder = "password"
-	inputs[FieldAuthMethod].CharLimit = 20
-	inputs[FieldAuthMethod].Width = 20
 	inputs[FieldAuthMethod].Prompt = ""
-	inputs[FieldAuthMethod].SetValue("password")
 
 	// Password
 	inputs[FieldPassword] = textinput.New()
@@ -130,175 +263,588 @@ func NewFormModel(groups []string) FormModel {
 
 	// Key password
 	inputs[FieldKeyPassword] = textinput.New()
-	inputs[FieldKeyPassword].Placeholder = "(optional)"
+	inputs[FieldKeyPassword].Placeholder = i18n.T("form.placeholder.optional")
 	inputs[FieldKeyPassword].CharLimit = 100
 	inputs[FieldKeyPassword].Width = 30
 	inputs[FieldKeyPassword].EchoMode = textinput.EchoPassword
 	inputs[FieldKeyPassword].Prompt = ""
 
-	// Group (display only, cycle with space)
+	// Agent key (a dropdown)
+	inputs[FieldAgentKey] = textinput.New()
+	inputs[FieldAgentKey].Prompt = ""
+
+	// GSSAPI toggle (display only, toggle with space)
+	inputs[FieldGSSAPI] = textinput.New()
+	inputs[FieldGSSAPI].Prompt = ""
+
+	// Group (a dropdown) and tags (a tag editor)
 	inputs[FieldGroup] = textinput.New()
-	inputs[FieldGroup].Placeholder = "Ungrouped"
-	inputs[FieldGroup].CharLimit = 50
-	inputs[FieldGroup].Width = 20
 	inputs[FieldGroup].Prompt = ""
-
-	// Tags
 	inputs[FieldTags] = textinput.New()
-	inputs[FieldTags].Placeholder = "web, nginx, prod"
-	inputs[FieldTags].CharLimit = 200
-	inputs[FieldTags].Width = 40
 	inputs[FieldTags].Prompt = ""
 
-	// Startup command
+	// Jump hosts
+	inputs[FieldJumpHosts] = textinput.New()
+	inputs[FieldJumpHosts].Placeholder = "bastion, inner-gw"
+	inputs[FieldJumpHosts].CharLimit = 200
+	inputs[FieldJumpHosts].Width = 40
+	inputs[FieldJumpHosts].Prompt = ""
+
+	// Bind address
+	inputs[FieldBindAddress] = textinput.New()
+	inputs[FieldBindAddress].Placeholder = "192.168.1.20 / wg0"
+	inputs[FieldBindAddress].CharLimit = 64
+	inputs[FieldBindAddress].Width = 40
+	inputs[FieldBindAddress].Prompt = ""
+
+	// Terminal type and locale overrides
+	inputs[FieldTerm] = textinput.New()
+	inputs[FieldTerm].Placeholder = "vt100"
+	inputs[FieldTerm].CharLimit = 64
+	inputs[FieldTerm].Width = 20
+	inputs[FieldTerm].Prompt = ""
+	inputs[FieldLocale] = textinput.New()
+	inputs[FieldLocale].Placeholder = "C.UTF-8"
+	inputs[FieldLocale].CharLimit = 64
+	inputs[FieldLocale].Width = 20
+	inputs[FieldLocale].Prompt = ""
+
+	// Directories SFTP starts in
+	inputs[FieldLocalDir] = textinput.New()
+	inputs[FieldLocalDir].Placeholder = "~/projects/app"
+	inputs[FieldLocalDir].CharLimit = 256
+	inputs[FieldLocalDir].Width = 40
+	inputs[FieldLocalDir].Prompt = ""
+	inputs[FieldRemoteDir] = textinput.New()
+	inputs[FieldRemoteDir].Placeholder = "/var/www/app"
+	inputs[FieldRemoteDir].CharLimit = 256
+	inputs[FieldRemoteDir].Width = 40
+	inputs[FieldRemoteDir].Prompt = ""
+
+	// Local commands run around sessions
+	inputs[FieldPreConnect] = textinput.New()
+	inputs[FieldPreConnect].Placeholder = "wg-quick up office"
+	inputs[FieldPreConnect].CharLimit = 512
+	inputs[FieldPreConnect].Width = 40
+	inputs[FieldPreConnect].Prompt = ""
+	inputs[FieldPostDisconnect] = textinput.New()
+	inputs[FieldPostDisconnect].Placeholder = "wg-quick down office"
+	inputs[FieldPostDisconnect].CharLimit = 512
+	inputs[FieldPostDisconnect].Width = 40
+	inputs[FieldPostDisconnect].Prompt = ""
+
+	// Startup command and notes (textareas)
 	inputs[FieldStartupCommand] = textinput.New()
-	inputs[FieldStartupCommand].Placeholder = "cd /app && source venv/bin/activate"
-	inputs[FieldStartupCommand].CharLimit = 500
-	inputs[FieldStartupCommand].Width = 50
 	inputs[FieldStartupCommand].Prompt = ""
+	inputs[FieldNotes] = textinput.New()
+	inputs[FieldNotes].Prompt = ""
+
+	// Login toggles (display only, toggle with space)
+	inputs[FieldQuietLogin] = textinput.New()
+	inputs[FieldQuietLogin].Prompt = ""
+	inputs[FieldShowBanner] = textinput.New()
+	inputs[FieldShowBanner].Prompt = ""
+
+	// Credential rotation period in days
+	inputs[FieldRotateAfter] = textinput.New()
+	inputs[FieldRotateAfter].Placeholder = "90"
+	inputs[FieldRotateAfter].CharLimit = 5
+	inputs[FieldRotateAfter].Width = 10
+	inputs[FieldRotateAfter].Prompt = ""
+
+	// Expiry date
+	inputs[FieldExpiresAt] = textinput.New()
+	inputs[FieldExpiresAt].Placeholder = expiryLayout
+	inputs[FieldExpiresAt].CharLimit = 10
+	inputs[FieldExpiresAt].Width = 12
+	inputs[FieldExpiresAt].Prompt = ""
 
 	// Focus first field
 	inputs[FieldName].Focus()
 
 	// Prepare groups list
-	allGroups := append([]string{"Ungrouped"}, groups...)
+	allGroups := append([]string{i18n.T("list.ungrouped")}, groups...)
+
+	// Remember the input widths to shrink from in narrow terminals
+	widths := make([]int, FieldCount)
+	for i := range inputs {
+		widths[i] = inputs[i].Width
+	}
 
 	return FormModel{
 		inputs:     inputs,
+		widths:     widths,
+		connType:   model.ConnTypeSSH,
 		authMethod: model.AuthPassword,
 		focusIndex: 0,
+		touched:    map[FormField]bool{},
 		keys:       DefaultFormKeyMap,
 		groups:     allGroups,
-		groupIndex: 0,
+		group:      NewDropdown(allGroups...),
+		auth:       NewDropdown(i18n.T("form.auth.opt.password"), i18n.T("form.auth.opt.key")),
+		agentKey:   NewDropdown(i18n.T("form.agent_key.any")),
+		agentKeys:  []string{""},
+		tags:       NewTagEditor(),
+		startup:    newTextarea("cd /app\nsource venv/bin/activate", 500),
+		notes:      newTextarea(i18n.T("form.placeholder.notes"), 2000),
 	}
 }
 
+// setAgentKeys lists the keys the SSH agent holds in the agent key
+// dropdown, after the option to offer any, and selects pinned. A pinned key
+// the agent does not hold is listed too, so saving keeps it.
+func (m *FormModel) setAgentKeys(pinned string) {
+	// Without an agent, only the pinned key is listed
+	keys, _ := ssh.AgentKeys()
+	m.agentKeys = []string{""}
+	options := []string{i18n.T("form.agent_key.any")}
+	for _, key := range keys {
+		label := key.Comment
+		if label == "" {
+			label = key.Type
+		}
+		m.agentKeys = append(m.agentKeys, key.Fingerprint)
+		options = append(options, label+" ("+Ellipsis(key.Fingerprint, 20)+")")
+	}
+	if pinned != "" && !slices.Contains(m.agentKeys, pinned) {
+		m.agentKeys = append(m.agentKeys, pinned)
+		options = append(options, fmt.Sprintf(i18n.T("form.agent_key.missing"), Ellipsis(pinned, 20)))
+	}
+	m.agentKey = NewDropdown(options...)
+	m.agentKey.Select(slices.Index(m.agentKeys, pinned))
+}
+
+// authMethods are the auth dropdown's options, in order
+var authMethods = []model.AuthType{model.AuthPassword, model.AuthKey}
+
+// Widths of the form's parts, in columns
+const (
+	formLabelWidth = 24 // Room for a label and the input's cursor
+	textareaWidth  = 50
+)
+
+// newTextarea creates a small multi-line input
+func newTextarea(placeholder string, limit int) textarea.Model {
+	ta := textarea.New()
+	ta.Placeholder = placeholder
+	ta.CharLimit = limit
+	ta.ShowLineNumbers = false
+	ta.Prompt = "  "
+	ta.FocusedStyle.CursorLine = lipgloss.NewStyle()
+	ta.SetWidth(textareaWidth)
+	ta.SetHeight(3)
+	return ta
+}
+
+// SetKnownTags sets the tags offered as completions in the tags field
+func (m *FormModel) SetKnownTags(tags []string) {
+	m.tags.SetKnown(tags)
+}
+
 // SetConnection populates the form with an existing connection
 func (m *FormModel) SetConnection(conn model.Connection) {
 	m.Editing = true
 	m.editID = conn.ID
+	m.forwards = conn.ForwardProfiles
 	m.inputs[FieldName].SetValue(conn.Name)
+	m.inputs[FieldAliases].SetValue(strings.Join(conn.Aliases, ", "))
 	m.inputs[FieldHost].SetValue(conn.Host)
 	m.inputs[FieldPort].SetValue(strconv.Itoa(conn.Port))
+	m.inputs[FieldAddresses].SetValue(strings.Join(conn.Addresses, ", "))
 	m.inputs[FieldUser].SetValue(conn.User)
-	m.authMethod = conn.AuthMethod
-	if conn.AuthMethod == model.AuthKey {
-		m.inputs[FieldAuthMethod].SetValue("key")
-	} else {
-		m.inputs[FieldAuthMethod].SetValue("password")
+	m.connType = model.ConnTypeSSH
+	if conn.IsTelnet() {
+		m.connType = model.ConnTypeTelnet
 	}
+	m.setAuthMethod(conn.AuthType)
 	m.inputs[FieldPassword].SetValue(conn.Password)
 	m.inputs[FieldKeyPath].SetValue(conn.KeyPath)
 	m.inputs[FieldKeyPassword].SetValue(conn.KeyPassword)
+	m.setAgentKeys(conn.AgentKey)
 
 	// Set group
 	groupName := conn.Group
 	if groupName == "" {
-		groupName = "Ungrouped"
-	}
-	m.inputs[FieldGroup].SetValue(groupName)
-	for i, g := range m.groups {
-		if g == groupName {
-			m.groupIndex = i
-			break
-		}
+		groupName = m.groups[0]
 	}
+	m.group.SelectValue(groupName)
 
 	// Set tags
-	if len(conn.Tags) > 0 {
-		m.inputs[FieldTags].SetValue(strings.Join(conn.Tags, ", "))
-	}
+	m.tags.SetTags(conn.Tags)
+
+	// Set jump hosts
+	m.inputs[FieldJumpHosts].SetValue(strings.Join(conn.JumpHosts, ", "))
+	m.inputs[FieldBindAddress].SetValue(conn.BindAddress)
+	m.inputs[FieldTerm].SetValue(conn.Term)
+	m.inputs[FieldLocale].SetValue(conn.Locale)
+	m.inputs[FieldLocalDir].SetValue(conn.LocalDir)
+	m.inputs[FieldRemoteDir].SetValue(conn.RemoteDir)
+
+	// Set startup command and notes
+	m.startup.SetValue(conn.StartupCommand)
+	m.inputs[FieldPreConnect].SetValue(conn.PreConnect)
+	m.inputs[FieldPostDisconnect].SetValue(conn.PostDisconnect)
+	m.notes.SetValue(conn.Notes)
+	m.gssapi = conn.GSSAPI
+	m.quietLogin = conn.QuietLogin
+	m.showBanner = conn.ShowBanner
 
-	// Set startup command
-	m.inputs[FieldStartupCommand].SetValue(conn.StartupCommand)
+	// Set credential policy
+	if conn.PasswordRotateAfter > 0 {
+		m.inputs[FieldRotateAfter].SetValue(strconv.Itoa(conn.PasswordRotateAfter))
+	}
+	if conn.ExpiresAt != nil {
+		m.inputs[FieldExpiresAt].SetValue(conn.ExpiresAt.Format(expiryLayout))
+	}
+	m.inspectKey()
 }
 
 // Reset clears the form
 func (m *FormModel) Reset() {
 	m.Editing = false
 	m.editID = ""
+	m.forwards = nil
 	m.focusIndex = 0
 	m.err = nil
-	m.authMethod = model.AuthPassword
-	m.groupIndex = 0
+	m.connType = model.ConnTypeSSH
+	m.setAuthMethod(model.AuthPassword)
+	m.group.Select(0)
+	m.setAgentKeys("")
+	m.tags.SetTags(nil)
+	m.startup.SetValue("")
+	m.notes.SetValue("")
+	m.gssapi = false
+	m.quietLogin = false
+	m.showBanner = false
+	m.testing = false
+	m.tested = false
+	m.picker = nil
+	m.touched = map[FormField]bool{}
+	m.submitted = false
+	m.keyPath = ""
+	m.keyFile = ssh.KeyFile{}
+	m.keyErr = nil
+	m.hideSecrets()
 
 	for i := range m.inputs {
 		m.inputs[i].SetValue("")
 		m.inputs[i].Blur()
 	}
-	m.inputs[FieldPort].SetValue("22")
-	m.inputs[FieldAuthMethod].SetValue("password")
-	m.inputs[FieldGroup].SetValue("Ungrouped")
+	m.tags.Blur()
+	m.startup.Blur()
+	m.notes.Blur()
+	m.inputs[FieldPort].SetValue(strconv.Itoa(m.defaults.NewConnectionPort(m.connType)))
+	m.inputs[FieldUser].SetValue(m.defaults.DefaultUser)
 	m.inputs[FieldName].Focus()
 }
 
-// GetConnection returns the connection from form values
+// SetDefaults sets the settings whose default port and user new
+// connections start with, from the next Reset
+func (m *FormModel) SetDefaults(settings model.Settings) {
+	m.defaults = settings
+}
+
+// setAuthMethod selects an auth method
+func (m *FormModel) setAuthMethod(auth model.AuthType) {
+	m.authMethod = auth
+	m.auth.Select(slices.Index(authMethods, auth))
+}
+
+// SetError shows an error that is not about a single field, e.g. from
+// saving the connection
+func (m *FormModel) SetError(err error) {
+	m.err = err
+}
+
+// GetConnection returns the connection from form values. When a field is
+// invalid it returns that field's error, shows the errors of every field
+// and moves the focus to the first one.
 func (m *FormModel) GetConnection() (model.Connection, error) {
-	port, err := strconv.Atoi(m.inputs[FieldPort].Value())
+	if f, err := m.firstError(); err != nil {
+		m.submitted = true
+		m.setFocus(f)
+		return model.Connection{}, err
+	}
+
+	port, err := strconv.Atoi(strings.TrimSpace(m.inputs[FieldPort].Value()))
 	if err != nil {
-		port = 22
+		port = m.defaults.NewConnectionPort(m.connType)
 	}
 
-	// Parse tags
-	tagsStr := m.inputs[FieldTags].Value()
-	var tags []string
-	if tagsStr != "" {
-		for _, tag := range strings.Split(tagsStr, ",") {
-			tag = strings.TrimSpace(tag)
-			if tag != "" {
-				tags = append(tags, tag)
-			}
-		}
+	// SSH is the default and is stored as an empty type
+	var connType model.ConnectionType
+	if m.connType == model.ConnTypeTelnet {
+		connType = model.ConnTypeTelnet
+	}
+
+	// Parse aliases, addresses, tags and jump hosts
+	aliases := splitList(m.inputs[FieldAliases].Value())
+	addresses := splitList(m.inputs[FieldAddresses].Value())
+	tags := m.tags.Tags()
+	var jumpHosts []string
+	if m.connType != model.ConnTypeTelnet {
+		jumpHosts = splitList(m.inputs[FieldJumpHosts].Value())
 	}
 
 	// Get group
-	group := m.inputs[FieldGroup].Value()
-	if group == "Ungrouped" {
+	group := m.group.Value()
+	if m.group.Index() == 0 {
 		group = ""
 	}
 
+	var agentKey string
+	if m.fieldVisible(FieldAgentKey) {
+		agentKey = m.agentKeys[m.agentKey.Index()]
+	}
+
 	conn := model.Connection{
-		Name:           m.inputs[FieldName].Value(),
-		Host:           m.inputs[FieldHost].Value(),
+		Name:           strings.TrimSpace(m.inputs[FieldName].Value()),
+		Aliases:        aliases,
+		Host:           strings.TrimSpace(m.inputs[FieldHost].Value()),
 		Port:           port,
-		User:           m.inputs[FieldUser].Value(),
-		AuthMethod:     m.authMethod,
+		Addresses:      addresses,
+		Type:           connType,
+		User:           strings.TrimSpace(m.inputs[FieldUser].Value()),
+		AuthType:       m.authMethod,
 		Password:       m.inputs[FieldPassword].Value(),
 		KeyPath:        m.inputs[FieldKeyPath].Value(),
 		KeyPassword:    m.inputs[FieldKeyPassword].Value(),
+		AgentKey:       agentKey,
+		GSSAPI:         m.gssapi,
 		Group:          group,
 		Tags:           tags,
-		StartupCommand: m.inputs[FieldStartupCommand].Value(),
+		JumpHosts:      jumpHosts,
+		BindAddress:    strings.TrimSpace(m.inputs[FieldBindAddress].Value()),
+		Term:           strings.TrimSpace(m.inputs[FieldTerm].Value()),
+		Locale:         strings.TrimSpace(m.inputs[FieldLocale].Value()),
+		LocalDir:       strings.TrimSpace(m.inputs[FieldLocalDir].Value()),
+		RemoteDir:      strings.TrimSpace(m.inputs[FieldRemoteDir].Value()),
+		StartupCommand: strings.TrimSpace(m.startup.Value()),
+		PreConnect:     strings.TrimSpace(m.inputs[FieldPreConnect].Value()),
+		PostDisconnect: strings.TrimSpace(m.inputs[FieldPostDisconnect].Value()),
+		Notes:          strings.TrimSpace(m.notes.Value()),
+		QuietLogin:     m.quietLogin,
+		ShowBanner:     m.showBanner,
 	}
 
 	if m.Editing {
 		conn.ID = m.editID
+		conn.ForwardProfiles = m.forwards
 	} else {
 		conn = model.NewConnection()
-		conn.Name = m.inputs[FieldName].Value()
-		conn.Host = m.inputs[FieldHost].Value()
+		conn.Name = strings.TrimSpace(m.inputs[FieldName].Value())
+		conn.Aliases = aliases
+		conn.Host = strings.TrimSpace(m.inputs[FieldHost].Value())
 		conn.Port = port
-		conn.User = m.inputs[FieldUser].Value()
-		conn.AuthMethod = m.authMethod
+		conn.Addresses = addresses
+		conn.Type = connType
+		conn.User = strings.TrimSpace(m.inputs[FieldUser].Value())
+		conn.AuthType = m.authMethod
 		conn.Password = m.inputs[FieldPassword].Value()
 		conn.KeyPath = m.inputs[FieldKeyPath].Value()
 		conn.KeyPassword = m.inputs[FieldKeyPassword].Value()
+		conn.AgentKey = agentKey
+		conn.GSSAPI = m.gssapi
 		conn.Group = group
 		conn.Tags = tags
-		conn.StartupCommand = m.inputs[FieldStartupCommand].Value()
+		conn.JumpHosts = jumpHosts
+		conn.BindAddress = strings.TrimSpace(m.inputs[FieldBindAddress].Value())
+		conn.Term = strings.TrimSpace(m.inputs[FieldTerm].Value())
+		conn.Locale = strings.TrimSpace(m.inputs[FieldLocale].Value())
+		conn.LocalDir = strings.TrimSpace(m.inputs[FieldLocalDir].Value())
+		conn.RemoteDir = strings.TrimSpace(m.inputs[FieldRemoteDir].Value())
+		conn.StartupCommand = strings.TrimSpace(m.startup.Value())
+		conn.PreConnect = strings.TrimSpace(m.inputs[FieldPreConnect].Value())
+		conn.PostDisconnect = strings.TrimSpace(m.inputs[FieldPostDisconnect].Value())
+		conn.Notes = strings.TrimSpace(m.notes.Value())
+		conn.QuietLogin = m.quietLogin
+		conn.ShowBanner = m.showBanner
+	}
+
+	// Parse credential policy, already checked by fieldError
+	if v := strings.TrimSpace(m.inputs[FieldRotateAfter].Value()); v != "" {
+		conn.PasswordRotateAfter, _ = strconv.Atoi(v)
+	}
+	if v := strings.TrimSpace(m.inputs[FieldExpiresAt].Value()); v != "" {
+		expires, _ := time.ParseInLocation(expiryLayout, v, time.Local)
+		conn.ExpiresAt = &expires
 	}
 
 	if err := conn.Validate(); err != nil {
 		return conn, err
 	}
-
 	return conn, nil
 }
 
+// fieldError checks the value of a single field, returning nil when it is
+// valid
+func (m *FormModel) fieldError(f FormField) error {
+	value := strings.TrimSpace(m.inputs[f].Value())
+	switch f {
+	case FieldName:
+		if value == "" {
+			return model.ErrNameRequired
+		}
+	case FieldAliases:
+		name := strings.TrimSpace(m.inputs[FieldName].Value())
+		for _, alias := range splitList(value) {
+			if alias == name || strings.ContainsFunc(alias, unicode.IsSpace) {
+				return fmt.Errorf("%w: %q", model.ErrInvalidAlias, alias)
+			}
+		}
+	case FieldHost:
+		if value == "" {
+			return model.ErrHostRequired
+		}
+		if !model.ValidHost(value) {
+			return model.ErrInvalidHost
+		}
+	case FieldPort:
+		// Left empty, the default port is used
+		if value == "" {
+			return nil
+		}
+		if port, err := strconv.Atoi(value); err != nil || port <= 0 || port > 65535 {
+			return model.ErrInvalidPort
+		}
+	case FieldAddresses:
+		for _, addr := range splitList(value) {
+			if !model.ValidHost(addr) {
+				return fmt.Errorf("%w: %s", model.ErrInvalidAddress, addr)
+			}
+		}
+	case FieldUser:
+		if value == "" && m.connType != model.ConnTypeTelnet {
+			return model.ErrUserRequired
+		}
+	case FieldKeyPath:
+		if value == "" && m.agentKey.Index() > 0 {
+			// The pinned agent key stands in for the key file
+			return nil
+		}
+		if value == "" {
+			return model.ErrKeyPathRequired
+		}
+		if m.keyErr != nil {
+			return model.ErrKeyUnreadable
+		}
+	case FieldKeyPassword:
+		if m.keyErr == nil && m.keyFile.Encrypted && m.inputs[FieldKeyPassword].Value() == "" {
+			return model.ErrKeyPassphrase
+		}
+	case FieldTerm:
+		if value != "" && !model.ValidEnvValue(value) {
+			return model.ErrInvalidTerm
+		}
+	case FieldLocale:
+		if value != "" && !model.ValidEnvValue(value) {
+			return model.ErrInvalidLocale
+		}
+	case FieldRotateAfter:
+		if value == "" {
+			return nil
+		}
+		if days, err := strconv.Atoi(value); err != nil || days <= 0 {
+			return model.ErrInvalidRotation
+		}
+	case FieldExpiresAt:
+		if value == "" {
+			return nil
+		}
+		if _, err := time.ParseInLocation(expiryLayout, value, time.Local); err != nil {
+			return model.ErrInvalidExpiry
+		}
+	}
+	return nil
+}
+
+// firstError returns the first visible field with an error and the error
+func (m *FormModel) firstError() (FormField, error) {
+	for f := FormField(0); f < FieldCount; f++ {
+		if !m.fieldVisible(f) {
+			continue
+		}
+		if err := m.fieldError(f); err != nil {
+			return f, err
+		}
+	}
+	return 0, nil
+}
+
+// shownError returns the error to show next to a field: once it has been
+// edited or left, or after saving was tried
+func (m *FormModel) shownError(f FormField) error {
+	if !m.touched[f] && !m.submitted {
+		return nil
+	}
+	return m.fieldError(f)
+}
+
+// inspectKey reads the key file when its path has changed, so it is not
+// read again on every keystroke
+func (m *FormModel) inspectKey() {
+	path := strings.TrimSpace(m.inputs[FieldKeyPath].Value())
+	if path == m.keyPath {
+		return
+	}
+	m.keyPath = path
+	m.keyFile, m.keyErr = ssh.InspectKey(path)
+}
+
+// StartTest marks a connection test of the entered values as running
+func (m *FormModel) StartTest() {
+	m.testing = true
+	m.tested = false
+}
+
+// SetTestResult shows the outcome of a connection test under the fields
+func (m *FormModel) SetTestResult(elapsed time.Duration, err error) {
+	m.testing = false
+	m.tested = true
+	m.testTime = elapsed
+	m.testErr = err
+}
+
+// testFailureText says why a connection test failed, telling credential,
+// host key and network problems apart
+func testFailureText(err error) string {
+	var netErr net.Error
+	label := i18n.T("error.connection")
+	switch {
+	case ssh.IsAuthError(err):
+		label = i18n.T("error.auth")
+	case errors.Is(err, ssh.ErrHostKeyChanged):
+		label = i18n.T("form.test.host_key")
+	case errors.As(err, &netErr) && netErr.Timeout():
+		label = i18n.T("error.timeout")
+	}
+	return label + ": " + ErrorText(err)
+}
+
+// splitList splits a comma-separated field, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // SetSize sets the view dimensions
 func (m *FormModel) SetSize(width, height int) {
 	m.width = width
 	m.height = height
+
+	// Narrow the inputs to fit beside their labels
+	layout := Layout{Width: width, Height: height}
+	for i := range m.inputs {
+		m.inputs[i].Width = layout.Fit(m.widths[i], formLabelWidth)
+	}
+	m.startup.SetWidth(layout.Fit(textareaWidth, 2))
+	m.notes.SetWidth(layout.Fit(textareaWidth, 2))
 }
 
 // Init initializes the form model
@@ -306,147 +852,428 @@ func (m FormModel) Init() tea.Cmd {
 	return textinput.Blink
 }
 
-// Update handles messages for the form model
-func (m FormModel) Update(msg tea.Msg) (FormModel, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch {
-		case key.Matches(msg, m.keys.Tab), msg.String() == "down":
-			m.nextField()
-		case key.Matches(msg, m.keys.ShiftTab), msg.String() == "up":
-			m.prevField()
-		case msg.String() == " " && m.focusIndex == int(FieldAuthMethod):
-			// Toggle auth method
-			if m.authMethod == model.AuthPassword {
-				m.authMethod = model.AuthKey
-				m.inputs[FieldAuthMethod].SetValue("key")
-			} else {
-				m.authMethod = model.AuthPassword
-				m.inputs[FieldAuthMethod].SetValue("password")
-			}
-			return m, nil
-		case msg.String() == " " && m.focusIndex == int(FieldGroup):
-			// Cycle through groups
-			m.groupIndex = (m.groupIndex + 1) % len(m.groups)
-			m.inputs[FieldGroup].SetValue(m.groups[m.groupIndex])
-			return m, nil
-		default:
-			// Handle input for current field
-			var cmd tea.Cmd
-			m.inputs[m.focusIndex], cmd = m.inputs[m.focusIndex].Update(msg)
-			return m, cmd
+// Captures reports whether the form handles msg itself, rather than the
+// app acting on it (enter saves, esc cancels): while the key picker or a
+// dropdown is open, and for enter in a multi-line field or on a dropdown
+func (m FormModel) Captures(msg tea.KeyMsg) bool {
+	if m.picker != nil || m.group.IsOpen() || m.auth.IsOpen() || m.agentKey.IsOpen() {
+		return true
+	}
+	if msg.String() != "enter" {
+		return false
+	}
+	switch FormField(m.focusIndex) {
+	case FieldStartupCommand, FieldNotes, FieldAuthMethod, FieldAgentKey, FieldGroup:
+		return true
+	}
+	return false
+}
+
+// Name returns the connection name entered
+func (m FormModel) Name() string {
+	return strings.TrimSpace(m.inputs[FieldName].Value())
+}
+
+// ToggleReveal shows the password and key passphrase, hidden again after
+// a while, or hides them when shown. It returns the fields shown that hold
+// a value, by their names in the config, for the audit log.
+func (m *FormModel) ToggleReveal() ([]string, tea.Cmd) {
+	if m.revealed {
+		m.hideSecrets()
+		return nil, nil
+	}
+
+	m.revealed = true
+	m.revealID++
+	m.inputs[FieldPassword].EchoMode = textinput.EchoNormal
+	m.inputs[FieldKeyPassword].EchoMode = textinput.EchoNormal
+
+	var shown []string
+	if m.fieldVisible(FieldPassword) && m.inputs[FieldPassword].Value() != "" {
+		shown = append(shown, "password")
+	}
+	if m.fieldVisible(FieldKeyPassword) && m.inputs[FieldKeyPassword].Value() != "" {
+		shown = append(shown, "key_password")
+	}
+	return shown, revealTimeout(revealerForm, m.revealID)
+}
+
+// hideSecrets masks the password and key passphrase again
+func (m *FormModel) hideSecrets() {
+	m.revealed = false
+	m.inputs[FieldPassword].EchoMode = textinput.EchoPassword
+	m.inputs[FieldKeyPassword].EchoMode = textinput.EchoPassword
+}
+
+// openPicker lists the keys in ~/.ssh and the config dir
+func (m *FormModel) openPicker() {
+	var dirs []string
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".ssh"))
+	}
+	if dir, err := config.ConfigDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+	picker := NewKeyPickerModel(dirs...)
+	m.picker = &picker
+}
+
+// updatePicker passes a message to the key file picker, filling in the
+// picked key. Encrypted keys move the focus to the passphrase.
+func (m FormModel) updatePicker(msg tea.Msg) (FormModel, tea.Cmd) {
+	picker, cmd := m.picker.Update(msg)
+	if !picker.Done() {
+		m.picker = &picker
+		return m, cmd
+	}
+
+	m.picker = nil
+	if key := picker.Chosen(); key != nil {
+		m.touched[FieldKeyPath] = true
+		m.setAuthMethod(model.AuthKey)
+		m.inputs[FieldKeyPath].SetValue(key.Path)
+		if key.Encrypted {
+			return m, m.setFocus(FieldKeyPassword)
 		}
+		return m, m.setFocus(FieldKeyPath)
 	}
+	return m, cmd
+}
 
-	// Update focused input
+// area returns the textarea of a multi-line field, or nil
+func (m *FormModel) area(f FormField) *textarea.Model {
+	switch f {
+	case FieldStartupCommand:
+		return &m.startup
+	case FieldNotes:
+		return &m.notes
+	}
+	return nil
+}
+
+// setFocus moves the focus to field f
+func (m *FormModel) setFocus(f FormField) tea.Cmd {
+	old := FormField(m.focusIndex)
+	m.touched[old] = true
+	m.inputs[old].Blur()
+	if old == FieldTags {
+		m.tags.Blur()
+	} else if ta := m.area(old); ta != nil {
+		ta.Blur()
+	}
+
+	m.focusIndex = int(f)
+	if f == FieldTags {
+		return m.tags.Focus()
+	}
+	if ta := m.area(f); ta != nil {
+		return ta.Focus()
+	}
+	return m.inputs[f].Focus()
+}
+
+// moveWithinArea reports whether up or down should move the cursor inside
+// the focused multi-line field rather than to another field
+func (m *FormModel) moveWithinArea(msg tea.KeyMsg) bool {
+	ta := m.area(FormField(m.focusIndex))
+	if ta == nil {
+		return false
+	}
+	switch msg.String() {
+	case "up":
+		return ta.Line() > 0
+	case "down":
+		return ta.Line() < ta.LineCount()-1
+	}
+	return false
+}
+
+// updateFocused passes a message to the focused field
+func (m FormModel) updateFocused(msg tea.Msg) (FormModel, tea.Cmd) {
 	var cmd tea.Cmd
-	m.inputs[m.focusIndex], cmd = m.inputs[m.focusIndex].Update(msg)
+	f := FormField(m.focusIndex)
+	if _, ok := msg.(tea.KeyMsg); ok {
+		m.touched[f] = true
+	}
+	switch {
+	case f == FieldTags:
+		m.tags, cmd = m.tags.Update(msg)
+	case m.area(f) != nil:
+		ta := m.area(f)
+		*ta, cmd = ta.Update(msg)
+	default:
+		m.inputs[f], cmd = m.inputs[f].Update(msg)
+	}
+	return m, cmd
+}
+
+// Update handles messages for the form model
+func (m FormModel) Update(msg tea.Msg) (FormModel, tea.Cmd) {
+	m, cmd := m.update(msg)
+	m.inspectKey()
 	return m, cmd
 }
 
-func (m *FormModel) nextField() {
-	m.inputs[m.focusIndex].Blur()
-	m.focusIndex++
-	if m.focusIndex >= int(FieldCount) {
-		m.focusIndex = 0
+func (m FormModel) update(msg tea.Msg) (FormModel, tea.Cmd) {
+	if msg, ok := msg.(RevealTimeoutMsg); ok {
+		if msg.view == revealerForm && msg.id == m.revealID {
+			m.hideSecrets()
+		}
+		return m, nil
 	}
-	// Skip password field if using key auth, skip key fields if using password
-	if m.authMethod == model.AuthKey && m.focusIndex == int(FieldPassword) {
-		m.focusIndex = int(FieldKeyPath)
+	if m.picker != nil {
+		return m.updatePicker(msg)
 	}
-	if m.authMethod == model.AuthPassword && (m.focusIndex == int(FieldKeyPath) || m.focusIndex == int(FieldKeyPassword)) {
-		m.focusIndex = int(FieldGroup)
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m.updateFocused(msg)
+	}
+
+	// An open dropdown takes every key until it closes
+	if m.auth.IsOpen() {
+		m.auth = m.auth.Update(keyMsg)
+		m.authMethod = authMethods[m.auth.Index()]
+		return m, nil
 	}
-	m.inputs[m.focusIndex].Focus()
+	if m.group.IsOpen() {
+		m.group = m.group.Update(keyMsg)
+		return m, nil
+	}
+	if m.agentKey.IsOpen() {
+		m.agentKey = m.agentKey.Update(keyMsg)
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, m.keys.Browse) && m.connType != model.ConnTypeTelnet:
+		m.openPicker()
+		return m, nil
+	case (keyMsg.String() == "up" || keyMsg.String() == "down") && m.moveWithinArea(keyMsg):
+		return m.updateFocused(msg)
+	case key.Matches(keyMsg, m.keys.Tab), keyMsg.String() == "down":
+		return m, m.nextField()
+	case key.Matches(keyMsg, m.keys.ShiftTab), keyMsg.String() == "up":
+		return m, m.prevField()
+	case key.Matches(keyMsg, m.keys.Toggle) && m.focusIndex == int(FieldType):
+		// Toggle connection type, moving the port along if it is the default
+		next := model.ConnTypeTelnet
+		if m.connType == model.ConnTypeTelnet {
+			next = model.ConnTypeSSH
+		}
+		if m.inputs[FieldPort].Value() == strconv.Itoa(m.defaults.NewConnectionPort(m.connType)) {
+			m.inputs[FieldPort].SetValue(strconv.Itoa(m.defaults.NewConnectionPort(next)))
+		}
+		m.connType = next
+		return m, nil
+	case m.focusIndex == int(FieldAuthMethod):
+		m.auth = m.auth.Update(keyMsg)
+		return m, nil
+	case m.focusIndex == int(FieldGroup):
+		m.group = m.group.Update(keyMsg)
+		return m, nil
+	case m.focusIndex == int(FieldAgentKey):
+		m.agentKey = m.agentKey.Update(keyMsg)
+		return m, nil
+	case key.Matches(keyMsg, m.keys.Toggle) && m.focusIndex == int(FieldGSSAPI):
+		m.gssapi = !m.gssapi
+		return m, nil
+	case key.Matches(keyMsg, m.keys.Toggle) && m.focusIndex == int(FieldQuietLogin):
+		m.quietLogin = !m.quietLogin
+		return m, nil
+	case key.Matches(keyMsg, m.keys.Toggle) && m.focusIndex == int(FieldShowBanner):
+		m.showBanner = !m.showBanner
+		return m, nil
+	}
+	return m.updateFocused(msg)
 }
 
-func (m *FormModel) prevField() {
-	m.inputs[m.focusIndex].Blur()
-	m.focusIndex--
-	if m.focusIndex < 0 {
-		m.focusIndex = int(FieldCount) - 1
+func (m *FormModel) nextField() tea.Cmd {
+	next := m.focusIndex
+	for {
+		next = (next + 1) % int(FieldCount)
+		if m.fieldVisible(FormField(next)) {
+			break
+		}
 	}
-	// Skip key fields if using password auth, skip password if using key
-	if m.authMethod == model.AuthPassword && (m.focusIndex == int(FieldKeyPath) || m.focusIndex == int(FieldKeyPassword)) {
-		m.focusIndex = int(FieldAuthMethod)
+	return m.setFocus(FormField(next))
+}
+
+func (m *FormModel) prevField() tea.Cmd {
+	prev := m.focusIndex
+	for {
+		prev = (prev + int(FieldCount) - 1) % int(FieldCount)
+		if m.fieldVisible(FormField(prev)) {
+			break
+		}
 	}
-	if m.authMethod == model.AuthKey && m.focusIndex == int(FieldPassword) {
-		m.focusIndex = int(FieldAuthMethod)
+	return m.setFocus(FormField(prev))
+}
+
+// fieldVisible reports whether a field applies to the current connection
+// type and auth method. Hidden fields are skipped when moving focus.
+func (m *FormModel) fieldVisible(f FormField) bool {
+	telnet := m.connType == model.ConnTypeTelnet
+	switch f {
+	case FieldAuthMethod, FieldGSSAPI, FieldJumpHosts, FieldLocale, FieldLocalDir, FieldRemoteDir, FieldQuietLogin, FieldShowBanner:
+		return !telnet
+	case FieldPassword:
+		return !telnet && m.authMethod == model.AuthPassword
+	case FieldKeyPath, FieldKeyPassword, FieldAgentKey:
+		return !telnet && m.authMethod == model.AuthKey
 	}
-	m.inputs[m.focusIndex].Focus()
+	return true
 }
 
 // View renders the form
 func (m FormModel) View() string {
+	if m.picker != nil {
+		return m.picker.View()
+	}
+
 	var b strings.Builder
 
-	title := "Add Connection"
+	title := i18n.T("form.title.add")
 	if m.Editing {
-		title = "Edit Connection"
+		title = i18n.T("form.title.edit")
 	}
 	b.WriteString(styles.TitleStyle.Render(title))
 	b.WriteString("\n\n")
 
+	// GSSAPI needs a build with the gssapi tag
+	gssapiNote := i18n.T("form.note.gssapi")
+	if !ssh.GSSAPISupported() {
+		gssapiNote = i18n.T("form.note.gssapi_unsupported")
+	}
+
 	// Form fields
 	fields := []struct {
 		label string
 		field FormField
-		show  bool
 		note  string
 	}{
-		{"Name", FieldName, true, ""},
-		{"Host", FieldHost, true, ""},
-		{"Port", FieldPort, true, ""},
-		{"User", FieldUser, true, ""},
-		{"Auth", FieldAuthMethod, true, "(space to toggle)"},
-		{"Password", FieldPassword, m.authMethod == model.AuthPassword, ""},
-		{"Key Path", FieldKeyPath, m.authMethod == model.AuthKey, ""},
-		{"Key Password", FieldKeyPassword, m.authMethod == model.AuthKey, "(optional)"},
-		{"Group", FieldGroup, true, "(space to cycle)"},
-		{"Tags", FieldTags, true, "(comma separated)"},
-		{"Startup Cmd", FieldStartupCommand, true, "(runs after connect)"},
+		{i18n.T("form.name"), FieldName, ""},
+		{i18n.T("form.aliases"), FieldAliases, i18n.T("form.note.aliases")},
+		{i18n.T("form.host"), FieldHost, i18n.T("form.note.host")},
+		{i18n.T("form.type"), FieldType, i18n.T("form.note.toggle")},
+		{i18n.T("form.port"), FieldPort, ""},
+		{i18n.T("form.addresses"), FieldAddresses, i18n.T("form.note.addresses")},
+		{i18n.T("form.user"), FieldUser, ""},
+		{i18n.T("form.auth_type"), FieldAuthMethod, i18n.T("form.note.select")},
+		{i18n.T("form.password"), FieldPassword, ""},
+		{i18n.T("form.key_path"), FieldKeyPath, i18n.T("form.note.key_path")},
+		{i18n.T("form.key_passphrase"), FieldKeyPassword, i18n.T("form.note.optional")},
+		{i18n.T("form.agent_key"), FieldAgentKey, i18n.T("form.note.agent_key")},
+		{i18n.T("form.gssapi"), FieldGSSAPI, gssapiNote},
+		{i18n.T("form.group"), FieldGroup, i18n.T("form.note.select")},
+		{i18n.T("form.tags"), FieldTags, i18n.T("form.note.tags")},
+		{i18n.T("form.jump_hosts"), FieldJumpHosts, i18n.T("form.note.jump_hosts")},
+		{i18n.T("form.bind_address"), FieldBindAddress, i18n.T("form.note.bind_address")},
+		{i18n.T("form.term"), FieldTerm, i18n.T("form.note.term")},
+		{i18n.T("form.locale"), FieldLocale, i18n.T("form.note.locale")},
+		{i18n.T("form.local_dir"), FieldLocalDir, i18n.T("form.note.local_dir")},
+		{i18n.T("form.remote_dir"), FieldRemoteDir, i18n.T("form.note.remote_dir")},
+		{i18n.T("form.startup_cmd"), FieldStartupCommand, i18n.T("form.note.startup")},
+		{i18n.T("form.pre_connect"), FieldPreConnect, i18n.T("form.note.pre_connect")},
+		{i18n.T("form.post_disconnect"), FieldPostDisconnect, i18n.T("form.note.post_disconnect")},
+		{i18n.T("form.notes"), FieldNotes, i18n.T("form.note.optional")},
+		{i18n.T("form.quiet_login"), FieldQuietLogin, i18n.T("form.note.quiet_login")},
+		{i18n.T("form.show_banner"), FieldShowBanner, i18n.T("form.note.show_banner")},
+		{i18n.T("form.rotate_after"), FieldRotateAfter, i18n.T("form.note.rotate_after")},
+		{i18n.T("form.expires_at"), FieldExpiresAt, i18n.T("form.note.optional")},
 	}
 
 	for _, f := range fields {
-		if !f.show {
+		if !m.fieldVisible(f.field) {
 			continue
 		}
+		if m.revealed && (f.field == FieldPassword || f.field == FieldKeyPassword) {
+			f.note = i18n.T("form.note.revealed")
+		}
 
 		label := styles.LabelStyle.Render(f.label + ":")
 		if m.focusIndex == int(f.field) {
 			label = styles.SelectedStyle.Render(f.label + ":")
 		}
 
+		// An invalid field shows its error in place of the note
+		note := ""
+		if err := m.shownError(f.field); err != nil {
+			note = styles.ErrorStyle.Render(ErrorText(err))
+		} else if f.note != "" {
+			note = styles.DimStyle.Render(f.note)
+		}
+
 		switch f.field {
-		case FieldAuthMethod:
+		case FieldType:
 			// Show as toggle
-			authDisplay := "[password] / key"
-			if m.authMethod == model.AuthKey {
-				authDisplay = "password / [key]"
+			typeDisplay := "[ssh] / telnet"
+			if m.connType == model.ConnTypeTelnet {
+				typeDisplay = "ssh / [telnet]"
+			}
+			if m.focusIndex == int(FieldType) {
+				typeDisplay = styles.SelectedStyle.Render(typeDisplay)
+			}
+			b.WriteString(label + " " + typeDisplay)
+			if note != "" {
+				b.WriteString(" " + note)
+			}
+			b.WriteString("\n")
+		case FieldAuthMethod, FieldAgentKey, FieldGroup:
+			// Show as dropdown
+			dropdown := m.auth
+			switch f.field {
+			case FieldAgentKey:
+				dropdown = m.agentKey
+			case FieldGroup:
+				dropdown = m.group
+			}
+			b.WriteString(label + " " + dropdown.View(m.focusIndex == int(f.field)))
+			if note != "" && !dropdown.IsOpen() {
+				b.WriteString(" " + note)
 			}
-			if m.focusIndex == int(FieldAuthMethod) {
-				authDisplay = styles.SelectedStyle.Render(authDisplay)
+			b.WriteString("\n")
+		case FieldTags:
+			b.WriteString(label + " " + m.tags.View())
+			if note != "" {
+				b.WriteString(" " + note)
 			}
-			b.WriteString(label + " " + authDisplay)
-			if f.note != "" {
-				b.WriteString(" " + styles.DimStyle.Render(f.note))
+			b.WriteString("\n")
+		case FieldStartupCommand, FieldNotes:
+			// Multi-line, below its label
+			b.WriteString(label)
+			if note != "" {
+				b.WriteString(" " + note)
 			}
 			b.WriteString("\n")
-		case FieldGroup:
-			// Show as selector
-			groupDisplay := "[" + m.inputs[FieldGroup].Value() + "]"
-			if m.focusIndex == int(FieldGroup) {
-				groupDisplay = styles.SelectedStyle.Render(groupDisplay)
+			b.WriteString(m.area(f.field).View())
+			b.WriteString("\n")
+		case FieldGSSAPI, FieldQuietLogin, FieldShowBanner:
+			// Show as on/off toggle
+			enabled := m.quietLogin
+			switch f.field {
+			case FieldGSSAPI:
+				enabled = m.gssapi
+			case FieldShowBanner:
+				enabled = m.showBanner
+			}
+			on, off := i18n.T("form.opt.on"), i18n.T("form.opt.off")
+			toggleDisplay := on + " / [" + off + "]"
+			if enabled {
+				toggleDisplay = "[" + on + "] / " + off
 			}
-			b.WriteString(label + " " + groupDisplay)
-			if f.note != "" {
-				b.WriteString(" " + styles.DimStyle.Render(f.note))
+			if m.focusIndex == int(f.field) {
+				toggleDisplay = styles.SelectedStyle.Render(toggleDisplay)
+			}
+			b.WriteString(label + " " + toggleDisplay)
+			if note != "" {
+				b.WriteString(" " + note)
 			}
 			b.WriteString("\n")
 		default:
 			b.WriteString(label + " " + m.inputs[f.field].View())
-			if f.note != "" {
-				b.WriteString(" " + styles.DimStyle.Render(f.note))
+			if note != "" {
+				b.WriteString(" " + note)
 			}
 			b.WriteString("\n")
 		}
@@ -455,13 +1282,29 @@ func (m FormModel) View() string {
 	// Error message
 	if m.err != nil {
 		b.WriteString("\n")
-		b.WriteString(styles.ErrorStyle.Render("Error: " + m.err.Error()))
+		b.WriteString(styles.ErrorStyle.Render(i18n.T("common.error") + ": " + ErrorText(m.err)))
+		b.WriteString("\n")
+	}
+
+	// Connection test
+	switch {
+	case m.testing:
+		b.WriteString("\n")
+		b.WriteString(styles.DimStyle.Render(i18n.T("form.test.running")))
+		b.WriteString("\n")
+	case m.tested && m.testErr != nil:
+		b.WriteString("\n")
+		b.WriteString(styles.ErrorStyle.Render(testFailureText(m.testErr)))
+		b.WriteString("\n")
+	case m.tested:
+		b.WriteString("\n")
+		b.WriteString(styles.SuccessStyle.Render(fmt.Sprintf(i18n.T("form.test.ok"), m.testTime.Round(time.Millisecond))))
 		b.WriteString("\n")
 	}
 
 	// Help
 	b.WriteString("\n")
-	help := styles.HelpStyle.Render("tab:next field  enter:save  esc:cancel")
+	help := styles.HelpStyle.Render(i18n.T("form.help"))
 	b.WriteString(help)
 
 	return b.String()
@@ -4,16 +4,30 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"gossh/internal/i18n"
 	"gossh/internal/ui/styles"
 )
 
-// HelpModel is the help screen
+// HelpSection is a titled group of key bindings on the help screen. The
+// bindings' help descriptions are i18n keys.
+type HelpSection struct {
+	Title string
+	Keys  []key.Binding
+}
+
+// HelpModel is the help screen, listing the key bindings of the view it
+// was opened from. It scrolls when the list is taller than the terminal;
+// any other key closes it.
 type HelpModel struct {
-	width   int
-	height  int
-	version string
+	width    int
+	height   int
+	version  string
+	sections []HelpSection
+	offset   int  // First line shown
+	closed   bool // A key other than scrolling was pressed
 }
 
 // NewHelpModel creates a new help model
@@ -28,12 +42,24 @@ func (m *HelpModel) SetVersion(version string) {
 	m.version = version
 }
 
+// SetSections sets the key bindings to list, scrolled to the top
+func (m *HelpModel) SetSections(sections ...HelpSection) {
+	m.sections = sections
+	m.offset = 0
+	m.closed = false
+}
+
 // SetSize sets the view dimensions
 func (m *HelpModel) SetSize(width, height int) {
 	m.width = width
 	m.height = height
 }
 
+// Closed reports whether the help screen was dismissed
+func (m HelpModel) Closed() bool {
+	return m.closed
+}
+
 // Init initializes the help model
 func (m HelpModel) Init() tea.Cmd {
 	return nil
@@ -41,117 +67,79 @@ func (m HelpModel) Init() tea.Cmd {
 
 // Update handles messages for the help model
 func (m HelpModel) Update(msg tea.Msg) (HelpModel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	hidden := len(m.lines()) - m.rows()
+	if hidden <= 0 {
+		m.closed = true
+		return m, nil
+	}
+	switch keyMsg.String() {
+	case "up", "k":
+		m.offset = max(m.offset-1, 0)
+	case "down", "j":
+		m.offset = min(m.offset+1, hidden)
+	case "pgup":
+		m.offset = max(m.offset-m.rows(), 0)
+	case "pgdown", " ":
+		m.offset = min(m.offset+m.rows(), hidden)
+	default:
+		m.closed = true
+	}
 	return m, nil
 }
 
-// View renders the help screen
+// lines renders the sections, one line per binding
+func (m HelpModel) lines() []string {
+	var lines []string
+	for _, section := range m.sections {
+		lines = append(lines, styles.LabelStyle.Render(section.Title))
+		for _, binding := range section.Keys {
+			if !binding.Enabled() {
+				continue
+			}
+			h := binding.Help()
+			lines = append(lines, "  "+styles.SelectedStyle.Render(fmt.Sprintf("%-10s", h.Key))+"  "+i18n.T(h.Desc))
+		}
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// title renders the heading with the version
+func (m HelpModel) title() string {
+	return styles.TitleStyle.Render(fmt.Sprintf("%s - v%s", i18n.T("help.title"), m.version))
+}
+
+// rows returns how many lines of sections fit, below the title and a
+// blank line and above the closing hint, which may wrap onto two lines
+func (m HelpModel) rows() int {
+	rows := Layout{Width: m.width, Height: m.height}.DialogRows()
+	if rows == 0 {
+		return len(m.lines())
+	}
+	return max(rows-lipgloss.Height(m.title())-3, 1)
+}
+
+// View renders the help screen in a dialog box
 func (m HelpModel) View() string {
 	var b strings.Builder
 
-	b.WriteString(styles.TitleStyle.Render(fmt.Sprintf("%s - v%s", i18n.T("help.title"), m.version)))
+	b.WriteString(m.title())
 	b.WriteString("\n\n")
 
-	sections := []struct {
-		title string
-		keys  []struct {
-			key  string
-			desc string
-		}
-	}{
-		{
-			title: i18n.T("help.navigation"),
-			keys: []struct {
-				key  string
-				desc string
-			}{
-				{"up/k", i18n.T("help.key.up")},
-				{"down/j", i18n.T("help.key.down")},
-				{"g", i18n.T("help.key.top")},
-				{"G", i18n.T("help.key.bottom")},
-				{"/", i18n.T("help.key.search")},
-				{"Enter", i18n.T("help.key.connect")},
-			},
-		},
-		{
-			title: i18n.T("help.connection"),
-			keys: []struct {
-				key  string
-				desc string
-			}{
-				{"a", i18n.T("help.key.add")},
-				{"e", i18n.T("help.key.edit")},
-				{"d", i18n.T("help.key.delete")},
-				{"t", i18n.T("help.key.test")},
-			},
-		},
-		{
-			title: i18n.T("help.form"),
-			keys: []struct {
-				key  string
-				desc string
-			}{
-				{"Tab", i18n.T("help.key.tab")},
-				{"Shift+Tab", i18n.T("help.key.shifttab")},
-				{"Space", i18n.T("help.key.space")},
-				{"Enter", i18n.T("help.key.save")},
-				{"Esc", i18n.T("help.key.cancel")},
-			},
-		},
-		{
-			title: i18n.T("help.search"),
-			keys: []struct {
-				key  string
-				desc string
-			}{
-				{"Type", i18n.T("help.key.type")},
-				{"Enter", i18n.T("help.key.confirm")},
-				{"Esc", i18n.T("help.key.cancel")},
-			},
-		},
-		{
-			title: i18n.T("help.general"),
-			keys: []struct {
-				key  string
-				desc string
-			}{
-				{"s", i18n.T("help.key.settings")},
-				{"?", i18n.T("help.key.help")},
-				{"q", i18n.T("help.key.quit")},
-				{"Esc", i18n.T("help.key.back")},
-			},
-		},
-		{
-			title: i18n.T("help.cli"),
-			keys: []struct {
-				key  string
-				desc string
-			}{
-				{"gossh list", i18n.T("help.cli.list")},
-				{"gossh connect <name>", i18n.T("help.cli.connect")},
-				{"gossh export [file]", i18n.T("help.cli.export")},
-				{"gossh import <file>", i18n.T("help.cli.import")},
-				{"gossh check", i18n.T("help.cli.check")},
-				{"gossh sftp <name>", i18n.T("help.cli.sftp")},
-				{"gossh forward <name>", i18n.T("help.cli.forward")},
-				{"gossh exec <cmd>", i18n.T("help.cli.exec")},
-			},
-		},
-	}
+	lines := m.lines()
+	end := min(m.offset+m.rows(), len(lines))
+	b.WriteString(strings.Join(lines[m.offset:end], "\n"))
+	b.WriteString("\n")
 
-	for _, section := range sections {
-		b.WriteString(styles.LabelStyle.Render(section.title))
-		b.WriteString("\n")
-		for _, k := range section.keys {
-			b.WriteString("  ")
-			b.WriteString(styles.SelectedStyle.Render(k.key))
-			b.WriteString("  ")
-			b.WriteString(k.desc)
-			b.WriteString("\n")
-		}
-		b.WriteString("\n")
+	hint := i18n.T("help.return")
+	if end-m.offset < len(lines) {
+		hint = i18n.T("help.scroll") + " • " + hint
 	}
+	b.WriteString(styles.HelpStyle.Render(hint))
 
-	b.WriteString(styles.HelpStyle.Render(i18n.T("help.return")))
-
-	return b.String()
+	return Layout{Width: m.width, Height: m.height}.Dialog(b.String())
 }
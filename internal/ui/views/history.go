@@ -0,0 +1,169 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"gossh/internal/config"
+	"gossh/internal/i18n"
+	"gossh/internal/model"
+	"gossh/internal/ui/styles"
+)
+
+// HistoryKeyMap defines key bindings for the edit history view
+type HistoryKeyMap struct {
+	Up      key.Binding
+	Down    key.Binding
+	Restore key.Binding
+	Back    key.Binding
+}
+
+// DefaultHistoryKeyMap returns default edit history key bindings
+var DefaultHistoryKeyMap = HistoryKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+	),
+	Restore: key.NewBinding(
+		key.WithKeys("r"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc", "q"),
+	),
+}
+
+// HistoryModel lists the edits of a connection, newest first, with what
+// the selected one changed. A revision can be restored.
+type HistoryModel struct {
+	cfg      *config.Manager
+	conn     model.Connection
+	cursor   int
+	keys     HistoryKeyMap
+	message  string
+	restored bool
+	width    int
+	height   int
+	done     bool
+}
+
+// NewHistoryModel creates the history view of conn
+func NewHistoryModel(cfg *config.Manager, conn model.Connection) HistoryModel {
+	return HistoryModel{
+		cfg:  cfg,
+		conn: conn,
+		keys: DefaultHistoryKeyMap,
+	}
+}
+
+// SetSize sets the view dimensions
+func (m *HistoryModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Done reports whether the view was closed
+func (m HistoryModel) Done() bool {
+	return m.done
+}
+
+// Restored reports whether a revision was restored
+func (m HistoryModel) Restored() bool {
+	return m.restored
+}
+
+// revisions returns the connection's revisions, newest first
+func (m HistoryModel) revisions() []model.Revision {
+	revisions := m.cfg.Revisions(m.conn.ID)
+	for i, j := 0, len(revisions)-1; i < j; i, j = i+1, j-1 {
+		revisions[i], revisions[j] = revisions[j], revisions[i]
+	}
+	return revisions
+}
+
+// Update handles a key
+func (m HistoryModel) Update(msg tea.KeyMsg) (HistoryModel, tea.Cmd) {
+	revisions := m.revisions()
+	m.message = ""
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.done = true
+	case key.Matches(msg, m.keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.cursor < len(revisions)-1 {
+			m.cursor++
+		}
+	case key.Matches(msg, m.keys.Restore):
+		if m.cursor >= len(revisions) {
+			return m, nil
+		}
+		conn, err := m.cfg.RestoreRevision(m.conn.ID, len(revisions)-1-m.cursor)
+		if err != nil {
+			m.message = i18n.T("common.error") + ": " + ErrorText(err)
+			return m, nil
+		}
+		m.message = fmt.Sprintf(i18n.T("history.restored"), revisions[m.cursor].At.Local().Format("2006-01-02 15:04"))
+		m.conn = conn
+		m.restored = true
+		m.cursor = 0 // The restore is now the newest edit
+	}
+	return m, nil
+}
+
+// View renders the history
+func (m HistoryModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.TitleStyle.Render(fmt.Sprintf(i18n.T("history.title"), m.conn.Name)))
+	b.WriteString("\n\n")
+
+	width := Layout{Width: m.width}.DialogWidth()
+	revisions := m.revisions()
+	if len(revisions) == 0 {
+		b.WriteString(styles.DimStyle.Render("  "+i18n.T("history.empty")) + "\n")
+	}
+	for i, r := range revisions {
+		cursor := "  "
+		style := lipgloss.NewStyle()
+		if i == m.cursor {
+			cursor = "▸ "
+			style = styles.SelectedStyle
+		}
+		by := r.By
+		if by == "" {
+			by = "-"
+		}
+		row := cursor + style.Render(fmt.Sprintf("%s  %-10s", r.At.Local().Format("01-02 15:04"), Ellipsis(by, 10))) + "  "
+		b.WriteString(row + styles.DimStyle.Render(Ellipsis(strings.Join(r.Fields, ", "), width-lipgloss.Width(row))) + "\n")
+	}
+
+	if m.cursor < len(revisions) {
+		if changes, err := m.cfg.RevisionChanges(m.conn.ID, len(revisions)-1-m.cursor); err == nil {
+			b.WriteString("\n")
+			for _, c := range changes {
+				line := fmt.Sprintf("  %s %s %s %s",
+					styles.LabelStyle.Render(c.Field+":"),
+					styles.ErrorStyle.Render(mergeValue(c.Before, c.Secret)),
+					styles.DimStyle.Render("→"),
+					styles.SuccessStyle.Render(mergeValue(c.After, c.Secret)))
+				b.WriteString(Ellipsis(line, width) + "\n")
+			}
+		}
+	}
+
+	if m.message != "" {
+		b.WriteString("\n" + styles.WarningStyle.Render(m.message) + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(styles.HelpStyle.Render(i18n.T("history.help")))
+
+	return Layout{Width: m.width, Height: m.height}.Dialog(b.String())
+}
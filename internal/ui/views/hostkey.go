@@ -150,7 +150,7 @@ func (m HostKeyModel) View() string {
 
 	// Old key for changed status
 	if m.result.Status == ssh.HostKeyChanged && m.result.OldKey != "" {
-		b.WriteString(styles.WarningStyle.Render("Previous fingerprint:"))
+		b.WriteString(styles.WarningStyle.Render(i18n.T("hostkey.previous")))
 		b.WriteString("\n")
 		b.WriteString(styles.DimStyle.Render("  " + m.result.OldKey))
 		b.WriteString("\n\n")
@@ -183,5 +183,5 @@ func (m HostKeyModel) View() string {
 	help := styles.HelpStyle.Render(i18n.T("hostkey.help"))
 	b.WriteString(help)
 
-	return styles.DialogStyle.Render(b.String())
+	return Layout{Width: m.width, Height: m.height}.Dialog(b.String())
 }
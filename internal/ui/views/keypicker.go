@@ -0,0 +1,91 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gossh/internal/i18n"
+	"gossh/internal/ssh"
+	"gossh/internal/ui/styles"
+)
+
+// KeyPickerModel is an overlay listing the private keys found in a few
+// directories to pick one from
+type KeyPickerModel struct {
+	dirs   []string
+	keys   []ssh.KeyFile
+	cursor int
+	done   bool
+	chosen *ssh.KeyFile
+}
+
+// NewKeyPickerModel lists the private keys in dirs
+func NewKeyPickerModel(dirs ...string) KeyPickerModel {
+	return KeyPickerModel{dirs: dirs, keys: ssh.FindKeys(dirs...)}
+}
+
+// Done reports whether a key was picked or the picker was closed
+func (m KeyPickerModel) Done() bool {
+	return m.done
+}
+
+// Chosen returns the picked key, or nil when the picker was closed
+func (m KeyPickerModel) Chosen() *ssh.KeyFile {
+	return m.chosen
+}
+
+// Update handles messages for the key picker
+func (m KeyPickerModel) Update(msg tea.Msg) (KeyPickerModel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch keyMsg.String() {
+	case "up", "k", "shift+tab":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j", "tab":
+		if m.cursor < len(m.keys)-1 {
+			m.cursor++
+		}
+	case "enter":
+		if len(m.keys) > 0 {
+			m.chosen = &m.keys[m.cursor]
+		}
+		m.done = true
+	case "esc":
+		m.done = true
+	}
+	return m, nil
+}
+
+// View renders the key picker
+func (m KeyPickerModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.TitleStyle.Render(i18n.T("form.keys.title")))
+	b.WriteString("\n\n")
+
+	if len(m.keys) == 0 {
+		b.WriteString(styles.DimStyle.Render(fmt.Sprintf(i18n.T("form.keys.none"), strings.Join(m.dirs, ", "))))
+		b.WriteString("\n")
+	}
+	for i, key := range m.keys {
+		line := fmt.Sprintf("%-50s %-20s", key.Path, key.Type)
+		if key.Encrypted {
+			line += " " + i18n.T("form.keys.encrypted")
+		}
+		if i == m.cursor {
+			b.WriteString(styles.SelectedStyle.Render("> " + line))
+		} else {
+			b.WriteString("  " + line)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(styles.HelpStyle.Render(i18n.T("form.keys.help")))
+	return b.String()
+}
@@ -0,0 +1,96 @@
+package views
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"gossh/internal/ui/styles"
+)
+
+// minColumn is the narrowest a shrunk column or input gets
+const minColumn = 8
+
+// Layout fits views to the terminal. A zero width or height, before the
+// first resize, means no limit.
+type Layout struct {
+	Width  int
+	Height int
+}
+
+// Dialog renders content in a dialog box, narrowed when the terminal is
+// smaller than the usual width
+func (l Layout) Dialog(content string) string {
+	style := styles.DialogStyle
+	// The style's width includes padding but not the border
+	if avail := l.Width - style.GetHorizontalBorderSize(); l.Width > 0 && avail < style.GetWidth() {
+		style = style.Width(max(avail, minColumn))
+	}
+	return style.Render(content)
+}
+
+// DialogRows returns how many lines of content fit in a dialog box
+func (l Layout) DialogRows() int {
+	if l.Height <= 0 {
+		return 0
+	}
+	return max(l.Height-styles.DialogStyle.GetVerticalFrameSize(), 1)
+}
+
+// DialogWidth returns how many columns of content fit in a dialog box
+func (l Layout) DialogWidth() int {
+	style := styles.DialogStyle
+	width := style.GetWidth()
+	if l.Width > 0 {
+		width = min(width, max(l.Width-style.GetHorizontalBorderSize(), minColumn))
+	}
+	return max(width-style.GetHorizontalPadding(), 1)
+}
+
+// Column returns the width of a column holding values: the widest value,
+// but at most share of the terminal width
+func (l Layout) Column(values []string, share float64) int {
+	width := 0
+	for _, v := range values {
+		width = max(width, ansi.StringWidth(v))
+	}
+	if l.Width > 0 {
+		width = min(width, max(int(float64(l.Width)*share), minColumn))
+	}
+	return width
+}
+
+// Fit returns width, shrunk to what is left of the terminal width after
+// used columns
+func (l Layout) Fit(width, used int) int {
+	if l.Width <= 0 {
+		return width
+	}
+	return max(min(width, l.Width-used), minColumn)
+}
+
+// ScrollX returns the part of line from column offset that fits the
+// terminal width, keeping its styles
+func (l Layout) ScrollX(line string, offset int) string {
+	if l.Width <= 0 {
+		return line
+	}
+	return ansi.Cut(line, offset, offset+l.Width)
+}
+
+// Ellipsis shortens s to width columns, marking the cut with "…". Styles
+// in s are kept.
+func Ellipsis(s string, width int) string {
+	if width <= 0 || ansi.StringWidth(s) <= width {
+		return s
+	}
+	return ansi.Truncate(s, width, "…")
+}
+
+// Pad fills s with spaces on the right to width columns
+func Pad(s string, width int) string {
+	if gap := width - lipgloss.Width(s); gap > 0 {
+		return s + strings.Repeat(" ", gap)
+	}
+	return s
+}
@@ -2,11 +2,16 @@ package views
 
 import (
 	"fmt"
+	"hash/fnv"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"gossh/internal/i18n"
 	"gossh/internal/model"
 	"gossh/internal/ui/styles"
@@ -14,70 +19,112 @@ import (
 
 // ListKeyMap defines key bindings for the list view
 type ListKeyMap struct {
-	Up     key.Binding
-	Down   key.Binding
-	Enter  key.Binding
-	Add    key.Binding
-	Edit   key.Binding
-	Delete key.Binding
-	Help   key.Binding
-	Quit   key.Binding
-	Search key.Binding
-	Top    key.Binding
-	Bottom key.Binding
+	Up      key.Binding
+	Down    key.Binding
+	Enter   key.Binding
+	Add     key.Binding
+	Edit    key.Binding
+	Delete  key.Binding
+	Help    key.Binding
+	Quit    key.Binding
+	Search  key.Binding
+	Top     key.Binding
+	Bottom  key.Binding
+	Left    key.Binding
+	Right   key.Binding
+	Layout  key.Binding
+	Sort    key.Binding
+	Reverse key.Binding
+	Filters key.Binding
+}
+
+// HelpSection lists the bindings for moving through the list
+func (k ListKeyMap) HelpSection() HelpSection {
+	return HelpSection{
+		Title: i18n.T("help.navigation"),
+		Keys:  []key.Binding{k.Up, k.Down, k.Top, k.Bottom, k.Left, k.Right, k.Search, k.Filters, k.Enter, k.Layout, k.Sort, k.Reverse},
+	}
 }
 
 // DefaultListKeyMap returns default list key bindings
 var DefaultListKeyMap = ListKeyMap{
 	Up: key.NewBinding(
 		key.WithKeys("up", "k"),
-		key.WithHelp("↑/k", "up"),
+		key.WithHelp("↑/k", "help.key.up"),
 	),
 	Down: key.NewBinding(
 		key.WithKeys("down", "j"),
-		key.WithHelp("↓/j", "down"),
+		key.WithHelp("↓/j", "help.key.down"),
 	),
 	Enter: key.NewBinding(
 		key.WithKeys("enter"),
-		key.WithHelp("enter", "connect"),
+		key.WithHelp("enter", "help.key.connect"),
 	),
 	Add: key.NewBinding(
 		key.WithKeys("a"),
-		key.WithHelp("a", "add"),
+		key.WithHelp("a", "help.key.add"),
 	),
 	Edit: key.NewBinding(
 		key.WithKeys("e"),
-		key.WithHelp("e", "edit"),
+		key.WithHelp("e", "help.key.edit"),
 	),
 	Delete: key.NewBinding(
 		key.WithKeys("d"),
-		key.WithHelp("d", "delete"),
+		key.WithHelp("d", "help.key.delete"),
 	),
 	Help: key.NewBinding(
 		key.WithKeys("?"),
-		key.WithHelp("?", "help"),
+		key.WithHelp("?", "help.key.help"),
 	),
 	Quit: key.NewBinding(
 		key.WithKeys("q", "ctrl+c"),
-		key.WithHelp("q", "quit"),
+		key.WithHelp("q", "help.key.quit"),
 	),
 	Search: key.NewBinding(
 		key.WithKeys("/"),
-		key.WithHelp("/", "search"),
+		key.WithHelp("/", "help.key.search"),
 	),
 	Top: key.NewBinding(
 		key.WithKeys("g"),
-		key.WithHelp("g", "top"),
+		key.WithHelp("g", "help.key.top"),
 	),
 	Bottom: key.NewBinding(
 		key.WithKeys("G"),
-		key.WithHelp("G", "bottom"),
+		key.WithHelp("G", "help.key.bottom"),
+	),
+	Left: key.NewBinding(
+		key.WithKeys("left", "h"),
+		key.WithHelp("←/h", "help.key.left"),
+	),
+	Right: key.NewBinding(
+		key.WithKeys("right", "l"),
+		key.WithHelp("→/l", "help.key.right"),
+	),
+	Layout: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "help.key.layout"),
+	),
+	Sort: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "help.key.sort"),
+	),
+	Reverse: key.NewBinding(
+		key.WithKeys("O"),
+		key.WithHelp("O", "help.key.sort_reverse"),
+	),
+	Filters: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "help.key.filters"),
 	),
 }
 
+// scrollStep is how many columns left and right scroll the list
+const scrollStep = 8
+
 // ListModel is the connection list view
 type ListModel struct {
 	connections []model.Connection
+	index       *model.SearchIndex // Search text of connections, rebuilt with them
 	filtered    []model.Connection
 	cursor      int
 	width       int
@@ -86,30 +133,115 @@ type ListModel struct {
 	searchInput textinput.Model
 	searching   bool
 	searchQuery string
-	groupView   bool // If true, show grouped by group
+	groupView   bool            // If true, show grouped by group
+	checking    map[string]bool // Connections with a health check in flight
+	xOffset     int             // Columns scrolled right, for narrow terminals
+	groupColors map[string]lipgloss.Color
+	layout      model.ListLayout
+	tableCols   []model.ListColumn // Columns of the table layout
+	sortBy      model.ListColumn   // Table column sorted by, empty for config order
+	sortDesc    bool
 }
 
 // NewListModel creates a new list model
 func NewListModel() ListModel {
 	search := textinput.New()
-	search.Placeholder = "Search..."
+	search.Placeholder = i18n.T("list.search.placeholder")
 	search.CharLimit = 50
 	search.Width = 30
 	search.Prompt = "/ "
 
 	return ListModel{
 		connections: []model.Connection{},
+		index:       model.NewSearchIndex(nil),
 		filtered:    []model.Connection{},
 		cursor:      0,
 		keys:        DefaultListKeyMap,
 		searchInput: search,
 		groupView:   true,
+		checking:    make(map[string]bool),
+		groupColors: make(map[string]lipgloss.Color),
+		layout:      model.LayoutLines,
+		tableCols:   model.ListColumns,
+	}
+}
+
+// ApplySettings takes the layout, table columns and sort order from the
+// settings
+func (m *ListModel) ApplySettings(settings model.Settings) {
+	m.layout = settings.Layout()
+	m.tableCols = settings.TableColumns()
+	m.sortBy = settings.ListSort
+	m.sortDesc = settings.ListSortDesc
+	m.applyFilter()
+}
+
+// Layout returns the layout the list is drawn in
+func (m *ListModel) Layout() model.ListLayout {
+	return m.layout
+}
+
+// ToggleLayout switches between the lines and table layouts
+func (m *ListModel) ToggleLayout() {
+	if m.layout == model.LayoutTable {
+		m.layout = model.LayoutLines
+	} else {
+		m.layout = model.LayoutTable
+	}
+	m.xOffset = 0
+	m.applyFilter()
+}
+
+// Sort returns the table column the list is sorted by, empty for the
+// config order, and whether the order is descending
+func (m *ListModel) Sort() (model.ListColumn, bool) {
+	return m.sortBy, m.sortDesc
+}
+
+// CycleSort sorts the table by the next column, going back to the config
+// order after the last one
+func (m *ListModel) CycleSort() {
+	i := slices.Index(m.tableCols, m.sortBy)
+	if i+1 < len(m.tableCols) {
+		m.sortBy = m.tableCols[i+1]
+	} else {
+		m.sortBy = ""
+	}
+	m.sortDesc = false
+	m.applyFilter()
+}
+
+// ReverseSort flips the sort order of the table
+func (m *ListModel) ReverseSort() {
+	if m.sortBy != "" {
+		m.sortDesc = !m.sortDesc
+		m.applyFilter()
 	}
 }
 
+// SetGroups sets the groups whose colors tint the list
+func (m *ListModel) SetGroups(groups []model.Group) {
+	m.groupColors = make(map[string]lipgloss.Color, len(groups))
+	for i, g := range groups {
+		m.groupColors[g.Name] = styles.GroupColor(g.Color, i)
+	}
+}
+
+// groupColor returns the color of a group. Groups missing from the config
+// get a palette color picked by name, so it stays the same between runs.
+func (m *ListModel) groupColor(name string) lipgloss.Color {
+	if color, ok := m.groupColors[name]; ok {
+		return color
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return styles.GroupPalette[h.Sum32()%uint32(len(styles.GroupPalette))]
+}
+
 // SetConnections updates the connections list
 func (m *ListModel) SetConnections(conns []model.Connection) {
 	m.connections = conns
+	m.index = model.NewSearchIndex(conns)
 	m.applyFilter()
 }
 
@@ -118,14 +250,19 @@ func (m *ListModel) applyFilter() {
 	if m.searchQuery == "" {
 		m.filtered = m.connections
 	} else {
-		m.filtered = make([]model.Connection, 0)
-		for _, conn := range m.connections {
-			if conn.MatchesFilter(m.searchQuery) {
-				m.filtered = append(m.filtered, conn)
-			}
+		hits := m.index.Match(m.searchQuery)
+		m.filtered = make([]model.Connection, len(hits))
+		for i, hit := range hits {
+			m.filtered[i] = m.connections[hit]
 		}
 	}
 
+	// The table can be sorted; the grouped lines keep the config order
+	if m.layout == model.LayoutTable && slices.Contains(m.tableCols, m.sortBy) {
+		m.filtered = slices.Clone(m.filtered)
+		model.SortConnections(m.filtered, m.sortBy, m.sortDesc)
+	}
+
 	// Adjust cursor if needed
 	if m.cursor >= len(m.filtered) && len(m.filtered) > 0 {
 		m.cursor = len(m.filtered) - 1
@@ -135,6 +272,20 @@ func (m *ListModel) applyFilter() {
 	}
 }
 
+// Visible returns the connections currently shown, after the search filter
+func (m *ListModel) Visible() []model.Connection {
+	return m.filtered
+}
+
+// SetChecking marks a connection as having a health check in flight
+func (m *ListModel) SetChecking(id string, checking bool) {
+	if checking {
+		m.checking[id] = true
+	} else {
+		delete(m.checking, id)
+	}
+}
+
 // SetSize sets the view dimensions
 func (m *ListModel) SetSize(width, height int) {
 	m.width = width
@@ -154,6 +305,21 @@ func (m *ListModel) IsSearching() bool {
 	return m.searching
 }
 
+// Filter returns the search filter applied to the list
+func (m *ListModel) Filter() string {
+	return m.searchQuery
+}
+
+// SetFilter filters the list with query, e.g. a saved filter, without
+// entering search mode
+func (m *ListModel) SetFilter(query string) {
+	m.searching = false
+	m.searchQuery = query
+	m.searchInput.SetValue(query)
+	m.searchInput.Blur()
+	m.applyFilter()
+}
+
 // StartSearch enters search mode
 func (m *ListModel) StartSearch() {
 	m.searching = true
@@ -213,6 +379,10 @@ func (m ListModel) Update(msg tea.Msg) (ListModel, tea.Cmd) {
 			if len(m.filtered) > 0 {
 				m.cursor = len(m.filtered) - 1
 			}
+		case key.Matches(msg, m.keys.Left):
+			m.xOffset = max(min(m.xOffset, m.maxScroll())-scrollStep, 0)
+		case key.Matches(msg, m.keys.Right):
+			m.xOffset = min(m.xOffset+scrollStep, m.maxScroll())
 		}
 	}
 	return m, nil
@@ -221,6 +391,8 @@ func (m ListModel) Update(msg tea.Msg) (ListModel, tea.Cmd) {
 // View renders the list
 func (m ListModel) View() string {
 	var b strings.Builder
+	layout := Layout{Width: m.width, Height: m.height}
+	xOffset := min(m.xOffset, m.maxScroll())
 
 	// Title
 	title := styles.TitleStyle.Render(i18n.T("list.title"))
@@ -231,9 +403,6 @@ func (m ListModel) View() string {
 	if m.searching {
 		b.WriteString(m.searchInput.View())
 		b.WriteString("\n\n")
-	} else if m.searchQuery != "" {
-		b.WriteString(styles.DimStyle.Render(fmt.Sprintf(i18n.T("list.filter"), m.searchQuery)))
-		b.WriteString("\n\n")
 	}
 
 	if len(m.filtered) == 0 {
@@ -243,15 +412,19 @@ func (m ListModel) View() string {
 			b.WriteString(styles.DimStyle.Render(i18n.T("list.empty")))
 		}
 		b.WriteString("\n")
+	} else if m.layout == model.LayoutTable {
+		widths := m.tableWidths()
+		b.WriteString(layout.ScrollX(m.renderTableHeader(widths), xOffset) + "\n")
+		for i, conn := range m.filtered {
+			b.WriteString(layout.ScrollX(m.renderTableRow(conn, i == m.cursor, widths), xOffset) + "\n")
+		}
+		b.WriteString("\n")
 	} else if m.groupView {
 		// Group by group name
 		groups := make(map[string][]model.Connection)
 		groupOrder := []string{}
 		for _, conn := range m.filtered {
 			group := conn.Group
-			if group == "" {
-				group = i18n.T("list.ungrouped")
-			}
 			if _, exists := groups[group]; !exists {
 				groupOrder = append(groupOrder, group)
 			}
@@ -260,26 +433,34 @@ func (m ListModel) View() string {
 
 		// Track absolute index for cursor
 		idx := 0
+		cols := m.columns()
 		for _, groupName := range groupOrder {
 			conns := groups[groupName]
-			// Group header
+			// Group header, tinted with the group's color
 			groupStyle := styles.LabelStyle
-			b.WriteString(groupStyle.Render("▾ " + groupName))
+			header := groupName
+			if groupName == "" {
+				header = i18n.T("list.ungrouped")
+			} else {
+				groupStyle = groupStyle.Foreground(m.groupColor(groupName))
+			}
+			b.WriteString(groupStyle.Render("▾ " + header))
 			b.WriteString(styles.DimStyle.Render(fmt.Sprintf(" (%d)", len(conns))))
 			b.WriteString("\n")
 
 			for _, conn := range conns {
-				line := m.renderConnectionLine(conn, idx == m.cursor)
-				b.WriteString("  " + line + "\n")
+				line := m.renderConnectionLine(conn, idx == m.cursor, cols)
+				b.WriteString(layout.ScrollX("  "+line, xOffset) + "\n")
 				idx++
 			}
 			b.WriteString("\n")
 		}
 	} else {
 		// Flat list
+		cols := m.columns()
 		for i, conn := range m.filtered {
-			line := m.renderConnectionLine(conn, i == m.cursor)
-			b.WriteString(line + "\n")
+			line := m.renderConnectionLine(conn, i == m.cursor, cols)
+			b.WriteString(layout.ScrollX(line, xOffset) + "\n")
 		}
 	}
 
@@ -303,7 +484,65 @@ func (m ListModel) View() string {
 	return b.String()
 }
 
-func (m *ListModel) renderConnectionLine(conn model.Connection, selected bool) string {
+// listColumns are the widths of the aligned parts of connection lines
+type listColumns struct {
+	name    int
+	details int
+}
+
+// columns sizes the name and user@host:port columns to the connections
+// shown, each taking at most a share of the terminal width
+func (m *ListModel) columns() listColumns {
+	layout := Layout{Width: m.width, Height: m.height}
+	var names, details []string
+	for _, conn := range m.filtered {
+		names = append(names, conn.Name)
+		details = append(details, connDetails(conn, 0))
+	}
+	return listColumns{
+		name:    layout.Column(names, 0.3),
+		details: layout.Column(details, 0.4),
+	}
+}
+
+// maxScroll returns how far the widest connection line can be scrolled
+// right before its end reaches the right edge
+func (m *ListModel) maxScroll() int {
+	if m.width <= 0 {
+		return 0
+	}
+	widest := 0
+	if m.layout == model.LayoutTable {
+		widths := m.tableWidths()
+		for _, conn := range m.filtered {
+			widest = max(widest, ansi.StringWidth(m.renderTableRow(conn, false, widths)))
+		}
+		return max(widest-m.width, 0)
+	}
+	cols := m.columns()
+	for _, conn := range m.filtered {
+		// Grouped lines are indented by two
+		widest = max(widest, ansi.StringWidth(m.renderConnectionLine(conn, false, cols))+2)
+	}
+	return max(widest-m.width, 0)
+}
+
+// connDetails formats user@host:port, shortening the host with an ellipsis
+// to fit width columns; zero width means no limit
+func connDetails(conn model.Connection, width int) string {
+	prefix := conn.User + "@"
+	suffix := fmt.Sprintf(":%d", conn.Port)
+	if len(conn.Addresses) > 0 && conn.LastAddress != "" && conn.LastAddress != conn.Host {
+		suffix += " " + fmt.Sprintf(i18n.T("list.via"), conn.LastAddress)
+	}
+	host := conn.Host
+	if width > 0 {
+		host = Ellipsis(host, max(width-ansi.StringWidth(prefix+suffix), minColumn))
+	}
+	return Ellipsis(prefix+host+suffix, width)
+}
+
+func (m *ListModel) renderConnectionLine(conn model.Connection, selected bool, cols listColumns) string {
 	cursor := "  "
 	style := styles.NormalStyle
 	if selected {
@@ -319,14 +558,25 @@ func (m *ListModel) renderConnectionLine(conn model.Connection, selected bool) s
 	case model.ConnStatusFailed:
 		statusIcon = styles.ErrorStyle.Render("●")
 	}
+	if m.checking[conn.ID] {
+		statusIcon = styles.DimStyle.Render("◌")
+	}
+
+	// Group marker, so the group shows in the flat list too
+	marker := " "
+	if conn.Group != "" {
+		marker = lipgloss.NewStyle().Foreground(m.groupColor(conn.Group)).Render("▌")
+	}
 
-	// Format: name (user@host:port)
-	name := style.Render(conn.Name)
-	details := styles.DimStyle.Render(fmt.Sprintf("%s@%s:%d", conn.User, conn.Host, conn.Port))
+	// Format: name user@host:port, in aligned columns
+	name := style.Render(Pad(Ellipsis(conn.Name, cols.name), cols.name))
+	details := styles.DimStyle.Render(Pad(connDetails(conn, cols.details), cols.details))
 
 	// Auth indicator
 	authIcon := "[key]"
-	if conn.AuthMethod == model.AuthPassword {
+	if conn.IsTelnet() {
+		authIcon = "[telnet]"
+	} else if conn.AuthType == model.AuthPassword {
 		authIcon = "[pwd]"
 	}
 
@@ -336,5 +586,157 @@ func (m *ListModel) renderConnectionLine(conn model.Connection, selected bool) s
 		tags = styles.DimStyle.Render(" [" + strings.Join(conn.Tags, ", ") + "]")
 	}
 
-	return fmt.Sprintf("%s%s %s %s %s%s", cursor, statusIcon, name, details, authIcon, tags)
+	// Stale credentials
+	var stale string
+	switch conn.Credentials(time.Now(), 0) {
+	case model.CredentialRotate:
+		stale = " " + styles.WarningStyle.Render("⚠ "+i18n.T("list.stale.rotate"))
+	case model.CredentialExpired:
+		stale = " " + styles.ErrorStyle.Render("⚠ "+i18n.T("list.stale.expired"))
+	}
+
+	// System facts
+	var system string
+	if summary := systemSummary(conn, time.Now()); summary != "" {
+		system = styles.DimStyle.Render(" · " + summary)
+	}
+
+	return fmt.Sprintf("%s%s%s %s %s %s%s%s%s", cursor, marker, statusIcon, name, details, authIcon, tags, system, stale)
+}
+
+// systemSummary describes the host's system from its facts, e.g.
+// "CentOS 7, up 12d", or returns "" when none were gathered
+func systemSummary(conn model.Connection, now time.Time) string {
+	if conn.Facts == nil {
+		return ""
+	}
+	var parts []string
+	if name := conn.Facts.OS(); name != "" {
+		parts = append(parts, name)
+	}
+	if uptime := conn.Facts.Uptime(now); uptime > 0 {
+		parts = append(parts, fmt.Sprintf(i18n.T("list.uptime"), model.ShortDuration(uptime)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// tableShares is the most of the terminal width each table column takes
+var tableShares = map[model.ListColumn]float64{
+	model.ColumnName:     0.25,
+	model.ColumnHost:     0.35,
+	model.ColumnGroup:    0.15,
+	model.ColumnTags:     0.2,
+	model.ColumnLastSeen: 0.2,
+	model.ColumnStatus:   0.1,
+	model.ColumnSystem:   0.2,
+}
+
+// tableWidths sizes each table column to its header and the connections
+// shown
+func (m *ListModel) tableWidths() []int {
+	layout := Layout{Width: m.width, Height: m.height}
+	widths := make([]int, len(m.tableCols))
+	for i, col := range m.tableCols {
+		values := []string{m.columnTitle(col)}
+		for _, conn := range m.filtered {
+			values = append(values, m.tableCell(conn, col))
+		}
+		widths[i] = layout.Column(values, tableShares[col])
+	}
+	return widths
+}
+
+// columnTitle returns the header of a table column, marked when the table
+// is sorted by it
+func (m *ListModel) columnTitle(col model.ListColumn) string {
+	title := i18n.T("list.column." + string(col))
+	if col == m.sortBy {
+		if m.sortDesc {
+			return title + " ▼"
+		}
+		return title + " ▲"
+	}
+	return title
+}
+
+// tableCell returns the text of a connection's table cell
+func (m *ListModel) tableCell(conn model.Connection, col model.ListColumn) string {
+	switch col {
+	case model.ColumnName:
+		return conn.Name
+	case model.ColumnHost:
+		return connDetails(conn, 0)
+	case model.ColumnGroup:
+		return conn.Group
+	case model.ColumnTags:
+		return strings.Join(conn.Tags, ", ")
+	case model.ColumnLastSeen:
+		if conn.LastConnected == nil {
+			return "-"
+		}
+		return conn.LastConnected.Local().Format("2006-01-02 15:04")
+	case model.ColumnStatus:
+		if m.checking[conn.ID] {
+			return i18n.T("list.status.checking")
+		}
+		switch conn.LastStatus {
+		case model.ConnStatusSuccess:
+			return i18n.T("list.status.ok")
+		case model.ConnStatusFailed:
+			return i18n.T("list.status.fail")
+		}
+		return i18n.T("list.status.unknown")
+	case model.ColumnSystem:
+		if summary := systemSummary(conn, time.Now()); summary != "" {
+			return summary
+		}
+		return "-"
+	}
+	return ""
+}
+
+func (m *ListModel) renderTableHeader(widths []int) string {
+	cells := make([]string, len(m.tableCols))
+	for i, col := range m.tableCols {
+		style := styles.LabelStyle
+		if col == model.ColumnName {
+			// Line up with the padded name cells
+			style = style.Padding(0, 1)
+		}
+		cells[i] = style.Render(Pad(Ellipsis(m.columnTitle(col), widths[i]), widths[i]))
+	}
+	return "  " + strings.Join(cells, "  ")
+}
+
+func (m *ListModel) renderTableRow(conn model.Connection, selected bool, widths []int) string {
+	cursor := "  "
+	nameStyle := styles.NormalStyle
+	if selected {
+		cursor = "> "
+		nameStyle = styles.SelectedStyle
+	}
+
+	cells := make([]string, len(m.tableCols))
+	for i, col := range m.tableCols {
+		text := Pad(Ellipsis(m.tableCell(conn, col), widths[i]), widths[i])
+		style := styles.DimStyle
+		switch col {
+		case model.ColumnName:
+			style = nameStyle
+		case model.ColumnGroup:
+			if conn.Group != "" {
+				style = lipgloss.NewStyle().Foreground(m.groupColor(conn.Group))
+			}
+		case model.ColumnStatus:
+			switch {
+			case m.checking[conn.ID]:
+			case conn.LastStatus == model.ConnStatusSuccess:
+				style = styles.SuccessStyle
+			case conn.LastStatus == model.ConnStatusFailed:
+				style = styles.ErrorStyle
+			}
+		}
+		cells[i] = style.Render(text)
+	}
+	return cursor + strings.Join(cells, "  ")
 }
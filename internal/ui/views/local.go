@@ -0,0 +1,108 @@
+package views
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"gossh/internal/i18n"
+	"gossh/internal/ui/styles"
+)
+
+// LocalKeyMap defines key bindings for the local command prompt
+type LocalKeyMap struct {
+	Run  key.Binding
+	Back key.Binding
+}
+
+// DefaultLocalKeyMap returns default local command prompt key bindings
+var DefaultLocalKeyMap = LocalKeyMap{
+	Run: key.NewBinding(
+		key.WithKeys("enter"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+	),
+}
+
+// LocalModel asks for a command to run on this machine, e.g. to look at a
+// downloaded file without switching terminals
+type LocalModel struct {
+	input  textinput.Model
+	keys   LocalKeyMap
+	width  int
+	height int
+	run    bool
+	done   bool
+}
+
+// NewLocalModel creates the local command prompt
+func NewLocalModel() LocalModel {
+	input := textinput.New()
+	input.Placeholder = i18n.T("local.command.placeholder")
+	input.Width = 40
+	input.Focus()
+
+	return LocalModel{
+		input: input,
+		keys:  DefaultLocalKeyMap,
+	}
+}
+
+// Init initializes the local command prompt
+func (m LocalModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// SetSize sets the view dimensions
+func (m *LocalModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.input.Width = max(Layout{Width: width}.DialogWidth()-lipgloss.Width(i18n.T("local.command"))-4, minColumn)
+}
+
+// Done reports whether the prompt was closed
+func (m LocalModel) Done() bool {
+	return m.done
+}
+
+// Run reports whether the prompt was closed to run the command
+func (m LocalModel) Run() bool {
+	return m.run
+}
+
+// Command returns the command entered, empty for an interactive shell
+func (m LocalModel) Command() string {
+	return strings.TrimSpace(m.input.Value())
+}
+
+// Update handles a key
+func (m LocalModel) Update(msg tea.KeyMsg) (LocalModel, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.done = true
+		return m, nil
+	case key.Matches(msg, m.keys.Run):
+		m.done = true
+		m.run = true
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// View renders the local command prompt
+func (m LocalModel) View() string {
+	var b strings.Builder
+	layout := Layout{Width: m.width, Height: m.height}
+
+	b.WriteString(styles.TitleStyle.Render(i18n.T("local.title")))
+	b.WriteString("\n\n")
+	b.WriteString(styles.LabelStyle.Render(i18n.T("local.command")) + " " + m.input.View() + "\n\n")
+	b.WriteString(styles.HelpStyle.Render(i18n.T("local.help")))
+	return layout.Dialog(b.String())
+}
@@ -0,0 +1,134 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"gossh/internal/i18n"
+	"gossh/internal/plugins"
+	"gossh/internal/ui/styles"
+)
+
+// PluginMenuKeyMap defines key bindings for the plugin menu
+type PluginMenuKeyMap struct {
+	Up   key.Binding
+	Down key.Binding
+	Run  key.Binding
+	Back key.Binding
+}
+
+// DefaultPluginMenuKeyMap returns default plugin menu key bindings
+var DefaultPluginMenuKeyMap = PluginMenuKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+	),
+	Run: key.NewBinding(
+		key.WithKeys("enter"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc", "q"),
+	),
+}
+
+// PluginMenuModel picks a plugin to run on the selected connection
+type PluginMenuModel struct {
+	plugins []plugins.Plugin
+	dir     string // Where plugins are installed, shown when there are none
+	conn    string // Name of the connection they run on
+	cursor  int
+	keys    PluginMenuKeyMap
+	width   int
+	height  int
+	chosen  *plugins.Plugin
+	done    bool
+}
+
+// NewPluginMenuModel creates the menu of the plugins installed in dir, to
+// run on the connection called conn
+func NewPluginMenuModel(list []plugins.Plugin, dir, conn string) PluginMenuModel {
+	return PluginMenuModel{
+		plugins: list,
+		dir:     dir,
+		conn:    conn,
+		keys:    DefaultPluginMenuKeyMap,
+	}
+}
+
+// SetSize sets the view dimensions
+func (m *PluginMenuModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Done reports whether the menu was closed
+func (m PluginMenuModel) Done() bool {
+	return m.done
+}
+
+// Chosen returns the plugin picked to run, if one was
+func (m PluginMenuModel) Chosen() (plugins.Plugin, bool) {
+	if m.chosen == nil {
+		return plugins.Plugin{}, false
+	}
+	return *m.chosen, true
+}
+
+// Update handles a key
+func (m PluginMenuModel) Update(msg tea.KeyMsg) (PluginMenuModel, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.done = true
+	case key.Matches(msg, m.keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.cursor < len(m.plugins)-1 {
+			m.cursor++
+		}
+	case key.Matches(msg, m.keys.Run):
+		if m.cursor < len(m.plugins) {
+			m.chosen = &m.plugins[m.cursor]
+			m.done = true
+		}
+	}
+	return m, nil
+}
+
+// View renders the menu
+func (m PluginMenuModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.TitleStyle.Render(fmt.Sprintf(i18n.T("plugins.title"), m.conn)))
+	b.WriteString("\n\n")
+
+	if len(m.plugins) == 0 {
+		b.WriteString(styles.DimStyle.Render("  "+fmt.Sprintf(i18n.T("plugins.empty"), plugins.Prefix, m.dir)) + "\n")
+	}
+	names := make([]string, len(m.plugins))
+	for i, p := range m.plugins {
+		names[i] = p.Name
+	}
+	width := Layout{Width: m.width}.Column(names, 0.3)
+	for i, p := range m.plugins {
+		cursor := "  "
+		style := lipgloss.NewStyle()
+		if i == m.cursor {
+			cursor = "▸ "
+			style = styles.SelectedStyle
+		}
+		name := style.Render(lipgloss.NewStyle().Width(width).MaxWidth(width).Render(p.Name))
+		b.WriteString(cursor + name + "  " + styles.DimStyle.Render(p.Path) + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(styles.HelpStyle.Render(i18n.T("plugins.help")))
+
+	return Layout{Width: m.width, Height: m.height}.Dialog(b.String())
+}
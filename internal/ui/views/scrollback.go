@@ -0,0 +1,298 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"gossh/internal/i18n"
+	"gossh/internal/ssh"
+	"gossh/internal/ui/styles"
+)
+
+// scrollbackChrome is how many lines the scrollback view uses around the
+// output
+const scrollbackChrome = 4
+
+// ScrollbackKeyMap defines key bindings for the scrollback view
+type ScrollbackKeyMap struct {
+	Up       key.Binding
+	Down     key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+	Top      key.Binding
+	Bottom   key.Binding
+	Search   key.Binding
+	Next     key.Binding
+	Prev     key.Binding
+	Back     key.Binding
+}
+
+// DefaultScrollbackKeyMap returns default scrollback key bindings
+var DefaultScrollbackKeyMap = ScrollbackKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+	),
+	PageUp: key.NewBinding(
+		key.WithKeys("pgup", "ctrl+u"),
+	),
+	PageDown: key.NewBinding(
+		key.WithKeys("pgdown", "ctrl+d", " "),
+	),
+	Top: key.NewBinding(
+		key.WithKeys("g", "home"),
+	),
+	Bottom: key.NewBinding(
+		key.WithKeys("G", "end"),
+	),
+	Search: key.NewBinding(
+		key.WithKeys("/"),
+	),
+	Next: key.NewBinding(
+		key.WithKeys("n"),
+	),
+	Prev: key.NewBinding(
+		key.WithKeys("N"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc", "q"),
+	),
+}
+
+// ScrollbackModel pages through the output a session left behind, like
+// less: it opens at the end, and / searches it upwards, n going on to
+// older matches and N back to newer ones
+type ScrollbackModel struct {
+	title     string
+	lines     []string
+	dropped   bool // Older output did not fit
+	top       int  // First line shown
+	keys      ScrollbackKeyMap
+	input     textinput.Model
+	searching bool   // Typing a search
+	query     string // Last search, lowercased
+	match     int    // Line of the current match, -1 for none
+	message   string
+	width     int
+	height    int
+	done      bool
+}
+
+// NewScrollbackModel creates the scrollback view of a session of the
+// connection called name
+func NewScrollbackModel(name string, scrollback *ssh.Scrollback) ScrollbackModel {
+	m := newPager(fmt.Sprintf(i18n.T("scrollback.title"), name), scrollback.Bytes())
+	m.dropped = scrollback.Dropped()
+	m.top = m.maxTop()
+	return m
+}
+
+// NewPagerModel creates the same view of text, e.g. an attachment, opening
+// at the top
+func NewPagerModel(title string, text []byte) ScrollbackModel {
+	return newPager(title, text)
+}
+
+// newPager creates the view of out under title, terminal escapes left out
+func newPager(title string, out []byte) ScrollbackModel {
+	input := textinput.New()
+	input.Prompt = "/"
+	input.Placeholder = i18n.T("scrollback.search.placeholder")
+
+	lines := ssh.PlainLines(out)
+	// The prompt the session ended at is usually left on a line of its own
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return ScrollbackModel{
+		title: title,
+		lines: lines,
+		keys:  DefaultScrollbackKeyMap,
+		input: input,
+		match: -1,
+	}
+}
+
+// SetSize sets the view dimensions
+func (m *ScrollbackModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.input.Width = max(width-4, minColumn)
+	m.top = min(m.top, m.maxTop())
+}
+
+// Done reports whether the view was closed
+func (m ScrollbackModel) Done() bool {
+	return m.done
+}
+
+// rows returns how many lines of output fit
+func (m ScrollbackModel) rows() int {
+	if m.height <= 0 {
+		return max(len(m.lines), 1)
+	}
+	return max(m.height-scrollbackChrome, 1)
+}
+
+// maxTop returns the first line shown when scrolled to the end
+func (m ScrollbackModel) maxTop() int {
+	return max(len(m.lines)-m.rows(), 0)
+}
+
+// scrollTo scrolls to show line top first, staying within the output
+func (m *ScrollbackModel) scrollTo(top int) {
+	m.top = min(max(top, 0), m.maxTop())
+}
+
+// find moves to the closest line matching the search above the current
+// match, or below it when !older, wrapping around. Without a current match
+// it starts from the bottom of the lines shown, as what is looked for
+// usually scrolled past last.
+func (m *ScrollbackModel) find(older bool) {
+	if m.query == "" || len(m.lines) == 0 {
+		return
+	}
+	start := m.match
+	if start < 0 {
+		start = min(m.top+m.rows(), len(m.lines))
+	}
+	n := len(m.lines)
+	for i := 1; i <= n; i++ {
+		line := start + i
+		if older {
+			line = start - i
+		}
+		line = (line%n + n) % n
+		if strings.Contains(strings.ToLower(m.lines[line]), m.query) {
+			m.match = line
+			m.message = ""
+			// Show the match a few lines down from the top, with what led to it
+			m.scrollTo(line - m.rows()/3)
+			return
+		}
+	}
+	m.match = -1
+	m.message = fmt.Sprintf(i18n.T("scrollback.no_match"), m.query)
+}
+
+// Update handles a key
+func (m ScrollbackModel) Update(msg tea.KeyMsg) (ScrollbackModel, tea.Cmd) {
+	if m.searching {
+		switch msg.Type {
+		case tea.KeyEnter:
+			m.searching = false
+			m.input.Blur()
+			if query := strings.ToLower(strings.TrimSpace(m.input.Value())); query != "" {
+				m.query = query
+				m.match = -1
+				m.find(true)
+			}
+			return m, nil
+		case tea.KeyEsc:
+			m.searching = false
+			m.input.Blur()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+
+	m.message = ""
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.done = true
+	case key.Matches(msg, m.keys.Up):
+		m.scrollTo(m.top - 1)
+	case key.Matches(msg, m.keys.Down):
+		m.scrollTo(m.top + 1)
+	case key.Matches(msg, m.keys.PageUp):
+		m.scrollTo(m.top - m.rows())
+	case key.Matches(msg, m.keys.PageDown):
+		m.scrollTo(m.top + m.rows())
+	case key.Matches(msg, m.keys.Top):
+		m.scrollTo(0)
+	case key.Matches(msg, m.keys.Bottom):
+		m.scrollTo(m.maxTop())
+	case key.Matches(msg, m.keys.Search):
+		m.searching = true
+		m.input.SetValue("")
+		m.input.Focus()
+		return m, textinput.Blink
+	case key.Matches(msg, m.keys.Next):
+		m.find(true)
+	case key.Matches(msg, m.keys.Prev):
+		m.find(false)
+	}
+	return m, nil
+}
+
+// highlight marks where the search matches line
+func (m ScrollbackModel) highlight(line string) string {
+	if m.query == "" {
+		return line
+	}
+	var b strings.Builder
+	lower := strings.ToLower(line)
+	for {
+		i := strings.Index(lower, m.query)
+		// Lowercasing may change byte lengths, so only cut where it didn't
+		if i < 0 || len(lower) != len(line) {
+			b.WriteString(line)
+			return b.String()
+		}
+		end := i + len(m.query)
+		b.WriteString(line[:i])
+		b.WriteString(styles.SelectedStyle.Render(line[i:end]))
+		line, lower = line[end:], lower[end:]
+	}
+}
+
+// View renders the scrollback
+func (m ScrollbackModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.TitleStyle.Render(m.title))
+	if len(m.lines) > 0 {
+		b.WriteString(styles.DimStyle.Render(fmt.Sprintf("  "+i18n.T("scrollback.position"), m.top+1, min(m.top+m.rows(), len(m.lines)), len(m.lines))))
+	}
+	if m.dropped {
+		b.WriteString(styles.DimStyle.Render("  " + i18n.T("scrollback.dropped")))
+	}
+	b.WriteString("\n\n")
+
+	rows := m.rows()
+	end := min(m.top+rows, len(m.lines))
+	for i := m.top; i < end; i++ {
+		line := Ellipsis(m.lines[i], m.width)
+		if i == m.match {
+			line = styles.WarningStyle.Render("▸") + " " + m.highlight(Ellipsis(m.lines[i], max(m.width-2, 1)))
+		} else {
+			line = m.highlight(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	if len(m.lines) == 0 {
+		b.WriteString(styles.DimStyle.Render(i18n.T("scrollback.empty")) + "\n")
+		end = m.top + 1
+	}
+	for i := end - m.top; i < rows && m.height > 0; i++ {
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	switch {
+	case m.searching:
+		b.WriteString(m.input.View())
+	case m.message != "":
+		b.WriteString(styles.WarningStyle.Render(m.message))
+	default:
+		b.WriteString(styles.HelpStyle.Render(i18n.T("scrollback.help")))
+	}
+	return b.String()
+}
@@ -2,7 +2,10 @@ package views
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -10,6 +13,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"gossh/internal/config"
 	"gossh/internal/i18n"
+	"gossh/internal/model"
 	"gossh/internal/ui/styles"
 )
 
@@ -22,8 +26,130 @@ const (
 	SettingsPasswordEnable
 	SettingsPasswordChange
 	SettingsPasswordDisable
+	SettingsImport
+	SettingsExport
+	SettingsTransferResult
+	SettingsTrash
+	SettingsGroups
+	SettingsColumns
+	SettingsRules
+	SettingsRuleAdd
+	SettingsDefaultGroup
+	SettingsRulePreview
+	SettingsMerge
+	SettingsDuplicates
+	SettingsConnection
+	SettingsValue
 )
 
+// SettingsKeyMap defines key bindings for the settings menus
+type SettingsKeyMap struct {
+	Up      key.Binding
+	Down    key.Binding
+	Enter   key.Binding
+	Back    key.Binding
+	Restore key.Binding
+	Purge   key.Binding
+	Prev    key.Binding
+	Next    key.Binding
+	Toggle  key.Binding
+	AddRule key.Binding
+	Delete  key.Binding
+	Default key.Binding
+	Preview key.Binding
+	Apply   key.Binding
+	Keep    key.Binding
+	Take    key.Binding
+	Import  key.Binding
+	Pick    key.Binding
+	Merge   key.Binding
+	Help    key.Binding
+}
+
+// DefaultSettingsKeyMap returns default settings key bindings
+var DefaultSettingsKeyMap = SettingsKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/k", "help.key.up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓/j", "help.key.down"),
+	),
+	Enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "help.key.open"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc", "q"),
+		key.WithHelp("esc", "help.key.back"),
+	),
+	Restore: key.NewBinding(
+		key.WithKeys("r", "enter"),
+		key.WithHelp("r", "help.key.restore"),
+	),
+	Purge: key.NewBinding(
+		key.WithKeys("p", "delete"),
+		key.WithHelp("p", "help.key.purge"),
+	),
+	Prev: key.NewBinding(
+		key.WithKeys("left", "h"),
+		key.WithHelp("←/h", "help.key.color_prev"),
+	),
+	Next: key.NewBinding(
+		key.WithKeys("right", "l"),
+		key.WithHelp("→/l", "help.key.color_next"),
+	),
+	Toggle: key.NewBinding(
+		key.WithKeys(" ", "enter"),
+		key.WithHelp("space", "help.key.toggle_column"),
+	),
+	AddRule: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "help.key.rule_add"),
+	),
+	Delete: key.NewBinding(
+		key.WithKeys("d", "delete"),
+		key.WithHelp("d", "help.key.rule_delete"),
+	),
+	Default: key.NewBinding(
+		key.WithKeys("g"),
+		key.WithHelp("g", "help.key.default_group"),
+	),
+	Preview: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "help.key.rule_preview"),
+	),
+	Apply: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "help.key.rule_apply"),
+	),
+	Keep: key.NewBinding(
+		key.WithKeys("left", "h"),
+		key.WithHelp("←/h", "help.key.merge_local"),
+	),
+	Take: key.NewBinding(
+		key.WithKeys("right", "l"),
+		key.WithHelp("→/l", "help.key.merge_incoming"),
+	),
+	Import: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "help.key.merge_apply"),
+	),
+	Pick: key.NewBinding(
+		key.WithKeys("left", "h", "right", "l"),
+		key.WithHelp("←/→", "help.key.dedupe_pick"),
+	),
+	Merge: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "help.key.dedupe_merge"),
+	),
+	Help: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "help.key.help"),
+	),
+}
+
 // SettingsModel represents the settings view
 type SettingsModel struct {
 	cfg           *config.Manager
@@ -33,6 +159,7 @@ type SettingsModel struct {
 	height        int
 	version       string
 	wantBack      bool // Flag to indicate user wants to go back
+	keys          SettingsKeyMap
 	
 	// For password input
 	passwordInput    textinput.Model
@@ -42,6 +169,44 @@ type SettingsModel struct {
 	
 	// Settings values
 	selectedLang  i18n.Language
+
+	// For import/export
+	pathInput      textinput.Model
+	formatIndex    int
+	pathFocused    bool
+	transferResult config.TransferResult
+	transferAction string // "import" or "export"
+
+	// For resolving import conflicts
+	mergePlan  config.MergePlan
+	mergeTotal int // Connections in the imported file
+	mergeIndex int // Selected diff, counted across all conflicts
+
+	// For merging duplicates
+	dupSets  [][]model.Connection
+	dupIndex int // Set shown
+	dupKeep  int // Connection of the set that absorbs the others
+
+	// For the trash
+	trashIndex   int
+	purgePending string // ID awaiting a second press to purge
+
+	// For group colors
+	groupIndex int
+
+	// For table columns
+	columnIndex int
+
+	// For grouping rules
+	ruleIndex    int
+	ruleInputs   []textinput.Model // Pattern, group and tags of a new rule
+	ruleFocused  int
+	defaultInput textinput.Model
+
+	// For connection and terminal defaults
+	connIndex   int
+	valueInput  textinput.Model
+	valueAction string // Setting being edited in valueInput
 	
 	// Messages
 	message     string
@@ -66,13 +231,37 @@ func NewSettingsModel(cfg *config.Manager) SettingsModel {
 	currentInput.EchoCharacter = '•'
 	currentInput.CharLimit = 64
 
+	pathInput := textinput.New()
+	pathInput.CharLimit = 255
+	pathInput.Width = 40
+
+	ruleInputs := make([]textinput.Model, 3)
+	for i := range ruleInputs {
+		ruleInputs[i] = textinput.New()
+		ruleInputs[i].CharLimit = 255
+		ruleInputs[i].Width = 40
+	}
+
+	defaultInput := textinput.New()
+	defaultInput.CharLimit = 64
+	defaultInput.Width = 30
+
+	valueInput := textinput.New()
+	valueInput.CharLimit = 64
+	valueInput.Width = 30
+
 	return SettingsModel{
+		valueInput:    valueInput,
+		ruleInputs:    ruleInputs,
+		defaultInput:  defaultInput,
+		pathInput:     pathInput,
 		cfg:           cfg,
 		state:         SettingsMain,
 		selectedIndex: 0,
 		selectedLang:  i18n.GetLanguage(),
 		version:       "1.2.0",
 		wantBack:      false,
+		keys:          DefaultSettingsKeyMap,
 		passwordInput: pwInput,
 		confirmInput:  confirmInput,
 		currentInput:  currentInput,
@@ -84,6 +273,40 @@ func (m *SettingsModel) SetVersion(version string) {
 	m.version = version
 }
 
+// Typing reports whether a text field has the focus, so keys such as ?
+// are input rather than commands
+func (m SettingsModel) Typing() bool {
+	switch m.state {
+	case SettingsPasswordEnable, SettingsPasswordChange, SettingsPasswordDisable,
+		SettingsImport, SettingsExport, SettingsRuleAdd, SettingsDefaultGroup, SettingsValue:
+		return true
+	}
+	return false
+}
+
+// HelpSections lists the bindings of the current settings screen
+func (m SettingsModel) HelpSections() []HelpSection {
+	keys := []key.Binding{m.keys.Up, m.keys.Down, m.keys.Enter}
+	switch m.state {
+	case SettingsTrash:
+		keys = []key.Binding{m.keys.Up, m.keys.Down, m.keys.Restore, m.keys.Purge}
+	case SettingsGroups:
+		keys = []key.Binding{m.keys.Up, m.keys.Down, m.keys.Prev, m.keys.Next}
+	case SettingsColumns:
+		keys = []key.Binding{m.keys.Up, m.keys.Down, m.keys.Toggle}
+	case SettingsRules:
+		keys = []key.Binding{m.keys.Up, m.keys.Down, m.keys.AddRule, m.keys.Delete, m.keys.Default, m.keys.Preview}
+	case SettingsRulePreview:
+		keys = []key.Binding{m.keys.Apply}
+	case SettingsMerge:
+		keys = []key.Binding{m.keys.Up, m.keys.Down, m.keys.Keep, m.keys.Take, m.keys.Toggle, m.keys.Import}
+	case SettingsDuplicates:
+		keys = []key.Binding{m.keys.Up, m.keys.Down, m.keys.Pick, m.keys.Merge}
+	}
+	keys = append(keys, m.keys.Back, m.keys.Help)
+	return []HelpSection{{Title: i18n.T("help.settings"), Keys: keys}}
+}
+
 // Init initializes the model
 func (m SettingsModel) Init() tea.Cmd {
 	return nil
@@ -109,6 +332,35 @@ func (m SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updatePasswordInput(msg)
 		case SettingsPasswordDisable:
 			return m.updatePasswordDisable(msg)
+		case SettingsImport, SettingsExport:
+			return m.updateTransfer(msg)
+		case SettingsTransferResult:
+			if key.Matches(msg, key.NewBinding(key.WithKeys("enter", "esc"))) {
+				m.state = SettingsMain
+			}
+			return m, nil
+		case SettingsTrash:
+			return m.updateTrash(msg)
+		case SettingsGroups:
+			return m.updateGroups(msg)
+		case SettingsColumns:
+			return m.updateColumns(msg)
+		case SettingsRules:
+			return m.updateRules(msg)
+		case SettingsRuleAdd:
+			return m.updateRuleAdd(msg)
+		case SettingsDefaultGroup:
+			return m.updateDefaultGroup(msg)
+		case SettingsRulePreview:
+			return m.updateRulePreview(msg)
+		case SettingsMerge:
+			return m.updateMerge(msg)
+		case SettingsDuplicates:
+			return m.updateDuplicates(msg)
+		case SettingsConnection:
+			return m.updateConnection(msg)
+		case SettingsValue:
+			return m.updateValue(msg)
 		}
 	}
 
@@ -119,17 +371,17 @@ func (m SettingsModel) updateMain(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	menuItems := m.getMenuItems()
 	
 	switch {
-	case key.Matches(msg, key.NewBinding(key.WithKeys("up", "k"))):
+	case key.Matches(msg, m.keys.Up):
 		if m.selectedIndex > 0 {
 			m.selectedIndex--
 		}
-	case key.Matches(msg, key.NewBinding(key.WithKeys("down", "j"))):
+	case key.Matches(msg, m.keys.Down):
 		if m.selectedIndex < len(menuItems)-1 {
 			m.selectedIndex++
 		}
-	case key.Matches(msg, key.NewBinding(key.WithKeys("enter"))):
+	case key.Matches(msg, m.keys.Enter):
 		return m.handleMenuSelect()
-	case key.Matches(msg, key.NewBinding(key.WithKeys("esc", "q"))):
+	case key.Matches(msg, m.keys.Back):
 		m.wantBack = true
 		return m, nil
 	}
@@ -138,27 +390,36 @@ func (m SettingsModel) updateMain(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m SettingsModel) updateLanguage(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	languages := i18n.SupportedLanguages()
+	current := 0
+	for i, lang := range languages {
+		if lang == m.selectedLang {
+			current = i
+			break
+		}
+	}
+
 	switch {
-	case key.Matches(msg, key.NewBinding(key.WithKeys("up", "k"))):
-		if m.selectedLang == i18n.LangZH {
-			m.selectedLang = i18n.LangEN
+	case key.Matches(msg, m.keys.Up):
+		if current > 0 {
+			m.selectedLang = languages[current-1]
 		}
-	case key.Matches(msg, key.NewBinding(key.WithKeys("down", "j"))):
-		if m.selectedLang == i18n.LangEN {
-			m.selectedLang = i18n.LangZH
+	case key.Matches(msg, m.keys.Down):
+		if current < len(languages)-1 {
+			m.selectedLang = languages[current+1]
 		}
-	case key.Matches(msg, key.NewBinding(key.WithKeys("enter"))):
+	case key.Matches(msg, m.keys.Enter):
 		// Save language setting
 		i18n.SetLanguage(m.selectedLang)
 		if err := m.cfg.SetLanguage(string(m.selectedLang)); err != nil {
-			m.message = fmt.Sprintf("Error: %v", err)
+			m.message = i18n.T("common.error") + ": " + ErrorText(err)
 			m.messageType = "error"
 		} else {
 			m.message = i18n.T("settings.saved")
 			m.messageType = "success"
 		}
 		m.state = SettingsMain
-	case key.Matches(msg, key.NewBinding(key.WithKeys("esc"))):
+	case key.Matches(msg, m.keys.Back):
 		m.state = SettingsMain
 	}
 	
@@ -231,7 +492,7 @@ func (m SettingsModel) updatePasswordDisable(msg tea.KeyMsg) (tea.Model, tea.Cmd
 		}
 		
 		if err := m.cfg.DisablePassword(currentPassword); err != nil {
-			m.message = fmt.Sprintf("%s: %v", i18n.T("common.error"), err)
+			m.message = i18n.T("common.error") + ": " + ErrorText(err)
 			m.messageType = "error"
 			return m, nil
 		}
@@ -288,6 +549,93 @@ func (m SettingsModel) handleMenuSelect() (tea.Model, tea.Cmd) {
 	switch item.action {
 	case "language":
 		m.state = SettingsLanguage
+	case "connection":
+		m.state = SettingsConnection
+		m.connIndex = 0
+	case "theme":
+		// Cycle dark -> light, recoloring at once
+		settings := m.cfg.GetSettings()
+		next := model.Themes[(slices.Index(model.Themes, settings.Theme)+1)%len(model.Themes)]
+		if err := m.cfg.SetTheme(next); err != nil {
+			m.message = i18n.T("common.error") + ": " + ErrorText(err)
+			m.messageType = "error"
+		} else {
+			styles.SetTheme(next)
+			m.message = i18n.T("settings.saved")
+			m.messageType = "success"
+		}
+	case "import":
+		m.state = SettingsImport
+		m.startTransfer("import")
+	case "groups":
+		m.state = SettingsGroups
+		m.groupIndex = 0
+	case "trash":
+		m.state = SettingsTrash
+		m.trashIndex = 0
+		m.purgePending = ""
+	case "export":
+		m.state = SettingsExport
+		m.startTransfer("export")
+	case "columns":
+		m.state = SettingsColumns
+		m.columnIndex = 0
+	case "rules":
+		m.state = SettingsRules
+		m.ruleIndex = 0
+	case "duplicates":
+		m.state = SettingsDuplicates
+		m.showDuplicates(0)
+	case "layout":
+		settings := m.cfg.GetSettings()
+		next := nextChoice(settings.Layout(), model.ListLayouts)
+		if err := m.cfg.SetListLayout(next); err != nil {
+			m.message = i18n.T("common.error") + ": " + ErrorText(err)
+			m.messageType = "error"
+		} else {
+			m.message = i18n.T("settings.saved")
+			m.messageType = "success"
+		}
+	case "notify":
+		settings := m.cfg.GetSettings()
+		next := nextChoice(settings.NotifyMode(), model.NotifyModes)
+		if err := m.cfg.SetNotify(next); err != nil {
+			m.message = i18n.T("common.error") + ": " + ErrorText(err)
+			m.messageType = "error"
+		} else {
+			m.message = i18n.T("settings.saved")
+			m.messageType = "success"
+		}
+	case "keep_warm":
+		settings := m.cfg.GetSettings()
+		next := nextChoice(settings.KeepWarmMinutes, model.KeepWarmChoices)
+		if err := m.cfg.SetKeepWarm(next); err != nil {
+			m.message = i18n.T("common.error") + ": " + ErrorText(err)
+			m.messageType = "error"
+		} else {
+			m.message = i18n.T("settings.saved")
+			m.messageType = "success"
+		}
+	case "scrollback":
+		settings := m.cfg.GetSettings()
+		next := nextChoice(settings.ScrollbackKB, model.ScrollbackChoices)
+		if err := m.cfg.SetScrollback(next); err != nil {
+			m.message = i18n.T("common.error") + ": " + ErrorText(err)
+			m.messageType = "error"
+		} else {
+			m.message = i18n.T("settings.saved")
+			m.messageType = "success"
+		}
+	case "key_cache":
+		settings := m.cfg.GetSettings()
+		next := nextChoice(settings.KeyCacheMinutes, model.KeyCacheChoices)
+		if err := m.cfg.SetKeyCache(next); err != nil {
+			m.message = i18n.T("common.error") + ": " + ErrorText(err)
+			m.messageType = "error"
+		} else {
+			m.message = i18n.T("settings.saved")
+			m.messageType = "success"
+		}
 	case "enable_password":
 		m.state = SettingsPasswordEnable
 		m.passwordFocused = 0
@@ -323,15 +671,22 @@ func (m SettingsModel) handlePasswordSubmit() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 	
-	if len(password) < 8 {
-		m.message = i18n.T("setup.password.weak")
+	settings := m.cfg.GetSettings()
+	if err := settings.PasswordPolicy.Check(password); err != nil {
+		m.message = ErrorText(err)
 		m.messageType = "error"
 		return m, nil
 	}
 	
-	// Enable password protection
-	if err := m.cfg.EnablePassword(password); err != nil {
-		m.message = fmt.Sprintf("%s: %v", i18n.T("common.error"), err)
+	// Enable password protection, or replace it
+	var err error
+	if m.state == SettingsPasswordChange {
+		err = m.cfg.ChangePassword(m.currentInput.Value(), password)
+	} else {
+		err = m.cfg.EnablePassword(password)
+	}
+	if err != nil {
+		m.message = i18n.T("common.error") + ": " + ErrorText(err)
 		m.messageType = "error"
 		return m, nil
 	}
@@ -344,20 +699,54 @@ func (m SettingsModel) handlePasswordSubmit() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// keepWarmLabel names a keep-warm time for the settings menu
+func keepWarmLabel(minutes int) string {
+	if minutes <= 0 {
+		return i18n.T("settings.keep_warm.off")
+	}
+	return fmt.Sprintf(i18n.T("settings.keep_warm.minutes"), minutes)
+}
+
+// scrollbackLabel describes a scrollback size in KB
+func scrollbackLabel(kb int) string {
+	switch {
+	case kb <= 0:
+		return i18n.T("settings.keep_warm.off")
+	case kb >= 1024:
+		return fmt.Sprintf("%d MB", kb/1024)
+	}
+	return fmt.Sprintf("%d KB", kb)
+}
+
 type menuItem struct {
 	label  string
 	action string
 }
 
 func (m SettingsModel) getMenuItems() []menuItem {
+	settings := m.cfg.GetSettings()
 	items := []menuItem{
 		{label: i18n.T("settings.language"), action: "language"},
+		{label: i18n.T("settings.import"), action: "import"},
+		{label: i18n.T("settings.export"), action: "export"},
+		{label: i18n.T("settings.groups"), action: "groups"},
+		{label: fmt.Sprintf(i18n.T("settings.theme"), i18n.T("settings.theme."+themeName(settings.Theme))), action: "theme"},
+		{label: fmt.Sprintf(i18n.T("settings.layout"), i18n.T("settings.layout."+string(settings.Layout()))), action: "layout"},
+		{label: i18n.T("settings.columns"), action: "columns"},
+		{label: fmt.Sprintf(i18n.T("settings.rules"), len(settings.GroupRules)), action: "rules"},
+		{label: fmt.Sprintf(i18n.T("settings.trash"), len(m.cfg.TrashedConnections())), action: "trash"},
+		{label: fmt.Sprintf(i18n.T("settings.duplicates"), len(m.cfg.Duplicates())), action: "duplicates"},
+		{label: fmt.Sprintf(i18n.T("settings.notify"), i18n.T("settings.notify."+string(settings.NotifyMode()))), action: "notify"},
+		{label: fmt.Sprintf(i18n.T("settings.keep_warm"), keepWarmLabel(settings.KeepWarmMinutes)), action: "keep_warm"},
+		{label: fmt.Sprintf(i18n.T("settings.scrollback"), scrollbackLabel(settings.ScrollbackKB)), action: "scrollback"},
+		{label: i18n.T("settings.connection"), action: "connection"},
 	}
 	
 	// Password related items based on current state
 	if m.cfg.IsPasswordProtected() {
 		items = append(items, menuItem{label: i18n.T("settings.password.change"), action: "change_password"})
 		items = append(items, menuItem{label: i18n.T("settings.password.disable"), action: "disable_password"})
+		items = append(items, menuItem{label: fmt.Sprintf(i18n.T("settings.key_cache"), keepWarmLabel(settings.KeyCacheMinutes)), action: "key_cache"})
 	} else {
 		items = append(items, menuItem{label: i18n.T("settings.password.enable"), action: "enable_password"})
 	}
@@ -386,6 +775,32 @@ func (m SettingsModel) View() string {
 		b.WriteString(m.renderPasswordChange())
 	case SettingsPasswordDisable:
 		b.WriteString(m.renderPasswordDisable())
+	case SettingsImport, SettingsExport:
+		b.WriteString(m.renderTransfer())
+	case SettingsTransferResult:
+		b.WriteString(m.renderTransferResult())
+	case SettingsTrash:
+		b.WriteString(m.renderTrash())
+	case SettingsGroups:
+		b.WriteString(m.renderGroups())
+	case SettingsColumns:
+		b.WriteString(m.renderColumns())
+	case SettingsRules:
+		b.WriteString(m.renderRules())
+	case SettingsRuleAdd:
+		b.WriteString(m.renderRuleAdd())
+	case SettingsDefaultGroup:
+		b.WriteString(m.renderDefaultGroup())
+	case SettingsRulePreview:
+		b.WriteString(m.renderRulePreview())
+	case SettingsMerge:
+		b.WriteString(m.renderMerge())
+	case SettingsDuplicates:
+		b.WriteString(m.renderDuplicates())
+	case SettingsConnection:
+		b.WriteString(m.renderConnection())
+	case SettingsValue:
+		b.WriteString(m.renderValue())
 	}
 	
 	// Message
@@ -408,6 +823,32 @@ func (m SettingsModel) View() string {
 		helpText = i18n.T("settings.help.password")
 	case SettingsPasswordDisable:
 		helpText = i18n.T("settings.help.password.disable")
+	case SettingsImport, SettingsExport:
+		helpText = i18n.T("settings.help.transfer")
+	case SettingsTransferResult:
+		helpText = i18n.T("settings.help.result")
+	case SettingsTrash:
+		helpText = i18n.T("settings.help.trash")
+	case SettingsGroups:
+		helpText = i18n.T("settings.help.groups")
+	case SettingsColumns:
+		helpText = i18n.T("settings.help.columns")
+	case SettingsRules:
+		helpText = i18n.T("settings.help.rules")
+	case SettingsRuleAdd:
+		helpText = i18n.T("settings.help.rules.add")
+	case SettingsDefaultGroup:
+		helpText = i18n.T("settings.help.rules.default")
+	case SettingsRulePreview:
+		helpText = i18n.T("settings.help.rules.preview")
+	case SettingsMerge:
+		helpText = i18n.T("settings.help.merge")
+	case SettingsDuplicates:
+		helpText = i18n.T("settings.help.duplicates")
+	case SettingsConnection:
+		helpText = i18n.T("settings.help")
+	case SettingsValue:
+		helpText = i18n.T("settings.help.value")
 	}
 	b.WriteString("\n\n" + styles.HelpStyle.Render(helpText))
 	
@@ -440,24 +881,16 @@ func (m SettingsModel) renderLanguageSelection() string {
 	
 	b.WriteString(styles.SubtitleStyle.Render(i18n.T("settings.language")) + "\n\n")
 	
-	languages := []struct {
-		code i18n.Language
-		name string
-	}{
-		{i18n.LangEN, "English"},
-		{i18n.LangZH, "中文"},
-	}
-	
-	for _, lang := range languages {
+	for _, lang := range i18n.SupportedLanguages() {
 		cursor := "  "
 		marker := "○"
 		style := lipgloss.NewStyle()
-		if lang.code == m.selectedLang {
+		if lang == m.selectedLang {
 			cursor = "▸ "
 			marker = "●"
 			style = styles.SelectedStyle
 		}
-		b.WriteString(fmt.Sprintf("%s%s %s\n", cursor, marker, style.Render(lang.name)))
+		b.WriteString(fmt.Sprintf("%s%s %s\n", cursor, marker, style.Render(i18n.LanguageName(lang))))
 	}
 	
 	return b.String()
@@ -474,7 +907,8 @@ func (m SettingsModel) renderPasswordEnable() string {
 	b.WriteString(i18n.T("setup.password.prompt") + "\n")
 	b.WriteString(m.passwordInput.View() + "\n\n")
 	b.WriteString(i18n.T("setup.password.confirm") + "\n")
-	b.WriteString(m.confirmInput.View() + "\n")
+	b.WriteString(m.confirmInput.View() + "\n\n")
+	b.WriteString(renderStrength(m.passwordInput.Value(), m.cfg.GetSettings().PasswordPolicy))
 	
 	return b.String()
 }
@@ -493,7 +927,8 @@ func (m SettingsModel) renderPasswordChange() string {
 	b.WriteString(i18n.T("setup.password.prompt") + "\n")
 	b.WriteString(m.passwordInput.View() + "\n\n")
 	b.WriteString(i18n.T("setup.password.confirm") + "\n")
-	b.WriteString(m.confirmInput.View() + "\n")
+	b.WriteString(m.confirmInput.View() + "\n\n")
+	b.WriteString(renderStrength(m.passwordInput.Value(), m.cfg.GetSettings().PasswordPolicy))
 	
 	return b.String()
 }
@@ -511,6 +946,1073 @@ func (m SettingsModel) renderPasswordDisable() string {
 	return b.String()
 }
 
+// defaultTransferPath returns the suggested file for an action and format
+func defaultTransferPath(action string, format config.TransferFormat) string {
+	if format == config.FormatSSHConfig {
+		if action == "import" {
+			return "~/.ssh/config"
+		}
+		return "ssh_config"
+	}
+	return "connections.yaml"
+}
+
+// startTransfer prepares the import/export form
+func (m *SettingsModel) startTransfer(action string) {
+	m.transferAction = action
+	m.formatIndex = 0
+	m.pathFocused = true
+	m.pathInput.SetValue(defaultTransferPath(action, config.TransferFormats[0]))
+	m.pathInput.CursorEnd()
+	m.pathInput.Focus()
+}
+
+func (m SettingsModel) updateTransfer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, key.NewBinding(key.WithKeys("esc"))):
+		m.state = SettingsMain
+		m.pathInput.Blur()
+		return m, nil
+	case key.Matches(msg, key.NewBinding(key.WithKeys("tab", "shift+tab", "up", "down"))):
+		m.pathFocused = !m.pathFocused
+		if m.pathFocused {
+			m.pathInput.Focus()
+		} else {
+			m.pathInput.Blur()
+		}
+		return m, nil
+	case !m.pathFocused && key.Matches(msg, key.NewBinding(key.WithKeys("left", "right", " ", "h", "l"))):
+		// Switch format, keeping a custom path but updating the suggested one
+		oldDefault := defaultTransferPath(m.transferAction, config.TransferFormats[m.formatIndex])
+		if msg.String() == "left" || msg.String() == "h" {
+			m.formatIndex = (m.formatIndex + len(config.TransferFormats) - 1) % len(config.TransferFormats)
+		} else {
+			m.formatIndex = (m.formatIndex + 1) % len(config.TransferFormats)
+		}
+		if m.pathInput.Value() == oldDefault {
+			m.pathInput.SetValue(defaultTransferPath(m.transferAction, config.TransferFormats[m.formatIndex]))
+		}
+		return m, nil
+	case key.Matches(msg, key.NewBinding(key.WithKeys("enter"))):
+		return m.handleTransferSubmit()
+	}
+
+	if !m.pathFocused {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.pathInput, cmd = m.pathInput.Update(msg)
+	return m, cmd
+}
+
+func (m SettingsModel) handleTransferSubmit() (tea.Model, tea.Cmd) {
+	path := strings.TrimSpace(m.pathInput.Value())
+	if path == "" {
+		m.message = i18n.T("form.error.required")
+		m.messageType = "error"
+		return m, nil
+	}
+
+	format := config.TransferFormats[m.formatIndex]
+	if m.transferAction == "import" {
+		connections, err := config.ReadImport(path, format)
+		if err != nil {
+			m.message = i18n.T("common.error") + ": " + ErrorText(err)
+			m.messageType = "error"
+			return m, nil
+		}
+		m.mergePlan = m.cfg.PlanMerge(connections)
+		m.mergeTotal = len(connections)
+		m.mergeIndex = 0
+		m.transferResult = config.TransferResult{Path: config.ExpandHome(path)}
+		m.pathInput.Blur()
+		if len(m.mergePlan.Conflicts) > 0 {
+			m.state = SettingsMerge
+			return m, nil
+		}
+		return m.applyMerge()
+	}
+
+	result, err := m.cfg.Export(path, format, m.version, false)
+	if err != nil {
+		m.message = i18n.T("common.error") + ": " + ErrorText(err)
+		m.messageType = "error"
+		return m, nil
+	}
+
+	m.transferResult = result
+	m.pathInput.Blur()
+	m.state = SettingsTransferResult
+	return m, nil
+}
+
+func (m SettingsModel) renderTransfer() string {
+	var b strings.Builder
+
+	title := i18n.T("settings.export")
+	if m.transferAction == "import" {
+		title = i18n.T("settings.import")
+	}
+	b.WriteString(styles.SubtitleStyle.Render(title) + "\n\n")
+
+	label := i18n.T("settings.transfer.path")
+	if m.pathFocused {
+		label = styles.SelectedStyle.Render(label)
+	}
+	b.WriteString(label + "\n")
+	b.WriteString(m.pathInput.View() + "\n\n")
+
+	label = i18n.T("settings.transfer.format")
+	if !m.pathFocused {
+		label = styles.SelectedStyle.Render(label)
+	}
+	b.WriteString(label + "\n")
+	for i, format := range config.TransferFormats {
+		marker := "○"
+		style := lipgloss.NewStyle()
+		if i == m.formatIndex {
+			marker = "●"
+			style = styles.SelectedStyle
+		}
+		b.WriteString(fmt.Sprintf("  %s %s\n", marker, style.Render(i18n.T("settings.transfer.format."+string(format)))))
+	}
+
+	if m.transferAction == "import" {
+		b.WriteString("\n" + styles.DimStyle.Render(i18n.T("settings.transfer.note.import")) + "\n")
+	}
+
+	return b.String()
+}
+
+// mergeDiff returns the conflict and diff the merge cursor is on
+func (m SettingsModel) mergeDiff(index int) (conflict, diff int) {
+	for i, c := range m.mergePlan.Conflicts {
+		if index < len(c.Diffs) {
+			return i, index
+		}
+		index -= len(c.Diffs)
+	}
+	return -1, -1
+}
+
+// mergeDiffCount counts the diffs across all conflicts
+func (m SettingsModel) mergeDiffCount() int {
+	n := 0
+	for _, c := range m.mergePlan.Conflicts {
+		n += len(c.Diffs)
+	}
+	return n
+}
+
+func (m SettingsModel) updateMerge(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.state = SettingsMain
+		m.mergePlan = config.MergePlan{}
+	case key.Matches(msg, m.keys.Up):
+		if m.mergeIndex > 0 {
+			m.mergeIndex--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.mergeIndex < m.mergeDiffCount()-1 {
+			m.mergeIndex++
+		}
+	case key.Matches(msg, m.keys.Import):
+		return m.applyMerge()
+	case key.Matches(msg, m.keys.Keep), key.Matches(msg, m.keys.Take), key.Matches(msg, m.keys.Toggle):
+		c, d := m.mergeDiff(m.mergeIndex)
+		if c < 0 {
+			break
+		}
+		// The plan is shared with the copy of the model Update was called
+		// on, so the diffs are copied before changing one
+		conflicts := slices.Clone(m.mergePlan.Conflicts)
+		conflicts[c].Diffs = slices.Clone(conflicts[c].Diffs)
+		diff := &conflicts[c].Diffs[d]
+		switch {
+		case key.Matches(msg, m.keys.Keep):
+			diff.TakeIncoming = false
+		case key.Matches(msg, m.keys.Take):
+			diff.TakeIncoming = true
+		default:
+			diff.TakeIncoming = !diff.TakeIncoming
+		}
+		m.mergePlan.Conflicts = conflicts
+	}
+
+	return m, nil
+}
+
+// applyMerge imports the planned connections as resolved so far
+func (m SettingsModel) applyMerge() (tea.Model, tea.Cmd) {
+	added, merged, err := m.cfg.ApplyMerge(m.mergePlan)
+	if err != nil {
+		m.message = i18n.T("common.error") + ": " + ErrorText(err)
+		m.messageType = "error"
+		return m, nil
+	}
+
+	m.transferResult.Total = m.mergeTotal
+	m.transferResult.Imported = added
+	m.transferResult.Merged = merged
+	m.transferResult.Skipped = m.mergeTotal - added - merged
+	m.mergePlan = config.MergePlan{}
+	m.state = SettingsTransferResult
+	return m, nil
+}
+
+// mergeValue shows one side of a diff, hiding secrets
+func mergeValue(value string, secret bool) string {
+	switch {
+	case value == "":
+		return "-"
+	case secret:
+		return "••••••"
+	}
+	return strings.ReplaceAll(value, "\n", " ⏎ ")
+}
+
+func (m SettingsModel) renderMerge() string {
+	var b strings.Builder
+
+	plan := m.mergePlan
+	b.WriteString(styles.SubtitleStyle.Render(fmt.Sprintf(i18n.T("settings.merge.title"), len(plan.Conflicts))) + "\n")
+	b.WriteString(styles.DimStyle.Render(fmt.Sprintf(i18n.T("settings.merge.summary"), len(plan.New), plan.Unchanged)) + "\n\n")
+
+	// One line per connection and per diff; only the lines around the
+	// cursor are shown when they do not fit
+	var lines []string
+	cursorLine := 0
+	index := 0
+	width := max(12, (m.width-24)/2)
+	for _, c := range plan.Conflicts {
+		header := c.Local.Name
+		if c.Incoming.Name != c.Local.Name {
+			header += " ← " + c.Incoming.Name
+		}
+		lines = append(lines, styles.LabelStyle.Render(header))
+		for _, d := range c.Diffs {
+			cursor := "  "
+			field := d.Field
+			if index == m.mergeIndex {
+				cursor = "▸ "
+				field = styles.SelectedStyle.Render(field)
+				cursorLine = len(lines)
+			}
+			field = lipgloss.NewStyle().Width(24).Render(field)
+			local, incoming := "○ ", "○ "
+			localStyle, incomingStyle := styles.DimStyle, styles.DimStyle
+			if d.TakeIncoming {
+				incoming, incomingStyle = "● ", lipgloss.NewStyle()
+			} else {
+				local, localStyle = "● ", lipgloss.NewStyle()
+			}
+			cell := lipgloss.NewStyle().Width(width).MaxWidth(width)
+			lines = append(lines, "  "+cursor+field+
+				localStyle.Render(cell.Render(local+mergeValue(d.Local, d.Secret)))+" "+
+				incomingStyle.Render(cell.Render(incoming+mergeValue(d.Incoming, d.Secret))))
+			index++
+		}
+	}
+
+	limit := max(5, m.height-14)
+	start := 0
+	if len(lines) > limit {
+		start = min(max(0, cursorLine-limit/2), len(lines)-limit)
+		lines = lines[start : start+limit]
+	}
+	for _, line := range lines {
+		b.WriteString(line + "\n")
+	}
+
+	return b.String()
+}
+
+func (m SettingsModel) renderTransferResult() string {
+	var b strings.Builder
+	r := m.transferResult
+
+	if m.transferAction == "import" {
+		b.WriteString(styles.SubtitleStyle.Render(i18n.T("settings.import.result")) + "\n\n")
+		b.WriteString(fmt.Sprintf("  %s: %s\n", i18n.T("settings.transfer.file"), r.Path))
+		b.WriteString(fmt.Sprintf("  %s: %s\n", i18n.T("settings.transfer.imported"), styles.SuccessStyle.Render(fmt.Sprint(r.Imported))))
+		b.WriteString(fmt.Sprintf("  %s: %d\n", i18n.T("settings.transfer.merged"), r.Merged))
+		b.WriteString(fmt.Sprintf("  %s: %d\n", i18n.T("settings.transfer.skipped"), r.Skipped))
+	} else {
+		b.WriteString(styles.SubtitleStyle.Render(i18n.T("settings.export.result")) + "\n\n")
+		b.WriteString(fmt.Sprintf("  %s: %s\n", i18n.T("settings.transfer.file"), r.Path))
+		b.WriteString(fmt.Sprintf("  %s: %s\n", i18n.T("settings.transfer.exported"), styles.SuccessStyle.Render(fmt.Sprint(r.Exported))))
+	}
+
+	return b.String()
+}
+
+func (m SettingsModel) updateTrash(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	trash := m.cfg.TrashedConnections()
+	pending := m.purgePending
+	m.purgePending = ""
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.state = SettingsMain
+	case key.Matches(msg, m.keys.Up):
+		if m.trashIndex > 0 {
+			m.trashIndex--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.trashIndex < len(trash)-1 {
+			m.trashIndex++
+		}
+	case key.Matches(msg, m.keys.Restore):
+		if m.trashIndex >= len(trash) {
+			break
+		}
+		conn := trash[m.trashIndex]
+		if err := m.cfg.RestoreConnection(conn.ID); err != nil {
+			m.message = err.Error()
+			m.messageType = "error"
+			break
+		}
+		m.message = fmt.Sprintf(i18n.T("settings.trash.restored"), conn.Name)
+		m.messageType = "success"
+	case key.Matches(msg, m.keys.Purge):
+		if m.trashIndex >= len(trash) {
+			break
+		}
+		conn := trash[m.trashIndex]
+		// Purging is permanent, so it takes a second press
+		if pending != conn.ID {
+			m.purgePending = conn.ID
+			m.message = fmt.Sprintf(i18n.T("settings.trash.confirm_purge"), conn.Name)
+			m.messageType = "error"
+			break
+		}
+		if err := m.cfg.PurgeConnection(conn.ID); err != nil {
+			m.message = err.Error()
+			m.messageType = "error"
+			break
+		}
+		m.message = fmt.Sprintf(i18n.T("settings.trash.purged"), conn.Name)
+		m.messageType = "success"
+	}
+
+	if n := len(m.cfg.TrashedConnections()); m.trashIndex >= n && n > 0 {
+		m.trashIndex = n - 1
+	}
+	return m, nil
+}
+
+func (m SettingsModel) renderTrash() string {
+	var b strings.Builder
+
+	b.WriteString(styles.SubtitleStyle.Render(i18n.T("settings.trash.title")) + "\n\n")
+
+	trash := m.cfg.TrashedConnections()
+	if len(trash) == 0 {
+		b.WriteString(styles.DimStyle.Render("  "+i18n.T("settings.trash.empty")) + "\n")
+		return b.String()
+	}
+
+	for i, conn := range trash {
+		cursor := "  "
+		style := lipgloss.NewStyle()
+		if i == m.trashIndex {
+			cursor = "▸ "
+			style = styles.SelectedStyle
+		}
+
+		daysLeft := int(time.Until(m.cfg.TrashExpiry(conn)).Hours()/24) + 1
+		if daysLeft < 0 {
+			daysLeft = 0
+		}
+		deleted := ""
+		if conn.DeletedAt != nil {
+			deleted = conn.DeletedAt.Format("2006-01-02")
+		}
+
+		line := fmt.Sprintf("%-20s %-30s", conn.Name, conn.User+"@"+conn.Host)
+		info := styles.DimStyle.Render(fmt.Sprintf(i18n.T("settings.trash.info"), deleted, daysLeft))
+		b.WriteString(cursor + style.Render(line) + " " + info + "\n")
+	}
+
+	return b.String()
+}
+
+func (m SettingsModel) updateGroups(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	groups := m.cfg.Groups()
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.state = SettingsMain
+	case key.Matches(msg, m.keys.Up):
+		if m.groupIndex > 0 {
+			m.groupIndex--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.groupIndex < len(groups)-1 {
+			m.groupIndex++
+		}
+	case key.Matches(msg, m.keys.Prev), key.Matches(msg, m.keys.Next):
+		if m.groupIndex >= len(groups) {
+			break
+		}
+		// Step through the palette from the color shown now
+		g := groups[m.groupIndex]
+		current := styles.GroupColor(g.Color, m.groupIndex)
+		step := 1
+		if key.Matches(msg, m.keys.Prev) {
+			step = len(styles.GroupPalette) - 1
+		}
+		next := styles.GroupPalette[0]
+		for i, color := range styles.GroupPalette {
+			if strings.EqualFold(string(color), string(current)) {
+				next = styles.GroupPalette[(i+step)%len(styles.GroupPalette)]
+			}
+		}
+		if err := m.cfg.SetGroupColor(g.Name, string(next)); err != nil {
+			m.message = i18n.T("common.error") + ": " + ErrorText(err)
+			m.messageType = "error"
+		}
+	}
+
+	return m, nil
+}
+
+func (m SettingsModel) renderGroups() string {
+	var b strings.Builder
+
+	b.WriteString(styles.SubtitleStyle.Render(i18n.T("settings.groups.title")) + "\n\n")
+
+	groups := m.cfg.Groups()
+	if len(groups) == 0 {
+		b.WriteString(styles.DimStyle.Render("  "+i18n.T("settings.groups.empty")) + "\n")
+		return b.String()
+	}
+
+	for i, g := range groups {
+		cursor := "  "
+		style := lipgloss.NewStyle()
+		if i == m.groupIndex {
+			cursor = "▸ "
+			style = styles.SelectedStyle
+		}
+
+		color := styles.GroupColor(g.Color, i)
+		swatch := lipgloss.NewStyle().Foreground(color).Render("██")
+		b.WriteString(fmt.Sprintf("%s%s %s %s\n", cursor, swatch, style.Render(fmt.Sprintf("%-20s", g.Name)), styles.DimStyle.Render(string(color))))
+	}
+
+	return b.String()
+}
+
+func (m SettingsModel) updateColumns(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.state = SettingsMain
+	case key.Matches(msg, m.keys.Up):
+		if m.columnIndex > 0 {
+			m.columnIndex--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.columnIndex < len(model.ListColumns)-1 {
+			m.columnIndex++
+		}
+	case key.Matches(msg, m.keys.Toggle):
+		col := model.ListColumns[m.columnIndex]
+		if col == model.ColumnName {
+			break // Rows need something to tell them apart
+		}
+		settings := m.cfg.GetSettings()
+		var columns []model.ListColumn
+		for _, c := range settings.TableColumns() {
+			if c != col {
+				columns = append(columns, c)
+			}
+		}
+		if !slices.Contains(settings.TableColumns(), col) {
+			columns = append(columns, col)
+		}
+		if err := m.cfg.SetListColumns(columns); err != nil {
+			m.message = i18n.T("common.error") + ": " + ErrorText(err)
+			m.messageType = "error"
+		}
+	}
+
+	return m, nil
+}
+
+func (m SettingsModel) renderColumns() string {
+	var b strings.Builder
+
+	b.WriteString(styles.SubtitleStyle.Render(i18n.T("settings.columns")) + "\n\n")
+
+	settings := m.cfg.GetSettings()
+	shown := settings.TableColumns()
+	for i, col := range model.ListColumns {
+		cursor := "  "
+		style := lipgloss.NewStyle()
+		if i == m.columnIndex {
+			cursor = "▸ "
+			style = styles.SelectedStyle
+		}
+		check := "[ ]"
+		if slices.Contains(shown, col) {
+			check = "[x]"
+		}
+		b.WriteString(fmt.Sprintf("%s%s %s\n", cursor, check, style.Render(i18n.T("list.column."+string(col)))))
+	}
+
+	if settings.Layout() != model.LayoutTable {
+		b.WriteString("\n" + styles.DimStyle.Render(i18n.T("settings.columns.note")) + "\n")
+	}
+
+	return b.String()
+}
+
+func (m SettingsModel) updateRules(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	rules := m.cfg.GetSettings().GroupRules
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.state = SettingsMain
+	case key.Matches(msg, m.keys.Up):
+		if m.ruleIndex > 0 {
+			m.ruleIndex--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.ruleIndex < len(rules)-1 {
+			m.ruleIndex++
+		}
+	case key.Matches(msg, m.keys.AddRule):
+		m.state = SettingsRuleAdd
+		for i := range m.ruleInputs {
+			m.ruleInputs[i].SetValue("")
+			m.ruleInputs[i].Blur()
+		}
+		m.ruleFocused = 0
+		return m, m.ruleInputs[0].Focus()
+	case key.Matches(msg, m.keys.Delete):
+		if m.ruleIndex >= len(rules) {
+			break
+		}
+		rules = slices.Delete(slices.Clone(rules), m.ruleIndex, m.ruleIndex+1)
+		if err := m.cfg.SetGroupRules(rules); err != nil {
+			m.message = i18n.T("common.error") + ": " + ErrorText(err)
+			m.messageType = "error"
+			break
+		}
+		if m.ruleIndex >= len(rules) && m.ruleIndex > 0 {
+			m.ruleIndex--
+		}
+	case key.Matches(msg, m.keys.Default):
+		m.state = SettingsDefaultGroup
+		m.defaultInput.SetValue(m.cfg.GetSettings().DefaultGroup)
+		m.defaultInput.CursorEnd()
+		return m, m.defaultInput.Focus()
+	case key.Matches(msg, m.keys.Preview):
+		m.state = SettingsRulePreview
+	}
+
+	return m, nil
+}
+
+func (m SettingsModel) updateRuleAdd(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.state = SettingsRules
+		return m, nil
+	case "tab", "down", "shift+tab", "up":
+		step := 1
+		if msg.String() == "shift+tab" || msg.String() == "up" {
+			step = len(m.ruleInputs) - 1
+		}
+		m.ruleInputs[m.ruleFocused].Blur()
+		m.ruleFocused = (m.ruleFocused + step) % len(m.ruleInputs)
+		return m, m.ruleInputs[m.ruleFocused].Focus()
+	case "enter":
+		rule := model.GroupRule{
+			Pattern: strings.TrimSpace(m.ruleInputs[0].Value()),
+			Group:   strings.TrimSpace(m.ruleInputs[1].Value()),
+		}
+		for _, tag := range strings.Split(m.ruleInputs[2].Value(), ",") {
+			if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(rule.Tags, tag) {
+				rule.Tags = append(rule.Tags, tag)
+			}
+		}
+		rules := append(slices.Clone(m.cfg.GetSettings().GroupRules), rule)
+		if err := m.cfg.SetGroupRules(rules); err != nil {
+			m.message = i18n.T("common.error") + ": " + ErrorText(err)
+			m.messageType = "error"
+			return m, nil
+		}
+		m.message = i18n.T("settings.saved")
+		m.messageType = "success"
+		m.ruleIndex = len(rules) - 1
+		m.state = SettingsRules
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.ruleInputs[m.ruleFocused], cmd = m.ruleInputs[m.ruleFocused].Update(msg)
+	return m, cmd
+}
+
+func (m SettingsModel) updateDefaultGroup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.state = SettingsRules
+		return m, nil
+	case "enter":
+		if err := m.cfg.SetDefaultGroup(strings.TrimSpace(m.defaultInput.Value())); err != nil {
+			m.message = i18n.T("common.error") + ": " + ErrorText(err)
+			m.messageType = "error"
+			return m, nil
+		}
+		m.message = i18n.T("settings.saved")
+		m.messageType = "success"
+		m.state = SettingsRules
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.defaultInput, cmd = m.defaultInput.Update(msg)
+	return m, cmd
+}
+
+func (m SettingsModel) updateRulePreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.state = SettingsRules
+	case key.Matches(msg, m.keys.Apply):
+		n, err := m.cfg.ApplyGroupRules()
+		if err != nil {
+			m.message = i18n.T("common.error") + ": " + ErrorText(err)
+			m.messageType = "error"
+			return m, nil
+		}
+		m.message = fmt.Sprintf(i18n.T("settings.rules.applied"), n)
+		m.messageType = "success"
+		m.state = SettingsRules
+	}
+
+	return m, nil
+}
+
+// ruleLabel describes what a grouping rule does, as pattern → group #tags
+func ruleLabel(r model.GroupRule) string {
+	var parts []string
+	if r.Group != "" {
+		parts = append(parts, r.Group)
+	}
+	for _, tag := range r.Tags {
+		parts = append(parts, "#"+tag)
+	}
+	return r.Pattern + " → " + strings.Join(parts, " ")
+}
+
+func (m SettingsModel) renderRules() string {
+	var b strings.Builder
+
+	b.WriteString(styles.SubtitleStyle.Render(i18n.T("settings.rules.title")) + "\n\n")
+
+	settings := m.cfg.GetSettings()
+	group := settings.DefaultGroup
+	if group == "" {
+		group = i18n.T("settings.rules.default.none")
+	}
+	b.WriteString(fmt.Sprintf(i18n.T("settings.rules.default"), group) + "\n\n")
+
+	if len(settings.GroupRules) == 0 {
+		b.WriteString(styles.DimStyle.Render("  "+i18n.T("settings.rules.empty")) + "\n")
+		return b.String()
+	}
+
+	for i, r := range settings.GroupRules {
+		cursor := "  "
+		style := lipgloss.NewStyle()
+		if i == m.ruleIndex {
+			cursor = "▸ "
+			style = styles.SelectedStyle
+		}
+		b.WriteString(fmt.Sprintf("%s%d. %s\n", cursor, i+1, style.Render(ruleLabel(r))))
+	}
+
+	return b.String()
+}
+
+func (m SettingsModel) renderRuleAdd() string {
+	var b strings.Builder
+
+	b.WriteString(styles.SubtitleStyle.Render(i18n.T("settings.rules.title")) + "\n\n")
+
+	m.ruleInputs[0].Placeholder = i18n.T("settings.rules.pattern.placeholder")
+	labels := []string{"settings.rules.pattern", "settings.rules.group", "settings.rules.tags"}
+	for i, label := range labels {
+		b.WriteString(i18n.T(label) + "\n")
+		b.WriteString(m.ruleInputs[i].View() + "\n\n")
+	}
+
+	return b.String()
+}
+
+func (m SettingsModel) renderDefaultGroup() string {
+	var b strings.Builder
+
+	b.WriteString(styles.SubtitleStyle.Render(i18n.T("settings.rules.title")) + "\n\n")
+	b.WriteString(i18n.T("settings.rules.default.prompt") + "\n")
+	b.WriteString(m.defaultInput.View() + "\n")
+
+	return b.String()
+}
+
+func (m SettingsModel) renderRulePreview() string {
+	var b strings.Builder
+
+	b.WriteString(styles.SubtitleStyle.Render(i18n.T("settings.rules.preview.title")) + "\n\n")
+
+	changes := m.cfg.PreviewGroupRules()
+	if len(changes) == 0 {
+		b.WriteString(styles.DimStyle.Render("  "+i18n.T("settings.rules.preview.empty")) + "\n")
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf(i18n.T("settings.rules.preview.count"), len(changes)) + "\n\n")
+	names := make([]string, len(changes))
+	for i, c := range changes {
+		names[i] = c.Connection.Name
+	}
+	width := Layout{Width: m.width}.Column(names, 0.3)
+	shown := changes
+	if limit := max(5, m.height-12); len(shown) > limit {
+		shown = shown[:limit]
+	}
+	for _, c := range shown {
+		var parts []string
+		if c.Group != "" {
+			from := c.Connection.Group
+			if from == "" {
+				from = "-"
+			}
+			parts = append(parts, from+" → "+c.Group)
+		}
+		for _, tag := range c.Tags {
+			parts = append(parts, "+#"+tag)
+		}
+		name := lipgloss.NewStyle().Width(width).MaxWidth(width).Render(c.Connection.Name)
+		b.WriteString("  " + name + "  " + styles.DimStyle.Render(strings.Join(parts, "  ")) + "\n")
+	}
+	if more := len(changes) - len(shown); more > 0 {
+		b.WriteString(styles.DimStyle.Render("  "+fmt.Sprintf(i18n.T("settings.rules.preview.more"), more)) + "\n")
+	}
+
+	return b.String()
+}
+
+// showDuplicates finds the duplicate sets again and shows the one at
+// index, or the last one
+func (m *SettingsModel) showDuplicates(index int) {
+	m.dupSets = m.cfg.Duplicates()
+	m.dupIndex = max(0, min(index, len(m.dupSets)-1))
+	m.dupKeep = 0
+	if m.dupIndex < len(m.dupSets) {
+		m.dupKeep = model.PickSurvivor(m.dupSets[m.dupIndex])
+	}
+}
+
+func (m SettingsModel) updateDuplicates(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.state = SettingsMain
+		m.dupSets = nil
+	case key.Matches(msg, m.keys.Up):
+		if m.dupIndex > 0 {
+			m.dupIndex--
+			m.dupKeep = model.PickSurvivor(m.dupSets[m.dupIndex])
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.dupIndex < len(m.dupSets)-1 {
+			m.dupIndex++
+			m.dupKeep = model.PickSurvivor(m.dupSets[m.dupIndex])
+		}
+	case key.Matches(msg, m.keys.Pick):
+		if m.dupIndex >= len(m.dupSets) {
+			break
+		}
+		n := len(m.dupSets[m.dupIndex])
+		if msg.String() == "left" || msg.String() == "h" {
+			m.dupKeep = (m.dupKeep + n - 1) % n
+		} else {
+			m.dupKeep = (m.dupKeep + 1) % n
+		}
+	case key.Matches(msg, m.keys.Merge):
+		if m.dupIndex >= len(m.dupSets) {
+			break
+		}
+		set := m.dupSets[m.dupIndex]
+		var ids []string
+		for i, c := range set {
+			if i != m.dupKeep {
+				ids = append(ids, c.ID)
+			}
+		}
+		merged, err := m.cfg.Dedupe(set[m.dupKeep].ID, ids)
+		if err != nil {
+			m.message = i18n.T("common.error") + ": " + ErrorText(err)
+			m.messageType = "error"
+			break
+		}
+		m.message = fmt.Sprintf(i18n.T("settings.duplicates.done"), len(ids), merged.Name)
+		m.messageType = "success"
+		m.showDuplicates(m.dupIndex)
+	}
+
+	return m, nil
+}
+
+func (m SettingsModel) renderDuplicates() string {
+	var b strings.Builder
+
+	if len(m.dupSets) == 0 {
+		b.WriteString(styles.SubtitleStyle.Render(i18n.T("settings.duplicates.title")) + "\n\n")
+		b.WriteString(styles.DimStyle.Render("  "+i18n.T("settings.duplicates.empty")) + "\n")
+		return b.String()
+	}
+
+	set := m.dupSets[m.dupIndex]
+	b.WriteString(styles.SubtitleStyle.Render(fmt.Sprintf("%s %d/%d", i18n.T("settings.duplicates.title"), m.dupIndex+1, len(m.dupSets))) + "\n")
+	b.WriteString(styles.DimStyle.Render(set[0].DuplicateKey()) + "\n\n")
+
+	// One column per connection, side by side
+	width := max(16, min(32, (m.width-4)/len(set)-2))
+	columns := make([]string, len(set))
+	for i, c := range set {
+		last := i18n.T("cli.dedupe.never")
+		if c.LastConnected != nil {
+			last = c.LastConnected.Format("2006-01-02")
+		}
+		group := c.Group
+		if group == "" {
+			group = "-"
+		}
+		rows := []string{
+			c.Name,
+			i18n.T("list.column.group") + ": " + group,
+			i18n.T("list.column.tags") + ": " + strings.Join(c.Tags, ", "),
+			i18n.T("list.column.last_seen") + ": " + last,
+			fmt.Sprintf(i18n.T("cli.dedupe.sessions"), len(c.History)),
+			strings.SplitN(c.Notes, "\n", 2)[0],
+		}
+		style := lipgloss.NewStyle().Width(width).Padding(0, 1).Border(lipgloss.RoundedBorder())
+		if i == m.dupKeep {
+			style = style.BorderForeground(styles.PrimaryColor)
+			rows[0] = styles.SelectedStyle.Render("● " + rows[0]) + " " + styles.SuccessStyle.Render(i18n.T("settings.duplicates.keep"))
+		} else {
+			rows[0] = "○ " + rows[0]
+			style = style.Foreground(styles.MutedColor)
+		}
+		columns[i] = style.Render(strings.Join(rows, "\n"))
+	}
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, columns...) + "\n")
+
+	return b.String()
+}
+
+// themeName returns the theme the settings menu shows, dark when unset
+func themeName(theme string) string {
+	if !slices.Contains(model.Themes, theme) {
+		return model.Themes[0]
+	}
+	return theme
+}
+
+// nextChoice returns the choice after current, wrapping around, so a
+// menu entry cycles through its values. A value that isn't one of them
+// starts over at the first.
+func nextChoice[T comparable](current T, choices []T) T {
+	i := slices.Index(choices, current)
+	return choices[(i+1)%len(choices)]
+}
+
+// healthCheckLabel names a health check interval for the settings menu
+func healthCheckLabel(minutes int) string {
+	if minutes <= 0 {
+		return i18n.T("settings.health.off")
+	}
+	return fmt.Sprintf(i18n.T("settings.health.minutes"), minutes)
+}
+
+// orNone returns value, or what the settings menu shows for an empty one
+func orNone(value, none string) string {
+	if value == "" {
+		return i18n.T(none)
+	}
+	return value
+}
+
+func (m SettingsModel) getConnectionItems() []menuItem {
+	settings := m.cfg.GetSettings()
+	keepalive := i18n.T("settings.keepalive.default")
+	if settings.KeepaliveSeconds > 0 {
+		keepalive = fmt.Sprintf(i18n.T("settings.seconds"), settings.KeepaliveSeconds)
+	}
+	confirm := i18n.T("settings.off")
+	if settings.ConfirmDelete() {
+		confirm = i18n.T("settings.on")
+	}
+	return []menuItem{
+		{label: fmt.Sprintf(i18n.T("settings.timeout"), fmt.Sprintf(i18n.T("settings.seconds"), settings.ConnectionTimeout)), action: "timeout"},
+		{label: fmt.Sprintf(i18n.T("settings.default_port"), settings.NewConnectionPort(model.ConnTypeSSH)), action: "port"},
+		{label: fmt.Sprintf(i18n.T("settings.default_user"), orNone(settings.DefaultUser, "settings.default_user.none")), action: "user"},
+		{label: fmt.Sprintf(i18n.T("settings.keepalive"), keepalive), action: "keepalive"},
+		{label: fmt.Sprintf(i18n.T("settings.health"), healthCheckLabel(settings.HealthCheckMinutes)), action: "health"},
+		{label: fmt.Sprintf(i18n.T("settings.confirm_delete"), confirm), action: "confirm_delete"},
+		{label: fmt.Sprintf(i18n.T("settings.term"), orNone(settings.Term, "settings.term.local")), action: "term"},
+		{label: fmt.Sprintf(i18n.T("settings.locale"), orNone(settings.Locale, "settings.locale.none")), action: "locale"},
+		{label: i18n.T("common.back"), action: "back"},
+	}
+}
+
+func (m SettingsModel) updateConnection(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	items := m.getConnectionItems()
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.connIndex > 0 {
+			m.connIndex--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.connIndex < len(items)-1 {
+			m.connIndex++
+		}
+	case key.Matches(msg, m.keys.Back):
+		m.state = SettingsMain
+	case key.Matches(msg, m.keys.Enter):
+		return m.handleConnectionSelect(items[m.connIndex].action)
+	}
+	return m, nil
+}
+
+func (m SettingsModel) handleConnectionSelect(action string) (tea.Model, tea.Cmd) {
+	settings := m.cfg.GetSettings()
+	var err error
+	switch action {
+	case "back":
+		m.state = SettingsMain
+		return m, nil
+	case "health":
+		next := nextChoice(settings.HealthCheckMinutes, model.HealthCheckChoices)
+		err = m.cfg.SetHealthCheck(next)
+	case "confirm_delete":
+		err = m.cfg.SetConfirmDelete(!settings.ConfirmDelete())
+	default:
+		// The others are typed in
+		value := map[string]string{
+			"timeout":   strconv.Itoa(settings.ConnectionTimeout),
+			"port":      strconv.Itoa(settings.NewConnectionPort(model.ConnTypeSSH)),
+			"user":      settings.DefaultUser,
+			"keepalive": strconv.Itoa(settings.KeepaliveSeconds),
+			"term":      settings.Term,
+			"locale":    settings.Locale,
+		}[action]
+		m.state = SettingsValue
+		m.valueAction = action
+		m.valueInput.SetValue(value)
+		m.valueInput.CursorEnd()
+		return m, m.valueInput.Focus()
+	}
+	if err != nil {
+		m.message = i18n.T("common.error") + ": " + ErrorText(err)
+		m.messageType = "error"
+	} else {
+		m.message = i18n.T("settings.saved")
+		m.messageType = "success"
+	}
+	return m, nil
+}
+
+func (m SettingsModel) updateValue(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.state = SettingsConnection
+		return m, nil
+	case "enter":
+		if err := m.saveValue(strings.TrimSpace(m.valueInput.Value())); err != nil {
+			m.message = i18n.T("common.error") + ": " + ErrorText(err)
+			m.messageType = "error"
+			return m, nil
+		}
+		m.message = i18n.T("settings.saved")
+		m.messageType = "success"
+		m.state = SettingsConnection
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.valueInput, cmd = m.valueInput.Update(msg)
+	return m, cmd
+}
+
+// saveValue saves the setting being edited from the text typed in
+func (m SettingsModel) saveValue(value string) error {
+	settings := m.cfg.GetSettings()
+	switch m.valueAction {
+	case "timeout":
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			return model.ErrInvalidTimeout
+		}
+		return m.cfg.SetConnectionTimeout(seconds)
+	case "port":
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return model.ErrInvalidPort
+		}
+		return m.cfg.SetDefaultPort(port)
+	case "user":
+		return m.cfg.SetDefaultUser(value)
+	case "keepalive":
+		if value == "" {
+			value = "0"
+		}
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			return model.ErrInvalidInterval
+		}
+		return m.cfg.SetKeepalive(seconds)
+	case "term":
+		return m.cfg.SetTerminal(value, settings.Locale)
+	case "locale":
+		return m.cfg.SetTerminal(settings.Term, value)
+	}
+	return nil
+}
+
+func (m SettingsModel) renderConnection() string {
+	var b strings.Builder
+
+	b.WriteString(styles.SubtitleStyle.Render(i18n.T("settings.connection.title")) + "\n\n")
+	for i, item := range m.getConnectionItems() {
+		cursor := "  "
+		style := lipgloss.NewStyle()
+		if i == m.connIndex {
+			cursor = "▸ "
+			style = styles.SelectedStyle
+		}
+		b.WriteString(cursor + style.Render(item.label) + "\n")
+	}
+
+	return b.String()
+}
+
+func (m SettingsModel) renderValue() string {
+	var b strings.Builder
+
+	b.WriteString(styles.SubtitleStyle.Render(i18n.T("settings.connection.title")) + "\n\n")
+	b.WriteString(i18n.T("settings."+m.valueAction+".prompt") + "\n")
+	b.WriteString(m.valueInput.View() + "\n")
+
+	return b.String()
+}
+
 // ShouldQuit returns true if the user wants to go back
 func (m SettingsModel) ShouldQuit() bool {
 	return m.wantBack
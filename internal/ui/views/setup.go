@@ -28,18 +28,19 @@ type SetupModel struct {
 	err             error
 	width           int
 	height          int
+	policy          crypto.PasswordPolicy // What the password must meet
 }
 
 // NewSetupModel creates a new setup model
 func NewSetupModel() SetupModel {
 	password := textinput.New()
-	password.Placeholder = "Enter master password"
+	password.Placeholder = i18n.T("setup.placeholder.password")
 	password.EchoMode = textinput.EchoPassword
 	password.CharLimit = 100
 	password.Width = 40
 
 	confirm := textinput.New()
-	confirm.Placeholder = "Confirm master password"
+	confirm.Placeholder = i18n.T("setup.placeholder.confirm")
 	confirm.EchoMode = textinput.EchoPassword
 	confirm.CharLimit = 100
 	confirm.Width = 40
@@ -64,8 +65,8 @@ func (m *SetupModel) GetPassword() (string, error) {
 	pwd := m.password.Value()
 	confirm := m.confirmPassword.Value()
 
-	if len(pwd) < 8 {
-		return "", crypto.ErrPasswordTooWeak
+	if err := m.policy.Check(pwd); err != nil {
+		return "", err
 	}
 
 	if pwd != confirm {
@@ -75,6 +76,11 @@ func (m *SetupModel) GetPassword() (string, error) {
 	return pwd, nil
 }
 
+// SetPolicy sets the password policy the password must meet
+func (m *SetupModel) SetPolicy(policy crypto.PasswordPolicy) {
+	m.policy = policy
+}
+
 // SkipPassword returns true if user chose to skip password protection
 func (m *SetupModel) SkipPassword() bool {
 	return m.selectedOption == 1
@@ -230,17 +236,12 @@ func (m SetupModel) View() string {
 		b.WriteString("\n\n")
 
 		// Password strength indicator
-		if m.password.Value() != "" {
-			score, desc := crypto.PasswordStrength(m.password.Value())
-			bar := renderStrengthBar(score)
-			b.WriteString(i18n.T("setup.password.strength") + ": " + bar + " " + desc)
-			b.WriteString("\n")
-		}
+		b.WriteString(renderStrength(m.password.Value(), m.policy))
 
 		// Error message
 		if m.err != nil {
 			b.WriteString("\n")
-			b.WriteString(styles.ErrorStyle.Render(i18n.T("common.error") + ": " + m.err.Error()))
+			b.WriteString(styles.ErrorStyle.Render(i18n.T("common.error") + ": " + ErrorText(m.err)))
 			b.WriteString("\n")
 		}
 
@@ -253,6 +254,20 @@ func (m SetupModel) View() string {
 	return b.String()
 }
 
+// renderStrength shows how strong password is and, when it doesn't meet
+// policy, why, for the password fields of setup and settings
+func renderStrength(password string, policy crypto.PasswordPolicy) string {
+	if password == "" {
+		return ""
+	}
+	score, desc := crypto.PasswordStrength(password)
+	line := i18n.T("setup.password.strength") + ": " + renderStrengthBar(score) + " " + desc + "\n"
+	if err := policy.Check(password); err != nil {
+		line += styles.WarningStyle.Render(ErrorText(err)) + "\n"
+	}
+	return line
+}
+
 func renderStrengthBar(score int) string {
 	filled := score + 1
 	empty := 4 - score
@@ -0,0 +1,115 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"gossh/internal/i18n"
+	"gossh/internal/ui/styles"
+)
+
+// toastDuration is how long a toast stays in the status bar
+const toastDuration = 5 * time.Second
+
+// StatusInfo is the context the status bar always shows
+type StatusInfo struct {
+	Profile   string // Config file in use
+	Protected bool   // A master password encrypts the config
+	Sessions  int    // Sessions suspended in the background
+	Jobs      int    // Operations running in the background
+	Filter    string // Search filter applied to the list
+}
+
+// StatusBar is the line at the bottom of the connection list. It shows
+// the context from StatusInfo on the left and the latest toast, a short
+// message that times out, on the right.
+type StatusBar struct {
+	width     int
+	toast     string
+	toastID   int
+	scheduled int // Last toast whose timeout is running
+}
+
+// toastTimeoutMsg clears a toast once its time is up
+type toastTimeoutMsg struct {
+	id int
+}
+
+// NewStatusBar creates an empty status bar
+func NewStatusBar() StatusBar {
+	return StatusBar{}
+}
+
+// SetSize sets the width of the bar
+func (s *StatusBar) SetSize(width int) {
+	s.width = width
+}
+
+// Toast shows text until it times out or another toast replaces it. The
+// timeout starts with the command returned by Schedule.
+func (s *StatusBar) Toast(text string) {
+	s.toast = text
+	s.toastID++
+}
+
+// Schedule returns a command timing out the latest toast, or nil when
+// there is none or it is already running
+func (s *StatusBar) Schedule() tea.Cmd {
+	if s.toast == "" || s.scheduled == s.toastID {
+		return nil
+	}
+	s.scheduled = s.toastID
+	id := s.toastID
+	return tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return toastTimeoutMsg{id: id}
+	})
+}
+
+// Update clears a toast that has timed out, unless a newer one replaced it
+func (s StatusBar) Update(msg tea.Msg) StatusBar {
+	if msg, ok := msg.(toastTimeoutMsg); ok && msg.id == s.toastID {
+		s.toast = ""
+	}
+	return s
+}
+
+// View renders the bar across the full width
+func (s StatusBar) View(info StatusInfo) string {
+	parts := []string{info.Profile}
+	if info.Protected {
+		parts = append(parts, i18n.T("status.protected"))
+	} else {
+		parts = append(parts, i18n.T("status.unprotected"))
+	}
+	if info.Sessions > 0 {
+		parts = append(parts, fmt.Sprintf(i18n.T("status.sessions"), info.Sessions))
+	}
+	if info.Jobs > 0 {
+		parts = append(parts, fmt.Sprintf(i18n.T("status.jobs"), info.Jobs))
+	}
+	if info.Filter != "" {
+		parts = append(parts, fmt.Sprintf(i18n.T("list.filter"), info.Filter))
+	}
+	left := strings.Join(parts, " │ ")
+
+	style := styles.StatusBarStyle
+	if s.width <= 0 {
+		return style.Render(strings.TrimSpace(left + "  " + s.toast))
+	}
+
+	// The toast goes on the right, or replaces the context when there is
+	// no room for both
+	inner := s.width - style.GetHorizontalFrameSize()
+	gap := inner - lipgloss.Width(left) - lipgloss.Width(s.toast)
+	line := left
+	switch {
+	case s.toast != "" && gap >= 2:
+		line = left + strings.Repeat(" ", gap) + s.toast
+	case s.toast != "":
+		line = s.toast
+	}
+	return style.Width(s.width).MaxWidth(s.width).Render(line)
+}
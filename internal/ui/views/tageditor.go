@@ -0,0 +1,122 @@
+package views
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"gossh/internal/ui/styles"
+)
+
+// TagEditor edits a list of tags shown as chips. Space or comma adds the
+// typed tag, backspace on an empty input removes the last one, and tags
+// already in use elsewhere are offered as completions.
+type TagEditor struct {
+	tags  []string
+	known []string
+	input textinput.Model
+}
+
+// NewTagEditor creates an empty tag editor
+func NewTagEditor() TagEditor {
+	input := textinput.New()
+	input.Prompt = ""
+	input.Placeholder = "web, nginx, prod"
+	input.CharLimit = 50
+	input.Width = 20
+	input.ShowSuggestions = true
+	// Tab moves between form fields, so completions are taken with right
+	input.KeyMap.AcceptSuggestion = key.NewBinding(key.WithKeys("right", "ctrl+e"))
+	input.KeyMap.NextSuggestion = key.NewBinding(key.WithKeys("ctrl+n"))
+	input.KeyMap.PrevSuggestion = key.NewBinding(key.WithKeys("ctrl+p"))
+	return TagEditor{input: input}
+}
+
+// SetKnown sets the tags offered as completions
+func (e *TagEditor) SetKnown(tags []string) {
+	e.known = tags
+	e.refreshSuggestions()
+}
+
+// SetTags replaces the tags
+func (e *TagEditor) SetTags(tags []string) {
+	e.tags = nil
+	for _, tag := range tags {
+		e.add(tag)
+	}
+	e.input.SetValue("")
+	e.refreshSuggestions()
+}
+
+// Tags returns the tags, including one still being typed
+func (e TagEditor) Tags() []string {
+	tags := slices.Clone(e.tags)
+	if tag := strings.TrimSpace(e.input.Value()); tag != "" && !slices.Contains(tags, tag) {
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// Focus focuses the input
+func (e *TagEditor) Focus() tea.Cmd {
+	return e.input.Focus()
+}
+
+// Blur blurs the input
+func (e *TagEditor) Blur() {
+	e.input.Blur()
+}
+
+// add appends a tag unless it is empty or already there
+func (e *TagEditor) add(tag string) {
+	tag = strings.TrimSpace(tag)
+	if tag != "" && !slices.Contains(e.tags, tag) {
+		e.tags = append(e.tags, tag)
+	}
+}
+
+// refreshSuggestions offers the known tags not added yet
+func (e *TagEditor) refreshSuggestions() {
+	var suggestions []string
+	for _, tag := range e.known {
+		if !slices.Contains(e.tags, tag) {
+			suggestions = append(suggestions, tag)
+		}
+	}
+	e.input.SetSuggestions(suggestions)
+}
+
+// Update handles messages for the tag editor
+func (e TagEditor) Update(msg tea.Msg) (TagEditor, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case " ", ",":
+			e.add(e.input.Value())
+			e.input.SetValue("")
+			e.refreshSuggestions()
+			return e, nil
+		case "backspace":
+			if e.input.Value() == "" && len(e.tags) > 0 {
+				e.tags = e.tags[:len(e.tags)-1]
+				e.refreshSuggestions()
+				return e, nil
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	e.input, cmd = e.input.Update(msg)
+	return e, cmd
+}
+
+// View renders the tags as chips followed by the input
+func (e TagEditor) View() string {
+	var b strings.Builder
+	for _, tag := range e.tags {
+		b.WriteString(styles.TagStyle.Render(tag))
+	}
+	b.WriteString(e.input.View())
+	return b.String()
+}
@@ -3,6 +3,7 @@ package views
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -10,12 +11,11 @@ import (
 	"gossh/internal/ui/styles"
 )
 
-const maxUnlockAttempts = 3
-
 // UnlockModel is the unlock view for entering master password
 type UnlockModel struct {
 	password textinput.Model
-	attempts int
+	failures int       // Wrong passwords entered since the last unlock
+	until    time.Time // When the next attempt may be made
 	err      error
 	width    int
 	height   int
@@ -24,7 +24,7 @@ type UnlockModel struct {
 // NewUnlockModel creates a new unlock model
 func NewUnlockModel() UnlockModel {
 	password := textinput.New()
-	password.Placeholder = "Enter master password"
+	password.Placeholder = i18n.T("unlock.placeholder")
 	password.EchoMode = textinput.EchoPassword
 	password.CharLimit = 100
 	password.Width = 40
@@ -32,7 +32,6 @@ func NewUnlockModel() UnlockModel {
 
 	return UnlockModel{
 		password: password,
-		attempts: 0,
 	}
 }
 
@@ -47,15 +46,16 @@ func (m *UnlockModel) GetPassword() string {
 	return m.password.Value()
 }
 
-// IncrementAttempts increments and returns the number of failed attempts
-func (m *UnlockModel) IncrementAttempts() int {
-	m.attempts++
-	return m.attempts
+// SetAttempts sets how many wrong passwords were entered and how long the
+// next attempt must wait
+func (m *UnlockModel) SetAttempts(failures int, wait time.Duration) {
+	m.failures = failures
+	m.until = time.Now().Add(wait)
 }
 
-// MaxAttemptsReached returns true if max attempts have been reached
-func (m *UnlockModel) MaxAttemptsReached() bool {
-	return m.attempts >= maxUnlockAttempts
+// Waiting reports whether the next attempt must still wait
+func (m UnlockModel) Waiting() bool {
+	return time.Now().Before(m.until)
 }
 
 // SetError sets an error message
@@ -94,22 +94,19 @@ func (m UnlockModel) View() string {
 	b.WriteString(m.password.View())
 	b.WriteString("\n\n")
 
-	// Attempt counter
-	remaining := maxUnlockAttempts - m.attempts
-	if m.attempts > 0 {
-		attemptStr := fmt.Sprintf(i18n.T("unlock.attempt"), m.attempts, maxUnlockAttempts)
-		if remaining <= 1 {
-			b.WriteString(styles.ErrorStyle.Render(attemptStr))
-		} else {
-			b.WriteString(styles.WarningStyle.Render(attemptStr))
-		}
+	// Failed attempts, and the wait before the next one
+	if m.failures > 0 {
+		b.WriteString(styles.WarningStyle.Render(fmt.Sprintf(i18n.T("unlock.attempt"), m.failures)))
 		b.WriteString("\n")
 	}
-
-	// Error message
-	if m.err != nil {
+	if m.Waiting() {
+		wait := time.Until(m.until).Round(time.Second)
+		b.WriteString(styles.ErrorStyle.Render(fmt.Sprintf(i18n.T("unlock.failed"), wait)))
+		b.WriteString("\n")
+	} else if m.err != nil {
+		// Error message
 		b.WriteString("\n")
-		b.WriteString(styles.ErrorStyle.Render(i18n.T("common.error") + ": " + m.err.Error()))
+		b.WriteString(styles.ErrorStyle.Render(i18n.T("common.error") + ": " + ErrorText(m.err)))
 		b.WriteString("\n")
 	}
 
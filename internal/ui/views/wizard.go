@@ -0,0 +1,166 @@
+package views
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gossh/internal/i18n"
+	"gossh/internal/model"
+	"gossh/internal/ui/styles"
+)
+
+// wizardItem is a discovered host in the import checklist
+type wizardItem struct {
+	conn       model.Connection
+	selected   bool
+	groupIndex int
+}
+
+// WizardModel is the first-run import wizard that offers hosts
+// discovered in ~/.ssh/config and on the tailnet as a checklist
+type WizardModel struct {
+	items  []wizardItem
+	groups []string
+	source string
+	cursor int
+	width  int
+	height int
+}
+
+// NewWizardModel creates a wizard for the discovered connections.
+// All hosts start selected, in the group their source put them in
+// (e.g. Tailnet) or ungrouped.
+func NewWizardModel(source string, discovered []model.Connection, groups []string) WizardModel {
+	choices := append([]string{i18n.T("list.ungrouped")}, groups...)
+	items := make([]wizardItem, len(discovered))
+	for i, conn := range discovered {
+		items[i] = wizardItem{conn: conn, selected: true}
+		if conn.Group == "" {
+			continue
+		}
+		index := slices.Index(choices[1:], conn.Group)
+		if index < 0 {
+			choices = append(choices, conn.Group)
+			index = len(choices) - 2
+		}
+		items[i].groupIndex = index + 1
+	}
+
+	return WizardModel{
+		items:  items,
+		groups: choices,
+		source: source,
+	}
+}
+
+// SetSize sets the view dimensions
+func (m *WizardModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Selected returns the checked connections with their assigned group
+func (m WizardModel) Selected() []model.Connection {
+	var conns []model.Connection
+	for _, item := range m.items {
+		if !item.selected {
+			continue
+		}
+		conn := item.conn
+		conn.Group = ""
+		if item.groupIndex > 0 {
+			conn.Group = m.groups[item.groupIndex]
+		}
+		conns = append(conns, conn)
+	}
+	return conns
+}
+
+// Total returns the number of discovered hosts
+func (m WizardModel) Total() int {
+	return len(m.items)
+}
+
+// Update handles messages for the wizard model
+func (m WizardModel) Update(msg tea.Msg) (WizardModel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || len(m.items) == 0 {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.items)-1 {
+			m.cursor++
+		}
+	case " ":
+		m.items[m.cursor].selected = !m.items[m.cursor].selected
+	case "a":
+		// Select all, or clear all when everything is already selected
+		all := true
+		for _, item := range m.items {
+			if !item.selected {
+				all = false
+				break
+			}
+		}
+		for i := range m.items {
+			m.items[i].selected = !all
+		}
+	case "g":
+		item := &m.items[m.cursor]
+		item.groupIndex = (item.groupIndex + 1) % len(m.groups)
+	case "G":
+		// Apply the current host's group to every host
+		groupIndex := m.items[m.cursor].groupIndex
+		for i := range m.items {
+			m.items[i].groupIndex = groupIndex
+		}
+	}
+	return m, nil
+}
+
+// View renders the wizard
+func (m WizardModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.TitleStyle.Render(i18n.T("wizard.title")))
+	b.WriteString("\n\n")
+	b.WriteString(fmt.Sprintf(i18n.T("wizard.desc"), len(m.items), m.source))
+	b.WriteString("\n\n")
+
+	selected := 0
+	for i, item := range m.items {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "▸ "
+		}
+		check := "[ ]"
+		if item.selected {
+			check = "[x]"
+			selected++
+		}
+
+		line := fmt.Sprintf("%s %-20s %s@%s:%d", check, item.conn.Name, item.conn.User, item.conn.Host, item.conn.Port)
+		group := styles.DimStyle.Render("[" + m.groups[item.groupIndex] + "]")
+		if i == m.cursor {
+			line = styles.SelectedStyle.Render(line)
+		} else if !item.selected {
+			line = styles.DimStyle.Render(line)
+		}
+		b.WriteString(cursor + line + " " + group + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(styles.DimStyle.Render(fmt.Sprintf(i18n.T("wizard.selected"), selected, len(m.items))))
+	b.WriteString("\n\n")
+	b.WriteString(styles.HelpStyle.Render(i18n.T("wizard.help")))
+
+	return b.String()
+}
@@ -0,0 +1,163 @@
+package gossh
+
+import (
+	"bytes"
+	"context"
+
+	"gossh/internal/sftp"
+	"gossh/internal/ssh"
+)
+
+// Client is an SSH connection to a host, for running commands on it.
+// Connections to the same host are shared within the process.
+type Client struct {
+	client *ssh.Client
+}
+
+// RunResult is what a command run by Client.Run did
+type RunResult struct {
+	Stdout   []byte
+	Stderr   []byte
+	ExitCode int
+}
+
+// Dial connects to conn, through its jump hosts if it has any
+func Dial(conn Connection, opts Options) (*Client, error) {
+	callback, err := opts.hostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+	client := ssh.NewClient(conn)
+	client.SetHostKeyCallback(callback)
+	if err := client.Connect(); err != nil {
+		return nil, err
+	}
+	return &Client{client: client}, nil
+}
+
+// Run runs command on the host and waits for it to exit. A command that
+// exits with a non-zero status is no error: see RunResult.ExitCode. When
+// ctx is done first the command's session is closed and ctx.Err() returned.
+func (c *Client) Run(ctx context.Context, command string) (RunResult, error) {
+	session, err := c.client.NewSession()
+	if err != nil {
+		return RunResult{}, err
+	}
+	defer session.Close()
+
+	var stdout, stderr bytes.Buffer
+	session.SetStdout(&stdout)
+	session.SetStderr(&stderr)
+	if err := session.Start(command); err != nil {
+		return RunResult{}, err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Wait()
+	}()
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		session.Close()
+		return RunResult{}, ctx.Err()
+	}
+
+	code, exited := ssh.ExitStatus(err)
+	if !exited {
+		return RunResult{}, err
+	}
+	return RunResult{Stdout: stdout.Bytes(), Stderr: stderr.Bytes(), ExitCode: code}, nil
+}
+
+// Close releases the connection
+func (c *Client) Close() error {
+	return c.client.Close()
+}
+
+// Exec runs command on conns in parallel, like gossh exec, and returns a
+// result for each host in the order of conns once all have finished. Hosts
+// that fail to connect or run the command have the error in their result.
+func Exec(ctx context.Context, conns []Connection, command string, opts Options) ([]ExecResult, error) {
+	callback, err := opts.hostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+	executor := ssh.NewBatchExecutor(conns)
+	executor.SetHostKeyCallback(callback)
+	if opts.Timeout > 0 {
+		executor.SetTimeout(opts.Timeout)
+	}
+	return executor.Execute(ctx, command), nil
+}
+
+// SFTPClient is an SFTP connection to a host, for copying files. Relative
+// remote paths are relative to the login directory, relative local ones to
+// the connection's local directory or the working directory.
+type SFTPClient struct {
+	client *sftp.Client
+}
+
+// DialSFTP opens an SFTP connection to conn
+func DialSFTP(conn Connection, opts Options) (*SFTPClient, error) {
+	callback, err := opts.hostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+	client := sftp.NewClient(conn)
+	client.SetHostKeyCallback(callback)
+	if err := client.Connect(); err != nil {
+		return nil, err
+	}
+	return &SFTPClient{client: client}, nil
+}
+
+// Upload copies a local file to the host
+func (c *SFTPClient) Upload(localPath, remotePath string) error {
+	return c.client.Upload(localPath, remotePath)
+}
+
+// Download copies a file of the host to a local one
+func (c *SFTPClient) Download(remotePath, localPath string) error {
+	return c.client.Download(remotePath, localPath)
+}
+
+// UploadDir copies a local directory to the host, with what it contains
+func (c *SFTPClient) UploadDir(localPath, remotePath string) error {
+	return c.client.UploadDir(localPath, remotePath)
+}
+
+// DownloadDir copies a directory of the host, with what it contains
+func (c *SFTPClient) DownloadDir(remotePath, localPath string) error {
+	return c.client.DownloadDir(remotePath, localPath)
+}
+
+// List lists a remote directory
+func (c *SFTPClient) List(remotePath string) ([]FileInfo, error) {
+	return c.client.List(remotePath)
+}
+
+// Stat describes a remote file
+func (c *SFTPClient) Stat(remotePath string) (*FileInfo, error) {
+	return c.client.Stat(remotePath)
+}
+
+// Mkdir creates a remote directory and any parents it is missing
+func (c *SFTPClient) Mkdir(remotePath string) error {
+	return c.client.Mkdir(remotePath)
+}
+
+// Remove removes a remote file or empty directory
+func (c *SFTPClient) Remove(remotePath string) error {
+	return c.client.Remove(remotePath)
+}
+
+// RemoveAll removes a remote path and anything it contains
+func (c *SFTPClient) RemoveAll(remotePath string) error {
+	return c.client.RemoveAll(remotePath)
+}
+
+// Close closes the SFTP connection
+func (c *SFTPClient) Close() error {
+	return c.client.Close()
+}
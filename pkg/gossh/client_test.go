@@ -0,0 +1,139 @@
+package gossh
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	cryptossh "golang.org/x/crypto/ssh"
+)
+
+// startServer runs an SSH server whose only commands are `exit <code>` and
+// `echo <text>`
+func startServer(t *testing.T) Connection {
+	t.Helper()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	signer, err := cryptossh.NewSignerFromKey(privateKey)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+	config := &cryptossh.ServerConfig{
+		PasswordCallback: func(cryptossh.ConnMetadata, []byte) (*cryptossh.Permissions, error) {
+			return nil, nil
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn, config)
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	conn := NewConnection()
+	conn.Name = "test"
+	conn.Host = addr.IP.String()
+	conn.Port = addr.Port
+	conn.User = "test"
+	conn.Password = "secret"
+	return conn
+}
+
+func serve(conn net.Conn, config *cryptossh.ServerConfig) {
+	defer conn.Close()
+	_, chans, reqs, err := cryptossh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go cryptossh.DiscardRequests(reqs)
+
+	for newChan := range chans {
+		ch, requests, err := newChan.Accept()
+		if err != nil {
+			continue
+		}
+		go func() {
+			defer ch.Close()
+			for req := range requests {
+				if req.Type != "exec" {
+					_ = req.Reply(false, nil)
+					continue
+				}
+				command := string(req.Payload[4:])
+				_ = req.Reply(true, nil)
+
+				if text, ok := strings.CutPrefix(command, "echo "); ok {
+					_, _ = io.WriteString(ch, text+"\n")
+				}
+				code, _ := strconv.Atoi(strings.TrimPrefix(command, "exit "))
+				status := make([]byte, 4)
+				binary.BigEndian.PutUint32(status, uint32(code))
+				_, _ = ch.SendRequest("exit-status", false, status)
+				return
+			}
+		}()
+	}
+}
+
+func TestClientRun(t *testing.T) {
+	opts := Options{HostKeyCallback: cryptossh.InsecureIgnoreHostKey()}
+	client, err := Dial(startServer(t), opts)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer client.Close()
+
+	result, err := client.Run(context.Background(), "echo hello")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if string(result.Stdout) != "hello\n" || result.ExitCode != 0 {
+		t.Errorf("Run() = %q, exit %d, want %q, exit 0", result.Stdout, result.ExitCode, "hello\n")
+	}
+
+	result, err = client.Run(context.Background(), "exit 3")
+	if err != nil {
+		t.Fatalf("Run of a failing command returned an error: %v", err)
+	}
+	if result.ExitCode != 3 {
+		t.Errorf("exit code = %d, want 3", result.ExitCode)
+	}
+}
+
+func TestExec(t *testing.T) {
+	conns := []Connection{startServer(t), startServer(t)}
+	opts := Options{HostKeyCallback: cryptossh.InsecureIgnoreHostKey()}
+	results, err := Exec(context.Background(), conns, "echo hi", opts)
+	if err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Exec() gave %d results, want 2", len(results))
+	}
+	for _, r := range results {
+		if r.Error != nil || strings.TrimSpace(r.Output) != "hi" {
+			t.Errorf("%s: output %q, error %v", r.Connection.Name, r.Output, r.Error)
+		}
+	}
+}
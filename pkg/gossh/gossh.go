@@ -0,0 +1,86 @@
+// Package gossh is the Go API of gossh, for programs that use its
+// connections without running the gossh binary: read and edit the
+// connection store, run commands over SSH on one host or many, and copy
+// files over SFTP.
+//
+// The package is the stable surface of gossh. What it exports keeps working
+// across releases; the internal packages behind it may change at any time.
+//
+//	store, err := gossh.Open("")
+//	if err != nil {
+//		return err
+//	}
+//	if store.Locked() {
+//		if err := store.Unlock(password); err != nil {
+//			return err
+//		}
+//	}
+//	conn, ok := store.Find("web")
+//	if !ok {
+//		return fmt.Errorf("no connection called web")
+//	}
+//	client, err := gossh.Dial(conn, gossh.Options{})
+//	if err != nil {
+//		return err
+//	}
+//	defer client.Close()
+//	result, err := client.Run(ctx, "uptime")
+package gossh
+
+import (
+	"time"
+
+	cryptossh "golang.org/x/crypto/ssh"
+	"gossh/internal/model"
+	"gossh/internal/sftp"
+	"gossh/internal/ssh"
+)
+
+// Connection is a saved server: how to reach it, log in, and what it is
+// filed under. Connections found in a Store carry their secrets decrypted
+// and their jump host chain resolved.
+type Connection = model.Connection
+
+// NewConnection returns a connection with a new ID and the defaults, to
+// fill in and Add to a Store
+func NewConnection() Connection {
+	return model.NewConnection()
+}
+
+// AuthType is how a connection logs in
+type AuthType = model.AuthType
+
+const (
+	AuthPassword = model.AuthPassword // Password, or keyboard-interactive
+	AuthKey      = model.AuthKey      // Private key file, optionally with a passphrase
+)
+
+// ExecResult is what a command run by Exec did on one host
+type ExecResult = ssh.BatchResult
+
+// FileInfo describes a remote file listed over SFTP
+type FileInfo = sftp.FileInfo
+
+// Options tune how the package connects. The zero value is the default.
+type Options struct {
+	// HostKeyCallback checks the key a host presents. When nil, hosts in
+	// gossh's known_hosts must present the key on record, while new hosts
+	// are accepted without being recorded.
+	HostKeyCallback cryptossh.HostKeyCallback
+
+	// Timeout bounds each host of Exec, from connecting to the end of the
+	// command. Zero means 30 seconds.
+	Timeout time.Duration
+}
+
+// hostKeyCallback returns the callback to connect with
+func (o Options) hostKeyCallback() (cryptossh.HostKeyCallback, error) {
+	if o.HostKeyCallback != nil {
+		return o.HostKeyCallback, nil
+	}
+	hkm, err := ssh.NewHostKeyManager()
+	if err != nil {
+		return nil, err
+	}
+	return ssh.VerifyHostKeyCallback(hkm), nil
+}
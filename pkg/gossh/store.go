@@ -0,0 +1,171 @@
+package gossh
+
+import (
+	"errors"
+	"fmt"
+
+	"gossh/internal/config"
+	"gossh/internal/keycache"
+)
+
+// ErrNotInitialized is returned for a store gossh has not been set up with
+// yet, see Store.Setup
+var ErrNotInitialized = errors.New("gossh is not set up yet")
+
+// ErrLocked is returned for a store that needs its master password, see
+// Store.Unlock
+var ErrLocked = errors.New("gossh is locked")
+
+// ErrNotFound is returned for a connection name the store does not have
+var ErrNotFound = errors.New("connection not found")
+
+// Store is the gossh connection store: the config file with the saved
+// connections, their secrets encrypted. It is safe for concurrent use.
+type Store struct {
+	cfg *config.Manager
+}
+
+// Open opens the store at path, or the one gossh uses when path is empty:
+// $GOSSH_CONFIG_DIR/config.yaml, else config.yaml in the user's config
+// directory. A store without a master password is unlocked straight away,
+// as is one whose key gossh remembers, see gossh remember.
+func Open(path string) (*Store, error) {
+	if path == "" {
+		var err error
+		if path, err = config.ConfigPath(); err != nil {
+			return nil, err
+		}
+	}
+	cfg, err := config.NewManagerAt(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	s := &Store{cfg: cfg}
+	if cfg.IsFirstRun() {
+		return s, nil
+	}
+	if err := cfg.AutoUnlockIfNeeded(); err != nil {
+		return nil, err
+	}
+	if !cfg.IsUnlocked() {
+		s.unlockRemembered()
+	}
+	return s, nil
+}
+
+// unlockRemembered unlocks with the key a recent gossh command cached or
+// the OS keyring holds, if any
+func (s *Store) unlockRemembered() {
+	settings := s.cfg.GetSettings()
+	if settings.KeyCache() > 0 {
+		if key, err := keycache.Get(s.cfg.Path(), settings.EncryptionSalt); err == nil && s.cfg.UnlockWithKey(key) == nil {
+			return
+		}
+	}
+	if settings.RememberKey {
+		_ = s.cfg.UnlockFromKeyring()
+	}
+}
+
+// Path returns the path of the config file
+func (s *Store) Path() string {
+	return s.cfg.Path()
+}
+
+// Initialized reports whether the store has been set up
+func (s *Store) Initialized() bool {
+	return !s.cfg.IsFirstRun()
+}
+
+// Setup sets up a new store, protecting it with password, or encrypting
+// secrets with a key of this machine when password is empty
+func (s *Store) Setup(password string) error {
+	if password == "" {
+		return s.cfg.SetupWithoutPassword()
+	}
+	return s.cfg.SetupMasterPassword(password)
+}
+
+// Locked reports whether the master password is needed to use the
+// connections
+func (s *Store) Locked() bool {
+	return s.Initialized() && !s.cfg.IsUnlocked()
+}
+
+// Unlock decrypts the store with the master password. Failed attempts
+// count towards the wait gossh imposes before the next one.
+func (s *Store) Unlock(password string) error {
+	return s.cfg.Unlock(password)
+}
+
+// Lock forgets the master key and the decrypted secrets
+func (s *Store) Lock() error {
+	return s.cfg.Lock()
+}
+
+// usable returns why the connections cannot be used yet, if they can't
+func (s *Store) usable() error {
+	switch {
+	case !s.Initialized():
+		return ErrNotInitialized
+	case !s.cfg.IsUnlocked():
+		return ErrLocked
+	}
+	return nil
+}
+
+// Connections returns the saved connections
+func (s *Store) Connections() ([]Connection, error) {
+	if err := s.usable(); err != nil {
+		return nil, err
+	}
+	return s.cfg.Connections(), nil
+}
+
+// Find returns the connection called name, or having it as an alias
+func (s *Store) Find(name string) (Connection, bool) {
+	conns, err := s.Connections()
+	if err != nil {
+		return Connection{}, false
+	}
+	for _, conn := range conns {
+		if conn.Name == name {
+			return conn, true
+		}
+	}
+	for _, conn := range conns {
+		if conn.HasName(name) {
+			return conn, true
+		}
+	}
+	return Connection{}, false
+}
+
+// Add saves a new connection, best started from NewConnection
+func (s *Store) Add(conn Connection) error {
+	if err := s.usable(); err != nil {
+		return err
+	}
+	return s.cfg.AddConnection(conn)
+}
+
+// Update saves the changes to a connection, found by its ID
+func (s *Store) Update(conn Connection) error {
+	if err := s.usable(); err != nil {
+		return err
+	}
+	return s.cfg.UpdateConnection(conn)
+}
+
+// Delete moves the connection called name to the trash, from where gossh
+// trash restore brings it back
+func (s *Store) Delete(name string) error {
+	conn, ok := s.Find(name)
+	if !ok {
+		if err := s.usable(); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return s.cfg.DeleteConnection(conn.ID)
+}
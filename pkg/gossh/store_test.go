@@ -0,0 +1,68 @@
+package gossh
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if store.Initialized() {
+		t.Fatal("new store is set up already")
+	}
+	if _, err := store.Connections(); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Connections() of a new store: %v, want ErrNotInitialized", err)
+	}
+	if err := store.Setup(""); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	conn := NewConnection()
+	conn.Name = "web"
+	conn.Aliases = []string{"www"}
+	conn.Host = "10.0.0.5"
+	conn.User = "deploy"
+	conn.Password = "s3cret"
+	if err := store.Add(conn); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	// Open again: without a master password the store unlocks by itself
+	store, err = Open(path)
+	if err != nil {
+		t.Fatalf("Open of the set up store failed: %v", err)
+	}
+	if store.Locked() {
+		t.Fatal("store without a master password is locked")
+	}
+	found, ok := store.Find("www")
+	if !ok {
+		t.Fatal("Find() by alias found nothing")
+	}
+	if found.ID != conn.ID || found.Password != "s3cret" {
+		t.Errorf("Find() = %s with password %q, want %s with the password decrypted", found.ID, found.Password, conn.ID)
+	}
+
+	found.Port = 2222
+	if err := store.Update(found); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if got, _ := store.Find("web"); got.Port != 2222 {
+		t.Errorf("port after Update = %d, want 2222", got.Port)
+	}
+
+	if err := store.Delete("web"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, ok := store.Find("web"); ok {
+		t.Error("Find() still finds a deleted connection")
+	}
+	if err := store.Delete("web"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete() of a missing connection: %v, want ErrNotFound", err)
+	}
+}